@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/fr0stylo/ddash/internal/config"
+	"github.com/fr0stylo/ddash/internal/db"
+)
+
+func main() {
+	var (
+		dbPath      string
+		backupDir   string
+		keep        int
+		list        bool
+		verifyPath  string
+		restorePath string
+	)
+
+	if err := godotenv.Load(); err != nil {
+		log.Printf("no .env file loaded: %v", err)
+	}
+
+	cfg, err := config.LoadForTool()
+	if err != nil {
+		log.Fatalf("load config: %v", err)
+	}
+
+	flag.StringVar(&dbPath, "db", cfg.Database.Path, "database path without .sqlite suffix")
+	flag.StringVar(&backupDir, "dir", cfg.Database.BackupDir, "directory snapshots are written to")
+	flag.IntVar(&keep, "keep", cfg.Database.BackupKeep, "number of snapshots kept after a backup")
+	flag.BoolVar(&list, "list", false, "list snapshots in -dir, newest first")
+	flag.StringVar(&verifyPath, "verify", "", "snapshot to verify instead of taking a backup")
+	flag.StringVar(&restorePath, "restore", "", "snapshot to restore over -db; stop ddash first")
+	flag.Parse()
+
+	ctx := context.Background()
+	switch {
+	case list:
+		backups, err := db.ListBackups(backupDir)
+		if err != nil {
+			log.Fatalf("list backups: %v", err)
+		}
+		for _, backup := range backups {
+			fmt.Printf("%s\t%s\n", backup.CreatedAt.Format(time.RFC3339), backup.Path)
+		}
+	case verifyPath != "":
+		if err := db.VerifyBackup(ctx, verifyPath); err != nil {
+			log.Fatalf("verify %s: %v", verifyPath, err)
+		}
+		fmt.Printf("%s verified\n", verifyPath)
+	case restorePath != "":
+		previous, err := db.RestoreBackup(ctx, restorePath, dbPath, time.Now())
+		if err != nil {
+			log.Fatalf("restore %s: %v", restorePath, err)
+		}
+		fmt.Printf("restored %s to %s.sqlite\n", restorePath, dbPath)
+		if previous != "" {
+			fmt.Printf("previous database kept at %s\n", previous)
+		}
+	default:
+		database, err := db.New(dbPath)
+		if err != nil {
+			log.Fatalf("open database: %v", err)
+		}
+		defer func() { _ = database.Close() }()
+
+		result, err := database.CreateBackup(ctx, backupDir, keep, time.Now())
+		if err != nil {
+			log.Fatalf("backup database: %v", err)
+		}
+		fmt.Printf("wrote %s (%d bytes, sha256 %s)\n", result.Path, result.Size, result.Checksum)
+		for _, pruned := range result.Pruned {
+			fmt.Printf("pruned %s\n", pruned)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"os"
 	"strings"
 	"time"
 
@@ -64,6 +65,11 @@ func main() {
 		log.Fatalf("count event_store rows: %v", err)
 	}
 
+	payloads, err := database.GetEventStorePayloadSizes(ctx)
+	if err != nil {
+		log.Fatalf("measure event payloads: %v", err)
+	}
+
 	windowRows := int64(0)
 	dailyRows := make([]queries.ListEventStoreDailyVolumeRow, 0)
 	if organizationID > 0 {
@@ -95,6 +101,21 @@ func main() {
 		fmt.Printf("Organization: none configured (showing global totals only)\n")
 	}
 	fmt.Printf("event_store rows: %d\n", totalRows)
+	if info, statErr := os.Stat(dbPath + ".sqlite"); statErr == nil {
+		fmt.Printf("database file: %s\n", formatBytes(info.Size()))
+	}
+	stored := payloads.PlainBytes + payloads.CompressedBytes
+	fmt.Printf("event bodies: %s stored (%d plain: %s, %d compressed: %s from %s)\n",
+		formatBytes(stored),
+		payloads.PlainEvents, formatBytes(payloads.PlainBytes),
+		payloads.CompressedEvents, formatBytes(payloads.CompressedBytes), formatBytes(payloads.OriginalBytes))
+	if payloads.OriginalBytes > 0 {
+		saved := payloads.OriginalBytes - payloads.CompressedBytes
+		fmt.Printf("compression savings: %s (%.1f%% smaller)\n", formatBytes(saved), float64(saved)/float64(payloads.OriginalBytes)*100)
+	}
+	if payloads.PlainEvents > 0 && payloads.PlainBytes > 0 {
+		fmt.Printf("plain event bodies left: %d (run apps/eventcompress to compress them)\n", payloads.PlainEvents)
+	}
 	if organizationID > 0 {
 		fmt.Printf("events in last %dd: %d (avg %.2f/day)\n", windowDays, windowRows, avgPerDay)
 	} else {
@@ -125,6 +146,24 @@ func main() {
 	}
 }
 
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size)
+	suffixes := []string{"KiB", "MiB", "GiB", "TiB"}
+	suffix := ""
+	for _, next := range suffixes {
+		value /= unit
+		suffix = next
+		if value < unit {
+			break
+		}
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
+
 func toString(value interface{}) string {
 	switch typed := value.(type) {
 	case string:
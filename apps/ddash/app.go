@@ -10,9 +10,10 @@ import (
 	"github.com/joho/godotenv"
 	_ "modernc.org/sqlite"
 
-	"github.com/fr0stylo/ddash/apps/ddash/internal/adapters/sqlite"
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
 	appingestion "github.com/fr0stylo/ddash/apps/ddash/internal/application/ingestion"
-	ingestionsqlite "github.com/fr0stylo/ddash/apps/ddash/internal/infrastructure/sqlite/ingestion"
+	appcatalog "github.com/fr0stylo/ddash/apps/ddash/internal/application/servicecatalog"
+	"github.com/fr0stylo/ddash/apps/ddash/internal/infrastructure/spool"
 	"github.com/fr0stylo/ddash/apps/ddash/internal/server"
 	"github.com/fr0stylo/ddash/apps/ddash/internal/server/routes"
 	"github.com/fr0stylo/ddash/internal/config"
@@ -60,20 +61,16 @@ func Run() error {
 
 	srv := server.New(log, publicFS)
 
-	database, err := db.New(cfg.Database.Path)
+	backend, err := openStorage(log, cfg.Database)
 	if err != nil {
-		return fmt.Errorf("failed to open database: %w", err)
+		return err
 	}
 	defer func() {
-		if err := database.Close(); err != nil {
+		if err := backend.close(); err != nil {
 			slog.Error("Failed to close database", "error", err)
 		}
 	}()
 
-	if cfg.Database.LogTiming {
-		go logDBLatencyStats(log, database)
-	}
-
 	routes.ConfigureAuth(routes.AuthConfig{
 		SessionKey:         cfg.Auth.SessionSecret,
 		GitHubClientID:     cfg.Auth.GitHubClientID,
@@ -82,18 +79,49 @@ func Run() error {
 		SecureCookies:      cfg.Auth.SecureCookie,
 	})
 
-	store := sqlite.NewStore(database)
+	store := backend.store
+	serviceChanges := appcatalog.NewServiceChangeHub()
+	ingestSettings := appingestion.NewSettingsCache()
+	ingestionStores := backend.ingestionStores
+	var webhookStores ports.IngestionStoreFactory = ingestionStores
+	if cfg.Ingestion.SpoolDir != "" {
+		ingestSpool, err := spool.Open(cfg.Ingestion.SpoolDir, ingestionStores, spool.Options{
+			OnAppended: serviceChanges.PublishAppended,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to open ingest spool: %w", err)
+		}
+		defer func() {
+			if err := ingestSpool.Close(); err != nil {
+				slog.Error("Failed to close ingest spool", "error", err)
+			}
+		}()
+		webhookStores = ingestSpool
+		slog.Info("Ingest spool enabled", "dir", cfg.Ingestion.SpoolDir, "pending", ingestSpool.Depth())
+	}
+
+	stopJobs := backend.start()
+	defer stopJobs()
 
 	srv.RegisterRouter(routes.NewAuthRoutes(store, cfg.IsLocalDevelopment()))
-	srv.RegisterRouter(routes.NewViewRoutes(store, store, store, routes.ViewExternalConfig{
-		PublicURL:           cfg.Integrations.PublicURL,
-		GitHubAppInstallURL: cfg.Integrations.GitHubAppInstallURL,
-		GitHubIngestorToken: cfg.Integrations.GitHubIngestorToken,
-	}))
-	srv.RegisterRouter(routes.NewWebhookRoutes(ingestionsqlite.NewSharedStoreFactory(database), appingestion.BatchConfig{
+	views := backend.views
+	views.PublicURL = cfg.Integrations.PublicURL
+	views.GitHubAppInstallURL = cfg.Integrations.GitHubAppInstallURL
+	views.GitHubIngestorToken = cfg.Integrations.GitHubIngestorToken
+	views.ServiceChanges = serviceChanges
+	views.DeadLetters = store
+	views.IngestionStores = ingestionStores
+	views.IngestCredentials = store
+	views.IngestHealth = store
+	views.IngestSettings = ingestSettings
+	srv.RegisterRouter(routes.NewViewRoutes(store, store, store, views))
+	srv.RegisterRouter(routes.NewWebhookRoutes(webhookStores, appingestion.BatchConfig{
 		Enabled:       cfg.Ingestion.BatchEnabled,
 		Size:          cfg.Ingestion.BatchSize,
 		FlushInterval: cfg.IngestionBatchFlushInterval(),
+		Publisher:     serviceChanges,
+		SignatureSkew: cfg.IngestionSignatureSkew(),
+		Settings:      ingestSettings,
 	}, store, store, cfg.Integrations.GitHubIngestorToken))
 
 	addr := fmt.Sprintf(":%d", cfg.Server.Port)
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+	"github.com/fr0stylo/ddash/internal/pgdb/queries"
+)
+
+var _ ports.DeadLetterStore = (*Store)(nil)
+
+// ListDeadLetters returns the newest rejected webhook deliveries for one organization.
+func (s *Store) ListDeadLetters(ctx context.Context, organizationID int64, limit int64) ([]ports.DeadLetter, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := s.database.ListIngestDeadLetters(ctx, queries.ListIngestDeadLettersParams{
+		OrganizationID: organizationID,
+		Limit:          limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.DeadLetter, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, mapDeadLetter(queries.GetIngestDeadLetterRow(row)))
+	}
+	return out, nil
+}
+
+// GetDeadLetter returns one rejected webhook delivery.
+func (s *Store) GetDeadLetter(ctx context.Context, organizationID, id int64) (ports.DeadLetter, error) {
+	row, err := s.database.GetIngestDeadLetter(ctx, queries.GetIngestDeadLetterParams{
+		OrganizationID: organizationID,
+		ID:             id,
+	})
+	if err != nil {
+		return ports.DeadLetter{}, err
+	}
+	return mapDeadLetter(row), nil
+}
+
+// MarkDeadLetterReplayed records the outcome of replaying one rejected delivery.
+func (s *Store) MarkDeadLetterReplayed(ctx context.Context, organizationID, id int64, result string, replayedAt time.Time) error {
+	return s.database.MarkIngestDeadLetterReplayed(ctx, queries.MarkIngestDeadLetterReplayedParams{
+		ReplayedAt:     sql.NullInt64{Int64: replayedAt.UTC().UnixMilli(), Valid: true},
+		ReplayResult:   result,
+		OrganizationID: organizationID,
+		ID:             id,
+	})
+}
+
+// DeleteDeadLetter removes one rejected webhook delivery.
+func (s *Store) DeleteDeadLetter(ctx context.Context, organizationID, id int64) error {
+	return s.database.DeleteIngestDeadLetter(ctx, queries.DeleteIngestDeadLetterParams{
+		OrganizationID: organizationID,
+		ID:             id,
+	})
+}
+
+func mapDeadLetter(row queries.GetIngestDeadLetterRow) ports.DeadLetter {
+	headers := map[string][]string{}
+	if row.HeadersJson != "" {
+		_ = json.Unmarshal([]byte(row.HeadersJson), &headers)
+	}
+	letter := ports.DeadLetter{
+		ID:             row.ID,
+		OrganizationID: row.OrganizationID,
+		Reason:         row.Reason,
+		Detail:         row.Detail,
+		Headers:        headers,
+		Body:           row.Body,
+		BodySize:       row.BodySize,
+		Truncated:      row.Truncated,
+		ReceivedAt:     time.UnixMilli(row.ReceivedAt).UTC(),
+		ReplayResult:   row.ReplayResult,
+	}
+	if row.ReplayedAt.Valid {
+		replayedAt := time.UnixMilli(row.ReplayedAt.Int64).UTC()
+		letter.ReplayedAt = &replayedAt
+	}
+	return letter
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+	"github.com/fr0stylo/ddash/internal/pgdb"
+	"github.com/fr0stylo/ddash/internal/pgdb/queries"
+)
+
+var _ ports.EnvironmentStore = (*Store)(nil)
+
+// ListEnvironments returns the environment registry in priority order.
+func (s *Store) ListEnvironments(ctx context.Context, organizationID int64) ([]ports.Environment, error) {
+	rows, err := s.database.ListOrganizationEnvironments(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := s.database.ListEnvironmentAliases(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.Environment, 0, len(rows))
+	for _, row := range rows {
+		environment := mapEnvironment(row)
+		for _, alias := range aliases {
+			if alias.EnvironmentID == row.ID && alias.Alias != row.Name {
+				environment.Aliases = append(environment.Aliases, alias.Alias)
+			}
+		}
+		out = append(out, environment)
+	}
+	return out, nil
+}
+
+// SaveEnvironment registers or updates an environment and its aliases.
+func (s *Store) SaveEnvironment(ctx context.Context, organizationID int64, environment ports.Environment) (ports.Environment, error) {
+	row, err := s.database.SaveOrganizationEnvironment(ctx, pgdb.EnvironmentInput{
+		OrganizationID: organizationID,
+		Name:           environment.Name,
+		Tier:           environment.Tier,
+		Color:          environment.Color,
+		Protected:      environment.Protected,
+		URL:            environment.URL,
+		Aliases:        environment.Aliases,
+	})
+	if err != nil {
+		return ports.Environment{}, err
+	}
+	saved := mapEnvironment(row)
+	saved.Aliases = environment.Aliases
+	return saved, nil
+}
+
+// DeleteEnvironment removes an environment and its aliases from the registry.
+func (s *Store) DeleteEnvironment(ctx context.Context, organizationID int64, name string) (bool, error) {
+	return s.database.DeleteOrganizationEnvironment(ctx, organizationID, name)
+}
+
+func mapEnvironment(row queries.OrganizationEnvironment) ports.Environment {
+	return ports.Environment{
+		Name:      row.Name,
+		Tier:      row.Tier,
+		Color:     row.Color,
+		Protected: row.Protected,
+		URL:       row.Url,
+		Source:    row.Source,
+		UpdatedAt: row.UpdatedAt.UTC(),
+	}
+}
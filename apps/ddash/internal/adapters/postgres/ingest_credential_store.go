@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+	"github.com/fr0stylo/ddash/internal/pgdb/queries"
+)
+
+var _ ports.IngestCredentialStore = (*Store)(nil)
+
+// ListIngestCredentials returns every additional ingest credential of one organization, newest first.
+func (s *Store) ListIngestCredentials(ctx context.Context, organizationID int64) ([]ports.IngestCredential, error) {
+	rows, err := s.database.ListOrganizationIngestCredentials(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.IngestCredential, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, mapIngestCredential(row))
+	}
+	return out, nil
+}
+
+// CreateIngestCredential stores one additional ingest credential.
+func (s *Store) CreateIngestCredential(ctx context.Context, credential ports.IngestCredential) (ports.IngestCredential, error) {
+	row, err := s.database.CreateOrganizationIngestCredential(ctx, queries.InsertOrganizationIngestCredentialParams{
+		OrganizationID: credential.OrganizationID,
+		Label:          credential.Label,
+		AuthToken:      credential.AuthToken,
+		WebhookSecret:  credential.WebhookSecret,
+		CreatedAt:      credential.CreatedAt.UTC().UnixMilli(),
+		ExpiresAt:      timeToNullMillis(credential.ExpiresAt),
+	})
+	if err != nil {
+		return ports.IngestCredential{}, err
+	}
+	return mapIngestCredential(row), nil
+}
+
+// ExpireIngestCredential sets when an unrevoked credential stops being accepted.
+func (s *Store) ExpireIngestCredential(ctx context.Context, organizationID, id int64, expiresAt time.Time) (bool, error) {
+	rows, err := s.database.ExpireOrganizationIngestCredential(ctx, queries.ExpireOrganizationIngestCredentialParams{
+		ExpiresAt:      sql.NullInt64{Int64: expiresAt.UTC().UnixMilli(), Valid: true},
+		OrganizationID: organizationID,
+		ID:             id,
+	})
+	return rows > 0, err
+}
+
+// RevokeIngestCredential stops accepting a credential immediately.
+func (s *Store) RevokeIngestCredential(ctx context.Context, organizationID, id int64, revokedAt time.Time) (bool, error) {
+	rows, err := s.database.RevokeOrganizationIngestCredential(ctx, queries.RevokeOrganizationIngestCredentialParams{
+		RevokedAt:      sql.NullInt64{Int64: revokedAt.UTC().UnixMilli(), Valid: true},
+		OrganizationID: organizationID,
+		ID:             id,
+	})
+	return rows > 0, err
+}
+
+func mapIngestCredential(row queries.OrganizationIngestCredential) ports.IngestCredential {
+	return ports.IngestCredential{
+		ID:             row.ID,
+		OrganizationID: row.OrganizationID,
+		Label:          row.Label,
+		AuthToken:      row.AuthToken,
+		WebhookSecret:  row.WebhookSecret,
+		CreatedAt:      time.UnixMilli(row.CreatedAt).UTC(),
+		ExpiresAt:      nullMillisToTime(row.ExpiresAt),
+		RevokedAt:      nullMillisToTime(row.RevokedAt),
+		LastUsedAt:     nullMillisToTime(row.LastUsedAt),
+	}
+}
+
+func nullMillisToTime(value sql.NullInt64) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	t := time.UnixMilli(value.Int64).UTC()
+	return &t
+}
+
+func timeToNullMillis(value *time.Time) sql.NullInt64 {
+	if value == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: value.UTC().UnixMilli(), Valid: true}
+}
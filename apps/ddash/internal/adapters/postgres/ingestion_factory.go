@@ -0,0 +1,19 @@
+package postgres
+
+import (
+	ingestionpostgres "github.com/fr0stylo/ddash/apps/ddash/internal/infrastructure/postgres/ingestion"
+	"github.com/fr0stylo/ddash/internal/pgdb"
+)
+
+// IngestionStoreFactory opens postgres-backed ingestion stores.
+type IngestionStoreFactory = ingestionpostgres.StoreFactory
+
+// NewIngestionStoreFactory creates a postgres ingestion store factory backed by a connection URL.
+func NewIngestionStoreFactory(url string) *IngestionStoreFactory {
+	return ingestionpostgres.NewStoreFactory(url)
+}
+
+// NewSharedIngestionStoreFactory creates a factory backed by an existing shared DB handle.
+func NewSharedIngestionStoreFactory(shared *pgdb.Database) *IngestionStoreFactory {
+	return ingestionpostgres.NewSharedStoreFactory(shared)
+}
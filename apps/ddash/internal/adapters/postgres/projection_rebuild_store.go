@@ -0,0 +1,14 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+var _ ports.ProjectionRebuildStore = (*Store)(nil)
+
+// RebuildOrganizationProjections replays one organization's events into its projections.
+func (s *Store) RebuildOrganizationProjections(ctx context.Context, organizationID int64, progress func(step string, index, total int)) error {
+	return s.database.RebuildOrganizationProjections(ctx, organizationID, progress)
+}
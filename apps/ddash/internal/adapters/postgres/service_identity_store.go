@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+	"github.com/fr0stylo/ddash/internal/pgdb/queries"
+)
+
+var _ ports.ServiceIdentityStore = (*Store)(nil)
+
+// ListServiceIdentityRules returns an organization's rules in the order they apply.
+func (s *Store) ListServiceIdentityRules(ctx context.Context, organizationID int64) ([]ports.ServiceIdentityRule, error) {
+	rows, err := s.database.ListServiceIdentityRules(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.ServiceIdentityRule, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, mapServiceIdentityRule(row))
+	}
+	return out, nil
+}
+
+// CreateServiceIdentityRule stores a rule and remaps the organization's service names.
+func (s *Store) CreateServiceIdentityRule(ctx context.Context, organizationID int64, rule ports.ServiceIdentityRule) (ports.ServiceIdentityRule, error) {
+	row, err := s.database.CreateServiceIdentityRule(ctx, queries.CreateServiceIdentityRuleParams{
+		OrganizationID: organizationID,
+		Kind:           rule.Kind,
+		Pattern:        rule.Pattern,
+		ServiceName:    rule.ServiceName,
+	})
+	if err != nil {
+		return ports.ServiceIdentityRule{}, err
+	}
+	return mapServiceIdentityRule(row), nil
+}
+
+// DeleteServiceIdentityRule removes a rule and remaps the organization's service names.
+func (s *Store) DeleteServiceIdentityRule(ctx context.Context, organizationID, id int64) (bool, error) {
+	rows, err := s.database.DeleteServiceIdentityRule(ctx, queries.DeleteServiceIdentityRuleParams{OrganizationID: organizationID, ID: id})
+	return rows > 0, err
+}
+
+// ListServiceIdentityAliases returns the derived service names the rules currently map.
+func (s *Store) ListServiceIdentityAliases(ctx context.Context, organizationID int64) ([]ports.ServiceIdentityAlias, error) {
+	rows, err := s.database.ListServiceIdentityAliases(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.ServiceIdentityAlias, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, ports.ServiceIdentityAlias{Alias: row.Alias, ServiceName: row.ServiceName})
+	}
+	return out, nil
+}
+
+// ResolveServiceName returns the service a derived name maps to, or the name itself.
+func (s *Store) ResolveServiceName(ctx context.Context, organizationID int64, name string) (string, error) {
+	resolved, err := s.database.GetServiceIdentityAlias(ctx, queries.GetServiceIdentityAliasParams{OrganizationID: organizationID, Alias: name})
+	if errors.Is(err, sql.ErrNoRows) {
+		return name, nil
+	}
+	return resolved, err
+}
+
+func mapServiceIdentityRule(row queries.ServiceIdentityRule) ports.ServiceIdentityRule {
+	return ports.ServiceIdentityRule{
+		ID:          row.ID,
+		Kind:        row.Kind,
+		Pattern:     row.Pattern,
+		ServiceName: row.ServiceName,
+		CreatedAt:   row.CreatedAt.UTC(),
+	}
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/adapters/storetest"
+	"github.com/fr0stylo/ddash/internal/pgdb"
+)
+
+// testURLEnv names the PostgreSQL server used by the store tests; start one
+// with `task test:postgres`.
+const testURLEnv = "DDASH_TEST_POSTGRES_URL"
+
+var testSchemaSeq atomic.Int64
+
+func TestStore(t *testing.T) {
+	baseURL := strings.TrimSpace(os.Getenv(testURLEnv))
+	if baseURL == "" {
+		t.Skipf("%s not set", testURLEnv)
+	}
+
+	admin, err := sql.Open("pgx", baseURL)
+	if err != nil {
+		t.Fatalf("open admin connection: %v", err)
+	}
+	t.Cleanup(func() { _ = admin.Close() })
+
+	storetest.Run(t, func(t *testing.T) storetest.Backend {
+		t.Helper()
+
+		schemaURL := newTestSchema(t, admin, baseURL)
+		database, err := pgdb.New(schemaURL)
+		if err != nil {
+			t.Fatalf("open test db: %v", err)
+		}
+		t.Cleanup(func() { _ = database.Close() })
+
+		return storetest.Backend{
+			Store:     NewStore(database),
+			Ingestion: NewIngestionStoreFactory(schemaURL),
+		}
+	})
+}
+
+// newTestSchema creates an empty schema for one test and returns a connection
+// URL that resolves unqualified names in it.
+func newTestSchema(t *testing.T, admin *sql.DB, baseURL string) string {
+	t.Helper()
+
+	schema := fmt.Sprintf("ddash_test_%d_%d", time.Now().Unix(), testSchemaSeq.Add(1))
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() { _, _ = admin.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		t.Fatalf("parse %s: %v", testURLEnv, err)
+	}
+	query := parsed.Query()
+	query.Set("search_path", schema)
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
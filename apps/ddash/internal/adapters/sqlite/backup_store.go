@@ -0,0 +1,22 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+var _ ports.BackupStore = (*Store)(nil)
+
+// CreateBackup writes a checksummed database snapshot into dir and rotates old ones.
+func (s *Store) CreateBackup(ctx context.Context, dir string, keep int, now time.Time) (ports.Backup, error) {
+	result, err := s.database.CreateBackup(ctx, dir, keep, now)
+	return ports.Backup{
+		Path:      result.Path,
+		Checksum:  result.Checksum,
+		Size:      result.Size,
+		CreatedAt: result.CreatedAt,
+		Pruned:    result.Pruned,
+	}, err
+}
@@ -0,0 +1,42 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+	"github.com/fr0stylo/ddash/internal/db/queries"
+)
+
+var _ ports.ChainStore = (*Store)(nil)
+
+// ListChainEvents returns the events sharing a chain_id, oldest first.
+func (s *Store) ListChainEvents(ctx context.Context, organizationID int64, chainID string, limit int64) ([]ports.ChainEvent, error) {
+	rows, err := s.database.ListChainEvents(ctx, queries.ListChainEventsParams{
+		OrganizationID: organizationID,
+		ChainID:        sql.NullString{String: chainID, Valid: true},
+		Limit:          limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.ChainEvent, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, ports.ChainEvent{
+			StoredEvent: ports.StoredEvent{
+				Seq:         row.Seq,
+				EventID:     row.EventID,
+				EventType:   row.EventType,
+				Source:      row.EventSource,
+				Timestamp:   time.UnixMilli(row.EventTsMs).UTC(),
+				SubjectID:   row.SubjectID,
+				SubjectType: row.SubjectType,
+				ChainID:     chainID,
+				IngestedAt:  row.IngestedAt.UTC(),
+			},
+			URL: toString(row.Url),
+		})
+	}
+	return out, nil
+}
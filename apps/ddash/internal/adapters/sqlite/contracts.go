@@ -3,7 +3,9 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"time"
 
+	"github.com/fr0stylo/ddash/internal/db"
 	"github.com/fr0stylo/ddash/internal/db/queries"
 )
 
@@ -43,12 +45,17 @@ type storeDatabase interface {
 	UpsertOrganizationFeature(ctx context.Context, organizationID int64, featureKey string, isEnabled bool) error
 	ListOrganizationPreferences(ctx context.Context, organizationID int64) ([]queries.ListOrganizationPreferencesRow, error)
 	UpsertOrganizationPreference(ctx context.Context, organizationID int64, preferenceKey, preferenceValue string) error
+	ListOrganizationEventPolicies(ctx context.Context, organizationID int64) ([]queries.ListOrganizationEventPoliciesRow, error)
 	ListDistinctServiceEnvironmentsFromEvents(ctx context.Context, organizationID int64) ([]string, error)
 
 	ListServiceInstancesFromEvents(ctx context.Context, organizationID int64) ([]queries.ListServiceInstancesFromEventsRow, error)
 	ListServiceInstancesByEnvFromEvents(ctx context.Context, params queries.ListServiceInstancesByEnvFromEventsParams) ([]queries.ListServiceInstancesByEnvFromEventsRow, error)
 	ListDeploymentsFromEvents(ctx context.Context, params queries.ListDeploymentsFromEventsParams) ([]queries.ListDeploymentsFromEventsRow, error)
+	ListDeploymentEnvironmentCounts(ctx context.Context, params queries.ListDeploymentEnvironmentCountsParams) ([]queries.ListDeploymentEnvironmentCountsRow, error)
+	ListDeploymentServices(ctx context.Context, organizationID int64) ([]string, error)
 	GetOrganizationRenderVersion(ctx context.Context, orgID int64) (interface{}, error)
+	GetOrganizationLatestEventSeq(ctx context.Context, organizationID int64) (int64, error)
+	ListServiceChangesAfterSeq(ctx context.Context, params queries.ListServiceChangesAfterSeqParams) ([]queries.ListServiceChangesAfterSeqRow, error)
 	GetServiceLatestFromEvents(ctx context.Context, params queries.GetServiceLatestFromEventsParams) (queries.GetServiceLatestFromEventsRow, error)
 	ListServiceEnvironmentsFromEvents(ctx context.Context, params queries.ListServiceEnvironmentsFromEventsParams) ([]queries.ListServiceEnvironmentsFromEventsRow, error)
 	ListDeploymentHistoryByServiceFromEvents(ctx context.Context, params queries.ListDeploymentHistoryByServiceFromEventsParams) ([]queries.ListDeploymentHistoryByServiceFromEventsRow, error)
@@ -76,5 +83,39 @@ type storeDatabase interface {
 	ListServiceMetadataByService(ctx context.Context, params queries.ListServiceMetadataByServiceParams) ([]queries.ListServiceMetadataByServiceRow, error)
 	ListServiceMetadataByOrganization(ctx context.Context, organizationID int64) ([]queries.ListServiceMetadataByOrganizationRow, error)
 
+	ListIngestDeadLetters(ctx context.Context, params queries.ListIngestDeadLettersParams) ([]queries.ListIngestDeadLettersRow, error)
+	GetIngestDeadLetter(ctx context.Context, params queries.GetIngestDeadLetterParams) (queries.GetIngestDeadLetterRow, error)
+	MarkIngestDeadLetterReplayed(ctx context.Context, params queries.MarkIngestDeadLetterReplayedParams) error
+	DeleteIngestDeadLetter(ctx context.Context, params queries.DeleteIngestDeadLetterParams) error
+
+	ListOrganizationIngestCredentials(ctx context.Context, organizationID int64) ([]queries.OrganizationIngestCredential, error)
+	CreateOrganizationIngestCredential(ctx context.Context, params queries.InsertOrganizationIngestCredentialParams) (queries.OrganizationIngestCredential, error)
+	ExpireOrganizationIngestCredential(ctx context.Context, params queries.ExpireOrganizationIngestCredentialParams) (int64, error)
+	RevokeOrganizationIngestCredential(ctx context.Context, params queries.RevokeOrganizationIngestCredentialParams) (int64, error)
+
+	ListIngestHourlyStats(ctx context.Context, params queries.ListIngestHourlyStatsParams) ([]queries.ListIngestHourlyStatsRow, error)
+	ListIngestSourceActivity(ctx context.Context, params queries.ListIngestSourceActivityParams) ([]queries.ListIngestSourceActivityRow, error)
+
+	RebuildServiceProjectionsWithProgress(ctx context.Context, organizationID int64, progress db.ProjectionRebuildProgress) (db.ProjectionRebuildStats, error)
+	ListProjectorStatus(ctx context.Context) ([]db.ProjectorStatus, error)
+	CatchUpProjectors(ctx context.Context, batchSize int64) (bool, error)
+	ArchiveExpiredEvents(ctx context.Context, dir string, now time.Time) ([]db.EventArchiveResult, error)
+	CreateBackup(ctx context.Context, dir string, keep int, now time.Time) (db.BackupResult, error)
+
+	ListEventStoreEvents(ctx context.Context, params queries.ListEventStoreEventsParams) ([]queries.ListEventStoreEventsRow, error)
+	GetEventStoreEvent(ctx context.Context, params queries.GetEventStoreEventParams) (queries.EventStore, error)
+	ListEventContributions(ctx context.Context, params queries.ListEventContributionsParams) ([]queries.ListEventContributionsRow, error)
+	ListChainEvents(ctx context.Context, params queries.ListChainEventsParams) ([]queries.ListChainEventsRow, error)
+	ListServiceEnvironmentStateAt(ctx context.Context, params queries.ListServiceEnvironmentStateAtParams) ([]queries.ListServiceEnvironmentStateAtRow, error)
+	ListServiceIdentityRules(ctx context.Context, organizationID int64) ([]queries.ServiceIdentityRule, error)
+	CreateServiceIdentityRule(ctx context.Context, params queries.CreateServiceIdentityRuleParams) (queries.ServiceIdentityRule, error)
+	DeleteServiceIdentityRule(ctx context.Context, params queries.DeleteServiceIdentityRuleParams) (int64, error)
+	ListServiceIdentityAliases(ctx context.Context, organizationID int64) ([]queries.ServiceIdentityAlias, error)
+	GetServiceIdentityAlias(ctx context.Context, params queries.GetServiceIdentityAliasParams) (string, error)
+	ListOrganizationEnvironments(ctx context.Context, organizationID int64) ([]queries.OrganizationEnvironment, error)
+	ListEnvironmentAliases(ctx context.Context, organizationID int64) ([]queries.EnvironmentAlias, error)
+	SaveOrganizationEnvironment(ctx context.Context, input db.EnvironmentInput) (queries.OrganizationEnvironment, error)
+	DeleteOrganizationEnvironment(ctx context.Context, organizationID int64, name string) (bool, error)
+
 	WithTx(ctx context.Context, fn func(*queries.Queries) error) error
 }
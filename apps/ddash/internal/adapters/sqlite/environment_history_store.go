@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+	"github.com/fr0stylo/ddash/internal/db/queries"
+)
+
+var _ ports.EnvironmentHistoryStore = (*Store)(nil)
+
+// ListServiceEnvironmentStateAt returns the latest service event per service
+// and environment at or before at.
+func (s *Store) ListServiceEnvironmentStateAt(ctx context.Context, organizationID int64, at time.Time) ([]ports.EnvironmentState, error) {
+	rows, err := s.database.ListServiceEnvironmentStateAt(ctx, queries.ListServiceEnvironmentStateAtParams{
+		OrganizationID: organizationID,
+		AtMs:           at.UnixMilli(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.EnvironmentState, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, ports.EnvironmentState{
+			Service:     row.ServiceName,
+			Environment: row.Environment,
+			ArtifactID:  toString(row.ArtifactID),
+			Status:      row.Status,
+			Actor:       toString(row.ActorName),
+			EventType:   row.EventType,
+			Seq:         row.EventSeq,
+			DeployedAt:  time.UnixMilli(row.EventTsMs).UTC(),
+		})
+	}
+	return out, nil
+}
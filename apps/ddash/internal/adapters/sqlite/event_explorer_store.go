@@ -0,0 +1,85 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+	"github.com/fr0stylo/ddash/internal/db/queries"
+)
+
+var _ ports.EventExplorerStore = (*Store)(nil)
+
+// ListEvents returns an organization's events matching the filter, newest first.
+func (s *Store) ListEvents(ctx context.Context, organizationID int64, filter ports.EventFilter) ([]ports.StoredEvent, error) {
+	params := queries.ListEventStoreEventsParams{
+		OrganizationID: organizationID,
+		BeforeSeq:      filter.BeforeSeq,
+		EventType:      filter.EventType,
+		SubjectType:    filter.SubjectType,
+		SubjectID:      filter.SubjectID,
+		EventSource:    filter.Source,
+		ChainID:        filter.ChainID,
+		Limit:          filter.Limit,
+	}
+	if !filter.From.IsZero() {
+		params.FromMs = filter.From.UnixMilli()
+	}
+	if !filter.To.IsZero() {
+		params.ToMs = filter.To.UnixMilli()
+	}
+	rows, err := s.database.ListEventStoreEvents(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.StoredEvent, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, ports.StoredEvent{
+			Seq:         row.Seq,
+			EventID:     row.EventID,
+			EventType:   row.EventType,
+			Source:      row.EventSource,
+			Timestamp:   time.UnixMilli(row.EventTsMs).UTC(),
+			SubjectID:   row.SubjectID,
+			SubjectType: row.SubjectType,
+			ChainID:     row.ChainID.String,
+			IngestedAt:  row.IngestedAt.UTC(),
+		})
+	}
+	return out, nil
+}
+
+// GetEvent returns one event with its raw payload and the service
+// environments it was projected into.
+func (s *Store) GetEvent(ctx context.Context, organizationID, seq int64) (ports.StoredEventDetail, error) {
+	row, err := s.database.GetEventStoreEvent(ctx, queries.GetEventStoreEventParams{OrganizationID: organizationID, Seq: seq})
+	if err != nil {
+		return ports.StoredEventDetail{}, err
+	}
+	contributions, err := s.database.ListEventContributions(ctx, queries.ListEventContributionsParams{OrganizationID: organizationID, Seq: seq})
+	if err != nil {
+		return ports.StoredEventDetail{}, err
+	}
+	detail := ports.StoredEventDetail{
+		StoredEvent: ports.StoredEvent{
+			Seq:         row.Seq,
+			EventID:     row.EventID,
+			EventType:   row.EventType,
+			Source:      row.EventSource,
+			Timestamp:   time.UnixMilli(row.EventTsMs).UTC(),
+			SubjectID:   row.SubjectID,
+			SubjectType: row.SubjectType,
+			ChainID:     row.ChainID.String,
+			IngestedAt:  row.IngestedAt.UTC(),
+		},
+		RawJSON:       row.RawEventJson,
+		Contributions: make([]ports.EventContribution, 0, len(contributions)),
+	}
+	for _, contribution := range contributions {
+		detail.Contributions = append(detail.Contributions, ports.EventContribution{
+			Service:     contribution.ServiceName,
+			Environment: contribution.Environment,
+		})
+	}
+	return detail, nil
+}
@@ -0,0 +1,25 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+var _ ports.EventRetentionStore = (*Store)(nil)
+
+// ArchiveExpiredEvents archives and deletes events outside each organization's retention window.
+func (s *Store) ArchiveExpiredEvents(ctx context.Context, dir string, now time.Time) ([]ports.EventArchive, error) {
+	rows, err := s.database.ArchiveExpiredEvents(ctx, dir, now)
+	out := make([]ports.EventArchive, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, ports.EventArchive{
+			OrganizationID: row.OrganizationID,
+			Path:           row.Path,
+			EventCount:     row.EventCount,
+			ArchivedBefore: row.ArchivedBefore,
+		})
+	}
+	return out, err
+}
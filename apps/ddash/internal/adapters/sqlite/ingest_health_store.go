@@ -0,0 +1,56 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+	"github.com/fr0stylo/ddash/internal/db/queries"
+)
+
+var _ ports.IngestHealthStore = (*Store)(nil)
+
+// ListIngestHourlyStats returns hourly ingest outcome counts of one organization since a point in time.
+func (s *Store) ListIngestHourlyStats(ctx context.Context, organizationID int64, since time.Time) ([]ports.IngestHourlyStat, error) {
+	rows, err := s.database.ListIngestHourlyStats(ctx, queries.ListIngestHourlyStatsParams{
+		OrganizationID: organizationID,
+		Since:          since.UTC().UnixMilli(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.IngestHourlyStat, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, ports.IngestHourlyStat{
+			BucketStart: time.UnixMilli(row.BucketStart).UTC(),
+			Outcome:     row.Outcome,
+			Reason:      row.Reason,
+			Count:       row.EventCount,
+		})
+	}
+	return out, nil
+}
+
+// ListIngestSourceActivity returns the latest accepted event per source, newest first.
+func (s *Store) ListIngestSourceActivity(ctx context.Context, organizationID int64, limit int64) ([]ports.IngestSourceActivity, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := s.database.ListIngestSourceActivity(ctx, queries.ListIngestSourceActivityParams{
+		OrganizationID: organizationID,
+		Limit:          limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.IngestSourceActivity, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, ports.IngestSourceActivity{
+			Source:        row.EventSource,
+			LastEventID:   row.LastEventID,
+			LastEventType: row.LastEventType,
+			LastSeenAt:    time.UnixMilli(row.LastSeenAt).UTC(),
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,20 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+	"github.com/fr0stylo/ddash/internal/db"
+)
+
+var _ ports.ProjectionRebuildStore = (*Store)(nil)
+
+// RebuildOrganizationProjections rebuilds one organization's projections in shadow tables and swaps them in.
+func (s *Store) RebuildOrganizationProjections(ctx context.Context, organizationID int64, progress func(step string, index, total int)) error {
+	_, err := s.database.RebuildServiceProjectionsWithProgress(ctx, organizationID, func(step db.ProjectionRebuildStep) {
+		if progress != nil {
+			progress(step.Name, step.Index, step.Total)
+		}
+	})
+	return err
+}
@@ -0,0 +1,32 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+var _ ports.ProjectorStore = (*Store)(nil)
+
+// ListProjectorStatus returns the checkpoint of every registered projector.
+func (s *Store) ListProjectorStatus(ctx context.Context) ([]ports.ProjectorStatus, error) {
+	rows, err := s.database.ListProjectorStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.ProjectorStatus, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, ports.ProjectorStatus{
+			Name:      row.Name,
+			LastSeq:   row.LastSeq,
+			HeadSeq:   row.HeadSeq,
+			UpdatedAt: row.UpdatedAt,
+		})
+	}
+	return out, nil
+}
+
+// CatchUpProjectors applies one batch of events to every lagging projector.
+func (s *Store) CatchUpProjectors(ctx context.Context, batchSize int64) (bool, error) {
+	return s.database.CatchUpProjectors(ctx, batchSize)
+}
@@ -32,19 +32,57 @@ func (s *Store) ListServiceInstances(ctx context.Context, organizationID int64,
 	return mapServiceInstancesByEnvRows(rows), nil
 }
 
-// ListDeployments lists deployment projections for filters.
-func (s *Store) ListDeployments(ctx context.Context, organizationID int64, env, service string) ([]domain.DeploymentRow, error) {
-	rows, err := s.database.ListDeploymentsFromEvents(ctx, queries.ListDeploymentsFromEventsParams{
+// ListDeployments lists one page of deployment projections for filters.
+func (s *Store) ListDeployments(ctx context.Context, organizationID int64, filter ports.DeploymentFilter) ([]domain.DeploymentRow, error) {
+	params := queries.ListDeploymentsFromEventsParams{
 		OrganizationID: organizationID,
-		Env:            env,
-		Service:        service,
-	})
+		Env:            filter.Env,
+		Service:        filter.Service,
+		Status:         string(filter.Status),
+		BeforeSeq:      filter.Before.Seq,
+		BeforeTsMs:     filter.Before.EventTsMs,
+		Limit:          filter.Limit,
+	}
+	if !filter.From.IsZero() {
+		params.FromMs = filter.From.UnixMilli()
+	}
+	if !filter.To.IsZero() {
+		params.ToMs = filter.To.UnixMilli()
+	}
+	rows, err := s.database.ListDeploymentsFromEvents(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 	return mapDeploymentsRows(rows), nil
 }
 
+// ListDeploymentEnvironmentCounts counts deployments per environment over the
+// 7 and 30 days before now.
+func (s *Store) ListDeploymentEnvironmentCounts(ctx context.Context, organizationID int64, now time.Time) ([]ports.DeploymentEnvironmentCount, error) {
+	rows, err := s.database.ListDeploymentEnvironmentCounts(ctx, queries.ListDeploymentEnvironmentCountsParams{
+		OrganizationID: organizationID,
+		FromMs:         now.Add(-30 * 24 * time.Hour).UnixMilli(),
+		RecentFromMs:   now.Add(-7 * 24 * time.Hour).UnixMilli(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.DeploymentEnvironmentCount, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, ports.DeploymentEnvironmentCount{
+			Environment: row.Environment,
+			Count7d:     row.RecentCount,
+			Count30d:    row.TotalCount,
+		})
+	}
+	return out, nil
+}
+
+// ListDeploymentServices lists the services that have been deployed.
+func (s *Store) ListDeploymentServices(ctx context.Context, organizationID int64) ([]string, error) {
+	return s.database.ListDeploymentServices(ctx, organizationID)
+}
+
 // GetOrganizationRenderVersion returns a coarse version for rendered fragments.
 func (s *Store) GetOrganizationRenderVersion(ctx context.Context, organizationID int64) (int64, error) {
 	version, err := s.database.GetOrganizationRenderVersion(ctx, organizationID)
@@ -54,6 +92,32 @@ func (s *Store) GetOrganizationRenderVersion(ctx context.Context, organizationID
 	return toInt64(version), nil
 }
 
+// GetLatestEventSeq returns the newest event_store sequence for an organization.
+func (s *Store) GetLatestEventSeq(ctx context.Context, organizationID int64) (int64, error) {
+	return s.database.GetOrganizationLatestEventSeq(ctx, organizationID)
+}
+
+// ListServiceChangesAfterSeq returns services with events committed after a sequence.
+func (s *Store) ListServiceChangesAfterSeq(ctx context.Context, organizationID int64, afterSeq int64, limit int64) ([]ports.ServiceChange, error) {
+	rows, err := s.database.ListServiceChangesAfterSeq(ctx, queries.ListServiceChangesAfterSeqParams{
+		OrganizationID: organizationID,
+		AfterSeq:       afterSeq,
+		Limit:          limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.ServiceChange, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, ports.ServiceChange{
+			OrganizationID: organizationID,
+			ServiceName:    row.ServiceName,
+			Seq:            row.LastSeq,
+		})
+	}
+	return out, nil
+}
+
 // GetServiceLatest returns latest known state for a service.
 func (s *Store) GetServiceLatest(ctx context.Context, organizationID int64, name string) (ports.ServiceLatest, error) {
 	row, err := s.database.GetServiceLatestFromEvents(ctx, queries.GetServiceLatestFromEventsParams{OrganizationID: organizationID, Service: name})
@@ -104,6 +168,7 @@ func (s *Store) ListDeploymentHistory(ctx context.Context, organizationID int64,
 			DeployedAt:  formatted,
 			DeployedAgo: relativeFromFormattedTimestamp(formatted),
 			Environment: toString(row.Environment),
+			ChainID:     row.ChainID,
 		})
 	}
 	return out, nil
@@ -387,10 +452,12 @@ func mapDeploymentsRows(rows []queries.ListDeploymentsFromEventsRow) []domain.De
 	out := make([]domain.DeploymentRow, 0, len(rows))
 	for _, row := range rows {
 		out = append(out, domain.DeploymentRow{
-			Service:     toString(row.Service),
-			Environment: toString(row.Environment),
+			Service:     row.Service,
+			Environment: row.Environment,
 			DeployedAt:  formatTimestamp(row.DeployedAt),
 			Status:      mapDeploymentStatus(row.Status),
+			Seq:         row.Seq,
+			EventTsMs:   row.EventTsMs,
 		})
 	}
 	return out
@@ -712,9 +779,10 @@ func (s *Store) GetArtifactAgeByEnvironment(ctx context.Context, organizationID
 	return out, nil
 }
 
-func (s *Store) GetMTTR(ctx context.Context, organizationID int64, sinceMs int64) (ports.MTTRStats, error) {
+func (s *Store) GetMTTR(ctx context.Context, organizationID int64, service string, sinceMs int64) (ports.MTTRStats, error) {
 	row, err := s.database.GetMTTR(ctx, queries.GetMTTRParams{
 		OrganizationID: organizationID,
+		ServiceName:    strings.TrimSpace(service),
 		SinceMs:        sinceMs,
 	})
 	if err != nil {
@@ -724,10 +792,11 @@ func (s *Store) GetMTTR(ctx context.Context, organizationID int64, sinceMs int64
 		return ports.MTTRStats{}, err
 	}
 	return ports.MTTRStats{
-		IncidentCount: row.IncidentCount,
-		MTTRSeconds:   float64(row.MttrSeconds),
-		MTTDSeconds:   float64(row.MttdSeconds),
-		MTTESeconds:   float64(row.MtteSeconds),
+		IncidentCount:     row.IncidentCount,
+		OpenIncidentCount: row.OpenCount,
+		MTTRSeconds:       row.MttrSeconds,
+		MTTDSeconds:       row.MttdSeconds,
+		MTTESeconds:       row.MtteSeconds,
 	}, nil
 }
 
@@ -746,11 +815,17 @@ func (s *Store) ListIncidentLinks(ctx context.Context, organizationID int64, ser
 		if row.DeploymentEventSeq.Valid {
 			deploymentSeq = row.DeploymentEventSeq.Int64
 		}
+		resolvedAt := int64(0)
+		if row.ResolvedAt.Valid {
+			resolvedAt = row.ResolvedAt.Int64
+		}
 		out = append(out, ports.IncidentLink{
 			IncidentID:         strings.TrimSpace(row.IncidentID),
 			IncidentType:       strings.TrimSpace(row.IncidentType),
+			Environment:        strings.TrimSpace(row.Environment),
 			LinkedAt:           row.LinkedAt,
 			DeploymentEventSeq: deploymentSeq,
+			ResolvedAt:         resolvedAt,
 		})
 	}
 	return out, nil
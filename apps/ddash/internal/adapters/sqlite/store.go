@@ -26,8 +26,10 @@ const (
 	featureShowServiceDependencies     = "show_service_dependencies"
 
 	prefDeploymentRetentionDays = "deployment_retention_days"
+	prefEventRetentionDays      = "event_retention_days"
 	prefDefaultDashboardView    = "default_dashboard_view"
 	prefStatusSemanticsMode     = "status_semantics_mode"
+	prefWebhookSignatureScheme  = "webhook_signature_scheme"
 )
 
 // Store is the sqlite/sqlc-backed implementation of AppStore.
@@ -481,6 +483,19 @@ func (s *Store) ListDistinctServiceEnvironmentsFromEvents(ctx context.Context, o
 	return s.database.ListDistinctServiceEnvironmentsFromEvents(ctx, organizationID)
 }
 
+// ListOrganizationEventPolicies returns event subject policies for one organization.
+func (s *Store) ListOrganizationEventPolicies(ctx context.Context, organizationID int64) ([]ports.EventSubjectPolicy, error) {
+	rows, err := s.database.ListOrganizationEventPolicies(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.EventSubjectPolicy, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, ports.EventSubjectPolicy{Subject: row.Subject, Mode: row.Mode})
+	}
+	return out, nil
+}
+
 // UpdateOrganizationSettings persists organization secrets and metadata settings.
 func (s *Store) UpdateOrganizationSettings(ctx context.Context, organizationID int64, params ports.OrganizationSettingsUpdate) error {
 	params.AuthToken = strings.TrimSpace(params.AuthToken)
@@ -534,8 +549,10 @@ func (s *Store) UpdateOrganizationSettings(ctx context.Context, organizationID i
 			value string
 		}{
 			{prefDeploymentRetentionDays, strings.TrimSpace(strconv.Itoa(params.DeploymentRetentionDays))},
+			{prefEventRetentionDays, strconv.Itoa(params.EventRetentionDays)},
 			{prefDefaultDashboardView, strings.TrimSpace(params.DefaultDashboardView)},
 			{prefStatusSemanticsMode, strings.TrimSpace(params.StatusSemanticsMode)},
+			{prefWebhookSignatureScheme, strings.TrimSpace(params.WebhookSignatureScheme)},
 		}
 		for _, preference := range preferences {
 			if preference.value == "" {
@@ -550,6 +567,24 @@ func (s *Store) UpdateOrganizationSettings(ctx context.Context, organizationID i
 			}
 		}
 
+		if err := q.DeleteOrganizationEventPolicies(ctx, organizationID); err != nil {
+			return err
+		}
+		for _, policy := range params.EventPolicies {
+			subject := strings.ToLower(strings.TrimSpace(policy.Subject))
+			mode := strings.ToLower(strings.TrimSpace(policy.Mode))
+			if subject == "" || mode == "" {
+				continue
+			}
+			if err := q.UpsertOrganizationEventPolicy(ctx, queries.UpsertOrganizationEventPolicyParams{
+				OrganizationID: organizationID,
+				Subject:        subject,
+				Mode:           mode,
+			}); err != nil {
+				return err
+			}
+		}
+
 		if err := q.DeleteOrganizationRequiredFields(ctx, organizationID); err != nil {
 			return err
 		}
@@ -596,6 +631,14 @@ func (s *Store) UpdateOrganizationSettings(ctx context.Context, organizationID i
 				}
 				return err
 			}
+			// Registered environments follow the order chosen here.
+			if err := q.SetOrganizationEnvironmentSortOrder(ctx, queries.SetOrganizationEnvironmentSortOrderParams{
+				SortOrder:      int64(index),
+				OrganizationID: organizationID,
+				Name:           value,
+			}); err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -0,0 +1,705 @@
+// Package storetest holds the store tests shared by every storage adapter so
+// each backend is held to the same behaviour.
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/domain"
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+// Store is the set of ports exercised by the suite.
+type Store interface {
+	ports.AppStore
+	ports.ServiceReadStore
+	ports.DeadLetterStore
+	ports.IngestCredentialStore
+	ports.ServiceIdentityStore
+	ports.EnvironmentStore
+	ports.ProjectionRebuildStore
+}
+
+// Backend is one empty storage backend opened for a single test.
+type Backend struct {
+	Store     Store
+	Ingestion ports.IngestionStoreFactory
+}
+
+// Opener opens a fresh backend and registers its cleanup on t.
+type Opener func(t *testing.T) Backend
+
+// Run runs the shared store tests against the backend returned by open.
+func Run(t *testing.T, open Opener) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, backend Backend)
+	}{
+		{"UpdateOrganizationSettingsPersistsAllFields", testUpdateOrganizationSettingsPersistsAllFields},
+		{"ReplaceServiceMetadataReplacesPreviousRows", testReplaceServiceMetadataReplacesPreviousRows},
+		{"UpdateOrganizationNameAndEnabled", testUpdateOrganizationNameAndEnabled},
+		{"DeleteOrganizationRemovesRow", testDeleteOrganizationRemovesRow},
+		{"IngestCredentialLifecycle", testIngestCredentialLifecycle},
+		{"IngestionStoreRoundTrip", testIngestionStoreRoundTrip},
+		{"IngestionStoreEventIdempotencyIsOrganizationScoped", testIngestionStoreEventIdempotencyIsOrganizationScoped},
+		{"IngestionStoreAppendEventsBatch", testIngestionStoreAppendEventsBatch},
+		{"ListDeploymentsPagesAndFilters", testListDeploymentsPagesAndFilters},
+		{"IngestionStoreDeadLettersArePrunedByAgeAndCount", testIngestionStoreDeadLettersArePrunedByAgeAndCount},
+		{"AppendedEventsUpdateServiceProjections", testAppendedEventsUpdateServiceProjections},
+		{"ServiceIdentityAndEnvironmentAliasesApplyToProjections", testServiceIdentityAndEnvironmentAliasesApplyToProjections},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.run(t, open(t))
+		})
+	}
+}
+
+func createOrganization(t *testing.T, store Store, name string) ports.Organization {
+	t.Helper()
+
+	org, err := store.CreateOrganization(context.Background(), ports.CreateOrganizationInput{
+		Name:          name,
+		AuthToken:     "token-" + name,
+		WebhookSecret: "secret-" + name,
+		Enabled:       true,
+	})
+	if err != nil {
+		t.Fatalf("create org %s: %v", name, err)
+	}
+	return org
+}
+
+func openIngestionStore(t *testing.T, backend Backend) ports.IngestionStore {
+	t.Helper()
+
+	store, err := backend.Ingestion.Open()
+	if err != nil {
+		t.Fatalf("open ingestion store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func testUpdateOrganizationSettingsPersistsAllFields(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	store := backend.Store
+	org := createOrganization(t, store, "org-a")
+
+	err := store.UpdateOrganizationSettings(ctx, org.ID, ports.OrganizationSettingsUpdate{
+		AuthToken:     "new-token",
+		WebhookSecret: "new-secret",
+		Enabled:       false,
+		RequiredFields: []ports.RequiredField{
+			{Label: "team", Type: "text", Filterable: true},
+			{Label: "tier", Type: "select", Filterable: false},
+		},
+		EnvironmentOrder: []string{"production", "staging"},
+		EventPolicies: []ports.EventSubjectPolicy{
+			{Subject: "Pipeline", Mode: "drop"},
+			{Subject: "service", Mode: "accept"},
+		},
+		WebhookSignatureScheme: "timestamped",
+	})
+	if err != nil {
+		t.Fatalf("update settings: %v", err)
+	}
+
+	updated, err := store.GetDefaultOrganization(ctx)
+	if err != nil {
+		t.Fatalf("get default org: %v", err)
+	}
+	if updated.AuthToken != "new-token" || updated.WebhookSecret != "new-secret" || updated.Enabled {
+		t.Fatalf("unexpected updated org: %+v", updated)
+	}
+
+	required, err := store.ListOrganizationRequiredFields(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("list required fields: %v", err)
+	}
+	if len(required) != 2 {
+		t.Fatalf("expected 2 required fields, got %d", len(required))
+	}
+	if required[0] != (ports.RequiredField{Label: "team", Type: "text", Filterable: true}) {
+		t.Fatalf("unexpected first required field: %+v", required[0])
+	}
+
+	envOrder, err := store.ListOrganizationEnvironmentPriorities(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("list environment priorities: %v", err)
+	}
+	if len(envOrder) != 2 || envOrder[0] != "production" || envOrder[1] != "staging" {
+		t.Fatalf("unexpected environment order: %+v", envOrder)
+	}
+
+	policies, err := store.ListOrganizationEventPolicies(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("list event policies: %v", err)
+	}
+	if len(policies) != 2 {
+		t.Fatalf("expected 2 event policies, got %+v", policies)
+	}
+	if policies[0] != (ports.EventSubjectPolicy{Subject: "pipeline", Mode: "drop"}) || policies[1] != (ports.EventSubjectPolicy{Subject: "service", Mode: "accept"}) {
+		t.Fatalf("unexpected event policies: %+v", policies)
+	}
+
+	scheme, err := openIngestionStore(t, backend).GetOrganizationPreference(ctx, org.ID, "webhook_signature_scheme")
+	if err != nil {
+		t.Fatalf("get signature scheme: %v", err)
+	}
+	if scheme != "timestamped" {
+		t.Fatalf("unexpected signature scheme: %q", scheme)
+	}
+}
+
+func testReplaceServiceMetadataReplacesPreviousRows(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	store := backend.Store
+	org := createOrganization(t, store, "org-b")
+
+	if err := store.ReplaceServiceMetadata(ctx, org.ID, "orders", []ports.MetadataValue{{Label: "team", Value: "platform"}, {Label: "tier", Value: "backend"}}); err != nil {
+		t.Fatalf("initial replace metadata: %v", err)
+	}
+
+	if err := store.ReplaceServiceMetadata(ctx, org.ID, "orders", []ports.MetadataValue{{Label: "team", Value: "core"}}); err != nil {
+		t.Fatalf("second replace metadata: %v", err)
+	}
+
+	rows, err := store.ListServiceMetadata(ctx, org.ID, "orders")
+	if err != nil {
+		t.Fatalf("list service metadata: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 metadata row after replacement, got %d", len(rows))
+	}
+	if rows[0].Label != "team" || rows[0].Value != "core" {
+		t.Fatalf("unexpected metadata row: %+v", rows[0])
+	}
+}
+
+func testUpdateOrganizationNameAndEnabled(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	store := backend.Store
+	org := createOrganization(t, store, "org-c")
+
+	if err := store.UpdateOrganizationName(ctx, org.ID, "org-c-renamed"); err != nil {
+		t.Fatalf("update org name: %v", err)
+	}
+	if err := store.UpdateOrganizationEnabled(ctx, org.ID, false); err != nil {
+		t.Fatalf("update org enabled: %v", err)
+	}
+
+	updated, err := store.GetOrganizationByID(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("get org by id: %v", err)
+	}
+	if updated.Name != "org-c-renamed" {
+		t.Fatalf("expected renamed org, got %q", updated.Name)
+	}
+	if updated.Enabled {
+		t.Fatalf("expected org to be disabled")
+	}
+}
+
+func testDeleteOrganizationRemovesRow(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	store := backend.Store
+	orgA := createOrganization(t, store, "org-d")
+	createOrganization(t, store, "org-e")
+
+	if err := store.DeleteOrganization(ctx, orgA.ID); err != nil {
+		t.Fatalf("delete org: %v", err)
+	}
+
+	rows, err := store.ListOrganizations(ctx)
+	if err != nil {
+		t.Fatalf("list orgs: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 organization left, got %d", len(rows))
+	}
+	if rows[0].Name != "org-e" {
+		t.Fatalf("expected org-e to remain, got %q", rows[0].Name)
+	}
+}
+
+func testIngestCredentialLifecycle(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	store := backend.Store
+	org := createOrganization(t, store, "org-f")
+
+	createdAt := time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC)
+	created, err := store.CreateIngestCredential(ctx, ports.IngestCredential{
+		OrganizationID: org.ID,
+		Label:          "ci",
+		AuthToken:      "token-f-ci",
+		WebhookSecret:  "secret-f-ci",
+		CreatedAt:      createdAt,
+	})
+	if err != nil {
+		t.Fatalf("create credential: %v", err)
+	}
+	if created.ID == 0 || created.ExpiresAt != nil || !created.CreatedAt.Equal(createdAt) || !created.Active(createdAt) {
+		t.Fatalf("unexpected created credential: %+v", created)
+	}
+
+	expiresAt := createdAt.Add(24 * time.Hour)
+	if ok, err := store.ExpireIngestCredential(ctx, org.ID, created.ID, expiresAt); err != nil || !ok {
+		t.Fatalf("expire credential: ok=%v err=%v", ok, err)
+	}
+	rows, err := store.ListIngestCredentials(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("list credentials: %v", err)
+	}
+	if len(rows) != 1 || rows[0].ExpiresAt == nil || !rows[0].ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("unexpected credentials after expire: %+v", rows)
+	}
+	if !rows[0].Active(expiresAt.Add(-time.Second)) || rows[0].Active(expiresAt) {
+		t.Fatalf("expected credential active only before expiry")
+	}
+
+	if ok, err := store.RevokeIngestCredential(ctx, org.ID+1, created.ID, createdAt); err != nil || ok {
+		t.Fatalf("expected revoke scoped to organization: ok=%v err=%v", ok, err)
+	}
+	if ok, err := store.RevokeIngestCredential(ctx, org.ID, created.ID, createdAt.Add(time.Hour)); err != nil || !ok {
+		t.Fatalf("revoke credential: ok=%v err=%v", ok, err)
+	}
+	if ok, err := store.ExpireIngestCredential(ctx, org.ID, created.ID, expiresAt); err != nil || ok {
+		t.Fatalf("expected revoked credential not to be expired again: ok=%v err=%v", ok, err)
+	}
+	rows, err = store.ListIngestCredentials(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("list credentials: %v", err)
+	}
+	if rows[0].RevokedAt == nil || rows[0].Active(createdAt) {
+		t.Fatalf("expected revoked credential: %+v", rows[0])
+	}
+}
+
+func testIngestionStoreRoundTrip(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	org := createOrganization(t, backend.Store, "ingest")
+
+	store, err := backend.Ingestion.Open()
+	if err != nil {
+		t.Fatalf("open ingestion store: %v", err)
+	}
+
+	loadedOrg, err := store.GetOrganizationByAuthToken(ctx, "token-ingest")
+	if err != nil {
+		t.Fatalf("get org by token: %v", err)
+	}
+	if loadedOrg.ID != org.ID || !loadedOrg.Enabled {
+		t.Fatalf("unexpected organization mapping: %+v", loadedOrg)
+	}
+
+	raw := `{"context":{"id":"evt-1","source":"tests/source","type":"dev.cdevents.service.deployed.0.3.0","timestamp":"2026-02-20T12:00:00Z","specversion":"0.5.0"},"subject":{"id":"service/orders","source":"tests/source","content":{"environment":{"id":"staging"},"artifactId":"pkg:generic/orders@v1"}}}`
+	subjectSource := "tests/source"
+	chainID := "chain-1"
+
+	err = store.AppendEvent(ctx, ports.EventRecord{
+		OrganizationID: org.ID,
+		EventID:        "evt-1",
+		EventType:      "dev.cdevents.service.deployed.0.3.0",
+		EventSource:    "tests/source",
+		EventTimestamp: "2026-02-20T12:00:00Z",
+		SubjectID:      "service/orders",
+		SubjectSource:  &subjectSource,
+		SubjectType:    "service",
+		ChainID:        &chainID,
+		RawEventJSON:   raw,
+	})
+	if err != nil {
+		t.Fatalf("append event: %v", err)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("close ingestion store: %v", err)
+	}
+
+	rows, err := backend.Store.ListDeployments(ctx, org.ID, ports.DeploymentFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("list deployments: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 deployment row, got %d", len(rows))
+	}
+	if rows[0].Service != "orders" || rows[0].Environment != "staging" {
+		t.Fatalf("unexpected deployment row: %+v", rows[0])
+	}
+}
+
+func testIngestionStoreEventIdempotencyIsOrganizationScoped(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	orgA := createOrganization(t, backend.Store, "org-a")
+	orgB := createOrganization(t, backend.Store, "org-b")
+
+	storeA := openIngestionStore(t, backend)
+	storeB := openIngestionStore(t, backend)
+
+	record := ports.EventRecord{
+		EventID:        "shared-event-id",
+		EventType:      "dev.cdevents.service.deployed.0.3.0",
+		EventSource:    "tests/source",
+		EventTimestamp: "2026-02-20T12:00:00Z",
+		SubjectID:      "service/orders",
+		SubjectType:    "service",
+		RawEventJSON:   `{"subject":{"content":{"environment":{"id":"staging"}}}}`,
+	}
+
+	record.OrganizationID = orgA.ID
+	if err := storeA.AppendEvent(ctx, record); err != nil {
+		t.Fatalf("append org a event: %v", err)
+	}
+	record.OrganizationID = orgB.ID
+	if err := storeB.AppendEvent(ctx, record); err != nil {
+		t.Fatalf("append org b event: %v", err)
+	}
+	if err := storeB.AppendEvent(ctx, record); err != nil {
+		t.Fatalf("append duplicate org b event: %v", err)
+	}
+
+	rowsA, err := backend.Store.ListDeployments(ctx, orgA.ID, ports.DeploymentFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("list org a deployments: %v", err)
+	}
+	if len(rowsA) != 1 {
+		t.Fatalf("expected 1 event for org a, got %d", len(rowsA))
+	}
+	rowsB, err := backend.Store.ListDeployments(ctx, orgB.ID, ports.DeploymentFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("list org b deployments: %v", err)
+	}
+	if len(rowsB) != 1 {
+		t.Fatalf("expected 1 event for org b, got %d", len(rowsB))
+	}
+}
+
+func testIngestionStoreAppendEventsBatch(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	org := createOrganization(t, backend.Store, "batch-org")
+	store := openIngestionStore(t, backend)
+
+	events := []ports.EventRecord{
+		{
+			OrganizationID: org.ID,
+			EventID:        "batch-1",
+			EventType:      "dev.cdevents.service.deployed.0.3.0",
+			EventSource:    "tests/source",
+			EventTimestamp: "2026-02-21T10:00:00Z",
+			EventTSMs:      1761069600000,
+			SubjectID:      "service/orders",
+			SubjectType:    "service",
+			RawEventJSON:   `{"subject":{"content":{"environment":{"id":"staging"},"artifactId":"pkg:generic/orders@v1"}}}`,
+		},
+		{
+			OrganizationID: org.ID,
+			EventID:        "batch-2",
+			EventType:      "dev.cdevents.service.upgraded.0.3.0",
+			EventSource:    "tests/source",
+			EventTimestamp: "2026-02-21T10:01:00Z",
+			EventTSMs:      1761069660000,
+			SubjectID:      "service/orders",
+			SubjectType:    "service",
+			RawEventJSON:   `{"subject":{"content":{"environment":{"id":"staging"},"artifactId":"pkg:generic/orders@v2"}}}`,
+		},
+	}
+
+	appended, err := store.AppendEvents(ctx, events)
+	if err != nil {
+		t.Fatalf("append batch: %v", err)
+	}
+	if len(appended) != 2 {
+		t.Fatalf("expected 2 appended events, got %d", len(appended))
+	}
+	if appended[0].ServiceName != "orders" || appended[1].Seq <= appended[0].Seq {
+		t.Fatalf("unexpected appended events: %+v", appended)
+	}
+
+	again, err := store.AppendEvents(ctx, events)
+	if err != nil {
+		t.Fatalf("append duplicate batch: %v", err)
+	}
+	if len(again) != 0 {
+		t.Fatalf("expected duplicates to be skipped, got %+v", again)
+	}
+
+	latest, err := backend.Store.GetLatestEventSeq(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("latest event seq: %v", err)
+	}
+	if latest != appended[1].Seq {
+		t.Fatalf("expected latest seq %d, got %d", appended[1].Seq, latest)
+	}
+	rows, err := backend.Store.ListDeployments(ctx, org.ID, ports.DeploymentFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("list deployments: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(rows))
+	}
+}
+
+func testListDeploymentsPagesAndFilters(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	org := createOrganization(t, backend.Store, "deployments-org")
+	store := openIngestionStore(t, backend)
+
+	start := time.Date(2026, 2, 21, 10, 0, 0, 0, time.UTC)
+	events := make([]ports.EventRecord, 0, 4)
+	for i, eventType := range []string{
+		"dev.cdevents.service.deployed.0.3.0",
+		"dev.cdevents.service.rolledback.0.3.0",
+		"dev.cdevents.service.deployed.0.3.0",
+		"dev.cdevents.service.upgraded.0.3.0",
+	} {
+		at := start.Add(time.Duration(i) * time.Hour)
+		events = append(events, ports.EventRecord{
+			OrganizationID: org.ID,
+			EventID:        fmt.Sprintf("page-%d", i),
+			EventType:      eventType,
+			EventSource:    "tests/source",
+			EventTimestamp: at.Format(time.RFC3339),
+			EventTSMs:      at.UnixMilli(),
+			SubjectID:      "service/orders",
+			SubjectType:    "service",
+			RawEventJSON:   `{"subject":{"content":{"environment":{"id":"staging"}}}}`,
+		})
+	}
+	if _, err := store.AppendEvents(ctx, events); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+
+	first, err := backend.Store.ListDeployments(ctx, org.ID, ports.DeploymentFilter{Limit: 3})
+	if err != nil {
+		t.Fatalf("list first page: %v", err)
+	}
+	if len(first) != 3 || first[0].EventTsMs != start.Add(3*time.Hour).UnixMilli() {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+	last := first[len(first)-1]
+	second, err := backend.Store.ListDeployments(ctx, org.ID, ports.DeploymentFilter{
+		Before: ports.DeploymentCursor{EventTsMs: last.EventTsMs, Seq: last.Seq},
+		Limit:  3,
+	})
+	if err != nil {
+		t.Fatalf("list second page: %v", err)
+	}
+	if len(second) != 1 || second[0].EventTsMs != start.UnixMilli() {
+		t.Fatalf("unexpected second page: %+v", second)
+	}
+
+	failed, err := backend.Store.ListDeployments(ctx, org.ID, ports.DeploymentFilter{Status: domain.DeploymentStatusError, Limit: 10})
+	if err != nil {
+		t.Fatalf("list failed deployments: %v", err)
+	}
+	if len(failed) != 1 || failed[0].Status != domain.DeploymentStatusError {
+		t.Fatalf("unexpected failed deployments: %+v", failed)
+	}
+
+	window, err := backend.Store.ListDeployments(ctx, org.ID, ports.DeploymentFilter{
+		From:  start.Add(time.Hour),
+		To:    start.Add(3 * time.Hour),
+		Limit: 10,
+	})
+	if err != nil {
+		t.Fatalf("list deployments in window: %v", err)
+	}
+	if len(window) != 2 {
+		t.Fatalf("expected 2 deployments in window, got %+v", window)
+	}
+}
+
+func testIngestionStoreDeadLettersArePrunedByAgeAndCount(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	org := createOrganization(t, backend.Store, "dead-letter-org")
+	store := openIngestionStore(t, backend)
+
+	retention := ports.DeadLetterRetention{MaxAge: 24 * time.Hour, MaxCount: 2}
+	now := time.Now().UTC()
+	for index, receivedAt := range []time.Time{now.Add(-48 * time.Hour), now.Add(-3 * time.Minute), now.Add(-2 * time.Minute), now.Add(-time.Minute)} {
+		err := store.AppendDeadLetter(ctx, ports.DeadLetter{
+			OrganizationID: org.ID,
+			Reason:         "invalid_schema",
+			Detail:         "invalid schema",
+			Headers:        map[string][]string{"Content-Type": {"application/json"}},
+			Body:           []byte(fmt.Sprintf(`{"n":%d}`, index)),
+			BodySize:       7,
+			ReceivedAt:     receivedAt,
+		}, retention)
+		if err != nil {
+			t.Fatalf("append dead letter %d: %v", index, err)
+		}
+	}
+
+	letters, err := backend.Store.ListDeadLetters(ctx, org.ID, 10)
+	if err != nil {
+		t.Fatalf("list dead letters: %v", err)
+	}
+	if len(letters) != 2 {
+		t.Fatalf("expected 2 retained dead letters, got %+v", letters)
+	}
+	if string(letters[0].Body) != `{"n":3}` || string(letters[1].Body) != `{"n":2}` {
+		t.Fatalf("unexpected retained dead letters: %s, %s", letters[0].Body, letters[1].Body)
+	}
+	if letters[0].Headers["Content-Type"][0] != "application/json" || letters[0].Reason != "invalid_schema" {
+		t.Fatalf("unexpected dead letter mapping: %+v", letters[0])
+	}
+}
+
+func testAppendedEventsUpdateServiceProjections(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	org := createOrganization(t, backend.Store, "projection-org")
+	store := openIngestionStore(t, backend)
+
+	base := time.Now().UTC().Add(-2 * time.Hour).Truncate(time.Second)
+	event := func(id, eventType string, at time.Time, environment, artifact string) ports.EventRecord {
+		return ports.EventRecord{
+			OrganizationID: org.ID,
+			EventID:        id,
+			EventType:      eventType,
+			EventSource:    "tests/source",
+			EventTimestamp: at.Format(time.RFC3339),
+			EventTSMs:      at.UnixMilli(),
+			SubjectID:      "service/payments",
+			SubjectType:    "service",
+			RawEventJSON:   fmt.Sprintf(`{"subject":{"content":{"environment":{"id":%q},"artifactId":%q}}}`, environment, artifact),
+		}
+	}
+	if _, err := store.AppendEvents(ctx, []ports.EventRecord{
+		event("deploy-1", "dev.cdevents.service.deployed.0.3.0", base, "staging", "pkg:generic/payments@v1"),
+		event("deploy-2", "dev.cdevents.service.deployed.0.3.0", base.Add(time.Minute), "production", "pkg:generic/payments@v1"),
+		event("deploy-3", "dev.cdevents.service.deployed.0.3.0", base.Add(2*time.Minute), "staging", "pkg:generic/payments@v2"),
+		event("rollback-1", "dev.cdevents.service.rolledback.0.3.0", base.Add(3*time.Minute), "staging", "pkg:generic/payments@v1"),
+	}); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+
+	environments, err := backend.Store.ListServiceEnvironments(ctx, org.ID, "payments")
+	if err != nil {
+		t.Fatalf("list service environments: %v", err)
+	}
+	if len(environments) != 2 {
+		t.Fatalf("expected 2 environments, got %+v", environments)
+	}
+
+	state, err := backend.Store.GetServiceCurrentState(ctx, org.ID, "payments")
+	if err != nil {
+		t.Fatalf("get current state: %v", err)
+	}
+	if state.LastEventTSMs != base.Add(3*time.Minute).UnixMilli() || state.LastStatus == "" {
+		t.Fatalf("unexpected current state: %+v", state)
+	}
+
+	stats, err := backend.Store.GetServiceDeliveryStats30d(ctx, org.ID, "payments")
+	if err != nil {
+		t.Fatalf("get delivery stats: %v", err)
+	}
+	if stats.Success30d != 3 || stats.Rollbacks30d != 1 {
+		t.Fatalf("unexpected delivery stats: %+v", stats)
+	}
+
+	discovered, err := backend.Store.ListDiscoveredEnvironments(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("list discovered environments: %v", err)
+	}
+	if len(discovered) != 2 {
+		t.Fatalf("expected 2 discovered environments, got %+v", discovered)
+	}
+}
+
+func testServiceIdentityAndEnvironmentAliasesApplyToProjections(t *testing.T, backend Backend) {
+	ctx := context.Background()
+	org := createOrganization(t, backend.Store, "alias-org")
+	store := openIngestionStore(t, backend)
+
+	base := time.Now().UTC().Add(-2 * time.Hour).Truncate(time.Second)
+	deploy := func(id string, at time.Time, environment string) ports.EventRecord {
+		return ports.EventRecord{
+			OrganizationID: org.ID,
+			EventID:        id,
+			EventType:      "dev.cdevents.service.deployed.0.3.0",
+			EventSource:    "tests/source",
+			EventTimestamp: at.Format(time.RFC3339),
+			EventTSMs:      at.UnixMilli(),
+			SubjectID:      "service/payments-api",
+			SubjectType:    "service",
+			RawEventJSON:   fmt.Sprintf(`{"subject":{"content":{"environment":{"id":%q},"artifactId":"pkg:generic/payments-api@v1"}}}`, environment),
+		}
+	}
+	if _, err := store.AppendEvents(ctx, []ports.EventRecord{
+		deploy("deploy-1", base, "staging"),
+		deploy("deploy-2", base.Add(time.Minute), "prod"),
+	}); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+
+	if _, err := backend.Store.CreateServiceIdentityRule(ctx, org.ID, ports.ServiceIdentityRule{
+		Kind:        "alias",
+		Pattern:     "payments-api",
+		ServiceName: "payments",
+	}); err != nil {
+		t.Fatalf("create service identity rule: %v", err)
+	}
+	if _, err := backend.Store.SaveEnvironment(ctx, org.ID, ports.Environment{
+		Name:    "production",
+		Tier:    "production",
+		Aliases: []string{"prod"},
+	}); err != nil {
+		t.Fatalf("save environment: %v", err)
+	}
+	if err := backend.Store.RebuildOrganizationProjections(ctx, org.ID, nil); err != nil {
+		t.Fatalf("rebuild projections: %v", err)
+	}
+	// Appended after the rule and the alias exist, so mapped without a rebuild.
+	if _, err := store.AppendEvents(ctx, []ports.EventRecord{deploy("deploy-3", base.Add(2*time.Minute), "PROD")}); err != nil {
+		t.Fatalf("append event: %v", err)
+	}
+
+	resolved, err := backend.Store.ResolveServiceName(ctx, org.ID, "payments-api")
+	if err != nil || resolved != "payments" {
+		t.Fatalf("expected payments-api to resolve to payments, got %q (%v)", resolved, err)
+	}
+
+	environments, err := backend.Store.ListServiceEnvironments(ctx, org.ID, "payments")
+	if err != nil {
+		t.Fatalf("list service environments: %v", err)
+	}
+	names := make([]string, 0, len(environments))
+	for _, environment := range environments {
+		names = append(names, environment.Name)
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"production", "staging"}) {
+		t.Fatalf("expected production and staging, got %v", names)
+	}
+
+	state, err := backend.Store.GetServiceCurrentState(ctx, org.ID, "payments")
+	if err != nil {
+		t.Fatalf("get current state: %v", err)
+	}
+	if state.LastEventTSMs != base.Add(2*time.Minute).UnixMilli() {
+		t.Fatalf("unexpected current state: %+v", state)
+	}
+
+	stats, err := backend.Store.GetServiceDeliveryStats30d(ctx, org.ID, "payments")
+	if err != nil {
+		t.Fatalf("get delivery stats: %v", err)
+	}
+	if stats.Success30d != 3 {
+		t.Fatalf("expected 3 deployments under payments, got %+v", stats)
+	}
+
+	discovered, err := backend.Store.ListDiscoveredEnvironments(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("list discovered environments: %v", err)
+	}
+	if !slices.Contains(discovered, "production") || slices.Contains(discovered, "prod") || slices.Contains(discovered, "PROD") {
+		t.Fatalf("expected prod to be discovered as production, got %v", discovered)
+	}
+}
@@ -65,6 +65,9 @@ type DeploymentRow struct {
 	DeployedAt   string
 	Status       DeploymentStatus
 	MetadataTags string
+	// Seq and EventTsMs place the row in the listing, newest first.
+	Seq       int64
+	EventTsMs int64
 }
 
 // ServiceEnvironment is one environment row in service details.
@@ -87,6 +90,7 @@ type DeploymentRecord struct {
 	DeployedAt  string
 	Environment string
 	DeployedAgo string
+	ChainID     string
 }
 
 // ServiceRiskEvent is one recent risk/audit event link.
@@ -2,13 +2,21 @@ package ports
 
 import (
 	"context"
+	"time"
 )
 
 // IngestionStore is the minimal storage contract needed by webhook ingestion.
 type IngestionStore interface {
 	GetOrganizationByAuthToken(ctx context.Context, token string) (Organization, error)
+	ListOrganizationEventPolicies(ctx context.Context, organizationID int64) ([]EventSubjectPolicy, error)
+	GetOrganizationPreference(ctx context.Context, organizationID int64, key string) (string, error)
+	RememberWebhookSignature(ctx context.Context, organizationID int64, signature string, seenAt, expiresAt time.Time) (bool, error)
+	ListIngestCredentials(ctx context.Context, organizationID int64) ([]IngestCredential, error)
+	TouchIngestCredential(ctx context.Context, organizationID, id int64, usedAt time.Time) error
+	RecordIngestOutcome(ctx context.Context, organizationID int64, outcome, reason string, count int64, at time.Time) error
 	AppendEvent(ctx context.Context, event EventRecord) error
-	AppendEvents(ctx context.Context, events []EventRecord) error
+	AppendEvents(ctx context.Context, events []EventRecord) ([]AppendedEvent, error)
+	AppendDeadLetter(ctx context.Context, letter DeadLetter, retention DeadLetterRetention) error
 	Close() error
 }
 
@@ -27,7 +35,112 @@ type EventRecord struct {
 	RawEventJSON   string
 }
 
+// AppendedEvent describes one event newly committed to the event store.
+type AppendedEvent struct {
+	OrganizationID int64
+	EventID        string
+	Seq            int64
+	SubjectType    string
+	ServiceName    string
+	// Queued marks an event accepted into the ingest spool but not yet
+	// appended; Seq and ServiceName are unset until it drains.
+	Queued bool
+}
+
+// ServiceChange signals that projections for one service were updated.
+type ServiceChange struct {
+	OrganizationID int64
+	ServiceName    string
+	Seq            int64
+}
+
+// ServiceChangePublisher receives service changes after events are committed.
+type ServiceChangePublisher interface {
+	PublishServiceChanges(changes []ServiceChange)
+}
+
 // IngestionStoreFactory creates request-scoped ingestion stores.
 type IngestionStoreFactory interface {
 	Open() (IngestionStore, error)
 }
+
+// DeadLetter is one rejected webhook delivery kept for inspection and replay.
+type DeadLetter struct {
+	ID             int64
+	OrganizationID int64
+	Reason         string
+	Detail         string
+	Headers        map[string][]string
+	Body           []byte
+	BodySize       int64
+	Truncated      bool
+	ReceivedAt     time.Time
+	ReplayedAt     *time.Time
+	ReplayResult   string
+}
+
+// DeadLetterRetention bounds how many rejected deliveries one organization keeps.
+type DeadLetterRetention struct {
+	MaxAge   time.Duration
+	MaxCount int64
+}
+
+// DeadLetterStore reads and updates rejected webhook deliveries.
+type DeadLetterStore interface {
+	ListDeadLetters(ctx context.Context, organizationID int64, limit int64) ([]DeadLetter, error)
+	GetDeadLetter(ctx context.Context, organizationID, id int64) (DeadLetter, error)
+	MarkDeadLetterReplayed(ctx context.Context, organizationID, id int64, result string, replayedAt time.Time) error
+	DeleteDeadLetter(ctx context.Context, organizationID, id int64) error
+}
+
+// IngestCredential is one additional auth token and webhook secret pair an
+// organization accepts alongside its primary credentials.
+type IngestCredential struct {
+	ID             int64
+	OrganizationID int64
+	Label          string
+	AuthToken      string
+	WebhookSecret  string
+	CreatedAt      time.Time
+	ExpiresAt      *time.Time
+	RevokedAt      *time.Time
+	LastUsedAt     *time.Time
+}
+
+// Active reports whether the credential is neither revoked nor expired at now.
+func (c IngestCredential) Active(now time.Time) bool {
+	if c.RevokedAt != nil {
+		return false
+	}
+	return c.ExpiresAt == nil || c.ExpiresAt.After(now)
+}
+
+// IngestCredentialStore manages the additional ingest credentials of an organization.
+type IngestCredentialStore interface {
+	ListIngestCredentials(ctx context.Context, organizationID int64) ([]IngestCredential, error)
+	CreateIngestCredential(ctx context.Context, credential IngestCredential) (IngestCredential, error)
+	ExpireIngestCredential(ctx context.Context, organizationID, id int64, expiresAt time.Time) (bool, error)
+	RevokeIngestCredential(ctx context.Context, organizationID, id int64, revokedAt time.Time) (bool, error)
+}
+
+// IngestHourlyStat is the number of events with one outcome and reason in one hour.
+type IngestHourlyStat struct {
+	BucketStart time.Time
+	Outcome     string
+	Reason      string
+	Count       int64
+}
+
+// IngestSourceActivity is the most recent accepted event of one event source.
+type IngestSourceActivity struct {
+	Source        string
+	LastEventID   string
+	LastEventType string
+	LastSeenAt    time.Time
+}
+
+// IngestHealthStore reads rolled-up ingestion outcomes of an organization.
+type IngestHealthStore interface {
+	ListIngestHourlyStats(ctx context.Context, organizationID int64, since time.Time) ([]IngestHourlyStat, error)
+	ListIngestSourceActivity(ctx context.Context, organizationID int64, limit int64) ([]IngestSourceActivity, error)
+}
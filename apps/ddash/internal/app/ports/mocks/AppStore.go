@@ -828,6 +828,74 @@ func (_c *MockAppStore_ListOrganizationEnvironmentPriorities_Call) RunAndReturn(
 	return _c
 }
 
+// ListOrganizationEventPolicies provides a mock function for the type MockAppStore
+func (_mock *MockAppStore) ListOrganizationEventPolicies(ctx context.Context, organizationID int64) ([]ports.EventSubjectPolicy, error) {
+	ret := _mock.Called(ctx, organizationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOrganizationEventPolicies")
+	}
+
+	var r0 []ports.EventSubjectPolicy
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) ([]ports.EventSubjectPolicy, error)); ok {
+		return returnFunc(ctx, organizationID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) []ports.EventSubjectPolicy); ok {
+		r0 = returnFunc(ctx, organizationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]ports.EventSubjectPolicy)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = returnFunc(ctx, organizationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAppStore_ListOrganizationEventPolicies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListOrganizationEventPolicies'
+type MockAppStore_ListOrganizationEventPolicies_Call struct {
+	*mock.Call
+}
+
+// ListOrganizationEventPolicies is a helper method to define mock.On call
+//   - ctx context.Context
+//   - organizationID int64
+func (_e *MockAppStore_Expecter) ListOrganizationEventPolicies(ctx interface{}, organizationID interface{}) *MockAppStore_ListOrganizationEventPolicies_Call {
+	return &MockAppStore_ListOrganizationEventPolicies_Call{Call: _e.mock.On("ListOrganizationEventPolicies", ctx, organizationID)}
+}
+
+func (_c *MockAppStore_ListOrganizationEventPolicies_Call) Run(run func(ctx context.Context, organizationID int64)) *MockAppStore_ListOrganizationEventPolicies_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAppStore_ListOrganizationEventPolicies_Call) Return(eventSubjectPolicys []ports.EventSubjectPolicy, err error) *MockAppStore_ListOrganizationEventPolicies_Call {
+	_c.Call.Return(eventSubjectPolicys, err)
+	return _c
+}
+
+func (_c *MockAppStore_ListOrganizationEventPolicies_Call) RunAndReturn(run func(ctx context.Context, organizationID int64) ([]ports.EventSubjectPolicy, error)) *MockAppStore_ListOrganizationEventPolicies_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListOrganizationFeatures provides a mock function for the type MockAppStore
 func (_mock *MockAppStore) ListOrganizationFeatures(ctx context.Context, organizationID int64) ([]ports.OrganizationFeature, error) {
 	ret := _mock.Called(ctx, organizationID)
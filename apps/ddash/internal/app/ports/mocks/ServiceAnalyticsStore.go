@@ -347,8 +347,8 @@ func (_c *MockServiceAnalyticsStore_GetEnvironmentDriftCount_Call) RunAndReturn(
 }
 
 // GetMTTR provides a mock function for the type MockServiceAnalyticsStore
-func (_mock *MockServiceAnalyticsStore) GetMTTR(ctx context.Context, organizationID int64, sinceMs int64) (ports.MTTRStats, error) {
-	ret := _mock.Called(ctx, organizationID, sinceMs)
+func (_mock *MockServiceAnalyticsStore) GetMTTR(ctx context.Context, organizationID int64, service string, sinceMs int64) (ports.MTTRStats, error) {
+	ret := _mock.Called(ctx, organizationID, service, sinceMs)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetMTTR")
@@ -356,16 +356,16 @@ func (_mock *MockServiceAnalyticsStore) GetMTTR(ctx context.Context, organizatio
 
 	var r0 ports.MTTRStats
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, int64) (ports.MTTRStats, error)); ok {
-		return returnFunc(ctx, organizationID, sinceMs)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, string, int64) (ports.MTTRStats, error)); ok {
+		return returnFunc(ctx, organizationID, service, sinceMs)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, int64) ports.MTTRStats); ok {
-		r0 = returnFunc(ctx, organizationID, sinceMs)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, string, int64) ports.MTTRStats); ok {
+		r0 = returnFunc(ctx, organizationID, service, sinceMs)
 	} else {
 		r0 = ret.Get(0).(ports.MTTRStats)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
-		r1 = returnFunc(ctx, organizationID, sinceMs)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int64, string, int64) error); ok {
+		r1 = returnFunc(ctx, organizationID, service, sinceMs)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -380,12 +380,13 @@ type MockServiceAnalyticsStore_GetMTTR_Call struct {
 // GetMTTR is a helper method to define mock.On call
 //   - ctx context.Context
 //   - organizationID int64
+//   - service string
 //   - sinceMs int64
-func (_e *MockServiceAnalyticsStore_Expecter) GetMTTR(ctx interface{}, organizationID interface{}, sinceMs interface{}) *MockServiceAnalyticsStore_GetMTTR_Call {
-	return &MockServiceAnalyticsStore_GetMTTR_Call{Call: _e.mock.On("GetMTTR", ctx, organizationID, sinceMs)}
+func (_e *MockServiceAnalyticsStore_Expecter) GetMTTR(ctx interface{}, organizationID interface{}, service interface{}, sinceMs interface{}) *MockServiceAnalyticsStore_GetMTTR_Call {
+	return &MockServiceAnalyticsStore_GetMTTR_Call{Call: _e.mock.On("GetMTTR", ctx, organizationID, service, sinceMs)}
 }
 
-func (_c *MockServiceAnalyticsStore_GetMTTR_Call) Run(run func(ctx context.Context, organizationID int64, sinceMs int64)) *MockServiceAnalyticsStore_GetMTTR_Call {
+func (_c *MockServiceAnalyticsStore_GetMTTR_Call) Run(run func(ctx context.Context, organizationID int64, service string, sinceMs int64)) *MockServiceAnalyticsStore_GetMTTR_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -395,14 +396,19 @@ func (_c *MockServiceAnalyticsStore_GetMTTR_Call) Run(run func(ctx context.Conte
 		if args[1] != nil {
 			arg1 = args[1].(int64)
 		}
-		var arg2 int64
+		var arg2 string
 		if args[2] != nil {
-			arg2 = args[2].(int64)
+			arg2 = args[2].(string)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
@@ -413,7 +419,7 @@ func (_c *MockServiceAnalyticsStore_GetMTTR_Call) Return(mTTRStats ports.MTTRSta
 	return _c
 }
 
-func (_c *MockServiceAnalyticsStore_GetMTTR_Call) RunAndReturn(run func(ctx context.Context, organizationID int64, sinceMs int64) (ports.MTTRStats, error)) *MockServiceAnalyticsStore_GetMTTR_Call {
+func (_c *MockServiceAnalyticsStore_GetMTTR_Call) RunAndReturn(run func(ctx context.Context, organizationID int64, service string, sinceMs int64) (ports.MTTRStats, error)) *MockServiceAnalyticsStore_GetMTTR_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/fr0stylo/ddash/apps/ddash/internal/app/domain"
 	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
@@ -108,6 +109,72 @@ func (_c *MockServiceQueryStore_DeleteServiceDependency_Call) RunAndReturn(run f
 	return _c
 }
 
+// GetLatestEventSeq provides a mock function for the type MockServiceQueryStore
+func (_mock *MockServiceQueryStore) GetLatestEventSeq(ctx context.Context, organizationID int64) (int64, error) {
+	ret := _mock.Called(ctx, organizationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestEventSeq")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) (int64, error)); ok {
+		return returnFunc(ctx, organizationID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) int64); ok {
+		r0 = returnFunc(ctx, organizationID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = returnFunc(ctx, organizationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockServiceQueryStore_GetLatestEventSeq_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLatestEventSeq'
+type MockServiceQueryStore_GetLatestEventSeq_Call struct {
+	*mock.Call
+}
+
+// GetLatestEventSeq is a helper method to define mock.On call
+//   - ctx context.Context
+//   - organizationID int64
+func (_e *MockServiceQueryStore_Expecter) GetLatestEventSeq(ctx interface{}, organizationID interface{}) *MockServiceQueryStore_GetLatestEventSeq_Call {
+	return &MockServiceQueryStore_GetLatestEventSeq_Call{Call: _e.mock.On("GetLatestEventSeq", ctx, organizationID)}
+}
+
+func (_c *MockServiceQueryStore_GetLatestEventSeq_Call) Run(run func(ctx context.Context, organizationID int64)) *MockServiceQueryStore_GetLatestEventSeq_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockServiceQueryStore_GetLatestEventSeq_Call) Return(n int64, err error) *MockServiceQueryStore_GetLatestEventSeq_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockServiceQueryStore_GetLatestEventSeq_Call) RunAndReturn(run func(ctx context.Context, organizationID int64) (int64, error)) *MockServiceQueryStore_GetLatestEventSeq_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetOrganizationRenderVersion provides a mock function for the type MockServiceQueryStore
 func (_mock *MockServiceQueryStore) GetOrganizationRenderVersion(ctx context.Context, organizationID int64) (int64, error) {
 	ret := _mock.Called(ctx, organizationID)
@@ -246,6 +313,80 @@ func (_c *MockServiceQueryStore_GetServiceLatest_Call) RunAndReturn(run func(ctx
 	return _c
 }
 
+// ListDeploymentEnvironmentCounts provides a mock function for the type MockServiceQueryStore
+func (_mock *MockServiceQueryStore) ListDeploymentEnvironmentCounts(ctx context.Context, organizationID int64, now time.Time) ([]ports.DeploymentEnvironmentCount, error) {
+	ret := _mock.Called(ctx, organizationID, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeploymentEnvironmentCounts")
+	}
+
+	var r0 []ports.DeploymentEnvironmentCount
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, time.Time) ([]ports.DeploymentEnvironmentCount, error)); ok {
+		return returnFunc(ctx, organizationID, now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, time.Time) []ports.DeploymentEnvironmentCount); ok {
+		r0 = returnFunc(ctx, organizationID, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]ports.DeploymentEnvironmentCount)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = returnFunc(ctx, organizationID, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockServiceQueryStore_ListDeploymentEnvironmentCounts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListDeploymentEnvironmentCounts'
+type MockServiceQueryStore_ListDeploymentEnvironmentCounts_Call struct {
+	*mock.Call
+}
+
+// ListDeploymentEnvironmentCounts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - organizationID int64
+//   - now time.Time
+func (_e *MockServiceQueryStore_Expecter) ListDeploymentEnvironmentCounts(ctx interface{}, organizationID interface{}, now interface{}) *MockServiceQueryStore_ListDeploymentEnvironmentCounts_Call {
+	return &MockServiceQueryStore_ListDeploymentEnvironmentCounts_Call{Call: _e.mock.On("ListDeploymentEnvironmentCounts", ctx, organizationID, now)}
+}
+
+func (_c *MockServiceQueryStore_ListDeploymentEnvironmentCounts_Call) Run(run func(ctx context.Context, organizationID int64, now time.Time)) *MockServiceQueryStore_ListDeploymentEnvironmentCounts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockServiceQueryStore_ListDeploymentEnvironmentCounts_Call) Return(deploymentEnvironmentCounts []ports.DeploymentEnvironmentCount, err error) *MockServiceQueryStore_ListDeploymentEnvironmentCounts_Call {
+	_c.Call.Return(deploymentEnvironmentCounts, err)
+	return _c
+}
+
+func (_c *MockServiceQueryStore_ListDeploymentEnvironmentCounts_Call) RunAndReturn(run func(ctx context.Context, organizationID int64, now time.Time) ([]ports.DeploymentEnvironmentCount, error)) *MockServiceQueryStore_ListDeploymentEnvironmentCounts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListDeploymentHistory provides a mock function for the type MockServiceQueryStore
 func (_mock *MockServiceQueryStore) ListDeploymentHistory(ctx context.Context, organizationID int64, service string, limit int64) ([]domain.DeploymentRecord, error) {
 	ret := _mock.Called(ctx, organizationID, service, limit)
@@ -326,9 +467,77 @@ func (_c *MockServiceQueryStore_ListDeploymentHistory_Call) RunAndReturn(run fun
 	return _c
 }
 
+// ListDeploymentServices provides a mock function for the type MockServiceQueryStore
+func (_mock *MockServiceQueryStore) ListDeploymentServices(ctx context.Context, organizationID int64) ([]string, error) {
+	ret := _mock.Called(ctx, organizationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeploymentServices")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) ([]string, error)); ok {
+		return returnFunc(ctx, organizationID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64) []string); ok {
+		r0 = returnFunc(ctx, organizationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = returnFunc(ctx, organizationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockServiceQueryStore_ListDeploymentServices_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListDeploymentServices'
+type MockServiceQueryStore_ListDeploymentServices_Call struct {
+	*mock.Call
+}
+
+// ListDeploymentServices is a helper method to define mock.On call
+//   - ctx context.Context
+//   - organizationID int64
+func (_e *MockServiceQueryStore_Expecter) ListDeploymentServices(ctx interface{}, organizationID interface{}) *MockServiceQueryStore_ListDeploymentServices_Call {
+	return &MockServiceQueryStore_ListDeploymentServices_Call{Call: _e.mock.On("ListDeploymentServices", ctx, organizationID)}
+}
+
+func (_c *MockServiceQueryStore_ListDeploymentServices_Call) Run(run func(ctx context.Context, organizationID int64)) *MockServiceQueryStore_ListDeploymentServices_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockServiceQueryStore_ListDeploymentServices_Call) Return(strings []string, err error) *MockServiceQueryStore_ListDeploymentServices_Call {
+	_c.Call.Return(strings, err)
+	return _c
+}
+
+func (_c *MockServiceQueryStore_ListDeploymentServices_Call) RunAndReturn(run func(ctx context.Context, organizationID int64) ([]string, error)) *MockServiceQueryStore_ListDeploymentServices_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListDeployments provides a mock function for the type MockServiceQueryStore
-func (_mock *MockServiceQueryStore) ListDeployments(ctx context.Context, organizationID int64, env string, service string) ([]domain.DeploymentRow, error) {
-	ret := _mock.Called(ctx, organizationID, env, service)
+func (_mock *MockServiceQueryStore) ListDeployments(ctx context.Context, organizationID int64, filter ports.DeploymentFilter) ([]domain.DeploymentRow, error) {
+	ret := _mock.Called(ctx, organizationID, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListDeployments")
@@ -336,18 +545,18 @@ func (_mock *MockServiceQueryStore) ListDeployments(ctx context.Context, organiz
 
 	var r0 []domain.DeploymentRow
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, string, string) ([]domain.DeploymentRow, error)); ok {
-		return returnFunc(ctx, organizationID, env, service)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, ports.DeploymentFilter) ([]domain.DeploymentRow, error)); ok {
+		return returnFunc(ctx, organizationID, filter)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, string, string) []domain.DeploymentRow); ok {
-		r0 = returnFunc(ctx, organizationID, env, service)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, ports.DeploymentFilter) []domain.DeploymentRow); ok {
+		r0 = returnFunc(ctx, organizationID, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.DeploymentRow)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
-		r1 = returnFunc(ctx, organizationID, env, service)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int64, ports.DeploymentFilter) error); ok {
+		r1 = returnFunc(ctx, organizationID, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -362,13 +571,12 @@ type MockServiceQueryStore_ListDeployments_Call struct {
 // ListDeployments is a helper method to define mock.On call
 //   - ctx context.Context
 //   - organizationID int64
-//   - env string
-//   - service string
-func (_e *MockServiceQueryStore_Expecter) ListDeployments(ctx interface{}, organizationID interface{}, env interface{}, service interface{}) *MockServiceQueryStore_ListDeployments_Call {
-	return &MockServiceQueryStore_ListDeployments_Call{Call: _e.mock.On("ListDeployments", ctx, organizationID, env, service)}
+//   - filter ports.DeploymentFilter
+func (_e *MockServiceQueryStore_Expecter) ListDeployments(ctx interface{}, organizationID interface{}, filter interface{}) *MockServiceQueryStore_ListDeployments_Call {
+	return &MockServiceQueryStore_ListDeployments_Call{Call: _e.mock.On("ListDeployments", ctx, organizationID, filter)}
 }
 
-func (_c *MockServiceQueryStore_ListDeployments_Call) Run(run func(ctx context.Context, organizationID int64, env string, service string)) *MockServiceQueryStore_ListDeployments_Call {
+func (_c *MockServiceQueryStore_ListDeployments_Call) Run(run func(ctx context.Context, organizationID int64, filter ports.DeploymentFilter)) *MockServiceQueryStore_ListDeployments_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -378,13 +586,88 @@ func (_c *MockServiceQueryStore_ListDeployments_Call) Run(run func(ctx context.C
 		if args[1] != nil {
 			arg1 = args[1].(int64)
 		}
-		var arg2 string
+		var arg2 ports.DeploymentFilter
 		if args[2] != nil {
-			arg2 = args[2].(string)
+			arg2 = args[2].(ports.DeploymentFilter)
 		}
-		var arg3 string
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockServiceQueryStore_ListDeployments_Call) Return(deploymentRows []domain.DeploymentRow, err error) *MockServiceQueryStore_ListDeployments_Call {
+	_c.Call.Return(deploymentRows, err)
+	return _c
+}
+
+func (_c *MockServiceQueryStore_ListDeployments_Call) RunAndReturn(run func(ctx context.Context, organizationID int64, filter ports.DeploymentFilter) ([]domain.DeploymentRow, error)) *MockServiceQueryStore_ListDeployments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListServiceChangesAfterSeq provides a mock function for the type MockServiceQueryStore
+func (_mock *MockServiceQueryStore) ListServiceChangesAfterSeq(ctx context.Context, organizationID int64, afterSeq int64, limit int64) ([]ports.ServiceChange, error) {
+	ret := _mock.Called(ctx, organizationID, afterSeq, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListServiceChangesAfterSeq")
+	}
+
+	var r0 []ports.ServiceChange
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, int64, int64) ([]ports.ServiceChange, error)); ok {
+		return returnFunc(ctx, organizationID, afterSeq, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int64, int64, int64) []ports.ServiceChange); ok {
+		r0 = returnFunc(ctx, organizationID, afterSeq, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]ports.ServiceChange)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int64, int64, int64) error); ok {
+		r1 = returnFunc(ctx, organizationID, afterSeq, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockServiceQueryStore_ListServiceChangesAfterSeq_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListServiceChangesAfterSeq'
+type MockServiceQueryStore_ListServiceChangesAfterSeq_Call struct {
+	*mock.Call
+}
+
+// ListServiceChangesAfterSeq is a helper method to define mock.On call
+//   - ctx context.Context
+//   - organizationID int64
+//   - afterSeq int64
+//   - limit int64
+func (_e *MockServiceQueryStore_Expecter) ListServiceChangesAfterSeq(ctx interface{}, organizationID interface{}, afterSeq interface{}, limit interface{}) *MockServiceQueryStore_ListServiceChangesAfterSeq_Call {
+	return &MockServiceQueryStore_ListServiceChangesAfterSeq_Call{Call: _e.mock.On("ListServiceChangesAfterSeq", ctx, organizationID, afterSeq, limit)}
+}
+
+func (_c *MockServiceQueryStore_ListServiceChangesAfterSeq_Call) Run(run func(ctx context.Context, organizationID int64, afterSeq int64, limit int64)) *MockServiceQueryStore_ListServiceChangesAfterSeq_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		var arg3 int64
 		if args[3] != nil {
-			arg3 = args[3].(string)
+			arg3 = args[3].(int64)
 		}
 		run(
 			arg0,
@@ -396,12 +679,12 @@ func (_c *MockServiceQueryStore_ListDeployments_Call) Run(run func(ctx context.C
 	return _c
 }
 
-func (_c *MockServiceQueryStore_ListDeployments_Call) Return(deploymentRows []domain.DeploymentRow, err error) *MockServiceQueryStore_ListDeployments_Call {
-	_c.Call.Return(deploymentRows, err)
+func (_c *MockServiceQueryStore_ListServiceChangesAfterSeq_Call) Return(serviceChanges []ports.ServiceChange, err error) *MockServiceQueryStore_ListServiceChangesAfterSeq_Call {
+	_c.Call.Return(serviceChanges, err)
 	return _c
 }
 
-func (_c *MockServiceQueryStore_ListDeployments_Call) RunAndReturn(run func(ctx context.Context, organizationID int64, env string, service string) ([]domain.DeploymentRow, error)) *MockServiceQueryStore_ListDeployments_Call {
+func (_c *MockServiceQueryStore_ListServiceChangesAfterSeq_Call) RunAndReturn(run func(ctx context.Context, organizationID int64, afterSeq int64, limit int64) ([]ports.ServiceChange, error)) *MockServiceQueryStore_ListServiceChangesAfterSeq_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/fr0stylo/ddash/apps/ddash/internal/app/domain"
 )
@@ -118,17 +119,20 @@ type ArtifactAge struct {
 }
 
 type MTTRStats struct {
-	IncidentCount int64
-	MTTRSeconds   float64
-	MTTDSeconds   float64
-	MTTESeconds   float64
+	IncidentCount     int64
+	OpenIncidentCount int64
+	MTTRSeconds       float64
+	MTTDSeconds       float64
+	MTTESeconds       float64
 }
 
 type IncidentLink struct {
 	IncidentID         string
 	IncidentType       string
+	Environment        string
 	LinkedAt           int64
 	DeploymentEventSeq int64
+	ResolvedAt         int64
 }
 
 type ComprehensiveDeliveryMetrics struct {
@@ -147,10 +151,46 @@ type ServiceDependency struct {
 	DependsOnName string
 }
 
+// DeploymentCursor is a position in the deployments listing, which is ordered
+// by event time and then sequence, newest first.
+type DeploymentCursor struct {
+	EventTsMs int64
+	Seq       int64
+}
+
+// IsZero reports whether the cursor starts at the newest deployment.
+func (c DeploymentCursor) IsZero() bool {
+	return c.Seq == 0
+}
+
+// DeploymentFilter narrows the deployments listing. Empty fields match every
+// deployment.
+type DeploymentFilter struct {
+	Env     string
+	Service string
+	Status  domain.DeploymentStatus
+	From    time.Time
+	To      time.Time
+	// Before lists deployments older than this position; the zero value
+	// starts at the newest.
+	Before DeploymentCursor
+	Limit  int64
+}
+
+// DeploymentEnvironmentCount is the number of deployments to one environment
+// in the 7 and 30 days before a point in time.
+type DeploymentEnvironmentCount struct {
+	Environment string
+	Count7d     int64
+	Count30d    int64
+}
+
 // ServiceQueryStore exposes non-analytical service/deployment reads.
 type ServiceQueryStore interface {
 	ListServiceInstances(ctx context.Context, organizationID int64, env string) ([]domain.Service, error)
-	ListDeployments(ctx context.Context, organizationID int64, env, service string) ([]domain.DeploymentRow, error)
+	ListDeployments(ctx context.Context, organizationID int64, filter DeploymentFilter) ([]domain.DeploymentRow, error)
+	ListDeploymentEnvironmentCounts(ctx context.Context, organizationID int64, now time.Time) ([]DeploymentEnvironmentCount, error)
+	ListDeploymentServices(ctx context.Context, organizationID int64) ([]string, error)
 	GetServiceLatest(ctx context.Context, organizationID int64, name string) (ServiceLatest, error)
 	ListServiceEnvironments(ctx context.Context, organizationID int64, service string) ([]domain.ServiceEnvironment, error)
 	ListDeploymentHistory(ctx context.Context, organizationID int64, service string, limit int64) ([]domain.DeploymentRecord, error)
@@ -159,6 +199,8 @@ type ServiceQueryStore interface {
 	UpsertServiceDependency(ctx context.Context, organizationID int64, serviceName, dependsOnServiceName string) error
 	DeleteServiceDependency(ctx context.Context, organizationID int64, serviceName, dependsOnServiceName string) error
 	GetOrganizationRenderVersion(ctx context.Context, organizationID int64) (int64, error)
+	GetLatestEventSeq(ctx context.Context, organizationID int64) (int64, error)
+	ListServiceChangesAfterSeq(ctx context.Context, organizationID int64, afterSeq int64, limit int64) ([]ServiceChange, error)
 }
 
 // ServiceMetadataStore exposes metadata/settings reads for service views.
@@ -184,7 +226,7 @@ type ServiceAnalyticsStore interface {
 	GetThroughputStats(ctx context.Context, organizationID int64, service string) (WeeklyThroughput, error)
 	ListWeeklyThroughput(ctx context.Context, organizationID int64, service string, limit int64) ([]WeeklyThroughput, error)
 	GetArtifactAgeByEnvironment(ctx context.Context, organizationID int64, service string) ([]ArtifactAge, error)
-	GetMTTR(ctx context.Context, organizationID int64, sinceMs int64) (MTTRStats, error)
+	GetMTTR(ctx context.Context, organizationID int64, service string, sinceMs int64) (MTTRStats, error)
 	ListIncidentLinks(ctx context.Context, organizationID int64, service string, limit int64) ([]IncidentLink, error)
 	GetComprehensiveDeliveryMetrics(ctx context.Context, organizationID int64, sinceMs int64) (ComprehensiveDeliveryMetrics, error)
 }
@@ -195,3 +237,186 @@ type ServiceReadStore interface {
 	ServiceMetadataStore
 	ServiceAnalyticsStore
 }
+
+// ProjectionRebuildStore rebuilds analytical projections from the event store.
+type ProjectionRebuildStore interface {
+	RebuildOrganizationProjections(ctx context.Context, organizationID int64, progress func(step string, index, total int)) error
+}
+
+// ServiceIdentityRule maps service names derived from events onto one
+// service. Kind is "alias" for an exact name or "regex" for a pattern whose
+// matches are rewritten to ServiceName, which may reference capture groups.
+type ServiceIdentityRule struct {
+	ID          int64
+	Kind        string
+	Pattern     string
+	ServiceName string
+	CreatedAt   time.Time
+}
+
+// ServiceIdentityAlias is one derived service name the rules currently map.
+type ServiceIdentityAlias struct {
+	Alias       string
+	ServiceName string
+}
+
+// ServiceIdentityStore manages service identity rules. Rule changes remap
+// names and carry metadata over immediately; projections follow on rebuild.
+type ServiceIdentityStore interface {
+	ListServiceIdentityRules(ctx context.Context, organizationID int64) ([]ServiceIdentityRule, error)
+	CreateServiceIdentityRule(ctx context.Context, organizationID int64, rule ServiceIdentityRule) (ServiceIdentityRule, error)
+	DeleteServiceIdentityRule(ctx context.Context, organizationID, id int64) (bool, error)
+	ListServiceIdentityAliases(ctx context.Context, organizationID int64) ([]ServiceIdentityAlias, error)
+	ResolveServiceName(ctx context.Context, organizationID int64, name string) (string, error)
+}
+
+// Environment is one entry of an organization's environment registry. Events
+// reporting the name or one of the aliases are projected under Name.
+type Environment struct {
+	Name      string
+	Tier      string
+	Color     string
+	Protected bool
+	URL       string
+	Source    string
+	Aliases   []string
+	UpdatedAt time.Time
+}
+
+// EnvironmentStore manages the environment registry. Changes reorder the
+// environment priorities immediately; projections follow on rebuild.
+type EnvironmentStore interface {
+	ListEnvironments(ctx context.Context, organizationID int64) ([]Environment, error)
+	SaveEnvironment(ctx context.Context, organizationID int64, environment Environment) (Environment, error)
+	DeleteEnvironment(ctx context.Context, organizationID int64, name string) (bool, error)
+}
+
+// ProjectorStatus is how far one projector has applied the event store.
+type ProjectorStatus struct {
+	Name      string
+	LastSeq   int64
+	HeadSeq   int64
+	UpdatedAt time.Time
+}
+
+// Lag returns the number of event store sequence numbers not yet applied.
+func (s ProjectorStatus) Lag() int64 {
+	if s.HeadSeq <= s.LastSeq {
+		return 0
+	}
+	return s.HeadSeq - s.LastSeq
+}
+
+// ProjectorStore reports projector checkpoints and catches lagging projectors up.
+type ProjectorStore interface {
+	ListProjectorStatus(ctx context.Context) ([]ProjectorStatus, error)
+	CatchUpProjectors(ctx context.Context, batchSize int64) (bool, error)
+}
+
+// EventArchive describes one archive of expired events.
+type EventArchive struct {
+	OrganizationID int64
+	Path           string
+	EventCount     int64
+	ArchivedBefore time.Time
+}
+
+// EventRetentionStore archives events that fall outside each organization's
+// event retention window.
+type EventRetentionStore interface {
+	ArchiveExpiredEvents(ctx context.Context, dir string, now time.Time) ([]EventArchive, error)
+}
+
+// Backup describes one database snapshot written by the backup job.
+type Backup struct {
+	Path      string
+	Checksum  string
+	Size      int64
+	CreatedAt time.Time
+	// Pruned lists older snapshots removed by rotation.
+	Pruned []string
+}
+
+// BackupStore takes consistent snapshots of the database while it serves
+// traffic and keeps the keep newest.
+type BackupStore interface {
+	CreateBackup(ctx context.Context, dir string, keep int, now time.Time) (Backup, error)
+}
+
+// EventFilter narrows an event store listing. Empty fields match every event.
+type EventFilter struct {
+	EventType   string
+	SubjectType string
+	SubjectID   string
+	Source      string
+	ChainID     string
+	From        time.Time
+	To          time.Time
+	// BeforeSeq lists events older than this sequence number; 0 starts at the head.
+	BeforeSeq int64
+	Limit     int64
+}
+
+// StoredEvent is one event as it landed in the event store.
+type StoredEvent struct {
+	Seq         int64
+	EventID     string
+	EventType   string
+	Source      string
+	Timestamp   time.Time
+	SubjectID   string
+	SubjectType string
+	ChainID     string
+	IngestedAt  time.Time
+}
+
+// EventContribution is a service environment an event was projected into.
+type EventContribution struct {
+	Service     string
+	Environment string
+}
+
+// StoredEventDetail is one event with its raw payload and the service
+// environments it contributed to.
+type StoredEventDetail struct {
+	StoredEvent
+	RawJSON       string
+	Contributions []EventContribution
+}
+
+// EventExplorerStore reads raw events from the event store.
+type EventExplorerStore interface {
+	ListEvents(ctx context.Context, organizationID int64, filter EventFilter) ([]StoredEvent, error)
+	GetEvent(ctx context.Context, organizationID, seq int64) (StoredEventDetail, error)
+}
+
+// ChainEvent is one event of a delivery chain with the link its sender
+// attached, if any.
+type ChainEvent struct {
+	StoredEvent
+	URL string
+}
+
+// ChainStore reads every event sharing a CDEvents chain_id.
+type ChainStore interface {
+	ListChainEvents(ctx context.Context, organizationID int64, chainID string, limit int64) ([]ChainEvent, error)
+}
+
+// EnvironmentState is the latest service event for one service in one
+// environment as of some moment.
+type EnvironmentState struct {
+	Service     string
+	Environment string
+	ArtifactID  string
+	Status      string
+	Actor       string
+	EventType   string
+	Seq         int64
+	DeployedAt  time.Time
+}
+
+// EnvironmentHistoryStore rebuilds past environment state from the event store.
+type EnvironmentHistoryStore interface {
+	ListServiceEnvironmentStateAt(ctx context.Context, organizationID int64, at time.Time) ([]EnvironmentState, error)
+	ListEnvironmentPriorities(ctx context.Context, organizationID int64) ([]string, error)
+}
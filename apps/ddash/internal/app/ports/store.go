@@ -35,6 +35,7 @@ type AppStore interface {
 	ListOrganizationEnvironmentPriorities(ctx context.Context, organizationID int64) ([]string, error)
 	ListOrganizationFeatures(ctx context.Context, organizationID int64) ([]OrganizationFeature, error)
 	ListOrganizationPreferences(ctx context.Context, organizationID int64) ([]OrganizationPreference, error)
+	ListOrganizationEventPolicies(ctx context.Context, organizationID int64) ([]EventSubjectPolicy, error)
 	ListDistinctServiceEnvironmentsFromEvents(ctx context.Context, organizationID int64) ([]string, error)
 
 	UpdateOrganizationSettings(ctx context.Context, organizationID int64, params OrganizationSettingsUpdate) error
@@ -81,10 +82,13 @@ type OrganizationSettingsUpdate struct {
 	ShowServiceDependencies     bool
 	ShowServiceDeliveryMetrics  bool
 	DeploymentRetentionDays     int
+	EventRetentionDays          int
 	DefaultDashboardView        string
 	StatusSemanticsMode         string
+	WebhookSignatureScheme      string
 	RequiredFields              []RequiredField
 	EnvironmentOrder            []string
+	EventPolicies               []EventSubjectPolicy
 }
 
 // OrganizationFeature is one organization feature flag.
@@ -99,6 +103,12 @@ type OrganizationPreference struct {
 	Value string
 }
 
+// EventSubjectPolicy is one organization ingestion rule for a CDEvents subject.
+type EventSubjectPolicy struct {
+	Subject string
+	Mode    string
+}
+
 type GitHubInstallationMapping struct {
 	InstallationID     int64
 	OrganizationID     int64
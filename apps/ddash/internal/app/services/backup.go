@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+const defaultBackupKeep = 7
+
+// BackupRunnerOptions tunes the background database backup job.
+type BackupRunnerOptions struct {
+	// Dir receives the snapshots and their checksum files.
+	Dir string
+	// Interval between snapshots.
+	Interval time.Duration
+	// Keep is how many snapshots are kept. Defaults to 7.
+	Keep int
+}
+
+// BackupRunner snapshots the database on a schedule and rotates old
+// snapshots out.
+type BackupRunner struct {
+	store   ports.BackupStore
+	options BackupRunnerOptions
+	now     func() time.Time
+	created metric.Int64Counter
+	failed  metric.Int64Counter
+	size    metric.Int64Gauge
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// StartBackupRunner takes a snapshot once and then on every interval until
+// Close is called.
+func StartBackupRunner(store ports.BackupStore, options BackupRunnerOptions) *BackupRunner {
+	if options.Interval <= 0 {
+		options.Interval = 24 * time.Hour
+	}
+	if options.Keep <= 0 {
+		options.Keep = defaultBackupKeep
+	}
+	meter := otel.Meter("github.com/fr0stylo/ddash/apps/ddash/internal/app/services")
+	created, _ := meter.Int64Counter("ddash.backups.created")
+	failed, _ := meter.Int64Counter("ddash.backups.failed")
+	size, _ := meter.Int64Gauge("ddash.backups.size_bytes")
+	r := &BackupRunner{
+		store:   store,
+		options: options,
+		now:     time.Now,
+		created: created,
+		failed:  failed,
+		size:    size,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Close stops the runner and waits for a snapshot in progress to finish.
+func (r *BackupRunner) Close() {
+	r.closeOnce.Do(func() {
+		close(r.stop)
+		<-r.done
+	})
+}
+
+func (r *BackupRunner) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.backup(context.Background())
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *BackupRunner) backup(ctx context.Context) {
+	backup, err := r.store.CreateBackup(ctx, r.options.Dir, r.options.Keep, r.now().UTC())
+	if backup.Path != "" {
+		r.created.Add(ctx, 1)
+		r.size.Record(ctx, backup.Size)
+		slog.InfoContext(ctx, "database_backup_written", "path", backup.Path, "bytes", backup.Size, "sha256", backup.Checksum, "pruned", len(backup.Pruned))
+	}
+	if err != nil {
+		r.failed.Add(ctx, 1)
+		slog.ErrorContext(ctx, "database_backup_failed", "error", err)
+	}
+}
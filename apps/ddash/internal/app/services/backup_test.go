@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+type recordingBackupStore struct {
+	keeps chan int
+}
+
+func (s *recordingBackupStore) CreateBackup(_ context.Context, dir string, keep int, now time.Time) (ports.Backup, error) {
+	s.keeps <- keep
+	return ports.Backup{Path: dir + "/ddash.sqlite", CreatedAt: now}, nil
+}
+
+func TestBackupRunnerSnapshotsOnStartWithDefaultRotation(t *testing.T) {
+	store := &recordingBackupStore{keeps: make(chan int, 1)}
+	runner := StartBackupRunner(store, BackupRunnerOptions{Dir: "backups", Interval: time.Hour})
+	defer runner.Close()
+
+	select {
+	case keep := <-store.keeps:
+		if keep != defaultBackupKeep {
+			t.Fatalf("unexpected keep: got=%d want=%d", keep, defaultBackupKeep)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("backup did not run on start")
+	}
+}
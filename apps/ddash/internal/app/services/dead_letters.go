@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+const (
+	deadLetterMaxBodyBytes = 256 << 10
+	deadLetterMaxAge       = 14 * 24 * time.Hour
+	deadLetterMaxCount     = 500
+	deadLetterListLimit    = 100
+
+	// DeadLetterReplayAccepted marks a replay that was appended to the event store.
+	DeadLetterReplayAccepted = "accepted"
+)
+
+// ErrDeadLetterTruncated indicates the stored body was cut and cannot be replayed.
+var ErrDeadLetterTruncated = errors.New("dead letter body truncated")
+
+// deadLetterDroppedHeaders are never persisted with a rejected delivery.
+var deadLetterDroppedHeaders = []string{"Authorization", "Cookie"}
+
+func isDeadLetterError(err error) bool {
+	switch ClassifyIngestError(err) {
+	case IngestErrorInvalidPayload, IngestErrorInvalidSchema, IngestErrorUnsupportedType:
+		return true
+	default:
+		return false
+	}
+}
+
+// recordDeadLetter keeps a rejected delivery; failures are logged and never
+// change the ingestion result returned to the sender.
+func (s *EventIngestService) recordDeadLetter(ctx context.Context, organizationID int64, headers http.Header, body []byte, cause error) {
+	stored := headers.Clone()
+	if stored == nil {
+		stored = http.Header{}
+	}
+	for _, name := range deadLetterDroppedHeaders {
+		stored.Del(name)
+	}
+	letter := ports.DeadLetter{
+		OrganizationID: organizationID,
+		Reason:         string(ClassifyIngestError(cause)),
+		Detail:         cause.Error(),
+		Headers:        stored,
+		Body:           body,
+		BodySize:       int64(len(body)),
+		ReceivedAt:     time.Now().UTC(),
+	}
+	if len(body) > deadLetterMaxBodyBytes {
+		letter.Body = body[:deadLetterMaxBodyBytes]
+		letter.Truncated = true
+	}
+
+	store, err := s.storeFactory.Open()
+	if err != nil {
+		slog.ErrorContext(ctx, "ingest_dead_letter_open_failed", "error", err, "organization_id", organizationID)
+		return
+	}
+	defer func() {
+		_ = store.Close()
+	}()
+	if err := store.AppendDeadLetter(ctx, letter, ports.DeadLetterRetention{MaxAge: deadLetterMaxAge, MaxCount: deadLetterMaxCount}); err != nil {
+		slog.ErrorContext(ctx, "ingest_dead_letter_append_failed", "error", err, "organization_id", organizationID, "reason", letter.Reason)
+	}
+}
+
+// DeadLetterService lists, replays and discards rejected webhook deliveries.
+type DeadLetterService struct {
+	store  ports.DeadLetterStore
+	ingest *EventIngestService
+}
+
+// NewDeadLetterService constructs a dead-letter service replaying through ingest.
+func NewDeadLetterService(store ports.DeadLetterStore, ingest *EventIngestService) *DeadLetterService {
+	return &DeadLetterService{store: store, ingest: ingest}
+}
+
+// List returns the newest rejected deliveries for one organization.
+func (s *DeadLetterService) List(ctx context.Context, organizationID int64) ([]ports.DeadLetter, error) {
+	return s.store.ListDeadLetters(ctx, organizationID, deadLetterListLimit)
+}
+
+// Replay re-submits one rejected delivery through the ingestion pipeline and
+// records the outcome. Ingestion rejections are returned as the replay result,
+// not as an error, so the delivery stays inspectable.
+func (s *DeadLetterService) Replay(ctx context.Context, organizationID, id int64) (string, error) {
+	letter, err := s.store.GetDeadLetter(ctx, organizationID, id)
+	if err != nil {
+		return "", err
+	}
+	if letter.Truncated {
+		return "", ErrDeadLetterTruncated
+	}
+
+	result := DeadLetterReplayAccepted
+	if ingestErr := s.ingest.ingestEvent(ctx, organizationID, http.Header(letter.Headers), letter.Body); ingestErr != nil {
+		if !isDeadLetterError(ingestErr) {
+			return "", ingestErr
+		}
+		result = string(ClassifyIngestError(ingestErr))
+	}
+	if err := s.store.MarkDeadLetterReplayed(ctx, organizationID, id, result, time.Now().UTC()); err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// Delete discards one rejected delivery.
+func (s *DeadLetterService) Delete(ctx context.Context, organizationID, id int64) error {
+	return s.store.DeleteDeadLetter(ctx, organizationID, id)
+}
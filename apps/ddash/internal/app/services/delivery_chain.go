@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+// maxChainEvents bounds how much of a runaway chain is loaded at once.
+const maxChainEvents = 500
+
+// DeliveryChain is every event sharing a chain_id, grouped into the stages
+// the change went through.
+type DeliveryChain struct {
+	ID       string
+	Stages   []ChainStage
+	Started  time.Time
+	Finished time.Time
+	// Truncated is set when the chain has more events than were loaded.
+	Truncated bool
+}
+
+// Duration is the time from the first to the last event of the chain.
+func (c DeliveryChain) Duration() time.Duration {
+	return c.Finished.Sub(c.Started)
+}
+
+// ChainStage is a run of consecutive events about the same subject, such as
+// a pipeline run from start to finish.
+type ChainStage struct {
+	SubjectType string
+	SubjectID   string
+	Events      []ports.ChainEvent
+	Started     time.Time
+	Finished    time.Time
+	// Wait is the idle time between the previous stage finishing and this
+	// one starting.
+	Wait time.Duration
+}
+
+// Duration is the time from the stage's first to its last event.
+func (s ChainStage) Duration() time.Duration {
+	return s.Finished.Sub(s.Started)
+}
+
+// ChainService assembles delivery chains from the event store.
+type ChainService struct {
+	store ports.ChainStore
+}
+
+// NewChainService constructs a delivery chain service.
+func NewChainService(store ports.ChainStore) *ChainService {
+	return &ChainService{store: store}
+}
+
+// Get returns the delivery chain with the given id, or sql.ErrNoRows when no
+// event carries it.
+func (s *ChainService) Get(ctx context.Context, organizationID int64, chainID string) (DeliveryChain, error) {
+	chainID = strings.TrimSpace(chainID)
+	if chainID == "" {
+		return DeliveryChain{}, sql.ErrNoRows
+	}
+	events, err := s.store.ListChainEvents(ctx, organizationID, chainID, maxChainEvents+1)
+	if err != nil {
+		return DeliveryChain{}, err
+	}
+	if len(events) == 0 {
+		return DeliveryChain{}, sql.ErrNoRows
+	}
+	chain := buildDeliveryChain(chainID, events[:min(len(events), maxChainEvents)])
+	chain.Truncated = len(events) > maxChainEvents
+	return chain, nil
+}
+
+// buildDeliveryChain groups time-ordered events into stages, starting a new
+// stage whenever the subject changes.
+func buildDeliveryChain(chainID string, events []ports.ChainEvent) DeliveryChain {
+	chain := DeliveryChain{ID: chainID}
+	for _, event := range events {
+		last := len(chain.Stages) - 1
+		if last >= 0 && chain.Stages[last].SubjectType == event.SubjectType && chain.Stages[last].SubjectID == event.SubjectID {
+			chain.Stages[last].Events = append(chain.Stages[last].Events, event)
+			chain.Stages[last].Finished = event.Timestamp
+			continue
+		}
+		stage := ChainStage{
+			SubjectType: event.SubjectType,
+			SubjectID:   event.SubjectID,
+			Events:      []ports.ChainEvent{event},
+			Started:     event.Timestamp,
+			Finished:    event.Timestamp,
+		}
+		if last >= 0 {
+			stage.Wait = max(stage.Started.Sub(chain.Stages[last].Finished), 0)
+		}
+		chain.Stages = append(chain.Stages, stage)
+	}
+	if len(events) > 0 {
+		chain.Started = events[0].Timestamp
+		chain.Finished = events[len(events)-1].Timestamp
+	}
+	return chain
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+type fakeChainStore struct {
+	events []ports.ChainEvent
+	limit  int64
+}
+
+func (s *fakeChainStore) ListChainEvents(_ context.Context, _ int64, _ string, limit int64) ([]ports.ChainEvent, error) {
+	s.limit = limit
+	return s.events[:min(int64(len(s.events)), limit)], nil
+}
+
+func TestChainServiceGetGroupsConsecutiveSubjects(t *testing.T) {
+	start := time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+	event := func(offset time.Duration, subjectType, subjectID string) ports.ChainEvent {
+		return ports.ChainEvent{StoredEvent: ports.StoredEvent{Timestamp: start.Add(offset), SubjectType: subjectType, SubjectID: subjectID}}
+	}
+	store := &fakeChainStore{events: []ports.ChainEvent{
+		event(0, "change", "change/42"),
+		event(time.Minute, "change", "change/42"),
+		event(3*time.Minute, "pipelineRun", "pipeline/7"),
+		event(9*time.Minute, "pipelineRun", "pipeline/7"),
+		event(10*time.Minute, "service", "service/payments"),
+		event(12*time.Minute, "pipelineRun", "pipeline/8"),
+		event(15*time.Minute, "service", "service/payments"),
+	}}
+
+	chain, err := NewChainService(store).Get(context.Background(), 1, " chain-1 ")
+	if err != nil {
+		t.Fatalf("get chain: %v", err)
+	}
+	if chain.ID != "chain-1" || chain.Duration() != 15*time.Minute || chain.Truncated || store.limit != maxChainEvents+1 {
+		t.Fatalf("unexpected chain: %+v limit=%d", chain, store.limit)
+	}
+	if len(chain.Stages) != 5 {
+		t.Fatalf("expected five stages, got %d", len(chain.Stages))
+	}
+	pipeline := chain.Stages[1]
+	if pipeline.SubjectID != "pipeline/7" || len(pipeline.Events) != 2 || pipeline.Duration() != 6*time.Minute || pipeline.Wait != 2*time.Minute {
+		t.Fatalf("unexpected pipeline stage: %+v", pipeline)
+	}
+	if last := chain.Stages[4]; last.SubjectID != "service/payments" || last.Wait != 3*time.Minute {
+		t.Fatalf("expected the second deploy to be its own stage: %+v", last)
+	}
+
+	if _, err := NewChainService(&fakeChainStore{}).Get(context.Background(), 1, "missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected no rows for an unknown chain, got %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+const (
+	// EnvironmentChangeAdded marks a service reaching an environment for the
+	// first time.
+	EnvironmentChangeAdded = "added"
+	// EnvironmentChangeArtifact marks a different artifact running.
+	EnvironmentChangeArtifact = "artifact"
+	// EnvironmentChangeStatus marks the same artifact with a new status, such
+	// as after a removal.
+	EnvironmentChangeStatus = "status"
+	// EnvironmentChangeRedeployed marks the same artifact deployed again.
+	EnvironmentChangeRedeployed = "redeployed"
+)
+
+// EnvironmentMatrix is what every service ran in every environment at one
+// moment.
+type EnvironmentMatrix struct {
+	At           time.Time
+	Environments []string
+	Rows         []EnvironmentMatrixRow
+}
+
+// EnvironmentMatrixRow holds one state per matrix environment; states with a
+// zero Seq mean the service had not reached that environment yet.
+type EnvironmentMatrixRow struct {
+	Service string
+	States  []ports.EnvironmentState
+}
+
+// EnvironmentChange is one service and environment whose state differs
+// between two moments.
+type EnvironmentChange struct {
+	Service     string
+	Environment string
+	Kind        string
+	// Before is zero for EnvironmentChangeAdded.
+	Before ports.EnvironmentState
+	After  ports.EnvironmentState
+}
+
+// EnvironmentDiff lists the changes between two moments, latest first.
+type EnvironmentDiff struct {
+	From    time.Time
+	To      time.Time
+	Changes []EnvironmentChange
+}
+
+// EnvironmentHistoryService answers "what was running at T" from the event
+// store rather than from the projections, which only hold the present.
+type EnvironmentHistoryService struct {
+	store ports.EnvironmentHistoryStore
+}
+
+// NewEnvironmentHistoryService constructs an environment history service.
+func NewEnvironmentHistoryService(store ports.EnvironmentHistoryStore) *EnvironmentHistoryService {
+	return &EnvironmentHistoryService{store: store}
+}
+
+// StateAt returns the service by environment matrix as of at. Environments
+// follow the organization's priority order, then name.
+func (s *EnvironmentHistoryService) StateAt(ctx context.Context, organizationID int64, at time.Time) (EnvironmentMatrix, error) {
+	states, err := s.store.ListServiceEnvironmentStateAt(ctx, organizationID, at)
+	if err != nil {
+		return EnvironmentMatrix{}, err
+	}
+	priorities, err := s.store.ListEnvironmentPriorities(ctx, organizationID)
+	if err != nil {
+		return EnvironmentMatrix{}, err
+	}
+	return buildEnvironmentMatrix(at, states, priorities), nil
+}
+
+// Diff compares the state at from with the state at to. The moments are
+// swapped when from is after to.
+func (s *EnvironmentHistoryService) Diff(ctx context.Context, organizationID int64, from, to time.Time) (EnvironmentDiff, error) {
+	if from.After(to) {
+		from, to = to, from
+	}
+	before, err := s.store.ListServiceEnvironmentStateAt(ctx, organizationID, from)
+	if err != nil {
+		return EnvironmentDiff{}, err
+	}
+	after, err := s.store.ListServiceEnvironmentStateAt(ctx, organizationID, to)
+	if err != nil {
+		return EnvironmentDiff{}, err
+	}
+	return EnvironmentDiff{From: from, To: to, Changes: diffEnvironmentStates(before, after)}, nil
+}
+
+func buildEnvironmentMatrix(at time.Time, states []ports.EnvironmentState, priorities []string) EnvironmentMatrix {
+	rank := map[string]int{}
+	for i, name := range priorities {
+		if _, ok := rank[name]; !ok {
+			rank[name] = i
+		}
+	}
+	seen := map[string]bool{}
+	matrix := EnvironmentMatrix{At: at}
+	for _, state := range states {
+		if !seen[state.Environment] {
+			seen[state.Environment] = true
+			matrix.Environments = append(matrix.Environments, state.Environment)
+		}
+	}
+	slices.SortFunc(matrix.Environments, func(a, b string) int {
+		ra, aok := rank[a]
+		rb, bok := rank[b]
+		switch {
+		case aok && bok:
+			return ra - rb
+		case aok:
+			return -1
+		case bok:
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+	column := make(map[string]int, len(matrix.Environments))
+	for i, name := range matrix.Environments {
+		column[name] = i
+	}
+
+	rows := map[string]int{}
+	for _, state := range states {
+		index, ok := rows[state.Service]
+		if !ok {
+			index = len(matrix.Rows)
+			rows[state.Service] = index
+			matrix.Rows = append(matrix.Rows, EnvironmentMatrixRow{
+				Service: state.Service,
+				States:  make([]ports.EnvironmentState, len(matrix.Environments)),
+			})
+		}
+		matrix.Rows[index].States[column[state.Environment]] = state
+	}
+	slices.SortFunc(matrix.Rows, func(a, b EnvironmentMatrixRow) int {
+		return strings.Compare(a.Service, b.Service)
+	})
+	return matrix
+}
+
+func diffEnvironmentStates(before, after []ports.EnvironmentState) []EnvironmentChange {
+	type key struct{ service, environment string }
+	previous := make(map[key]ports.EnvironmentState, len(before))
+	for _, state := range before {
+		previous[key{state.Service, state.Environment}] = state
+	}
+	changes := []EnvironmentChange{}
+	for _, state := range after {
+		old, ok := previous[key{state.Service, state.Environment}]
+		change := EnvironmentChange{Service: state.Service, Environment: state.Environment, Before: old, After: state}
+		switch {
+		case !ok:
+			change.Kind = EnvironmentChangeAdded
+		case old.Seq == state.Seq:
+			continue
+		case old.ArtifactID != state.ArtifactID:
+			change.Kind = EnvironmentChangeArtifact
+		case old.Status != state.Status:
+			change.Kind = EnvironmentChangeStatus
+		default:
+			change.Kind = EnvironmentChangeRedeployed
+		}
+		changes = append(changes, change)
+	}
+	slices.SortStableFunc(changes, func(a, b EnvironmentChange) int {
+		return b.After.DeployedAt.Compare(a.After.DeployedAt)
+	})
+	return changes
+}
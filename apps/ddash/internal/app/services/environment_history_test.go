@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+type memoryEnvironmentHistoryStore struct {
+	events     []ports.EnvironmentState
+	priorities []string
+}
+
+func (s *memoryEnvironmentHistoryStore) ListServiceEnvironmentStateAt(_ context.Context, _ int64, at time.Time) ([]ports.EnvironmentState, error) {
+	latest := map[[2]string]ports.EnvironmentState{}
+	for _, event := range s.events {
+		if event.DeployedAt.After(at) {
+			continue
+		}
+		latest[[2]string{event.Service, event.Environment}] = event
+	}
+	out := make([]ports.EnvironmentState, 0, len(latest))
+	for _, state := range latest {
+		out = append(out, state)
+	}
+	return out, nil
+}
+
+func (s *memoryEnvironmentHistoryStore) ListEnvironmentPriorities(context.Context, int64) ([]string, error) {
+	return s.priorities, nil
+}
+
+func TestEnvironmentHistoryServiceStateAtAndDiff(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := &memoryEnvironmentHistoryStore{
+		priorities: []string{"production", "staging"},
+		events: []ports.EnvironmentState{
+			{Seq: 1, Service: "payments", Environment: "staging", ArtifactID: "v1", Status: "synced", DeployedAt: base},
+			{Seq: 2, Service: "payments", Environment: "production", ArtifactID: "v1", Status: "synced", DeployedAt: base.Add(time.Hour)},
+			{Seq: 3, Service: "billing", Environment: "dev", ArtifactID: "b1", Status: "synced", DeployedAt: base.Add(time.Hour)},
+			{Seq: 4, Service: "payments", Environment: "staging", ArtifactID: "v2", Status: "synced", DeployedAt: base.Add(2 * time.Hour)},
+			{Seq: 5, Service: "payments", Environment: "production", ArtifactID: "v1", Status: "synced", DeployedAt: base.Add(3 * time.Hour)},
+			{Seq: 6, Service: "billing", Environment: "dev", ArtifactID: "b1", Status: "out-of-sync", DeployedAt: base.Add(3 * time.Hour)},
+			{Seq: 7, Service: "search", Environment: "staging", ArtifactID: "s1", Status: "synced", DeployedAt: base.Add(4 * time.Hour)},
+		},
+	}
+	service := NewEnvironmentHistoryService(store)
+	ctx := context.Background()
+
+	matrix, err := service.StateAt(ctx, 1, base.Add(90*time.Minute))
+	if err != nil {
+		t.Fatalf("state at: %v", err)
+	}
+	if !slices.Equal(matrix.Environments, []string{"production", "staging", "dev"}) {
+		t.Fatalf("unexpected environment order: %v", matrix.Environments)
+	}
+	if len(matrix.Rows) != 2 || matrix.Rows[0].Service != "billing" || matrix.Rows[1].Service != "payments" {
+		t.Fatalf("unexpected rows: %+v", matrix.Rows)
+	}
+	if matrix.Rows[0].States[0].Seq != 0 || matrix.Rows[1].States[1].ArtifactID != "v1" {
+		t.Fatalf("unexpected cells: %+v", matrix.Rows)
+	}
+
+	diff, err := service.Diff(ctx, 1, base.Add(5*time.Hour), base.Add(90*time.Minute))
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	if !diff.From.Before(diff.To) {
+		t.Fatalf("expected from and to to be swapped: %+v", diff)
+	}
+	kinds := map[string]string{}
+	for _, change := range diff.Changes {
+		kinds[change.Service+"/"+change.Environment] = change.Kind
+	}
+	want := map[string]string{
+		"search/staging":      EnvironmentChangeAdded,
+		"payments/staging":    EnvironmentChangeArtifact,
+		"payments/production": EnvironmentChangeRedeployed,
+		"billing/dev":         EnvironmentChangeStatus,
+	}
+	if len(kinds) != len(want) {
+		t.Fatalf("unexpected changes: %+v", diff.Changes)
+	}
+	for key, kind := range want {
+		if kinds[key] != kind {
+			t.Fatalf("expected %s to be %s, got %+v", key, kind, diff.Changes)
+		}
+	}
+	if diff.Changes[0].Service != "search" {
+		t.Fatalf("expected the latest change first, got %+v", diff.Changes[0])
+	}
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+const (
+	// EnvironmentTierProduction marks environments that serve production traffic.
+	EnvironmentTierProduction = "production"
+	// EnvironmentTierNonProduction marks every other environment.
+	EnvironmentTierNonProduction = "non-production"
+)
+
+var (
+	// ErrEnvironmentInvalid indicates a registry entry that cannot be stored.
+	ErrEnvironmentInvalid = errors.New("invalid environment")
+	// ErrEnvironmentProtected indicates a protected environment cannot be deleted.
+	ErrEnvironmentProtected = errors.New("environment is protected")
+	// ErrEnvironmentNotFound indicates the environment is not registered.
+	ErrEnvironmentNotFound = errors.New("environment not found")
+)
+
+var environmentColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// EnvironmentRegistryService manages the canonical environments of an
+// organization: their aliases, tier, display color and protection.
+type EnvironmentRegistryService struct {
+	store ports.EnvironmentStore
+}
+
+// NewEnvironmentRegistryService constructs an environment registry service.
+func NewEnvironmentRegistryService(store ports.EnvironmentStore) *EnvironmentRegistryService {
+	return &EnvironmentRegistryService{store: store}
+}
+
+// List returns the registered environments in priority order.
+func (s *EnvironmentRegistryService) List(ctx context.Context, organizationID int64) ([]ports.Environment, error) {
+	return s.store.ListEnvironments(ctx, organizationID)
+}
+
+// Save validates and stores an environment. Callers rebuild projections
+// afterwards so history is regrouped under the registered names.
+func (s *EnvironmentRegistryService) Save(ctx context.Context, organizationID int64, environment ports.Environment) (ports.Environment, error) {
+	environment.Name = strings.TrimSpace(environment.Name)
+	environment.Tier = strings.TrimSpace(environment.Tier)
+	environment.Color = strings.TrimSpace(environment.Color)
+	environment.URL = strings.TrimSpace(environment.URL)
+	if environment.Name == "" {
+		return ports.Environment{}, fmt.Errorf("%w: name is required", ErrEnvironmentInvalid)
+	}
+	if environment.Tier != EnvironmentTierProduction && environment.Tier != EnvironmentTierNonProduction {
+		return ports.Environment{}, fmt.Errorf("%w: unknown tier %q", ErrEnvironmentInvalid, environment.Tier)
+	}
+	if environment.Color != "" && !environmentColorPattern.MatchString(environment.Color) {
+		return ports.Environment{}, fmt.Errorf("%w: color must look like #1f2937", ErrEnvironmentInvalid)
+	}
+
+	existing, err := s.store.ListEnvironments(ctx, organizationID)
+	if err != nil {
+		return ports.Environment{}, err
+	}
+	owners := map[string]string{}
+	for _, item := range existing {
+		if strings.EqualFold(item.Name, environment.Name) {
+			continue
+		}
+		owners[strings.ToLower(item.Name)] = item.Name
+		for _, alias := range item.Aliases {
+			owners[strings.ToLower(alias)] = item.Name
+		}
+	}
+	aliases := make([]string, 0, len(environment.Aliases))
+	seen := map[string]bool{strings.ToLower(environment.Name): true}
+	for _, alias := range append([]string{environment.Name}, environment.Aliases...) {
+		alias = strings.TrimSpace(alias)
+		if owner, ok := owners[strings.ToLower(alias)]; ok {
+			return ports.Environment{}, fmt.Errorf("%w: %q already belongs to %s", ErrEnvironmentInvalid, alias, owner)
+		}
+		if alias == "" || seen[strings.ToLower(alias)] {
+			continue
+		}
+		seen[strings.ToLower(alias)] = true
+		aliases = append(aliases, alias)
+	}
+	environment.Aliases = aliases
+	return s.store.SaveEnvironment(ctx, organizationID, environment)
+}
+
+// Delete removes an environment and its aliases. Protected environments have
+// to be unprotected first.
+func (s *EnvironmentRegistryService) Delete(ctx context.Context, organizationID int64, name string) error {
+	name = strings.TrimSpace(name)
+	existing, err := s.store.ListEnvironments(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	for _, item := range existing {
+		if !strings.EqualFold(item.Name, name) {
+			continue
+		}
+		if item.Protected {
+			return ErrEnvironmentProtected
+		}
+		if _, err := s.store.DeleteEnvironment(ctx, organizationID, item.Name); err != nil {
+			return err
+		}
+		return nil
+	}
+	return ErrEnvironmentNotFound
+}
+
+// ParseEnvironmentAliases splits a comma or newline separated alias list.
+func ParseEnvironmentAliases(value string) []string {
+	fields := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '\n' || r == '\r' })
+	out := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			out = append(out, field)
+		}
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+type memoryEnvironmentStore struct {
+	environments []ports.Environment
+}
+
+func (s *memoryEnvironmentStore) ListEnvironments(context.Context, int64) ([]ports.Environment, error) {
+	return s.environments, nil
+}
+
+func (s *memoryEnvironmentStore) SaveEnvironment(_ context.Context, _ int64, environment ports.Environment) (ports.Environment, error) {
+	for i, item := range s.environments {
+		if strings.EqualFold(item.Name, environment.Name) {
+			s.environments[i] = environment
+			return environment, nil
+		}
+	}
+	s.environments = append(s.environments, environment)
+	return environment, nil
+}
+
+func (s *memoryEnvironmentStore) DeleteEnvironment(_ context.Context, _ int64, name string) (bool, error) {
+	for i, item := range s.environments {
+		if item.Name == name {
+			s.environments = append(s.environments[:i], s.environments[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestEnvironmentRegistryServiceValidatesEnvironments(t *testing.T) {
+	store := &memoryEnvironmentStore{}
+	service := NewEnvironmentRegistryService(store)
+	ctx := context.Background()
+
+	invalid := []ports.Environment{
+		{Name: " ", Tier: EnvironmentTierProduction},
+		{Name: "production", Tier: "critical"},
+		{Name: "production", Tier: EnvironmentTierProduction, Color: "green"},
+	}
+	for _, item := range invalid {
+		if _, err := service.Save(ctx, 1, item); !errors.Is(err, ErrEnvironmentInvalid) {
+			t.Fatalf("expected %+v to be rejected, got %v", item, err)
+		}
+	}
+
+	saved, err := service.Save(ctx, 1, ports.Environment{Name: " production ", Tier: EnvironmentTierProduction, Color: "#16a34a", Protected: true, Aliases: ParseEnvironmentAliases("prod, PROD,\nprd")})
+	if err != nil || saved.Name != "production" || !slices.Equal(saved.Aliases, []string{"prod", "prd"}) {
+		t.Fatalf("unexpected saved environment: %+v err=%v", saved, err)
+	}
+	if _, err := service.Save(ctx, 1, ports.Environment{Name: "staging", Tier: EnvironmentTierNonProduction, Aliases: []string{"Prod"}}); !errors.Is(err, ErrEnvironmentInvalid) {
+		t.Fatalf("expected an alias of another environment to be rejected, got %v", err)
+	}
+	if _, err := service.Save(ctx, 1, ports.Environment{Name: "PRD", Tier: EnvironmentTierNonProduction}); !errors.Is(err, ErrEnvironmentInvalid) {
+		t.Fatalf("expected a name taken as alias to be rejected, got %v", err)
+	}
+
+	if err := service.Delete(ctx, 1, "production"); !errors.Is(err, ErrEnvironmentProtected) {
+		t.Fatalf("expected protected environment to be kept, got %v", err)
+	}
+	saved.Protected = false
+	if _, err := service.Save(ctx, 1, saved); err != nil {
+		t.Fatalf("unprotect environment: %v", err)
+	}
+	if err := service.Delete(ctx, 1, "Production"); err != nil {
+		t.Fatalf("delete environment: %v", err)
+	}
+	if err := service.Delete(ctx, 1, "production"); !errors.Is(err, ErrEnvironmentNotFound) {
+		t.Fatalf("expected missing environment, got %v", err)
+	}
+}
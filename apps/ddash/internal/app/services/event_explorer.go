@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+const (
+	defaultEventExplorerLimit = 50
+	maxEventExplorerLimit     = 200
+)
+
+// EventPage is one page of the event explorer. NextCursor is the BeforeSeq of
+// the following page, or 0 when there is none.
+type EventPage struct {
+	Events     []ports.StoredEvent
+	NextCursor int64
+}
+
+// EventExplorerService lists and inspects raw events for debugging senders.
+type EventExplorerService struct {
+	store ports.EventExplorerStore
+}
+
+// NewEventExplorerService constructs an event explorer service.
+func NewEventExplorerService(store ports.EventExplorerStore) *EventExplorerService {
+	return &EventExplorerService{store: store}
+}
+
+// List returns one page of events matching the filter, newest first.
+func (s *EventExplorerService) List(ctx context.Context, organizationID int64, filter ports.EventFilter) (EventPage, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultEventExplorerLimit
+	}
+	limit = min(limit, maxEventExplorerLimit)
+	// One extra row tells whether another page follows.
+	filter.Limit = limit + 1
+	events, err := s.store.ListEvents(ctx, organizationID, filter)
+	if err != nil {
+		return EventPage{}, err
+	}
+	page := EventPage{Events: events}
+	if int64(len(events)) > limit {
+		page.Events = events[:limit]
+		page.NextCursor = page.Events[limit-1].Seq
+	}
+	return page, nil
+}
+
+// Get returns one event with its raw payload.
+func (s *EventExplorerService) Get(ctx context.Context, organizationID, seq int64) (ports.StoredEventDetail, error) {
+	return s.store.GetEvent(ctx, organizationID, seq)
+}
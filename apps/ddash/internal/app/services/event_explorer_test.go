@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+type fakeEventExplorerStore struct {
+	seqs   []int64
+	filter ports.EventFilter
+}
+
+func (s *fakeEventExplorerStore) ListEvents(_ context.Context, _ int64, filter ports.EventFilter) ([]ports.StoredEvent, error) {
+	s.filter = filter
+	out := []ports.StoredEvent{}
+	for _, seq := range s.seqs {
+		if filter.BeforeSeq > 0 && seq >= filter.BeforeSeq {
+			continue
+		}
+		if int64(len(out)) == filter.Limit {
+			break
+		}
+		out = append(out, ports.StoredEvent{Seq: seq})
+	}
+	return out, nil
+}
+
+func (s *fakeEventExplorerStore) GetEvent(_ context.Context, _ int64, seq int64) (ports.StoredEventDetail, error) {
+	return ports.StoredEventDetail{StoredEvent: ports.StoredEvent{Seq: seq}}, nil
+}
+
+func TestEventExplorerServiceListPagesBySeq(t *testing.T) {
+	store := &fakeEventExplorerStore{seqs: []int64{9, 7, 5, 3, 1}}
+	service := NewEventExplorerService(store)
+
+	first, err := service.List(context.Background(), 1, ports.EventFilter{EventType: "dev.cdevents.service.deployed.0.3.0", Limit: 2})
+	if err != nil {
+		t.Fatalf("list events: %v", err)
+	}
+	if len(first.Events) != 2 || first.NextCursor != 7 {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+	if store.filter.Limit != 3 || store.filter.EventType != "dev.cdevents.service.deployed.0.3.0" {
+		t.Fatalf("unexpected store filter: %+v", store.filter)
+	}
+
+	last, err := service.List(context.Background(), 1, ports.EventFilter{BeforeSeq: 3, Limit: 2})
+	if err != nil {
+		t.Fatalf("list events: %v", err)
+	}
+	if len(last.Events) != 1 || last.Events[0].Seq != 1 || last.NextCursor != 0 {
+		t.Fatalf("unexpected last page: %+v", last)
+	}
+
+	if _, err := service.List(context.Background(), 1, ports.EventFilter{Limit: 10_000}); err != nil {
+		t.Fatalf("list events: %v", err)
+	}
+	if store.filter.Limit != maxEventExplorerLimit+1 {
+		t.Fatalf("expected limit to be capped: %d", store.filter.Limit)
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+const defaultEventRetentionInterval = time.Hour
+
+// EventRetentionRunnerOptions tunes the background event retention job.
+type EventRetentionRunnerOptions struct {
+	// Dir receives the compressed archive files.
+	Dir string
+	// Interval between retention runs.
+	Interval time.Duration
+}
+
+// EventRetentionRunner archives and deletes events past each organization's
+// event retention window. Projections keep the archived history.
+type EventRetentionRunner struct {
+	store    ports.EventRetentionStore
+	options  EventRetentionRunnerOptions
+	now      func() time.Time
+	archived metric.Int64Counter
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// StartEventRetentionRunner runs retention once and then on every interval
+// until Close is called.
+func StartEventRetentionRunner(store ports.EventRetentionStore, options EventRetentionRunnerOptions) *EventRetentionRunner {
+	if options.Interval <= 0 {
+		options.Interval = defaultEventRetentionInterval
+	}
+	meter := otel.Meter("github.com/fr0stylo/ddash/apps/ddash/internal/app/services")
+	archived, _ := meter.Int64Counter("ddash.events.archived")
+	r := &EventRetentionRunner{
+		store:    store,
+		options:  options,
+		now:      time.Now,
+		archived: archived,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Close stops the runner and waits for a run in progress to finish.
+func (r *EventRetentionRunner) Close() {
+	r.closeOnce.Do(func() {
+		close(r.stop)
+		<-r.done
+	})
+}
+
+func (r *EventRetentionRunner) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.archive(context.Background())
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *EventRetentionRunner) archive(ctx context.Context) {
+	archives, err := r.store.ArchiveExpiredEvents(ctx, r.options.Dir, r.now().UTC())
+	for _, archive := range archives {
+		r.archived.Add(ctx, archive.EventCount, metric.WithAttributes(attribute.Int64("organization_id", archive.OrganizationID)))
+		slog.InfoContext(ctx, "event_archive_written", "organization_id", archive.OrganizationID, "path", archive.Path, "events", archive.EventCount, "archived_before", archive.ArchivedBefore)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "event_retention_failed", "error", err)
+	}
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+type recordingRetentionStore struct {
+	dirs chan string
+}
+
+func (s *recordingRetentionStore) ArchiveExpiredEvents(_ context.Context, dir string, _ time.Time) ([]ports.EventArchive, error) {
+	s.dirs <- dir
+	return []ports.EventArchive{{OrganizationID: 1, Path: dir + "/org-1/events-1-2.ndjson.gz", EventCount: 2}}, nil
+}
+
+func TestEventRetentionRunnerArchivesOnStart(t *testing.T) {
+	store := &recordingRetentionStore{dirs: make(chan string, 1)}
+	runner := StartEventRetentionRunner(store, EventRetentionRunnerOptions{Dir: "archive", Interval: time.Hour})
+	defer runner.Close()
+
+	select {
+	case dir := <-store.dirs:
+		if dir != "archive" {
+			t.Fatalf("unexpected archive dir: %s", dir)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("retention did not run on start")
+	}
+}
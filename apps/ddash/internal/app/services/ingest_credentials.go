@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+const maxIngestCredentialLabelLength = 64
+
+var (
+	// ErrIngestCredentialLabelRequired indicates a credential was created without a label.
+	ErrIngestCredentialLabelRequired = errors.New("ingest credential label required")
+	// ErrIngestCredentialNotFound indicates the credential does not exist or is already revoked.
+	ErrIngestCredentialNotFound = errors.New("ingest credential not found")
+)
+
+// IngestCredentialService manages additional ingest credentials so tokens and
+// webhook secrets can be rotated with overlapping validity.
+type IngestCredentialService struct {
+	store          ports.IngestCredentialStore
+	now            func() time.Time
+	ingestSettings *IngestSettingsCache
+}
+
+// NewIngestCredentialService constructs an ingest credential service.
+func NewIngestCredentialService(store ports.IngestCredentialStore) *IngestCredentialService {
+	return &IngestCredentialService{store: store, now: time.Now}
+}
+
+// NewIngestCredentialServiceWithCache constructs an ingest credential service
+// that invalidates cached ingestion settings on every change.
+func NewIngestCredentialServiceWithCache(store ports.IngestCredentialStore, ingestSettings *IngestSettingsCache) *IngestCredentialService {
+	return &IngestCredentialService{store: store, now: time.Now, ingestSettings: ingestSettings}
+}
+
+// List returns every credential of one organization, including expired and revoked ones.
+func (s *IngestCredentialService) List(ctx context.Context, organizationID int64) ([]ports.IngestCredential, error) {
+	return s.store.ListIngestCredentials(ctx, organizationID)
+}
+
+// Create issues a new auth token and webhook secret pair. A non-positive
+// validity creates a credential that never expires.
+func (s *IngestCredentialService) Create(ctx context.Context, organizationID int64, label string, validity time.Duration) (ports.IngestCredential, error) {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return ports.IngestCredential{}, ErrIngestCredentialLabelRequired
+	}
+	if len(label) > maxIngestCredentialLabelLength {
+		label = label[:maxIngestCredentialLabelLength]
+	}
+	authToken, err := randomHexToken(16)
+	if err != nil {
+		return ports.IngestCredential{}, err
+	}
+	secret, err := randomHexToken(24)
+	if err != nil {
+		return ports.IngestCredential{}, err
+	}
+
+	now := s.now().UTC()
+	credential := ports.IngestCredential{
+		OrganizationID: organizationID,
+		Label:          label,
+		AuthToken:      authToken,
+		WebhookSecret:  secret,
+		CreatedAt:      now,
+	}
+	if validity > 0 {
+		expiresAt := now.Add(validity)
+		credential.ExpiresAt = &expiresAt
+	}
+	defer s.ingestSettings.Invalidate(organizationID)
+	return s.store.CreateIngestCredential(ctx, credential)
+}
+
+// Expire keeps a credential valid for the given grace period and rejects it afterwards.
+func (s *IngestCredentialService) Expire(ctx context.Context, organizationID, id int64, grace time.Duration) error {
+	if grace < 0 {
+		grace = 0
+	}
+	defer s.ingestSettings.Invalidate(organizationID)
+	updated, err := s.store.ExpireIngestCredential(ctx, organizationID, id, s.now().UTC().Add(grace))
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrIngestCredentialNotFound
+	}
+	return nil
+}
+
+// Revoke rejects a credential immediately.
+func (s *IngestCredentialService) Revoke(ctx context.Context, organizationID, id int64) error {
+	defer s.ingestSettings.Invalidate(organizationID)
+	updated, err := s.store.RevokeIngestCredential(ctx, organizationID, id, s.now().UTC())
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrIngestCredentialNotFound
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+const (
+	ingestHealthWindow      = 24 * time.Hour
+	ingestHealthSourceLimit = 50
+)
+
+// IngestHealthHour is the ingestion volume of one organization in one hour.
+type IngestHealthHour struct {
+	Start      time.Time
+	Accepted   int64
+	Duplicates int64
+	Dropped    int64
+	Rejected   int64
+}
+
+// Total returns every event received in the hour, whatever its outcome.
+func (h IngestHealthHour) Total() int64 {
+	return h.Accepted + h.Duplicates + h.Dropped + h.Rejected
+}
+
+// IngestRejectionCount is the number of rejected events for one reason.
+type IngestRejectionCount struct {
+	Reason string
+	Count  int64
+}
+
+// IngestHealth summarizes the last 24 hours of ingestion for one organization.
+type IngestHealth struct {
+	Hours      []IngestHealthHour
+	Totals     IngestHealthHour
+	Rejections []IngestRejectionCount
+	Sources    []ports.IngestSourceActivity
+}
+
+// IngestHealthService reads rolled-up ingestion outcomes so teams can tell
+// whether their pipelines are reporting.
+type IngestHealthService struct {
+	store ports.IngestHealthStore
+	now   func() time.Time
+}
+
+// NewIngestHealthService constructs an ingest health service.
+func NewIngestHealthService(store ports.IngestHealthStore) *IngestHealthService {
+	return &IngestHealthService{store: store, now: time.Now}
+}
+
+// Get returns hourly volume, rejection reasons and the latest event per source.
+// Hours are oldest first and include empty hours.
+func (s *IngestHealthService) Get(ctx context.Context, organizationID int64) (IngestHealth, error) {
+	current := s.now().UTC().Truncate(time.Hour)
+	since := current.Add(-ingestHealthWindow + time.Hour)
+	stats, err := s.store.ListIngestHourlyStats(ctx, organizationID, since)
+	if err != nil {
+		return IngestHealth{}, err
+	}
+	sources, err := s.store.ListIngestSourceActivity(ctx, organizationID, ingestHealthSourceLimit)
+	if err != nil {
+		return IngestHealth{}, err
+	}
+
+	hours := make([]IngestHealthHour, 0, int(ingestHealthWindow/time.Hour))
+	index := map[int64]int{}
+	for start := since; !start.After(current); start = start.Add(time.Hour) {
+		index[start.UnixMilli()] = len(hours)
+		hours = append(hours, IngestHealthHour{Start: start})
+	}
+
+	health := IngestHealth{Sources: sources}
+	rejections := map[string]int64{}
+	for _, stat := range stats {
+		position, ok := index[stat.BucketStart.UnixMilli()]
+		if !ok {
+			continue
+		}
+		hour := &hours[position]
+		switch stat.Outcome {
+		case IngestBatchAccepted:
+			hour.Accepted += stat.Count
+			health.Totals.Accepted += stat.Count
+		case IngestBatchDuplicate:
+			hour.Duplicates += stat.Count
+			health.Totals.Duplicates += stat.Count
+		case IngestBatchDropped:
+			hour.Dropped += stat.Count
+			health.Totals.Dropped += stat.Count
+		case IngestBatchRejected:
+			hour.Rejected += stat.Count
+			health.Totals.Rejected += stat.Count
+			rejections[stat.Reason] += stat.Count
+		}
+	}
+	health.Hours = hours
+
+	for reason, count := range rejections {
+		health.Rejections = append(health.Rejections, IngestRejectionCount{Reason: reason, Count: count})
+	}
+	sort.Slice(health.Rejections, func(i, j int) bool {
+		if health.Rejections[i].Count != health.Rejections[j].Count {
+			return health.Rejections[i].Count > health.Rejections[j].Count
+		}
+		return health.Rejections[i].Reason < health.Rejections[j].Reason
+	})
+	return health, nil
+}
@@ -42,38 +42,37 @@ var (
 	ErrUnsupportedType = errors.New("unsupported event type")
 	// ErrIngestBusy indicates ingestion queue saturation.
 	ErrIngestBusy = errors.New("ingestion busy")
+	// ErrStaleSignature indicates a timestamped signature outside the allowed skew.
+	ErrStaleSignature = errors.New("stale signature timestamp")
+	// ErrReplayedSignature indicates a timestamped signature that was already used.
+	ErrReplayedSignature = errors.New("replayed signature")
 )
 
 const bearerPrefix = "Bearer "
 
-var allowedDeliveryTypes = map[string]struct{}{
-	cdeventsv05.EnvironmentCreatedEventType.String():  {},
-	cdeventsv05.EnvironmentModifiedEventType.String(): {},
-	cdeventsv05.EnvironmentDeletedEventType.String():  {},
-	cdeventsv05.ServiceDeployedEventType.String():     {},
-	cdeventsv05.ServiceUpgradedEventType.String():     {},
-	cdeventsv05.ServiceRolledbackEventType.String():   {},
-	cdeventsv05.ServiceRemovedEventType.String():      {},
-	cdeventsv05.ServicePublishedEventType.String():    {},
-}
-
-var allowedTypePrefixes = []string{
-	"dev.cdevents.pipeline.",
-	"dev.cdevents.change.",
-	"dev.cdevents.artifact.",
-	"dev.cdevents.incident.",
-}
-
 // EventIngestService validates and appends CDEvents to the event store.
 type EventIngestService struct {
 	storeFactory ports.IngestionStoreFactory
 	batcher      *ingestBatcher
+	publisher    ports.ServiceChangePublisher
+	skew         time.Duration
+	now          func() time.Time
+	metrics      ingestMetrics
+	settings     *IngestSettingsCache
 }
 
 type IngestBatchConfig struct {
 	Enabled       bool
 	Size          int
 	FlushInterval time.Duration
+	// Publisher receives service changes once appended events are committed.
+	Publisher ports.ServiceChangePublisher
+	// SignatureSkew bounds how far a timestamped signature may drift from now.
+	SignatureSkew time.Duration
+	// Settings caches per-organization ingestion settings; share it with the
+	// settings and credential services so their updates invalidate it. Nil
+	// reads the settings for every delivery.
+	Settings *IngestSettingsCache
 }
 
 // IngestErrorKind classifies ingestion failures for transport-specific mapping.
@@ -96,12 +95,20 @@ const (
 	IngestErrorUnsupportedType IngestErrorKind = "unsupported_type"
 	// IngestErrorBusy indicates ingestion queue saturation.
 	IngestErrorBusy IngestErrorKind = "busy"
+	// IngestErrorStaleSignature indicates a signature timestamp outside the skew window.
+	IngestErrorStaleSignature IngestErrorKind = "stale_signature"
+	// IngestErrorReplayedSignature indicates a signature that was already accepted.
+	IngestErrorReplayedSignature IngestErrorKind = "replayed_signature"
+	// IngestErrorBatchTooLarge indicates a batch exceeds the event limit.
+	IngestErrorBatchTooLarge IngestErrorKind = "batch_too_large"
 )
 
 // IngestCommand is transport-agnostic webhook ingestion input.
 type IngestCommand struct {
 	AuthorizationHeader string
 	SignatureHeader     string
+	TimestampHeader     string
+	NonceHeader         string
 	Headers             http.Header
 	Body                []byte
 }
@@ -116,7 +123,11 @@ func NewEventIngestService(storeFactory ports.IngestionStoreFactory) *EventInges
 }
 
 func NewEventIngestServiceWithConfig(storeFactory ports.IngestionStoreFactory, batchCfg IngestBatchConfig) *EventIngestService {
-	service := &EventIngestService{storeFactory: storeFactory}
+	skew := batchCfg.SignatureSkew
+	if skew <= 0 {
+		skew = defaultSignatureSkew
+	}
+	service := &EventIngestService{storeFactory: storeFactory, publisher: batchCfg.Publisher, skew: skew, now: time.Now, metrics: newIngestMetrics(), settings: batchCfg.Settings}
 	if batchCfg.Enabled {
 		size := batchCfg.Size
 		if size <= 0 {
@@ -129,7 +140,7 @@ func NewEventIngestServiceWithConfig(storeFactory ports.IngestionStoreFactory, b
 		if interval <= 0 {
 			interval = 50 * time.Millisecond
 		}
-		service.batcher = newIngestBatcher(storeFactory, size, interval)
+		service.batcher = newIngestBatcher(storeFactory, size, interval, batchCfg.Publisher, service.metrics)
 	}
 	return service
 }
@@ -153,6 +164,12 @@ func ClassifyIngestError(err error) IngestErrorKind {
 		return IngestErrorUnsupportedType
 	case errors.Is(err, ErrIngestBusy):
 		return IngestErrorBusy
+	case errors.Is(err, ErrStaleSignature):
+		return IngestErrorStaleSignature
+	case errors.Is(err, ErrReplayedSignature):
+		return IngestErrorReplayedSignature
+	case errors.Is(err, ErrBatchTooLarge):
+		return IngestErrorBatchTooLarge
 	default:
 		return IngestErrorUnknown
 	}
@@ -160,82 +177,37 @@ func ClassifyIngestError(err error) IngestErrorKind {
 
 // Ingest validates command auth/signature/event and appends to event store.
 func (s *EventIngestService) Ingest(ctx context.Context, cmd IngestCommand) error {
-	token, err := bearerToken(cmd.AuthorizationHeader)
+	org, err := s.authenticate(ctx, cmd)
 	if err != nil {
-		return ErrMissingAuthToken
-	}
-
-	org, err := s.lookupOrganization(ctx, token)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return ErrInvalidAuthToken
-		}
 		return err
 	}
 	ctx = observability.WithRequestIdentity(ctx, 0, org.ID)
 
-	if !validSignature(cmd.Body, org.WebhookSecret, cmd.SignatureHeader) {
-		return ErrInvalidSignature
-	}
+	return s.ingestRecordingDeadLetters(ctx, org.ID, cmd.Headers, cmd.Body)
+}
 
-	event, err := parseIncomingEvent(ctx, cmd.Headers, cmd.Body)
+// authenticate resolves the organization of a command and verifies the body signature.
+func (s *EventIngestService) authenticate(ctx context.Context, cmd IngestCommand) (ports.Organization, error) {
+	token, err := bearerToken(cmd.AuthorizationHeader)
 	if err != nil {
-		return ErrInvalidPayload
-	}
-	eventType := event.GetType().String()
-	if !isSupportedEventType(eventType) {
-		return ErrUnsupportedType
-	}
-	if requiresStrictSchemaValidation(eventType) {
-		if err := cdeventsapi.Validate(event); err != nil {
-			return ErrInvalidSchema
-		}
-	}
-
-	subjectType := strings.TrimSpace(event.GetType().Subject)
-	if subjectType == "" {
-		parsedType, parseErr := cdeventsapi.ParseType(eventType)
-		if parseErr == nil {
-			subjectType = strings.TrimSpace(parsedType.Subject)
-		}
-	}
-	if subjectType == "" {
-		subjectType = "unknown"
+		s.metrics.recordRejected(ctx, 0, "", IngestErrorMissingAuth)
+		return ports.Organization{}, ErrMissingAuthToken
 	}
 
-	raw, err := cdeventsapi.AsJsonString(event)
+	org, err := s.lookupOrganization(ctx, token)
 	if err != nil {
-		return err
-	}
-
-	var chainID *string
-	if eventV04, ok := event.(cdeventsapi.CDEventReaderV04); ok {
-		value := strings.TrimSpace(eventV04.GetChainId())
-		if value != "" {
-			chainID = &value
+		if errors.Is(err, sql.ErrNoRows) {
+			s.metrics.recordRejected(ctx, 0, "", IngestErrorInvalidAuth)
+			return ports.Organization{}, ErrInvalidAuthToken
 		}
+		return ports.Organization{}, err
 	}
 
-	var subjectSource *string
-	if value := strings.TrimSpace(event.GetSubjectSource()); value != "" {
-		subjectSource = &value
-	}
-
-	record := ports.EventRecord{
-		OrganizationID: org.ID,
-		EventID:        event.GetId(),
-		EventType:      eventType,
-		EventSource:    event.GetSource(),
-		EventTimestamp: event.GetTimestamp().UTC().Format(time.RFC3339Nano),
-		EventTSMs:      event.GetTimestamp().UTC().UnixMilli(),
-		SubjectID:      event.GetSubjectId(),
-		SubjectSource:  subjectSource,
-		SubjectType:    subjectType,
-		ChainID:        chainID,
-		RawEventJSON:   raw,
+	if err := s.verifySignature(ctx, org, token, cmd); err != nil {
+		s.recordRejection(ctx, org.ID, "", err)
+		return ports.Organization{}, err
 	}
-
-	return s.appendRecord(ctx, record)
+	return org, nil
 }
 
 // IngestForOrganization ingests an event for a pre-resolved organization.
@@ -245,19 +217,51 @@ func (s *EventIngestService) IngestForOrganization(ctx context.Context, organiza
 	}
 	ctx = observability.WithRequestIdentity(ctx, 0, organizationID)
 
+	return s.ingestRecordingDeadLetters(ctx, organizationID, headers, body)
+}
+
+func (s *EventIngestService) ingestRecordingDeadLetters(ctx context.Context, organizationID int64, headers http.Header, body []byte) error {
+	err := s.ingestEvent(ctx, organizationID, headers, body)
+	if isDeadLetterError(err) {
+		s.recordDeadLetter(ctx, organizationID, headers, body, err)
+	}
+	return err
+}
+
+// ingestEvent parses, admits and appends one event for an authenticated organization.
+func (s *EventIngestService) ingestEvent(ctx context.Context, organizationID int64, headers http.Header, body []byte) error {
 	event, err := parseIncomingEvent(ctx, headers, body)
 	if err != nil {
+		s.recordRejection(ctx, organizationID, "", ErrInvalidPayload)
 		return ErrInvalidPayload
 	}
 	eventType := event.GetType().String()
-	if !isSupportedEventType(eventType) {
-		return ErrUnsupportedType
+	admitted, err := s.admitEvent(ctx, organizationID, event)
+	if err != nil {
+		s.recordRejection(ctx, organizationID, eventType, err)
+		return err
 	}
-	if requiresStrictSchemaValidation(eventType) {
-		if err := cdeventsapi.Validate(event); err != nil {
-			return ErrInvalidSchema
-		}
+	if !admitted {
+		s.metrics.recordDropped(ctx, organizationID, eventType)
+		s.recordOutcomes(ctx, organizationID, IngestBatchDropped, "", 1)
+		return nil
 	}
+	record, err := buildEventRecord(organizationID, event)
+	if err != nil {
+		return err
+	}
+	appended, err := s.appendRecord(ctx, record)
+	if err != nil {
+		s.metrics.recordRejected(ctx, organizationID, eventType, ClassifyIngestError(err))
+		return err
+	}
+	s.metrics.recordAppended(ctx, organizationID, eventType, appended)
+	return nil
+}
+
+// buildEventRecord normalizes a parsed CDEvent into an event-store append request.
+func buildEventRecord(organizationID int64, event cdeventsapi.CDEventV04) (ports.EventRecord, error) {
+	eventType := event.GetType().String()
 
 	subjectType := strings.TrimSpace(event.GetType().Subject)
 	if subjectType == "" {
@@ -272,7 +276,7 @@ func (s *EventIngestService) IngestForOrganization(ctx context.Context, organiza
 
 	raw, err := cdeventsapi.AsJsonString(event)
 	if err != nil {
-		return err
+		return ports.EventRecord{}, err
 	}
 
 	var chainID *string
@@ -288,7 +292,7 @@ func (s *EventIngestService) IngestForOrganization(ctx context.Context, organiza
 		subjectSource = &value
 	}
 
-	record := ports.EventRecord{
+	return ports.EventRecord{
 		OrganizationID: organizationID,
 		EventID:        event.GetId(),
 		EventType:      eventType,
@@ -300,12 +304,11 @@ func (s *EventIngestService) IngestForOrganization(ctx context.Context, organiza
 		SubjectType:    subjectType,
 		ChainID:        chainID,
 		RawEventJSON:   raw,
-	}
-
-	return s.appendRecord(ctx, record)
+	}, nil
 }
 
-func (s *EventIngestService) appendRecord(ctx context.Context, record ports.EventRecord) error {
+// appendRecord appends one record and reports whether it was new to the event store.
+func (s *EventIngestService) appendRecord(ctx context.Context, record ports.EventRecord) (bool, error) {
 
 	if s.batcher != nil {
 		return s.batcher.append(ctx, record)
@@ -313,33 +316,53 @@ func (s *EventIngestService) appendRecord(ctx context.Context, record ports.Even
 
 	store, err := s.storeFactory.Open()
 	if err != nil {
-		return err
+		return false, err
 	}
 	defer func() {
 		_ = store.Close()
 	}()
-	return store.AppendEvent(ctx, record)
+	appended, err := store.AppendEvents(ctx, []ports.EventRecord{record})
+	if err != nil {
+		return false, err
+	}
+	publishServiceChanges(s.publisher, appended)
+	return len(appended) > 0, nil
 }
 
-func isSupportedEventType(eventType string) bool {
-	eventType = strings.TrimSpace(eventType)
-	if eventType == "" {
-		return false
+func publishServiceChanges(publisher ports.ServiceChangePublisher, appended []ports.AppendedEvent) {
+	if publisher == nil || len(appended) == 0 {
+		return
 	}
-	if _, ok := allowedDeliveryTypes[eventType]; ok {
-		return true
+	if changes := serviceChangesFromAppended(appended); len(changes) > 0 {
+		publisher.PublishServiceChanges(changes)
 	}
-	for _, prefix := range allowedTypePrefixes {
-		if strings.HasPrefix(eventType, prefix) {
-			return true
-		}
+}
+
+// admitEvent applies the organization event policy and reports whether the
+// event should be stored. Dropped events are acknowledged without an error.
+func (s *EventIngestService) admitEvent(ctx context.Context, organizationID int64, event cdeventsapi.CDEventV04) (bool, error) {
+	policy, err := s.loadEventPolicy(ctx, organizationID)
+	if err != nil {
+		return false, err
 	}
-	return false
+	return policy.admit(event)
 }
 
-func requiresStrictSchemaValidation(eventType string) bool {
-	_, ok := allowedDeliveryTypes[strings.TrimSpace(eventType)]
-	return ok
+// admit reports whether the event should be stored under this policy.
+func (p eventPolicy) admit(event cdeventsapi.CDEventV04) (bool, error) {
+	switch p.mode(event.GetType().String()) {
+	case EventPolicyAccept:
+		return true, nil
+	case EventPolicyStrict:
+		if err := cdeventsapi.Validate(event); err != nil {
+			return false, ErrInvalidSchema
+		}
+		return true, nil
+	case EventPolicyDrop:
+		return false, nil
+	default:
+		return false, ErrUnsupportedType
+	}
 }
 
 func (s *EventIngestService) lookupOrganization(ctx context.Context, token string) (ports.Organization, error) {
@@ -364,6 +387,7 @@ func (s *EventIngestService) lookupOrganization(ctx context.Context, token strin
 
 type ingestBatcher struct {
 	storeFactory  ports.IngestionStoreFactory
+	publisher     ports.ServiceChangePublisher
 	batchSize     int
 	flushInterval time.Duration
 	queue         chan ingestBatchRequest
@@ -372,20 +396,29 @@ type ingestBatcher struct {
 	flushBatches  atomic.Int64
 	flushEvents   atomic.Int64
 	flushErrors   atomic.Int64
+	metrics       ingestMetrics
 }
 
 type ingestBatchRequest struct {
 	ctx    context.Context
 	event  ports.EventRecord
-	result chan error
+	result chan ingestBatchOutcome
+}
+
+// ingestBatchOutcome reports whether a queued record was new to the event store.
+type ingestBatchOutcome struct {
+	appended bool
+	err      error
 }
 
-func newIngestBatcher(storeFactory ports.IngestionStoreFactory, batchSize int, flushInterval time.Duration) *ingestBatcher {
+func newIngestBatcher(storeFactory ports.IngestionStoreFactory, batchSize int, flushInterval time.Duration, publisher ports.ServiceChangePublisher, metrics ingestMetrics) *ingestBatcher {
 	b := &ingestBatcher{
 		storeFactory:  storeFactory,
+		publisher:     publisher,
 		batchSize:     batchSize,
 		flushInterval: flushInterval,
 		queue:         make(chan ingestBatchRequest, batchSize*8),
+		metrics:       metrics,
 	}
 	b.startOnce.Do(func() {
 		go b.run()
@@ -393,24 +426,24 @@ func newIngestBatcher(storeFactory ports.IngestionStoreFactory, batchSize int, f
 	return b
 }
 
-func (b *ingestBatcher) append(ctx context.Context, event ports.EventRecord) error {
-	result := make(chan error, 1)
+func (b *ingestBatcher) append(ctx context.Context, event ports.EventRecord) (bool, error) {
+	result := make(chan ingestBatchOutcome, 1)
 	request := ingestBatchRequest{ctx: ctx, event: event, result: result}
 
 	select {
 	case b.queue <- request:
 		b.accepted.Add(1)
 	default:
-		return ErrIngestBusy
+		return false, ErrIngestBusy
 	case <-ctx.Done():
-		return ctx.Err()
+		return false, ctx.Err()
 	}
 
 	select {
-	case err := <-result:
-		return err
+	case outcome := <-result:
+		return outcome.appended, outcome.err
 	case <-ctx.Done():
-		return ctx.Err()
+		return false, ctx.Err()
 	}
 }
 
@@ -453,7 +486,7 @@ func (b *ingestBatcher) flush(batch []ingestBatchRequest) {
 		b.flushErrors.Add(1)
 		slog.Error("ingest_batch_open_failed", "error", err, "batch_size", len(batch))
 		for _, item := range batch {
-			item.result <- err
+			item.result <- ingestBatchOutcome{err: err}
 		}
 		return
 	}
@@ -466,19 +499,37 @@ func (b *ingestBatcher) flush(batch []ingestBatchRequest) {
 		events = append(events, item.event)
 	}
 
-	err = store.AppendEvents(context.Background(), events)
+	started := time.Now()
+	appended, err := store.AppendEvents(context.Background(), events)
+	b.metrics.recordFlush(context.Background(), "batcher", len(events), started, err)
 	if err != nil {
 		b.flushErrors.Add(1)
 		slog.Error("ingest_batch_flush_failed", "error", err, "batch_size", len(events))
 	} else {
 		b.flushBatches.Add(1)
 		b.flushEvents.Add(int64(len(events)))
+		publishServiceChanges(b.publisher, appended)
+	}
+	fresh := make(map[appendedKey]int, len(appended))
+	for _, event := range appended {
+		fresh[appendedKey{organizationID: event.OrganizationID, eventID: event.EventID}]++
 	}
 	for _, item := range batch {
-		item.result <- err
+		key := appendedKey{organizationID: item.event.OrganizationID, eventID: item.event.EventID}
+		isNew := fresh[key] > 0
+		if isNew {
+			fresh[key]--
+		}
+		item.result <- ingestBatchOutcome{appended: isNew, err: err}
 	}
 }
 
+// appendedKey matches queued records to the events a flush actually appended.
+type appendedKey struct {
+	organizationID int64
+	eventID        string
+}
+
 func parseIncomingEvent(ctx context.Context, headers http.Header, body []byte) (cdeventsapi.CDEventV04, error) {
 	event, err := cdeventsv05.NewFromJsonBytes(body)
 	if err == nil {
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	cdeventsv05 "github.com/cdevents/sdk-go/pkg/api/v05"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+	"github.com/fr0stylo/ddash/internal/observability"
+)
+
+const (
+	// MaxIngestBatchEvents caps how many events one batch request may carry.
+	MaxIngestBatchEvents = 1000
+
+	// IngestBatchAccepted marks an event newly appended to the event store.
+	IngestBatchAccepted = "accepted"
+	// IngestBatchQueued marks an event spooled for a later append; duplicates
+	// among queued events are only detected once the spool drains.
+	IngestBatchQueued = "queued"
+	// IngestBatchDuplicate marks an event already present in the event store or earlier in the batch.
+	IngestBatchDuplicate = "duplicate"
+	// IngestBatchDropped marks an event acknowledged but discarded by the organization event policy.
+	IngestBatchDropped = "dropped"
+	// IngestBatchRejected marks an event refused during parsing, validation or policy checks.
+	IngestBatchRejected = "rejected"
+)
+
+// ErrBatchTooLarge indicates a batch carries more than MaxIngestBatchEvents events.
+var ErrBatchTooLarge = errors.New("batch too large")
+
+// IngestBatchEventResult is the outcome for one event of a batch.
+type IngestBatchEventResult struct {
+	Index   int
+	EventID string
+	Status  string
+	Reason  IngestErrorKind
+}
+
+// IngestBatchResult summarizes one batch ingestion.
+type IngestBatchResult struct {
+	Accepted   int
+	Queued     int
+	Duplicates int
+	Dropped    int
+	Rejected   int
+	Events     []IngestBatchEventResult
+}
+
+// IngestBatch verifies one signature over a JSON array or NDJSON stream of
+// CDEvents, admits each event independently and appends the admitted events
+// in a single transaction. Per-event rejections are reported in the result
+// and kept as dead letters; only request-level failures return an error.
+func (s *EventIngestService) IngestBatch(ctx context.Context, cmd IngestCommand) (IngestBatchResult, error) {
+	org, err := s.authenticate(ctx, cmd)
+	if err != nil {
+		return IngestBatchResult{}, err
+	}
+	ctx = observability.WithRequestIdentity(ctx, 0, org.ID)
+
+	items, err := splitBatchBody(cmd.Body)
+	if err != nil {
+		return IngestBatchResult{}, err
+	}
+	policy, err := s.loadEventPolicy(ctx, org.ID)
+	if err != nil {
+		return IngestBatchResult{}, err
+	}
+
+	results := make([]IngestBatchEventResult, len(items))
+	eventTypes := make([]string, len(items))
+	records := make([]ports.EventRecord, 0, len(items))
+	pending := map[string]int{}
+	for index, item := range items {
+		results[index] = IngestBatchEventResult{Index: index}
+		record, admitted, itemErr := admitBatchItem(org.ID, policy, item)
+		results[index].EventID = record.EventID
+		eventTypes[index] = record.EventType
+		switch {
+		case itemErr != nil:
+			results[index].Status = IngestBatchRejected
+			results[index].Reason = ClassifyIngestError(itemErr)
+			s.recordDeadLetter(ctx, org.ID, cmd.Headers, item, itemErr)
+		case !admitted:
+			results[index].Status = IngestBatchDropped
+		default:
+			if _, seen := pending[record.EventID]; seen {
+				results[index].Status = IngestBatchDuplicate
+				continue
+			}
+			pending[record.EventID] = index
+			records = append(records, record)
+		}
+	}
+
+	if len(records) > 0 {
+		appended, err := s.appendBatch(ctx, records)
+		if err != nil {
+			return IngestBatchResult{}, err
+		}
+		for _, event := range appended {
+			index, ok := pending[event.EventID]
+			switch {
+			case !ok:
+			case event.Queued:
+				results[index].Status = IngestBatchQueued
+			default:
+				results[index].Status = IngestBatchAccepted
+			}
+		}
+		for _, index := range pending {
+			if results[index].Status == "" {
+				results[index].Status = IngestBatchDuplicate
+			}
+		}
+	}
+
+	out := IngestBatchResult{Events: results}
+	rejections := map[IngestErrorKind]int64{}
+	for index, result := range results {
+		switch result.Status {
+		case IngestBatchAccepted:
+			out.Accepted++
+			s.metrics.recordAppended(ctx, org.ID, eventTypes[index], true)
+		case IngestBatchQueued:
+			out.Queued++
+			s.metrics.recordAppended(ctx, org.ID, eventTypes[index], true)
+		case IngestBatchDuplicate:
+			out.Duplicates++
+			s.metrics.recordAppended(ctx, org.ID, eventTypes[index], false)
+		case IngestBatchDropped:
+			out.Dropped++
+			s.metrics.recordDropped(ctx, org.ID, eventTypes[index])
+		case IngestBatchRejected:
+			out.Rejected++
+			rejections[result.Reason]++
+			s.metrics.recordRejected(ctx, org.ID, eventTypes[index], result.Reason)
+		}
+	}
+	s.recordOutcomes(ctx, org.ID, IngestBatchDropped, "", int64(out.Dropped))
+	for reason, count := range rejections {
+		s.recordOutcomes(ctx, org.ID, IngestBatchRejected, string(reason), count)
+	}
+	return out, nil
+}
+
+// appendBatch appends records in one transaction, bypassing the request batcher.
+func (s *EventIngestService) appendBatch(ctx context.Context, records []ports.EventRecord) ([]ports.AppendedEvent, error) {
+	store, err := s.storeFactory.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = store.Close()
+	}()
+	started := time.Now()
+	appended, err := store.AppendEvents(ctx, records)
+	s.metrics.recordFlush(ctx, "batch_request", len(records), started, err)
+	if err != nil {
+		return nil, err
+	}
+	publishServiceChanges(s.publisher, appended)
+	return appended, nil
+}
+
+func admitBatchItem(organizationID int64, policy eventPolicy, item []byte) (ports.EventRecord, bool, error) {
+	event, err := cdeventsv05.NewFromJsonBytes(item)
+	if err != nil {
+		return ports.EventRecord{}, false, ErrInvalidPayload
+	}
+	partial := ports.EventRecord{EventID: event.GetId(), EventType: event.GetType().String()}
+	admitted, err := policy.admit(event)
+	if err != nil || !admitted {
+		return partial, false, err
+	}
+	record, err := buildEventRecord(organizationID, event)
+	if err != nil {
+		return partial, false, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	return record, true, nil
+}
+
+// splitBatchBody returns the raw events of a JSON array or NDJSON body.
+func splitBatchBody(body []byte) ([][]byte, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return nil, ErrInvalidPayload
+	}
+
+	var items [][]byte
+	if trimmed[0] == '[' {
+		var raw []json.RawMessage
+		if err := json.Unmarshal(trimmed, &raw); err != nil {
+			return nil, ErrInvalidPayload
+		}
+		items = make([][]byte, 0, len(raw))
+		for _, item := range raw {
+			items = append(items, item)
+		}
+	} else {
+		for _, line := range bytes.Split(trimmed, []byte("\n")) {
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				items = append(items, line)
+			}
+		}
+	}
+
+	if len(items) == 0 {
+		return nil, ErrInvalidPayload
+	}
+	if len(items) > MaxIngestBatchEvents {
+		return nil, ErrBatchTooLarge
+	}
+	return items, nil
+}
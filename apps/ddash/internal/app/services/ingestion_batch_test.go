@@ -0,0 +1,30 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSplitBatchBodyAcceptsArrayAndNDJSON(t *testing.T) {
+	array, err := splitBatchBody([]byte(` [{"a":1}, {"b":2}] `))
+	if err != nil || len(array) != 2 || string(array[1]) != `{"b":2}` {
+		t.Fatalf("unexpected array split: %q err=%v", array, err)
+	}
+	lines, err := splitBatchBody([]byte("{\"a\":1}\n\n{\"b\":2}\r\n"))
+	if err != nil || len(lines) != 2 || string(lines[1]) != `{"b":2}` {
+		t.Fatalf("unexpected ndjson split: %q err=%v", lines, err)
+	}
+}
+
+func TestSplitBatchBodyRejectsEmptyAndOversizedBatches(t *testing.T) {
+	for _, body := range []string{"", "  \n ", "[]", "[{"} {
+		if _, err := splitBatchBody([]byte(body)); !errors.Is(err, ErrInvalidPayload) {
+			t.Fatalf("expected invalid payload for %q, got %v", body, err)
+		}
+	}
+	oversized := strings.Repeat("{}\n", MaxIngestBatchEvents+1)
+	if _, err := splitBatchBody([]byte(oversized)); !errors.Is(err, ErrBatchTooLarge) {
+		t.Fatalf("expected batch too large, got %v", err)
+	}
+}
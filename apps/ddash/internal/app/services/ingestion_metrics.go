@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ingestMetrics records ingestion outcomes labelled by organization, event
+// type and rejection kind, and append latency of batched writes.
+type ingestMetrics struct {
+	accepted      metric.Int64Counter
+	duplicates    metric.Int64Counter
+	dropped       metric.Int64Counter
+	rejected      metric.Int64Counter
+	flushDuration metric.Float64Histogram
+	flushSize     metric.Int64Histogram
+}
+
+func newIngestMetrics() ingestMetrics {
+	meter := otel.Meter("github.com/fr0stylo/ddash/apps/ddash/internal/app/services")
+	accepted, _ := meter.Int64Counter("ddash.ingestion.events.accepted")
+	duplicates, _ := meter.Int64Counter("ddash.ingestion.events.duplicate")
+	dropped, _ := meter.Int64Counter("ddash.ingestion.events.dropped")
+	rejected, _ := meter.Int64Counter("ddash.ingestion.events.rejected")
+	flushDuration, _ := meter.Float64Histogram("ddash.ingestion.batch.flush_duration", metric.WithUnit("ms"))
+	flushSize, _ := meter.Int64Histogram("ddash.ingestion.batch.flush_size")
+	return ingestMetrics{
+		accepted:      accepted,
+		duplicates:    duplicates,
+		dropped:       dropped,
+		rejected:      rejected,
+		flushDuration: flushDuration,
+		flushSize:     flushSize,
+	}
+}
+
+func (m ingestMetrics) recordAppended(ctx context.Context, organizationID int64, eventType string, appended bool) {
+	if appended {
+		m.accepted.Add(ctx, 1, eventAttributes(organizationID, eventType))
+		return
+	}
+	m.duplicates.Add(ctx, 1, eventAttributes(organizationID, eventType))
+}
+
+func (m ingestMetrics) recordDropped(ctx context.Context, organizationID int64, eventType string) {
+	m.dropped.Add(ctx, 1, eventAttributes(organizationID, eventType))
+}
+
+func (m ingestMetrics) recordRejected(ctx context.Context, organizationID int64, eventType string, kind IngestErrorKind) {
+	m.rejected.Add(ctx, 1, metric.WithAttributes(
+		attribute.Int64("organization_id", organizationID),
+		attribute.String("event_type", eventTypeLabel(eventType)),
+		attribute.String("reason", string(kind)),
+	))
+}
+
+func (m ingestMetrics) recordFlush(ctx context.Context, path string, size int, started time.Time, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	attrs := metric.WithAttributes(attribute.String("path", path), attribute.String("outcome", outcome))
+	m.flushDuration.Record(ctx, float64(time.Since(started).Microseconds())/1000, attrs)
+	m.flushSize.Record(ctx, int64(size), attrs)
+}
+
+func eventAttributes(organizationID int64, eventType string) metric.MeasurementOption {
+	return metric.WithAttributes(
+		attribute.Int64("organization_id", organizationID),
+		attribute.String("event_type", eventTypeLabel(eventType)),
+	)
+}
+
+func eventTypeLabel(eventType string) string {
+	if eventType == "" {
+		return "unknown"
+	}
+	return eventType
+}
+
+// recordRejection counts one rejected event and, when the organization is
+// known, adds it to the organization's ingest health rollup.
+func (s *EventIngestService) recordRejection(ctx context.Context, organizationID int64, eventType string, cause error) {
+	kind := ClassifyIngestError(cause)
+	s.metrics.recordRejected(ctx, organizationID, eventType, kind)
+	s.recordOutcomes(ctx, organizationID, IngestBatchRejected, string(kind), 1)
+}
+
+// recordOutcomes persists rejected and dropped counts; failures are logged and
+// never change the ingestion result.
+func (s *EventIngestService) recordOutcomes(ctx context.Context, organizationID int64, outcome, reason string, count int64) {
+	if organizationID <= 0 || count <= 0 {
+		return
+	}
+	store, err := s.storeFactory.Open()
+	if err != nil {
+		slog.WarnContext(ctx, "ingest_outcome_record_failed", "error", err, "organization_id", organizationID)
+		return
+	}
+	defer func() {
+		_ = store.Close()
+	}()
+	if err := store.RecordIngestOutcome(ctx, organizationID, outcome, reason, count, s.now().UTC()); err != nil {
+		slog.WarnContext(ctx, "ingest_outcome_record_failed", "error", err, "organization_id", organizationID, "outcome", outcome)
+	}
+}
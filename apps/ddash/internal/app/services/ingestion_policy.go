@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	cdeventsv05 "github.com/cdevents/sdk-go/pkg/api/v05"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+const (
+	// EventPolicyAccept stores events of the subject without schema validation.
+	EventPolicyAccept = "accept"
+	// EventPolicyStrict stores events only after CDEvents schema validation passes.
+	EventPolicyStrict = "strict"
+	// EventPolicyDrop acknowledges events of the subject without storing them.
+	EventPolicyDrop = "drop"
+	// EventPolicyReject refuses events of the subject as unsupported.
+	EventPolicyReject = "reject"
+)
+
+const cdeventsTypePrefix = "dev.cdevents."
+
+// EventPolicySubjects lists CDEvents subjects configurable per organization, in display order.
+var EventPolicySubjects = []string{
+	"service",
+	"environment",
+	"pipeline",
+	"change",
+	"artifact",
+	"incident",
+	"pipelinerun",
+	"taskrun",
+	"build",
+	"repository",
+	"branch",
+	"testcaserun",
+	"testsuiterun",
+	"ticket",
+}
+
+var defaultEventPolicyModes = map[string]string{
+	"service":     EventPolicyStrict,
+	"environment": EventPolicyStrict,
+	"pipeline":    EventPolicyAccept,
+	"change":      EventPolicyAccept,
+	"artifact":    EventPolicyAccept,
+	"incident":    EventPolicyAccept,
+}
+
+// strictEventTypes pins the event types a strict subject accepts to the
+// CDEvents v0.5 types ddash projects; other versions are rejected unless the
+// subject is set to accept.
+var strictEventTypes = map[string]map[string]struct{}{
+	"service": {
+		cdeventsv05.ServiceDeployedEventType.String():   {},
+		cdeventsv05.ServiceUpgradedEventType.String():   {},
+		cdeventsv05.ServiceRolledbackEventType.String(): {},
+		cdeventsv05.ServiceRemovedEventType.String():    {},
+		cdeventsv05.ServicePublishedEventType.String():  {},
+	},
+	"environment": {
+		cdeventsv05.EnvironmentCreatedEventType.String():  {},
+		cdeventsv05.EnvironmentModifiedEventType.String(): {},
+		cdeventsv05.EnvironmentDeletedEventType.String():  {},
+	},
+}
+
+// eventPolicy resolves the ingestion mode for event types of one organization.
+type eventPolicy map[string]string
+
+func newEventPolicy(overrides []ports.EventSubjectPolicy) eventPolicy {
+	policy := eventPolicy{}
+	for _, subject := range EventPolicySubjects {
+		policy[subject] = defaultEventPolicyMode(subject)
+	}
+	for _, override := range overrides {
+		subject := strings.ToLower(strings.TrimSpace(override.Subject))
+		mode := strings.ToLower(strings.TrimSpace(override.Mode))
+		if _, ok := policy[subject]; !ok || !isValidEventPolicyMode(mode) {
+			continue
+		}
+		policy[subject] = mode
+	}
+	return policy
+}
+
+func (p eventPolicy) mode(eventType string) string {
+	subject := eventTypeSubject(eventType)
+	if subject == "" {
+		return EventPolicyReject
+	}
+	mode, ok := p[subject]
+	if !ok {
+		return EventPolicyReject
+	}
+	if types, pinned := strictEventTypes[subject]; pinned && mode == EventPolicyStrict {
+		if _, ok := types[strings.TrimSpace(eventType)]; !ok {
+			return EventPolicyReject
+		}
+	}
+	return mode
+}
+
+func (p eventPolicy) subjects() []ports.EventSubjectPolicy {
+	out := make([]ports.EventSubjectPolicy, 0, len(EventPolicySubjects))
+	for _, subject := range EventPolicySubjects {
+		out = append(out, ports.EventSubjectPolicy{Subject: subject, Mode: p[subject]})
+	}
+	return out
+}
+
+// eventTypeSubject returns the CDEvents subject segment of an event type.
+func eventTypeSubject(eventType string) string {
+	eventType = strings.ToLower(strings.TrimSpace(eventType))
+	if !strings.HasPrefix(eventType, cdeventsTypePrefix) {
+		return ""
+	}
+	subject, _, _ := strings.Cut(strings.TrimPrefix(eventType, cdeventsTypePrefix), ".")
+	return subject
+}
+
+func defaultEventPolicyMode(subject string) string {
+	if mode, ok := defaultEventPolicyModes[subject]; ok {
+		return mode
+	}
+	return EventPolicyReject
+}
+
+func isValidEventPolicyMode(mode string) bool {
+	switch mode {
+	case EventPolicyAccept, EventPolicyStrict, EventPolicyDrop, EventPolicyReject:
+		return true
+	default:
+		return false
+	}
+}
+
+// normalizeEventPolicyInput keeps only known subjects with valid modes, last value wins.
+func normalizeEventPolicyInput(values []ports.EventSubjectPolicy) []ports.EventSubjectPolicy {
+	if len(values) == 0 {
+		return nil
+	}
+	return newEventPolicy(values).subjects()
+}
+
+func (s *EventIngestService) loadEventPolicy(ctx context.Context, organizationID int64) (eventPolicy, error) {
+	settings, err := s.loadIngestSettings(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	return settings.policy, nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+func TestEventPolicyModeDefaultsMatchBuiltInRules(t *testing.T) {
+	policy := newEventPolicy(nil)
+
+	cases := map[string]string{
+		"dev.cdevents.service.deployed.0.3.0":     EventPolicyStrict,
+		"dev.cdevents.environment.created.0.3.0":  EventPolicyStrict,
+		"dev.cdevents.service.deployed.0.2.0":     EventPolicyReject,
+		"dev.cdevents.environment.created.0.2.0":  EventPolicyReject,
+		"dev.cdevents.service.unknown.0.3.0":      EventPolicyReject,
+		"dev.cdevents.pipeline.run.started.0.1.0": EventPolicyAccept,
+		"dev.cdevents.change.merged.0.2.0":        EventPolicyAccept,
+		"dev.cdevents.incident.detected.0.1.0":    EventPolicyAccept,
+		"dev.cdevents.build.started.0.2.0":        EventPolicyReject,
+		"dev.cdevents.unknown.thing.0.1.0":        EventPolicyReject,
+		"com.example.custom":                      EventPolicyReject,
+		"":                                        EventPolicyReject,
+	}
+	for eventType, want := range cases {
+		if got := policy.mode(eventType); got != want {
+			t.Fatalf("mode(%q) = %q, want %q", eventType, got, want)
+		}
+	}
+}
+
+func TestEventPolicyOverridesKnownSubjectsOnly(t *testing.T) {
+	policy := newEventPolicy([]ports.EventSubjectPolicy{
+		{Subject: "Service", Mode: "accept"},
+		{Subject: "build", Mode: "strict"},
+		{Subject: "change", Mode: "invalid"},
+		{Subject: "custom", Mode: "accept"},
+	})
+
+	if got := policy.mode("dev.cdevents.service.deployed.0.2.0"); got != EventPolicyAccept {
+		t.Fatalf("expected service override, got %q", got)
+	}
+	if got := policy.mode("dev.cdevents.build.finished.0.2.0"); got != EventPolicyStrict {
+		t.Fatalf("expected build override, got %q", got)
+	}
+	if got := policy.mode("dev.cdevents.change.merged.0.2.0"); got != EventPolicyAccept {
+		t.Fatalf("expected invalid mode to keep default, got %q", got)
+	}
+	if got := policy.mode("dev.cdevents.custom.done.0.1.0"); got != EventPolicyReject {
+		t.Fatalf("expected unknown subject to stay rejected, got %q", got)
+	}
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+// defaultIngestSettingsTTL bounds how long cached settings survive updates
+// made outside this process, such as by another replica or an import.
+const defaultIngestSettingsTTL = 30 * time.Second
+
+// ingestSettings is the per-organization configuration every delivery reads.
+type ingestSettings struct {
+	policy          eventPolicy
+	signatureScheme string
+	credentials     []ports.IngestCredential
+}
+
+// IngestSettingsCache keeps the event policy, signature scheme and ingest
+// credentials of each organization between deliveries. The settings and
+// credential services invalidate an organization when they change it.
+type IngestSettingsCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[int64]ingestSettingsEntry
+	// generations change on every invalidation so a load that raced an
+	// update is not cached.
+	generations map[int64]uint64
+}
+
+type ingestSettingsEntry struct {
+	settings  ingestSettings
+	expiresAt time.Time
+}
+
+// NewIngestSettingsCache constructs a cache whose entries expire after ttl;
+// a non-positive ttl uses the default.
+func NewIngestSettingsCache(ttl time.Duration) *IngestSettingsCache {
+	if ttl <= 0 {
+		ttl = defaultIngestSettingsTTL
+	}
+	return &IngestSettingsCache{
+		ttl:         ttl,
+		now:         time.Now,
+		entries:     map[int64]ingestSettingsEntry{},
+		generations: map[int64]uint64{},
+	}
+}
+
+// Invalidate drops the cached settings of one organization.
+func (c *IngestSettingsCache) Invalidate(organizationID int64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, organizationID)
+	c.generations[organizationID]++
+}
+
+func (c *IngestSettingsCache) get(organizationID int64) (ingestSettings, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[organizationID]
+	if ok && c.now().Before(entry.expiresAt) {
+		return entry.settings, 0, true
+	}
+	delete(c.entries, organizationID)
+	return ingestSettings{}, c.generations[organizationID], false
+}
+
+func (c *IngestSettingsCache) put(organizationID int64, generation uint64, settings ingestSettings) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generations[organizationID] != generation {
+		return
+	}
+	c.entries[organizationID] = ingestSettingsEntry{settings: settings, expiresAt: c.now().Add(c.ttl)}
+}
+
+// loadIngestSettings returns the organization settings, from the cache when
+// one is configured and still holds them.
+func (s *EventIngestService) loadIngestSettings(ctx context.Context, organizationID int64) (ingestSettings, error) {
+	if s.settings == nil {
+		return s.readIngestSettings(ctx, organizationID)
+	}
+	settings, generation, ok := s.settings.get(organizationID)
+	if ok {
+		return settings, nil
+	}
+	settings, err := s.readIngestSettings(ctx, organizationID)
+	if err != nil {
+		return ingestSettings{}, err
+	}
+	s.settings.put(organizationID, generation, settings)
+	return settings, nil
+}
+
+func (s *EventIngestService) readIngestSettings(ctx context.Context, organizationID int64) (ingestSettings, error) {
+	store, err := s.storeFactory.Open()
+	if err != nil {
+		return ingestSettings{}, err
+	}
+	defer func() {
+		_ = store.Close()
+	}()
+
+	overrides, err := store.ListOrganizationEventPolicies(ctx, organizationID)
+	if err != nil {
+		return ingestSettings{}, err
+	}
+	scheme, err := store.GetOrganizationPreference(ctx, organizationID, prefWebhookSignatureScheme)
+	if err != nil {
+		return ingestSettings{}, err
+	}
+	credentials, err := store.ListIngestCredentials(ctx, organizationID)
+	if err != nil {
+		return ingestSettings{}, err
+	}
+	return ingestSettings{
+		policy:          newEventPolicy(overrides),
+		signatureScheme: normalizeSignatureScheme(scheme),
+		credentials:     credentials,
+	}, nil
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+type settingsIngestionStore struct {
+	ports.IngestionStore
+	policies []ports.EventSubjectPolicy
+	scheme   string
+	reads    int
+}
+
+func (s *settingsIngestionStore) Open() (ports.IngestionStore, error) { return s, nil }
+
+func (s *settingsIngestionStore) Close() error { return nil }
+
+func (s *settingsIngestionStore) ListOrganizationEventPolicies(context.Context, int64) ([]ports.EventSubjectPolicy, error) {
+	s.reads++
+	return s.policies, nil
+}
+
+func (s *settingsIngestionStore) GetOrganizationPreference(context.Context, int64, string) (string, error) {
+	return s.scheme, nil
+}
+
+func (s *settingsIngestionStore) ListIngestCredentials(context.Context, int64) ([]ports.IngestCredential, error) {
+	return nil, nil
+}
+
+func TestIngestSettingsCacheServesUntilInvalidated(t *testing.T) {
+	store := &settingsIngestionStore{}
+	cache := NewIngestSettingsCache(time.Minute)
+	service := NewEventIngestServiceWithConfig(store, IngestBatchConfig{Settings: cache})
+	ctx := context.Background()
+
+	for range 3 {
+		policy, err := service.loadEventPolicy(ctx, 1)
+		if err != nil {
+			t.Fatalf("load policy: %v", err)
+		}
+		if got := policy.mode("dev.cdevents.build.started.0.2.0"); got != EventPolicyReject {
+			t.Fatalf("expected default build mode, got %q", got)
+		}
+	}
+	if store.reads != 1 {
+		t.Fatalf("expected one settings read, got %d", store.reads)
+	}
+
+	store.policies = []ports.EventSubjectPolicy{{Subject: "build", Mode: EventPolicyAccept}}
+	store.scheme = SignatureSchemeTimestamped
+	cache.Invalidate(1)
+
+	policy, err := service.loadEventPolicy(ctx, 1)
+	if err != nil {
+		t.Fatalf("load policy: %v", err)
+	}
+	if got := policy.mode("dev.cdevents.build.started.0.2.0"); got != EventPolicyAccept {
+		t.Fatalf("expected updated build mode, got %q", got)
+	}
+	scheme, err := service.loadSignatureScheme(ctx, 1)
+	if err != nil || scheme != SignatureSchemeTimestamped {
+		t.Fatalf("expected updated scheme, got %q err=%v", scheme, err)
+	}
+	if store.reads != 2 {
+		t.Fatalf("expected a reload after invalidation, got %d reads", store.reads)
+	}
+
+	cache.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if _, err := service.loadEventPolicy(ctx, 1); err != nil {
+		t.Fatalf("load policy: %v", err)
+	}
+	if store.reads != 3 {
+		t.Fatalf("expected a reload after expiry, got %d reads", store.reads)
+	}
+}
+
+func TestIngestSettingsCacheSkipsLoadsThatRacedAnUpdate(t *testing.T) {
+	cache := NewIngestSettingsCache(time.Minute)
+	_, generation, ok := cache.get(1)
+	if ok {
+		t.Fatal("expected an empty cache")
+	}
+	cache.Invalidate(1)
+	cache.put(1, generation, ingestSettings{signatureScheme: SignatureSchemeBody})
+	if _, _, ok := cache.get(1); ok {
+		t.Fatal("expected a stale load not to be cached")
+	}
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+const (
+	// SignatureSchemeBody signs the raw request body only.
+	SignatureSchemeBody = "body"
+	// SignatureSchemeTimestamped signs a timestamp, optional nonce and the body,
+	// and rejects stale or already used signatures.
+	SignatureSchemeTimestamped = "timestamped"
+
+	defaultSignatureSkew = 5 * time.Minute
+)
+
+// verifySignature checks the request signature against the organization scheme.
+// Timestamped signatures are honoured under either scheme so senders can
+// migrate before the organization stops accepting body-only signatures.
+// The signature must be made with the secret paired with the bearer token: the
+// primary webhook secret for the primary token, or the secret of the ingest
+// credential the token belongs to.
+func (s *EventIngestService) verifySignature(ctx context.Context, org ports.Organization, token string, cmd IngestCommand) error {
+	secret, err := s.loadSigningSecret(ctx, org, token)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(cmd.TimestampHeader) != "" {
+		return s.verifyTimestampedSignature(ctx, org, secret, cmd)
+	}
+	scheme, err := s.loadSignatureScheme(ctx, org.ID)
+	if err != nil {
+		return err
+	}
+	if scheme == SignatureSchemeTimestamped {
+		return ErrInvalidSignature
+	}
+	if !validSignature(cmd.Body, secret.secret, cmd.SignatureHeader) {
+		return ErrInvalidSignature
+	}
+	s.touchSigningSecret(ctx, org.ID, secret)
+	return nil
+}
+
+func (s *EventIngestService) verifyTimestampedSignature(ctx context.Context, org ports.Organization, secret signingSecret, cmd IngestCommand) error {
+	timestamp := strings.TrimSpace(cmd.TimestampHeader)
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	signature := strings.ToLower(strings.TrimSpace(cmd.SignatureHeader))
+	payload := timestampedSignaturePayload(timestamp, strings.TrimSpace(cmd.NonceHeader), cmd.Body)
+	if !validSignature(payload, secret.secret, signature) {
+		return ErrInvalidSignature
+	}
+
+	now := s.now().UTC()
+	signedTime := time.Unix(signedAt, 0).UTC()
+	if drift := now.Sub(signedTime); drift > s.skew || drift < -s.skew {
+		return ErrStaleSignature
+	}
+
+	store, err := s.storeFactory.Open()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = store.Close()
+	}()
+	fresh, err := store.RememberWebhookSignature(ctx, org.ID, signature, now, signedTime.Add(s.skew))
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrReplayedSignature
+	}
+	s.touchSigningSecret(ctx, org.ID, secret)
+	return nil
+}
+
+// signingSecret is one webhook secret an organization accepts; credentialID is
+// zero for the primary organization secret.
+type signingSecret struct {
+	credentialID int64
+	secret       string
+}
+
+// loadSigningSecret returns the secret paired with the bearer token: the
+// primary secret for the primary token, otherwise the secret of the active
+// ingest credential issued with the token.
+func (s *EventIngestService) loadSigningSecret(ctx context.Context, org ports.Organization, token string) (signingSecret, error) {
+	if token == org.AuthToken {
+		return signingSecret{secret: org.WebhookSecret}, nil
+	}
+	settings, err := s.loadIngestSettings(ctx, org.ID)
+	if err != nil {
+		return signingSecret{}, err
+	}
+	now := s.now().UTC()
+	for _, credential := range settings.credentials {
+		if credential.AuthToken == token && credential.Active(now) {
+			return signingSecret{credentialID: credential.ID, secret: credential.WebhookSecret}, nil
+		}
+	}
+	return signingSecret{}, ErrInvalidAuthToken
+}
+
+// touchSigningSecret records credential use; failures are logged and never
+// change the ingestion result.
+func (s *EventIngestService) touchSigningSecret(ctx context.Context, organizationID int64, matched signingSecret) {
+	if matched.credentialID == 0 {
+		return
+	}
+	store, err := s.storeFactory.Open()
+	if err != nil {
+		slog.WarnContext(ctx, "ingest_credential_touch_failed", "error", err, "organization_id", organizationID)
+		return
+	}
+	defer func() {
+		_ = store.Close()
+	}()
+	if err := store.TouchIngestCredential(ctx, organizationID, matched.credentialID, s.now().UTC()); err != nil {
+		slog.WarnContext(ctx, "ingest_credential_touch_failed", "error", err, "organization_id", organizationID, "credential_id", matched.credentialID)
+	}
+}
+
+func (s *EventIngestService) loadSignatureScheme(ctx context.Context, organizationID int64) (string, error) {
+	settings, err := s.loadIngestSettings(ctx, organizationID)
+	if err != nil {
+		return "", err
+	}
+	return settings.signatureScheme, nil
+}
+
+// timestampedSignaturePayload is the message signed under the timestamped scheme.
+func timestampedSignaturePayload(timestamp, nonce string, body []byte) []byte {
+	payload := make([]byte, 0, len(timestamp)+len(nonce)+len(body)+2)
+	payload = append(payload, timestamp...)
+	payload = append(payload, '.')
+	payload = append(payload, nonce...)
+	payload = append(payload, '.')
+	return append(payload, body...)
+}
+
+func normalizeSignatureScheme(value string) string {
+	if strings.ToLower(strings.TrimSpace(value)) == SignatureSchemeTimestamped {
+		return SignatureSchemeTimestamped
+	}
+	return SignatureSchemeBody
+}
@@ -109,6 +109,10 @@ func (f *metadataStoreFake) ListOrganizationPreferences(context.Context, int64)
 	return nil, nil
 }
 
+func (f *metadataStoreFake) ListOrganizationEventPolicies(context.Context, int64) ([]ports.EventSubjectPolicy, error) {
+	return nil, nil
+}
+
 func (f *metadataStoreFake) ListDistinctServiceEnvironmentsFromEvents(context.Context, int64) ([]string, error) {
 	return nil, nil
 }
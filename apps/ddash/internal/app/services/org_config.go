@@ -27,13 +27,16 @@ const (
 	featureShowServiceDeliveryMetrics  = "show_service_delivery_metrics"
 
 	prefDeploymentRetentionDays = "deployment_retention_days"
+	prefEventRetentionDays      = "event_retention_days"
 	prefDefaultDashboardView    = "default_dashboard_view"
 	prefStatusSemanticsMode     = "status_semantics_mode"
+	prefWebhookSignatureScheme  = "webhook_signature_scheme"
 )
 
 // OrganizationConfigService provides org-level settings read/write operations.
 type OrganizationConfigService struct {
-	store ports.AppStore
+	store          ports.AppStore
+	ingestSettings *IngestSettingsCache
 }
 
 // NewOrganizationConfigService constructs organization config service.
@@ -41,6 +44,12 @@ func NewOrganizationConfigService(store ports.AppStore) *OrganizationConfigServi
 	return &OrganizationConfigService{store: store}
 }
 
+// NewOrganizationConfigServiceWithCache constructs organization config service
+// that invalidates cached ingestion settings on update.
+func NewOrganizationConfigServiceWithCache(store ports.AppStore, ingestSettings *IngestSettingsCache) *OrganizationConfigService {
+	return &OrganizationConfigService{store: store, ingestSettings: ingestSettings}
+}
+
 // OrganizationSettings contains values rendered on settings page.
 type OrganizationSettings struct {
 	AuthToken                   string
@@ -61,10 +70,13 @@ type OrganizationSettings struct {
 	ShowServiceDependencies     bool
 	ShowServiceDeliveryMetrics  bool
 	DeploymentRetentionDays     int
+	EventRetentionDays          int
 	DefaultDashboardView        string
 	StatusSemanticsMode         string
+	WebhookSignatureScheme      string
 	RequiredFields              []domain.MetadataField
 	EnvironmentOrder            []string
+	EventPolicies               []ports.EventSubjectPolicy
 }
 
 // RequiredFieldInput is one required metadata field definition.
@@ -94,10 +106,13 @@ type OrganizationSettingsUpdate struct {
 	ShowServiceDependencies     bool
 	ShowServiceDeliveryMetrics  bool
 	DeploymentRetentionDays     int
+	EventRetentionDays          int
 	DefaultDashboardView        string
 	StatusSemanticsMode         string
+	WebhookSignatureScheme      string
 	RequiredFields              []RequiredFieldInput
 	EnvironmentOrder            []string
+	EventPolicies               []ports.EventSubjectPolicy
 }
 
 // GetSettings returns organization settings view model.
@@ -148,13 +163,20 @@ func (s *OrganizationConfigService) GetSettings(ctx context.Context, organizatio
 		}
 	}
 
+	eventPolicies, err := s.store.ListOrganizationEventPolicies(ctx, org.ID)
+	if err != nil {
+		return OrganizationSettings{}, err
+	}
+
 	prefs, err := s.store.ListOrganizationPreferences(ctx, org.ID)
 	if err != nil {
 		return OrganizationSettings{}, err
 	}
 	deploymentRetentionDays := 30
+	eventRetentionDays := 0
 	defaultDashboardView := "grid"
 	statusSemanticsMode := "technical"
+	webhookSignatureScheme := SignatureSchemeBody
 	for _, preference := range prefs {
 		key := strings.ToLower(strings.TrimSpace(preference.Key))
 		value := strings.TrimSpace(preference.Value)
@@ -163,6 +185,10 @@ func (s *OrganizationConfigService) GetSettings(ctx context.Context, organizatio
 			if parsed, convErr := strconv.Atoi(value); convErr == nil && parsed > 0 {
 				deploymentRetentionDays = parsed
 			}
+		case prefEventRetentionDays:
+			if parsed, convErr := strconv.Atoi(value); convErr == nil && parsed > 0 {
+				eventRetentionDays = parsed
+			}
 		case prefDefaultDashboardView:
 			if value == "table" || value == "grid" {
 				defaultDashboardView = value
@@ -171,6 +197,8 @@ func (s *OrganizationConfigService) GetSettings(ctx context.Context, organizatio
 			if value == "plain" || value == "technical" {
 				statusSemanticsMode = value
 			}
+		case prefWebhookSignatureScheme:
+			webhookSignatureScheme = normalizeSignatureScheme(value)
 		}
 	}
 
@@ -193,10 +221,13 @@ func (s *OrganizationConfigService) GetSettings(ctx context.Context, organizatio
 		ShowServiceDependencies:     featureFlags[featureShowServiceDependencies],
 		ShowServiceDeliveryMetrics:  featureFlags[featureShowServiceDeliveryMetrics],
 		DeploymentRetentionDays:     deploymentRetentionDays,
+		EventRetentionDays:          eventRetentionDays,
 		DefaultDashboardView:        defaultDashboardView,
 		StatusSemanticsMode:         statusSemanticsMode,
+		WebhookSignatureScheme:      webhookSignatureScheme,
 		RequiredFields:              normalizeSettingsFields(fields),
 		EnvironmentOrder:            mergeEnvironmentOrder(normalizeEnvironmentOrder(envPriorities), normalizeEnvironmentOrderInput(discoveredEnvs)),
+		EventPolicies:               newEventPolicy(eventPolicies).subjects(),
 	}, nil
 }
 
@@ -220,6 +251,7 @@ func (s *OrganizationConfigService) UpdateSettings(ctx context.Context, organiza
 		})
 	}
 
+	defer s.ingestSettings.Invalidate(organizationID)
 	return s.store.UpdateOrganizationSettings(ctx, organizationID, ports.OrganizationSettingsUpdate{
 		AuthToken:                   update.AuthToken,
 		WebhookSecret:               update.WebhookSecret,
@@ -239,10 +271,13 @@ func (s *OrganizationConfigService) UpdateSettings(ctx context.Context, organiza
 		ShowServiceDeliveryMetrics:  update.ShowServiceDeliveryMetrics,
 		ShowServiceDependencies:     update.ShowServiceDependencies,
 		DeploymentRetentionDays:     update.DeploymentRetentionDays,
+		EventRetentionDays:          max(update.EventRetentionDays, 0),
 		DefaultDashboardView:        update.DefaultDashboardView,
 		StatusSemanticsMode:         update.StatusSemanticsMode,
+		WebhookSignatureScheme:      normalizeSignatureScheme(update.WebhookSignatureScheme),
 		RequiredFields:              requiredFields,
 		EnvironmentOrder:            update.EnvironmentOrder,
+		EventPolicies:               normalizeEventPolicyInput(update.EventPolicies),
 	})
 }
 
@@ -11,6 +11,7 @@ type orgConfigStoreFake struct {
 	org          ports.Organization
 	features     []ports.OrganizationFeature
 	prefs        []ports.OrganizationPreference
+	policies     []ports.EventSubjectPolicy
 	updateParams ports.OrganizationSettingsUpdate
 }
 
@@ -110,6 +111,10 @@ func (f *orgConfigStoreFake) ListOrganizationPreferences(context.Context, int64)
 	return f.prefs, nil
 }
 
+func (f *orgConfigStoreFake) ListOrganizationEventPolicies(context.Context, int64) ([]ports.EventSubjectPolicy, error) {
+	return f.policies, nil
+}
+
 func (f *orgConfigStoreFake) ListDistinctServiceEnvironmentsFromEvents(context.Context, int64) ([]string, error) {
 	return nil, nil
 }
@@ -137,6 +142,7 @@ func TestOrganizationConfigGetSettingsReadsFeaturesAndPreferences(t *testing.T)
 		},
 		prefs: []ports.OrganizationPreference{
 			{Key: "deployment_retention_days", Value: "14"},
+			{Key: "event_retention_days", Value: "180"},
 			{Key: "default_dashboard_view", Value: "table"},
 			{Key: "status_semantics_mode", Value: "plain"},
 		},
@@ -154,6 +160,9 @@ func TestOrganizationConfigGetSettingsReadsFeaturesAndPreferences(t *testing.T)
 	if settings.DeploymentRetentionDays != 14 || settings.DefaultDashboardView != "table" || settings.StatusSemanticsMode != "plain" {
 		t.Fatalf("unexpected preferences: days=%d view=%s mode=%s", settings.DeploymentRetentionDays, settings.DefaultDashboardView, settings.StatusSemanticsMode)
 	}
+	if settings.EventRetentionDays != 180 {
+		t.Fatalf("unexpected event retention days: %d", settings.EventRetentionDays)
+	}
 }
 
 func TestOrganizationConfigUpdateSettingsForwardsFeatureFields(t *testing.T) {
@@ -178,6 +187,7 @@ func TestOrganizationConfigUpdateSettingsForwardsFeatureFields(t *testing.T) {
 		ShowServiceDetailInsights:   false,
 		ShowServiceDependencies:     false,
 		DeploymentRetentionDays:     7,
+		EventRetentionDays:          -1,
 		DefaultDashboardView:        "table",
 		StatusSemanticsMode:         "plain",
 	})
@@ -188,7 +198,47 @@ func TestOrganizationConfigUpdateSettingsForwardsFeatureFields(t *testing.T) {
 	if store.updateParams.ShowSyncStatus || store.updateParams.ShowMetadataBadges || store.updateParams.ShowEnvironmentColumn || store.updateParams.EnableSSELiveUpdates {
 		t.Fatalf("expected forwarded disabled flags, got %+v", store.updateParams)
 	}
-	if store.updateParams.DeploymentRetentionDays != 7 || store.updateParams.DefaultDashboardView != "table" || store.updateParams.StatusSemanticsMode != "plain" {
+	if store.updateParams.DeploymentRetentionDays != 7 || store.updateParams.EventRetentionDays != 0 || store.updateParams.DefaultDashboardView != "table" || store.updateParams.StatusSemanticsMode != "plain" {
 		t.Fatalf("unexpected forwarded preferences: %+v", store.updateParams)
 	}
 }
+
+func TestOrganizationConfigEventPoliciesDefaultAndOverride(t *testing.T) {
+	store := &orgConfigStoreFake{
+		org:      ports.Organization{ID: 10, Enabled: true},
+		policies: []ports.EventSubjectPolicy{{Subject: "pipeline", Mode: "drop"}, {Subject: "unknown", Mode: "accept"}},
+	}
+	svc := NewOrganizationConfigService(store)
+
+	settings, err := svc.GetSettings(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("GetSettings error: %v", err)
+	}
+	modes := map[string]string{}
+	for _, policy := range settings.EventPolicies {
+		modes[policy.Subject] = policy.Mode
+	}
+	if len(settings.EventPolicies) != len(EventPolicySubjects) {
+		t.Fatalf("expected one policy per known subject, got %+v", settings.EventPolicies)
+	}
+	if modes["service"] != EventPolicyStrict || modes["pipeline"] != EventPolicyDrop || modes["change"] != EventPolicyAccept || modes["build"] != EventPolicyReject {
+		t.Fatalf("unexpected effective policies: %+v", modes)
+	}
+	if _, ok := modes["unknown"]; ok {
+		t.Fatalf("expected unknown subject to be ignored, got %+v", modes)
+	}
+
+	err = svc.UpdateSettings(context.Background(), 10, OrganizationSettingsUpdate{
+		EventPolicies: []ports.EventSubjectPolicy{{Subject: " Incident ", Mode: "STRICT"}, {Subject: "change", Mode: "bogus"}},
+	})
+	if err != nil {
+		t.Fatalf("UpdateSettings error: %v", err)
+	}
+	forwarded := map[string]string{}
+	for _, policy := range store.updateParams.EventPolicies {
+		forwarded[policy.Subject] = policy.Mode
+	}
+	if forwarded["incident"] != EventPolicyStrict || forwarded["change"] != EventPolicyAccept {
+		t.Fatalf("unexpected forwarded policies: %+v", store.updateParams.EventPolicies)
+	}
+}
@@ -126,6 +126,10 @@ func (f *fakeOrgStore) ListOrganizationPreferences(context.Context, int64) ([]po
 	return nil, nil
 }
 
+func (f *fakeOrgStore) ListOrganizationEventPolicies(context.Context, int64) ([]ports.EventSubjectPolicy, error) {
+	return nil, nil
+}
+
 func (f *fakeOrgStore) ListDistinctServiceEnvironmentsFromEvents(context.Context, int64) ([]string, error) {
 	return nil, nil
 }
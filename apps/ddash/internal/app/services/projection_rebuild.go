@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+// ErrProjectionRebuildRunning indicates the organization is already being rebuilt.
+var ErrProjectionRebuildRunning = errors.New("projection rebuild already running")
+
+// ProjectionRebuildService rebuilds organization projections in the
+// background, one rebuild per organization at a time. Dashboards keep reading
+// the current projections until the rebuilt rows are swapped in.
+type ProjectionRebuildService struct {
+	store   ports.ProjectionRebuildStore
+	mu      sync.Mutex
+	running map[int64]struct{}
+	pending map[int64]struct{}
+}
+
+// NewProjectionRebuildService constructs a projection rebuild service.
+func NewProjectionRebuildService(store ports.ProjectionRebuildStore) *ProjectionRebuildService {
+	return &ProjectionRebuildService{store: store, running: map[int64]struct{}{}, pending: map[int64]struct{}{}}
+}
+
+// Start schedules a rebuild of one organization and returns without waiting
+// for it. Progress and the outcome are logged.
+func (s *ProjectionRebuildService) Start(ctx context.Context, organizationID int64) error {
+	s.mu.Lock()
+	if _, ok := s.running[organizationID]; ok {
+		s.mu.Unlock()
+		return ErrProjectionRebuildRunning
+	}
+	s.running[organizationID] = struct{}{}
+	s.mu.Unlock()
+	s.launch(ctx, organizationID)
+	return nil
+}
+
+// Schedule starts a rebuild of one organization, or queues another one to
+// run after the rebuild in progress, which may have read stale inputs.
+func (s *ProjectionRebuildService) Schedule(ctx context.Context, organizationID int64) {
+	s.mu.Lock()
+	if _, ok := s.running[organizationID]; ok {
+		s.pending[organizationID] = struct{}{}
+		s.mu.Unlock()
+		return
+	}
+	s.running[organizationID] = struct{}{}
+	s.mu.Unlock()
+	s.launch(ctx, organizationID)
+}
+
+func (s *ProjectionRebuildService) launch(ctx context.Context, organizationID int64) {
+	// The rebuild outlives the request that started it.
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		for {
+			s.run(ctx, organizationID)
+
+			s.mu.Lock()
+			if _, ok := s.pending[organizationID]; !ok {
+				delete(s.running, organizationID)
+				s.mu.Unlock()
+				return
+			}
+			delete(s.pending, organizationID)
+			s.mu.Unlock()
+		}
+	}()
+}
+
+// Running reports whether a rebuild of the organization is in progress.
+func (s *ProjectionRebuildService) Running(organizationID int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.running[organizationID]
+	return ok
+}
+
+func (s *ProjectionRebuildService) run(ctx context.Context, organizationID int64) {
+	started := time.Now()
+	err := s.store.RebuildOrganizationProjections(ctx, organizationID, func(step string, index, total int) {
+		slog.InfoContext(ctx, "projection_rebuild_progress", "organization_id", organizationID, "step", step, "index", index, "total", total)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "projection_rebuild_failed", "error", err, "organization_id", organizationID)
+		return
+	}
+	slog.InfoContext(ctx, "projection_rebuild_completed", "organization_id", organizationID, "duration_ms", time.Since(started).Milliseconds())
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type blockingProjectionStore struct {
+	started chan int64
+	release chan struct{}
+}
+
+func (s *blockingProjectionStore) RebuildOrganizationProjections(_ context.Context, organizationID int64, progress func(step string, index, total int)) error {
+	s.started <- organizationID
+	progress("swap", 1, 1)
+	<-s.release
+	return nil
+}
+
+func TestProjectionRebuildServiceRunsOneRebuildPerOrganization(t *testing.T) {
+	store := &blockingProjectionStore{started: make(chan int64, 2), release: make(chan struct{})}
+	service := NewProjectionRebuildService(store)
+
+	if err := service.Start(context.Background(), 7); err != nil {
+		t.Fatalf("start rebuild: %v", err)
+	}
+	if got := <-store.started; got != 7 {
+		t.Fatalf("unexpected organization rebuilt: %d", got)
+	}
+	if !service.Running(7) || service.Running(8) {
+		t.Fatalf("expected only organization 7 to be running")
+	}
+	if err := service.Start(context.Background(), 7); !errors.Is(err, ErrProjectionRebuildRunning) {
+		t.Fatalf("expected running error, got %v", err)
+	}
+	if err := service.Start(context.Background(), 8); err != nil {
+		t.Fatalf("start second organization: %v", err)
+	}
+	<-store.started
+
+	close(store.release)
+	deadline := time.Now().Add(time.Second)
+	for service.Running(7) || service.Running(8) {
+		if time.Now().After(deadline) {
+			t.Fatalf("rebuilds did not finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := service.Start(context.Background(), 7); err != nil {
+		t.Fatalf("restart after completion: %v", err)
+	}
+	<-store.started
+}
+
+func TestProjectionRebuildServiceScheduleRerunsOnce(t *testing.T) {
+	store := &blockingProjectionStore{started: make(chan int64, 3), release: make(chan struct{})}
+	service := NewProjectionRebuildService(store)
+
+	service.Schedule(context.Background(), 7)
+	<-store.started
+	// Both land while the first rebuild runs and fold into one rerun.
+	service.Schedule(context.Background(), 7)
+	service.Schedule(context.Background(), 7)
+
+	close(store.release)
+	<-store.started
+	deadline := time.Now().Add(time.Second)
+	for service.Running(7) {
+		if time.Now().After(deadline) {
+			t.Fatalf("rebuild did not finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if len(store.started) != 0 {
+		t.Fatalf("expected exactly one rerun, got %d more", len(store.started))
+	}
+}
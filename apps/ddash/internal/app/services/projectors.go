@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+const (
+	defaultProjectorInterval  = 5 * time.Second
+	defaultProjectorBatchSize = 500
+)
+
+// ProjectorService reads projector checkpoints for the admin page.
+type ProjectorService struct {
+	store ports.ProjectorStore
+}
+
+// NewProjectorService constructs a projector status service.
+func NewProjectorService(store ports.ProjectorStore) *ProjectorService {
+	return &ProjectorService{store: store}
+}
+
+// List returns every projector with its checkpoint and lag.
+func (s *ProjectorService) List(ctx context.Context) ([]ports.ProjectorStatus, error) {
+	return s.store.ListProjectorStatus(ctx)
+}
+
+// ProjectorRunnerOptions tunes the background projector catch-up.
+type ProjectorRunnerOptions struct {
+	// Interval between checks once every projector is caught up.
+	Interval time.Duration
+	// BatchSize caps the events applied to one projector per transaction.
+	BatchSize int64
+}
+
+// ProjectorRunner catches lagging projectors up in the background, one batch
+// per transaction so appends are not blocked for long, and reports lag per
+// projector as a metric.
+type ProjectorRunner struct {
+	store   ports.ProjectorStore
+	options ProjectorRunnerOptions
+
+	mu       sync.Mutex
+	statuses []ports.ProjectorStatus
+
+	registration metric.Registration
+	stop         chan struct{}
+	done         chan struct{}
+	closeOnce    sync.Once
+}
+
+// StartProjectorRunner starts catching projectors up until Close is called.
+func StartProjectorRunner(store ports.ProjectorStore, options ProjectorRunnerOptions) *ProjectorRunner {
+	if options.Interval <= 0 {
+		options.Interval = defaultProjectorInterval
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultProjectorBatchSize
+	}
+	r := &ProjectorRunner{
+		store:   store,
+		options: options,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	r.registration = r.registerMetrics()
+	go r.run()
+	return r
+}
+
+// Close stops the runner and waits for the current batch to finish.
+func (r *ProjectorRunner) Close() {
+	r.closeOnce.Do(func() {
+		close(r.stop)
+		<-r.done
+		if r.registration != nil {
+			_ = r.registration.Unregister()
+		}
+	})
+}
+
+func (r *ProjectorRunner) run() {
+	defer close(r.done)
+	ctx := context.Background()
+	ticker := time.NewTicker(r.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		lagging, err := r.store.CatchUpProjectors(ctx, r.options.BatchSize)
+		if err != nil {
+			slog.ErrorContext(ctx, "projector_catch_up_failed", "error", err)
+		}
+		r.refresh(ctx)
+		if lagging && err == nil {
+			select {
+			case <-r.stop:
+				return
+			default:
+			}
+			continue
+		}
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *ProjectorRunner) refresh(ctx context.Context) {
+	statuses, err := r.store.ListProjectorStatus(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "projector_status_failed", "error", err)
+		return
+	}
+	r.mu.Lock()
+	r.statuses = statuses
+	r.mu.Unlock()
+}
+
+func (r *ProjectorRunner) registerMetrics() metric.Registration {
+	meter := otel.Meter("github.com/fr0stylo/ddash/apps/ddash/internal/app/services")
+	lag, _ := meter.Int64ObservableGauge("ddash.projections.lag")
+	registration, _ := meter.RegisterCallback(func(_ context.Context, observer metric.Observer) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for _, status := range r.statuses {
+			observer.ObserveInt64(lag, status.Lag(), metric.WithAttributes(attribute.String("projector", status.Name)))
+		}
+		return nil
+	}, lag)
+	return registration
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+type laggingProjectorStore struct {
+	mu        sync.Mutex
+	remaining int
+	calls     int
+}
+
+func (s *laggingProjectorStore) ListProjectorStatus(context.Context) ([]ports.ProjectorStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return []ports.ProjectorStatus{{Name: "recording", LastSeq: 10 - int64(s.remaining), HeadSeq: 10}}, nil
+}
+
+func (s *laggingProjectorStore) CatchUpProjectors(context.Context, int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.remaining > 0 {
+		s.remaining--
+	}
+	return s.remaining > 0, nil
+}
+
+func TestProjectorRunnerCatchesUpWithoutWaitingForInterval(t *testing.T) {
+	store := &laggingProjectorStore{remaining: 3}
+	runner := StartProjectorRunner(store, ProjectorRunnerOptions{Interval: time.Hour})
+	defer runner.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		store.mu.Lock()
+		calls := store.calls
+		store.mu.Unlock()
+		if calls >= 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("runner stopped catching up after %d batches", calls)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	statuses, err := NewProjectorService(store).List(context.Background())
+	if err != nil {
+		t.Fatalf("list projectors: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].Lag() != 0 {
+		t.Fatalf("expected caught-up projector: %+v", statuses)
+	}
+}
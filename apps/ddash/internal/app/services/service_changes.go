@@ -0,0 +1,103 @@
+package services
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+const serviceChangeSubscriberBuffer = 64
+
+// ServiceChangeHub fans out committed service changes to in-process subscribers.
+type ServiceChangeHub struct {
+	mu          sync.RWMutex
+	subscribers map[int64]map[*serviceChangeSubscription]struct{}
+}
+
+type serviceChangeSubscription struct {
+	changes chan ports.ServiceChange
+}
+
+// NewServiceChangeHub constructs an empty service change hub.
+func NewServiceChangeHub() *ServiceChangeHub {
+	return &ServiceChangeHub{subscribers: map[int64]map[*serviceChangeSubscription]struct{}{}}
+}
+
+// Subscribe registers a listener for one organization's service changes.
+// Delivery is best-effort: when a subscriber falls behind, changes are dropped
+// and the subscriber is expected to catch up from the event store.
+func (h *ServiceChangeHub) Subscribe(organizationID int64) (<-chan ports.ServiceChange, func()) {
+	if h == nil {
+		return nil, func() {}
+	}
+	sub := &serviceChangeSubscription{changes: make(chan ports.ServiceChange, serviceChangeSubscriberBuffer)}
+
+	h.mu.Lock()
+	orgSubscribers, ok := h.subscribers[organizationID]
+	if !ok {
+		orgSubscribers = map[*serviceChangeSubscription]struct{}{}
+		h.subscribers[organizationID] = orgSubscribers
+	}
+	orgSubscribers[sub] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return sub.changes, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			delete(h.subscribers[organizationID], sub)
+			if len(h.subscribers[organizationID]) == 0 {
+				delete(h.subscribers, organizationID)
+			}
+		})
+	}
+}
+
+// PublishServiceChanges delivers changes to subscribers of each organization.
+func (h *ServiceChangeHub) PublishServiceChanges(changes []ports.ServiceChange) {
+	if h == nil || len(changes) == 0 {
+		return
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, change := range changes {
+		for sub := range h.subscribers[change.OrganizationID] {
+			select {
+			case sub.changes <- change:
+			default:
+			}
+		}
+	}
+}
+
+// PublishAppended publishes service changes for events committed outside the
+// ingestion service, such as those drained from the ingest spool.
+func (h *ServiceChangeHub) PublishAppended(appended []ports.AppendedEvent) {
+	publishServiceChanges(h, appended)
+}
+
+// serviceChangesFromAppended collapses appended events into one change per service.
+func serviceChangesFromAppended(appended []ports.AppendedEvent) []ports.ServiceChange {
+	changes := make([]ports.ServiceChange, 0, len(appended))
+	index := map[ports.ServiceChange]int{}
+	for _, event := range appended {
+		name := strings.TrimSpace(event.ServiceName)
+		if name == "" {
+			continue
+		}
+		key := ports.ServiceChange{OrganizationID: event.OrganizationID, ServiceName: name}
+		if position, ok := index[key]; ok {
+			if event.Seq > changes[position].Seq {
+				changes[position].Seq = event.Seq
+			}
+			continue
+		}
+		index[key] = len(changes)
+		changes = append(changes, ports.ServiceChange{OrganizationID: event.OrganizationID, ServiceName: name, Seq: event.Seq})
+	}
+	return changes
+}
+
+var _ ports.ServiceChangePublisher = (*ServiceChangeHub)(nil)
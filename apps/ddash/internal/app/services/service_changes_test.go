@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+func TestServiceChangeHubDeliversPerOrganization(t *testing.T) {
+	t.Parallel()
+
+	hub := NewServiceChangeHub()
+	orgA, unsubscribeA := hub.Subscribe(1)
+	orgB, unsubscribeB := hub.Subscribe(2)
+	defer unsubscribeB()
+
+	hub.PublishServiceChanges([]ports.ServiceChange{{OrganizationID: 1, ServiceName: "orders", Seq: 3}})
+
+	select {
+	case change := <-orgA:
+		if change.ServiceName != "orders" || change.Seq != 3 {
+			t.Fatalf("unexpected change: %+v", change)
+		}
+	default:
+		t.Fatal("expected change for subscribed organization")
+	}
+	select {
+	case change := <-orgB:
+		t.Fatalf("unexpected cross-organization change: %+v", change)
+	default:
+	}
+
+	unsubscribeA()
+	unsubscribeA()
+	hub.PublishServiceChanges([]ports.ServiceChange{{OrganizationID: 1, ServiceName: "orders", Seq: 4}})
+	select {
+	case change := <-orgA:
+		t.Fatalf("unexpected change after unsubscribe: %+v", change)
+	default:
+	}
+}
+
+func TestServiceChangesFromAppendedCollapsesPerService(t *testing.T) {
+	t.Parallel()
+
+	changes := serviceChangesFromAppended([]ports.AppendedEvent{
+		{OrganizationID: 1, Seq: 10, SubjectType: "service", ServiceName: "orders"},
+		{OrganizationID: 1, Seq: 11, SubjectType: "pipeline"},
+		{OrganizationID: 1, Seq: 12, SubjectType: "service", ServiceName: "orders"},
+		{OrganizationID: 2, Seq: 13, SubjectType: "service", ServiceName: "orders"},
+	})
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	if changes[0].OrganizationID != 1 || changes[0].Seq != 12 {
+		t.Fatalf("expected latest seq for org 1, got %+v", changes[0])
+	}
+	if changes[1].OrganizationID != 2 || changes[1].Seq != 13 {
+		t.Fatalf("unexpected org 2 change: %+v", changes[1])
+	}
+}
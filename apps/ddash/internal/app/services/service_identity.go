@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+	"github.com/fr0stylo/ddash/internal/serviceidentity"
+)
+
+const (
+	// ServiceIdentityKindAlias maps one exact service name onto another.
+	ServiceIdentityKindAlias = serviceidentity.KindAlias
+	// ServiceIdentityKindRegex rewrites every service name a pattern matches.
+	ServiceIdentityKindRegex = serviceidentity.KindRegex
+)
+
+var (
+	// ErrServiceIdentityRuleInvalid indicates a rule that cannot be applied.
+	ErrServiceIdentityRuleInvalid = serviceidentity.ErrInvalidRule
+	// ErrServiceIdentityRuleExists indicates the organization already has a rule for the pattern.
+	ErrServiceIdentityRuleExists = errors.New("service identity rule already exists")
+	// ErrServiceIdentityRuleNotFound indicates the rule does not exist.
+	ErrServiceIdentityRuleNotFound = errors.New("service identity rule not found")
+)
+
+// ServiceIdentityService manages the rules that map the service names found
+// in events onto the services shown on dashboards, for renames, aliases and
+// merges.
+type ServiceIdentityService struct {
+	store ports.ServiceIdentityStore
+}
+
+// NewServiceIdentityService constructs a service identity service.
+func NewServiceIdentityService(store ports.ServiceIdentityStore) *ServiceIdentityService {
+	return &ServiceIdentityService{store: store}
+}
+
+// List returns the organization's rules in the order they apply.
+func (s *ServiceIdentityService) List(ctx context.Context, organizationID int64) ([]ports.ServiceIdentityRule, error) {
+	return s.store.ListServiceIdentityRules(ctx, organizationID)
+}
+
+// Aliases returns the derived service names the rules currently map.
+func (s *ServiceIdentityService) Aliases(ctx context.Context, organizationID int64) ([]ports.ServiceIdentityAlias, error) {
+	return s.store.ListServiceIdentityAliases(ctx, organizationID)
+}
+
+// Create validates and stores a rule. Callers rebuild projections afterwards
+// so history is regrouped under the new names.
+func (s *ServiceIdentityService) Create(ctx context.Context, organizationID int64, kind, pattern, serviceName string) (ports.ServiceIdentityRule, error) {
+	rule := ports.ServiceIdentityRule{
+		Kind:        strings.TrimSpace(kind),
+		Pattern:     strings.TrimSpace(pattern),
+		ServiceName: strings.TrimSpace(serviceName),
+	}
+	if err := serviceidentity.Validate(serviceidentity.Rule{Kind: rule.Kind, Pattern: rule.Pattern, ServiceName: rule.ServiceName}); err != nil {
+		return ports.ServiceIdentityRule{}, err
+	}
+
+	existing, err := s.store.ListServiceIdentityRules(ctx, organizationID)
+	if err != nil {
+		return ports.ServiceIdentityRule{}, err
+	}
+	for _, item := range existing {
+		if item.Kind == rule.Kind && item.Pattern == rule.Pattern {
+			return ports.ServiceIdentityRule{}, ErrServiceIdentityRuleExists
+		}
+	}
+	return s.store.CreateServiceIdentityRule(ctx, organizationID, rule)
+}
+
+// Delete removes a rule. Metadata already carried over to a merged service
+// stays with it.
+func (s *ServiceIdentityService) Delete(ctx context.Context, organizationID, id int64) error {
+	deleted, err := s.store.DeleteServiceIdentityRule(ctx, organizationID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrServiceIdentityRuleNotFound
+	}
+	return nil
+}
+
+// Resolve returns the service a derived name maps to, or the name itself.
+func (s *ServiceIdentityService) Resolve(ctx context.Context, organizationID int64, name string) (string, error) {
+	return s.store.ResolveServiceName(ctx, organizationID, strings.TrimSpace(name))
+}
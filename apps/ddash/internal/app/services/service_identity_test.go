@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+type memoryServiceIdentityStore struct {
+	rules []ports.ServiceIdentityRule
+}
+
+func (s *memoryServiceIdentityStore) ListServiceIdentityRules(context.Context, int64) ([]ports.ServiceIdentityRule, error) {
+	return s.rules, nil
+}
+
+func (s *memoryServiceIdentityStore) CreateServiceIdentityRule(_ context.Context, _ int64, rule ports.ServiceIdentityRule) (ports.ServiceIdentityRule, error) {
+	rule.ID = int64(len(s.rules) + 1)
+	s.rules = append(s.rules, rule)
+	return rule, nil
+}
+
+func (s *memoryServiceIdentityStore) DeleteServiceIdentityRule(_ context.Context, _ int64, id int64) (bool, error) {
+	for i, rule := range s.rules {
+		if rule.ID == id {
+			s.rules = append(s.rules[:i], s.rules[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *memoryServiceIdentityStore) ListServiceIdentityAliases(context.Context, int64) ([]ports.ServiceIdentityAlias, error) {
+	return nil, nil
+}
+
+func (s *memoryServiceIdentityStore) ResolveServiceName(_ context.Context, _ int64, name string) (string, error) {
+	return name, nil
+}
+
+func TestServiceIdentityServiceValidatesRules(t *testing.T) {
+	service := NewServiceIdentityService(&memoryServiceIdentityStore{})
+	ctx := context.Background()
+
+	invalid := []struct{ kind, pattern, target string }{
+		{ServiceIdentityKindAlias, "payments", "payments"},
+		{ServiceIdentityKindAlias, " ", "payments"},
+		{ServiceIdentityKindRegex, "(", "payments"},
+		{"prefix", "pay", "payments"},
+	}
+	for _, item := range invalid {
+		if _, err := service.Create(ctx, 1, item.kind, item.pattern, item.target); !errors.Is(err, ErrServiceIdentityRuleInvalid) {
+			t.Fatalf("expected %+v to be rejected, got %v", item, err)
+		}
+	}
+
+	rule, err := service.Create(ctx, 1, ServiceIdentityKindRegex, ` ^(.+)-canary$ `, "$1")
+	if err != nil || rule.Pattern != `^(.+)-canary$` {
+		t.Fatalf("unexpected rule: %+v err=%v", rule, err)
+	}
+	if _, err := service.Create(ctx, 1, ServiceIdentityKindRegex, `^(.+)-canary$`, "other"); !errors.Is(err, ErrServiceIdentityRuleExists) {
+		t.Fatalf("expected duplicate pattern to be rejected, got %v", err)
+	}
+	if err := service.Delete(ctx, 1, rule.ID); err != nil {
+		t.Fatalf("delete rule: %v", err)
+	}
+	if err := service.Delete(ctx, 1, rule.ID); !errors.Is(err, ErrServiceIdentityRuleNotFound) {
+		t.Fatalf("expected missing rule error, got %v", err)
+	}
+}
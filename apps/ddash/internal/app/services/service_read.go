@@ -12,6 +12,18 @@ import (
 	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
 )
 
+const (
+	defaultDeploymentPageSize = 50
+	maxDeploymentPageSize     = 200
+)
+
+// DeploymentPage is one page of the deployments listing. Next is the cursor of
+// the following page, or the zero cursor when there is none.
+type DeploymentPage struct {
+	Rows []domain.DeploymentRow
+	Next ports.DeploymentCursor
+}
+
 // ServiceReadService provides read-side projections for service/deployment views.
 type ServiceReadService struct {
 	serviceStore   ports.ServiceQueryStore
@@ -59,17 +71,45 @@ func (s *ServiceReadService) GetServicesByEnv(ctx context.Context, organizationI
 	return applyMetadataToServices(services, metadata), nil
 }
 
-// GetDeployments returns deployment rows enriched with metadata tags.
-func (s *ServiceReadService) GetDeployments(ctx context.Context, organizationID int64, env, service string) ([]domain.DeploymentRow, []domain.MetadataFilterOption, error) {
-	rows, err := s.serviceStore.ListDeployments(ctx, organizationID, env, service)
+// GetDeployments returns one page of deployment rows enriched with metadata tags.
+func (s *ServiceReadService) GetDeployments(ctx context.Context, organizationID int64, filter ports.DeploymentFilter) (DeploymentPage, []domain.MetadataFilterOption, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultDeploymentPageSize
+	}
+	limit = min(limit, maxDeploymentPageSize)
+	// One extra row tells whether another page follows.
+	filter.Limit = limit + 1
+	rows, err := s.serviceStore.ListDeployments(ctx, organizationID, filter)
 	if err != nil {
-		return nil, nil, err
+		return DeploymentPage{}, nil, err
 	}
 	metadata, err := s.loadMetadataFilterData(ctx, organizationID)
+	if err != nil {
+		return DeploymentPage{}, nil, err
+	}
+	page := DeploymentPage{}
+	if int64(len(rows)) > limit {
+		rows = rows[:limit]
+		last := rows[limit-1]
+		page.Next = ports.DeploymentCursor{EventTsMs: last.EventTsMs, Seq: last.Seq}
+	}
+	page.Rows = applyMetadataToDeployments(rows, metadata)
+	return page, metadata.Options, nil
+}
+
+// GetDeploymentOverview returns per-environment deployment counts for the 7
+// and 30 days before now, and the services offered by the deployments filter.
+func (s *ServiceReadService) GetDeploymentOverview(ctx context.Context, organizationID int64, now time.Time) ([]ports.DeploymentEnvironmentCount, []string, error) {
+	counts, err := s.serviceStore.ListDeploymentEnvironmentCounts(ctx, organizationID, now)
 	if err != nil {
 		return nil, nil, err
 	}
-	return applyMetadataToDeployments(rows, metadata), metadata.Options, nil
+	services, err := s.serviceStore.ListDeploymentServices(ctx, organizationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return counts, services, nil
 }
 
 // GetOrganizationRenderVersion returns a lightweight version stamp for UI fragments.
@@ -77,6 +117,19 @@ func (s *ServiceReadService) GetOrganizationRenderVersion(ctx context.Context, o
 	return s.serviceStore.GetOrganizationRenderVersion(ctx, organizationID)
 }
 
+// GetLatestEventSeq returns the newest event sequence, used as a live-stream resume point.
+func (s *ServiceReadService) GetLatestEventSeq(ctx context.Context, organizationID int64) (int64, error) {
+	return s.serviceStore.GetLatestEventSeq(ctx, organizationID)
+}
+
+// ListServiceChangesAfterSeq returns services changed by events newer than afterSeq.
+func (s *ServiceReadService) ListServiceChangesAfterSeq(ctx context.Context, organizationID int64, afterSeq int64, limit int64) ([]ports.ServiceChange, error) {
+	if limit <= 0 {
+		limit = 500
+	}
+	return s.serviceStore.ListServiceChangesAfterSeq(ctx, organizationID, afterSeq, limit)
+}
+
 // GetServiceDetail returns a fully composed service details view model.
 func (s *ServiceReadService) GetServiceDetail(ctx context.Context, organizationID int64, name string) (domain.ServiceDetail, error) {
 	service, err := s.serviceStore.GetServiceLatest(ctx, organizationID, name)
@@ -61,7 +61,7 @@ func TestGetDeployments_AppliesMetadataAndReturnsOptions(t *testing.T) {
 	metadataStore := mocks.NewMockServiceMetadataStore(t)
 	analyticsStore := mocks.NewMockServiceAnalyticsStore(t)
 
-	queryStore.On("ListDeployments", context.Background(), int64(202), "dev", "svc-a").Return([]domain.DeploymentRow{
+	queryStore.On("ListDeployments", context.Background(), int64(202), ports.DeploymentFilter{Env: "dev", Service: "svc-a", Limit: defaultDeploymentPageSize + 1}).Return([]domain.DeploymentRow{
 		{Service: "svc-a"},
 		{Service: "svc-b"},
 	}, nil)
@@ -76,10 +76,14 @@ func TestGetDeployments_AppliesMetadataAndReturnsOptions(t *testing.T) {
 	}, nil)
 
 	svc := NewServiceReadService(queryStore, metadataStore, analyticsStore)
-	rows, options, err := svc.GetDeployments(context.Background(), 202, "dev", "svc-a")
+	page, options, err := svc.GetDeployments(context.Background(), 202, ports.DeploymentFilter{Env: "dev", Service: "svc-a"})
 	if err != nil {
 		t.Fatalf("GetDeployments returned error: %v", err)
 	}
+	rows := page.Rows
+	if !page.Next.IsZero() {
+		t.Fatalf("expected no next page, got %+v", page.Next)
+	}
 
 	if rows[0].MetadataTags != "|team:platform|" {
 		t.Fatalf("expected first row tags |team:platform|, got %q", rows[0].MetadataTags)
@@ -95,6 +99,32 @@ func TestGetDeployments_AppliesMetadataAndReturnsOptions(t *testing.T) {
 	}
 }
 
+func TestGetDeployments_ReturnsCursorForNextPage(t *testing.T) {
+	queryStore := mocks.NewMockServiceQueryStore(t)
+	metadataStore := mocks.NewMockServiceMetadataStore(t)
+	analyticsStore := mocks.NewMockServiceAnalyticsStore(t)
+
+	queryStore.On("ListDeployments", context.Background(), int64(202), ports.DeploymentFilter{Status: domain.DeploymentStatusError, Limit: 3}).Return([]domain.DeploymentRow{
+		{Service: "svc-a", Seq: 9, EventTsMs: 3000},
+		{Service: "svc-a", Seq: 7, EventTsMs: 2000},
+		{Service: "svc-a", Seq: 4, EventTsMs: 1000},
+	}, nil)
+	metadataStore.On("ListRequiredFields", context.Background(), int64(202)).Return(nil, nil)
+	metadataStore.On("ListServiceMetadataValuesByOrganization", context.Background(), int64(202)).Return(nil, nil)
+
+	svc := NewServiceReadService(queryStore, metadataStore, analyticsStore)
+	page, _, err := svc.GetDeployments(context.Background(), 202, ports.DeploymentFilter{Status: domain.DeploymentStatusError, Limit: 2})
+	if err != nil {
+		t.Fatalf("GetDeployments returned error: %v", err)
+	}
+	if len(page.Rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(page.Rows))
+	}
+	if page.Next != (ports.DeploymentCursor{EventTsMs: 2000, Seq: 7}) {
+		t.Fatalf("unexpected next cursor: %+v", page.Next)
+	}
+}
+
 func TestGetServiceDetail_ComposesMetadataAndEnvironmentOrder(t *testing.T) {
 	queryStore := mocks.NewMockServiceQueryStore(t)
 	metadataStore := mocks.NewMockServiceMetadataStore(t)
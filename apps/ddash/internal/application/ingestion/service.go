@@ -11,16 +11,31 @@ import (
 type BatchConfig = appservices.IngestBatchConfig
 type Command = appservices.IngestCommand
 type ErrorKind = appservices.IngestErrorKind
+type BatchResult = appservices.IngestBatchResult
+type BatchEventResult = appservices.IngestBatchEventResult
+type SettingsCache = appservices.IngestSettingsCache
 
 const (
-	ErrorUnknown          ErrorKind = appservices.IngestErrorUnknown
-	ErrorMissingAuth      ErrorKind = appservices.IngestErrorMissingAuth
-	ErrorInvalidAuth      ErrorKind = appservices.IngestErrorInvalidAuth
-	ErrorInvalidSignature ErrorKind = appservices.IngestErrorInvalidSignature
-	ErrorInvalidPayload   ErrorKind = appservices.IngestErrorInvalidPayload
-	ErrorInvalidSchema    ErrorKind = appservices.IngestErrorInvalidSchema
-	ErrorUnsupportedType  ErrorKind = appservices.IngestErrorUnsupportedType
-	ErrorBusy             ErrorKind = appservices.IngestErrorBusy
+	ErrorUnknown           ErrorKind = appservices.IngestErrorUnknown
+	ErrorMissingAuth       ErrorKind = appservices.IngestErrorMissingAuth
+	ErrorInvalidAuth       ErrorKind = appservices.IngestErrorInvalidAuth
+	ErrorInvalidSignature  ErrorKind = appservices.IngestErrorInvalidSignature
+	ErrorInvalidPayload    ErrorKind = appservices.IngestErrorInvalidPayload
+	ErrorInvalidSchema     ErrorKind = appservices.IngestErrorInvalidSchema
+	ErrorUnsupportedType   ErrorKind = appservices.IngestErrorUnsupportedType
+	ErrorBusy              ErrorKind = appservices.IngestErrorBusy
+	ErrorStaleSignature    ErrorKind = appservices.IngestErrorStaleSignature
+	ErrorReplayedSignature ErrorKind = appservices.IngestErrorReplayedSignature
+	ErrorBatchTooLarge     ErrorKind = appservices.IngestErrorBatchTooLarge
+)
+
+const (
+	MaxBatchEvents = appservices.MaxIngestBatchEvents
+	BatchAccepted  = appservices.IngestBatchAccepted
+	BatchQueued    = appservices.IngestBatchQueued
+	BatchDuplicate = appservices.IngestBatchDuplicate
+	BatchDropped   = appservices.IngestBatchDropped
+	BatchRejected  = appservices.IngestBatchRejected
 )
 
 var (
@@ -28,6 +43,12 @@ var (
 	ErrInvalidAuthToken = appservices.ErrInvalidAuthToken
 )
 
+// NewSettingsCache constructs the ingestion settings cache shared by the
+// webhook handlers and the settings pages.
+func NewSettingsCache() *SettingsCache {
+	return appservices.NewIngestSettingsCache(0)
+}
+
 type Service struct {
 	delegate *appservices.EventIngestService
 }
@@ -40,6 +61,10 @@ func (s *Service) Ingest(ctx context.Context, cmd Command) error {
 	return s.delegate.Ingest(ctx, cmd)
 }
 
+func (s *Service) IngestBatch(ctx context.Context, cmd Command) (BatchResult, error) {
+	return s.delegate.IngestBatch(ctx, cmd)
+}
+
 func (s *Service) IngestForOrganization(ctx context.Context, organizationID int64, headers http.Header, body []byte) error {
 	return s.delegate.IngestForOrganization(ctx, organizationID, headers, body)
 }
@@ -47,3 +72,47 @@ func (s *Service) IngestForOrganization(ctx context.Context, organizationID int6
 func ClassifyError(err error) ErrorKind {
 	return appservices.ClassifyIngestError(err)
 }
+
+type DeadLetter = ports.DeadLetter
+
+var ErrDeadLetterTruncated = appservices.ErrDeadLetterTruncated
+
+// DeadLetterService exposes rejected webhook deliveries for inspection and replay.
+type DeadLetterService struct {
+	delegate *appservices.DeadLetterService
+}
+
+// NewDeadLetterService replays through an unbatched ingest service sharing the
+// webhook store factory and change publisher.
+func NewDeadLetterService(store ports.DeadLetterStore, storeFactory ports.IngestionStoreFactory, publisher ports.ServiceChangePublisher) *DeadLetterService {
+	ingest := appservices.NewEventIngestServiceWithConfig(storeFactory, appservices.IngestBatchConfig{Enabled: false, Publisher: publisher})
+	return &DeadLetterService{delegate: appservices.NewDeadLetterService(store, ingest)}
+}
+
+func (s *DeadLetterService) List(ctx context.Context, organizationID int64) ([]DeadLetter, error) {
+	return s.delegate.List(ctx, organizationID)
+}
+
+func (s *DeadLetterService) Replay(ctx context.Context, organizationID, id int64) (string, error) {
+	return s.delegate.Replay(ctx, organizationID, id)
+}
+
+func (s *DeadLetterService) Delete(ctx context.Context, organizationID, id int64) error {
+	return s.delegate.Delete(ctx, organizationID, id)
+}
+
+type Health = appservices.IngestHealth
+type HealthHour = appservices.IngestHealthHour
+
+// HealthService reports the last 24 hours of ingestion for an organization.
+type HealthService struct {
+	delegate *appservices.IngestHealthService
+}
+
+func NewHealthService(store ports.IngestHealthStore) *HealthService {
+	return &HealthService{delegate: appservices.NewIngestHealthService(store)}
+}
+
+func (s *HealthService) Get(ctx context.Context, organizationID int64) (Health, error) {
+	return s.delegate.Get(ctx, organizationID)
+}
@@ -2,6 +2,7 @@ package orgconfig
 
 import (
 	"context"
+	"time"
 
 	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
 	appservices "github.com/fr0stylo/ddash/apps/ddash/internal/app/services"
@@ -10,13 +11,17 @@ import (
 type OrganizationSettings = appservices.OrganizationSettings
 type RequiredFieldInput = appservices.RequiredFieldInput
 type OrganizationSettingsUpdate = appservices.OrganizationSettingsUpdate
+type EventSubjectPolicy = ports.EventSubjectPolicy
+type IngestSettingsCache = appservices.IngestSettingsCache
 
 type Service struct {
 	delegate *appservices.OrganizationConfigService
 }
 
-func NewService(store ports.AppStore) *Service {
-	return &Service{delegate: appservices.NewOrganizationConfigService(store)}
+// NewService constructs the settings service; updates invalidate the
+// organization in ingestSettings, which may be nil.
+func NewService(store ports.AppStore, ingestSettings *IngestSettingsCache) *Service {
+	return &Service{delegate: appservices.NewOrganizationConfigServiceWithCache(store, ingestSettings)}
 }
 
 func (s *Service) GetSettings(ctx context.Context, organizationID int64) (OrganizationSettings, error) {
@@ -26,3 +31,117 @@ func (s *Service) GetSettings(ctx context.Context, organizationID int64) (Organi
 func (s *Service) UpdateSettings(ctx context.Context, organizationID int64, update OrganizationSettingsUpdate) error {
 	return s.delegate.UpdateSettings(ctx, organizationID, update)
 }
+
+type IngestCredential = ports.IngestCredential
+
+var (
+	ErrIngestCredentialLabelRequired = appservices.ErrIngestCredentialLabelRequired
+	ErrIngestCredentialNotFound      = appservices.ErrIngestCredentialNotFound
+)
+
+// CredentialService manages additional ingest credentials of an organization.
+type CredentialService struct {
+	delegate *appservices.IngestCredentialService
+}
+
+func NewCredentialService(store ports.IngestCredentialStore, ingestSettings *IngestSettingsCache) *CredentialService {
+	return &CredentialService{delegate: appservices.NewIngestCredentialServiceWithCache(store, ingestSettings)}
+}
+
+func (s *CredentialService) List(ctx context.Context, organizationID int64) ([]IngestCredential, error) {
+	return s.delegate.List(ctx, organizationID)
+}
+
+func (s *CredentialService) Create(ctx context.Context, organizationID int64, label string, validity time.Duration) (IngestCredential, error) {
+	return s.delegate.Create(ctx, organizationID, label, validity)
+}
+
+func (s *CredentialService) Expire(ctx context.Context, organizationID, id int64, grace time.Duration) error {
+	return s.delegate.Expire(ctx, organizationID, id, grace)
+}
+
+func (s *CredentialService) Revoke(ctx context.Context, organizationID, id int64) error {
+	return s.delegate.Revoke(ctx, organizationID, id)
+}
+
+type ServiceIdentityRule = ports.ServiceIdentityRule
+type ServiceIdentityAlias = ports.ServiceIdentityAlias
+
+const (
+	ServiceIdentityKindAlias = appservices.ServiceIdentityKindAlias
+	ServiceIdentityKindRegex = appservices.ServiceIdentityKindRegex
+)
+
+var (
+	ErrServiceIdentityRuleInvalid  = appservices.ErrServiceIdentityRuleInvalid
+	ErrServiceIdentityRuleExists   = appservices.ErrServiceIdentityRuleExists
+	ErrServiceIdentityRuleNotFound = appservices.ErrServiceIdentityRuleNotFound
+)
+
+// IdentityService manages the service identity rules of an organization.
+type IdentityService struct {
+	delegate *appservices.ServiceIdentityService
+}
+
+func NewIdentityService(store ports.ServiceIdentityStore) *IdentityService {
+	return &IdentityService{delegate: appservices.NewServiceIdentityService(store)}
+}
+
+func (s *IdentityService) List(ctx context.Context, organizationID int64) ([]ServiceIdentityRule, error) {
+	return s.delegate.List(ctx, organizationID)
+}
+
+func (s *IdentityService) Aliases(ctx context.Context, organizationID int64) ([]ServiceIdentityAlias, error) {
+	return s.delegate.Aliases(ctx, organizationID)
+}
+
+func (s *IdentityService) Create(ctx context.Context, organizationID int64, kind, pattern, serviceName string) (ServiceIdentityRule, error) {
+	return s.delegate.Create(ctx, organizationID, kind, pattern, serviceName)
+}
+
+func (s *IdentityService) Delete(ctx context.Context, organizationID, id int64) error {
+	return s.delegate.Delete(ctx, organizationID, id)
+}
+
+func (s *IdentityService) Resolve(ctx context.Context, organizationID int64, name string) (string, error) {
+	return s.delegate.Resolve(ctx, organizationID, name)
+}
+
+type Environment = ports.Environment
+
+const (
+	EnvironmentTierProduction    = appservices.EnvironmentTierProduction
+	EnvironmentTierNonProduction = appservices.EnvironmentTierNonProduction
+)
+
+var (
+	ErrEnvironmentInvalid   = appservices.ErrEnvironmentInvalid
+	ErrEnvironmentProtected = appservices.ErrEnvironmentProtected
+	ErrEnvironmentNotFound  = appservices.ErrEnvironmentNotFound
+)
+
+// ParseEnvironmentAliases splits a comma or newline separated alias list.
+func ParseEnvironmentAliases(value string) []string {
+	return appservices.ParseEnvironmentAliases(value)
+}
+
+// EnvironmentService manages the environment registry of an organization.
+type EnvironmentService struct {
+	delegate *appservices.EnvironmentRegistryService
+}
+
+func NewEnvironmentService(store ports.EnvironmentStore) *EnvironmentService {
+	return &EnvironmentService{delegate: appservices.NewEnvironmentRegistryService(store)}
+}
+
+func (s *EnvironmentService) List(ctx context.Context, organizationID int64) ([]Environment, error) {
+	return s.delegate.List(ctx, organizationID)
+}
+
+func (s *EnvironmentService) Save(ctx context.Context, organizationID int64, environment Environment) (Environment, error) {
+	return s.delegate.Save(ctx, organizationID, environment)
+}
+
+func (s *EnvironmentService) Delete(ctx context.Context, organizationID int64, name string) error {
+	return s.delegate.Delete(ctx, organizationID, name)
+}
@@ -84,17 +84,20 @@ type ArtifactAge struct {
 }
 
 type MTTRStats struct {
-	IncidentCount int64   `json:"incident_count"`
-	MTTRSeconds   float64 `json:"mttr_seconds"`
-	MTTDSeconds   float64 `json:"mttd_seconds"`
-	MTTESeconds   float64 `json:"mtte_seconds"`
+	IncidentCount     int64   `json:"incident_count"`
+	OpenIncidentCount int64   `json:"open_incident_count"`
+	MTTRSeconds       float64 `json:"mttr_seconds"`
+	MTTDSeconds       float64 `json:"mttd_seconds"`
+	MTTESeconds       float64 `json:"mtte_seconds"`
 }
 
 type IncidentLink struct {
 	IncidentID         string `json:"incident_id"`
 	IncidentType       string `json:"incident_type"`
+	Environment        string `json:"environment"`
 	LinkedAt           int64  `json:"linked_at"`
 	DeploymentEventSeq int64  `json:"deployment_event_seq"`
+	ResolvedAt         int64  `json:"resolved_at"`
 }
 
 type ComprehensiveDeliveryMetrics struct {
@@ -107,6 +110,12 @@ type ComprehensiveDeliveryMetrics struct {
 	ActiveDeployDays30d          int64   `json:"active_deploy_days_30d"`
 }
 
+type ServiceChangeHub = appservices.ServiceChangeHub
+
+func NewServiceChangeHub() *ServiceChangeHub {
+	return appservices.NewServiceChangeHub()
+}
+
 func NewService(store ports.ServiceReadStore) *Service {
 	return &Service{read: appservices.NewServiceReadServiceFromStore(store), store: store}
 }
@@ -119,14 +128,26 @@ func (s *Service) GetServicesByEnv(ctx context.Context, organizationID int64, en
 	return s.read.GetServicesByEnv(ctx, organizationID, env)
 }
 
-func (s *Service) GetDeployments(ctx context.Context, organizationID int64, env, service string) ([]domain.DeploymentRow, []domain.MetadataFilterOption, error) {
-	return s.read.GetDeployments(ctx, organizationID, env, service)
+func (s *Service) GetDeployments(ctx context.Context, organizationID int64, filter DeploymentFilter) (DeploymentPage, []domain.MetadataFilterOption, error) {
+	return s.read.GetDeployments(ctx, organizationID, filter)
+}
+
+func (s *Service) GetDeploymentOverview(ctx context.Context, organizationID int64, now time.Time) ([]DeploymentEnvironmentCount, []string, error) {
+	return s.read.GetDeploymentOverview(ctx, organizationID, now)
 }
 
 func (s *Service) GetOrganizationRenderVersion(ctx context.Context, organizationID int64) (int64, error) {
 	return s.read.GetOrganizationRenderVersion(ctx, organizationID)
 }
 
+func (s *Service) GetLatestEventSeq(ctx context.Context, organizationID int64) (int64, error) {
+	return s.read.GetLatestEventSeq(ctx, organizationID)
+}
+
+func (s *Service) ListServiceChangesAfterSeq(ctx context.Context, organizationID int64, afterSeq int64, limit int64) ([]ports.ServiceChange, error) {
+	return s.read.ListServiceChangesAfterSeq(ctx, organizationID, afterSeq, limit)
+}
+
 func (s *Service) GetServiceDetail(ctx context.Context, organizationID int64, name string) (domain.ServiceDetail, error) {
 	return s.read.GetServiceDetail(ctx, organizationID, name)
 }
@@ -298,6 +319,7 @@ type ServiceMetricsResponse struct {
 	RedeploymentRate    ports.RedeploymentRate             `json:"redeployment_rate"`
 	Throughput          ports.WeeklyThroughput             `json:"throughput"`
 	ArtifactAges        []ports.ArtifactAge                `json:"artifact_ages"`
+	Incidents           ports.MTTRStats                    `json:"incidents"`
 	Comprehensive       ports.ComprehensiveDeliveryMetrics `json:"comprehensive"`
 }
 
@@ -340,6 +362,11 @@ func (s *Service) GetServiceMetrics(ctx context.Context, organizationID int64, s
 		return ServiceMetricsResponse{}, err
 	}
 
+	incidents, err := s.store.GetMTTR(ctx, organizationID, serviceName, sinceMs)
+	if err != nil {
+		return ServiceMetricsResponse{}, err
+	}
+
 	comprehensive, err := s.store.GetComprehensiveDeliveryMetrics(ctx, organizationID, sinceMs)
 	if err != nil {
 		return ServiceMetricsResponse{}, err
@@ -353,6 +380,7 @@ func (s *Service) GetServiceMetrics(ctx context.Context, organizationID int64, s
 		RedeploymentRate:    redeployRate,
 		Throughput:          throughput,
 		ArtifactAges:        artifactAges,
+		Incidents:           incidents,
 		Comprehensive:       comprehensive,
 	}, nil
 }
@@ -361,6 +389,7 @@ type OrgMetricsResponse struct {
 	TotalServices       int64                              `json:"total_services"`
 	PipelineStats       ports.PipelineStats                `json:"pipeline_stats"`
 	Comprehensive       ports.ComprehensiveDeliveryMetrics `json:"comprehensive"`
+	Incidents           ports.MTTRStats                    `json:"incidents"`
 	LeadTimeReport      LeadTimeReport                     `json:"lead_time"`
 	WeeklyThroughput    []ports.WeeklyThroughput           `json:"weekly_throughput"`
 	TopRedeployServices []ports.RedeploymentRate           `json:"top_redeploy_services"`
@@ -406,6 +435,11 @@ func (s *Service) GetOrgMetrics(ctx context.Context, organizationID int64, days
 		comprehensive = comp
 	}
 
+	incidents, err := s.store.GetMTTR(ctx, organizationID, "", sinceMs)
+	if err != nil {
+		incidents = ports.MTTRStats{}
+	}
+
 	leadTimeReport, err := s.BuildLeadTimeReport(ctx, organizationID, days)
 	if err != nil {
 		leadTimeReport = LeadTimeReport{}
@@ -415,7 +449,156 @@ func (s *Service) GetOrgMetrics(ctx context.Context, organizationID int64, days
 		TotalServices:    int64(len(serviceNames)),
 		PipelineStats:    totalPipelineStats,
 		Comprehensive:    comprehensive,
+		Incidents:        incidents,
 		LeadTimeReport:   leadTimeReport,
 		WeeklyThroughput: []ports.WeeklyThroughput{},
 	}, nil
 }
+
+var ErrProjectionRebuildRunning = appservices.ErrProjectionRebuildRunning
+
+// ProjectionRebuildService rebuilds an organization's projections in the background.
+type ProjectionRebuildService struct {
+	delegate *appservices.ProjectionRebuildService
+}
+
+func NewProjectionRebuildService(store ports.ProjectionRebuildStore) *ProjectionRebuildService {
+	return &ProjectionRebuildService{delegate: appservices.NewProjectionRebuildService(store)}
+}
+
+func (s *ProjectionRebuildService) Start(ctx context.Context, organizationID int64) error {
+	return s.delegate.Start(ctx, organizationID)
+}
+
+func (s *ProjectionRebuildService) Schedule(ctx context.Context, organizationID int64) {
+	s.delegate.Schedule(ctx, organizationID)
+}
+
+func (s *ProjectionRebuildService) Running(organizationID int64) bool {
+	return s.delegate.Running(organizationID)
+}
+
+type ProjectorStatus = ports.ProjectorStatus
+
+// ProjectorService lists projector checkpoints and lag.
+type ProjectorService struct {
+	delegate *appservices.ProjectorService
+}
+
+func NewProjectorService(store ports.ProjectorStore) *ProjectorService {
+	return &ProjectorService{delegate: appservices.NewProjectorService(store)}
+}
+
+func (s *ProjectorService) List(ctx context.Context) ([]ProjectorStatus, error) {
+	return s.delegate.List(ctx)
+}
+
+type ProjectorRunner = appservices.ProjectorRunner
+
+type ProjectorRunnerOptions = appservices.ProjectorRunnerOptions
+
+func StartProjectorRunner(store ports.ProjectorStore, options ProjectorRunnerOptions) *ProjectorRunner {
+	return appservices.StartProjectorRunner(store, options)
+}
+
+type EventRetentionRunner = appservices.EventRetentionRunner
+
+type EventRetentionRunnerOptions = appservices.EventRetentionRunnerOptions
+
+func StartEventRetentionRunner(store ports.EventRetentionStore, options EventRetentionRunnerOptions) *EventRetentionRunner {
+	return appservices.StartEventRetentionRunner(store, options)
+}
+
+type BackupRunner = appservices.BackupRunner
+
+type BackupRunnerOptions = appservices.BackupRunnerOptions
+
+func StartBackupRunner(store ports.BackupStore, options BackupRunnerOptions) *BackupRunner {
+	return appservices.StartBackupRunner(store, options)
+}
+
+type DeploymentFilter = ports.DeploymentFilter
+
+type DeploymentCursor = ports.DeploymentCursor
+
+type DeploymentEnvironmentCount = ports.DeploymentEnvironmentCount
+
+type DeploymentPage = appservices.DeploymentPage
+
+type EventFilter = ports.EventFilter
+
+type StoredEvent = ports.StoredEvent
+
+type StoredEventDetail = ports.StoredEventDetail
+
+type EventPage = appservices.EventPage
+
+// EventExplorerService lists and inspects raw events.
+type EventExplorerService struct {
+	delegate *appservices.EventExplorerService
+}
+
+func NewEventExplorerService(store ports.EventExplorerStore) *EventExplorerService {
+	return &EventExplorerService{delegate: appservices.NewEventExplorerService(store)}
+}
+
+func (s *EventExplorerService) List(ctx context.Context, organizationID int64, filter EventFilter) (EventPage, error) {
+	return s.delegate.List(ctx, organizationID, filter)
+}
+
+func (s *EventExplorerService) Get(ctx context.Context, organizationID, seq int64) (StoredEventDetail, error) {
+	return s.delegate.Get(ctx, organizationID, seq)
+}
+
+type DeliveryChain = appservices.DeliveryChain
+
+type ChainStage = appservices.ChainStage
+
+type ChainEvent = ports.ChainEvent
+
+// ChainService assembles delivery chains.
+type ChainService struct {
+	delegate *appservices.ChainService
+}
+
+func NewChainService(store ports.ChainStore) *ChainService {
+	return &ChainService{delegate: appservices.NewChainService(store)}
+}
+
+func (s *ChainService) Get(ctx context.Context, organizationID int64, chainID string) (DeliveryChain, error) {
+	return s.delegate.Get(ctx, organizationID, chainID)
+}
+
+type EnvironmentState = ports.EnvironmentState
+
+type EnvironmentMatrix = appservices.EnvironmentMatrix
+
+type EnvironmentMatrixRow = appservices.EnvironmentMatrixRow
+
+type EnvironmentChange = appservices.EnvironmentChange
+
+type EnvironmentDiff = appservices.EnvironmentDiff
+
+const (
+	EnvironmentChangeAdded      = appservices.EnvironmentChangeAdded
+	EnvironmentChangeArtifact   = appservices.EnvironmentChangeArtifact
+	EnvironmentChangeStatus     = appservices.EnvironmentChangeStatus
+	EnvironmentChangeRedeployed = appservices.EnvironmentChangeRedeployed
+)
+
+// EnvironmentHistoryService rebuilds past environment state.
+type EnvironmentHistoryService struct {
+	delegate *appservices.EnvironmentHistoryService
+}
+
+func NewEnvironmentHistoryService(store ports.EnvironmentHistoryStore) *EnvironmentHistoryService {
+	return &EnvironmentHistoryService{delegate: appservices.NewEnvironmentHistoryService(store)}
+}
+
+func (s *EnvironmentHistoryService) StateAt(ctx context.Context, organizationID int64, at time.Time) (EnvironmentMatrix, error) {
+	return s.delegate.StateAt(ctx, organizationID, at)
+}
+
+func (s *EnvironmentHistoryService) Diff(ctx context.Context, organizationID int64, from, to time.Time) (EnvironmentDiff, error) {
+	return s.delegate.Diff(ctx, organizationID, from, to)
+}
@@ -0,0 +1,3 @@
+package ingestion
+
+// Package ingestion contains postgres adapters for ingestion context.
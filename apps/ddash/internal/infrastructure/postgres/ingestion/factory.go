@@ -0,0 +1,36 @@
+package ingestion
+
+import (
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+	"github.com/fr0stylo/ddash/internal/pgdb"
+)
+
+// StoreFactory opens postgres-backed stores for webhook ingestion.
+type StoreFactory struct {
+	url    string
+	shared *pgdb.Database
+}
+
+// NewStoreFactory creates a postgres ingestion store factory backed by a connection URL.
+func NewStoreFactory(url string) *StoreFactory {
+	return &StoreFactory{url: url}
+}
+
+// NewSharedStoreFactory creates a factory backed by an existing shared DB handle.
+func NewSharedStoreFactory(shared *pgdb.Database) *StoreFactory {
+	return &StoreFactory{shared: shared}
+}
+
+// Open creates a request-scoped postgres ingestion store.
+func (f *StoreFactory) Open() (ports.IngestionStore, error) {
+	if f.shared != nil {
+		return newStore(f.shared, nil), nil
+	}
+	database, err := pgdb.New(f.url)
+	if err != nil {
+		return nil, err
+	}
+	return newStore(database, database.Close), nil
+}
+
+var _ ports.IngestionStoreFactory = (*StoreFactory)(nil)
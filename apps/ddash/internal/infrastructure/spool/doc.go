@@ -0,0 +1,3 @@
+package spool
+
+// Package spool provides a durable on-disk write-ahead queue in front of ingestion stores.
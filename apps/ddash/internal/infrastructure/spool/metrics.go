@@ -0,0 +1,70 @@
+package spool
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type spoolMetrics struct {
+	drained             metric.Int64Counter
+	drainErrors         metric.Int64Counter
+	quarantinedSegments metric.Int64Counter
+	quarantinedEvents   metric.Int64Counter
+	registration        metric.Registration
+}
+
+func newSpoolMetrics(s *Spool) *spoolMetrics {
+	meter := otel.Meter("github.com/fr0stylo/ddash/apps/ddash/internal/infrastructure/spool")
+	depth, _ := meter.Int64ObservableGauge("ddash.ingestion.spool.depth")
+	lag, _ := meter.Int64ObservableGauge("ddash.ingestion.spool.drain_lag_ms")
+	drained, _ := meter.Int64Counter("ddash.ingestion.spool.drained")
+	drainErrors, _ := meter.Int64Counter("ddash.ingestion.spool.drain_errors")
+	stuck, _ := meter.Int64ObservableGauge("ddash.ingestion.spool.stuck_segments")
+	quarantinedSegments, _ := meter.Int64Counter("ddash.ingestion.spool.quarantined_segments")
+	quarantinedEvents, _ := meter.Int64Counter("ddash.ingestion.spool.quarantined_events")
+
+	registration, _ := meter.RegisterCallback(func(_ context.Context, observer metric.Observer) error {
+		observer.ObserveInt64(depth, s.Depth())
+		var lagMs int64
+		if oldest, ok := s.oldestPendingAt(); ok {
+			lagMs = time.Since(oldest).Milliseconds()
+		}
+		observer.ObserveInt64(lag, lagMs)
+		var stuckSegments int64
+		if s.stuck() {
+			stuckSegments = 1
+		}
+		observer.ObserveInt64(stuck, stuckSegments)
+		return nil
+	}, depth, lag, stuck)
+
+	return &spoolMetrics{
+		drained:             drained,
+		drainErrors:         drainErrors,
+		quarantinedSegments: quarantinedSegments,
+		quarantinedEvents:   quarantinedEvents,
+		registration:        registration,
+	}
+}
+
+func (m *spoolMetrics) recordDrained(count int64) {
+	m.drained.Add(context.Background(), count)
+}
+
+func (m *spoolMetrics) recordDrainError() {
+	m.drainErrors.Add(context.Background(), 1)
+}
+
+func (m *spoolMetrics) recordQuarantined(events int64) {
+	m.quarantinedSegments.Add(context.Background(), 1)
+	m.quarantinedEvents.Add(context.Background(), events)
+}
+
+func (m *spoolMetrics) unregister() {
+	if m.registration != nil {
+		_ = m.registration.Unregister()
+	}
+}
@@ -0,0 +1,547 @@
+package spool
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+const (
+	segmentSuffix = ".ndjson"
+	// quarantineDir holds, below the spool directory, the events that kept
+	// failing to drain.
+	quarantineDir = "quarantine"
+)
+
+// ErrClosed indicates the spool no longer accepts events.
+var ErrClosed = errors.New("ingest spool closed")
+
+// Options tunes how spooled events are drained into the event store.
+type Options struct {
+	// OnAppended receives events newly committed by the drainer.
+	OnAppended     func(appended []ports.AppendedEvent)
+	DrainBatchSize int
+	PollInterval   time.Duration
+	RetryInterval  time.Duration
+	// MaxDrainAttempts is how many times in a row the oldest segment may fail
+	// to drain before its failing events are quarantined.
+	MaxDrainAttempts int
+}
+
+// Spool acknowledges events once they are fsynced to a local segment file and
+// drains them into the wrapped ingestion store in the background. Segments are
+// deleted only after every event in them was appended, so a crash replays the
+// segment; event-store idempotency drops the duplicates. A segment that keeps
+// failing has its failing events moved to the quarantine directory so the
+// rest of the spool keeps draining.
+type Spool struct {
+	dir     string
+	inner   ports.IngestionStoreFactory
+	options Options
+	metrics *spoolMetrics
+
+	mu      sync.Mutex
+	closed  bool
+	nextSeq uint64
+	active  *activeSegment
+	pending []segment
+
+	depth    atomic.Int64
+	failures atomic.Int64
+	notify   chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+type segment struct {
+	path      string
+	createdAt time.Time
+	records   int64
+}
+
+type activeSegment struct {
+	segment
+	file *os.File
+}
+
+// spoolRecord is the on-disk line format; field names are part of the format.
+type spoolRecord struct {
+	SpooledAtMs    int64   `json:"spooledAtMs"`
+	OrganizationID int64   `json:"organizationId"`
+	EventID        string  `json:"eventId"`
+	EventType      string  `json:"eventType"`
+	EventSource    string  `json:"eventSource"`
+	EventTimestamp string  `json:"eventTimestamp"`
+	EventTSMs      int64   `json:"eventTsMs"`
+	SubjectID      string  `json:"subjectId"`
+	SubjectSource  *string `json:"subjectSource,omitempty"`
+	SubjectType    string  `json:"subjectType"`
+	ChainID        *string `json:"chainId,omitempty"`
+	RawEventJSON   string  `json:"rawEventJson"`
+}
+
+// Open prepares the spool directory, picks up segments left by a previous
+// process and starts the background drainer.
+func Open(dir string, inner ports.IngestionStoreFactory, options Options) (*Spool, error) {
+	if strings.TrimSpace(dir) == "" {
+		return nil, errors.New("spool directory is required")
+	}
+	if options.DrainBatchSize <= 0 {
+		options.DrainBatchSize = 500
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = time.Second
+	}
+	if options.RetryInterval <= 0 {
+		options.RetryInterval = 5 * time.Second
+	}
+	if options.MaxDrainAttempts <= 0 {
+		options.MaxDrainAttempts = 10
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	s := &Spool{
+		dir:     dir,
+		inner:   inner,
+		options: options,
+		notify:  make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if err := s.loadPending(); err != nil {
+		return nil, err
+	}
+	s.metrics = newSpoolMetrics(s)
+
+	go s.run()
+	return s, nil
+}
+
+// Open returns an ingestion store that spools appends and delegates reads.
+func (s *Spool) Open() (ports.IngestionStore, error) {
+	inner, err := s.inner.Open()
+	if err != nil {
+		return nil, err
+	}
+	return &store{IngestionStore: inner, spool: s}, nil
+}
+
+// Depth returns the number of spooled events not yet drained.
+func (s *Spool) Depth() int64 {
+	return s.depth.Load()
+}
+
+// Close stops the drainer and closes the active segment. Undrained segments
+// stay on disk and are drained by the next Open.
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.stop)
+	<-s.done
+	s.metrics.unregister()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.active == nil {
+		return nil
+	}
+	err := s.active.file.Close()
+	s.active = nil
+	return err
+}
+
+func (s *Spool) append(events []ports.EventRecord) error {
+	if len(events) == 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, event := range events {
+		if err := encoder.Encode(toSpoolRecord(event, now)); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	if s.active == nil {
+		active, err := s.createSegment(now)
+		if err != nil {
+			return err
+		}
+		s.active = active
+	}
+	if _, err := s.active.file.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	if err := s.active.file.Sync(); err != nil {
+		return err
+	}
+	s.active.records += int64(len(events))
+	s.depth.Add(int64(len(events)))
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (s *Spool) createSegment(now time.Time) (*activeSegment, error) {
+	path := filepath.Join(s.dir, fmt.Sprintf("%020d%s", s.nextSeq, segmentSuffix))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, err
+	}
+	if err := syncDir(s.dir); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	s.nextSeq++
+	return &activeSegment{segment: segment{path: path, createdAt: now}, file: file}, nil
+}
+
+func (s *Spool) loadPending() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), segmentSuffix) {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, segmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		if seq >= s.nextSeq {
+			s.nextSeq = seq + 1
+		}
+		path := filepath.Join(s.dir, name)
+		records, err := readSegment(path)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			_ = os.Remove(path)
+			continue
+		}
+		s.pending = append(s.pending, segment{
+			path:      path,
+			createdAt: time.UnixMilli(records[0].SpooledAtMs).UTC(),
+			records:   int64(len(records)),
+		})
+		s.depth.Add(int64(len(records)))
+	}
+	return nil
+}
+
+// oldestPendingAt returns when the oldest undrained event was spooled.
+func (s *Spool) oldestPendingAt() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.pending) > 0 {
+		return s.pending[0].createdAt, true
+	}
+	if s.active != nil && s.active.records > 0 {
+		return s.active.createdAt, true
+	}
+	return time.Time{}, false
+}
+
+func (s *Spool) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.drainPending(); err != nil {
+			slog.Error("ingest_spool_drain_failed", "error", err, "depth", s.depth.Load())
+			select {
+			case <-s.stop:
+				return
+			case <-time.After(s.options.RetryInterval):
+			}
+			continue
+		}
+		select {
+		case <-s.stop:
+			return
+		case <-s.notify:
+		case <-ticker.C:
+		}
+	}
+}
+
+// drainPending appends sealed segments oldest first until the spool is empty.
+func (s *Spool) drainPending() error {
+	for {
+		select {
+		case <-s.stop:
+			return nil
+		default:
+		}
+		next, ok, err := s.nextSegment()
+		if err != nil || !ok {
+			return err
+		}
+		if err := s.drainSegment(next); err != nil {
+			s.metrics.recordDrainError()
+			if s.failures.Add(1) < int64(s.options.MaxDrainAttempts) {
+				return err
+			}
+			slog.Error("ingest_spool_segment_stuck", "error", err, "segment", filepath.Base(next.path), "attempts", s.failures.Load())
+			if err := s.quarantineSegment(next); err != nil {
+				return err
+			}
+		}
+		s.failures.Store(0)
+		if err := os.Remove(next.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		s.mu.Lock()
+		s.pending = s.pending[1:]
+		s.mu.Unlock()
+		s.depth.Add(-next.records)
+	}
+}
+
+// nextSegment returns the oldest pending segment, sealing the active one when
+// nothing older is waiting.
+func (s *Spool) nextSegment() (segment, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.pending) == 0 && s.active != nil && s.active.records > 0 {
+		if err := s.active.file.Close(); err != nil {
+			return segment{}, false, err
+		}
+		s.pending = append(s.pending, s.active.segment)
+		s.active = nil
+	}
+	if len(s.pending) == 0 {
+		return segment{}, false, nil
+	}
+	return s.pending[0], true, nil
+}
+
+func (s *Spool) drainSegment(next segment) error {
+	records, err := readSegment(next.path)
+	if err != nil {
+		return err
+	}
+	store, err := s.inner.Open()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = store.Close()
+	}()
+
+	for start := 0; start < len(records); start += s.options.DrainBatchSize {
+		end := min(start+s.options.DrainBatchSize, len(records))
+		events := make([]ports.EventRecord, 0, end-start)
+		for _, record := range records[start:end] {
+			events = append(events, record.eventRecord())
+		}
+		appended, err := store.AppendEvents(context.Background(), events)
+		if err != nil {
+			return err
+		}
+		s.metrics.recordDrained(int64(len(events)))
+		if s.options.OnAppended != nil && len(appended) > 0 {
+			s.options.OnAppended(appended)
+		}
+	}
+	return nil
+}
+
+// quarantineSegment appends the events of a segment that keeps failing one at
+// a time and writes the ones that still fail to the quarantine directory, so
+// one bad event cannot stall the spool. A segment that cannot be decoded is
+// moved there as a whole. Quarantined files keep the segment format and drain
+// again when moved back into the spool directory before a restart.
+func (s *Spool) quarantineSegment(next segment) error {
+	target := filepath.Join(s.dir, quarantineDir, filepath.Base(next.path))
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return err
+	}
+	records, err := readSegment(next.path)
+	if err != nil {
+		if err := os.Rename(next.path, target); err != nil {
+			return err
+		}
+		slog.Error("ingest_spool_segment_quarantined", "error", err, "segment", filepath.Base(next.path), "events", next.records)
+		s.metrics.recordQuarantined(next.records)
+		return syncDir(s.dir)
+	}
+	store, err := s.inner.Open()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = store.Close()
+	}()
+
+	var failed bytes.Buffer
+	encoder := json.NewEncoder(&failed)
+	var quarantined int64
+	for _, record := range records {
+		appended, err := store.AppendEvents(context.Background(), []ports.EventRecord{record.eventRecord()})
+		if err == nil {
+			s.metrics.recordDrained(1)
+			if s.options.OnAppended != nil && len(appended) > 0 {
+				s.options.OnAppended(appended)
+			}
+			continue
+		}
+		slog.Error("ingest_spool_event_quarantined", "error", err, "segment", filepath.Base(next.path), "organization_id", record.OrganizationID, "event_id", record.EventID)
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+		quarantined++
+	}
+	if quarantined == 0 {
+		return nil
+	}
+	if err := writeSynced(target, failed.Bytes()); err != nil {
+		return err
+	}
+	s.metrics.recordQuarantined(quarantined)
+	return nil
+}
+
+// writeSynced writes a new file and fsyncs it and its directory.
+func writeSynced(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o640)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+// stuck reports whether the oldest segment failed its last drain attempt.
+func (s *Spool) stuck() bool {
+	return s.failures.Load() > 0
+}
+
+// readSegment decodes one segment. A torn final line from a crash mid-write is
+// skipped because it was never acknowledged to the sender.
+func readSegment(path string) ([]spoolRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	records := make([]spoolRecord, 0)
+	reader := bufio.NewReader(file)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			record := spoolRecord{}
+			if err := json.Unmarshal(line, &record); err != nil {
+				if readErr == nil {
+					return nil, fmt.Errorf("decode spool segment %s: %w", filepath.Base(path), err)
+				}
+				slog.Warn("ingest_spool_torn_record_skipped", "segment", filepath.Base(path))
+			} else {
+				records = append(records, record)
+			}
+		}
+		if readErr != nil {
+			break
+		}
+	}
+	return records, nil
+}
+
+func syncDir(dir string) error {
+	handle, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = handle.Close()
+	}()
+	return handle.Sync()
+}
+
+func toSpoolRecord(event ports.EventRecord, spooledAt time.Time) spoolRecord {
+	return spoolRecord{
+		SpooledAtMs:    spooledAt.UnixMilli(),
+		OrganizationID: event.OrganizationID,
+		EventID:        event.EventID,
+		EventType:      event.EventType,
+		EventSource:    event.EventSource,
+		EventTimestamp: event.EventTimestamp,
+		EventTSMs:      event.EventTSMs,
+		SubjectID:      event.SubjectID,
+		SubjectSource:  event.SubjectSource,
+		SubjectType:    event.SubjectType,
+		ChainID:        event.ChainID,
+		RawEventJSON:   event.RawEventJSON,
+	}
+}
+
+func (r spoolRecord) eventRecord() ports.EventRecord {
+	return ports.EventRecord{
+		OrganizationID: r.OrganizationID,
+		EventID:        r.EventID,
+		EventType:      r.EventType,
+		EventSource:    r.EventSource,
+		EventTimestamp: r.EventTimestamp,
+		EventTSMs:      r.EventTSMs,
+		SubjectID:      r.SubjectID,
+		SubjectSource:  r.SubjectSource,
+		SubjectType:    r.SubjectType,
+		ChainID:        r.ChainID,
+		RawEventJSON:   r.RawEventJSON,
+	}
+}
+
+var _ ports.IngestionStoreFactory = (*Spool)(nil)
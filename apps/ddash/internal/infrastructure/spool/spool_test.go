@@ -0,0 +1,273 @@
+package spool
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+type fakeStoreFactory struct {
+	mu       sync.Mutex
+	events   []ports.EventRecord
+	failures int
+	// poisoned event ids fail every append that carries them.
+	poisoned map[string]bool
+}
+
+func (f *fakeStoreFactory) Open() (ports.IngestionStore, error) {
+	return &fakeStore{factory: f}, nil
+}
+
+func (f *fakeStoreFactory) appended() []ports.EventRecord {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]ports.EventRecord(nil), f.events...)
+}
+
+type fakeStore struct {
+	ports.IngestionStore
+	factory *fakeStoreFactory
+}
+
+func (s *fakeStore) AppendEvents(_ context.Context, events []ports.EventRecord) ([]ports.AppendedEvent, error) {
+	s.factory.mu.Lock()
+	defer s.factory.mu.Unlock()
+	if s.factory.failures > 0 {
+		s.factory.failures--
+		return nil, errors.New("database is locked")
+	}
+	for _, event := range events {
+		if s.factory.poisoned[event.EventID] {
+			return nil, errors.New("constraint failed")
+		}
+	}
+	out := make([]ports.AppendedEvent, 0, len(events))
+	for _, event := range events {
+		s.factory.events = append(s.factory.events, event)
+		out = append(out, ports.AppendedEvent{
+			OrganizationID: event.OrganizationID,
+			Seq:            int64(len(s.factory.events)),
+			SubjectType:    event.SubjectType,
+			ServiceName:    event.SubjectID,
+		})
+	}
+	return out, nil
+}
+
+func (s *fakeStore) Close() error {
+	return nil
+}
+
+func testEvent(id string) ports.EventRecord {
+	return ports.EventRecord{
+		OrganizationID: 1,
+		EventID:        id,
+		EventType:      "dev.cdevents.service.deployed.0.3.0",
+		EventSource:    "ci",
+		EventTimestamp: "2026-01-01T00:00:00Z",
+		EventTSMs:      1767225600000,
+		SubjectID:      "orders",
+		SubjectType:    "service",
+		RawEventJSON:   `{"context":{"id":"` + id + `"}}`,
+	}
+}
+
+func testOptions() Options {
+	return Options{PollInterval: 10 * time.Millisecond, RetryInterval: 10 * time.Millisecond}
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("condition not met before deadline")
+}
+
+func TestSpoolDrainsAcknowledgedEvents(t *testing.T) {
+	t.Parallel()
+
+	inner := &fakeStoreFactory{}
+	var mu sync.Mutex
+	var published []ports.AppendedEvent
+	options := testOptions()
+	options.OnAppended = func(appended []ports.AppendedEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		published = append(published, appended...)
+	}
+	spooled, err := Open(t.TempDir(), inner, options)
+	if err != nil {
+		t.Fatalf("open spool: %v", err)
+	}
+	t.Cleanup(func() { _ = spooled.Close() })
+
+	store, err := spooled.Open()
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	appended, err := store.AppendEvents(context.Background(), []ports.EventRecord{testEvent("evt-1"), testEvent("evt-2")})
+	if err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+	if len(appended) != 2 || appended[0].EventID != "evt-1" || appended[0].Seq != 0 || appended[0].ServiceName != "" || !appended[0].Queued {
+		t.Fatalf("expected queued events without sequence or service, got %+v", appended)
+	}
+	if err := store.AppendEvent(context.Background(), testEvent("evt-3")); err != nil {
+		t.Fatalf("append event: %v", err)
+	}
+
+	waitFor(t, func() bool { return len(inner.appended()) == 3 && spooled.Depth() == 0 })
+	if got := inner.appended()[2]; got != testEvent("evt-3") {
+		t.Fatalf("unexpected drained event %+v", got)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(published) != 3 {
+		t.Fatalf("expected 3 published appended events, got %d", len(published))
+	}
+}
+
+func TestSpoolDrainsSegmentsLeftByPreviousProcess(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	failing := &fakeStoreFactory{failures: 1 << 30}
+	first, err := Open(dir, failing, testOptions())
+	if err != nil {
+		t.Fatalf("open first spool: %v", err)
+	}
+	store, err := first.Open()
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	if err := store.AppendEvent(context.Background(), testEvent("evt-1")); err != nil {
+		t.Fatalf("append event: %v", err)
+	}
+	if err := first.Close(); err != nil {
+		t.Fatalf("close first spool: %v", err)
+	}
+
+	// Simulate a crash in the middle of the next write.
+	segments, _ := filepath.Glob(filepath.Join(dir, "*"+segmentSuffix))
+	if len(segments) != 1 {
+		t.Fatalf("expected one leftover segment, got %d", len(segments))
+	}
+	file, err := os.OpenFile(segments[0], os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("open segment: %v", err)
+	}
+	_, _ = file.WriteString(`{"spooledAtMs":1,"eventId":"torn`)
+	_ = file.Close()
+
+	inner := &fakeStoreFactory{}
+	second, err := Open(dir, inner, testOptions())
+	if err != nil {
+		t.Fatalf("open second spool: %v", err)
+	}
+	t.Cleanup(func() { _ = second.Close() })
+
+	waitFor(t, func() bool { return len(inner.appended()) == 1 && second.Depth() == 0 })
+	if got := inner.appended()[0].EventID; got != "evt-1" {
+		t.Fatalf("expected evt-1 drained, got %q", got)
+	}
+	waitFor(t, func() bool {
+		remaining, _ := filepath.Glob(filepath.Join(dir, "*"+segmentSuffix))
+		return len(remaining) == 0
+	})
+}
+
+func TestSpoolRetriesFailedDrain(t *testing.T) {
+	t.Parallel()
+
+	inner := &fakeStoreFactory{failures: 2}
+	spooled, err := Open(t.TempDir(), inner, testOptions())
+	if err != nil {
+		t.Fatalf("open spool: %v", err)
+	}
+	t.Cleanup(func() { _ = spooled.Close() })
+
+	store, err := spooled.Open()
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	if err := store.AppendEvent(context.Background(), testEvent("evt-1")); err != nil {
+		t.Fatalf("append event: %v", err)
+	}
+
+	waitFor(t, func() bool { return len(inner.appended()) == 1 && spooled.Depth() == 0 })
+}
+
+func TestSpoolQuarantinesEventsThatKeepFailing(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	inner := &fakeStoreFactory{poisoned: map[string]bool{"evt-bad": true}}
+	options := testOptions()
+	options.MaxDrainAttempts = 3
+	spooled, err := Open(dir, inner, options)
+	if err != nil {
+		t.Fatalf("open spool: %v", err)
+	}
+	t.Cleanup(func() { _ = spooled.Close() })
+
+	store, err := spooled.Open()
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	if _, err := store.AppendEvents(context.Background(), []ports.EventRecord{testEvent("evt-1"), testEvent("evt-bad"), testEvent("evt-2")}); err != nil {
+		t.Fatalf("append events: %v", err)
+	}
+
+	waitFor(t, func() bool { return len(inner.appended()) == 2 && spooled.Depth() == 0 })
+	if spooled.stuck() {
+		t.Fatal("expected the spool to be unstuck after quarantining")
+	}
+	quarantined, _ := filepath.Glob(filepath.Join(dir, quarantineDir, "*"+segmentSuffix))
+	if len(quarantined) != 1 {
+		t.Fatalf("expected one quarantined file, got %v", quarantined)
+	}
+	records, err := readSegment(quarantined[0])
+	if err != nil {
+		t.Fatalf("read quarantined file: %v", err)
+	}
+	if len(records) != 1 || records[0].EventID != "evt-bad" {
+		t.Fatalf("expected only evt-bad quarantined, got %+v", records)
+	}
+
+	if _, err := store.AppendEvents(context.Background(), []ports.EventRecord{testEvent("evt-3")}); err != nil {
+		t.Fatalf("append after quarantine: %v", err)
+	}
+	waitFor(t, func() bool { return len(inner.appended()) == 3 && spooled.Depth() == 0 })
+}
+
+func TestSpoolRejectsAppendsAfterClose(t *testing.T) {
+	t.Parallel()
+
+	spooled, err := Open(t.TempDir(), &fakeStoreFactory{}, testOptions())
+	if err != nil {
+		t.Fatalf("open spool: %v", err)
+	}
+	store, err := spooled.Open()
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	if err := spooled.Close(); err != nil {
+		t.Fatalf("close spool: %v", err)
+	}
+	if err := store.AppendEvent(context.Background(), testEvent("evt-1")); !errors.Is(err, ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
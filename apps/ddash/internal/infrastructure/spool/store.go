@@ -0,0 +1,40 @@
+package spool
+
+import (
+	"context"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+// store spools appends and delegates every other call to the wrapped store.
+type store struct {
+	ports.IngestionStore
+	spool *Spool
+}
+
+// AppendEvent spools one event for background append.
+func (s *store) AppendEvent(_ context.Context, event ports.EventRecord) error {
+	return s.spool.append([]ports.EventRecord{event})
+}
+
+// AppendEvents spools events for background append. Spooled events are
+// reported without a sequence or service name, so callers acknowledge them
+// without publishing changes; the drainer reports them once committed.
+// Duplicates are only detected when the spool drains.
+func (s *store) AppendEvents(_ context.Context, events []ports.EventRecord) ([]ports.AppendedEvent, error) {
+	if err := s.spool.append(events); err != nil {
+		return nil, err
+	}
+	out := make([]ports.AppendedEvent, 0, len(events))
+	for _, event := range events {
+		out = append(out, ports.AppendedEvent{
+			OrganizationID: event.OrganizationID,
+			EventID:        event.EventID,
+			SubjectType:    event.SubjectType,
+			Queued:         true,
+		})
+	}
+	return out, nil
+}
+
+var _ ports.IngestionStore = (*store)(nil)
@@ -3,17 +3,32 @@ package ingestion
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
 
 	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+	"github.com/fr0stylo/ddash/internal/db"
 	"github.com/fr0stylo/ddash/internal/db/queries"
 )
 
 type databaseContract interface {
 	GetOrganizationByAuthToken(ctx context.Context, authToken string) (queries.Organization, error)
+	GetOrganizationByIngestCredentialToken(ctx context.Context, authToken string, now int64) (queries.Organization, error)
+	ListOrganizationIngestCredentials(ctx context.Context, organizationID int64) ([]queries.OrganizationIngestCredential, error)
+	TouchOrganizationIngestCredential(ctx context.Context, organizationID, credentialID, usedAt, throttle int64) error
+	RecordIngestOutcome(ctx context.Context, organizationID int64, outcome, reason string, count int64, at time.Time) error
+	ListOrganizationEventPolicies(ctx context.Context, organizationID int64) ([]queries.ListOrganizationEventPoliciesRow, error)
+	GetOrganizationPreference(ctx context.Context, params queries.GetOrganizationPreferenceParams) (string, error)
+	RememberIngestSignature(ctx context.Context, params queries.InsertIngestSeenSignatureParams) (bool, error)
 	AppendEventStore(ctx context.Context, params queries.AppendEventStoreParams) error
-	AppendEventStoreBatch(ctx context.Context, params []queries.AppendEventStoreParams) error
+	AppendEventStoreBatch(ctx context.Context, params []queries.AppendEventStoreParams) ([]db.AppendedEvent, error)
+	AppendIngestDeadLetter(ctx context.Context, params queries.InsertIngestDeadLetterParams, receivedBefore, keepCount int64) error
 }
 
+// credentialTouchThrottle bounds how often last-used timestamps are written.
+const credentialTouchThrottle = time.Minute
+
 type store struct {
 	db      databaseContract
 	closeFn func() error
@@ -23,8 +38,13 @@ func newStore(database databaseContract, closeFn func() error) *store {
 	return &store{db: database, closeFn: closeFn}
 }
 
+// GetOrganizationByAuthToken resolves the primary organization token first and
+// falls back to active additional ingest credentials.
 func (s *store) GetOrganizationByAuthToken(ctx context.Context, token string) (ports.Organization, error) {
 	org, err := s.db.GetOrganizationByAuthToken(ctx, token)
+	if errors.Is(err, sql.ErrNoRows) {
+		org, err = s.db.GetOrganizationByIngestCredentialToken(ctx, token, time.Now().UTC().UnixMilli())
+	}
 	if err != nil {
 		return ports.Organization{}, err
 	}
@@ -37,20 +57,108 @@ func (s *store) GetOrganizationByAuthToken(ctx context.Context, token string) (p
 	}, nil
 }
 
+func (s *store) ListOrganizationEventPolicies(ctx context.Context, organizationID int64) ([]ports.EventSubjectPolicy, error) {
+	rows, err := s.db.ListOrganizationEventPolicies(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.EventSubjectPolicy, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, ports.EventSubjectPolicy{Subject: row.Subject, Mode: row.Mode})
+	}
+	return out, nil
+}
+
+func (s *store) GetOrganizationPreference(ctx context.Context, organizationID int64, key string) (string, error) {
+	value, err := s.db.GetOrganizationPreference(ctx, queries.GetOrganizationPreferenceParams{
+		OrganizationID: organizationID,
+		PreferenceKey:  key,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return value, err
+}
+
+func (s *store) RememberWebhookSignature(ctx context.Context, organizationID int64, signature string, seenAt, expiresAt time.Time) (bool, error) {
+	return s.db.RememberIngestSignature(ctx, queries.InsertIngestSeenSignatureParams{
+		OrganizationID: organizationID,
+		Signature:      signature,
+		SeenAt:         seenAt.UTC().UnixMilli(),
+		ExpiresAt:      expiresAt.UTC().UnixMilli(),
+	})
+}
+
+func (s *store) ListIngestCredentials(ctx context.Context, organizationID int64) ([]ports.IngestCredential, error) {
+	rows, err := s.db.ListOrganizationIngestCredentials(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.IngestCredential, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, mapIngestCredential(row))
+	}
+	return out, nil
+}
+
+func (s *store) TouchIngestCredential(ctx context.Context, organizationID, id int64, usedAt time.Time) error {
+	return s.db.TouchOrganizationIngestCredential(ctx, organizationID, id, usedAt.UTC().UnixMilli(), credentialTouchThrottle.Milliseconds())
+}
+
+func (s *store) RecordIngestOutcome(ctx context.Context, organizationID int64, outcome, reason string, count int64, at time.Time) error {
+	return s.db.RecordIngestOutcome(ctx, organizationID, outcome, reason, count, at)
+}
+
 func (s *store) AppendEvent(ctx context.Context, event ports.EventRecord) error {
 	params := toAppendEventParams(event)
 	return s.db.AppendEventStore(ctx, params)
 }
 
-func (s *store) AppendEvents(ctx context.Context, events []ports.EventRecord) error {
+func (s *store) AppendEvents(ctx context.Context, events []ports.EventRecord) ([]ports.AppendedEvent, error) {
 	if len(events) == 0 {
-		return nil
+		return nil, nil
 	}
 	params := make([]queries.AppendEventStoreParams, 0, len(events))
 	for _, event := range events {
 		params = append(params, toAppendEventParams(event))
 	}
-	return s.db.AppendEventStoreBatch(ctx, params)
+	appended, err := s.db.AppendEventStoreBatch(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ports.AppendedEvent, 0, len(appended))
+	for _, item := range appended {
+		out = append(out, ports.AppendedEvent{
+			OrganizationID: item.OrganizationID,
+			EventID:        item.EventID,
+			Seq:            item.Seq,
+			SubjectType:    item.SubjectType,
+			ServiceName:    item.ServiceName,
+		})
+	}
+	return out, nil
+}
+
+func (s *store) AppendDeadLetter(ctx context.Context, letter ports.DeadLetter, retention ports.DeadLetterRetention) error {
+	headers, err := json.Marshal(letter.Headers)
+	if err != nil {
+		return err
+	}
+	truncated := int64(0)
+	if letter.Truncated {
+		truncated = 1
+	}
+	receivedAt := letter.ReceivedAt.UTC()
+	return s.db.AppendIngestDeadLetter(ctx, queries.InsertIngestDeadLetterParams{
+		OrganizationID: letter.OrganizationID,
+		Reason:         letter.Reason,
+		Detail:         letter.Detail,
+		HeadersJson:    string(headers),
+		Body:           letter.Body,
+		BodySize:       letter.BodySize,
+		Truncated:      truncated,
+		ReceivedAt:     receivedAt.UnixMilli(),
+	}, receivedAt.Add(-retention.MaxAge).UnixMilli(), retention.MaxCount)
 }
 
 func toAppendEventParams(event ports.EventRecord) queries.AppendEventStoreParams {
@@ -79,6 +187,28 @@ func toAppendEventParams(event ports.EventRecord) queries.AppendEventStoreParams
 	}
 }
 
+func mapIngestCredential(row queries.OrganizationIngestCredential) ports.IngestCredential {
+	return ports.IngestCredential{
+		ID:             row.ID,
+		OrganizationID: row.OrganizationID,
+		Label:          row.Label,
+		AuthToken:      row.AuthToken,
+		WebhookSecret:  row.WebhookSecret,
+		CreatedAt:      time.UnixMilli(row.CreatedAt).UTC(),
+		ExpiresAt:      nullMillisToTime(row.ExpiresAt),
+		RevokedAt:      nullMillisToTime(row.RevokedAt),
+		LastUsedAt:     nullMillisToTime(row.LastUsedAt),
+	}
+}
+
+func nullMillisToTime(value sql.NullInt64) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	t := time.UnixMilli(value.Int64).UTC()
+	return &t
+}
+
 func (s *store) Close() error {
 	if s.closeFn == nil {
 		return nil
@@ -0,0 +1,166 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	appcatalog "github.com/fr0stylo/ddash/apps/ddash/internal/application/servicecatalog"
+	"github.com/fr0stylo/ddash/views/components"
+	"github.com/fr0stylo/ddash/views/pages"
+)
+
+type chainEventJSON struct {
+	eventJSON
+	URL string `json:"url,omitempty"`
+}
+
+type chainStageJSON struct {
+	SubjectType     string           `json:"subject_type"`
+	SubjectID       string           `json:"subject_id"`
+	Started         time.Time        `json:"started"`
+	Finished        time.Time        `json:"finished"`
+	DurationSeconds float64          `json:"duration_seconds"`
+	WaitSeconds     float64          `json:"wait_seconds"`
+	Events          []chainEventJSON `json:"events"`
+}
+
+type chainJSON struct {
+	ID              string           `json:"id"`
+	Started         time.Time        `json:"started"`
+	Finished        time.Time        `json:"finished"`
+	DurationSeconds float64          `json:"duration_seconds"`
+	Truncated       bool             `json:"truncated"`
+	Stages          []chainStageJSON `json:"stages"`
+}
+
+func (v *ViewRoutes) handleChain(c echo.Context) error {
+	chain, err := v.loadChain(c)
+	if err != nil {
+		return err
+	}
+	if chain == nil {
+		return nil
+	}
+
+	out := components.DeliveryChain{
+		ID:        chain.ID,
+		Started:   chain.Started.Format("2006-01-02 15:04:05 UTC"),
+		Finished:  chain.Finished.Format("2006-01-02 15:04:05 UTC"),
+		Duration:  formatChainDuration(chain.Duration()),
+		Truncated: chain.Truncated,
+		EventsURL: "/events?" + url.Values{"chain": {chain.ID}}.Encode(),
+		Stages:    make([]components.ChainStage, 0, len(chain.Stages)),
+	}
+	for i, stage := range chain.Stages {
+		row := components.ChainStage{
+			SubjectType: stage.SubjectType,
+			SubjectID:   stage.SubjectID,
+			Started:     stage.Started.Format("2006-01-02 15:04:05 UTC"),
+			Duration:    formatChainDuration(stage.Duration()),
+			Events:      make([]components.ChainEvent, 0, len(stage.Events)),
+		}
+		if i > 0 {
+			row.Wait = formatChainDuration(stage.Wait)
+		}
+		for _, event := range stage.Events {
+			row.Events = append(row.Events, components.ChainEvent{
+				EventType: event.EventType,
+				Timestamp: event.Timestamp.Format("15:04:05"),
+				Offset:    "+" + formatChainDuration(event.Timestamp.Sub(chain.Started)),
+				DetailURL: mapEventRow(event.StoredEvent).DetailURL,
+				URL:       externalChainURL(event.URL),
+			})
+		}
+		out.Stages = append(out.Stages, row)
+	}
+	return c.Render(http.StatusOK, "", pages.ChainPage(out))
+}
+
+func (v *ViewRoutes) handleChainData(c echo.Context) error {
+	chain, err := v.loadChain(c)
+	if err != nil {
+		return err
+	}
+	if chain == nil {
+		return nil
+	}
+
+	out := chainJSON{
+		ID:              chain.ID,
+		Started:         chain.Started,
+		Finished:        chain.Finished,
+		DurationSeconds: chain.Duration().Seconds(),
+		Truncated:       chain.Truncated,
+		Stages:          make([]chainStageJSON, 0, len(chain.Stages)),
+	}
+	for _, stage := range chain.Stages {
+		row := chainStageJSON{
+			SubjectType:     stage.SubjectType,
+			SubjectID:       stage.SubjectID,
+			Started:         stage.Started,
+			Finished:        stage.Finished,
+			DurationSeconds: stage.Duration().Seconds(),
+			WaitSeconds:     stage.Wait.Seconds(),
+			Events:          make([]chainEventJSON, 0, len(stage.Events)),
+		}
+		for _, event := range stage.Events {
+			row.Events = append(row.Events, chainEventJSON{eventJSON: mapEventJSON(event.StoredEvent), URL: event.URL})
+		}
+		out.Stages = append(out.Stages, row)
+	}
+	return c.JSON(http.StatusOK, out)
+}
+
+// loadChain resolves the chain named in the path. A nil chain with a nil
+// error means a response has already been written.
+func (v *ViewRoutes) loadChain(c echo.Context) (*appcatalog.DeliveryChain, error) {
+	ctx := c.Request().Context()
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return nil, err
+	}
+	if v.chains == nil {
+		return nil, c.NoContent(http.StatusServiceUnavailable)
+	}
+	chainID, err := url.PathUnescape(c.Param("id"))
+	if err != nil || strings.TrimSpace(chainID) == "" {
+		return nil, c.NoContent(http.StatusNotFound)
+	}
+	chain, err := v.chains.Get(ctx, orgID, chainID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, c.NoContent(http.StatusNotFound)
+		}
+		return nil, err
+	}
+	return &chain, nil
+}
+
+// chainURL links to the delivery chain view, or returns "" for events
+// without a chain.
+func chainURL(chainID string) string {
+	if strings.TrimSpace(chainID) == "" {
+		return ""
+	}
+	return "/chains/" + url.PathEscape(chainID)
+}
+
+// externalChainURL keeps sender-supplied links only when they are plain web
+// links.
+func externalChainURL(raw string) string {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return ""
+	}
+	return parsed.String()
+}
+
+func formatChainDuration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
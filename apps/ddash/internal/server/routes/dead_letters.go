@@ -0,0 +1,123 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	appingestion "github.com/fr0stylo/ddash/apps/ddash/internal/application/ingestion"
+	"github.com/fr0stylo/ddash/views/components"
+	"github.com/fr0stylo/ddash/views/pages"
+)
+
+const deadLetterBodyPreviewBytes = 8 << 10
+
+func (v *ViewRoutes) handleDeadLetters(c echo.Context) error {
+	ctx := c.Request().Context()
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	if err := v.requireOrganizationAdmin(c, orgID); err != nil {
+		return err
+	}
+	if v.deadLetters == nil {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+	rows, err := v.deadLetters.List(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, "", pages.DeadLettersPage(mapDeadLetters(rows), csrfToken(c)))
+}
+
+func (v *ViewRoutes) handleDeadLetterReplay(c echo.Context) error {
+	ctx := c.Request().Context()
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	if err := v.requireOrganizationAdmin(c, orgID); err != nil {
+		return err
+	}
+	if v.deadLetters == nil {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+	id, err := strconv.ParseInt(strings.TrimSpace(c.FormValue("id")), 10, 64)
+	if err != nil || id <= 0 {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	if _, err := v.deadLetters.Replay(ctx, orgID, id); err != nil {
+		if errors.Is(err, appingestion.ErrDeadLetterTruncated) {
+			return c.NoContent(http.StatusUnprocessableEntity)
+		}
+		return err
+	}
+	return c.Redirect(http.StatusFound, "/settings/dead-letters")
+}
+
+func (v *ViewRoutes) handleDeadLetterDelete(c echo.Context) error {
+	ctx := c.Request().Context()
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	if err := v.requireOrganizationAdmin(c, orgID); err != nil {
+		return err
+	}
+	if v.deadLetters == nil {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+	id, err := strconv.ParseInt(strings.TrimSpace(c.FormValue("id")), 10, 64)
+	if err != nil || id <= 0 {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	if err := v.deadLetters.Delete(ctx, orgID, id); err != nil {
+		return err
+	}
+	return c.Redirect(http.StatusFound, "/settings/dead-letters")
+}
+
+func mapDeadLetters(rows []appingestion.DeadLetter) []components.DeadLetter {
+	out := make([]components.DeadLetter, 0, len(rows))
+	for _, row := range rows {
+		body := string(row.Body)
+		if len(body) > deadLetterBodyPreviewBytes {
+			body = body[:deadLetterBodyPreviewBytes] + "\n…"
+		}
+		replayStatus := ""
+		if row.ReplayedAt != nil {
+			replayStatus = fmt.Sprintf("%s at %s", row.ReplayResult, row.ReplayedAt.Format("2006-01-02 15:04:05 UTC"))
+		}
+		out = append(out, components.DeadLetter{
+			ID:           row.ID,
+			ReceivedAt:   row.ReceivedAt.Format("2006-01-02 15:04:05 UTC"),
+			Reason:       row.Reason,
+			Detail:       row.Detail,
+			Size:         fmt.Sprintf("%d bytes", row.BodySize),
+			Headers:      formatDeadLetterHeaders(row.Headers),
+			Body:         body,
+			Replayable:   !row.Truncated,
+			ReplayStatus: replayStatus,
+		})
+	}
+	return out
+}
+
+func formatDeadLetterHeaders(headers map[string][]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, name+": "+strings.Join(headers[name], ", "))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,75 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+	"github.com/fr0stylo/ddash/apps/ddash/internal/renderer"
+)
+
+type deadLetterStoreFake struct {
+	deleted []int64
+}
+
+func (f *deadLetterStoreFake) ListDeadLetters(context.Context, int64, int64) ([]ports.DeadLetter, error) {
+	return nil, nil
+}
+
+func (f *deadLetterStoreFake) GetDeadLetter(context.Context, int64, int64) (ports.DeadLetter, error) {
+	return ports.DeadLetter{}, errors.New("not found")
+}
+
+func (f *deadLetterStoreFake) MarkDeadLetterReplayed(context.Context, int64, int64, string, time.Time) error {
+	return nil
+}
+
+func (f *deadLetterStoreFake) DeleteDeadLetter(_ context.Context, _, id int64) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+type unusedIngestionStores struct{}
+
+func (unusedIngestionStores) Open() (ports.IngestionStore, error) {
+	return nil, errors.New("unused")
+}
+
+func TestHandleDeadLetterDeleteRequiresOrganizationAdmin(t *testing.T) {
+	initAuthStoreForTests()
+	e := echo.New()
+	e.Renderer = &renderer.Renderer{}
+
+	for role, wantDeleted := range map[string]bool{"member": false, "admin": true} {
+		store := &orgRouteStoreFake{org: ports.Organization{ID: 1, Name: "org-a", Enabled: true}, roleByUserID: map[int64]string{10: role}}
+		deadLetters := &deadLetterStoreFake{}
+		v := NewViewRoutes(store, nil, store, ViewExternalConfig{DeadLetters: deadLetters, IngestionStores: unusedIngestionStores{}})
+
+		form := url.Values{}
+		form.Set("id", "7")
+		c, rec := newAuthedContext(t, e, http.MethodPost, "/settings/dead-letters/delete", form)
+		err := v.handleDeadLetterDelete(c)
+		if wantDeleted && err != nil {
+			t.Fatalf("%s: handler error: %v", role, err)
+		}
+		if !wantDeleted && !errors.Is(err, errOrganizationAdminRequired) {
+			t.Fatalf("%s: expected admin access error, got %v", role, err)
+		}
+		if got := len(deadLetters.deleted) == 1; got != wantDeleted {
+			t.Fatalf("%s: deleted=%v, want %v", role, deadLetters.deleted, wantDeleted)
+		}
+		if rec.Code != http.StatusFound {
+			t.Fatalf("%s: expected redirect, got %d", role, rec.Code)
+		}
+		if !wantDeleted && !strings.Contains(rec.Header().Get("Location"), "/organizations") {
+			t.Fatalf("%s: expected redirect to organizations, got %q", role, rec.Header().Get("Location"))
+		}
+	}
+}
@@ -45,7 +45,15 @@ func mapDomainMetadataOptions(rows []domain.MetadataFilterOption) []components.M
 	return out
 }
 
-func mapDomainServiceDetail(detail domain.ServiceDetail) components.ServiceDetail {
+// mapDomainServiceDetail maps a service detail; linkChains is false when the
+// storage backend does not serve /chains, so chain links are left out.
+func mapDomainServiceDetail(detail domain.ServiceDetail, linkChains bool) components.ServiceDetail {
+	chainLink := func(chainID string) string {
+		if !linkChains {
+			return ""
+		}
+		return chainURL(chainID)
+	}
 	metadataFields := mapDomainMetadataFields(detail.MetadataFields)
 	requiredFields := mapDomainMetadataFields(detail.OrgRequiredFields)
 
@@ -72,6 +80,7 @@ func mapDomainServiceDetail(detail domain.ServiceDetail) components.ServiceDetai
 			DeployedAt:  row.DeployedAt,
 			DeployedAgo: row.DeployedAgo,
 			Environment: row.Environment,
+			ChainURL:    chainLink(row.ChainID),
 		})
 	}
 
@@ -82,6 +91,7 @@ func mapDomainServiceDetail(detail domain.ServiceDetail) components.ServiceDetai
 			Environment:   row.Environment,
 			Artifact:      row.Artifact,
 			ChainID:       row.ChainID,
+			ChainURL:      chainLink(row.ChainID),
 			PipelineRunID: row.PipelineRunID,
 			RunURL:        row.RunURL,
 			ActorName:     row.ActorName,
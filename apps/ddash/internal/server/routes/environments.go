@@ -0,0 +1,112 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	apporgconfig "github.com/fr0stylo/ddash/apps/ddash/internal/application/orgconfig"
+	"github.com/fr0stylo/ddash/views/components"
+	"github.com/fr0stylo/ddash/views/pages"
+)
+
+func (v *ViewRoutes) handleEnvironments(c echo.Context) error {
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	if err := v.requireOrganizationAdmin(c, orgID); err != nil {
+		return err
+	}
+	return v.renderEnvironments(c, orgID, http.StatusOK, "")
+}
+
+func (v *ViewRoutes) handleEnvironmentSave(c echo.Context) error {
+	ctx := c.Request().Context()
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	if err := v.requireOrganizationAdmin(c, orgID); err != nil {
+		return err
+	}
+	if v.environments == nil {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+	if _, err := v.environments.Save(ctx, orgID, apporgconfig.Environment{
+		Name:      c.FormValue("name"),
+		Tier:      c.FormValue("tier"),
+		Color:     c.FormValue("color"),
+		Protected: c.FormValue("protected") != "",
+		URL:       c.FormValue("url"),
+		Aliases:   apporgconfig.ParseEnvironmentAliases(c.FormValue("aliases")),
+	}); err != nil {
+		if errors.Is(err, apporgconfig.ErrEnvironmentInvalid) {
+			return v.renderEnvironments(c, orgID, http.StatusBadRequest, err.Error())
+		}
+		return err
+	}
+	v.rebuildAfterIdentityChange(c, orgID)
+	return c.Redirect(http.StatusFound, "/settings/environments")
+}
+
+func (v *ViewRoutes) handleEnvironmentDelete(c echo.Context) error {
+	ctx := c.Request().Context()
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	if err := v.requireOrganizationAdmin(c, orgID); err != nil {
+		return err
+	}
+	if v.environments == nil {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+	name := strings.TrimSpace(c.FormValue("name"))
+	if name == "" {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	if err := v.environments.Delete(ctx, orgID, name); err != nil {
+		switch {
+		case errors.Is(err, apporgconfig.ErrEnvironmentNotFound):
+			return c.NoContent(http.StatusNotFound)
+		case errors.Is(err, apporgconfig.ErrEnvironmentProtected):
+			return v.renderEnvironments(c, orgID, http.StatusConflict, name+" is protected; unprotect it before deleting it.")
+		}
+		return err
+	}
+	v.rebuildAfterIdentityChange(c, orgID)
+	return c.Redirect(http.StatusFound, "/settings/environments")
+}
+
+func (v *ViewRoutes) renderEnvironments(c echo.Context, orgID int64, status int, errorMessage string) error {
+	ctx := c.Request().Context()
+	if v.environments == nil {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+	environments, err := v.environments.List(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	rebuilding := v.projections != nil && v.projections.Running(orgID)
+	return c.Render(status, "", pages.EnvironmentsPage(mapEnvironmentEntries(environments), errorMessage, rebuilding, csrfToken(c)))
+}
+
+func mapEnvironmentEntries(rows []apporgconfig.Environment) []components.EnvironmentEntry {
+	out := make([]components.EnvironmentEntry, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, components.EnvironmentEntry{
+			Name:      row.Name,
+			Tier:      row.Tier,
+			Color:     row.Color,
+			Protected: row.Protected,
+			URL:       row.URL,
+			Source:    row.Source,
+			Aliases:   strings.Join(row.Aliases, ", "),
+			UpdatedAt: row.UpdatedAt.Format("2006-01-02 15:04 UTC"),
+		})
+	}
+	return out
+}
@@ -0,0 +1,273 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	appcatalog "github.com/fr0stylo/ddash/apps/ddash/internal/application/servicecatalog"
+	"github.com/fr0stylo/ddash/views/components"
+	"github.com/fr0stylo/ddash/views/pages"
+)
+
+// eventFilterTimeLayouts are accepted for the from and to filters; the
+// second is what datetime-local inputs submit.
+var eventFilterTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02"}
+
+type eventJSON struct {
+	Seq         int64     `json:"seq"`
+	EventID     string    `json:"event_id"`
+	EventType   string    `json:"event_type"`
+	Source      string    `json:"source"`
+	Timestamp   time.Time `json:"timestamp"`
+	SubjectID   string    `json:"subject_id"`
+	SubjectType string    `json:"subject_type"`
+	ChainID     string    `json:"chain_id,omitempty"`
+	IngestedAt  time.Time `json:"ingested_at"`
+}
+
+type eventPageJSON struct {
+	Events     []eventJSON `json:"events"`
+	NextCursor int64       `json:"next_cursor,omitempty"`
+}
+
+type eventContributionJSON struct {
+	Service     string `json:"service"`
+	Environment string `json:"environment"`
+}
+
+type eventDetailJSON struct {
+	eventJSON
+	Contributions []eventContributionJSON `json:"contributions"`
+	Raw           json.RawMessage         `json:"raw_event"`
+}
+
+func (v *ViewRoutes) handleEvents(c echo.Context) error {
+	ctx := c.Request().Context()
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	if v.events == nil {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+	filter, err := parseEventFilter(c)
+	if err != nil {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	page, err := v.events.List(ctx, orgID, filter)
+	if err != nil {
+		return err
+	}
+	rows := make([]components.EventRow, 0, len(page.Events))
+	for _, event := range page.Events {
+		rows = append(rows, mapEventRow(event))
+	}
+	nextURL := ""
+	if page.NextCursor > 0 {
+		query := c.QueryParams()
+		query.Set("cursor", strconv.FormatInt(page.NextCursor, 10))
+		nextURL = "/events?" + query.Encode()
+	}
+	return c.Render(http.StatusOK, "", pages.EventsPage(components.EventFilter{
+		EventType:   filter.EventType,
+		SubjectType: filter.SubjectType,
+		SubjectID:   filter.SubjectID,
+		Source:      filter.Source,
+		ChainID:     filter.ChainID,
+		From:        formatEventFilterTime(filter.From),
+		To:          formatEventFilterTime(filter.To),
+	}, rows, nextURL))
+}
+
+func (v *ViewRoutes) handleEventDetail(c echo.Context) error {
+	ctx := c.Request().Context()
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	if v.events == nil {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+	seq, err := strconv.ParseInt(c.Param("seq"), 10, 64)
+	if err != nil || seq <= 0 {
+		return c.NoContent(http.StatusNotFound)
+	}
+	event, err := v.events.Get(ctx, orgID, seq)
+	if err != nil {
+		return err
+	}
+
+	detail := components.EventDetail{
+		EventRow:   mapEventRow(event.StoredEvent),
+		EventID:    event.EventID,
+		IngestedAt: event.IngestedAt.Format("2006-01-02 15:04:05 UTC"),
+		RawJSON:    indentEventJSON(event.RawJSON),
+	}
+	if event.ChainID != "" {
+		detail.ChainURL = chainURL(event.ChainID)
+	}
+	seen := map[string]bool{}
+	for _, contribution := range event.Contributions {
+		if !seen[contribution.Service] {
+			seen[contribution.Service] = true
+			detail.Links = append(detail.Links, components.EventLink{
+				Label: "Service " + contribution.Service,
+				URL:   "/s/" + url.PathEscape(contribution.Service),
+			})
+		}
+		detail.Links = append(detail.Links, components.EventLink{
+			Label: contribution.Service + " in " + contribution.Environment,
+			URL:   "/deployments?" + url.Values{"service": {contribution.Service}, "env": {contribution.Environment}}.Encode(),
+		})
+	}
+	return c.Render(http.StatusOK, "", pages.EventDetailPage(detail))
+}
+
+func (v *ViewRoutes) handleEventsData(c echo.Context) error {
+	ctx := c.Request().Context()
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	if v.events == nil {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+	filter, err := parseEventFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	page, err := v.events.List(ctx, orgID, filter)
+	if err != nil {
+		return err
+	}
+	out := eventPageJSON{Events: make([]eventJSON, 0, len(page.Events)), NextCursor: page.NextCursor}
+	for _, event := range page.Events {
+		out.Events = append(out.Events, mapEventJSON(event))
+	}
+	return c.JSON(http.StatusOK, out)
+}
+
+func (v *ViewRoutes) handleEventData(c echo.Context) error {
+	ctx := c.Request().Context()
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	if v.events == nil {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+	seq, err := strconv.ParseInt(c.Param("seq"), 10, 64)
+	if err != nil || seq <= 0 {
+		return c.NoContent(http.StatusNotFound)
+	}
+	event, err := v.events.Get(ctx, orgID, seq)
+	if err != nil {
+		return err
+	}
+	out := eventDetailJSON{
+		eventJSON:     mapEventJSON(event.StoredEvent),
+		Contributions: make([]eventContributionJSON, 0, len(event.Contributions)),
+		Raw:           json.RawMessage(event.RawJSON),
+	}
+	if !json.Valid(out.Raw) {
+		raw, _ := json.Marshal(event.RawJSON)
+		out.Raw = raw
+	}
+	for _, contribution := range event.Contributions {
+		out.Contributions = append(out.Contributions, eventContributionJSON{Service: contribution.Service, Environment: contribution.Environment})
+	}
+	return c.JSON(http.StatusOK, out)
+}
+
+func parseEventFilter(c echo.Context) (appcatalog.EventFilter, error) {
+	filter := appcatalog.EventFilter{
+		EventType:   strings.TrimSpace(c.QueryParam("type")),
+		SubjectType: strings.TrimSpace(c.QueryParam("subject_type")),
+		SubjectID:   strings.TrimSpace(c.QueryParam("subject")),
+		Source:      strings.TrimSpace(c.QueryParam("source")),
+		ChainID:     strings.TrimSpace(c.QueryParam("chain")),
+	}
+	var err error
+	if filter.From, err = parseEventFilterTime(c.QueryParam("from")); err != nil {
+		return filter, fmt.Errorf("invalid from: %w", err)
+	}
+	if filter.To, err = parseEventFilterTime(c.QueryParam("to")); err != nil {
+		return filter, fmt.Errorf("invalid to: %w", err)
+	}
+	if raw := strings.TrimSpace(c.QueryParam("cursor")); raw != "" {
+		if filter.BeforeSeq, err = strconv.ParseInt(raw, 10, 64); err != nil || filter.BeforeSeq < 0 {
+			return filter, fmt.Errorf("invalid cursor %q", raw)
+		}
+	}
+	if raw := strings.TrimSpace(c.QueryParam("limit")); raw != "" {
+		if filter.Limit, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			return filter, fmt.Errorf("invalid limit %q", raw)
+		}
+	}
+	return filter, nil
+}
+
+func parseEventFilterTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range eventFilterTimeLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return parsed.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised time %q", value)
+}
+
+func formatEventFilterTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+	return value.UTC().Format("2006-01-02T15:04")
+}
+
+func mapEventRow(event appcatalog.StoredEvent) components.EventRow {
+	return components.EventRow{
+		Seq:         event.Seq,
+		EventType:   event.EventType,
+		SubjectType: event.SubjectType,
+		SubjectID:   event.SubjectID,
+		Source:      event.Source,
+		ChainID:     event.ChainID,
+		Timestamp:   event.Timestamp.Format("2006-01-02 15:04:05 UTC"),
+		DetailURL:   fmt.Sprintf("/events/%d", event.Seq),
+	}
+}
+
+func mapEventJSON(event appcatalog.StoredEvent) eventJSON {
+	return eventJSON{
+		Seq:         event.Seq,
+		EventID:     event.EventID,
+		EventType:   event.EventType,
+		Source:      event.Source,
+		Timestamp:   event.Timestamp,
+		SubjectID:   event.SubjectID,
+		SubjectType: event.SubjectType,
+		ChainID:     event.ChainID,
+		IngestedAt:  event.IngestedAt,
+	}
+}
+
+// indentEventJSON pretty-prints a stored payload, falling back to the raw
+// text when it does not parse.
+func indentEventJSON(raw string) string {
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(raw), "", "  "); err != nil {
+		return raw
+	}
+	return out.String()
+}
@@ -0,0 +1,192 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	appcatalog "github.com/fr0stylo/ddash/apps/ddash/internal/application/servicecatalog"
+	"github.com/fr0stylo/ddash/views/components"
+	"github.com/fr0stylo/ddash/views/pages"
+)
+
+type environmentStateJSON struct {
+	Service     string    `json:"service"`
+	Environment string    `json:"environment"`
+	ArtifactID  string    `json:"artifact_id"`
+	Status      string    `json:"status"`
+	Actor       string    `json:"actor,omitempty"`
+	EventType   string    `json:"event_type"`
+	Seq         int64     `json:"seq"`
+	DeployedAt  time.Time `json:"deployed_at"`
+}
+
+type environmentChangeJSON struct {
+	Service     string                `json:"service"`
+	Environment string                `json:"environment"`
+	Kind        string                `json:"kind"`
+	Before      *environmentStateJSON `json:"before,omitempty"`
+	After       environmentStateJSON  `json:"after"`
+}
+
+type environmentHistoryJSON struct {
+	At           time.Time               `json:"at"`
+	Environments []string                `json:"environments"`
+	States       []environmentStateJSON  `json:"states"`
+	Compare      *time.Time              `json:"compare,omitempty"`
+	Changes      []environmentChangeJSON `json:"changes,omitempty"`
+}
+
+// environmentHistory is the matrix at the requested moment and, when a
+// second moment was given, the changes between the two.
+type environmentHistory struct {
+	Matrix  appcatalog.EnvironmentMatrix
+	Compare time.Time
+	Diff    *appcatalog.EnvironmentDiff
+}
+
+func (v *ViewRoutes) handleHistory(c echo.Context) error {
+	history, err := v.loadEnvironmentHistory(c)
+	if err != nil || history == nil {
+		return err
+	}
+
+	filter := components.HistoryFilter{At: formatEventFilterTime(history.Matrix.At)}
+	matrix := components.EnvironmentMatrix{
+		At:           history.Matrix.At.Format("2006-01-02 15:04 UTC"),
+		Environments: history.Matrix.Environments,
+		Rows:         make([]components.EnvironmentMatrixRow, 0, len(history.Matrix.Rows)),
+	}
+	for _, row := range history.Matrix.Rows {
+		out := components.EnvironmentMatrixRow{
+			Service:    row.Service,
+			ServiceURL: components.ServiceDetailsHref(components.Service{Title: row.Service}),
+			Cells:      make([]components.EnvironmentMatrixCell, 0, len(row.States)),
+		}
+		for _, state := range row.States {
+			out.Cells = append(out.Cells, mapEnvironmentMatrixCell(state))
+		}
+		matrix.Rows = append(matrix.Rows, out)
+	}
+	var changes []components.EnvironmentChangeRow
+	if history.Diff != nil {
+		filter.Compare = formatEventFilterTime(history.Compare)
+		changes = make([]components.EnvironmentChangeRow, 0, len(history.Diff.Changes))
+		for _, change := range history.Diff.Changes {
+			changes = append(changes, components.EnvironmentChangeRow{
+				Service:     change.Service,
+				Environment: change.Environment,
+				Kind:        change.Kind,
+				Before:      mapEnvironmentMatrixCell(change.Before),
+				After:       mapEnvironmentMatrixCell(change.After),
+			})
+		}
+	}
+	return c.Render(http.StatusOK, "", pages.HistoryPage(filter, matrix, changes, history.Diff != nil))
+}
+
+func (v *ViewRoutes) handleHistoryData(c echo.Context) error {
+	history, err := v.loadEnvironmentHistory(c)
+	if err != nil || history == nil {
+		return err
+	}
+
+	out := environmentHistoryJSON{
+		At:           history.Matrix.At,
+		Environments: history.Matrix.Environments,
+		States:       []environmentStateJSON{},
+	}
+	if out.Environments == nil {
+		out.Environments = []string{}
+	}
+	for _, row := range history.Matrix.Rows {
+		for _, state := range row.States {
+			if state.Seq > 0 {
+				out.States = append(out.States, mapEnvironmentStateJSON(state))
+			}
+		}
+	}
+	if history.Diff != nil {
+		out.Compare = &history.Compare
+		out.Changes = make([]environmentChangeJSON, 0, len(history.Diff.Changes))
+		for _, change := range history.Diff.Changes {
+			row := environmentChangeJSON{
+				Service:     change.Service,
+				Environment: change.Environment,
+				Kind:        change.Kind,
+				After:       mapEnvironmentStateJSON(change.After),
+			}
+			if change.Before.Seq > 0 {
+				before := mapEnvironmentStateJSON(change.Before)
+				row.Before = &before
+			}
+			out.Changes = append(out.Changes, row)
+		}
+	}
+	return c.JSON(http.StatusOK, out)
+}
+
+// loadEnvironmentHistory reads the at and compare query parameters. at
+// defaults to now. A nil result with a nil error means a response has
+// already been written.
+func (v *ViewRoutes) loadEnvironmentHistory(c echo.Context) (*environmentHistory, error) {
+	ctx := c.Request().Context()
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return nil, err
+	}
+	if v.history == nil {
+		return nil, c.NoContent(http.StatusServiceUnavailable)
+	}
+	at, err := parseEventFilterTime(c.QueryParam("at"))
+	if err != nil {
+		return nil, c.NoContent(http.StatusBadRequest)
+	}
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+	compare, err := parseEventFilterTime(c.QueryParam("compare"))
+	if err != nil {
+		return nil, c.NoContent(http.StatusBadRequest)
+	}
+
+	matrix, err := v.history.StateAt(ctx, orgID, at)
+	if err != nil {
+		return nil, err
+	}
+	history := &environmentHistory{Matrix: matrix, Compare: compare}
+	if !compare.IsZero() {
+		diff, err := v.history.Diff(ctx, orgID, compare, at)
+		if err != nil {
+			return nil, err
+		}
+		history.Diff = &diff
+	}
+	return history, nil
+}
+
+func mapEnvironmentMatrixCell(state appcatalog.EnvironmentState) components.EnvironmentMatrixCell {
+	if state.Seq == 0 {
+		return components.EnvironmentMatrixCell{}
+	}
+	return components.EnvironmentMatrixCell{
+		ArtifactID: state.ArtifactID,
+		Status:     state.Status,
+		Actor:      state.Actor,
+		DeployedAt: state.DeployedAt.Format("2006-01-02 15:04 UTC"),
+	}
+}
+
+func mapEnvironmentStateJSON(state appcatalog.EnvironmentState) environmentStateJSON {
+	return environmentStateJSON{
+		Service:     state.Service,
+		Environment: state.Environment,
+		ArtifactID:  state.ArtifactID,
+		Status:      state.Status,
+		Actor:       state.Actor,
+		EventType:   state.EventType,
+		Seq:         state.Seq,
+		DeployedAt:  state.DeployedAt,
+	}
+}
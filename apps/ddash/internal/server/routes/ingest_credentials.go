@@ -0,0 +1,138 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	apporgconfig "github.com/fr0stylo/ddash/apps/ddash/internal/application/orgconfig"
+	"github.com/fr0stylo/ddash/views/components"
+)
+
+const maxIngestCredentialHours = 24 * 365
+
+func (v *ViewRoutes) handleIngestCredentialCreate(c echo.Context) error {
+	ctx := c.Request().Context()
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	if err := v.requireOrganizationAdmin(c, orgID); err != nil {
+		return err
+	}
+	if v.credentials == nil {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+	validity, ok := parseCredentialHours(c.FormValue("validHours"))
+	if !ok {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	if _, err := v.credentials.Create(ctx, orgID, c.FormValue("label"), validity); err != nil {
+		if errors.Is(err, apporgconfig.ErrIngestCredentialLabelRequired) {
+			return c.NoContent(http.StatusBadRequest)
+		}
+		return err
+	}
+	return c.Redirect(http.StatusFound, "/settings")
+}
+
+func (v *ViewRoutes) handleIngestCredentialExpire(c echo.Context) error {
+	ctx := c.Request().Context()
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	if err := v.requireOrganizationAdmin(c, orgID); err != nil {
+		return err
+	}
+	if v.credentials == nil {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+	id, err := strconv.ParseInt(strings.TrimSpace(c.FormValue("id")), 10, 64)
+	if err != nil || id <= 0 {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	grace, ok := parseCredentialHours(c.FormValue("graceHours"))
+	if !ok {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	if err := v.credentials.Expire(ctx, orgID, id, grace); err != nil {
+		if errors.Is(err, apporgconfig.ErrIngestCredentialNotFound) {
+			return c.NoContent(http.StatusNotFound)
+		}
+		return err
+	}
+	return c.Redirect(http.StatusFound, "/settings")
+}
+
+func (v *ViewRoutes) handleIngestCredentialRevoke(c echo.Context) error {
+	ctx := c.Request().Context()
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	if err := v.requireOrganizationAdmin(c, orgID); err != nil {
+		return err
+	}
+	if v.credentials == nil {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+	id, err := strconv.ParseInt(strings.TrimSpace(c.FormValue("id")), 10, 64)
+	if err != nil || id <= 0 {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	if err := v.credentials.Revoke(ctx, orgID, id); err != nil {
+		if errors.Is(err, apporgconfig.ErrIngestCredentialNotFound) {
+			return c.NoContent(http.StatusNotFound)
+		}
+		return err
+	}
+	return c.Redirect(http.StatusFound, "/settings")
+}
+
+// parseCredentialHours reads an optional whole number of hours; blank means zero.
+func parseCredentialHours(raw string) (time.Duration, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, true
+	}
+	hours, err := strconv.Atoi(raw)
+	if err != nil || hours < 0 || hours > maxIngestCredentialHours {
+		return 0, false
+	}
+	return time.Duration(hours) * time.Hour, true
+}
+
+func mapIngestCredentials(rows []apporgconfig.IngestCredential, now time.Time) []components.IngestCredential {
+	const layout = "2006-01-02 15:04 UTC"
+	out := make([]components.IngestCredential, 0, len(rows))
+	for _, row := range rows {
+		item := components.IngestCredential{
+			ID:            row.ID,
+			Label:         row.Label,
+			AuthToken:     row.AuthToken,
+			WebhookSecret: row.WebhookSecret,
+			CreatedAt:     row.CreatedAt.Format(layout),
+			Active:        row.Active(now),
+			Status:        "active",
+		}
+		if row.ExpiresAt != nil {
+			item.ExpiresAt = row.ExpiresAt.Format(layout)
+		}
+		if row.LastUsedAt != nil {
+			item.LastUsedAt = row.LastUsedAt.Format(layout)
+		}
+		switch {
+		case row.RevokedAt != nil:
+			item.Status = "revoked"
+		case !item.Active:
+			item.Status = "expired"
+		}
+		out = append(out, item)
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+	"github.com/fr0stylo/ddash/apps/ddash/internal/renderer"
+)
+
+type ingestCredentialStoreFake struct {
+	revoked []int64
+}
+
+func (f *ingestCredentialStoreFake) ListIngestCredentials(context.Context, int64) ([]ports.IngestCredential, error) {
+	return nil, nil
+}
+
+func (f *ingestCredentialStoreFake) CreateIngestCredential(_ context.Context, credential ports.IngestCredential) (ports.IngestCredential, error) {
+	return credential, nil
+}
+
+func (f *ingestCredentialStoreFake) ExpireIngestCredential(context.Context, int64, int64, time.Time) (bool, error) {
+	return true, nil
+}
+
+func (f *ingestCredentialStoreFake) RevokeIngestCredential(_ context.Context, _, id int64, _ time.Time) (bool, error) {
+	f.revoked = append(f.revoked, id)
+	return true, nil
+}
+
+func TestHandleIngestCredentialRevokeRequiresOrganizationAdmin(t *testing.T) {
+	initAuthStoreForTests()
+	e := echo.New()
+	e.Renderer = &renderer.Renderer{}
+
+	for role, wantRevoked := range map[string]bool{"member": false, "admin": true} {
+		store := &orgRouteStoreFake{org: ports.Organization{ID: 1, Name: "org-a", Enabled: true}, roleByUserID: map[int64]string{10: role}}
+		credentials := &ingestCredentialStoreFake{}
+		v := NewViewRoutes(store, nil, store, ViewExternalConfig{IngestCredentials: credentials})
+
+		form := url.Values{}
+		form.Set("id", "3")
+		c, rec := newAuthedContext(t, e, http.MethodPost, "/settings/credentials/revoke", form)
+		err := v.handleIngestCredentialRevoke(c)
+		if wantRevoked && err != nil {
+			t.Fatalf("%s: handler error: %v", role, err)
+		}
+		if !wantRevoked && !errors.Is(err, errOrganizationAdminRequired) {
+			t.Fatalf("%s: expected admin access error, got %v", role, err)
+		}
+		if got := len(credentials.revoked) == 1; got != wantRevoked {
+			t.Fatalf("%s: revoked=%v, want %v", role, credentials.revoked, wantRevoked)
+		}
+		if rec.Code != http.StatusFound {
+			t.Fatalf("%s: expected redirect, got %d", role, rec.Code)
+		}
+		if !wantRevoked && !strings.Contains(rec.Header().Get("Location"), "/organizations") {
+			t.Fatalf("%s: expected redirect to organizations, got %q", role, rec.Header().Get("Location"))
+		}
+	}
+}
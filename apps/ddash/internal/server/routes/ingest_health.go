@@ -0,0 +1,78 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	appingestion "github.com/fr0stylo/ddash/apps/ddash/internal/application/ingestion"
+	"github.com/fr0stylo/ddash/views/components"
+	"github.com/fr0stylo/ddash/views/pages"
+)
+
+// ingestSourceStaleAfter flags sources that have not reported for a while.
+const ingestSourceStaleAfter = 24 * time.Hour
+
+func (v *ViewRoutes) handleIngestHealth(c echo.Context) error {
+	ctx := c.Request().Context()
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	if v.ingestHealth == nil {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+	health, err := v.ingestHealth.Get(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, "", pages.IngestHealthPage(mapIngestHealth(health, time.Now().UTC()), v.storageViews()))
+}
+
+func mapIngestHealth(health appingestion.Health, now time.Time) components.IngestHealth {
+	out := components.IngestHealth{
+		Accepted:   health.Totals.Accepted,
+		Duplicates: health.Totals.Duplicates,
+		Dropped:    health.Totals.Dropped,
+		Rejected:   health.Totals.Rejected,
+		Hours:      make([]components.IngestHealthHour, 0, len(health.Hours)),
+		Rejections: make([]components.IngestRejection, 0, len(health.Rejections)),
+		Sources:    make([]components.IngestSource, 0, len(health.Sources)),
+	}
+	var peak int64
+	for _, hour := range health.Hours {
+		if total := hour.Total(); total > peak {
+			peak = total
+		}
+	}
+	for i := len(health.Hours) - 1; i >= 0; i-- {
+		hour := health.Hours[i]
+		width := "0%"
+		if peak > 0 {
+			width = fmt.Sprintf("%d%%", hour.Total()*100/peak)
+		}
+		out.Hours = append(out.Hours, components.IngestHealthHour{
+			Label:      hour.Start.Format("15:04"),
+			Accepted:   hour.Accepted,
+			Duplicates: hour.Duplicates,
+			Dropped:    hour.Dropped,
+			Rejected:   hour.Rejected,
+			BarWidth:   width,
+		})
+	}
+	for _, item := range health.Rejections {
+		out.Rejections = append(out.Rejections, components.IngestRejection{Reason: item.Reason, Count: item.Count})
+	}
+	for _, item := range health.Sources {
+		out.Sources = append(out.Sources, components.IngestSource{
+			Source:        item.Source,
+			LastEventType: item.LastEventType,
+			LastEventID:   item.LastEventID,
+			LastSeen:      item.LastSeenAt.Format("2006-01-02 15:04:05 UTC"),
+			Stale:         now.Sub(item.LastSeenAt) > ingestSourceStaleAfter,
+		})
+	}
+	return out
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/labstack/echo/v4"
 
 	appidentity "github.com/fr0stylo/ddash/apps/ddash/internal/application/identity"
+	appcatalog "github.com/fr0stylo/ddash/apps/ddash/internal/application/servicecatalog"
 	"github.com/fr0stylo/ddash/views/pages"
 )
 
@@ -80,10 +81,12 @@ func (v *ViewRoutes) handleOrganizations(c echo.Context) error {
 	items := make([]pages.OrganizationRow, 0, len(rows))
 	for _, row := range rows {
 		items = append(items, pages.OrganizationRow{
-			ID:      row.ID,
-			Name:    row.Name,
-			Enabled: row.Enabled,
-			Active:  row.ID == activeID,
+			ID:         row.ID,
+			Name:       row.Name,
+			Enabled:    row.Enabled,
+			Active:     row.ID == activeID,
+			CanRebuild: v.projections != nil,
+			Rebuilding: v.projections != nil && v.projections.Running(row.ID),
 		})
 	}
 	activeOrg, err := v.orgs.GetOrganizationByID(ctx, activeID)
@@ -407,18 +410,50 @@ func (v *ViewRoutes) handleOrganizationJoinRequestReject(c echo.Context) error {
 	return c.Redirect(http.StatusFound, organizationsMembersRedirectURL("Join request rejected", "success"))
 }
 
+func (v *ViewRoutes) handleOrganizationProjectionRebuild(c echo.Context) error {
+	ctx := c.Request().Context()
+	id, err := strconv.ParseInt(strings.TrimSpace(c.FormValue("organizationID")), 10, 64)
+	if err != nil || id <= 0 {
+		return c.Redirect(http.StatusFound, organizationsRedirectURL("Invalid organization id", "error"))
+	}
+	if err := v.requireOrganizationAdmin(c, id); err != nil {
+		return err
+	}
+	if v.projections == nil {
+		return c.Redirect(http.StatusFound, organizationsRedirectURL("Projection rebuild is not available", "error"))
+	}
+	if err := v.projections.Start(ctx, id); err != nil {
+		if errors.Is(err, appcatalog.ErrProjectionRebuildRunning) {
+			return c.Redirect(http.StatusFound, organizationsRedirectURL("Projection rebuild already running", "error"))
+		}
+		return err
+	}
+	return c.Redirect(http.StatusFound, organizationsRedirectURL("Projection rebuild started", "success"))
+}
+
+// errOrganizationAdminRequired stops a handler once requireOrganizationAdmin
+// has redirected the request; the response is already committed, so echo
+// does not write it again.
+var errOrganizationAdminRequired = errors.New("organization admin access required")
+
 func (v *ViewRoutes) requireOrganizationAdmin(c echo.Context, organizationID int64) error {
 	ctx := c.Request().Context()
 	userID, ok := GetAuthUserID(c)
 	if !ok || userID <= 0 {
-		return c.Redirect(http.StatusFound, "/login")
+		if err := c.Redirect(http.StatusFound, "/login"); err != nil {
+			return err
+		}
+		return errOrganizationAdminRequired
 	}
 	canManage, err := v.orgs.CanManageOrganization(ctx, organizationID, userID)
 	if err != nil {
 		return err
 	}
 	if !canManage {
-		return c.Redirect(http.StatusFound, organizationsRedirectURL("Organization admin access required", "error"))
+		if err := c.Redirect(http.StatusFound, organizationsRedirectURL("Organization admin access required", "error")); err != nil {
+			return err
+		}
+		return errOrganizationAdminRequired
 	}
 	return nil
 }
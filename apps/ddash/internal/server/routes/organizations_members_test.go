@@ -147,6 +147,10 @@ func (f *orgRouteStoreFake) ListOrganizationPreferences(context.Context, int64)
 	return nil, nil
 }
 
+func (f *orgRouteStoreFake) ListOrganizationEventPolicies(context.Context, int64) ([]ports.EventSubjectPolicy, error) {
+	return nil, nil
+}
+
 func (f *orgRouteStoreFake) ListDistinctServiceEnvironmentsFromEvents(context.Context, int64) ([]string, error) {
 	return nil, nil
 }
@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/fr0stylo/ddash/views/components"
+	"github.com/fr0stylo/ddash/views/pages"
+)
+
+func (v *ViewRoutes) handleProjectors(c echo.Context) error {
+	ctx := c.Request().Context()
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	if err := v.requireOrganizationAdmin(c, orgID); err != nil {
+		return err
+	}
+	if v.projectors == nil {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+	statuses, err := v.projectors.List(ctx)
+	if err != nil {
+		return err
+	}
+	rows := make([]components.ProjectorRow, 0, len(statuses))
+	for _, status := range statuses {
+		updatedAt := "Never"
+		if !status.UpdatedAt.IsZero() {
+			updatedAt = status.UpdatedAt.Format("2006-01-02 15:04:05 UTC")
+		}
+		rows = append(rows, components.ProjectorRow{
+			Name:      status.Name,
+			LastSeq:   status.LastSeq,
+			HeadSeq:   status.HeadSeq,
+			Lag:       status.Lag(),
+			UpdatedAt: updatedAt,
+		})
+	}
+	return c.Render(http.StatusOK, "", pages.ProjectorsPage(rows))
+}
@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
@@ -48,8 +49,16 @@ func (m *mockServiceReadStore) ListServiceInstances(ctx context.Context, organiz
 	return m.MockServiceQueryStore.ListServiceInstances(ctx, organizationID, env)
 }
 
-func (m *mockServiceReadStore) ListDeployments(ctx context.Context, organizationID int64, env, service string) ([]domain.DeploymentRow, error) {
-	return m.MockServiceQueryStore.ListDeployments(ctx, organizationID, env, service)
+func (m *mockServiceReadStore) ListDeployments(ctx context.Context, organizationID int64, filter ports.DeploymentFilter) ([]domain.DeploymentRow, error) {
+	return m.MockServiceQueryStore.ListDeployments(ctx, organizationID, filter)
+}
+
+func (m *mockServiceReadStore) ListDeploymentEnvironmentCounts(ctx context.Context, organizationID int64, now time.Time) ([]ports.DeploymentEnvironmentCount, error) {
+	return m.MockServiceQueryStore.ListDeploymentEnvironmentCounts(ctx, organizationID, now)
+}
+
+func (m *mockServiceReadStore) ListDeploymentServices(ctx context.Context, organizationID int64) ([]string, error) {
+	return m.MockServiceQueryStore.ListDeploymentServices(ctx, organizationID)
 }
 
 func (m *mockServiceReadStore) GetServiceLatest(ctx context.Context, organizationID int64, name string) (ports.ServiceLatest, error) {
@@ -84,6 +93,14 @@ func (m *mockServiceReadStore) GetOrganizationRenderVersion(ctx context.Context,
 	return m.MockServiceQueryStore.GetOrganizationRenderVersion(ctx, organizationID)
 }
 
+func (m *mockServiceReadStore) GetLatestEventSeq(ctx context.Context, organizationID int64) (int64, error) {
+	return m.MockServiceQueryStore.GetLatestEventSeq(ctx, organizationID)
+}
+
+func (m *mockServiceReadStore) ListServiceChangesAfterSeq(ctx context.Context, organizationID int64, afterSeq int64, limit int64) ([]ports.ServiceChange, error) {
+	return m.MockServiceQueryStore.ListServiceChangesAfterSeq(ctx, organizationID, afterSeq, limit)
+}
+
 func (m *mockServiceReadStore) ListRequiredFields(ctx context.Context, organizationID int64) ([]ports.RequiredField, error) {
 	return m.MockServiceMetadataStore.ListRequiredFields(ctx, organizationID)
 }
@@ -152,8 +169,8 @@ func (m *mockServiceReadStore) GetArtifactAgeByEnvironment(ctx context.Context,
 	return m.MockServiceAnalyticsStore.GetArtifactAgeByEnvironment(ctx, organizationID, service)
 }
 
-func (m *mockServiceReadStore) GetMTTR(ctx context.Context, organizationID int64, sinceMs int64) (ports.MTTRStats, error) {
-	return m.MockServiceAnalyticsStore.GetMTTR(ctx, organizationID, sinceMs)
+func (m *mockServiceReadStore) GetMTTR(ctx context.Context, organizationID int64, service string, sinceMs int64) (ports.MTTRStats, error) {
+	return m.MockServiceAnalyticsStore.GetMTTR(ctx, organizationID, service, sinceMs)
 }
 
 func (m *mockServiceReadStore) ListIncidentLinks(ctx context.Context, organizationID int64, service string, limit int64) ([]ports.IncidentLink, error) {
@@ -0,0 +1,125 @@
+package routes
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	apporgconfig "github.com/fr0stylo/ddash/apps/ddash/internal/application/orgconfig"
+	"github.com/fr0stylo/ddash/views/components"
+	"github.com/fr0stylo/ddash/views/pages"
+)
+
+func (v *ViewRoutes) handleServiceIdentities(c echo.Context) error {
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	if err := v.requireOrganizationAdmin(c, orgID); err != nil {
+		return err
+	}
+	return v.renderServiceIdentities(c, orgID, http.StatusOK, "")
+}
+
+func (v *ViewRoutes) handleServiceIdentityCreate(c echo.Context) error {
+	ctx := c.Request().Context()
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	if err := v.requireOrganizationAdmin(c, orgID); err != nil {
+		return err
+	}
+	if v.identities == nil {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+	if _, err := v.identities.Create(ctx, orgID, c.FormValue("kind"), c.FormValue("pattern"), c.FormValue("service")); err != nil {
+		if errors.Is(err, apporgconfig.ErrServiceIdentityRuleInvalid) || errors.Is(err, apporgconfig.ErrServiceIdentityRuleExists) {
+			return v.renderServiceIdentities(c, orgID, http.StatusBadRequest, err.Error())
+		}
+		return err
+	}
+	v.rebuildAfterIdentityChange(c, orgID)
+	return c.Redirect(http.StatusFound, "/settings/service-identities")
+}
+
+func (v *ViewRoutes) handleServiceIdentityDelete(c echo.Context) error {
+	ctx := c.Request().Context()
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	if err := v.requireOrganizationAdmin(c, orgID); err != nil {
+		return err
+	}
+	if v.identities == nil {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+	id, err := strconv.ParseInt(strings.TrimSpace(c.FormValue("id")), 10, 64)
+	if err != nil || id <= 0 {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	if err := v.identities.Delete(ctx, orgID, id); err != nil {
+		if errors.Is(err, apporgconfig.ErrServiceIdentityRuleNotFound) {
+			return c.NoContent(http.StatusNotFound)
+		}
+		return err
+	}
+	v.rebuildAfterIdentityChange(c, orgID)
+	return c.Redirect(http.StatusFound, "/settings/service-identities")
+}
+
+// rebuildAfterIdentityChange regroups history under the new names. Events
+// appended meanwhile are projected with the new rules already.
+func (v *ViewRoutes) rebuildAfterIdentityChange(c echo.Context, orgID int64) {
+	if v.projections != nil {
+		v.projections.Schedule(c.Request().Context(), orgID)
+	}
+}
+
+func (v *ViewRoutes) renderServiceIdentities(c echo.Context, orgID int64, status int, errorMessage string) error {
+	ctx := c.Request().Context()
+	if v.identities == nil {
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+	rules, err := v.identities.List(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	aliases, err := v.identities.Aliases(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	rebuilding := v.projections != nil && v.projections.Running(orgID)
+	return c.Render(status, "", pages.ServiceIdentitiesPage(mapServiceIdentityRules(rules), mapServiceIdentityAliases(aliases), errorMessage, rebuilding, csrfToken(c)))
+}
+
+func mapServiceIdentityRules(rows []apporgconfig.ServiceIdentityRule) []components.ServiceIdentityRule {
+	out := make([]components.ServiceIdentityRule, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, components.ServiceIdentityRule{
+			ID:          row.ID,
+			Kind:        row.Kind,
+			Pattern:     row.Pattern,
+			ServiceName: row.ServiceName,
+			CreatedAt:   row.CreatedAt.Format("2006-01-02 15:04 UTC"),
+		})
+	}
+	return out
+}
+
+func mapServiceIdentityAliases(rows []apporgconfig.ServiceIdentityAlias) []components.ServiceIdentityAlias {
+	out := make([]components.ServiceIdentityAlias, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, components.ServiceIdentityAlias{
+			Alias:       row.Alias,
+			ServiceName: row.ServiceName,
+			ServiceURL:  "/s/" + url.PathEscape(row.ServiceName),
+		})
+	}
+	return out
+}
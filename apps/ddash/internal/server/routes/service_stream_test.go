@@ -0,0 +1,98 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	mock "github.com/stretchr/testify/mock"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/domain"
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+)
+
+func TestHandleServiceStreamDisabledByFeatureFlag(t *testing.T) {
+	initAuthStoreForTests()
+	e := echo.New()
+
+	store := &orgRouteStoreFake{
+		org:      ports.Organization{ID: 1, Name: "org-a", Enabled: true},
+		features: []ports.OrganizationFeature{{Key: "enable_sse_live_updates", Enabled: false}},
+	}
+	readStore := newMockServiceReadStore(t)
+	v := NewViewRoutes(store, readStore, store, ViewExternalConfig{})
+
+	c, rec := newAuthedContext(t, e, http.MethodGet, "/services/stream?view=grid", nil)
+	if err := v.handleServiceStream(c); err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 when live updates are disabled, got %d", rec.Code)
+	}
+}
+
+func TestHandleServiceStreamResumesFromLastEventID(t *testing.T) {
+	initAuthStoreForTests()
+	e := echo.New()
+
+	store := &orgRouteStoreFake{org: ports.Organization{ID: 1, Name: "org-a", Enabled: true}}
+	readStore := newMockServiceReadStore(t)
+	readStore.MockServiceQueryStore.On("ListServiceChangesAfterSeq", mock.Anything, int64(1), int64(5), int64(500)).
+		Return([]ports.ServiceChange{{OrganizationID: 1, ServiceName: "orders", Seq: 7}}, nil)
+	readStore.MockServiceQueryStore.On("ListServiceInstances", mock.Anything, int64(1), "all").
+		Return([]domain.Service{
+			{Title: "orders", Environment: "production", Status: domain.ServiceStatusSynced},
+			{Title: "billing", Environment: "production", Status: domain.ServiceStatusSynced},
+		}, nil)
+	readStore.MockServiceMetadataStore.On("ListRequiredFields", mock.Anything, int64(1)).Return(nil, nil)
+	readStore.MockServiceMetadataStore.On("ListServiceMetadataValuesByOrganization", mock.Anything, int64(1)).Return(nil, nil)
+
+	v := NewViewRoutes(store, readStore, store, ViewExternalConfig{})
+
+	c, _ := newAuthedContext(t, e, http.MethodGet, "/services/stream?view=grid", nil)
+	c.Request().Header.Set("Last-Event-ID", "5")
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+	c.SetRequest(c.Request().WithContext(ctx))
+	rec := &flushSignalRecorder{ResponseRecorder: httptest.NewRecorder(), flushed: make(chan struct{}, 4)}
+	c.Response().Writer = rec
+
+	done := make(chan error, 1)
+	go func() { done <- v.handleServiceStream(c) }()
+	// The first flush sends headers, the second one carries the catch-up events.
+	for range 2 {
+		select {
+		case <-rec.flushed:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for stream flush")
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "id: 7\nevent: service-card-update-orders\n") {
+		t.Fatalf("expected orders card update with resume id, got %q", body)
+	}
+	if strings.Contains(body, "billing") {
+		t.Fatalf("unchanged service should not be re-rendered, got %q", body)
+	}
+	if strings.Contains(body, "service-row-update") {
+		t.Fatalf("grid stream should not emit row updates, got %q", body)
+	}
+}
+
+type flushSignalRecorder struct {
+	*httptest.ResponseRecorder
+	flushed chan struct{}
+}
+
+func (r *flushSignalRecorder) Flush() {
+	r.ResponseRecorder.Flush()
+	r.flushed <- struct{}{}
+}
@@ -2,36 +2,46 @@ package routes
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
 	apporgconfig "github.com/fr0stylo/ddash/apps/ddash/internal/application/orgconfig"
+	"github.com/fr0stylo/ddash/views/components"
 	"github.com/fr0stylo/ddash/views/pages"
 )
 
 type settingsPayload struct {
-	AuthToken                   string               `json:"authToken"`
-	WebhookSecret               string               `json:"webhookSecret"`
-	Enabled                     bool                 `json:"enabled"`
-	ShowSyncStatus              bool                 `json:"showSyncStatus"`
-	ShowMetadataBadges          bool                 `json:"showMetadataBadges"`
-	ShowEnvironmentColumn       bool                 `json:"showEnvironmentColumn"`
-	EnableSSELiveUpdates        bool                 `json:"enableSSELiveUpdates"`
-	ShowDeploymentHistory       bool                 `json:"showDeploymentHistory"`
-	ShowMetadataFilters         bool                 `json:"showMetadataFilters"`
-	StrictMetadataEnforcement   bool                 `json:"strictMetadataEnforcement"`
-	MaskSensitiveMetadataValues bool                 `json:"maskSensitiveMetadataValues"`
-	AllowServiceMetadataEditing bool                 `json:"allowServiceMetadataEditing"`
-	ShowOnboardingHints         bool                 `json:"showOnboardingHints"`
-	ShowIntegrationTypeBadges   bool                 `json:"showIntegrationTypeBadges"`
-	ShowServiceDetailInsights   bool                 `json:"showServiceDetailInsights"`
-	ShowServiceDependencies     bool                 `json:"showServiceDependencies"`
-	ShowServiceDeliveryMetrics  bool                 `json:"showServiceDeliveryMetrics"`
-	DeploymentRetentionDays     int                  `json:"deploymentRetentionDays"`
-	DefaultDashboardView        string               `json:"defaultDashboardView"`
-	StatusSemanticsMode         string               `json:"statusSemanticsMode"`
-	RequiredFields              []settingsFieldInput `json:"requiredFields"`
-	EnvironmentOrder            []string             `json:"environmentOrder"`
+	AuthToken                   string                `json:"authToken"`
+	WebhookSecret               string                `json:"webhookSecret"`
+	Enabled                     bool                  `json:"enabled"`
+	ShowSyncStatus              bool                  `json:"showSyncStatus"`
+	ShowMetadataBadges          bool                  `json:"showMetadataBadges"`
+	ShowEnvironmentColumn       bool                  `json:"showEnvironmentColumn"`
+	EnableSSELiveUpdates        bool                  `json:"enableSSELiveUpdates"`
+	ShowDeploymentHistory       bool                  `json:"showDeploymentHistory"`
+	ShowMetadataFilters         bool                  `json:"showMetadataFilters"`
+	StrictMetadataEnforcement   bool                  `json:"strictMetadataEnforcement"`
+	MaskSensitiveMetadataValues bool                  `json:"maskSensitiveMetadataValues"`
+	AllowServiceMetadataEditing bool                  `json:"allowServiceMetadataEditing"`
+	ShowOnboardingHints         bool                  `json:"showOnboardingHints"`
+	ShowIntegrationTypeBadges   bool                  `json:"showIntegrationTypeBadges"`
+	ShowServiceDetailInsights   bool                  `json:"showServiceDetailInsights"`
+	ShowServiceDependencies     bool                  `json:"showServiceDependencies"`
+	ShowServiceDeliveryMetrics  bool                  `json:"showServiceDeliveryMetrics"`
+	DeploymentRetentionDays     int                   `json:"deploymentRetentionDays"`
+	EventRetentionDays          int                   `json:"eventRetentionDays"`
+	DefaultDashboardView        string                `json:"defaultDashboardView"`
+	StatusSemanticsMode         string                `json:"statusSemanticsMode"`
+	WebhookSignatureScheme      string                `json:"webhookSignatureScheme"`
+	RequiredFields              []settingsFieldInput  `json:"requiredFields"`
+	EnvironmentOrder            []string              `json:"environmentOrder"`
+	EventPolicies               []settingsEventPolicy `json:"eventPolicies"`
+}
+
+type settingsEventPolicy struct {
+	Subject string `json:"subject"`
+	Mode    string `json:"mode"`
 }
 
 type settingsFieldInput struct {
@@ -50,10 +60,19 @@ func (v *ViewRoutes) handleSettings(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	var credentials []components.IngestCredential
+	if v.credentials != nil {
+		rows, err := v.credentials.List(ctx, orgID)
+		if err != nil {
+			return err
+		}
+		credentials = mapIngestCredentials(rows, time.Now().UTC())
+	}
 
 	return c.Render(http.StatusOK, "", pages.SettingsPage(
 		mapDomainMetadataFields(settings.RequiredFields),
 		settings.EnvironmentOrder,
+		mapEventPolicies(settings.EventPolicies),
 		settings.AuthToken,
 		settings.WebhookSecret,
 		settings.Enabled,
@@ -72,12 +91,27 @@ func (v *ViewRoutes) handleSettings(c echo.Context) error {
 		settings.ShowServiceDeliveryMetrics,
 		settings.ShowServiceDependencies,
 		settings.DeploymentRetentionDays,
+		settings.EventRetentionDays,
 		settings.DefaultDashboardView,
 		settings.StatusSemanticsMode,
+		settings.WebhookSignatureScheme,
+		credentials,
+		v.credentials != nil,
+		v.storageViews(),
 		csrfToken(c),
 	))
 }
 
+// storageViews reports which storage-backed pages the current backend serves.
+func (v *ViewRoutes) storageViews() components.StorageViews {
+	return components.StorageViews{
+		Events:            v.events != nil,
+		Projectors:        v.projectors != nil,
+		ServiceIdentities: v.identities != nil,
+		Environments:      v.environments != nil,
+	}
+}
+
 func (v *ViewRoutes) handleSettingsUpdate(c echo.Context) error {
 	ctx := c.Request().Context()
 	orgID, err := v.currentOrganizationID(c)
@@ -108,8 +142,10 @@ func (v *ViewRoutes) handleSettingsUpdate(c echo.Context) error {
 		ShowServiceDependencies:     payload.ShowServiceDependencies,
 		ShowServiceDeliveryMetrics:  payload.ShowServiceDeliveryMetrics,
 		DeploymentRetentionDays:     payload.DeploymentRetentionDays,
+		EventRetentionDays:          payload.EventRetentionDays,
 		DefaultDashboardView:        payload.DefaultDashboardView,
 		StatusSemanticsMode:         payload.StatusSemanticsMode,
+		WebhookSignatureScheme:      payload.WebhookSignatureScheme,
 		EnvironmentOrder:            payload.EnvironmentOrder,
 		RequiredFields:              make([]apporgconfig.RequiredFieldInput, 0, len(payload.RequiredFields)),
 	}
@@ -121,6 +157,13 @@ func (v *ViewRoutes) handleSettingsUpdate(c echo.Context) error {
 		})
 	}
 
+	for _, policy := range payload.EventPolicies {
+		update.EventPolicies = append(update.EventPolicies, apporgconfig.EventSubjectPolicy{
+			Subject: policy.Subject,
+			Mode:    policy.Mode,
+		})
+	}
+
 	err = v.config.UpdateSettings(ctx, orgID, update)
 	if err != nil {
 		return err
@@ -128,3 +171,11 @@ func (v *ViewRoutes) handleSettingsUpdate(c echo.Context) error {
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+func mapEventPolicies(policies []apporgconfig.EventSubjectPolicy) []components.EventPolicy {
+	out := make([]components.EventPolicy, 0, len(policies))
+	for _, policy := range policies {
+		out = append(out, components.EventPolicy{Subject: policy.Subject, Mode: policy.Mode})
+	}
+	return out
+}
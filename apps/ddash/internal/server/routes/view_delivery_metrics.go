@@ -128,6 +128,12 @@ func (v *ViewRoutes) handleServiceMetricsFragment(c echo.Context) error {
 			}
 			return result
 		}(),
+		Incidents: components.IncidentStatsData{
+			IncidentCount:     metrics.Incidents.IncidentCount,
+			OpenIncidentCount: metrics.Incidents.OpenIncidentCount,
+			MTTRSeconds:       metrics.Incidents.MTTRSeconds,
+			MTTDSeconds:       metrics.Incidents.MTTDSeconds,
+		},
 		Comprehensive: components.ComprehensiveData{
 			LeadTimeSeconds:              metrics.Comprehensive.LeadTimeSeconds,
 			DeploymentFrequency30d:       metrics.Comprehensive.DeploymentFrequency30d,
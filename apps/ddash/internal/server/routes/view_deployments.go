@@ -3,10 +3,15 @@ package routes
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/domain"
+	appcatalog "github.com/fr0stylo/ddash/apps/ddash/internal/application/servicecatalog"
 	"github.com/fr0stylo/ddash/apps/ddash/internal/renderer"
 	"github.com/fr0stylo/ddash/views/components"
 	"github.com/fr0stylo/ddash/views/pages"
@@ -18,7 +23,15 @@ func (v *ViewRoutes) handleDeployments(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	deployments, metadataOptions, err := v.read.GetDeployments(ctx, orgID, "", "")
+	filter, err := parseDeploymentFilter(c)
+	if err != nil {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	deployments, metadataOptions, err := v.read.GetDeployments(ctx, orgID, filter)
+	if err != nil {
+		return err
+	}
+	environmentCounts, services, err := v.read.GetDeploymentOverview(ctx, orgID, time.Now())
 	if err != nil {
 		return err
 	}
@@ -26,18 +39,43 @@ func (v *ViewRoutes) handleDeployments(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	return c.Render(http.StatusOK, "", pages.DeploymentsPage(mapDomainDeployments(deployments), mapDomainMetadataOptions(metadataOptions), settings.ShowSyncStatus, settings.ShowEnvironmentColumn, settings.ShowMetadataFilters, settings.EnableSSELiveUpdates, settings.StatusSemanticsMode))
+	return c.Render(http.StatusOK, "", pages.DeploymentsPage(mapDeploymentFilter(filter), mapDomainDeployments(deployments.Rows), deploymentRowsURL(filter, deployments.Next), services, mapDeploymentEnvironmentCounts(environmentCounts), mapDomainMetadataOptions(metadataOptions), settings.ShowSyncStatus, settings.ShowEnvironmentColumn, settings.ShowMetadataFilters, settings.EnableSSELiveUpdates, settings.StatusSemanticsMode))
 }
 
 func (v *ViewRoutes) handleDeploymentFilter(c echo.Context) error {
 	ctx := c.Request().Context()
-	env := c.QueryParam("env")
-	service := c.QueryParam("service")
 	orgID, err := v.currentOrganizationID(c)
 	if err != nil {
 		return err
 	}
-	deployments, _, err := v.read.GetDeployments(ctx, orgID, env, service)
+	filter, err := parseDeploymentFilter(c)
+	if err != nil {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	deployments, _, err := v.read.GetDeployments(ctx, orgID, filter)
+	if err != nil {
+		return err
+	}
+	settings, err := v.loadDashboardSettings(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, "", pages.DeploymentResults(mapDeploymentFilter(filter), mapDomainDeployments(deployments.Rows), deploymentRowsURL(filter, deployments.Next), settings.ShowSyncStatus, settings.ShowEnvironmentColumn, settings.EnableSSELiveUpdates, settings.StatusSemanticsMode))
+}
+
+// handleDeploymentRows returns the page of rows below the cursor, for the
+// infinite scroll on the deployments page.
+func (v *ViewRoutes) handleDeploymentRows(c echo.Context) error {
+	ctx := c.Request().Context()
+	orgID, err := v.currentOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	filter, err := parseDeploymentFilter(c)
+	if err != nil {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	deployments, _, err := v.read.GetDeployments(ctx, orgID, filter)
 	if err != nil {
 		return err
 	}
@@ -45,7 +83,7 @@ func (v *ViewRoutes) handleDeploymentFilter(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	return c.Render(http.StatusOK, "", pages.DeploymentResults(mapDomainDeployments(deployments), env, service, settings.ShowSyncStatus, settings.ShowEnvironmentColumn, settings.EnableSSELiveUpdates, settings.StatusSemanticsMode))
+	return c.Render(http.StatusOK, "", pages.DeploymentRows(mapDomainDeployments(deployments.Rows), deploymentRowsURL(filter, deployments.Next), settings.ShowSyncStatus, settings.ShowEnvironmentColumn, settings.StatusSemanticsMode))
 }
 
 func (v *ViewRoutes) handleDeploymentStream(c echo.Context) error {
@@ -68,18 +106,21 @@ func (v *ViewRoutes) handleDeploymentStream(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	filter, err := parseDeploymentFilter(c)
+	if err != nil {
+		return c.NoContent(http.StatusBadRequest)
+	}
+	// The stream only watches the newest page for rows it has not sent yet.
+	filter.Before = appcatalog.DeploymentCursor{}
 	ticker := time.NewTicker(2 * time.Second)
 	defer ticker.Stop()
 
-	envFilter := c.QueryParam("env")
-	serviceFilter := c.QueryParam("service")
-
 	seen := map[string]bool{}
-	initialRows, _, err := v.read.GetDeployments(ctx, orgID, envFilter, serviceFilter)
+	initialRows, _, err := v.read.GetDeployments(ctx, orgID, filter)
 	if err != nil {
 		return err
 	}
-	for _, row := range mapDomainDeployments(initialRows) {
+	for _, row := range mapDomainDeployments(initialRows.Rows) {
 		seen[deploymentEventKey(row)] = true
 	}
 
@@ -88,11 +129,11 @@ func (v *ViewRoutes) handleDeploymentStream(c echo.Context) error {
 		case <-ctx.Done():
 			return nil
 		case <-ticker.C:
-			rows, _, err := v.read.GetDeployments(ctx, orgID, envFilter, serviceFilter)
+			rows, _, err := v.read.GetDeployments(ctx, orgID, filter)
 			if err != nil {
 				return err
 			}
-			uiRows := mapDomainDeployments(rows)
+			uiRows := mapDomainDeployments(rows.Rows)
 			newRows := make([]components.DeploymentRow, 0, len(uiRows))
 			for _, row := range uiRows {
 				key := deploymentEventKey(row)
@@ -120,3 +161,101 @@ func (v *ViewRoutes) handleDeploymentStream(c echo.Context) error {
 func deploymentEventKey(row components.DeploymentRow) string {
 	return fmt.Sprintf("%s|%s|%s|%s", row.DeployedAt, row.Service, row.Environment, row.Status)
 }
+
+// deploymentCursorSeparator joins the cursor parts. It cannot appear in an
+// int64, unlike "-", which a timestamp before 1970 starts with.
+const deploymentCursorSeparator = "_"
+
+// parseDeploymentFilter reads the deployments listing filter. The cursor is
+// "<event_ts_ms>_<seq>" of the last row on the previous page.
+func parseDeploymentFilter(c echo.Context) (appcatalog.DeploymentFilter, error) {
+	filter := appcatalog.DeploymentFilter{
+		Env:     strings.TrimSpace(c.QueryParam("env")),
+		Service: strings.TrimSpace(c.QueryParam("service")),
+	}
+	switch status := strings.TrimSpace(c.QueryParam("status")); status {
+	case "", "all":
+	case string(domain.DeploymentStatusSuccess), string(domain.DeploymentStatusError), string(domain.DeploymentStatusQueued):
+		filter.Status = domain.DeploymentStatus(status)
+	default:
+		return filter, fmt.Errorf("invalid status %q", status)
+	}
+	var err error
+	if filter.From, err = parseEventFilterTime(c.QueryParam("from")); err != nil {
+		return filter, fmt.Errorf("invalid from: %w", err)
+	}
+	if filter.To, err = parseEventFilterTime(c.QueryParam("to")); err != nil {
+		return filter, fmt.Errorf("invalid to: %w", err)
+	}
+	if raw := strings.TrimSpace(c.QueryParam("cursor")); raw != "" {
+		tsRaw, seqRaw, ok := strings.Cut(raw, deploymentCursorSeparator)
+		tsMs, tsErr := strconv.ParseInt(tsRaw, 10, 64)
+		seq, seqErr := strconv.ParseInt(seqRaw, 10, 64)
+		if !ok || tsErr != nil || seqErr != nil || seq <= 0 {
+			return filter, fmt.Errorf("invalid cursor %q", raw)
+		}
+		filter.Before = appcatalog.DeploymentCursor{EventTsMs: tsMs, Seq: seq}
+	}
+	if raw := strings.TrimSpace(c.QueryParam("limit")); raw != "" {
+		if filter.Limit, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			return filter, fmt.Errorf("invalid limit %q", raw)
+		}
+	}
+	return filter, nil
+}
+
+// deploymentRowsURL links to the next page, or is empty on the last page.
+func deploymentRowsURL(filter appcatalog.DeploymentFilter, next appcatalog.DeploymentCursor) string {
+	if next.IsZero() {
+		return ""
+	}
+	query := url.Values{}
+	if filter.Env != "" && filter.Env != "all" {
+		query.Set("env", filter.Env)
+	}
+	if filter.Service != "" && filter.Service != "all" {
+		query.Set("service", filter.Service)
+	}
+	if filter.Status != "" {
+		query.Set("status", string(filter.Status))
+	}
+	if !filter.From.IsZero() {
+		query.Set("from", filter.From.UTC().Format(time.RFC3339))
+	}
+	if !filter.To.IsZero() {
+		query.Set("to", filter.To.UTC().Format(time.RFC3339))
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.FormatInt(filter.Limit, 10))
+	}
+	query.Set("cursor", strconv.FormatInt(next.EventTsMs, 10)+deploymentCursorSeparator+strconv.FormatInt(next.Seq, 10))
+	return "/deployments/rows?" + query.Encode()
+}
+
+func mapDeploymentFilter(filter appcatalog.DeploymentFilter) components.DeploymentFilter {
+	out := components.DeploymentFilter{
+		Env:     filter.Env,
+		Service: filter.Service,
+		Status:  string(filter.Status),
+		From:    formatEventFilterTime(filter.From),
+		To:      formatEventFilterTime(filter.To),
+	}
+	if out.Env == "" {
+		out.Env = "all"
+	}
+	if out.Service == "" {
+		out.Service = "all"
+	}
+	if out.Status == "" {
+		out.Status = "all"
+	}
+	return out
+}
+
+func mapDeploymentEnvironmentCounts(counts []appcatalog.DeploymentEnvironmentCount) []components.DeploymentEnvironmentCount {
+	out := make([]components.DeploymentEnvironmentCount, 0, len(counts))
+	for _, item := range counts {
+		out = append(out, components.DeploymentEnvironmentCount{Environment: item.Environment, Count7d: item.Count7d, Count30d: item.Count30d})
+	}
+	return out
+}
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/domain"
+	appcatalog "github.com/fr0stylo/ddash/apps/ddash/internal/application/servicecatalog"
+)
+
+func TestDeploymentRowsURLRoundTripsThroughParseDeploymentFilter(t *testing.T) {
+	e := echo.New()
+	filter := appcatalog.DeploymentFilter{
+		Env:     "production",
+		Service: "billing",
+		Status:  domain.DeploymentStatusSuccess,
+		From:    time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC),
+		Limit:   25,
+	}
+
+	for _, next := range []appcatalog.DeploymentCursor{
+		{EventTsMs: 1767225600000, Seq: 42},
+		{EventTsMs: -86400000, Seq: 7},
+	} {
+		target := deploymentRowsURL(filter, next)
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, target, nil), httptest.NewRecorder())
+		parsed, err := parseDeploymentFilter(c)
+		if err != nil {
+			t.Fatalf("parse %q: %v", target, err)
+		}
+		if parsed.Before != next {
+			t.Fatalf("cursor from %q = %+v, want %+v", target, parsed.Before, next)
+		}
+		if parsed.Env != filter.Env || parsed.Service != filter.Service || parsed.Status != filter.Status || !parsed.From.Equal(filter.From) || parsed.Limit != filter.Limit {
+			t.Fatalf("filter from %q = %+v, want %+v", target, parsed, filter)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	appservices "github.com/fr0stylo/ddash/apps/ddash/internal/app/services"
 	appgithub "github.com/fr0stylo/ddash/apps/ddash/internal/application/githubintegration"
 	appidentity "github.com/fr0stylo/ddash/apps/ddash/internal/application/identity"
+	appingestion "github.com/fr0stylo/ddash/apps/ddash/internal/application/ingestion"
 	apporgconfig "github.com/fr0stylo/ddash/apps/ddash/internal/application/orgconfig"
 	appcatalog "github.com/fr0stylo/ddash/apps/ddash/internal/application/servicecatalog"
 	"github.com/fr0stylo/ddash/apps/ddash/internal/renderer"
@@ -25,23 +26,113 @@ type ViewRoutes struct {
 	orgs              *appidentity.Service
 	githubIntegration *appgithub.Service
 	fragments         *renderer.FragmentRenderer
+	serviceChanges    *appcatalog.ServiceChangeHub
+	deadLetters       *appingestion.DeadLetterService
+	credentials       *apporgconfig.CredentialService
+	ingestHealth      *appingestion.HealthService
+	projections       *appcatalog.ProjectionRebuildService
+	projectors        *appcatalog.ProjectorService
+	events            *appcatalog.EventExplorerService
+	chains            *appcatalog.ChainService
+	history           *appcatalog.EnvironmentHistoryService
+	identities        *apporgconfig.IdentityService
+	environments      *apporgconfig.EnvironmentService
 }
 
 type ViewExternalConfig struct {
 	PublicURL           string
 	GitHubAppInstallURL string
 	GitHubIngestorToken string
+	// ServiceChanges feeds /services/stream; live updates stay idle when nil.
+	ServiceChanges *appcatalog.ServiceChangeHub
+	// DeadLetters and IngestionStores enable /settings/dead-letters; replay is
+	// unavailable when either is nil.
+	DeadLetters     ports.DeadLetterStore
+	IngestionStores ports.IngestionStoreFactory
+	// IngestCredentials enables managing additional ingest credentials on /settings.
+	IngestCredentials ports.IngestCredentialStore
+	// IngestHealth enables /settings/ingest-health.
+	IngestHealth ports.IngestHealthStore
+	// Projections enables the organization projection rebuild action.
+	Projections ports.ProjectionRebuildStore
+	// Projectors enables /settings/projections.
+	Projectors ports.ProjectorStore
+	// Events enables the /events explorer and its JSON API.
+	Events ports.EventExplorerStore
+	// Chains enables /chains/:id delivery chain views.
+	Chains ports.ChainStore
+	// EnvironmentHistory enables /history and its JSON API.
+	EnvironmentHistory ports.EnvironmentHistoryStore
+	// ServiceIdentities enables /settings/service-identities and redirects
+	// from mapped service names to the service they map onto.
+	ServiceIdentities ports.ServiceIdentityStore
+	// Environments enables /settings/environments.
+	Environments ports.EnvironmentStore
+	// IngestSettings is the webhook settings cache; settings and credential
+	// changes invalidate it.
+	IngestSettings *appingestion.SettingsCache
 }
 
 // NewViewRoutes constructs view routes.
 func NewViewRoutes(configStore ports.AppStore, readStore ports.ServiceReadStore, installStore ports.GitHubInstallationStore, external ViewExternalConfig) *ViewRoutes {
+	var deadLetters *appingestion.DeadLetterService
+	if external.DeadLetters != nil && external.IngestionStores != nil {
+		deadLetters = appingestion.NewDeadLetterService(external.DeadLetters, external.IngestionStores, external.ServiceChanges)
+	}
+	var credentials *apporgconfig.CredentialService
+	if external.IngestCredentials != nil {
+		credentials = apporgconfig.NewCredentialService(external.IngestCredentials, external.IngestSettings)
+	}
+	var ingestHealth *appingestion.HealthService
+	if external.IngestHealth != nil {
+		ingestHealth = appingestion.NewHealthService(external.IngestHealth)
+	}
+	var projections *appcatalog.ProjectionRebuildService
+	if external.Projections != nil {
+		projections = appcatalog.NewProjectionRebuildService(external.Projections)
+	}
+	var projectors *appcatalog.ProjectorService
+	if external.Projectors != nil {
+		projectors = appcatalog.NewProjectorService(external.Projectors)
+	}
+	var events *appcatalog.EventExplorerService
+	if external.Events != nil {
+		events = appcatalog.NewEventExplorerService(external.Events)
+	}
+	var chains *appcatalog.ChainService
+	if external.Chains != nil {
+		chains = appcatalog.NewChainService(external.Chains)
+	}
+	var history *appcatalog.EnvironmentHistoryService
+	if external.EnvironmentHistory != nil {
+		history = appcatalog.NewEnvironmentHistoryService(external.EnvironmentHistory)
+	}
+	var identities *apporgconfig.IdentityService
+	if external.ServiceIdentities != nil {
+		identities = apporgconfig.NewIdentityService(external.ServiceIdentities)
+	}
+	var environments *apporgconfig.EnvironmentService
+	if external.Environments != nil {
+		environments = apporgconfig.NewEnvironmentService(external.Environments)
+	}
 	return &ViewRoutes{
 		read:              appcatalog.NewService(readStore),
 		metadata:          appservices.NewMetadataService(configStore),
-		config:            apporgconfig.NewService(configStore),
+		config:            apporgconfig.NewService(configStore, external.IngestSettings),
 		orgs:              appidentity.NewService(configStore),
 		githubIntegration: appgithub.NewService(installStore, NewGitHubIngestorClient(external.GitHubAppInstallURL, external.GitHubIngestorToken, external.PublicURL)),
 		fragments:         renderer.NewFragmentRenderer(512, 5*time.Second),
+		serviceChanges:    external.ServiceChanges,
+		deadLetters:       deadLetters,
+		credentials:       credentials,
+		ingestHealth:      ingestHealth,
+		projections:       projections,
+		projectors:        projectors,
+		events:            events,
+		chains:            chains,
+		history:           history,
+		identities:        identities,
+		environments:      environments,
 	}
 }
 
@@ -64,11 +155,33 @@ func (v *ViewRoutes) RegisterRoutes(s *echo.Echo) {
 	orgAuthed.GET("/api/services/:name/metrics", v.handleServiceMetrics)
 	orgAuthed.GET("/api/services/:name/metrics/fragment", v.handleServiceMetricsFragment)
 	orgAuthed.GET("/api/metrics", v.handleOrgMetrics)
+	orgAuthed.GET("/events", v.handleEvents)
+	orgAuthed.GET("/events/:seq", v.handleEventDetail)
+	orgAuthed.GET("/api/events", v.handleEventsData)
+	orgAuthed.GET("/api/events/:seq", v.handleEventData)
+	orgAuthed.GET("/chains/:id", v.handleChain)
+	orgAuthed.GET("/api/chains/:id", v.handleChainData)
+	orgAuthed.GET("/history", v.handleHistory)
+	orgAuthed.GET("/api/history", v.handleHistoryData)
 	orgAuthed.POST("/s/:name/metadata", v.handleServiceMetadataUpdate)
 	orgAuthed.POST("/s/:name/dependencies", v.handleServiceDependencyUpsert)
 	orgAuthed.POST("/s/:name/dependencies/delete", v.handleServiceDependencyDelete)
 	orgAuthed.GET("/settings", v.handleSettings)
 	orgAuthed.POST("/settings", v.handleSettingsUpdate)
+	orgAuthed.POST("/settings/credentials", v.handleIngestCredentialCreate)
+	orgAuthed.POST("/settings/credentials/expire", v.handleIngestCredentialExpire)
+	orgAuthed.POST("/settings/credentials/revoke", v.handleIngestCredentialRevoke)
+	orgAuthed.GET("/settings/ingest-health", v.handleIngestHealth)
+	orgAuthed.GET("/settings/projections", v.handleProjectors)
+	orgAuthed.GET("/settings/service-identities", v.handleServiceIdentities)
+	orgAuthed.POST("/settings/service-identities", v.handleServiceIdentityCreate)
+	orgAuthed.POST("/settings/service-identities/delete", v.handleServiceIdentityDelete)
+	orgAuthed.GET("/settings/environments", v.handleEnvironments)
+	orgAuthed.POST("/settings/environments", v.handleEnvironmentSave)
+	orgAuthed.POST("/settings/environments/delete", v.handleEnvironmentDelete)
+	orgAuthed.GET("/settings/dead-letters", v.handleDeadLetters)
+	orgAuthed.POST("/settings/dead-letters/replay", v.handleDeadLetterReplay)
+	orgAuthed.POST("/settings/dead-letters/delete", v.handleDeadLetterDelete)
 	orgAuthed.GET("/settings/integrations/github", v.handleGitHubIntegration)
 	orgAuthed.POST("/settings/integrations/github/link", v.handleGitHubIntegrationLink)
 	orgAuthed.POST("/settings/integrations/github/delete", v.handleGitHubIntegrationDelete)
@@ -78,6 +191,7 @@ func (v *ViewRoutes) RegisterRoutes(s *echo.Echo) {
 	orgAuthed.POST("/organizations/rename", v.handleOrganizationRename)
 	orgAuthed.POST("/organizations/toggle", v.handleOrganizationToggle)
 	orgAuthed.POST("/organizations/delete", v.handleOrganizationDelete)
+	orgAuthed.POST("/organizations/projections/rebuild", v.handleOrganizationProjectionRebuild)
 	orgAuthed.POST("/organizations/switch", v.handleOrganizationSwitch)
 	orgAuthed.POST("/organizations/members/add", v.handleOrganizationMemberAdd)
 	orgAuthed.POST("/organizations/members/role", v.handleOrganizationMemberRole)
@@ -88,6 +202,7 @@ func (v *ViewRoutes) RegisterRoutes(s *echo.Echo) {
 
 	orgAuthed.GET("/deployments", v.handleDeployments)
 	orgAuthed.GET("/deployments/filter", v.handleDeploymentFilter, v.htmxFragmentCacheMiddleware("deployments-filter"))
+	orgAuthed.GET("/deployments/rows", v.handleDeploymentRows, v.htmxFragmentCacheMiddleware("deployments-rows"))
 	orgAuthed.GET("/services/stream", v.handleServiceStream)
 
 	orgAuthed.GET("/services/grid", v.handleServiceGrid, v.htmxFragmentCacheMiddleware("services-grid"))
@@ -1,18 +1,22 @@
 package routes
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 
 	appdomain "github.com/fr0stylo/ddash/apps/ddash/internal/app/domain"
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
 	appservices "github.com/fr0stylo/ddash/apps/ddash/internal/app/services"
 	"github.com/fr0stylo/ddash/apps/ddash/internal/renderer"
 	"github.com/fr0stylo/ddash/views/components"
@@ -52,6 +56,12 @@ func (v *ViewRoutes) handleServiceDetails(c echo.Context) error {
 	detail, err := v.read.GetServiceDetail(ctx, orgID, name)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
+			// Old links keep working after a rename or merge.
+			if v.identities != nil {
+				if resolved, resolveErr := v.identities.Resolve(ctx, orgID, name); resolveErr == nil && resolved != name {
+					return c.Redirect(http.StatusFound, "/s/"+url.PathEscape(resolved))
+				}
+			}
 			return c.NoContent(http.StatusNotFound)
 		}
 		return err
@@ -78,7 +88,7 @@ func (v *ViewRoutes) handleServiceDetails(c echo.Context) error {
 	if flashLevel != "error" {
 		flashLevel = "success"
 	}
-	return c.Render(http.StatusOK, "", pages.ServicePage(mapDomainServiceDetail(detail), settings.ShowMetadataBadges, settings.ShowDeploymentHistory, settings.AllowServiceMetadataEditing, settings.ShowIntegrationTypeBadges, settings.ShowServiceDetailInsights, settings.ShowServiceDeliveryMetrics, settings.ShowServiceDependencies, flashMessage, flashLevel, csrfToken(c)))
+	return c.Render(http.StatusOK, "", pages.ServicePage(mapDomainServiceDetail(detail, v.chains != nil), settings.ShowMetadataBadges, settings.ShowDeploymentHistory, settings.AllowServiceMetadataEditing, settings.ShowIntegrationTypeBadges, settings.ShowServiceDetailInsights, settings.ShowServiceDeliveryMetrics, settings.ShowServiceDependencies, flashMessage, flashLevel, csrfToken(c)))
 }
 
 func (v *ViewRoutes) handleServiceGrid(c echo.Context) error {
@@ -140,84 +150,149 @@ func (v *ViewRoutes) handleServiceFilter(c echo.Context) error {
 	return c.Render(http.StatusOK, "", pages.ServiceGridFragment(mapDomainServices(services), settings.ShowSyncStatus, settings.ShowMetadataBadges, settings.ShowEnvironmentColumn, settings.EnableSSELiveUpdates, settings.StatusSemanticsMode))
 }
 
-func (v *ViewRoutes) handleServiceStream(c echo.Context) error {
-	w := c.Response().Writer
-	c.Response().Header().Set("Content-Type", "text/event-stream")
-	c.Response().Header().Set("Cache-Control", "no-cache")
-	c.Response().Header().Set("Connection", "keep-alive")
-
-	flusher, ok := w.(http.Flusher)
-	if !ok {
-		return errors.New("streaming unsupported")
-	}
+const (
+	serviceStreamHeartbeatInterval = 25 * time.Second
+	lastEventIDHeader              = "Last-Event-ID"
+)
 
+func (v *ViewRoutes) handleServiceStream(c echo.Context) error {
 	ctx := c.Request().Context()
 	orgID, err := v.currentOrganizationID(c)
 	if err != nil {
 		return err
 	}
-	view := c.QueryParam("view")
 	settings, err := v.loadDashboardSettings(ctx, orgID)
 	if err != nil {
 		return err
 	}
-	ticker := time.NewTicker(3 * time.Second)
-	defer ticker.Stop()
+	if !settings.EnableSSELiveUpdates {
+		// 204 tells EventSource clients to stop reconnecting.
+		return c.NoContent(http.StatusNoContent)
+	}
 
-	services, err := v.read.GetServicesByEnv(ctx, orgID, "all")
-	if err != nil {
-		return err
+	w := c.Response().Writer
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return errors.New("streaming unsupported")
 	}
-	uiServices := mapDomainServices(services)
-	indexes := make([]int, len(uiServices))
-	for i := range services {
-		indexes[i] = i
+
+	// Subscribe before reading the resume point so no commit falls in between.
+	changes, unsubscribe := v.serviceChanges.Subscribe(orgID)
+	defer unsubscribe()
+
+	lastSeq, resumed := parseLastEventID(c.Request().Header.Get(lastEventIDHeader))
+	if !resumed {
+		lastSeq, err = v.read.GetLatestEventSeq(ctx, orgID)
+		if err != nil {
+			return err
+		}
 	}
-	if len(indexes) == 0 {
-		return nil
+
+	c.Response().Header().Set("Content-Type", "text/event-stream")
+	c.Response().Header().Set("Cache-Control", "no-cache")
+	c.Response().Header().Set("Connection", "keep-alive")
+	c.Response().WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	view := c.QueryParam("view")
+	if resumed {
+		lastSeq, err = v.writeServiceChanges(ctx, w, orgID, lastSeq, view, settings)
+		if err != nil {
+			return err
+		}
+		flusher.Flush()
 	}
 
-	step := 0
+	heartbeat := time.NewTicker(serviceStreamHeartbeatInterval)
+	defer heartbeat.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
-		case <-ticker.C:
-			idx := indexes[step%len(indexes)]
-			service := uiServices[idx]
-			service.Status = nextServiceStatus(service.Status)
-			if service.Status == components.StatusSynced {
-				service.LastDeploy = time.Now().Format("2006-01-02 15:04")
+		case <-heartbeat.C:
+			_, _ = w.Write([]byte(": keep-alive\n\n"))
+			flusher.Flush()
+		case change := <-changes:
+			if change.Seq <= lastSeq {
+				continue
 			}
-			uiServices[idx] = service
-
-			switch view {
-			case "table":
-				rowPayload, err := renderer.ServiceRow(ctx, uiServices, service, settings.ShowSyncStatus, settings.ShowMetadataBadges, settings.ShowEnvironmentColumn, settings.StatusSemanticsMode)
-				if err != nil {
-					return err
-				}
-				_, _ = w.Write([]byte("event: " + components.ServiceRowEventName(service) + "\ndata: " + rowPayload + "\n\n"))
-			case "grid":
-				payload, err := renderer.ServiceCard(ctx, uiServices, service, settings.ShowSyncStatus, settings.ShowMetadataBadges, settings.ShowEnvironmentColumn, settings.StatusSemanticsMode)
-				if err != nil {
-					return err
-				}
-				_, _ = w.Write([]byte("event: " + components.ServiceCardEventName(service) + "\ndata: " + payload + "\n\n"))
-			default:
-				payload, err := renderer.ServiceCard(ctx, uiServices, service, settings.ShowSyncStatus, settings.ShowMetadataBadges, settings.ShowEnvironmentColumn, settings.StatusSemanticsMode)
-				if err != nil {
-					return err
-				}
-				_, _ = w.Write([]byte("event: " + components.ServiceCardEventName(service) + "\ndata: " + payload + "\n\n"))
-				rowPayload, err := renderer.ServiceRow(ctx, uiServices, service, settings.ShowSyncStatus, settings.ShowMetadataBadges, settings.ShowEnvironmentColumn, settings.StatusSemanticsMode)
-				if err != nil {
-					return err
-				}
-				_, _ = w.Write([]byte("event: " + components.ServiceRowEventName(service) + "\ndata: " + rowPayload + "\n\n"))
+			drainServiceChanges(changes)
+			lastSeq, err = v.writeServiceChanges(ctx, w, orgID, lastSeq, view, settings)
+			if err != nil {
+				return err
 			}
 			flusher.Flush()
-			step++
+		}
+	}
+}
+
+// writeServiceChanges re-renders services whose events were committed after
+// afterSeq and returns the new resume point. The event store is the source of
+// truth, so changes dropped by a slow subscriber are still picked up here.
+func (v *ViewRoutes) writeServiceChanges(ctx context.Context, w io.Writer, orgID, afterSeq int64, view string, settings appservices.OrganizationSettings) (int64, error) {
+	changes, err := v.read.ListServiceChangesAfterSeq(ctx, orgID, afterSeq, 0)
+	if err != nil || len(changes) == 0 {
+		return afterSeq, err
+	}
+	services, err := v.read.GetServicesByEnv(ctx, orgID, "all")
+	if err != nil {
+		return afterSeq, err
+	}
+	uiServices := mapDomainServices(services)
+
+	for _, change := range changes {
+		for _, service := range uiServices {
+			if service.Title != change.ServiceName {
+				continue
+			}
+			if err := writeServiceStreamEvents(ctx, w, change.Seq, view, uiServices, service, settings); err != nil {
+				return afterSeq, err
+			}
+		}
+		if change.Seq > afterSeq {
+			afterSeq = change.Seq
+		}
+	}
+	return afterSeq, nil
+}
+
+func writeServiceStreamEvents(ctx context.Context, w io.Writer, seq int64, view string, uiServices []components.Service, service components.Service, settings appservices.OrganizationSettings) error {
+	if view != "table" {
+		payload, err := renderer.ServiceCard(ctx, uiServices, service, settings.ShowSyncStatus, settings.ShowMetadataBadges, settings.ShowEnvironmentColumn, settings.StatusSemanticsMode)
+		if err != nil {
+			return err
+		}
+		writeServerSentEvent(w, seq, components.ServiceCardEventName(service), payload)
+	}
+	if view != "grid" {
+		rowPayload, err := renderer.ServiceRow(ctx, uiServices, service, settings.ShowSyncStatus, settings.ShowMetadataBadges, settings.ShowEnvironmentColumn, settings.StatusSemanticsMode)
+		if err != nil {
+			return err
+		}
+		writeServerSentEvent(w, seq, components.ServiceRowEventName(service), rowPayload)
+	}
+	return nil
+}
+
+func writeServerSentEvent(w io.Writer, id int64, event, data string) {
+	_, _ = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", id, event, data)
+}
+
+func parseLastEventID(value string) (int64, bool) {
+	seq, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || seq < 0 {
+		return 0, false
+	}
+	return seq, true
+}
+
+func drainServiceChanges(changes <-chan ports.ServiceChange) {
+	for {
+		select {
+		case <-changes:
+		default:
+			return
 		}
 	}
 }
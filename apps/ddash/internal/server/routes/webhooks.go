@@ -30,6 +30,7 @@ func NewWebhookRoutes(storeFactory ports.IngestionStoreFactory, batchConfig appi
 func (w *WebhookRoutes) RegisterRoutes(s *echo.Echo) {
 	s.POST("/webhooks/custom", w.handleLegacyCustomWebhook)
 	s.POST("/webhooks/cdevents", w.handleCustomWebhook)
+	s.POST("/webhooks/cdevents/batch", w.handleCustomBatchWebhook)
 	s.POST("/webhooks/github-app", w.handleGitHubAppWebhook)
 	s.POST("/webhooks/gitlab-app", w.handleGitLabAppWebhook)
 }
@@ -38,6 +39,10 @@ func (w *WebhookRoutes) handleCustomWebhook(c echo.Context) error {
 	return w.custom.Handle(c.Response(), c.Request())
 }
 
+func (w *WebhookRoutes) handleCustomBatchWebhook(c echo.Context) error {
+	return w.custom.HandleBatch(c.Response(), c.Request())
+}
+
 func (w *WebhookRoutes) handleLegacyCustomWebhook(c echo.Context) error {
 	return c.JSON(http.StatusGone, map[string]string{
 		"error": "legacy custom payload ingestion removed; send CDEvents delivery events to /webhooks/cdevents",
@@ -11,6 +11,10 @@ import (
 const (
 	// SignatureHeader is the HMAC signature header.
 	SignatureHeader = "X-Webhook-Signature"
+	// TimestampHeader carries the unix time signed under the timestamped scheme.
+	TimestampHeader = "X-Webhook-Timestamp"
+	// NonceHeader carries the optional nonce signed under the timestamped scheme.
+	NonceHeader = "X-Webhook-Nonce"
 	// AuthorizationHeader contains the bearer token.
 	AuthorizationHeader = "Authorization"
 	maxPayloadBytes     = 1 << 20
@@ -18,27 +22,35 @@ const (
 
 // Handler processes CDEvents delivery payloads.
 type Handler struct {
-	ingest *appingest.Service
+	ingest  *appingest.Service
+	metrics providerIngestionMetrics
 }
 
 // NewHandler constructs a CDEvents webhook handler.
 func NewHandler(storeFactory ports.IngestionStoreFactory, batchConfig appingest.BatchConfig) *Handler {
-	return &Handler{ingest: appingest.NewService(storeFactory, batchConfig)}
+	return &Handler{ingest: appingest.NewService(storeFactory, batchConfig), metrics: newProviderIngestionMetrics()}
 }
 
 // Handle validates and processes a webhook request.
 func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) error {
+	h.metrics.recordRequest(r.Context(), "cdevents")
 	body, readErr := io.ReadAll(io.LimitReader(r.Body, maxPayloadBytes))
 	if readErr != nil {
+		h.metrics.recordRejected(r.Context(), "cdevents", "read_failed")
 		http.Error(w, "invalid payload", http.StatusBadRequest)
 		return readErr
 	}
 	ingestErr := h.ingest.Ingest(r.Context(), appingest.Command{
 		AuthorizationHeader: r.Header.Get(AuthorizationHeader),
 		SignatureHeader:     r.Header.Get(SignatureHeader),
+		TimestampHeader:     r.Header.Get(TimestampHeader),
+		NonceHeader:         r.Header.Get(NonceHeader),
 		Headers:             r.Header,
 		Body:                body,
 	})
+	if ingestErr != nil {
+		h.metrics.recordRejected(r.Context(), "cdevents", string(appingest.ClassifyError(ingestErr)))
+	}
 	if handled := writeIngestHTTPError(w, ingestErr); handled {
 		return nil
 	}
@@ -46,6 +58,7 @@ func (h *Handler) Handle(w http.ResponseWriter, r *http.Request) error {
 		return ingestErr
 	}
 
+	h.metrics.recordAccepted(r.Context(), "cdevents")
 	w.WriteHeader(http.StatusAccepted)
 	return nil
 }
@@ -63,6 +76,12 @@ func writeIngestHTTPError(w http.ResponseWriter, err error) bool {
 	case appingest.ErrorInvalidSignature:
 		http.Error(w, "invalid signature", http.StatusUnauthorized)
 		return true
+	case appingest.ErrorStaleSignature:
+		http.Error(w, "stale signature timestamp", http.StatusUnauthorized)
+		return true
+	case appingest.ErrorReplayedSignature:
+		http.Error(w, "signature already used", http.StatusConflict)
+		return true
 	case appingest.ErrorInvalidPayload:
 		http.Error(w, "invalid cdevent payload", http.StatusBadRequest)
 		return true
@@ -0,0 +1,86 @@
+package custom
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	appingest "github.com/fr0stylo/ddash/apps/ddash/internal/application/ingestion"
+)
+
+const maxBatchPayloadBytes = 16 << 20
+
+type batchEventResponse struct {
+	Index   int    `json:"index"`
+	EventID string `json:"eventId,omitempty"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+type batchResponse struct {
+	Accepted   int                  `json:"accepted"`
+	Queued     int                  `json:"queued"`
+	Duplicates int                  `json:"duplicates"`
+	Dropped    int                  `json:"dropped"`
+	Rejected   int                  `json:"rejected"`
+	Results    []batchEventResponse `json:"results"`
+}
+
+// HandleBatch validates one signature over a JSON array or NDJSON stream of
+// CDEvents and reports the outcome of every event.
+func (h *Handler) HandleBatch(w http.ResponseWriter, r *http.Request) error {
+	h.metrics.recordRequest(r.Context(), "cdevents_batch")
+	body, readErr := io.ReadAll(io.LimitReader(r.Body, maxBatchPayloadBytes+1))
+	if readErr != nil {
+		h.metrics.recordRejected(r.Context(), "cdevents_batch", "read_failed")
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return readErr
+	}
+	if len(body) > maxBatchPayloadBytes {
+		h.metrics.recordRejected(r.Context(), "cdevents_batch", "payload_too_large")
+		http.Error(w, "batch payload too large", http.StatusRequestEntityTooLarge)
+		return nil
+	}
+	result, ingestErr := h.ingest.IngestBatch(r.Context(), appingest.Command{
+		AuthorizationHeader: r.Header.Get(AuthorizationHeader),
+		SignatureHeader:     r.Header.Get(SignatureHeader),
+		TimestampHeader:     r.Header.Get(TimestampHeader),
+		NonceHeader:         r.Header.Get(NonceHeader),
+		Headers:             r.Header,
+		Body:                body,
+	})
+	if ingestErr != nil {
+		h.metrics.recordRejected(r.Context(), "cdevents_batch", string(appingest.ClassifyError(ingestErr)))
+	}
+	if appingest.ClassifyError(ingestErr) == appingest.ErrorBatchTooLarge {
+		http.Error(w, "batch exceeds event limit", http.StatusRequestEntityTooLarge)
+		return nil
+	}
+	if handled := writeIngestHTTPError(w, ingestErr); handled {
+		return nil
+	}
+	if ingestErr != nil {
+		return ingestErr
+	}
+
+	response := batchResponse{
+		Accepted:   result.Accepted,
+		Queued:     result.Queued,
+		Duplicates: result.Duplicates,
+		Dropped:    result.Dropped,
+		Rejected:   result.Rejected,
+		Results:    make([]batchEventResponse, 0, len(result.Events)),
+	}
+	for _, event := range result.Events {
+		item := batchEventResponse{Index: event.Index, EventID: event.EventID, Status: event.Status}
+		if event.Status == appingest.BatchRejected {
+			item.Reason = string(event.Reason)
+		}
+		response.Results = append(response.Results, item)
+	}
+
+	h.metrics.recordAccepted(r.Context(), "cdevents_batch")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
@@ -5,10 +5,13 @@ import (
 	"context"
 	"crypto/hmac"
 	"crypto/sha256"
+	"database/sql"
 	"encoding/hex"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 
@@ -16,7 +19,9 @@ import (
 	cdeventsv05 "github.com/cdevents/sdk-go/pkg/api/v05"
 
 	"github.com/fr0stylo/ddash/apps/ddash/internal/adapters/sqlite"
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
 	appingest "github.com/fr0stylo/ddash/apps/ddash/internal/application/ingestion"
+	"github.com/fr0stylo/ddash/apps/ddash/internal/infrastructure/spool"
 	"github.com/fr0stylo/ddash/internal/db"
 	"github.com/fr0stylo/ddash/internal/db/queries"
 )
@@ -85,7 +90,7 @@ func TestHandleAcceptsValidDeliveryCDEvent(t *testing.T) {
 	}
 	t.Cleanup(func() { _ = reloaded.Close() })
 
-	rows, err := reloaded.ListDeploymentsFromEvents(ctx, queries.ListDeploymentsFromEventsParams{OrganizationID: org.ID, Env: "", Service: ""})
+	rows, err := reloaded.ListDeploymentsFromEvents(ctx, queries.ListDeploymentsFromEventsParams{OrganizationID: org.ID, Env: "", Service: "", Status: "", Limit: 10})
 	if err != nil {
 		t.Fatalf("query projections: %v", err)
 	}
@@ -150,7 +155,7 @@ func TestHandleRejectsInvalidSignature(t *testing.T) {
 	}
 	t.Cleanup(func() { _ = reloaded.Close() })
 
-	rows, err := reloaded.ListDeploymentsFromEvents(ctx, queries.ListDeploymentsFromEventsParams{OrganizationID: org.ID, Env: "", Service: ""})
+	rows, err := reloaded.ListDeploymentsFromEvents(ctx, queries.ListDeploymentsFromEventsParams{OrganizationID: org.ID, Env: "", Service: "", Status: "", Limit: 10})
 	if err != nil {
 		t.Fatalf("query projections: %v", err)
 	}
@@ -166,6 +171,675 @@ func TestHandleRejectsInvalidSignature(t *testing.T) {
 	}
 }
 
+type recordingPublisher struct {
+	changes []ports.ServiceChange
+}
+
+func (p *recordingPublisher) PublishServiceChanges(changes []ports.ServiceChange) {
+	p.changes = append(p.changes, changes...)
+}
+
+func TestHandlePublishesServiceChangeOnlyForNewEvents(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "testdb")
+	database, err := db.New(dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+
+	org, err := database.CreateOrganization(ctx, queries.CreateOrganizationParams{
+		Name:          "default",
+		AuthToken:     "test-token",
+		WebhookSecret: "test-secret",
+		Enabled:       1,
+	})
+	if err != nil {
+		t.Fatalf("create org: %v", err)
+	}
+
+	event, err := cdeventsv05.NewServiceDeployedEvent()
+	if err != nil {
+		t.Fatalf("new event: %v", err)
+	}
+	event.SetId("evt-publish")
+	event.SetSource("tests/source")
+	event.SetTimestamp(time.Now().UTC())
+	event.SetSubjectId("service/orders")
+	event.SetSubjectEnvironment(&cdeventsapi.Reference{Id: "staging"})
+	event.SetSubjectArtifactId("pkg:generic/orders@abc123")
+	body, err := cdeventsapi.AsJsonBytes(event)
+	if err != nil {
+		t.Fatalf("encode event: %v", err)
+	}
+
+	publisher := &recordingPublisher{}
+	h := NewHandler(sqlite.NewSharedIngestionStoreFactory(database), appingest.BatchConfig{Enabled: false, Publisher: publisher})
+	for range 2 {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/cdevents", bytes.NewReader(body))
+		req.Header.Set(AuthorizationHeader, "Bearer test-token")
+		req.Header.Set(SignatureHeader, signTest(body, "test-secret"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		if err := h.Handle(rec, req); err != nil {
+			t.Fatalf("handle request: %v", err)
+		}
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("unexpected status: got=%d want=%d", rec.Code, http.StatusAccepted)
+		}
+	}
+
+	if len(publisher.changes) != 1 {
+		t.Fatalf("expected one change for the first delivery only, got %+v", publisher.changes)
+	}
+	change := publisher.changes[0]
+	if change.OrganizationID != org.ID || change.ServiceName != "orders" || change.Seq <= 0 {
+		t.Fatalf("unexpected change: %+v", change)
+	}
+}
+
+func TestHandleAppliesOrganizationEventPolicy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		mode       string
+		wantStatus int
+		wantStored int64
+	}{
+		{mode: "strict", wantStatus: http.StatusBadRequest, wantStored: 0},
+		{mode: "accept", wantStatus: http.StatusAccepted, wantStored: 1},
+		{mode: "drop", wantStatus: http.StatusAccepted, wantStored: 0},
+		{mode: "reject", wantStatus: http.StatusUnprocessableEntity, wantStored: 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.mode, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			database, err := db.New(filepath.Join(t.TempDir(), "testdb"))
+			if err != nil {
+				t.Fatalf("open db: %v", err)
+			}
+			t.Cleanup(func() { _ = database.Close() })
+
+			org, err := database.CreateOrganization(ctx, queries.CreateOrganizationParams{
+				Name:          "default",
+				AuthToken:     "test-token",
+				WebhookSecret: "test-secret",
+				Enabled:       1,
+			})
+			if err != nil {
+				t.Fatalf("create org: %v", err)
+			}
+			if err := database.UpsertOrganizationEventPolicy(ctx, queries.UpsertOrganizationEventPolicyParams{
+				OrganizationID: org.ID,
+				Subject:        "service",
+				Mode:           tc.mode,
+			}); err != nil {
+				t.Fatalf("upsert policy: %v", err)
+			}
+
+			event, err := cdeventsv05.NewServiceDeployedEvent()
+			if err != nil {
+				t.Fatalf("new event: %v", err)
+			}
+			event.SetId("evt-policy")
+			event.SetSource("tests/source")
+			event.SetTimestamp(time.Date(2026, 2, 19, 10, 0, 0, 0, time.UTC))
+			event.SetSubjectId("service/orders")
+			event.SetSubjectEnvironment(&cdeventsapi.Reference{Id: "staging"})
+			event.SetSubjectArtifactId("orders-build-42")
+			body, err := cdeventsapi.AsJsonBytes(event)
+			if err != nil {
+				t.Fatalf("encode event: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/cdevents", bytes.NewReader(body))
+			req.Header.Set(AuthorizationHeader, "Bearer test-token")
+			req.Header.Set(SignatureHeader, signTest(body, "test-secret"))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			h := NewHandler(sqlite.NewSharedIngestionStoreFactory(database), appingest.BatchConfig{Enabled: false})
+			if err := h.Handle(rec, req); err != nil {
+				t.Fatalf("handle request: %v", err)
+			}
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("unexpected status: got=%d want=%d", rec.Code, tc.wantStatus)
+			}
+			count, err := database.CountEventStore(ctx)
+			if err != nil {
+				t.Fatalf("count events: %v", err)
+			}
+			if count != tc.wantStored {
+				t.Fatalf("unexpected event-store count: got=%d want=%d", count, tc.wantStored)
+			}
+		})
+	}
+}
+
+func TestHandleRecordsRejectedDeliveryAndReplaysIt(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.New(filepath.Join(t.TempDir(), "testdb"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+
+	org, err := database.CreateOrganization(ctx, queries.CreateOrganizationParams{
+		Name:          "default",
+		AuthToken:     "test-token",
+		WebhookSecret: "test-secret",
+		Enabled:       1,
+	})
+	if err != nil {
+		t.Fatalf("create org: %v", err)
+	}
+
+	event, err := cdeventsv05.NewServiceDeployedEvent()
+	if err != nil {
+		t.Fatalf("new event: %v", err)
+	}
+	event.SetId("evt-dead-letter")
+	event.SetSource("tests/source")
+	event.SetTimestamp(time.Date(2026, 2, 19, 10, 0, 0, 0, time.UTC))
+	event.SetSubjectId("service/orders")
+	event.SetSubjectEnvironment(&cdeventsapi.Reference{Id: "staging"})
+	event.SetSubjectArtifactId("orders-build-42")
+	body, err := cdeventsapi.AsJsonBytes(event)
+	if err != nil {
+		t.Fatalf("encode event: %v", err)
+	}
+
+	factory := sqlite.NewSharedIngestionStoreFactory(database)
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/cdevents", bytes.NewReader(body))
+	req.Header.Set(AuthorizationHeader, "Bearer test-token")
+	req.Header.Set(SignatureHeader, signTest(body, "test-secret"))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	if err := NewHandler(factory, appingest.BatchConfig{Enabled: false}).Handle(rec, req); err != nil {
+		t.Fatalf("handle request: %v", err)
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: got=%d want=%d", rec.Code, http.StatusBadRequest)
+	}
+
+	deadLetters := appingest.NewDeadLetterService(sqlite.NewStore(database), factory, nil)
+	letters, err := deadLetters.List(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("list dead letters: %v", err)
+	}
+	if len(letters) != 1 {
+		t.Fatalf("expected one dead letter, got %d", len(letters))
+	}
+	letter := letters[0]
+	if letter.Reason != string(appingest.ErrorInvalidSchema) || !bytes.Equal(letter.Body, body) {
+		t.Fatalf("unexpected dead letter: %+v", letter)
+	}
+	if _, ok := letter.Headers[AuthorizationHeader]; ok {
+		t.Fatalf("authorization header must not be stored: %+v", letter.Headers)
+	}
+
+	result, err := deadLetters.Replay(ctx, org.ID, letter.ID)
+	if err != nil {
+		t.Fatalf("replay while still invalid: %v", err)
+	}
+	if result != string(appingest.ErrorInvalidSchema) {
+		t.Fatalf("unexpected replay result: %q", result)
+	}
+
+	if err := database.UpsertOrganizationEventPolicy(ctx, queries.UpsertOrganizationEventPolicyParams{
+		OrganizationID: org.ID,
+		Subject:        "service",
+		Mode:           "accept",
+	}); err != nil {
+		t.Fatalf("upsert policy: %v", err)
+	}
+	result, err = deadLetters.Replay(ctx, org.ID, letter.ID)
+	if err != nil {
+		t.Fatalf("replay after policy change: %v", err)
+	}
+	if result != "accepted" {
+		t.Fatalf("unexpected replay result: %q", result)
+	}
+
+	count, err := database.CountEventStore(ctx)
+	if err != nil {
+		t.Fatalf("count events: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("unexpected event-store count: got=%d want=1", count)
+	}
+	letters, err = deadLetters.List(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("list dead letters after replay: %v", err)
+	}
+	if len(letters) != 1 || letters[0].ReplayResult != "accepted" || letters[0].ReplayedAt == nil {
+		t.Fatalf("expected replay outcome recorded without new dead letters, got %+v", letters)
+	}
+}
+
+func TestHandleBatchReportsPerEventResults(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.New(filepath.Join(t.TempDir(), "testdb"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+
+	org, err := database.CreateOrganization(ctx, queries.CreateOrganizationParams{
+		Name:          "default",
+		AuthToken:     "test-token",
+		WebhookSecret: "test-secret",
+		Enabled:       1,
+	})
+	if err != nil {
+		t.Fatalf("create org: %v", err)
+	}
+
+	encode := func(id, artifactID string) []byte {
+		t.Helper()
+		event, err := cdeventsv05.NewServiceDeployedEvent()
+		if err != nil {
+			t.Fatalf("new event: %v", err)
+		}
+		event.SetId(id)
+		event.SetSource("tests/source")
+		event.SetTimestamp(time.Date(2026, 2, 19, 10, 0, 0, 0, time.UTC))
+		event.SetSubjectId("service/orders")
+		event.SetSubjectEnvironment(&cdeventsapi.Reference{Id: "staging"})
+		event.SetSubjectArtifactId(artifactID)
+		body, err := cdeventsapi.AsJsonBytes(event)
+		if err != nil {
+			t.Fatalf("encode event: %v", err)
+		}
+		return body
+	}
+	h := NewHandler(sqlite.NewSharedIngestionStoreFactory(database), appingest.BatchConfig{Enabled: false})
+	send := func(body []byte) batchResponse {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/cdevents/batch", bytes.NewReader(body))
+		req.Header.Set(AuthorizationHeader, "Bearer test-token")
+		req.Header.Set(SignatureHeader, signTest(body, "test-secret"))
+		rec := httptest.NewRecorder()
+		if err := h.HandleBatch(rec, req); err != nil {
+			t.Fatalf("handle batch: %v", err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("unexpected status: got=%d want=%d body=%s", rec.Code, http.StatusOK, rec.Body.String())
+		}
+		response := batchResponse{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return response
+	}
+
+	ndjson := bytes.Join([][]byte{
+		encode("evt-a", "pkg:generic/orders@abc123"),
+		encode("evt-a", "pkg:generic/orders@abc123"),
+		encode("evt-invalid", "orders-build-42"),
+		[]byte("{not json"),
+	}, []byte("\n"))
+	first := send(ndjson)
+	wantFirst := []batchEventResponse{
+		{Index: 0, EventID: "evt-a", Status: appingest.BatchAccepted},
+		{Index: 1, EventID: "evt-a", Status: appingest.BatchDuplicate},
+		{Index: 2, EventID: "evt-invalid", Status: appingest.BatchRejected, Reason: string(appingest.ErrorInvalidSchema)},
+		{Index: 3, Status: appingest.BatchRejected, Reason: string(appingest.ErrorInvalidPayload)},
+	}
+	if first.Accepted != 1 || first.Duplicates != 1 || first.Rejected != 2 || len(first.Results) != len(wantFirst) {
+		t.Fatalf("unexpected first batch summary: %+v", first)
+	}
+	for i, want := range wantFirst {
+		if first.Results[i] != want {
+			t.Fatalf("unexpected result %d: got=%+v want=%+v", i, first.Results[i], want)
+		}
+	}
+
+	array := []byte("[" + string(encode("evt-a", "pkg:generic/orders@abc123")) + "," + string(encode("evt-b", "pkg:generic/orders@def456")) + "]")
+	second := send(array)
+	if second.Accepted != 1 || second.Duplicates != 1 || second.Results[0].Status != appingest.BatchDuplicate || second.Results[1].Status != appingest.BatchAccepted {
+		t.Fatalf("unexpected second batch result: %+v", second)
+	}
+
+	count, err := database.CountEventStore(ctx)
+	if err != nil {
+		t.Fatalf("count events: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("unexpected event-store count: got=%d want=2", count)
+	}
+	letters, err := sqlite.NewStore(database).ListDeadLetters(ctx, org.ID, 10)
+	if err != nil {
+		t.Fatalf("list dead letters: %v", err)
+	}
+	if len(letters) != 2 {
+		t.Fatalf("expected rejected batch events kept as dead letters, got %d", len(letters))
+	}
+}
+
+func TestHandleBatchReportsSpooledEventsAsQueued(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.New(filepath.Join(t.TempDir(), "testdb"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+
+	if _, err := database.CreateOrganization(ctx, queries.CreateOrganizationParams{
+		Name:          "default",
+		AuthToken:     "test-token",
+		WebhookSecret: "test-secret",
+		Enabled:       1,
+	}); err != nil {
+		t.Fatalf("create org: %v", err)
+	}
+	spooled, err := spool.Open(t.TempDir(), sqlite.NewSharedIngestionStoreFactory(database), spool.Options{PollInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("open spool: %v", err)
+	}
+	t.Cleanup(func() { _ = spooled.Close() })
+
+	body := []byte(`[{"context":{"id":"evt-q","source":"tests/source","type":"dev.cdevents.service.deployed.0.3.0","timestamp":"2026-02-19T10:00:00Z","specversion":"0.5.0"},"subject":{"id":"service/orders","source":"tests/source","content":{"environment":{"id":"staging"},"artifactId":"pkg:generic/orders@abc123"}}}]`)
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/cdevents/batch", bytes.NewReader(body))
+	req.Header.Set(AuthorizationHeader, "Bearer test-token")
+	req.Header.Set(SignatureHeader, signTest(body, "test-secret"))
+	rec := httptest.NewRecorder()
+	h := NewHandler(spooled, appingest.BatchConfig{Enabled: false})
+	if err := h.HandleBatch(rec, req); err != nil {
+		t.Fatalf("handle batch: %v", err)
+	}
+	response := batchResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if response.Accepted != 0 || response.Queued != 1 || len(response.Results) != 1 || response.Results[0].Status != appingest.BatchQueued {
+		t.Fatalf("expected the spooled event reported as queued, got %+v", response)
+	}
+}
+
+func TestHandleBatchRejectsTamperedSignature(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.New(filepath.Join(t.TempDir(), "testdb"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+
+	if _, err := database.CreateOrganization(ctx, queries.CreateOrganizationParams{
+		Name:          "default",
+		AuthToken:     "test-token",
+		WebhookSecret: "test-secret",
+		Enabled:       1,
+	}); err != nil {
+		t.Fatalf("create org: %v", err)
+	}
+
+	body := []byte(`[{"context":{"id":"evt-1"}}]`)
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/cdevents/batch", bytes.NewReader(body))
+	req.Header.Set(AuthorizationHeader, "Bearer test-token")
+	req.Header.Set(SignatureHeader, signTest([]byte(`[]`), "test-secret"))
+	rec := httptest.NewRecorder()
+	h := NewHandler(sqlite.NewSharedIngestionStoreFactory(database), appingest.BatchConfig{Enabled: false})
+	if err := h.HandleBatch(rec, req); err != nil {
+		t.Fatalf("handle batch: %v", err)
+	}
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("unexpected status: got=%d want=%d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestHandleTimestampedSignatureRejectsStaleAndReplayedRequests(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.New(filepath.Join(t.TempDir(), "testdb"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+
+	org, err := database.CreateOrganization(ctx, queries.CreateOrganizationParams{
+		Name:          "default",
+		AuthToken:     "test-token",
+		WebhookSecret: "test-secret",
+		Enabled:       1,
+	})
+	if err != nil {
+		t.Fatalf("create org: %v", err)
+	}
+	if err := database.UpsertOrganizationPreference(ctx, org.ID, "webhook_signature_scheme", "timestamped"); err != nil {
+		t.Fatalf("upsert preference: %v", err)
+	}
+
+	event, err := cdeventsv05.NewServiceDeployedEvent()
+	if err != nil {
+		t.Fatalf("new event: %v", err)
+	}
+	event.SetId("evt-signed")
+	event.SetSource("tests/source")
+	event.SetTimestamp(time.Date(2026, 2, 19, 10, 0, 0, 0, time.UTC))
+	event.SetSubjectId("service/orders")
+	event.SetSubjectEnvironment(&cdeventsapi.Reference{Id: "staging"})
+	event.SetSubjectArtifactId("pkg:generic/orders@abc123")
+	body, err := cdeventsapi.AsJsonBytes(event)
+	if err != nil {
+		t.Fatalf("encode event: %v", err)
+	}
+
+	h := NewHandler(sqlite.NewSharedIngestionStoreFactory(database), appingest.BatchConfig{Enabled: false, SignatureSkew: time.Minute})
+	send := func(timestamp, nonce, signature string) int {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/cdevents", bytes.NewReader(body))
+		req.Header.Set(AuthorizationHeader, "Bearer test-token")
+		req.Header.Set(SignatureHeader, signature)
+		if timestamp != "" {
+			req.Header.Set(TimestampHeader, timestamp)
+			req.Header.Set(NonceHeader, nonce)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		if err := h.Handle(rec, req); err != nil {
+			t.Fatalf("handle request: %v", err)
+		}
+		return rec.Code
+	}
+	signTimestamped := func(timestamp, nonce string) string {
+		return signTest(append([]byte(timestamp+"."+nonce+"."), body...), "test-secret")
+	}
+
+	if code := send("", "", signTest(body, "test-secret")); code != http.StatusUnauthorized {
+		t.Fatalf("expected body-only signature refused, got %d", code)
+	}
+	stale := strconv.FormatInt(time.Now().Add(-2*time.Minute).Unix(), 10)
+	if code := send(stale, "n-1", signTimestamped(stale, "n-1")); code != http.StatusUnauthorized {
+		t.Fatalf("expected stale timestamp refused, got %d", code)
+	}
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	if code := send(now, "n-2", signTimestamped(now, "n-3")); code != http.StatusUnauthorized {
+		t.Fatalf("expected nonce mismatch refused, got %d", code)
+	}
+	if code := send(now, "n-2", signTimestamped(now, "n-2")); code != http.StatusAccepted {
+		t.Fatalf("expected timestamped signature accepted, got %d", code)
+	}
+	if code := send(now, "n-2", signTimestamped(now, "n-2")); code != http.StatusConflict {
+		t.Fatalf("expected replayed signature refused, got %d", code)
+	}
+}
+
+func TestHandleBindsSignatureToIngestCredential(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.New(filepath.Join(t.TempDir(), "testdb"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+
+	org, err := database.CreateOrganization(ctx, queries.CreateOrganizationParams{
+		Name:          "default",
+		AuthToken:     "test-token",
+		WebhookSecret: "test-secret",
+		Enabled:       1,
+	})
+	if err != nil {
+		t.Fatalf("create org: %v", err)
+	}
+	now := time.Now().UTC()
+	createCredential := func(label, token, secret string, expiresAt sql.NullInt64) queries.OrganizationIngestCredential {
+		t.Helper()
+		credential, err := database.CreateOrganizationIngestCredential(ctx, queries.InsertOrganizationIngestCredentialParams{
+			OrganizationID: org.ID,
+			Label:          label,
+			AuthToken:      token,
+			WebhookSecret:  secret,
+			CreatedAt:      now.Add(-time.Hour).UnixMilli(),
+			ExpiresAt:      expiresAt,
+		})
+		if err != nil {
+			t.Fatalf("create credential %s: %v", label, err)
+		}
+		return credential
+	}
+	rotated := createCredential("rotated", "rotated-token", "rotated-secret", sql.NullInt64{Int64: now.Add(time.Hour).UnixMilli(), Valid: true})
+	createCredential("other", "other-token", "other-secret", sql.NullInt64{})
+	createCredential("expired", "expired-token", "expired-secret", sql.NullInt64{Int64: now.Add(-time.Minute).UnixMilli(), Valid: true})
+	revoked := createCredential("revoked", "revoked-token", "revoked-secret", sql.NullInt64{})
+	if _, err := database.RevokeOrganizationIngestCredential(ctx, queries.RevokeOrganizationIngestCredentialParams{
+		RevokedAt:      sql.NullInt64{Int64: now.UnixMilli(), Valid: true},
+		OrganizationID: org.ID,
+		ID:             revoked.ID,
+	}); err != nil {
+		t.Fatalf("revoke credential: %v", err)
+	}
+
+	body := []byte(`{"context":{"id":"evt-rotated","source":"tests/source","type":"dev.cdevents.service.deployed.0.3.0","timestamp":"2026-02-19T10:00:00Z","specversion":"0.5.0"},"subject":{"id":"service/orders","source":"tests/source","content":{"environment":{"id":"staging"},"artifactId":"pkg:generic/orders@abc123"}}}`)
+	h := NewHandler(sqlite.NewSharedIngestionStoreFactory(database), appingest.BatchConfig{Enabled: false})
+	send := func(token, secret string) int {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/cdevents", bytes.NewReader(body))
+		req.Header.Set(AuthorizationHeader, "Bearer "+token)
+		req.Header.Set(SignatureHeader, signTest(body, secret))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		if err := h.Handle(rec, req); err != nil {
+			t.Fatalf("handle request: %v", err)
+		}
+		return rec.Code
+	}
+
+	if code := send("rotated-token", "rotated-secret"); code != http.StatusAccepted {
+		t.Fatalf("expected rotated credential accepted, got %d", code)
+	}
+	if code := send("test-token", "test-secret"); code != http.StatusAccepted {
+		t.Fatalf("expected primary credentials accepted, got %d", code)
+	}
+	if code := send("test-token", "rotated-secret"); code != http.StatusUnauthorized {
+		t.Fatalf("expected rotated secret refused with primary token, got %d", code)
+	}
+	if code := send("rotated-token", "test-secret"); code != http.StatusUnauthorized {
+		t.Fatalf("expected primary secret refused with rotated token, got %d", code)
+	}
+	if code := send("rotated-token", "other-secret"); code != http.StatusUnauthorized {
+		t.Fatalf("expected another credential secret refused with rotated token, got %d", code)
+	}
+	if code := send("expired-token", "expired-secret"); code != http.StatusUnauthorized {
+		t.Fatalf("expected expired credential refused, got %d", code)
+	}
+	if code := send("revoked-token", "revoked-secret"); code != http.StatusUnauthorized {
+		t.Fatalf("expected revoked credential refused, got %d", code)
+	}
+	if code := send("test-token", "revoked-secret"); code != http.StatusUnauthorized {
+		t.Fatalf("expected revoked secret refused, got %d", code)
+	}
+
+	credentials, err := database.ListOrganizationIngestCredentials(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("list credentials: %v", err)
+	}
+	for _, credential := range credentials {
+		used := credential.LastUsedAt.Valid
+		if used != (credential.ID == rotated.ID) {
+			t.Fatalf("unexpected last used state for %s: %+v", credential.Label, credential.LastUsedAt)
+		}
+	}
+}
+
+func TestHandleRollsUpIngestHealth(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database, err := db.New(filepath.Join(t.TempDir(), "testdb"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+
+	org, err := database.CreateOrganization(ctx, queries.CreateOrganizationParams{
+		Name:          "default",
+		AuthToken:     "test-token",
+		WebhookSecret: "test-secret",
+		Enabled:       1,
+	})
+	if err != nil {
+		t.Fatalf("create org: %v", err)
+	}
+
+	h := NewHandler(sqlite.NewSharedIngestionStoreFactory(database), appingest.BatchConfig{Enabled: false})
+	send := func(body []byte) int {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/cdevents", bytes.NewReader(body))
+		req.Header.Set(AuthorizationHeader, "Bearer test-token")
+		req.Header.Set(SignatureHeader, signTest(body, "test-secret"))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		if err := h.Handle(rec, req); err != nil {
+			t.Fatalf("handle request: %v", err)
+		}
+		return rec.Code
+	}
+
+	valid := []byte(`{"context":{"id":"evt-health","source":"tests/pipeline","type":"dev.cdevents.service.deployed.0.3.0","timestamp":"2026-02-19T10:00:00Z","specversion":"0.5.0"},"subject":{"id":"service/orders","source":"tests/pipeline","content":{"environment":{"id":"staging"},"artifactId":"pkg:generic/orders@abc123"}}}`)
+	if code := send(valid); code != http.StatusAccepted {
+		t.Fatalf("expected first delivery accepted, got %d", code)
+	}
+	if code := send(valid); code != http.StatusAccepted {
+		t.Fatalf("expected duplicate delivery acknowledged, got %d", code)
+	}
+	if code := send([]byte(`{"not":"a cdevent"}`)); code != http.StatusBadRequest {
+		t.Fatalf("expected invalid payload refused, got %d", code)
+	}
+
+	health, err := appingest.NewHealthService(sqlite.NewStore(database)).Get(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("get ingest health: %v", err)
+	}
+	if len(health.Hours) != 24 {
+		t.Fatalf("expected 24 hourly buckets, got %d", len(health.Hours))
+	}
+	if health.Totals.Accepted != 1 || health.Totals.Duplicates != 1 || health.Totals.Rejected != 1 {
+		t.Fatalf("unexpected totals: %+v", health.Totals)
+	}
+	if len(health.Rejections) != 1 || health.Rejections[0].Reason != string(appingest.ErrorInvalidPayload) {
+		t.Fatalf("unexpected rejection reasons: %+v", health.Rejections)
+	}
+	if len(health.Sources) != 1 || health.Sources[0].Source != "tests/pipeline" || health.Sources[0].LastEventID != "evt-health" {
+		t.Fatalf("unexpected source activity: %+v", health.Sources)
+	}
+}
+
 func signTest(body []byte, secret string) string {
 	mac := hmac.New(sha256.New, []byte(secret))
 	_, _ = mac.Write(body)
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/fr0stylo/ddash/apps/ddash/internal/adapters/postgres"
+	"github.com/fr0stylo/ddash/apps/ddash/internal/adapters/sqlite"
+	"github.com/fr0stylo/ddash/apps/ddash/internal/app/ports"
+	appcatalog "github.com/fr0stylo/ddash/apps/ddash/internal/application/servicecatalog"
+	ingestionpostgres "github.com/fr0stylo/ddash/apps/ddash/internal/infrastructure/postgres/ingestion"
+	ingestionsqlite "github.com/fr0stylo/ddash/apps/ddash/internal/infrastructure/sqlite/ingestion"
+	"github.com/fr0stylo/ddash/apps/ddash/internal/server/routes"
+	"github.com/fr0stylo/ddash/internal/config"
+	"github.com/fr0stylo/ddash/internal/db"
+	"github.com/fr0stylo/ddash/internal/pgdb"
+)
+
+// appStore is the set of ports every storage backend implements.
+type appStore interface {
+	ports.AppStore
+	ports.ServiceReadStore
+	ports.GitHubInstallationStore
+	ports.GitLabProjectStore
+	ports.DeadLetterStore
+	ports.IngestCredentialStore
+	ports.IngestHealthStore
+}
+
+// storage is the opened storage backend selected by DatabaseConfig.
+type storage struct {
+	store           appStore
+	ingestionStores ports.IngestionStoreFactory
+	// views carries the stores for views only the backend supports.
+	views routes.ViewExternalConfig
+	// start launches backend background jobs and returns their shutdown.
+	start func() func()
+	close func() error
+}
+
+func openStorage(log *slog.Logger, cfg config.DatabaseConfig) (*storage, error) {
+	switch cfg.Driver {
+	case config.DatabaseDriverPostgres:
+		return openPostgresStorage(cfg)
+	default:
+		return openSQLiteStorage(log, cfg)
+	}
+}
+
+func openSQLiteStorage(log *slog.Logger, cfg config.DatabaseConfig) (*storage, error) {
+	database, err := db.NewWithOptions(cfg.Path, db.Options{ReadPoolSize: cfg.ReadPoolSize, ReadPoolIdle: cfg.ReadPoolIdle})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if cfg.LogTiming {
+		go logDBLatencyStats(log, database)
+	}
+
+	store := sqlite.NewStore(database)
+	return &storage{
+		store:           store,
+		ingestionStores: ingestionsqlite.NewSharedStoreFactory(database),
+		views: routes.ViewExternalConfig{
+			Projections:        store,
+			Projectors:         store,
+			Events:             store,
+			Chains:             store,
+			EnvironmentHistory: store,
+			ServiceIdentities:  store,
+			Environments:       store,
+		},
+		start: func() func() {
+			projectors := appcatalog.StartProjectorRunner(store, appcatalog.ProjectorRunnerOptions{})
+			retention := appcatalog.StartEventRetentionRunner(store, appcatalog.EventRetentionRunnerOptions{Dir: cfg.ArchiveDir})
+			var backups *appcatalog.BackupRunner
+			if cfg.BackupInterval > 0 {
+				backups = appcatalog.StartBackupRunner(store, appcatalog.BackupRunnerOptions{
+					Dir:      cfg.BackupDir,
+					Interval: cfg.BackupInterval,
+					Keep:     cfg.BackupKeep,
+				})
+			}
+			return func() {
+				if backups != nil {
+					backups.Close()
+				}
+				retention.Close()
+				projectors.Close()
+			}
+		},
+		close: database.Close,
+	}, nil
+}
+
+// openPostgresStorage opens the PostgreSQL backend. Its projections are
+// applied synchronously on append, so there are no projector or retention
+// jobs. The projector, event explorer, chain and environment history views
+// stay disabled, so the pages that link to them hide those links.
+func openPostgresStorage(cfg config.DatabaseConfig) (*storage, error) {
+	database, err := pgdb.New(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	store := postgres.NewStore(database)
+	return &storage{
+		store:           store,
+		ingestionStores: ingestionpostgres.NewSharedStoreFactory(database),
+		views: routes.ViewExternalConfig{
+			Projections:       store,
+			ServiceIdentities: store,
+			Environments:      store,
+		},
+		start: func() func() { return func() {} },
+		close: database.Close,
+	}, nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/fr0stylo/ddash/internal/config"
+	"github.com/fr0stylo/ddash/internal/db"
+)
+
+func main() {
+	var (
+		dbPath      string
+		archiveDir  string
+		restorePath string
+	)
+
+	if err := godotenv.Load(); err != nil {
+		log.Printf("no .env file loaded: %v", err)
+	}
+
+	cfg, err := config.LoadForTool()
+	if err != nil {
+		log.Fatalf("load config: %v", err)
+	}
+
+	flag.StringVar(&dbPath, "db", cfg.Database.Path, "database path without .sqlite suffix")
+	flag.StringVar(&archiveDir, "dir", cfg.Database.ArchiveDir, "directory archives are written to")
+	flag.StringVar(&restorePath, "restore", "", "archive file to restore into the event store instead of archiving")
+	flag.Parse()
+
+	database, err := db.New(dbPath)
+	if err != nil {
+		log.Fatalf("open database: %v", err)
+	}
+	defer func() { _ = database.Close() }()
+
+	ctx := context.Background()
+	if restorePath != "" {
+		restored, err := database.RestoreEventArchive(ctx, restorePath)
+		if err != nil {
+			log.Fatalf("restore archive: %v", err)
+		}
+		fmt.Printf("restored %d events from %s\n", restored, restorePath)
+		return
+	}
+
+	archives, err := database.ArchiveExpiredEvents(ctx, archiveDir, time.Now())
+	for _, archive := range archives {
+		fmt.Printf("org %d: archived %d events before %s to %s\n", archive.OrganizationID, archive.EventCount, archive.ArchivedBefore.Format(time.DateOnly), archive.Path)
+	}
+	if err != nil {
+		log.Fatalf("archive events: %v", err)
+	}
+	fmt.Printf("event retention complete: %d archives written\n", len(archives))
+}
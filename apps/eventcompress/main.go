@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/fr0stylo/ddash/internal/config"
+	"github.com/fr0stylo/ddash/internal/db"
+)
+
+func main() {
+	var (
+		dbPath    string
+		batchSize int64
+		pause     time.Duration
+	)
+
+	if err := godotenv.Load(); err != nil {
+		log.Printf("no .env file loaded: %v", err)
+	}
+
+	cfg, err := config.LoadForTool()
+	if err != nil {
+		log.Fatalf("load config: %v", err)
+	}
+
+	flag.StringVar(&dbPath, "db", cfg.Database.Path, "database path without .sqlite suffix")
+	flag.Int64Var(&batchSize, "batch", 500, "events compressed per transaction")
+	flag.DurationVar(&pause, "pause", 50*time.Millisecond, "pause between batches so ingestion can write")
+	flag.Parse()
+
+	database, err := db.New(dbPath)
+	if err != nil {
+		log.Fatalf("open database: %v", err)
+	}
+	defer func() { _ = database.Close() }()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	done, err := database.CompressEventPayloads(ctx, batchSize, pause, func(progress db.EventPayloadCompressionProgress) {
+		fmt.Printf("compressed %d events (through seq %d)\n", progress.Events, progress.LastSeq)
+	})
+	if err != nil {
+		log.Fatalf("compress events after seq %d: %v", done.LastSeq, err)
+	}
+	fmt.Printf("event compression complete: %d events compressed\n", done.Events)
+}
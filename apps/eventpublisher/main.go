@@ -36,6 +36,7 @@ func main() {
 	pipelineURL := flag.String("pipeline-url", "", "Pipeline run URL (optional)")
 	source := flag.String("source", strings.TrimSpace(v.GetString("DDASH_EVENT_SOURCE")), "Event source")
 	timeout := flag.Duration("timeout", 10*time.Second, "Request timeout")
+	signatureScheme := flag.String("signature-scheme", strings.TrimSpace(v.GetString("DDASH_SIGNATURE_SCHEME")), "Signature scheme: body or timestamped (or DDASH_SIGNATURE_SCHEME)")
 	flag.Parse()
 	if strings.TrimSpace(*source) == "" {
 		*source = "ci/pipeline"
@@ -46,10 +47,11 @@ func main() {
 	}
 
 	client := eventpublisher.Client{
-		Endpoint: strings.TrimSpace(*endpoint),
-		Token:    strings.TrimSpace(*token),
-		Secret:   strings.TrimSpace(*secret),
-		Timeout:  *timeout,
+		Endpoint:        strings.TrimSpace(*endpoint),
+		Token:           strings.TrimSpace(*token),
+		Secret:          strings.TrimSpace(*secret),
+		Timeout:         *timeout,
+		SignatureScheme: strings.TrimSpace(*signatureScheme),
 	}
 	resolvedType, err := client.Publish(context.Background(), eventpublisher.Event{
 		Type:        strings.TrimSpace(*eventType),
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/joho/godotenv"
+
+	"github.com/fr0stylo/ddash/internal/config"
+	"github.com/fr0stylo/ddash/internal/db"
+)
+
+func main() {
+	var (
+		dbPath         string
+		organizationID int64
+		outPath        string
+		importPath     string
+		targetID       int64
+		name           string
+		owner          string
+		reassign       bool
+	)
+
+	if err := godotenv.Load(); err != nil {
+		log.Printf("no .env file loaded: %v", err)
+	}
+
+	cfg, err := config.LoadForTool()
+	if err != nil {
+		log.Fatalf("load config: %v", err)
+	}
+
+	flag.StringVar(&dbPath, "db", cfg.Database.Path, "database path without .sqlite suffix")
+	flag.Int64Var(&organizationID, "export", 0, "organization id to export")
+	flag.StringVar(&outPath, "out", "", "bundle file written by -export (default org-<id>.ndjson.gz)")
+	flag.StringVar(&importPath, "import", "", "bundle file to import")
+	flag.Int64Var(&targetID, "into", 0, "existing organization id to import into (default: create a new organization)")
+	flag.StringVar(&name, "name", "", "name of the organization created by -import (default: name from the bundle)")
+	flag.BoolVar(&reassign, "reassign", false, "move GitHub installation and GitLab project mappings owned by another organization")
+	flag.StringVar(&owner, "owner", "", "email or nickname of an existing user made owner of the imported organization")
+	flag.Parse()
+
+	if (organizationID > 0) == (importPath != "") {
+		log.Fatalf("exactly one of -export or -import is required")
+	}
+
+	database, err := db.New(dbPath)
+	if err != nil {
+		log.Fatalf("open database: %v", err)
+	}
+	defer func() { _ = database.Close() }()
+
+	ctx := context.Background()
+	if organizationID > 0 {
+		if outPath == "" {
+			outPath = fmt.Sprintf("org-%d.ndjson.gz", organizationID)
+		}
+		result, err := exportBundle(ctx, database, organizationID, outPath)
+		if err != nil {
+			log.Fatalf("export organization: %v", err)
+		}
+		fmt.Printf("exported organization %q (%d): %d events to %s\n", result.Name, result.OrganizationID, result.EventCount, outPath)
+		return
+	}
+
+	file, err := os.Open(importPath)
+	if err != nil {
+		log.Fatalf("open bundle: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	result, err := database.ImportOrganizationBundle(ctx, file, db.OrganizationImportOptions{
+		OrganizationID: targetID,
+		Name:           name,
+		Reassign:       reassign,
+	})
+	if err != nil {
+		log.Fatalf("import organization: %v", err)
+	}
+	if owner != "" {
+		if err := addOwner(ctx, database, result.OrganizationID, owner); err != nil {
+			log.Fatalf("add owner: %v", err)
+		}
+	}
+	action := "updated"
+	if result.Created {
+		action = "created"
+	}
+	fmt.Printf("%s organization %q (%d)\n", action, result.Name, result.OrganizationID)
+	for _, installationID := range result.SkippedGitHubInstallations {
+		fmt.Printf("skipped GitHub installation %d: mapped to another organization (use -reassign to move it)\n", installationID)
+	}
+	for _, projectID := range result.SkippedGitLabProjects {
+		fmt.Printf("skipped GitLab project %d: mapped to another organization (use -reassign to move it)\n", projectID)
+	}
+	fmt.Printf("events imported: %d, already present: %d\n", result.EventsImported, result.EventsSkipped)
+	fmt.Printf("service_current_state rows: %d\n", result.Projections.CurrentStateRows)
+	fmt.Printf("service_env_state rows: %d\n", result.Projections.EnvStateRows)
+}
+
+func exportBundle(ctx context.Context, database *db.Database, organizationID int64, path string) (db.OrganizationExportResult, error) {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return db.OrganizationExportResult{}, err
+	}
+	tmpPath := file.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	result, err := database.ExportOrganizationBundle(ctx, organizationID, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return result, err
+	}
+	return result, os.Rename(tmpPath, path)
+}
+
+func addOwner(ctx context.Context, database *db.Database, organizationID int64, owner string) error {
+	user, err := database.GetUserByEmailOrNickname(ctx, owner, owner)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("user %q not found", owner)
+	}
+	if err != nil {
+		return err
+	}
+	return database.UpsertOrganizationMember(ctx, organizationID, user.ID, "owner")
+}
@@ -28,7 +28,7 @@ func main() {
 	}
 
 	flag.StringVar(&dbPath, "db", cfg.Database.Path, "database path without .sqlite suffix")
-	flag.Int64Var(&organizationID, "org", 0, "organization id for stats output (rebuild is global)")
+	flag.Int64Var(&organizationID, "org", 0, "organization id to rebuild (0 rebuilds every organization)")
 	flag.Parse()
 
 	database, err := db.New(dbPath)
@@ -37,7 +37,9 @@ func main() {
 	}
 	defer func() { _ = database.Close() }()
 
-	stats, err := database.RebuildServiceProjections(context.Background(), organizationID)
+	stats, err := database.RebuildServiceProjectionsWithProgress(context.Background(), organizationID, func(step db.ProjectionRebuildStep) {
+		fmt.Printf("org %d [%d/%d] %s\n", step.OrganizationID, step.Index, step.Total, step.Name)
+	})
 	if err != nil {
 		log.Fatalf("rebuild projections: %v", err)
 	}
@@ -47,4 +49,10 @@ func main() {
 	fmt.Printf("service_env_state rows: %d\n", stats.EnvStateRows)
 	fmt.Printf("service_delivery_stats_daily rows: %d\n", stats.DailyStatsRows)
 	fmt.Printf("service_change_links rows: %d\n", stats.ChangeLinkRows)
+	fmt.Printf("service_pipeline_stats_daily rows: %d\n", stats.PipelineStatsRows)
+	fmt.Printf("service_deployment_durations rows: %d\n", stats.DeploymentDurationRows)
+	fmt.Printf("service_environment_drift rows: %d\n", stats.EnvironmentDriftRows)
+	fmt.Printf("service_redeployment_stats rows: %d\n", stats.RedeploymentStatsRows)
+	fmt.Printf("service_throughput_stats rows: %d\n", stats.ThroughputStatsRows)
+	fmt.Printf("service_incident_links rows: %d\n", stats.IncidentLinkRows)
 }
@@ -22,9 +22,32 @@ type ServerConfig struct {
 	Port int
 }
 
+// Database drivers selectable through DDASH_DB_DRIVER.
+const (
+	DatabaseDriverSQLite   = "sqlite"
+	DatabaseDriverPostgres = "postgres"
+)
+
 type DatabaseConfig struct {
-	Path      string
+	// Driver selects the storage backend: sqlite (default) or postgres.
+	Driver string
+	Path   string
+	// URL is the PostgreSQL connection string used by the postgres driver.
+	URL       string
 	LogTiming bool
+	// ReadPoolSize caps the read-only SQLite connections serving dashboard
+	// reads; writes always go through a single connection.
+	ReadPoolSize int
+	// ReadPoolIdle is how many read connections are kept open when idle.
+	ReadPoolIdle int
+	// ArchiveDir receives event archives written by the retention job.
+	ArchiveDir string
+	// BackupDir receives scheduled database snapshots.
+	BackupDir string
+	// BackupInterval is the time between snapshots; zero disables backups.
+	BackupInterval time.Duration
+	// BackupKeep is how many snapshots are kept.
+	BackupKeep int
 }
 
 type AuthConfig struct {
@@ -50,6 +73,10 @@ type IngestionConfig struct {
 	BatchEnabled bool
 	BatchSize    int
 	BatchFlushMS int
+	// SpoolDir enables the durable ingest spool when set.
+	SpoolDir string
+	// SignatureSkewSeconds bounds clock drift for timestamped webhook signatures.
+	SignatureSkewSeconds int
 }
 
 type IntegrationsConfig struct {
@@ -75,8 +102,16 @@ func load(requireSessionSecret bool) (Config, error) {
 	v.SetDefault("app_env", "")
 	v.SetDefault("go_env", "")
 	v.SetDefault("ddash_port", 8080)
+	v.SetDefault("ddash_db_driver", DatabaseDriverSQLite)
 	v.SetDefault("ddash_db_path", "data/default")
+	v.SetDefault("ddash_db_url", "")
 	v.SetDefault("ddash_db_timing", false)
+	v.SetDefault("ddash_db_read_pool_size", 4)
+	v.SetDefault("ddash_db_read_pool_idle", 0)
+	v.SetDefault("ddash_event_archive_dir", "data/archive")
+	v.SetDefault("ddash_backup_dir", "data/backups")
+	v.SetDefault("ddash_backup_interval_minutes", 0)
+	v.SetDefault("ddash_backup_keep", 7)
 	v.SetDefault("ddash_secure_cookie", false)
 	v.SetDefault("ddash_otel_enabled", false)
 	v.SetDefault("otel_exporter_otlp_endpoint", "")
@@ -92,6 +127,8 @@ func load(requireSessionSecret bool) (Config, error) {
 	v.SetDefault("ddash_ingest_batch_enabled", true)
 	v.SetDefault("ddash_ingest_batch_size", 100)
 	v.SetDefault("ddash_ingest_batch_flush_ms", 50)
+	v.SetDefault("ddash_ingest_spool_dir", "")
+	v.SetDefault("ddash_ingest_signature_skew_seconds", 300)
 	v.SetDefault("ddash_public_url", "")
 	v.SetDefault("github_app_install_url", "")
 	v.SetDefault("github_app_ingestor_setup_token", "")
@@ -126,6 +163,34 @@ func load(requireSessionSecret bool) (Config, error) {
 		batchFlush = 5000
 	}
 
+	readPoolSize := v.GetInt("ddash_db_read_pool_size")
+	if readPoolSize <= 0 {
+		readPoolSize = 4
+	}
+	if readPoolSize > 64 {
+		readPoolSize = 64
+	}
+
+	readPoolIdle := v.GetInt("ddash_db_read_pool_idle")
+	if readPoolIdle <= 0 || readPoolIdle > readPoolSize {
+		readPoolIdle = readPoolSize
+	}
+
+	backupInterval := v.GetInt("ddash_backup_interval_minutes")
+	if backupInterval < 0 {
+		backupInterval = 0
+	}
+
+	backupKeep := v.GetInt("ddash_backup_keep")
+	if backupKeep <= 0 {
+		backupKeep = 7
+	}
+
+	signatureSkew := v.GetInt("ddash_ingest_signature_skew_seconds")
+	if signatureSkew <= 0 {
+		signatureSkew = 300
+	}
+
 	callbackURL := strings.TrimSpace(v.GetString("github_callback_url"))
 	if callbackURL == "" {
 		callbackURL = fmt.Sprintf("http://localhost:%d/auth/github/callback", port)
@@ -160,8 +225,16 @@ func load(requireSessionSecret bool) (Config, error) {
 		Environment: env,
 		Server:      ServerConfig{Port: port},
 		Database: DatabaseConfig{
-			Path:      strings.TrimSpace(v.GetString("ddash_db_path")),
-			LogTiming: v.GetBool("ddash_db_timing"),
+			Driver:         strings.ToLower(strings.TrimSpace(v.GetString("ddash_db_driver"))),
+			Path:           strings.TrimSpace(v.GetString("ddash_db_path")),
+			URL:            strings.TrimSpace(v.GetString("ddash_db_url")),
+			LogTiming:      v.GetBool("ddash_db_timing"),
+			ReadPoolSize:   readPoolSize,
+			ReadPoolIdle:   readPoolIdle,
+			ArchiveDir:     strings.TrimSpace(v.GetString("ddash_event_archive_dir")),
+			BackupDir:      strings.TrimSpace(v.GetString("ddash_backup_dir")),
+			BackupInterval: time.Duration(backupInterval) * time.Minute,
+			BackupKeep:     backupKeep,
 		},
 		Auth: AuthConfig{
 			SessionSecret:      strings.TrimSpace(v.GetString("ddash_session_secret")),
@@ -181,9 +254,11 @@ func load(requireSessionSecret bool) (Config, error) {
 			MetricsConsole:    metricsConsole,
 		},
 		Ingestion: IngestionConfig{
-			BatchEnabled: v.GetBool("ddash_ingest_batch_enabled"),
-			BatchSize:    batchSize,
-			BatchFlushMS: batchFlush,
+			BatchEnabled:         v.GetBool("ddash_ingest_batch_enabled"),
+			BatchSize:            batchSize,
+			BatchFlushMS:         batchFlush,
+			SpoolDir:             strings.TrimSpace(v.GetString("ddash_ingest_spool_dir")),
+			SignatureSkewSeconds: signatureSkew,
 		},
 		Integrations: IntegrationsConfig{
 			PublicURL:           strings.TrimSpace(v.GetString("ddash_public_url")),
@@ -192,9 +267,27 @@ func load(requireSessionSecret bool) (Config, error) {
 		},
 	}
 
+	if cfg.Database.Driver == "" {
+		cfg.Database.Driver = DatabaseDriverSQLite
+	}
+	switch cfg.Database.Driver {
+	case DatabaseDriverSQLite:
+	case DatabaseDriverPostgres:
+		if cfg.Database.URL == "" {
+			return Config{}, fmt.Errorf("DDASH_DB_URL is required when DDASH_DB_DRIVER is postgres")
+		}
+	default:
+		return Config{}, fmt.Errorf("invalid DDASH_DB_DRIVER: %s", cfg.Database.Driver)
+	}
 	if strings.TrimSpace(cfg.Database.Path) == "" {
 		cfg.Database.Path = "data/default"
 	}
+	if cfg.Database.ArchiveDir == "" {
+		cfg.Database.ArchiveDir = "data/archive"
+	}
+	if cfg.Database.BackupDir == "" {
+		cfg.Database.BackupDir = "data/backups"
+	}
 	if requireSessionSecret && !cfg.IsLocalDevelopment() && cfg.Auth.SessionSecret == "" {
 		return Config{}, fmt.Errorf("DDASH_SESSION_SECRET is required outside local/dev environments")
 	}
@@ -260,6 +353,10 @@ func (c Config) IngestionBatchFlushInterval() time.Duration {
 	return time.Duration(c.Ingestion.BatchFlushMS) * time.Millisecond
 }
 
+func (c Config) IngestionSignatureSkew() time.Duration {
+	return time.Duration(c.Ingestion.SignatureSkewSeconds) * time.Second
+}
+
 func resolveEnvironment(v *viper.Viper) string {
 	for _, key := range []string{"ddash_env", "app_env", "go_env"} {
 		value := strings.TrimSpace(v.GetString(key))
@@ -1,6 +1,9 @@
 package config
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestLoadDefaultsForLocalDevelopment(t *testing.T) {
 	t.Setenv("DDASH_ENV", "dev")
@@ -72,3 +75,81 @@ func TestLoadParsesOTLPHeadersAndMetricsConsole(t *testing.T) {
 		t.Fatalf("expected metric-specific header, got %#v", cfg.Observability.OTLPMetricHeaders)
 	}
 }
+
+func TestLoadSelectsDatabaseDriver(t *testing.T) {
+	t.Setenv("DDASH_ENV", "dev")
+	t.Setenv("DDASH_SESSION_SECRET", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Database.Driver != DatabaseDriverSQLite {
+		t.Fatalf("expected sqlite driver by default, got %q", cfg.Database.Driver)
+	}
+
+	t.Setenv("DDASH_DB_DRIVER", "postgres")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for postgres driver without DDASH_DB_URL")
+	}
+
+	t.Setenv("DDASH_DB_URL", "postgres://ddash@localhost:5432/ddash")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("load postgres config: %v", err)
+	}
+	if cfg.Database.Driver != DatabaseDriverPostgres || cfg.Database.URL != "postgres://ddash@localhost:5432/ddash" {
+		t.Fatalf("unexpected database config: %+v", cfg.Database)
+	}
+
+	t.Setenv("DDASH_DB_DRIVER", "mysql")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for unknown database driver")
+	}
+}
+
+func TestLoadClampsDatabaseReadPool(t *testing.T) {
+	t.Setenv("DDASH_ENV", "dev")
+	t.Setenv("DDASH_SESSION_SECRET", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Database.ReadPoolSize != 4 || cfg.Database.ReadPoolIdle != 4 {
+		t.Fatalf("unexpected default read pool: size=%d idle=%d", cfg.Database.ReadPoolSize, cfg.Database.ReadPoolIdle)
+	}
+
+	t.Setenv("DDASH_DB_READ_POOL_SIZE", "500")
+	t.Setenv("DDASH_DB_READ_POOL_IDLE", "2")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Database.ReadPoolSize != 64 || cfg.Database.ReadPoolIdle != 2 {
+		t.Fatalf("unexpected read pool: size=%d idle=%d", cfg.Database.ReadPoolSize, cfg.Database.ReadPoolIdle)
+	}
+}
+
+func TestLoadReadsBackupSchedule(t *testing.T) {
+	t.Setenv("DDASH_ENV", "dev")
+	t.Setenv("DDASH_SESSION_SECRET", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Database.BackupInterval != 0 || cfg.Database.BackupKeep != 7 || cfg.Database.BackupDir != "data/backups" {
+		t.Fatalf("unexpected default backup config: %+v", cfg.Database)
+	}
+
+	t.Setenv("DDASH_BACKUP_INTERVAL_MINUTES", "90")
+	t.Setenv("DDASH_BACKUP_KEEP", "3")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Database.BackupInterval != 90*time.Minute || cfg.Database.BackupKeep != 3 {
+		t.Fatalf("unexpected backup config: %+v", cfg.Database)
+	}
+}
@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fr0stylo/ddash/internal/db/queries"
+)
+
+type analyticsSnapshot struct {
+	pipelineStarted   int64
+	pipelineSucceeded int64
+	pipelineDuration  int64
+	durationSamples   int64
+	driftCount        int64
+	redeployCount     int64
+	changesCount      int64
+	deploymentsCount  int64
+}
+
+func TestAppendEventStore_UpdatesAnalyticsProjections(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+	appendAnalyticsFixture(t, ctx, database, org.ID)
+
+	got := loadAnalyticsSnapshot(t, ctx, database, org.ID, "payments")
+	want := analyticsSnapshot{
+		pipelineStarted:   1,
+		pipelineSucceeded: 1,
+		pipelineDuration:  90,
+		durationSamples:   3,
+		driftCount:        2,
+		redeployCount:     1,
+		changesCount:      1,
+		deploymentsCount:  3,
+	}
+	if got != want {
+		t.Fatalf("unexpected analytics snapshot:\n got=%+v\nwant=%+v", got, want)
+	}
+}
+
+func TestRebuildServiceProjections_RebuildsAnalyticsProjections(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+	appendAnalyticsFixture(t, ctx, database, org.ID)
+
+	before := loadAnalyticsSnapshot(t, ctx, database, org.ID, "payments")
+	stats, err := database.RebuildServiceProjections(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("rebuild projections: %v", err)
+	}
+	after := loadAnalyticsSnapshot(t, ctx, database, org.ID, "payments")
+	if after != before {
+		t.Fatalf("rebuild changed analytics:\nbefore=%+v\n after=%+v", before, after)
+	}
+	if stats.PipelineStatsRows != 1 || stats.DeploymentDurationRows != 3 || stats.EnvironmentDriftRows != 2 || stats.ThroughputStatsRows == 0 || stats.RedeploymentStatsRows == 0 {
+		t.Fatalf("unexpected rebuild stats: %+v", stats)
+	}
+}
+
+func appendAnalyticsFixture(t *testing.T, ctx context.Context, database *Database, organizationID int64) {
+	t.Helper()
+
+	appendSubjectEvent(t, ctx, database, organizationID, "run-start", "dev.cdevents.pipeline.run.started.0.3.0", recentTimestamp(0), "pipeline", "pipeline/payments/42", "staging", "pkg:generic/payments@abc")
+	appendSubjectEvent(t, ctx, database, organizationID, "run-done", "dev.cdevents.pipeline.run.succeeded.0.3.0", recentTimestamp(90*time.Second), "pipeline", "pipeline/payments/42", "staging", "pkg:generic/payments@abc")
+	appendSubjectEvent(t, ctx, database, organizationID, "change-1", "dev.cdevents.change.merged.0.3.0", recentTimestamp(2*time.Minute), "change", "change/abc", "", "pkg:generic/payments@abc")
+	appendEvent(t, ctx, database, organizationID, "deploy-1", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(3*time.Minute), "service/payments", "staging", "pkg:generic/payments@abc")
+	appendEvent(t, ctx, database, organizationID, "deploy-2", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(4*time.Minute), "service/payments", "staging", "pkg:generic/payments@abc")
+	appendEvent(t, ctx, database, organizationID, "deploy-3", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(5*time.Minute), "service/payments", "production", "pkg:generic/payments@old")
+}
+
+func loadAnalyticsSnapshot(t *testing.T, ctx context.Context, database *Database, organizationID int64, service string) analyticsSnapshot {
+	t.Helper()
+
+	pipeline, err := database.GetPipelineStats30d(ctx, queries.GetPipelineStats30dParams{OrganizationID: organizationID, ServiceName: service})
+	if err != nil {
+		t.Fatalf("get pipeline stats: %v", err)
+	}
+	durations, err := database.GetDeploymentDurationStats(ctx, queries.GetDeploymentDurationStatsParams{
+		OrganizationID: organizationID,
+		ServiceName:    service,
+		Environment:    "staging",
+		SinceMs:        0,
+	})
+	if err != nil {
+		t.Fatalf("get deployment durations: %v", err)
+	}
+	drift, err := database.GetEnvironmentDriftCount(ctx, queries.GetEnvironmentDriftCountParams{OrganizationID: organizationID, ServiceName: service, SinceMs: 0})
+	if err != nil {
+		t.Fatalf("get drift count: %v", err)
+	}
+	redeploy, err := database.GetRedeploymentRate30d(ctx, queries.GetRedeploymentRate30dParams{OrganizationID: organizationID, ServiceName: service})
+	if err != nil {
+		t.Fatalf("get redeployment rate: %v", err)
+	}
+	throughput, err := database.GetThroughputStats(ctx, queries.GetThroughputStatsParams{OrganizationID: organizationID, ServiceName: service})
+	if err != nil {
+		t.Fatalf("get throughput: %v", err)
+	}
+
+	return analyticsSnapshot{
+		pipelineStarted:   toInt64(pipeline.PipelineStartedCount),
+		pipelineSucceeded: toInt64(pipeline.PipelineSucceededCount),
+		pipelineDuration:  toInt64(pipeline.TotalDurationSeconds),
+		durationSamples:   durations.SampleCount,
+		driftCount:        drift,
+		redeployCount:     toInt64(redeploy.RedeployCount),
+		changesCount:      toInt64(throughput.ChangesCount),
+		deploymentsCount:  toInt64(throughput.DeploymentsCount),
+	}
+}
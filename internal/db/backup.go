@@ -0,0 +1,360 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+const (
+	backupFilePrefix     = "ddash-"
+	backupFileSuffix     = ".sqlite"
+	backupChecksumSuffix = ".sha256"
+	backupTimeLayout     = "20060102T150405.000Z"
+)
+
+// ErrBackupChecksumMismatch is returned when a snapshot no longer matches the
+// checksum recorded when it was written.
+var ErrBackupChecksumMismatch = errors.New("backup checksum mismatch")
+
+// ErrDatabaseInUse is returned when a restore finds the database open
+// elsewhere, such as in a running server.
+var ErrDatabaseInUse = errors.New("database is in use")
+
+// BackupResult describes one snapshot written by CreateBackup.
+type BackupResult struct {
+	Path      string
+	Checksum  string
+	Size      int64
+	CreatedAt time.Time
+	// Pruned lists snapshots removed by rotation.
+	Pruned []string
+}
+
+// BackupFile is one snapshot found in a backup directory.
+type BackupFile struct {
+	Path      string
+	CreatedAt time.Time
+}
+
+// CreateBackup writes a consistent snapshot of the database into dir with
+// VACUUM INTO, records its SHA-256 checksum next to it and removes all but
+// the keep newest snapshots. keep <= 0 keeps every snapshot.
+//
+// The snapshot is taken on a connection of its own, so appends carry on
+// while it is written.
+func (c *Database) CreateBackup(ctx context.Context, dir string, keep int, now time.Time) (BackupResult, error) {
+	now = now.UTC()
+	result := BackupResult{CreatedAt: now}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return result, err
+	}
+	path := filepath.Join(dir, backupFilePrefix+now.Format(backupTimeLayout)+backupFileSuffix)
+	if _, err := os.Stat(path); err == nil {
+		return result, fmt.Errorf("backup %s already exists", path)
+	}
+
+	tmpPath := path + ".tmp"
+	_ = os.Remove(tmpPath)
+	defer func() { _ = os.Remove(tmpPath) }()
+	if err := c.vacuumInto(ctx, tmpPath); err != nil {
+		return result, fmt.Errorf("snapshot database: %w", err)
+	}
+	checksum, size, err := syncAndChecksum(tmpPath)
+	if err != nil {
+		return result, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return result, err
+	}
+	if err := writeChecksumFile(path, checksum); err != nil {
+		_ = os.Remove(path)
+		return result, err
+	}
+	if err := syncDir(dir); err != nil {
+		return result, err
+	}
+	result.Path = path
+	result.Checksum = checksum
+	result.Size = size
+
+	pruned, err := pruneBackups(dir, keep)
+	result.Pruned = pruned
+	return result, err
+}
+
+func (c *Database) vacuumInto(ctx context.Context, path string) error {
+	snapshotDB, err := sql.Open(driver, c.dsn)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = snapshotDB.Close() }()
+	_, err = snapshotDB.ExecContext(ctx, "VACUUM INTO ?", path)
+	return err
+}
+
+// ListBackups returns the snapshots in dir, newest first.
+func ListBackups(dir string) ([]BackupFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	backups := make([]BackupFile, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, backupFilePrefix) || !strings.HasSuffix(name, backupFileSuffix) {
+			continue
+		}
+		createdAt, err := time.Parse(backupTimeLayout, strings.TrimSuffix(strings.TrimPrefix(name, backupFilePrefix), backupFileSuffix))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, BackupFile{Path: filepath.Join(dir, name), CreatedAt: createdAt})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+	return backups, nil
+}
+
+func pruneBackups(dir string, keep int) ([]string, error) {
+	if keep <= 0 {
+		return nil, nil
+	}
+	backups, err := ListBackups(dir)
+	if err != nil || len(backups) <= keep {
+		return nil, err
+	}
+	pruned := make([]string, 0, len(backups)-keep)
+	for _, backup := range backups[keep:] {
+		if err := os.Remove(backup.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return pruned, err
+		}
+		if err := os.Remove(backup.Path + backupChecksumSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return pruned, err
+		}
+		pruned = append(pruned, backup.Path)
+	}
+	return pruned, nil
+}
+
+// VerifyBackup checks a snapshot against its recorded checksum, then opens it
+// read-only, runs an integrity check and applies the embedded migrations
+// read-only. The last step fails for a snapshot whose schema is behind this
+// build, because bringing it up to date would have to write.
+func VerifyBackup(ctx context.Context, path string) error {
+	expected, err := readChecksumFile(path)
+	if err != nil {
+		return err
+	}
+	actual, _, err := fileChecksum(path)
+	if err != nil {
+		return err
+	}
+	if actual != expected {
+		return fmt.Errorf("%w: %s", ErrBackupChecksumMismatch, path)
+	}
+
+	values := url.Values{}
+	values.Set("mode", "ro")
+	values.Add("_pragma", "query_only(ON)")
+	snapshot, err := sql.Open(driver, fmt.Sprintf("file:%s?%s", path, values.Encode()))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = snapshot.Close() }()
+
+	var integrity string
+	if err := snapshot.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&integrity); err != nil {
+		return fmt.Errorf("integrity check: %w", err)
+	}
+	if integrity != "ok" {
+		return fmt.Errorf("integrity check: %s", integrity)
+	}
+
+	// A provider of its own leaves goose's package-level settings alone.
+	migrations, err := fs.Sub(migrationsFS, "migrations")
+	if err != nil {
+		return err
+	}
+	provider, err := goose.NewProvider(goose.DialectSQLite3, snapshot, migrations)
+	if err != nil {
+		return fmt.Errorf("load migrations: %w", err)
+	}
+	if _, err := provider.Up(ctx); err != nil {
+		return fmt.Errorf("migrate snapshot read-only: %w", err)
+	}
+	return nil
+}
+
+// RestoreBackup verifies a snapshot and swaps it in as the database at
+// dbPath (without the .sqlite suffix). The current database, with its WAL
+// and shared-memory files, is moved aside and its new path returned, so a
+// restore can be undone by hand. The server must be stopped first: the
+// restore holds the database's exclusive lock from before the checkpoint
+// until the snapshot is in place, and fails with ErrDatabaseInUse while any
+// process has the database open.
+func RestoreBackup(ctx context.Context, snapshotPath, dbPath string, now time.Time) (string, error) {
+	if err := VerifyBackup(ctx, snapshotPath); err != nil {
+		return "", err
+	}
+	target := dbPath + ".sqlite"
+	dir := filepath.Dir(target)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	lock, err := lockDatabase(dbPath, true)
+	if err != nil {
+		if errors.Is(err, ErrDatabaseInUse) {
+			return "", fmt.Errorf("%w: stop ddash before restoring", err)
+		}
+		return "", err
+	}
+	defer func() { _ = closeLock(lock) }()
+
+	tmpPath := target + ".restore"
+	defer func() { _ = os.Remove(tmpPath) }()
+	if err := copyFile(snapshotPath, tmpPath); err != nil {
+		return "", err
+	}
+
+	var previous string
+	if _, err := os.Stat(target); err == nil {
+		if err := checkpointForRestore(ctx, dbPath); err != nil {
+			return "", err
+		}
+		previous = target + ".pre-restore-" + now.UTC().Format(backupTimeLayout)
+		for _, suffix := range []string{"", "-wal", "-shm"} {
+			if err := os.Rename(target+suffix, previous+suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return "", err
+			}
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	if err := os.Rename(tmpPath, target); err != nil {
+		return previous, err
+	}
+	return previous, syncDir(dir)
+}
+
+// checkpointForRestore folds the WAL into the database file so the file
+// moved aside is complete. It also refuses when a connection that does not
+// take the database lock, such as a sqlite3 shell, keeps the checkpoint from
+// finishing.
+func checkpointForRestore(ctx context.Context, dbPath string) error {
+	current, err := sql.Open(driver, sqliteDSN(dbPath))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = current.Close() }()
+
+	var busy, logFrames, checkpointed int64
+	if err := current.QueryRowContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &logFrames, &checkpointed); err != nil {
+		return fmt.Errorf("checkpoint current database: %w", err)
+	}
+	if busy != 0 {
+		return fmt.Errorf("%w: stop ddash before restoring", ErrDatabaseInUse)
+	}
+	return nil
+}
+
+func syncAndChecksum(path string) (string, int64, error) {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = file.Close() }()
+	if err := file.Sync(); err != nil {
+		return "", 0, err
+	}
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+func fileChecksum(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = file.Close() }()
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// writeChecksumFile stores the checksum in sha256sum format, so snapshots
+// can also be checked with `sha256sum -c`.
+func writeChecksumFile(path, checksum string) error {
+	checksumPath := path + backupChecksumSuffix
+	tmpPath := checksumPath + ".tmp"
+	content := fmt.Sprintf("%s  %s\n", checksum, filepath.Base(path))
+	if err := os.WriteFile(tmpPath, []byte(content), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, checksumPath)
+}
+
+func readChecksumFile(path string) (string, error) {
+	content, err := os.ReadFile(path + backupChecksumSuffix)
+	if err != nil {
+		return "", fmt.Errorf("read checksum: %w", err)
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("read checksum: %s is empty", path+backupChecksumSuffix)
+	}
+	return fields[0], nil
+}
+
+func copyFile(from, to string) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+	dst, err := os.OpenFile(to, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := dst.Sync(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+func syncDir(dir string) error {
+	handle, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = handle.Close() }()
+	return handle.Sync()
+}
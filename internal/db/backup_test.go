@@ -0,0 +1,149 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCreateBackup_RotatesSnapshots(t *testing.T) {
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	createTestOrganization(t, ctx, database)
+	dir := filepath.Join(t.TempDir(), "backups")
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var last BackupResult
+	for i := range 3 {
+		result, err := database.CreateBackup(ctx, dir, 2, start.Add(time.Duration(i)*time.Hour))
+		if err != nil {
+			t.Fatalf("create backup %d: %v", i, err)
+		}
+		last = result
+	}
+	if len(last.Pruned) != 1 {
+		t.Fatalf("unexpected pruned snapshots: %v", last.Pruned)
+	}
+
+	backups, err := ListBackups(dir)
+	if err != nil {
+		t.Fatalf("list backups: %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("unexpected backup count: got=%d want=2", len(backups))
+	}
+	if backups[0].Path != last.Path || !backups[0].CreatedAt.Equal(start.Add(2*time.Hour)) {
+		t.Fatalf("unexpected newest backup: %+v", backups[0])
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ddash-20260301T120000.000Z.sqlite.sha256")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected pruned checksum to be removed, got %v", err)
+	}
+	if err := VerifyBackup(ctx, last.Path); err != nil {
+		t.Fatalf("verify backup: %v", err)
+	}
+}
+
+func TestVerifyBackup_DetectsCorruption(t *testing.T) {
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	result, err := database.CreateBackup(ctx, t.TempDir(), 0, time.Now())
+	if err != nil {
+		t.Fatalf("create backup: %v", err)
+	}
+
+	file, err := os.OpenFile(result.Path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("open backup: %v", err)
+	}
+	if _, err := file.Write([]byte("tampered")); err != nil {
+		t.Fatalf("tamper backup: %v", err)
+	}
+	_ = file.Close()
+
+	if err := VerifyBackup(ctx, result.Path); !errors.Is(err, ErrBackupChecksumMismatch) {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+}
+
+func TestRestoreBackup_SwapsSnapshotIn(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "restore")
+	database, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	org := createTestOrganization(t, ctx, database)
+	backup, err := database.CreateBackup(ctx, t.TempDir(), 0, time.Now())
+	if err != nil {
+		t.Fatalf("create backup: %v", err)
+	}
+	if err := database.DeleteOrganization(ctx, org.ID); err != nil {
+		t.Fatalf("delete organization: %v", err)
+	}
+	if err := database.Close(); err != nil {
+		t.Fatalf("close db: %v", err)
+	}
+
+	previous, err := RestoreBackup(ctx, backup.Path, dbPath, time.Now())
+	if err != nil {
+		t.Fatalf("restore backup: %v", err)
+	}
+	if _, err := os.Stat(previous); err != nil {
+		t.Fatalf("expected previous database to be kept: %v", err)
+	}
+
+	restored, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("reopen db: %v", err)
+	}
+	t.Cleanup(func() { _ = restored.Close() })
+	if _, err := restored.GetOrganizationByID(ctx, org.ID); err != nil {
+		t.Fatalf("expected restored organization: %v", err)
+	}
+}
+
+func TestRestoreBackup_RefusesWhileDatabaseIsOpen(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "busy")
+	database, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+	org := createTestOrganization(t, ctx, database)
+	backup, err := database.CreateBackup(ctx, t.TempDir(), 0, time.Now())
+	if err != nil {
+		t.Fatalf("create backup: %v", err)
+	}
+
+	// The open database is idle: no transaction keeps a checkpoint busy.
+	if _, err := RestoreBackup(ctx, backup.Path, dbPath, time.Now()); !errors.Is(err, ErrDatabaseInUse) {
+		t.Fatalf("expected database in use, got %v", err)
+	}
+	matches, err := filepath.Glob(dbPath + ".sqlite.pre-restore-*")
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	if len(matches) != 0 {
+		t.Fatalf("expected the live database to stay in place, found %v", matches)
+	}
+	if _, err := database.GetOrganizationByID(ctx, org.ID); err != nil {
+		t.Fatalf("expected the open database to keep working: %v", err)
+	}
+}
+
+func TestNew_RefusesWhileRestoreHoldsTheLock(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "restoring")
+	lock, err := lockDatabase(dbPath, true)
+	if err != nil {
+		t.Fatalf("take exclusive lock: %v", err)
+	}
+	defer func() { _ = closeLock(lock) }()
+
+	if _, err := New(dbPath); !errors.Is(err, ErrDatabaseInUse) {
+		t.Fatalf("expected database in use, got %v", err)
+	}
+}
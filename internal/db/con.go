@@ -3,11 +3,15 @@ package db
 import (
 	"database/sql"
 	"embed"
+	"errors"
 	"fmt"
 	"net/url"
+	"os"
+	"slices"
 	"strings"
 
 	"github.com/pressly/goose/v3"
+	"go.opentelemetry.io/otel/metric"
 	// SQLite driver.
 	_ "modernc.org/sqlite"
 
@@ -19,38 +23,110 @@ var migrationsFS embed.FS
 
 var driver = "sqlite"
 
-// Database wraps sqlc queries with the shared connection.
+// Database wraps sqlc queries with the shared connections. Writes go through
+// a single-connection writer pool, so SQLite never queues writers behind its
+// own lock; reads are served by a separate read-only pool and never wait for
+// an ingest commit.
 type Database struct {
 	*queries.Queries
-	db      *sql.DB
-	tracker *queryLatencyTracker
+	db         *sql.DB
+	reader     *queries.Queries
+	readDB     *sql.DB
+	dsn        string
+	tracker    *queryLatencyTracker
+	metrics    metric.Registration
+	projectors []Projector
+	lock       *os.File
+	// identityRules caches compiled service identity rules per organization.
+	identityRules *serviceIdentityRuleCache
 }
 
-// New opens the SQLite database at the provided path.
+// Options tunes the connection pools opened by NewWithOptions.
+type Options struct {
+	// ReadPoolSize caps open read-only connections. Defaults to 4.
+	ReadPoolSize int
+	// ReadPoolIdle is how many read connections stay open when idle.
+	// Defaults to ReadPoolSize.
+	ReadPoolIdle int
+}
+
+const defaultReadPoolSize = 4
+
+// New opens the SQLite database at the provided path with default pool sizes.
 func New(path string, openParams ...string) (*Database, error) {
+	return NewWithOptions(path, Options{}, openParams...)
+}
+
+// NewWithOptions opens the SQLite database at the provided path, migrates it
+// and opens the writer and reader pools.
+func NewWithOptions(path string, options Options, openParams ...string) (*Database, error) {
 	if path == "" {
 		path = "data/default"
 	}
+	lock, err := lockDatabase(path, false)
+	if err != nil {
+		if errors.Is(err, ErrDatabaseInUse) {
+			return nil, fmt.Errorf("%w: a restore is in progress", err)
+		}
+		return nil, err
+	}
 	dsn := sqliteDSN(path, openParams...)
 	db, err := sql.Open(driver, dsn)
 	if err != nil {
+		_ = closeLock(lock)
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
 
 	goose.SetBaseFS(migrationsFS)
 
 	if err := goose.SetDialect(driver); err != nil {
+		_ = db.Close()
+		_ = closeLock(lock)
 		return nil, fmt.Errorf("failed to set goose dialect: %w", err)
 	}
 
 	if err := goose.Up(db, "migrations"); err != nil {
+		_ = db.Close()
+		_ = closeLock(lock)
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	// The reader is opened after migrations so the database file and its WAL
+	// index exist; a read-only connection cannot create them.
+	readDB, err := sql.Open(driver, sqliteReadDSN(path, openParams...))
+	if err != nil {
+		_ = db.Close()
+		_ = closeLock(lock)
+		return nil, fmt.Errorf("failed to open read pool: %w", err)
+	}
+	readPoolSize := options.ReadPoolSize
+	if readPoolSize <= 0 {
+		readPoolSize = defaultReadPoolSize
+	}
+	readPoolIdle := options.ReadPoolIdle
+	if readPoolIdle <= 0 || readPoolIdle > readPoolSize {
+		readPoolIdle = readPoolSize
+	}
+	readDB.SetMaxOpenConns(readPoolSize)
+	readDB.SetMaxIdleConns(readPoolIdle)
+
 	tracker := newQueryLatencyTracker()
-	wrapped := newInstrumentedDBTX(db, tracker)
 
-	return &Database{db: db, Queries: queries.New(wrapped), tracker: tracker}, nil
+	database := &Database{
+		db:            db,
+		Queries:       queries.New(newInstrumentedDBTX(db, tracker)),
+		readDB:        readDB,
+		reader:        queries.New(newInstrumentedDBTX(readDB, tracker)),
+		dsn:           dsn,
+		tracker:       tracker,
+		projectors:    slices.Clone(builtinProjectors),
+		lock:          lock,
+		identityRules: newServiceIdentityRuleCache(),
+	}
+	database.metrics = registerPoolMetrics(database)
+	return database, nil
 }
 
 func sqliteDSN(path string, openParams ...string) string {
@@ -66,6 +142,28 @@ func sqliteDSN(path string, openParams ...string) string {
 	values.Add("_pragma", "wal_autocheckpoint(1000)")
 	values.Add("_pragma", "optimize")
 
+	addOpenParams(values, openParams)
+
+	return fmt.Sprintf("file:%s.sqlite?%s", path, values.Encode())
+}
+
+// sqliteReadDSN opens the database read-only. Pragmas that write, such as
+// journal_mode and optimize, are left to the writer.
+func sqliteReadDSN(path string, openParams ...string) string {
+	values := url.Values{}
+	values.Set("mode", "ro")
+
+	values.Add("_pragma", "query_only(ON)")
+	values.Add("_pragma", "busy_timeout(5000)")
+	values.Add("_pragma", "temp_store(MEMORY)")
+	values.Add("_pragma", "cache_size(-200000)")
+
+	addOpenParams(values, openParams)
+
+	return fmt.Sprintf("file:%s.sqlite?%s", path, values.Encode())
+}
+
+func addOpenParams(values url.Values, openParams []string) {
 	for _, param := range openParams {
 		part := strings.TrimSpace(strings.TrimPrefix(param, "&"))
 		if part == "" {
@@ -77,11 +175,19 @@ func sqliteDSN(path string, openParams ...string) string {
 		}
 		values.Add(strings.TrimSpace(key), strings.TrimSpace(value))
 	}
-
-	return fmt.Sprintf("file:%s.sqlite?%s", path, values.Encode())
 }
 
-// Close closes the underlying database connection.
+// Close closes the writer and reader pools.
 func (c *Database) Close() error {
-	return c.db.Close()
+	if c.metrics != nil {
+		_ = c.metrics.Unregister()
+	}
+	return errors.Join(c.readDB.Close(), c.db.Close(), closeLock(c.lock))
+}
+
+func closeLock(lock *os.File) error {
+	if lock == nil {
+		return nil
+	}
+	return lock.Close()
 }
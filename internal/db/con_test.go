@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fr0stylo/ddash/internal/db/queries"
+)
+
+func TestNewWithOptions_SizesPools(t *testing.T) {
+	database, err := NewWithOptions(filepath.Join(t.TempDir(), "pools"), Options{ReadPoolSize: 3, ReadPoolIdle: 5})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { _ = database.Close() })
+
+	write, read := database.PoolStats()
+	if write.MaxOpenConnections != 1 {
+		t.Fatalf("unexpected writer pool size: got=%d want=1", write.MaxOpenConnections)
+	}
+	if read.MaxOpenConnections != 3 {
+		t.Fatalf("unexpected reader pool size: got=%d want=3", read.MaxOpenConnections)
+	}
+}
+
+func TestReader_IsReadOnly(t *testing.T) {
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+
+	got, err := database.GetOrganizationByID(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("read organization through reader: %v", err)
+	}
+	if got.Name != org.Name {
+		t.Fatalf("reader did not see committed write: got=%q want=%q", got.Name, org.Name)
+	}
+
+	if _, err := database.readDB.ExecContext(ctx, "UPDATE organizations SET name = 'changed' WHERE id = ?", org.ID); err == nil {
+		t.Fatal("expected write through reader to fail")
+	}
+}
+
+func TestReader_DoesNotWaitForWriter(t *testing.T) {
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+
+	release := make(chan struct{})
+	holding := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- database.WithTx(ctx, func(q *queries.Queries) error {
+			if err := q.UpdateOrganizationName(ctx, queries.UpdateOrganizationNameParams{Name: "pending", ID: org.ID}); err != nil {
+				return err
+			}
+			close(holding)
+			<-release
+			return nil
+		})
+	}()
+	<-holding
+
+	readCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	got, err := database.GetOrganizationByID(readCtx, org.ID)
+	close(release)
+	if err != nil {
+		t.Fatalf("read while writer is busy: %v", err)
+	}
+	if got.Name != org.Name {
+		t.Fatalf("reader saw uncommitted write: got=%q want=%q", got.Name, org.Name)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("writer transaction: %v", err)
+	}
+}
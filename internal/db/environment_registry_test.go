@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestEnvironmentRegistry_NormalizesProjectedEnvironments(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+	appendSubjectEvent(t, ctx, database, org.ID, "env-1", "dev.cdevents.environment.created.0.3.0", recentTimestamp(0), "environment", "environment/production", "", "")
+	appendEvent(t, ctx, database, org.ID, "deploy-1", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(time.Minute), "service/payments", "prod", "pkg:generic/payments@abc")
+	appendEvent(t, ctx, database, org.ID, "deploy-2", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(2*time.Minute), "service/payments", "PRODUCTION", "pkg:generic/payments@def")
+	appendEvent(t, ctx, database, org.ID, "deploy-3", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(3*time.Minute), "service/payments", "staging", "pkg:generic/payments@def")
+
+	environments, err := database.ListOrganizationEnvironments(ctx, org.ID)
+	if err != nil || len(environments) != 1 || environments[0].Name != "production" || environments[0].Tier != EnvironmentTierProduction || environments[0].Source != EnvironmentSourceEvent {
+		t.Fatalf("expected the environment event to register production: %+v err=%v", environments, err)
+	}
+
+	if _, err := database.SaveOrganizationEnvironment(ctx, EnvironmentInput{OrganizationID: org.ID, Name: "production", Tier: EnvironmentTierProduction, Color: "#16a34a", Protected: true, Aliases: []string{"prod"}}); err != nil {
+		t.Fatalf("save environment: %v", err)
+	}
+	if _, err := database.SaveOrganizationEnvironment(ctx, EnvironmentInput{OrganizationID: org.ID, Name: "staging", Tier: EnvironmentTierNonProduction, Aliases: []string{"Prod"}}); !errors.Is(err, ErrInvalidEnvironment) {
+		t.Fatalf("expected a taken alias to be rejected, got %v", err)
+	}
+
+	if _, err := database.RebuildServiceProjections(ctx, org.ID); err != nil {
+		t.Fatalf("rebuild projections: %v", err)
+	}
+	appendEvent(t, ctx, database, org.ID, "deploy-4", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(4*time.Minute), "service/payments", "Prod", "pkg:generic/payments@ghi")
+	if names := projectedEnvironmentNames(t, ctx, database, org.ID); !slices.Equal(names, []string{"production", "staging"}) {
+		t.Fatalf("expected environments to be normalized, got %v", names)
+	}
+	priorities, err := database.ListOrganizationEnvironmentPriorities(ctx, org.ID)
+	if err != nil || len(priorities) == 0 || priorities[0].Environment != "production" {
+		t.Fatalf("expected the registry to lead the priorities: %+v err=%v", priorities, err)
+	}
+
+	// Protected environments survive environment.deleted events and have to
+	// be unprotected before they can be deleted in settings.
+	appendSubjectEvent(t, ctx, database, org.ID, "env-2", "dev.cdevents.environment.deleted.0.3.0", time.Now().UTC().Add(time.Hour).Format(time.RFC3339), "environment", "environment/production", "", "")
+	if _, err := database.DeleteOrganizationEnvironment(ctx, org.ID, "production"); !errors.Is(err, ErrProtectedEnvironment) {
+		t.Fatalf("expected protected environment to be kept, got %v", err)
+	}
+	environments, err = database.ListOrganizationEnvironments(ctx, org.ID)
+	if err != nil || len(environments) != 1 || environments[0].Color != "#16a34a" {
+		t.Fatalf("unexpected registry: %+v err=%v", environments, err)
+	}
+}
+
+func projectedEnvironmentNames(t *testing.T, ctx context.Context, database *Database, organizationID int64) []string {
+	t.Helper()
+	rows, err := database.db.QueryContext(ctx, `SELECT environment FROM service_env_state WHERE organization_id = ?1
+		UNION SELECT environment FROM service_change_links WHERE organization_id = ?1
+		UNION SELECT environment FROM service_deployment_durations WHERE organization_id = ?1
+		ORDER BY 1`, organizationID)
+	if err != nil {
+		t.Fatalf("list projected environments: %v", err)
+	}
+	defer func() { _ = rows.Close() }()
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("scan projected environment: %v", err)
+		}
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fr0stylo/ddash/internal/db/queries"
+)
+
+func TestListServiceEnvironmentStateAt_ReturnsLatestEventAtTime(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+	if _, err := database.SaveOrganizationEnvironment(ctx, EnvironmentInput{OrganizationID: org.ID, Name: "production", Tier: EnvironmentTierProduction, Aliases: []string{"prod"}}); err != nil {
+		t.Fatalf("save environment: %v", err)
+	}
+	appendEvent(t, ctx, database, org.ID, "deploy-1", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(0), "service/payments", "staging", "pkg:generic/payments@v1")
+	appendEvent(t, ctx, database, org.ID, "deploy-2", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(time.Hour), "service/payments", "prod", "pkg:generic/payments@v1")
+	appendEvent(t, ctx, database, org.ID, "deploy-3", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(2*time.Hour), "service/payments", "staging", "pkg:generic/payments@v2")
+	appendEvent(t, ctx, database, org.ID, "remove-1", "dev.cdevents.service.removed.0.3.0", recentTimestamp(3*time.Hour), "service/payments", "production", "pkg:generic/payments@v1")
+
+	base, err := time.Parse(time.RFC3339, recentTimestamp(0))
+	if err != nil {
+		t.Fatalf("parse timestamp: %v", err)
+	}
+	stateAt := func(at time.Time) map[string]queries.ListServiceEnvironmentStateAtRow {
+		t.Helper()
+		rows, err := database.ListServiceEnvironmentStateAt(ctx, queries.ListServiceEnvironmentStateAtParams{OrganizationID: org.ID, AtMs: at.UnixMilli()})
+		if err != nil {
+			t.Fatalf("list state at %s: %v", at, err)
+		}
+		out := map[string]queries.ListServiceEnvironmentStateAtRow{}
+		for _, row := range rows {
+			out[row.ServiceName+"/"+row.Environment] = row
+		}
+		return out
+	}
+
+	if state := stateAt(base.Add(-time.Minute)); len(state) != 0 {
+		t.Fatalf("expected nothing before the first deployment, got %+v", state)
+	}
+	state := stateAt(base.Add(90 * time.Minute))
+	if len(state) != 2 || state["payments/staging"].ArtifactID != "pkg:generic/payments@v1" || state["payments/production"].Status != "synced" {
+		t.Fatalf("unexpected state after the production deployment: %+v", state)
+	}
+	state = stateAt(base.Add(4 * time.Hour))
+	if len(state) != 2 || state["payments/staging"].ArtifactID != "pkg:generic/payments@v2" || state["payments/production"].Status != "out-of-sync" {
+		t.Fatalf("unexpected latest state: %+v", state)
+	}
+}
@@ -0,0 +1,343 @@
+package db
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fr0stylo/ddash/internal/db/queries"
+)
+
+const eventArchiveBatchSize = 1000
+
+// ArchivedEvent is one event_store row in an archive file. Archives are
+// gzip-compressed NDJSON, one event per line in append order.
+type ArchivedEvent struct {
+	Seq            int64     `json:"seq"`
+	OrganizationID int64     `json:"organization_id"`
+	EventID        string    `json:"event_id"`
+	EventType      string    `json:"event_type"`
+	EventSource    string    `json:"event_source"`
+	EventTimestamp string    `json:"event_timestamp"`
+	SubjectID      string    `json:"subject_id"`
+	SubjectSource  *string   `json:"subject_source,omitempty"`
+	SubjectType    string    `json:"subject_type"`
+	ChainID        *string   `json:"chain_id,omitempty"`
+	RawEvent       string    `json:"raw_event_json"`
+	IngestedAt     time.Time `json:"ingested_at"`
+	EventTsMs      int64     `json:"event_ts_ms"`
+}
+
+// EventArchiveResult describes one archive written by the retention job.
+type EventArchiveResult struct {
+	OrganizationID int64
+	Path           string
+	EventCount     int64
+	ArchivedBefore time.Time
+}
+
+// EventArchiveCutoff returns the instant before which events fall outside a
+// retention window of retentionDays.
+func EventArchiveCutoff(now time.Time, retentionDays int) time.Time {
+	return startOfUTCWeek(now.AddDate(0, 0, -retentionDays))
+}
+
+// startOfUTCWeek rounds back to Sunday midnight UTC, the week start used by
+// service_throughput_stats, so every daily and weekly projection bucket is
+// either fully archived or fully kept.
+func startOfUTCWeek(t time.Time) time.Time {
+	day := t.UTC().Truncate(24 * time.Hour)
+	return day.AddDate(0, 0, -int(day.Weekday()))
+}
+
+// ArchiveExpiredEvents archives the events of every organization with an
+// event retention policy that fall outside its window.
+func (c *Database) ArchiveExpiredEvents(ctx context.Context, dir string, now time.Time) ([]EventArchiveResult, error) {
+	policies, err := c.Queries.ListEventRetentionPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]EventArchiveResult, 0, len(policies))
+	for _, policy := range policies {
+		result, err := c.ArchiveOrganizationEvents(ctx, policy.OrganizationID, EventArchiveCutoff(now, int(policy.RetentionDays)), dir)
+		if err != nil {
+			return results, fmt.Errorf("archive organization %d: %w", policy.OrganizationID, err)
+		}
+		if result.EventCount > 0 {
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// ArchiveOrganizationEvents exports an organization's events older than
+// before, rounded back to the start of its UTC week, to a compressed archive
+// under dir and deletes them from the store. Projections are left as they
+// are. The environment state at the cutoff is kept so later rebuilds can
+// continue from it.
+func (c *Database) ArchiveOrganizationEvents(ctx context.Context, organizationID int64, before time.Time, dir string) (EventArchiveResult, error) {
+	before = startOfUTCWeek(before)
+	result := EventArchiveResult{OrganizationID: organizationID, ArchivedBefore: before}
+	beforeMs := before.UnixMilli()
+
+	orgDir := filepath.Join(dir, fmt.Sprintf("org-%d", organizationID))
+	if err := os.MkdirAll(orgDir, 0o755); err != nil {
+		return result, err
+	}
+	file, err := os.CreateTemp(orgDir, "events-*.ndjson.gz.tmp")
+	if err != nil {
+		return result, err
+	}
+	tmpPath := file.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	firstSeq, lastSeq, count, err := c.exportArchivedEvents(ctx, file, organizationID, beforeMs)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || count == 0 {
+		return result, err
+	}
+
+	path := filepath.Join(orgDir, fmt.Sprintf("events-%d-%d.ndjson.gz", firstSeq, lastSeq))
+	if err := os.Rename(tmpPath, path); err != nil {
+		return result, err
+	}
+	if err := c.deleteArchivedEvents(ctx, queries.InsertEventArchiveParams{
+		OrganizationID:   organizationID,
+		Path:             path,
+		FirstSeq:         firstSeq,
+		LastSeq:          lastSeq,
+		EventCount:       count,
+		ArchivedBeforeMs: beforeMs,
+		CreatedAt:        time.Now().UTC().UnixMilli(),
+	}); err != nil {
+		// The events are still in the store, so the archive would duplicate
+		// them once the next run exports them again.
+		_ = os.Remove(path)
+		return result, err
+	}
+	result.Path = path
+	result.EventCount = count
+	return result, nil
+}
+
+func (c *Database) exportArchivedEvents(ctx context.Context, file *os.File, organizationID, beforeMs int64) (int64, int64, int64, error) {
+	zw := gzip.NewWriter(file)
+	buffered := bufio.NewWriter(zw)
+	encoder := json.NewEncoder(buffered)
+
+	var firstSeq, lastSeq, count int64
+	for {
+		rows, err := c.Queries.ListEventsForArchive(ctx, queries.ListEventsForArchiveParams{
+			OrganizationID: organizationID,
+			BeforeMs:       beforeMs,
+			AfterSeq:       lastSeq,
+			Limit:          eventArchiveBatchSize,
+		})
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		for _, row := range rows {
+			event, err := archivedEventFromRow(row)
+			if err != nil {
+				return 0, 0, 0, err
+			}
+			if err := encoder.Encode(event); err != nil {
+				return 0, 0, 0, err
+			}
+			if count == 0 {
+				firstSeq = row.Seq
+			}
+			lastSeq = row.Seq
+			count++
+		}
+		if len(rows) < eventArchiveBatchSize {
+			break
+		}
+	}
+	if err := buffered.Flush(); err != nil {
+		return 0, 0, 0, err
+	}
+	if err := zw.Close(); err != nil {
+		return 0, 0, 0, err
+	}
+	return firstSeq, lastSeq, count, file.Sync()
+}
+
+// deleteArchivedEvents folds the archived events into the environment state
+// baseline, deletes them and records the archive in one transaction.
+func (c *Database) deleteArchivedEvents(ctx context.Context, archive queries.InsertEventArchiveParams) error {
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	err = func() error {
+		if _, err := tx.ExecContext(ctx, archiveEnvStateStatement, archive.OrganizationID, archive.ArchivedBeforeMs, archive.FirstSeq, archive.LastSeq); err != nil {
+			return fmt.Errorf("update archived environment state: %w", err)
+		}
+		q := queries.New(newInstrumentedDBTX(tx, c.tracker))
+		deleted, err := q.DeleteArchivedEvents(ctx, queries.DeleteArchivedEventsParams{
+			OrganizationID: archive.OrganizationID,
+			BeforeMs:       archive.ArchivedBeforeMs,
+			FirstSeq:       archive.FirstSeq,
+			LastSeq:        archive.LastSeq,
+		})
+		if err != nil {
+			return err
+		}
+		if deleted != archive.EventCount {
+			return fmt.Errorf("archived %d events but deleted %d", archive.EventCount, deleted)
+		}
+		_, err = q.InsertEventArchive(ctx, archive)
+		return err
+	}()
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return rollbackErr
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListEventArchives returns an organization's newest archives first.
+func (c *Database) ListEventArchives(ctx context.Context, organizationID, limit int64) ([]queries.EventArchive, error) {
+	return c.Queries.ListEventArchives(ctx, queries.ListEventArchivesParams{OrganizationID: organizationID, Limit: limit})
+}
+
+// RestoreEventArchive inserts the events of an archive file back into the
+// store with their original sequence numbers and returns how many were
+// restored. Events already present are skipped. Projections are not touched:
+// they still include the archived events, and rebuilds ignore events older
+// than the archive cutoff.
+func (c *Database) RestoreEventArchive(ctx context.Context, path string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = file.Close() }()
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = zr.Close() }()
+
+	decoder := json.NewDecoder(bufio.NewReader(zr))
+	var restored int64
+	err = c.WithTx(ctx, func(q *queries.Queries) error {
+		for {
+			var event ArchivedEvent
+			if err := decoder.Decode(&event); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return fmt.Errorf("decode archived event: %w", err)
+			}
+			rows, err := q.RestoreArchivedEvent(ctx, event.restoreParams())
+			if err != nil {
+				return err
+			}
+			restored += rows
+		}
+	})
+	return restored, err
+}
+
+func archivedEventFromRow(row queries.EventStore) (ArchivedEvent, error) {
+	raw, err := eventPayload(row.RawEventJson, row.RawEventCompressed)
+	if err != nil {
+		return ArchivedEvent{}, fmt.Errorf("event %d: %w", row.Seq, err)
+	}
+	event := ArchivedEvent{
+		Seq:            row.Seq,
+		OrganizationID: row.OrganizationID,
+		EventID:        row.EventID,
+		EventType:      row.EventType,
+		EventSource:    row.EventSource,
+		EventTimestamp: row.EventTimestamp,
+		SubjectID:      row.SubjectID,
+		SubjectType:    row.SubjectType,
+		RawEvent:       raw,
+		IngestedAt:     row.IngestedAt.UTC(),
+		EventTsMs:      row.EventTsMs,
+	}
+	if row.SubjectSource.Valid {
+		event.SubjectSource = &row.SubjectSource.String
+	}
+	if row.ChainID.Valid {
+		event.ChainID = &row.ChainID.String
+	}
+	return event, nil
+}
+
+func (e ArchivedEvent) restoreParams() queries.RestoreArchivedEventParams {
+	params := queries.RestoreArchivedEventParams{
+		Seq:            e.Seq,
+		OrganizationID: e.OrganizationID,
+		EventID:        e.EventID,
+		EventType:      e.EventType,
+		EventSource:    e.EventSource,
+		EventTimestamp: e.EventTimestamp,
+		SubjectID:      e.SubjectID,
+		SubjectType:    e.SubjectType,
+		RawEventJson:   e.RawEvent,
+		IngestedAt:     e.IngestedAt,
+		EventTsMs:      e.EventTsMs,
+	}
+	if e.SubjectSource != nil {
+		params.SubjectSource = sql.NullString{String: *e.SubjectSource, Valid: true}
+	}
+	if e.ChainID != nil {
+		params.ChainID = sql.NullString{String: *e.ChainID, Valid: true}
+	}
+	return params
+}
+
+// archiveEnvStateStatement moves the latest archived service event per
+// environment into event_archive_env_state, bound as organization ?1, cutoff
+// ?2 and the archived seq range ?3..?4. It runs before the events are deleted.
+const archiveEnvStateStatement = `INSERT INTO event_archive_env_state (
+		organization_id, service_name, environment,
+		latest_event_seq, latest_event_type, latest_event_ts_ms,
+		latest_status, latest_artifact_id
+	)
+	WITH ranked AS (
+		SELECT
+			es.organization_id,
+			es.service_name,
+			es.environment,
+			es.seq,
+			es.event_type,
+			es.event_ts_ms,
+			es.artifact_id,
+			es.outcome AS status,
+			row_number() OVER (
+				PARTITION BY
+					es.service_name,
+					es.environment
+				ORDER BY es.event_ts_ms DESC, es.seq DESC
+			) AS rn
+		FROM event_store es
+		WHERE es.organization_id = ?1 AND es.event_ts_ms < ?2 AND es.seq >= ?3 AND es.seq <= ?4
+			AND es.subject_type = 'service'
+	)
+	SELECT organization_id, service_name, environment, seq, event_type, event_ts_ms, status, artifact_id
+	FROM ranked
+	WHERE rn = 1
+	ON CONFLICT(organization_id, service_name, environment) DO UPDATE SET
+		latest_event_seq = excluded.latest_event_seq,
+		latest_event_type = excluded.latest_event_type,
+		latest_event_ts_ms = excluded.latest_event_ts_ms,
+		latest_status = excluded.latest_status,
+		latest_artifact_id = excluded.latest_artifact_id
+	WHERE excluded.latest_event_ts_ms > event_archive_env_state.latest_event_ts_ms
+		OR (excluded.latest_event_ts_ms = event_archive_env_state.latest_event_ts_ms AND excluded.latest_event_seq > event_archive_env_state.latest_event_seq)`
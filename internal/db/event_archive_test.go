@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestEventArchiveCutoff_StartsUTCWeek(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 12, 15, 30, 0, 0, time.UTC) // Thursday
+	got := EventArchiveCutoff(now, 30)
+	want := time.Date(2026, 2, 8, 0, 0, 0, 0, time.UTC) // Sunday before 2026-02-10
+	if !got.Equal(want) {
+		t.Fatalf("unexpected cutoff: got=%s want=%s", got, want)
+	}
+}
+
+func TestArchiveOrganizationEvents_KeepsProjectionsAcrossRebuildAndRestore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+	old := func(offset time.Duration) string {
+		return time.Now().UTC().Truncate(time.Hour).Add(-21 * 24 * time.Hour).Add(offset).Format(time.RFC3339)
+	}
+	appendSubjectEvent(t, ctx, database, org.ID, "run-start", "dev.cdevents.pipeline.run.started.0.3.0", old(0), "pipeline", "pipeline/payments/42", "staging", "pkg:generic/payments@abc")
+	appendSubjectEvent(t, ctx, database, org.ID, "run-done", "dev.cdevents.pipeline.run.succeeded.0.3.0", old(90*time.Second), "pipeline", "pipeline/payments/42", "staging", "pkg:generic/payments@abc")
+	appendSubjectEvent(t, ctx, database, org.ID, "change-1", "dev.cdevents.change.merged.0.3.0", old(2*time.Minute), "change", "change/abc", "", "pkg:generic/payments@abc")
+	appendEvent(t, ctx, database, org.ID, "deploy-1", "dev.cdevents.service.deployed.0.3.0", old(3*time.Minute), "service/payments", "staging", "pkg:generic/payments@abc")
+	appendEvent(t, ctx, database, org.ID, "deploy-2", "dev.cdevents.service.deployed.0.3.0", old(4*time.Minute), "service/payments", "production", "pkg:generic/payments@old")
+	// A redeploy of the archived staging artifact only counts as one when the
+	// rebuild starts from the archived environment state.
+	appendEvent(t, ctx, database, org.ID, "deploy-3", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(0), "service/payments", "staging", "pkg:generic/payments@abc")
+
+	before := dumpProjectionRows(t, ctx, database, org.ID)
+
+	dir := t.TempDir()
+	result, err := database.ArchiveOrganizationEvents(ctx, org.ID, time.Now().Add(-14*24*time.Hour), dir)
+	if err != nil {
+		t.Fatalf("archive events: %v", err)
+	}
+	if result.EventCount != 5 || result.ArchivedBefore.Weekday() != time.Sunday {
+		t.Fatalf("unexpected archive result: %+v", result)
+	}
+	if _, err := os.Stat(result.Path); err != nil {
+		t.Fatalf("archive file missing: %v", err)
+	}
+	if remaining := countOrganizationEvents(t, ctx, database, org.ID); remaining != 1 {
+		t.Fatalf("expected archived events to be deleted, %d left", remaining)
+	}
+	if again, err := database.ArchiveOrganizationEvents(ctx, org.ID, time.Now().Add(-14*24*time.Hour), dir); err != nil || again.EventCount != 0 {
+		t.Fatalf("expected nothing left to archive: result=%+v err=%v", again, err)
+	}
+
+	assertProjectionsUnchanged := func(stage string) {
+		t.Helper()
+		if _, err := database.RebuildServiceProjections(ctx, org.ID); err != nil {
+			t.Fatalf("%s: rebuild projections: %v", stage, err)
+		}
+		after := dumpProjectionRows(t, ctx, database, org.ID)
+		for _, table := range projectionTables {
+			if !slices.Equal(after[table], before[table]) {
+				t.Fatalf("%s: %s changed:\nbefore=%v\n after=%v", stage, table, before[table], after[table])
+			}
+		}
+	}
+	assertProjectionsUnchanged("after archive")
+
+	restored, err := database.RestoreEventArchive(ctx, result.Path)
+	if err != nil {
+		t.Fatalf("restore archive: %v", err)
+	}
+	if restored != 5 || countOrganizationEvents(t, ctx, database, org.ID) != 6 {
+		t.Fatalf("unexpected restore: restored=%d", restored)
+	}
+	if again, err := database.RestoreEventArchive(ctx, result.Path); err != nil || again != 0 {
+		t.Fatalf("expected restore to skip present events: restored=%d err=%v", again, err)
+	}
+	assertProjectionsUnchanged("after restore")
+}
+
+func countOrganizationEvents(t *testing.T, ctx context.Context, database *Database, organizationID int64) int64 {
+	t.Helper()
+	var count int64
+	if err := database.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM event_store WHERE organization_id = ?", organizationID).Scan(&count); err != nil {
+		t.Fatalf("count events: %v", err)
+	}
+	return count
+}
+
+// dumpProjectionRows renders an organization's projection rows without the
+// bookkeeping timestamps a rebuild resets.
+func dumpProjectionRows(t *testing.T, ctx context.Context, database *Database, organizationID int64) map[string][]string {
+	t.Helper()
+	out := map[string][]string{}
+	for _, table := range projectionTables {
+		rows, err := database.db.QueryContext(ctx, "SELECT * FROM "+table+" WHERE organization_id = ?", organizationID)
+		if err != nil {
+			t.Fatalf("select %s: %v", table, err)
+		}
+		columns, err := rows.Columns()
+		if err != nil {
+			t.Fatalf("columns %s: %v", table, err)
+		}
+		for rows.Next() {
+			values := make([]any, len(columns))
+			pointers := make([]any, len(columns))
+			for i := range values {
+				pointers[i] = &values[i]
+			}
+			if err := rows.Scan(pointers...); err != nil {
+				t.Fatalf("scan %s: %v", table, err)
+			}
+			line := ""
+			for i, column := range columns {
+				if column == "updated_at" || column == "created_at" {
+					continue
+				}
+				line += fmt.Sprintf("%s=%v ", column, values[i])
+			}
+			out[table] = append(out[table], line)
+		}
+		if err := rows.Close(); err != nil {
+			t.Fatalf("close %s: %v", table, err)
+		}
+		slices.Sort(out[table])
+	}
+	return out
+}
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fr0stylo/ddash/internal/db/queries"
+)
+
+func TestListEventStoreEvents_FiltersAndPagesBySeq(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+	appendSubjectEvent(t, ctx, database, org.ID, "run-1", "dev.cdevents.pipeline.run.started.0.3.0", recentTimestamp(0), "pipeline", "pipeline/payments/1", "staging", "pkg:generic/payments@abc")
+	appendEvent(t, ctx, database, org.ID, "deploy-1", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(time.Minute), "service/payments", "staging", "pkg:generic/payments@abc")
+	appendEvent(t, ctx, database, org.ID, "deploy-2", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(2*time.Minute), "service/payments", "production", "pkg:generic/payments@abc")
+
+	all, err := database.ListEventStoreEvents(ctx, queries.ListEventStoreEventsParams{OrganizationID: org.ID, Limit: 10})
+	if err != nil {
+		t.Fatalf("list events: %v", err)
+	}
+	if len(all) != 3 || all[0].Seq < all[2].Seq {
+		t.Fatalf("expected every event newest first: %+v", all)
+	}
+
+	deploys, err := database.ListEventStoreEvents(ctx, queries.ListEventStoreEventsParams{
+		OrganizationID: org.ID,
+		EventType:      "dev.cdevents.service.deployed.0.3.0",
+		SubjectType:    "service",
+		BeforeSeq:      all[0].Seq,
+		Limit:          10,
+	})
+	if err != nil {
+		t.Fatalf("list filtered events: %v", err)
+	}
+	if len(deploys) != 1 || deploys[0].Seq != all[1].Seq {
+		t.Fatalf("expected the older deploy only: %+v", deploys)
+	}
+
+	window, err := database.ListEventStoreEvents(ctx, queries.ListEventStoreEventsParams{
+		OrganizationID: org.ID,
+		FromMs:         all[1].EventTsMs,
+		ToMs:           all[0].EventTsMs,
+		Limit:          10,
+	})
+	if err != nil {
+		t.Fatalf("list events in window: %v", err)
+	}
+	if len(window) != 1 || window[0].Seq != all[1].Seq {
+		t.Fatalf("expected the time window to be half-open: %+v", window)
+	}
+
+	contributions, err := database.ListEventContributions(ctx, queries.ListEventContributionsParams{OrganizationID: org.ID, Seq: all[0].Seq})
+	if err != nil {
+		t.Fatalf("list contributions: %v", err)
+	}
+	if len(contributions) != 1 || contributions[0].ServiceName != "payments" || contributions[0].Environment != "production" {
+		t.Fatalf("unexpected contributions: %+v", contributions)
+	}
+}
+
+func TestListChainEvents_OrdersAcrossSubjects(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+	appendChained := func(eventID, eventType string, offset time.Duration, subjectType, subjectID, chainID, content string) {
+		t.Helper()
+		timestamp := recentTimestamp(offset)
+		raw := fmt.Sprintf(`{"context":{"id":%q,"source":"tests/source","type":%q,"timestamp":%q,"chainId":%q,"specversion":"0.5.0"},"subject":{"id":%q,"source":"tests/source","content":%s}}`,
+			eventID, eventType, timestamp, chainID, subjectID, content)
+		if err := database.AppendEventStore(ctx, queries.AppendEventStoreParams{
+			OrganizationID: org.ID,
+			EventID:        eventID,
+			EventType:      eventType,
+			EventSource:    "tests/source",
+			EventTimestamp: timestamp,
+			EventTsMs:      mustUnixMillis(t, timestamp),
+			SubjectID:      subjectID,
+			SubjectSource:  sql.NullString{String: "tests/source", Valid: true},
+			SubjectType:    subjectType,
+			ChainID:        sql.NullString{String: chainID, Valid: chainID != ""},
+			RawEventJson:   raw,
+		}); err != nil {
+			t.Fatalf("append event %s: %v", eventID, err)
+		}
+	}
+	// Appended out of order so the query has to sort by event time.
+	appendChained("deploy-1", "dev.cdevents.service.deployed.0.3.0", 3*time.Minute, "service", "service/payments", "chain-1", `{"environment":{"id":"staging"},"artifactId":"pkg:generic/payments@abc"}`)
+	appendChained("change-1", "dev.cdevents.change.merged.0.3.0", 0, "change", "change/42", "chain-1", `{"url":"https://git.example.com/pr/42"}`)
+	appendChained("run-1", "dev.cdevents.pipelinerun.finished.0.3.0", 2*time.Minute, "pipelineRun", "pipeline/7", "chain-1", `{"pipeline":{"url":"https://ci.example.com/7"}}`)
+	appendChained("other-1", "dev.cdevents.change.merged.0.3.0", time.Minute, "change", "change/43", "chain-2", `{}`)
+
+	events, err := database.ListChainEvents(ctx, queries.ListChainEventsParams{
+		OrganizationID: org.ID,
+		ChainID:        sql.NullString{String: "chain-1", Valid: true},
+		Limit:          10,
+	})
+	if err != nil {
+		t.Fatalf("list chain events: %v", err)
+	}
+	if len(events) != 3 || events[0].EventID != "change-1" || events[1].EventID != "run-1" || events[2].EventID != "deploy-1" {
+		t.Fatalf("expected chain-1 events in time order: %+v", events)
+	}
+	if events[0].Url != "https://git.example.com/pr/42" || events[1].Url != "https://ci.example.com/7" || events[2].Url != "" {
+		t.Fatalf("unexpected event links: %v %v %v", events[0].Url, events[1].Url, events[2].Url)
+	}
+}
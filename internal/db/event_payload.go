@@ -0,0 +1,176 @@
+package db
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	sqldriver "database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"modernc.org/sqlite"
+
+	"github.com/fr0stylo/ddash/internal/db/queries"
+)
+
+const defaultEventPayloadCompressionBatch = 500
+
+// Event bodies are stored in event_store.raw_event_compressed as a codec byte
+// followed by the DEFLATE stream. The codec byte pins the dictionary the
+// stream was written with, so the dictionary below must never change once
+// rows use it; a new dictionary needs a new codec byte.
+const eventPayloadCodecDeflateV1 byte = 1
+
+// eventPayloadDictionaryV1 primes DEFLATE with the bodies ddash stores: the
+// envelope the CDEvents SDK writes for service, environment, change and
+// incident events, and the pipeline run bodies the GitHub and GitLab bridges
+// publish, whose keys are sorted. Later strings are cheaper to reference, so
+// the most common fragments come last.
+var eventPayloadDictionaryV1 = []byte(`` +
+	`"durationSeconds":` +
+	`{"context":{"chainId":"","id":"","source":"gitlab/webhook","specversion":"0.5.0","timestamp":"",` +
+	`"type":"dev.cdevents.pipeline.run.started.0.3.0"},"subject":{"content":{"actor":{"name":""},` +
+	`"artifactId":"pkg:generic/","environment":{"id":"production"},"pipeline":{"runId":"","url":"https://gitlab.com/"}},` +
+	`"id":"pipeline/","source":"github/app","type":"pipeline"}}` +
+	`"type":"dev.cdevents.pipeline.run.failed.0.3.0"` +
+	`"type":"dev.cdevents.pipeline.run.succeeded.0.3.0"},"subject":{"content":{"actor":{"name":""},` +
+	`"artifactId":"pkg:generic/","environment":{"id":"production"},"pipeline":{"runId":"","url":"https://github.com/"}},` +
+	`"type":"dev.cdevents.incident.reported.0.1.0"` +
+	`"type":"dev.cdevents.incident.resolved.0.1.0"` +
+	`"type":"dev.cdevents.incident.detected.0.1.0",` +
+	`"content":{"artifactId":"pkg:generic/","environment":{"id":"production"},"service":{"id":"service/"},"ticketURI":""}}}` +
+	`"type":"dev.cdevents.environment.deleted.0.3.0"` +
+	`"type":"dev.cdevents.environment.modified.0.3.0"` +
+	`"type":"dev.cdevents.environment.created.0.3.0"` +
+	`"type":"dev.cdevents.change.pushed.0.3.0"` +
+	`"type":"dev.cdevents.change.merged.0.3.0","content":{}}}` +
+	`"type":"dev.cdevents.service.removed.0.3.0"` +
+	`"type":"dev.cdevents.service.published.0.3.0"` +
+	`"type":"dev.cdevents.service.rolledback.0.3.0"` +
+	`"type":"dev.cdevents.service.upgraded.0.3.0"` +
+	`"content":{"artifactId":"pkg:oci/","environment":{"id":"staging"}}}}` +
+	`{"context":{"id":"","source":"","type":"dev.cdevents.service.deployed.0.3.0",` +
+	`"timestamp":"2026-01-01T00:00:00Z","specversion":"0.5.0","chainId":""},` +
+	`"subject":{"id":"service/","source":"","content":{"artifactId":"pkg:generic/","environment":{"id":"production"}}}}`)
+
+// ErrUnknownEventPayloadCodec is returned for a compressed event body written
+// by a codec this build does not know.
+var ErrUnknownEventPayloadCodec = errors.New("unknown event payload codec")
+
+func init() {
+	// ddash_event_compress(raw_event_json) returns the compressed body, or
+	// NULL for an empty one.
+	sqlite.MustRegisterDeterministicScalarFunction("ddash_event_compress", 1, func(_ *sqlite.FunctionContext, args []sqldriver.Value) (sqldriver.Value, error) {
+		raw, err := sqliteText(args[0])
+		if err != nil || raw == "" {
+			return nil, err
+		}
+		return compressEventPayload(raw)
+	})
+	// ddash_event_json(raw_event_json, raw_event_compressed) returns the event
+	// body whether or not it is stored compressed.
+	sqlite.MustRegisterDeterministicScalarFunction("ddash_event_json", 2, func(_ *sqlite.FunctionContext, args []sqldriver.Value) (sqldriver.Value, error) {
+		raw, err := sqliteText(args[0])
+		if err != nil {
+			return nil, err
+		}
+		compressed, ok := args[1].([]byte)
+		if args[1] != nil && !ok {
+			return nil, fmt.Errorf("ddash_event_json: unexpected %T for compressed body", args[1])
+		}
+		return eventPayload(raw, compressed)
+	})
+}
+
+// EventPayloadCompressionProgress reports how far CompressEventPayloads got.
+type EventPayloadCompressionProgress struct {
+	Events  int64
+	LastSeq int64
+}
+
+// CompressEventPayloads compresses event bodies that are still stored as
+// plain text, batchSize events per statement. Each batch commits on its own
+// and the run pauses between batches, so ingestion only waits for the write
+// lock for one batch at a time. progress, when set, is called after every
+// batch. The run can be stopped and started again; compressed events are
+// skipped.
+func (c *Database) CompressEventPayloads(ctx context.Context, batchSize int64, pause time.Duration, progress func(EventPayloadCompressionProgress)) (EventPayloadCompressionProgress, error) {
+	if batchSize <= 0 {
+		batchSize = defaultEventPayloadCompressionBatch
+	}
+	var done EventPayloadCompressionProgress
+	for {
+		seqs, err := c.Queries.CompressEventStorePayloads(ctx, queries.CompressEventStorePayloadsParams{
+			AfterSeq: done.LastSeq,
+			Limit:    batchSize,
+		})
+		if err != nil {
+			return done, err
+		}
+		for _, seq := range seqs {
+			done.LastSeq = max(done.LastSeq, seq)
+		}
+		done.Events += int64(len(seqs))
+		if progress != nil && len(seqs) > 0 {
+			progress(done)
+		}
+		if int64(len(seqs)) < batchSize {
+			return done, nil
+		}
+		select {
+		case <-ctx.Done():
+			return done, ctx.Err()
+		case <-time.After(pause):
+		}
+	}
+}
+
+// compressEventPayload encodes an event body for raw_event_compressed.
+func compressEventPayload(raw string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(eventPayloadCodecDeflateV1)
+	writer, err := flate.NewWriterDict(&buf, flate.BestCompression, eventPayloadDictionaryV1)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(writer, raw); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// eventPayload returns the event body stored as raw_event_json or, when set,
+// raw_event_compressed.
+func eventPayload(raw string, compressed []byte) (string, error) {
+	if compressed == nil {
+		return raw, nil
+	}
+	if len(compressed) == 0 || compressed[0] != eventPayloadCodecDeflateV1 {
+		return "", ErrUnknownEventPayloadCodec
+	}
+	reader := flate.NewReaderDict(bytes.NewReader(compressed[1:]), eventPayloadDictionaryV1)
+	defer func() { _ = reader.Close() }()
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("decompress event payload: %w", err)
+	}
+	return string(body), nil
+}
+
+func sqliteText(value sqldriver.Value) (string, error) {
+	switch typed := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return typed, nil
+	case []byte:
+		return string(typed), nil
+	default:
+		return "", fmt.Errorf("expected text, got %T", value)
+	}
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fr0stylo/ddash/internal/db/queries"
+)
+
+func TestAppendEventStore_CompressesPayload(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+	appendEvent(t, ctx, database, org.ID, "c1", "dev.cdevents.service.deployed.0.3.0", "2026-02-19T10:00:00Z", "service/orders", "staging", "pkg:generic/orders@a1")
+
+	var plain string
+	var compressed []byte
+	if err := database.db.QueryRowContext(ctx, `SELECT raw_event_json, raw_event_compressed FROM event_store WHERE event_id = 'c1'`).Scan(&plain, &compressed); err != nil {
+		t.Fatalf("read stored payload: %v", err)
+	}
+	if plain != "" || len(compressed) == 0 {
+		t.Fatalf("expected only a compressed payload, got plain=%q compressed=%d bytes", plain, len(compressed))
+	}
+
+	rows, err := database.ListEventStoreEvents(ctx, queries.ListEventStoreEventsParams{OrganizationID: org.ID, Limit: 1})
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("list events: rows=%v err=%v", rows, err)
+	}
+	event, err := database.GetEventStoreEvent(ctx, queries.GetEventStoreEventParams{OrganizationID: org.ID, Seq: rows[0].Seq})
+	if err != nil {
+		t.Fatalf("get event: %v", err)
+	}
+	if !strings.Contains(event.RawEventJson, `"artifactId":"pkg:generic/orders@a1"`) {
+		t.Fatalf("unexpected decompressed payload: %s", event.RawEventJson)
+	}
+}
+
+func TestCompressEventPayloads_RewritesPlainEventsInBatches(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+	raw := `{"context":{"id":"p","source":"tests/source","type":"dev.cdevents.service.deployed.0.3.0","timestamp":"2026-02-19T10:00:00Z","specversion":"0.5.0"},"subject":{"id":"service/orders","source":"tests/source","content":{"environment":{"id":"staging"},"artifactId":"pkg:generic/orders@v1"}}}`
+	for _, eventID := range []string{"p1", "p2", "p3"} {
+		if _, err := database.db.ExecContext(ctx, `INSERT INTO event_store (
+			organization_id, event_id, event_type, event_source, event_timestamp, event_ts_ms,
+			subject_id, subject_type, raw_event_json
+		) VALUES (?, ?, 'dev.cdevents.service.deployed.0.3.0', 'tests/source', '2026-02-19T10:00:00Z', 1771495200000,
+			'service/orders', 'service', ?)`, org.ID, eventID, raw); err != nil {
+			t.Fatalf("insert plain event: %v", err)
+		}
+	}
+
+	before, err := database.GetEventStorePayloadSizes(ctx)
+	if err != nil {
+		t.Fatalf("measure payloads: %v", err)
+	}
+	if before.PlainEvents != 3 || before.PlainBytes != int64(3*len(raw)) {
+		t.Fatalf("unexpected plain payload sizes: %+v", before)
+	}
+
+	batches := 0
+	done, err := database.CompressEventPayloads(ctx, 2, time.Millisecond, func(EventPayloadCompressionProgress) { batches++ })
+	if err != nil {
+		t.Fatalf("compress payloads: %v", err)
+	}
+	if done.Events != 3 || batches != 2 {
+		t.Fatalf("unexpected compression run: %+v in %d batches", done, batches)
+	}
+
+	after, err := database.GetEventStorePayloadSizes(ctx)
+	if err != nil {
+		t.Fatalf("measure payloads: %v", err)
+	}
+	if after.PlainEvents != 0 || after.CompressedEvents != 3 || after.OriginalBytes != before.PlainBytes {
+		t.Fatalf("unexpected compressed payload sizes: %+v", after)
+	}
+	if after.CompressedBytes >= after.OriginalBytes/2 {
+		t.Fatalf("expected payloads to shrink by more than half: %+v", after)
+	}
+
+	var environment string
+	if err := database.db.QueryRowContext(ctx, `SELECT json_extract(ddash_event_json(raw_event_json, raw_event_compressed), '$.subject.content.environment.id')
+		FROM event_store WHERE event_id = 'p2'`).Scan(&environment); err != nil {
+		t.Fatalf("read compressed payload field: %v", err)
+	}
+	if environment != "staging" {
+		t.Fatalf("unexpected environment from compressed payload: %q", environment)
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"database/sql"
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/pressly/goose/v3"
+
 	"github.com/fr0stylo/ddash/internal/db/queries"
 )
 
@@ -109,6 +112,23 @@ func appendEvent(
 	artifactID string,
 ) {
 	t.Helper()
+	appendSubjectEvent(t, ctx, database, organizationID, eventID, eventType, timestamp, "service", subjectID, environmentID, artifactID)
+}
+
+func appendSubjectEvent(
+	t *testing.T,
+	ctx context.Context,
+	database *Database,
+	organizationID int64,
+	eventID string,
+	eventType string,
+	timestamp string,
+	subjectType string,
+	subjectID string,
+	environmentID string,
+	artifactID string,
+) {
+	t.Helper()
 
 	raw := fmt.Sprintf(`{"context":{"id":%q,"source":"tests/source","type":%q,"timestamp":%q,"specversion":"0.5.0"},"subject":{"id":%q,"source":"tests/source","content":{"environment":{"id":%q},"artifactId":%q}}}`,
 		eventID,
@@ -128,7 +148,7 @@ func appendEvent(
 		EventTsMs:      mustUnixMillis(t, timestamp),
 		SubjectID:      subjectID,
 		SubjectSource:  sql.NullString{String: "tests/source", Valid: true},
-		SubjectType:    "service",
+		SubjectType:    subjectType,
 		ChainID:        sql.NullString{},
 		RawEventJson:   raw,
 	})
@@ -145,3 +165,190 @@ func mustUnixMillis(t *testing.T, value string) int64 {
 	}
 	return parsed.UTC().UnixMilli()
 }
+
+// recentTimestamp returns an RFC3339 timestamp offset from the current hour so
+// that events stay inside rolling reporting windows.
+func recentTimestamp(offset time.Duration) string {
+	return time.Now().UTC().Truncate(time.Hour).Add(-24 * time.Hour).Add(offset).Format(time.RFC3339)
+}
+
+func TestAppendEventStore_StoresDerivedColumns(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+
+	appendEvent(t, ctx, database, org.ID, "d1", "dev.cdevents.service.rolledback.0.3.0", "2026-02-19T10:00:00Z", "service/orders", "staging", "pkg:generic/orders@a1")
+	appendSubjectEvent(t, ctx, database, org.ID, "d2", "dev.cdevents.change.merged.0.3.0", "2026-02-19T10:01:00Z", "change", "change/42", "", "")
+
+	got := derivedColumns(t, ctx, database, org.ID)
+	want := []string{
+		"d1|orders|staging|pkg:generic/orders@a1|warning",
+		"d2|42|unknown||",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected derived columns: got=%v want=%v", got, want)
+	}
+}
+
+func TestDerivedColumnsMigration_BackfillsExistingEvents(t *testing.T) {
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+
+	if err := goose.DownTo(database.db, "migrations", 20260306090000); err != nil {
+		t.Fatalf("migrate down: %v", err)
+	}
+	if _, err := database.db.ExecContext(ctx, `INSERT INTO event_store (
+		organization_id, event_id, event_type, event_source, event_timestamp, event_ts_ms,
+		subject_id, subject_type, raw_event_json
+	) VALUES (?, 'b1', 'dev.cdevents.service.deployed.0.3.0', 'tests/source', '2026-02-19T10:00:00Z', 1771495200000,
+		'service/billing', 'service', '{"subject":{"content":{"environment":{"id":"production"},"artifactId":"pkg:generic/billing@v3"}}}')`, org.ID); err != nil {
+		t.Fatalf("insert legacy event: %v", err)
+	}
+	if err := goose.Up(database.db, "migrations"); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+
+	got := derivedColumns(t, ctx, database, org.ID)
+	if len(got) != 1 || got[0] != "b1|billing|production|pkg:generic/billing@v3|synced" {
+		t.Fatalf("unexpected backfilled columns: %v", got)
+	}
+}
+
+func TestAppendEventStore_StoresContentColumns(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+
+	for _, event := range []struct {
+		id, subjectType, subjectID, content string
+	}{
+		{"p1", "pipeline", "pipeline/orders/7", `{"environment":{"id":"staging"},"service":"orders","pipeline":{"runId":"7","url":"https://ci/runs/7"},"durationSeconds":42}`},
+		{"i1", "incident", "incident/9", `{"service":{"id":"service/billing"},"url":"https://pager/9"}`},
+	} {
+		if err := database.AppendEventStore(ctx, queries.AppendEventStoreParams{
+			OrganizationID: org.ID,
+			EventID:        event.id,
+			EventType:      "dev.cdevents." + event.subjectType + ".test.0.1.0",
+			EventSource:    "tests/source",
+			EventTimestamp: "2026-02-19T10:00:00Z",
+			EventTsMs:      mustUnixMillis(t, "2026-02-19T10:00:00Z"),
+			SubjectID:      event.subjectID,
+			SubjectType:    event.subjectType,
+			RawEventJson:   `{"subject":{"id":"` + event.subjectID + `","content":` + event.content + `}}`,
+		}); err != nil {
+			t.Fatalf("append event %s: %v", event.id, err)
+		}
+	}
+
+	got := contentColumns(t, ctx, database, org.ID)
+	want := []string{
+		"p1|staging|orders||7|https://ci/runs/7|https://ci/runs/7|42",
+		"i1|||service/billing|||https://pager/9|0",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected content columns: got=%v want=%v", got, want)
+	}
+}
+
+func TestContentColumnsMigration_BackfillsCompressedEvents(t *testing.T) {
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+
+	if err := goose.DownTo(database.db, "migrations", 20260308090000); err != nil {
+		t.Fatalf("migrate down: %v", err)
+	}
+	for _, event := range []struct {
+		id, body   string
+		compressed bool
+	}{
+		{"c1", `{"subject":{"content":{"environment":{"id":"production"},"pipeline":{"runId":"3","url":"https://ci/runs/3"},"durationSeconds":5}}}`, true},
+		{"c2", `{"subject":{"content":{"service":"billing"}}}`, false},
+	} {
+		rawExpr, compressedExpr := "?", "NULL"
+		if event.compressed {
+			rawExpr, compressedExpr = "''", "ddash_event_compress(?)"
+		}
+		if _, err := database.db.ExecContext(ctx, `INSERT INTO event_store (
+			organization_id, event_id, event_type, event_source, event_timestamp, event_ts_ms,
+			subject_id, subject_type, raw_event_json, raw_event_compressed
+		) VALUES (?, ?, 'dev.cdevents.pipeline.run.succeeded.0.3.0', 'tests/source', '2026-02-19T10:00:00Z', 1771495200000,
+			'pipeline/orders', 'pipeline', `+rawExpr+`, `+compressedExpr+`)`, org.ID, event.id, event.body); err != nil {
+			t.Fatalf("insert legacy event %s: %v", event.id, err)
+		}
+	}
+	if err := goose.Up(database.db, "migrations"); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+
+	got := contentColumns(t, ctx, database, org.ID)
+	want := []string{
+		"c1|production|||3|https://ci/runs/3|https://ci/runs/3|5",
+		"c2||billing|||||0",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected backfilled columns: got=%v want=%v", got, want)
+	}
+	var plain string
+	if err := database.db.QueryRowContext(ctx, `SELECT raw_event_json FROM event_store WHERE event_id = 'c1'`).Scan(&plain); err != nil {
+		t.Fatalf("read compressed event: %v", err)
+	}
+	if plain != "" {
+		t.Fatalf("expected compressed event to keep an empty plain body, got %q", plain)
+	}
+}
+
+func contentColumns(t *testing.T, ctx context.Context, database *Database, organizationID int64) []string {
+	t.Helper()
+
+	rows, err := database.db.QueryContext(ctx, `SELECT event_id, environment_id, content_service, content_service_id,
+		pipeline_run_id, run_url, link_url, CAST(duration_seconds AS INTEGER)
+		FROM event_store WHERE organization_id = ? ORDER BY seq`, organizationID)
+	if err != nil {
+		t.Fatalf("query content columns: %v", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []string
+	for rows.Next() {
+		var eventID, environment, service, serviceID, runID, runURL, linkURL string
+		var duration int64
+		if err := rows.Scan(&eventID, &environment, &service, &serviceID, &runID, &runURL, &linkURL, &duration); err != nil {
+			t.Fatalf("scan content columns: %v", err)
+		}
+		out = append(out, strings.Join([]string{eventID, environment, service, serviceID, runID, runURL, linkURL, strconv.FormatInt(duration, 10)}, "|"))
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("iterate content columns: %v", err)
+	}
+	return out
+}
+
+func derivedColumns(t *testing.T, ctx context.Context, database *Database, organizationID int64) []string {
+	t.Helper()
+
+	rows, err := database.db.QueryContext(ctx, `SELECT event_id, service_name, environment, artifact_id, outcome
+		FROM event_store WHERE organization_id = ? ORDER BY seq`, organizationID)
+	if err != nil {
+		t.Fatalf("query derived columns: %v", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []string
+	for rows.Next() {
+		var eventID, service, environment, artifact, outcome string
+		if err := rows.Scan(&eventID, &service, &environment, &artifact, &outcome); err != nil {
+			t.Fatalf("scan derived columns: %v", err)
+		}
+		out = append(out, strings.Join([]string{eventID, service, environment, artifact, outcome}, "|"))
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("iterate derived columns: %v", err)
+	}
+	return out
+}
@@ -0,0 +1,176 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fr0stylo/ddash/internal/db/queries"
+)
+
+func TestAppendEventStore_LinksIncidentsToLatestDeployment(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+	appendIncidentFixture(t, ctx, database, org.ID)
+
+	stats, err := database.GetMTTR(ctx, queries.GetMTTRParams{OrganizationID: org.ID, ServiceName: "payments", SinceMs: 0})
+	if err != nil {
+		t.Fatalf("get mttr: %v", err)
+	}
+	if stats.IncidentCount != 2 || stats.OpenCount != 1 {
+		t.Fatalf("unexpected incident counts: %+v", stats)
+	}
+	if stats.MttrSeconds != 1800 {
+		t.Fatalf("unexpected mttr: got=%v want=1800", stats.MttrSeconds)
+	}
+	if stats.MttdSeconds != 900 {
+		t.Fatalf("unexpected mttd: got=%v want=900", stats.MttdSeconds)
+	}
+
+	links, err := database.ListIncidentLinks(ctx, queries.ListIncidentLinksParams{OrganizationID: org.ID, ServiceName: "payments", Limit: 10})
+	if err != nil {
+		t.Fatalf("list incident links: %v", err)
+	}
+	if len(links) != 2 {
+		t.Fatalf("unexpected links len: got=%d want=2", len(links))
+	}
+	resolved := links[1]
+	if resolved.IncidentID != "INC-1" || resolved.IncidentType != "detected" || resolved.Environment != "production" {
+		t.Fatalf("unexpected resolved incident link: %+v", resolved)
+	}
+	if !resolved.ResolvedAt.Valid || !resolved.DeploymentEventSeq.Valid {
+		t.Fatalf("expected resolved incident to be linked and resolved: %+v", resolved)
+	}
+
+	var deployedArtifact string
+	if err := database.db.QueryRowContext(ctx, "SELECT artifact_id FROM service_deployment_durations WHERE event_seq = ?", resolved.DeploymentEventSeq.Int64).Scan(&deployedArtifact); err != nil {
+		t.Fatalf("load linked deployment: %v", err)
+	}
+	if deployedArtifact != "pkg:generic/payments@v2" {
+		t.Fatalf("incident linked to wrong deployment: %s", deployedArtifact)
+	}
+
+	orgStats, err := database.GetMTTR(ctx, queries.GetMTTRParams{OrganizationID: org.ID, ServiceName: "", SinceMs: 0})
+	if err != nil {
+		t.Fatalf("get org mttr: %v", err)
+	}
+	if orgStats != stats {
+		t.Fatalf("org stats should match single service: got=%+v want=%+v", orgStats, stats)
+	}
+}
+
+func TestGetMTTR_IgnoresIncidentsOnlySeenResolved(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+	appendIncidentFixture(t, ctx, database, org.ID)
+	appendIncidentEvent(t, ctx, database, org.ID, "inc-3-resolved", "dev.cdevents.incident.resolved.0.1.0", recentTimestamp(50*time.Minute), "INC-3", "payments", "production")
+
+	links, err := database.ListIncidentLinks(ctx, queries.ListIncidentLinksParams{OrganizationID: org.ID, ServiceName: "payments", Limit: 10})
+	if err != nil {
+		t.Fatalf("list incident links: %v", err)
+	}
+	if len(links) != 3 || links[0].IncidentID != "INC-3" || links[0].IncidentType != "resolved" {
+		t.Fatalf("expected a resolved-only link for INC-3: %+v", links)
+	}
+
+	stats, err := database.GetMTTR(ctx, queries.GetMTTRParams{OrganizationID: org.ID, ServiceName: "payments", SinceMs: 0})
+	if err != nil {
+		t.Fatalf("get mttr: %v", err)
+	}
+	if stats.IncidentCount != 2 || stats.OpenCount != 1 {
+		t.Fatalf("unexpected incident counts: %+v", stats)
+	}
+	if stats.MttrSeconds != 1800 || stats.MttdSeconds != 900 || stats.MtteSeconds != 1800 {
+		t.Fatalf("resolved-only link changed the aggregates: %+v", stats)
+	}
+}
+
+func TestRebuildServiceProjections_RebuildsIncidentLinks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+	appendIncidentFixture(t, ctx, database, org.ID)
+
+	params := queries.GetMTTRParams{OrganizationID: org.ID, ServiceName: "payments", SinceMs: 0}
+	before, err := database.GetMTTR(ctx, params)
+	if err != nil {
+		t.Fatalf("get mttr: %v", err)
+	}
+	stats, err := database.RebuildServiceProjections(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("rebuild projections: %v", err)
+	}
+	if stats.IncidentLinkRows != 2 {
+		t.Fatalf("unexpected incident rows: got=%d want=2", stats.IncidentLinkRows)
+	}
+	after, err := database.GetMTTR(ctx, params)
+	if err != nil {
+		t.Fatalf("get mttr after rebuild: %v", err)
+	}
+	if after != before {
+		t.Fatalf("rebuild changed incident stats:\nbefore=%+v\n after=%+v", before, after)
+	}
+}
+
+func appendIncidentFixture(t *testing.T, ctx context.Context, database *Database, organizationID int64) {
+	t.Helper()
+
+	appendEvent(t, ctx, database, organizationID, "deploy-1", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(0), "service/payments", "production", "pkg:generic/payments@v1")
+	appendEvent(t, ctx, database, organizationID, "deploy-2", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(5*time.Minute), "service/payments", "production", "pkg:generic/payments@v2")
+	appendEvent(t, ctx, database, organizationID, "deploy-3", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(8*time.Minute), "service/payments", "staging", "pkg:generic/payments@v3")
+	appendIncidentEvent(t, ctx, database, organizationID, "inc-1-detected", "dev.cdevents.incident.detected.0.1.0", recentTimestamp(15*time.Minute), "INC-1", "payments", "production")
+	appendIncidentEvent(t, ctx, database, organizationID, "inc-2-reported", "dev.cdevents.incident.reported.0.1.0", recentTimestamp(25*time.Minute), "INC-2", "payments", "production")
+	appendIncidentEvent(t, ctx, database, organizationID, "inc-1-resolved", "dev.cdevents.incident.resolved.0.1.0", recentTimestamp(45*time.Minute), "INC-1", "payments", "production")
+}
+
+func appendIncidentEvent(
+	t *testing.T,
+	ctx context.Context,
+	database *Database,
+	organizationID int64,
+	eventID string,
+	eventType string,
+	timestamp string,
+	incidentID string,
+	service string,
+	environmentID string,
+) {
+	t.Helper()
+
+	subjectID := "incident/" + incidentID
+	raw := fmt.Sprintf(`{"context":{"id":%q,"source":"tests/monitoring","type":%q,"timestamp":%q,"specversion":"0.5.0"},"subject":{"id":%q,"source":"tests/monitoring","type":"incident","content":{"environment":{"id":%q},"service":{"id":%q}}}}`,
+		eventID,
+		eventType,
+		timestamp,
+		subjectID,
+		environmentID,
+		"service/"+service,
+	)
+
+	err := database.AppendEventStore(ctx, queries.AppendEventStoreParams{
+		OrganizationID: organizationID,
+		EventID:        eventID,
+		EventType:      eventType,
+		EventSource:    "tests/monitoring",
+		EventTimestamp: timestamp,
+		EventTsMs:      mustUnixMillis(t, timestamp),
+		SubjectID:      subjectID,
+		SubjectSource:  sql.NullString{String: "tests/monitoring", Valid: true},
+		SubjectType:    "incident",
+		ChainID:        sql.NullString{},
+		RawEventJson:   raw,
+	})
+	if err != nil {
+		t.Fatalf("append incident event %s: %v", eventID, err)
+	}
+}
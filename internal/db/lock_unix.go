@@ -0,0 +1,34 @@
+//go:build unix
+
+package db
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// lockDatabase takes an advisory lock on the lock file next to the database
+// at path (without the .sqlite suffix). Every Database holds a shared lock
+// for as long as it is open; RestoreBackup takes the exclusive lock, so it
+// fails while any process has the database open and nothing can open it
+// while the files are being swapped.
+func lockDatabase(path string, exclusive bool) (*os.File, error) {
+	file, err := os.OpenFile(path+".sqlite.lock", os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open database lock: %w", err)
+	}
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	if err := syscall.Flock(int(file.Fd()), how|syscall.LOCK_NB); err != nil {
+		_ = file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, ErrDatabaseInUse
+		}
+		return nil, fmt.Errorf("lock database: %w", err)
+	}
+	return file, nil
+}
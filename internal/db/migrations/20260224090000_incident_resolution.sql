@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE service_incident_links ADD COLUMN environment TEXT NOT NULL DEFAULT '';
+ALTER TABLE service_incident_links ADD COLUMN deployment_ts_ms INTEGER;
+ALTER TABLE service_incident_links ADD COLUMN resolved_at INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_incident_links_org_linked
+ON service_incident_links(organization_id, linked_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_incident_links_org_linked;
+
+ALTER TABLE service_incident_links DROP COLUMN resolved_at;
+ALTER TABLE service_incident_links DROP COLUMN deployment_ts_ms;
+ALTER TABLE service_incident_links DROP COLUMN environment;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE organization_event_policies
+(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    subject         TEXT NOT NULL,
+    mode            TEXT NOT NULL,
+    created_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (organization_id, subject)
+);
+
+CREATE INDEX idx_org_event_policies_org
+    ON organization_event_policies (organization_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_org_event_policies_org;
+DROP TABLE organization_event_policies;
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE ingest_dead_letters
+(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    reason          TEXT NOT NULL,
+    detail          TEXT NOT NULL DEFAULT '',
+    headers_json    TEXT NOT NULL DEFAULT '{}',
+    body            BLOB NOT NULL,
+    body_size       INTEGER NOT NULL,
+    truncated       INTEGER NOT NULL DEFAULT 0,
+    received_at     INTEGER NOT NULL,
+    replayed_at     INTEGER,
+    replay_result   TEXT NOT NULL DEFAULT '',
+    created_at      DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ingest_dead_letters_org_received
+    ON ingest_dead_letters (organization_id, received_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_ingest_dead_letters_org_received;
+DROP TABLE ingest_dead_letters;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE ingest_seen_signatures
+(
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    signature       TEXT NOT NULL,
+    seen_at         INTEGER NOT NULL,
+    expires_at      INTEGER NOT NULL,
+    PRIMARY KEY (organization_id, signature)
+);
+
+CREATE INDEX idx_ingest_seen_signatures_expires
+    ON ingest_seen_signatures (expires_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_ingest_seen_signatures_expires;
+DROP TABLE ingest_seen_signatures;
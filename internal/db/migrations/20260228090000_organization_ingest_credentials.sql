@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE organization_ingest_credentials
+(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    label           TEXT NOT NULL,
+    auth_token      TEXT NOT NULL UNIQUE,
+    webhook_secret  TEXT NOT NULL,
+    created_at      INTEGER NOT NULL,
+    expires_at      INTEGER,
+    revoked_at      INTEGER,
+    last_used_at    INTEGER
+);
+
+CREATE INDEX idx_org_ingest_credentials_org
+    ON organization_ingest_credentials (organization_id, created_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_org_ingest_credentials_org;
+DROP TABLE organization_ingest_credentials;
@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE ingest_hourly_stats
+(
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    bucket_start    INTEGER NOT NULL,
+    outcome         TEXT NOT NULL,
+    reason          TEXT NOT NULL DEFAULT '',
+    event_count     INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (organization_id, bucket_start, outcome, reason)
+);
+
+CREATE TABLE ingest_source_activity
+(
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    event_source    TEXT NOT NULL,
+    last_event_id   TEXT NOT NULL,
+    last_event_type TEXT NOT NULL,
+    last_seen_at    INTEGER NOT NULL,
+    PRIMARY KEY (organization_id, event_source)
+);
+
+-- +goose Down
+DROP TABLE ingest_source_activity;
+DROP TABLE ingest_hourly_stats;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE projector_checkpoints
+(
+    name       TEXT PRIMARY KEY,
+    last_seq   INTEGER NOT NULL DEFAULT 0,
+    updated_at INTEGER NOT NULL
+);
+
+-- Projections written so far are current, so the built-in projectors start
+-- at the head of the log instead of replaying it.
+INSERT INTO projector_checkpoints (name, last_seq, updated_at)
+SELECT projector.name, COALESCE((SELECT MAX(seq) FROM event_store), 0), CAST(strftime('%s', 'now') AS INTEGER) * 1000
+FROM (SELECT 'service_state' AS name UNION ALL SELECT 'throughput' UNION ALL SELECT 'pipeline_stats') AS projector;
+
+-- +goose Down
+DROP TABLE projector_checkpoints;
@@ -0,0 +1,34 @@
+-- +goose Up
+CREATE TABLE event_archives
+(
+    id                 INTEGER PRIMARY KEY AUTOINCREMENT,
+    organization_id    INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    path               TEXT NOT NULL,
+    first_seq          INTEGER NOT NULL,
+    last_seq           INTEGER NOT NULL,
+    event_count        INTEGER NOT NULL,
+    archived_before_ms INTEGER NOT NULL,
+    created_at         INTEGER NOT NULL
+);
+
+CREATE INDEX idx_event_archives_org_created
+    ON event_archives (organization_id, created_at DESC);
+
+-- Environment state as of the newest archive cutoff, so projections that
+-- depend on the state before an event can be rebuilt without the archived log.
+CREATE TABLE event_archive_env_state
+(
+    organization_id    INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    service_name       TEXT NOT NULL,
+    environment        TEXT NOT NULL,
+    latest_event_seq   INTEGER NOT NULL,
+    latest_event_type  TEXT NOT NULL,
+    latest_event_ts_ms INTEGER NOT NULL,
+    latest_status      TEXT NOT NULL,
+    latest_artifact_id TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (organization_id, service_name, environment)
+);
+
+-- +goose Down
+DROP TABLE event_archive_env_state;
+DROP TABLE event_archives;
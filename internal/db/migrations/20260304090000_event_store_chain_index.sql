@@ -0,0 +1,7 @@
+-- +goose Up
+CREATE INDEX IF NOT EXISTS idx_event_store_org_chain_time
+ON event_store(organization_id, chain_id, event_ts_ms, seq)
+WHERE chain_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_event_store_org_chain_time;
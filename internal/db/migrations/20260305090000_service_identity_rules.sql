@@ -0,0 +1,29 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS service_identity_rules
+(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    kind            TEXT NOT NULL,
+    pattern         TEXT NOT NULL,
+    service_name    TEXT NOT NULL,
+    created_at      DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (organization_id, kind, pattern),
+    CHECK (kind IN ('alias', 'regex')),
+    CHECK (length(trim(pattern)) > 0),
+    CHECK (length(trim(service_name)) > 0)
+);
+
+-- service_identity_aliases holds every derived service name the rules rewrite,
+-- so projections and reads resolve names with an indexed lookup.
+CREATE TABLE IF NOT EXISTS service_identity_aliases
+(
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    alias           TEXT NOT NULL,
+    service_name    TEXT NOT NULL,
+    PRIMARY KEY (organization_id, alias),
+    CHECK (alias <> service_name)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS service_identity_aliases;
+DROP TABLE IF EXISTS service_identity_rules;
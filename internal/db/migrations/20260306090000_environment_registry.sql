@@ -0,0 +1,76 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS organization_environments
+(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name            TEXT NOT NULL COLLATE NOCASE,
+    tier            TEXT NOT NULL DEFAULT 'non-production',
+    color           TEXT NOT NULL DEFAULT '',
+    protected       INTEGER NOT NULL DEFAULT 0,
+    sort_order      INTEGER NOT NULL DEFAULT 0,
+    url             TEXT NOT NULL DEFAULT '',
+    source          TEXT NOT NULL DEFAULT 'settings',
+    changed_at_ms   INTEGER NOT NULL DEFAULT 0,
+    created_at      DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (organization_id, name),
+    CHECK (tier IN ('production', 'non-production')),
+    CHECK (source IN ('event', 'settings')),
+    CHECK (length(trim(name)) > 0)
+);
+
+-- environment_aliases maps every name an environment is reported under,
+-- including its own name in any letter case, onto the registered name.
+CREATE TABLE IF NOT EXISTS environment_aliases
+(
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    alias           TEXT NOT NULL COLLATE NOCASE,
+    environment_id  INTEGER NOT NULL REFERENCES organization_environments(id) ON DELETE CASCADE,
+    environment     TEXT NOT NULL,
+    PRIMARY KEY (organization_id, alias)
+);
+
+CREATE INDEX IF NOT EXISTS idx_environment_aliases_environment
+    ON environment_aliases (environment_id);
+
+-- Register the environments already announced by environment events, unless
+-- their latest event deleted them.
+INSERT INTO organization_environments (organization_id, name, sort_order, source, changed_at_ms)
+SELECT
+    latest.organization_id,
+    latest.name,
+    COALESCE(
+        (SELECT p.sort_order
+         FROM organization_environment_priorities p
+         WHERE p.organization_id = latest.organization_id AND p.environment = latest.name),
+        1000
+    ),
+    'event',
+    latest.event_ts_ms
+FROM (
+    SELECT
+        es.organization_id,
+        CASE WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1) ELSE es.subject_id END AS name,
+        es.event_type,
+        es.event_ts_ms,
+        row_number() OVER (
+            PARTITION BY es.organization_id, lower(CASE WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1) ELSE es.subject_id END)
+            ORDER BY es.event_ts_ms DESC, es.seq DESC
+        ) AS rn
+    FROM event_store es
+    WHERE es.subject_type = 'environment'
+) latest
+WHERE latest.rn = 1
+  AND latest.event_type NOT LIKE 'dev.cdevents.environment.deleted.%'
+  AND length(trim(latest.name)) > 0
+ON CONFLICT(organization_id, name) DO NOTHING;
+
+INSERT INTO environment_aliases (organization_id, alias, environment_id, environment)
+SELECT organization_id, name, id, name
+FROM organization_environments
+WHERE true
+ON CONFLICT(organization_id, alias) DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS environment_aliases;
+DROP TABLE IF EXISTS organization_environments;
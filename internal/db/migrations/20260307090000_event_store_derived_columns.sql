@@ -0,0 +1,60 @@
+-- +goose Up
+-- Values the read queries used to derive from subject_id and raw_event_json
+-- on every row are stored on the event at append time instead.
+-- service_name is the subject id after the first '/', environment falls back
+-- to 'unknown', and outcome is the deployment status of service events
+-- ('synced', 'warning', 'out-of-sync' or 'unknown'; empty for other subjects).
+ALTER TABLE event_store ADD COLUMN service_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE event_store ADD COLUMN environment TEXT NOT NULL DEFAULT '';
+ALTER TABLE event_store ADD COLUMN artifact_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE event_store ADD COLUMN outcome TEXT NOT NULL DEFAULT '';
+ALTER TABLE event_store ADD COLUMN actor_name TEXT NOT NULL DEFAULT '';
+
+UPDATE event_store
+SET
+    service_name = CASE
+        WHEN instr(subject_id, '/') > 0 THEN substr(subject_id, instr(subject_id, '/') + 1)
+        ELSE subject_id
+    END,
+    environment = COALESCE(NULLIF(json_extract(raw_event_json, '$.subject.content.environment.id'), ''), 'unknown'),
+    artifact_id = COALESCE(json_extract(raw_event_json, '$.subject.content.artifactId'), ''),
+    outcome = CASE
+        WHEN subject_type <> 'service' THEN ''
+        WHEN event_type LIKE 'dev.cdevents.service.deployed.%' THEN 'synced'
+        WHEN event_type LIKE 'dev.cdevents.service.upgraded.%' THEN 'synced'
+        WHEN event_type LIKE 'dev.cdevents.service.published.%' THEN 'synced'
+        WHEN event_type LIKE 'dev.cdevents.service.rolledback.%' THEN 'warning'
+        WHEN event_type LIKE 'dev.cdevents.service.removed.%' THEN 'out-of-sync'
+        ELSE 'unknown'
+    END,
+    actor_name = COALESCE(json_extract(raw_event_json, '$.subject.content.actor.name'), '');
+
+CREATE INDEX IF NOT EXISTS idx_event_store_org_service_time
+ON event_store(organization_id, subject_type, service_name, event_ts_ms DESC, seq DESC);
+
+CREATE INDEX IF NOT EXISTS idx_event_store_org_environment_time
+ON event_store(organization_id, subject_type, environment, event_ts_ms DESC, seq DESC);
+
+CREATE INDEX IF NOT EXISTS idx_event_store_org_outcome_time
+ON event_store(organization_id, outcome, event_ts_ms DESC, seq DESC)
+WHERE outcome <> '';
+
+CREATE INDEX IF NOT EXISTS idx_event_store_org_artifact
+ON event_store(organization_id, artifact_id)
+WHERE artifact_id <> '';
+
+CREATE INDEX IF NOT EXISTS idx_event_store_org_actor_time
+ON event_store(organization_id, actor_name, event_ts_ms DESC)
+WHERE actor_name <> '';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_event_store_org_actor_time;
+DROP INDEX IF EXISTS idx_event_store_org_artifact;
+DROP INDEX IF EXISTS idx_event_store_org_outcome_time;
+DROP INDEX IF EXISTS idx_event_store_org_environment_time;
+DROP INDEX IF EXISTS idx_event_store_org_service_time;
+ALTER TABLE event_store DROP COLUMN actor_name;
+ALTER TABLE event_store DROP COLUMN outcome;
+ALTER TABLE event_store DROP COLUMN artifact_id;
+ALTER TABLE event_store DROP COLUMN environment;
+ALTER TABLE event_store DROP COLUMN service_name;
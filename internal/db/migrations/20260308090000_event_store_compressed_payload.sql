@@ -0,0 +1,13 @@
+-- +goose Up
+-- Event bodies are written to raw_event_compressed by ddash_event_compress
+-- (DEFLATE against a shared dictionary, see event_payload.go) and
+-- raw_event_json is left empty for those rows. Rows written before this
+-- migration keep their plain body until apps/eventcompress rewrites them;
+-- ddash_event_json reads either form.
+ALTER TABLE event_store ADD COLUMN raw_event_compressed BLOB;
+
+-- +goose Down
+UPDATE event_store
+SET raw_event_json = ddash_event_json(raw_event_json, raw_event_compressed)
+WHERE raw_event_compressed IS NOT NULL;
+ALTER TABLE event_store DROP COLUMN raw_event_compressed;
@@ -0,0 +1,56 @@
+-- +goose Up
+-- The remaining values projections and chain views read from the event body
+-- are stored on the event at append time, so no read has to decompress it.
+-- environment_id is the environment as sent ('' when missing), content_service
+-- is subject.content.service when it is a plain string, and
+-- content_service_id is subject.content.service.id when it is an object.
+-- link_url is subject.content.url, falling back to the pipeline url.
+ALTER TABLE event_store ADD COLUMN environment_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE event_store ADD COLUMN content_service TEXT NOT NULL DEFAULT '';
+ALTER TABLE event_store ADD COLUMN content_service_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE event_store ADD COLUMN pipeline_run_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE event_store ADD COLUMN run_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE event_store ADD COLUMN link_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE event_store ADD COLUMN duration_seconds REAL NOT NULL DEFAULT 0;
+
+-- Compressed bodies are unpacked into raw_event_json for the backfill, so
+-- each is decompressed once, and cleared again afterwards.
+UPDATE event_store
+SET raw_event_json = ddash_event_json(raw_event_json, raw_event_compressed)
+WHERE raw_event_compressed IS NOT NULL;
+
+UPDATE event_store
+SET
+    environment_id = COALESCE(json_extract(raw_event_json, '$.subject.content.environment.id'), ''),
+    content_service = CASE
+        WHEN json_type(raw_event_json, '$.subject.content.service') = 'text'
+        THEN json_extract(raw_event_json, '$.subject.content.service')
+        ELSE ''
+    END,
+    content_service_id = CASE
+        WHEN json_type(raw_event_json, '$.subject.content.service') = 'object'
+        THEN COALESCE(json_extract(raw_event_json, '$.subject.content.service.id'), '')
+        ELSE ''
+    END,
+    pipeline_run_id = COALESCE(json_extract(raw_event_json, '$.subject.content.pipeline.runId'), ''),
+    run_url = COALESCE(json_extract(raw_event_json, '$.subject.content.pipeline.url'), ''),
+    link_url = COALESCE(
+        NULLIF(json_extract(raw_event_json, '$.subject.content.url'), ''),
+        json_extract(raw_event_json, '$.subject.content.pipeline.url'),
+        ''
+    ),
+    duration_seconds = COALESCE(json_extract(raw_event_json, '$.subject.content.durationSeconds'), 0)
+WHERE raw_event_json <> '';
+
+UPDATE event_store
+SET raw_event_json = ''
+WHERE raw_event_compressed IS NOT NULL;
+
+-- +goose Down
+ALTER TABLE event_store DROP COLUMN duration_seconds;
+ALTER TABLE event_store DROP COLUMN link_url;
+ALTER TABLE event_store DROP COLUMN run_url;
+ALTER TABLE event_store DROP COLUMN pipeline_run_id;
+ALTER TABLE event_store DROP COLUMN content_service_id;
+ALTER TABLE event_store DROP COLUMN content_service;
+ALTER TABLE event_store DROP COLUMN environment_id;
@@ -0,0 +1,674 @@
+package db
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/fr0stylo/ddash/internal/db/queries"
+)
+
+// OrganizationBundleVersion is the bundle format written by
+// ExportOrganizationBundle. Imports reject other versions.
+const OrganizationBundleVersion = 1
+
+const organizationBundleBatchSize = 1000
+
+// OrganizationBundle is the manifest on the first line of an organization
+// bundle. Bundles are gzip-compressed NDJSON: the manifest is followed by the
+// organization's events, one ArchivedEvent per line in append order. Ids are
+// not kept; an import re-keys everything to the target organization.
+// Secrets, ingest credentials and members are not exported.
+type OrganizationBundle struct {
+	Version               int                         `json:"version"`
+	ExportedAt            time.Time                   `json:"exported_at"`
+	Organization          BundleOrganization          `json:"organization"`
+	Features              []BundleFeature             `json:"features"`
+	Preferences           []BundlePreference          `json:"preferences"`
+	RequiredFields        []BundleRequiredField       `json:"required_fields"`
+	EnvironmentPriorities []BundleEnvironmentPriority `json:"environment_priorities"`
+	EventPolicies         []BundleEventPolicy         `json:"event_policies"`
+	ServiceMetadata       []BundleServiceMetadata     `json:"service_metadata"`
+	ServiceDependencies   []BundleServiceDependency   `json:"service_dependencies"`
+	GitHubInstallations   []BundleGitHubInstallation  `json:"github_installations"`
+	GitLabProjects        []BundleGitLabProject       `json:"gitlab_projects"`
+	ServiceIdentityRules  []BundleServiceIdentityRule `json:"service_identity_rules,omitempty"`
+	Environments          []BundleEnvironment         `json:"environments,omitempty"`
+}
+
+// BundleOrganization is the exported organization row.
+type BundleOrganization struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// BundleFeature is one organization feature toggle.
+type BundleFeature struct {
+	Key     string `json:"key"`
+	Enabled bool   `json:"enabled"`
+}
+
+// BundlePreference is one organization preference.
+type BundlePreference struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// BundleRequiredField is one required service metadata field.
+type BundleRequiredField struct {
+	Label      string `json:"label"`
+	FieldType  string `json:"field_type"`
+	SortOrder  int64  `json:"sort_order"`
+	Filterable bool   `json:"filterable"`
+}
+
+// BundleEnvironmentPriority is one environment display priority.
+type BundleEnvironmentPriority struct {
+	Environment string `json:"environment"`
+	SortOrder   int64  `json:"sort_order"`
+}
+
+// BundleEventPolicy is one per-subject ingest policy.
+type BundleEventPolicy struct {
+	Subject string `json:"subject"`
+	Mode    string `json:"mode"`
+}
+
+// BundleServiceMetadata is one service metadata value.
+type BundleServiceMetadata struct {
+	Service string `json:"service"`
+	Label   string `json:"label"`
+	Value   string `json:"value"`
+}
+
+// BundleServiceDependency is one service dependency edge.
+type BundleServiceDependency struct {
+	Service   string `json:"service"`
+	DependsOn string `json:"depends_on"`
+}
+
+// BundleGitHubInstallation is one GitHub App installation mapping.
+type BundleGitHubInstallation struct {
+	InstallationID     int64  `json:"installation_id"`
+	Label              string `json:"label"`
+	DefaultEnvironment string `json:"default_environment"`
+	Enabled            bool   `json:"enabled"`
+}
+
+// BundleGitLabProject is one GitLab project mapping.
+type BundleGitLabProject struct {
+	ProjectID          int64  `json:"project_id"`
+	Path               string `json:"path"`
+	DefaultEnvironment string `json:"default_environment"`
+	Enabled            bool   `json:"enabled"`
+}
+
+// BundleServiceIdentityRule is one service identity rule, in the order the
+// rules apply.
+type BundleServiceIdentityRule struct {
+	Kind        string `json:"kind"`
+	Pattern     string `json:"pattern"`
+	ServiceName string `json:"service_name"`
+}
+
+// BundleEnvironment is one environment registry entry, in registry order.
+type BundleEnvironment struct {
+	Name        string   `json:"name"`
+	Tier        string   `json:"tier"`
+	Color       string   `json:"color,omitempty"`
+	Protected   bool     `json:"protected"`
+	URL         string   `json:"url,omitempty"`
+	Source      string   `json:"source"`
+	ChangedAtMs int64    `json:"changed_at_ms"`
+	Aliases     []string `json:"aliases,omitempty"`
+}
+
+// OrganizationExportResult describes one written bundle.
+type OrganizationExportResult struct {
+	OrganizationID int64
+	Name           string
+	EventCount     int64
+}
+
+// OrganizationImportOptions configures an organization bundle import.
+type OrganizationImportOptions struct {
+	// OrganizationID imports into this existing organization. Zero creates a
+	// new organization.
+	OrganizationID int64
+	// Name overrides the bundle's organization name for a new organization.
+	Name string
+	// Reassign moves GitHub installation and GitLab project mappings that
+	// belong to another organization; they are skipped otherwise.
+	Reassign bool
+}
+
+// OrganizationImportResult describes one imported bundle.
+type OrganizationImportResult struct {
+	OrganizationID int64
+	Name           string
+	Created        bool
+	EventsImported int64
+	EventsSkipped  int64
+	Projections    ProjectionRebuildStats
+	// SkippedGitHubInstallations and SkippedGitLabProjects list mappings left
+	// with the organization that already owns them.
+	SkippedGitHubInstallations []int64
+	SkippedGitLabProjects      []int64
+}
+
+// ExportOrganizationBundle writes an organization's settings, service
+// metadata, dependencies, integration mappings and events to w as a bundle.
+// Archived events are not included.
+func (c *Database) ExportOrganizationBundle(ctx context.Context, organizationID int64, w io.Writer) (OrganizationExportResult, error) {
+	bundle, err := c.loadOrganizationBundle(ctx, organizationID)
+	if err != nil {
+		return OrganizationExportResult{}, err
+	}
+	result := OrganizationExportResult{OrganizationID: organizationID, Name: bundle.Organization.Name}
+
+	zw := gzip.NewWriter(w)
+	buffered := bufio.NewWriter(zw)
+	encoder := json.NewEncoder(buffered)
+	if err := encoder.Encode(bundle); err != nil {
+		return result, err
+	}
+	var afterSeq int64
+	for {
+		rows, err := c.Queries.ListEventsForArchive(ctx, queries.ListEventsForArchiveParams{
+			OrganizationID: organizationID,
+			BeforeMs:       math.MaxInt64,
+			AfterSeq:       afterSeq,
+			Limit:          organizationBundleBatchSize,
+		})
+		if err != nil {
+			return result, err
+		}
+		for _, row := range rows {
+			event, err := archivedEventFromRow(row)
+			if err != nil {
+				return result, err
+			}
+			if err := encoder.Encode(event); err != nil {
+				return result, err
+			}
+			afterSeq = row.Seq
+			result.EventCount++
+		}
+		if len(rows) < organizationBundleBatchSize {
+			break
+		}
+	}
+	if err := buffered.Flush(); err != nil {
+		return result, err
+	}
+	return result, zw.Close()
+}
+
+func (c *Database) loadOrganizationBundle(ctx context.Context, organizationID int64) (OrganizationBundle, error) {
+	org, err := c.Queries.GetOrganizationByID(ctx, organizationID)
+	if err != nil {
+		return OrganizationBundle{}, fmt.Errorf("load organization %d: %w", organizationID, err)
+	}
+	bundle := OrganizationBundle{
+		Version:      OrganizationBundleVersion,
+		ExportedAt:   time.Now().UTC(),
+		Organization: BundleOrganization{Name: org.Name, Enabled: org.Enabled == 1},
+	}
+
+	features, err := c.Queries.ListOrganizationFeatures(ctx, organizationID)
+	if err != nil {
+		return bundle, err
+	}
+	for _, row := range features {
+		bundle.Features = append(bundle.Features, BundleFeature{Key: row.FeatureKey, Enabled: row.IsEnabled == 1})
+	}
+	preferences, err := c.Queries.ListOrganizationPreferences(ctx, organizationID)
+	if err != nil {
+		return bundle, err
+	}
+	for _, row := range preferences {
+		bundle.Preferences = append(bundle.Preferences, BundlePreference{Key: row.PreferenceKey, Value: row.PreferenceValue})
+	}
+	fields, err := c.Queries.ListOrganizationRequiredFields(ctx, organizationID)
+	if err != nil {
+		return bundle, err
+	}
+	for _, row := range fields {
+		bundle.RequiredFields = append(bundle.RequiredFields, BundleRequiredField{
+			Label:      row.Label,
+			FieldType:  row.FieldType,
+			SortOrder:  row.SortOrder,
+			Filterable: row.IsFilterable == 1,
+		})
+	}
+	priorities, err := c.Queries.ListOrganizationEnvironmentPriorities(ctx, organizationID)
+	if err != nil {
+		return bundle, err
+	}
+	for _, row := range priorities {
+		bundle.EnvironmentPriorities = append(bundle.EnvironmentPriorities, BundleEnvironmentPriority{Environment: row.Environment, SortOrder: row.SortOrder})
+	}
+	policies, err := c.Queries.ListOrganizationEventPolicies(ctx, organizationID)
+	if err != nil {
+		return bundle, err
+	}
+	for _, row := range policies {
+		bundle.EventPolicies = append(bundle.EventPolicies, BundleEventPolicy{Subject: row.Subject, Mode: row.Mode})
+	}
+	metadata, err := c.Queries.ListServiceMetadataByOrganization(ctx, organizationID)
+	if err != nil {
+		return bundle, err
+	}
+	for _, row := range metadata {
+		bundle.ServiceMetadata = append(bundle.ServiceMetadata, BundleServiceMetadata{Service: row.ServiceName, Label: row.Label, Value: row.Value})
+	}
+	dependencies, err := c.Queries.ListOrganizationServiceDependencies(ctx, organizationID)
+	if err != nil {
+		return bundle, err
+	}
+	for _, row := range dependencies {
+		bundle.ServiceDependencies = append(bundle.ServiceDependencies, BundleServiceDependency{Service: row.ServiceName, DependsOn: row.DependsOnServiceName})
+	}
+	installations, err := c.Queries.ListGitHubInstallationMappings(ctx, organizationID)
+	if err != nil {
+		return bundle, err
+	}
+	for _, row := range installations {
+		bundle.GitHubInstallations = append(bundle.GitHubInstallations, BundleGitHubInstallation{
+			InstallationID:     row.InstallationID,
+			Label:              row.OrganizationLabel,
+			DefaultEnvironment: row.DefaultEnvironment,
+			Enabled:            row.Enabled == 1,
+		})
+	}
+	projects, err := c.Queries.ListGitLabProjectMappings(ctx, organizationID)
+	if err != nil {
+		return bundle, err
+	}
+	for _, row := range projects {
+		bundle.GitLabProjects = append(bundle.GitLabProjects, BundleGitLabProject{
+			ProjectID:          row.ProjectID,
+			Path:               row.ProjectPath,
+			DefaultEnvironment: row.DefaultEnvironment,
+			Enabled:            row.Enabled == 1,
+		})
+	}
+	rules, err := c.Queries.ListServiceIdentityRules(ctx, organizationID)
+	if err != nil {
+		return bundle, err
+	}
+	for _, row := range rules {
+		bundle.ServiceIdentityRules = append(bundle.ServiceIdentityRules, BundleServiceIdentityRule{
+			Kind:        row.Kind,
+			Pattern:     row.Pattern,
+			ServiceName: row.ServiceName,
+		})
+	}
+	environments, err := c.Queries.ListOrganizationEnvironments(ctx, organizationID)
+	if err != nil {
+		return bundle, err
+	}
+	aliases, err := c.Queries.ListEnvironmentAliases(ctx, organizationID)
+	if err != nil {
+		return bundle, err
+	}
+	for _, row := range environments {
+		environment := BundleEnvironment{
+			Name:        row.Name,
+			Tier:        row.Tier,
+			Color:       row.Color,
+			Protected:   row.Protected == 1,
+			URL:         row.Url,
+			Source:      row.Source,
+			ChangedAtMs: row.ChangedAtMs,
+		}
+		for _, alias := range aliases {
+			if alias.EnvironmentID == row.ID && alias.Alias != row.Name {
+				environment.Aliases = append(environment.Aliases, alias.Alias)
+			}
+		}
+		bundle.Environments = append(bundle.Environments, environment)
+	}
+	return bundle, nil
+}
+
+// ImportOrganizationBundle loads a bundle into a new organization with fresh
+// secrets, or into options.OrganizationID, and rebuilds its projections.
+// Settings are replaced, mappings are pointed at the organization unless
+// another organization owns them, and events already present are skipped, so
+// importing the same bundle into the same organization again is a no-op.
+func (c *Database) ImportOrganizationBundle(ctx context.Context, r io.Reader, options OrganizationImportOptions) (OrganizationImportResult, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return OrganizationImportResult{}, err
+	}
+	defer func() { _ = zr.Close() }()
+	decoder := json.NewDecoder(bufio.NewReader(zr))
+
+	var bundle OrganizationBundle
+	if err := decoder.Decode(&bundle); err != nil {
+		return OrganizationImportResult{}, fmt.Errorf("decode bundle manifest: %w", err)
+	}
+	if bundle.Version != OrganizationBundleVersion {
+		return OrganizationImportResult{}, fmt.Errorf("unsupported bundle version %d", bundle.Version)
+	}
+	name := strings.TrimSpace(options.Name)
+	if name == "" {
+		name = strings.TrimSpace(bundle.Organization.Name)
+	}
+	if name == "" && options.OrganizationID <= 0 {
+		return OrganizationImportResult{}, errors.New("bundle has no organization name")
+	}
+
+	var result OrganizationImportResult
+	err = c.WithTx(ctx, func(q *queries.Queries) error {
+		org, created, err := getImportOrganization(ctx, q, options.OrganizationID, name, bundle.Organization.Enabled)
+		if err != nil {
+			return err
+		}
+		result.OrganizationID = org.ID
+		result.Name = org.Name
+		result.Created = created
+		c.identityRules.invalidate(org.ID)
+		return importOrganizationSettings(ctx, q, org.ID, bundle, options.Reassign, &result)
+	})
+	if err != nil {
+		return result, err
+	}
+
+	batch := make([]queries.AppendEventStoreParams, 0, organizationBundleBatchSize)
+	for {
+		var event ArchivedEvent
+		err := decoder.Decode(&event)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return result, fmt.Errorf("decode bundle event: %w", err)
+		}
+		if err == nil {
+			batch = append(batch, event.appendParams(result.OrganizationID))
+		}
+		if len(batch) == organizationBundleBatchSize || (errors.Is(err, io.EOF) && len(batch) > 0) {
+			imported, appendErr := c.appendImportedEvents(ctx, batch)
+			if appendErr != nil {
+				return result, appendErr
+			}
+			result.EventsImported += imported
+			result.EventsSkipped += int64(len(batch)) - imported
+			batch = batch[:0]
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+	}
+
+	stats, err := c.RebuildServiceProjections(ctx, result.OrganizationID)
+	if err != nil {
+		return result, fmt.Errorf("rebuild projections: %w", err)
+	}
+	result.Projections = stats
+	return result, nil
+}
+
+// getImportOrganization returns the target organization, or creates one when
+// no target is given. An organization that happens to share the bundle's name
+// is never imported into implicitly.
+func getImportOrganization(ctx context.Context, q *queries.Queries, organizationID int64, name string, enabled bool) (queries.Organization, bool, error) {
+	if organizationID > 0 {
+		org, err := q.GetOrganizationByID(ctx, organizationID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return org, false, fmt.Errorf("organization %d not found", organizationID)
+		}
+		return org, false, err
+	}
+	org, err := q.GetOrganizationByName(ctx, name)
+	if err == nil {
+		return org, false, fmt.Errorf("organization %q already exists (id %d); import into it by id or choose another name", name, org.ID)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return org, false, err
+	}
+	authToken, err := randomHexToken(16)
+	if err != nil {
+		return org, false, err
+	}
+	secret, err := randomHexToken(24)
+	if err != nil {
+		return org, false, err
+	}
+	joinCode, err := randomHexToken(8)
+	if err != nil {
+		return org, false, err
+	}
+	enabledValue := int64(0)
+	if enabled {
+		enabledValue = 1
+	}
+	org, err = q.CreateOrganization(ctx, queries.CreateOrganizationParams{
+		Name:          name,
+		AuthToken:     authToken,
+		JoinCode:      sql.NullString{String: joinCode, Valid: true},
+		WebhookSecret: secret,
+		Enabled:       enabledValue,
+	})
+	return org, err == nil, err
+}
+
+func importOrganizationSettings(ctx context.Context, q *queries.Queries, organizationID int64, bundle OrganizationBundle, reassign bool, result *OrganizationImportResult) error {
+	for _, feature := range bundle.Features {
+		if err := q.UpsertOrganizationFeature(ctx, queries.UpsertOrganizationFeatureParams{
+			OrganizationID: organizationID,
+			FeatureKey:     feature.Key,
+			IsEnabled:      boolToInt64(feature.Enabled),
+		}); err != nil {
+			return err
+		}
+	}
+	for _, preference := range bundle.Preferences {
+		if err := q.UpsertOrganizationPreference(ctx, queries.UpsertOrganizationPreferenceParams{
+			OrganizationID:  organizationID,
+			PreferenceKey:   preference.Key,
+			PreferenceValue: preference.Value,
+		}); err != nil {
+			return err
+		}
+	}
+	if err := q.DeleteOrganizationRequiredFields(ctx, organizationID); err != nil {
+		return err
+	}
+	for _, field := range bundle.RequiredFields {
+		if _, err := q.CreateOrganizationRequiredField(ctx, queries.CreateOrganizationRequiredFieldParams{
+			OrganizationID: organizationID,
+			Label:          field.Label,
+			FieldType:      field.FieldType,
+			SortOrder:      field.SortOrder,
+			IsFilterable:   boolToInt64(field.Filterable),
+		}); err != nil {
+			return err
+		}
+	}
+	if err := q.DeleteOrganizationEnvironmentPriorities(ctx, organizationID); err != nil {
+		return err
+	}
+	for _, priority := range bundle.EnvironmentPriorities {
+		if _, err := q.CreateOrganizationEnvironmentPriority(ctx, queries.CreateOrganizationEnvironmentPriorityParams{
+			OrganizationID: organizationID,
+			Environment:    priority.Environment,
+			SortOrder:      priority.SortOrder,
+		}); err != nil {
+			return err
+		}
+	}
+	// Imported events only change environments after the changes recorded
+	// here, so replaying them leaves the registry as it was exported.
+	if err := q.DeleteOrganizationEnvironments(ctx, organizationID); err != nil {
+		return err
+	}
+	for _, entry := range bundle.Environments {
+		environment, err := q.UpsertOrganizationEnvironment(ctx, queries.UpsertOrganizationEnvironmentParams{
+			OrganizationID: organizationID,
+			Name:           entry.Name,
+			Tier:           entry.Tier,
+			Color:          entry.Color,
+			Protected:      boolToInt64(entry.Protected),
+			Url:            entry.URL,
+			Source:         entry.Source,
+			ChangedAtMs:    entry.ChangedAtMs,
+		})
+		if err != nil {
+			return err
+		}
+		if err := replaceEnvironmentAliases(ctx, q, environment, entry.Aliases); err != nil {
+			return err
+		}
+	}
+	if err := q.DeleteOrganizationEventPolicies(ctx, organizationID); err != nil {
+		return err
+	}
+	for _, policy := range bundle.EventPolicies {
+		if err := q.UpsertOrganizationEventPolicy(ctx, queries.UpsertOrganizationEventPolicyParams{
+			OrganizationID: organizationID,
+			Subject:        policy.Subject,
+			Mode:           policy.Mode,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, metadata := range bundle.ServiceMetadata {
+		if err := q.UpsertServiceMetadata(ctx, queries.UpsertServiceMetadataParams{
+			OrganizationID: organizationID,
+			ServiceName:    metadata.Service,
+			Label:          metadata.Label,
+			Value:          metadata.Value,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, dependency := range bundle.ServiceDependencies {
+		if err := q.UpsertServiceDependency(ctx, queries.UpsertServiceDependencyParams{
+			OrganizationID:       organizationID,
+			ServiceName:          dependency.Service,
+			DependsOnServiceName: dependency.DependsOn,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, installation := range bundle.GitHubInstallations {
+		owner, err := q.GetGitHubInstallationMappingOrganizationID(ctx, installation.InstallationID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if err == nil && owner != organizationID && !reassign {
+			result.SkippedGitHubInstallations = append(result.SkippedGitHubInstallations, installation.InstallationID)
+			continue
+		}
+		if err := q.UpsertGitHubInstallationMapping(ctx, queries.UpsertGitHubInstallationMappingParams{
+			InstallationID:     installation.InstallationID,
+			OrganizationID:     organizationID,
+			OrganizationLabel:  installation.Label,
+			DefaultEnvironment: installation.DefaultEnvironment,
+			Enabled:            boolToInt64(installation.Enabled),
+		}); err != nil {
+			return err
+		}
+	}
+	for _, project := range bundle.GitLabProjects {
+		owner, err := q.GetGitLabProjectMappingOrganizationID(ctx, project.ProjectID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if err == nil && owner != organizationID && !reassign {
+			result.SkippedGitLabProjects = append(result.SkippedGitLabProjects, project.ProjectID)
+			continue
+		}
+		if err := q.UpsertGitLabProjectMapping(ctx, queries.UpsertGitLabProjectMappingParams{
+			ProjectID:          project.ProjectID,
+			OrganizationID:     organizationID,
+			ProjectPath:        project.Path,
+			DefaultEnvironment: project.DefaultEnvironment,
+			Enabled:            boolToInt64(project.Enabled),
+		}); err != nil {
+			return err
+		}
+	}
+	if err := q.DeleteServiceIdentityRules(ctx, organizationID); err != nil {
+		return err
+	}
+	for _, rule := range bundle.ServiceIdentityRules {
+		if _, err := q.CreateServiceIdentityRule(ctx, queries.CreateServiceIdentityRuleParams{
+			OrganizationID: organizationID,
+			Kind:           rule.Kind,
+			Pattern:        rule.Pattern,
+			ServiceName:    rule.ServiceName,
+		}); err != nil {
+			return err
+		}
+	}
+	// Regex rules map the names of imported events as they are appended.
+	return syncServiceIdentityAliases(ctx, q, organizationID)
+}
+
+// appendImportedEvents appends one batch of imported events through the live
+// projectors, so their checkpoints keep up with the new sequence numbers, and
+// returns how many were not already present.
+func (c *Database) appendImportedEvents(ctx context.Context, batch []queries.AppendEventStoreParams) (int64, error) {
+	var imported int64
+	err := c.WithTx(ctx, func(q *queries.Queries) error {
+		projectors := newLiveProjectors(c.projectors)
+		for _, params := range batch {
+			_, inserted, err := appendEventWithProjections(ctx, q, params, projectors, c.identityRules)
+			if err != nil {
+				return err
+			}
+			if inserted {
+				imported++
+			}
+		}
+		return projectors.commit(ctx, q)
+	})
+	return imported, err
+}
+
+func (e ArchivedEvent) appendParams(organizationID int64) queries.AppendEventStoreParams {
+	params := queries.AppendEventStoreParams{
+		OrganizationID: organizationID,
+		EventID:        e.EventID,
+		EventType:      e.EventType,
+		EventSource:    e.EventSource,
+		EventTimestamp: e.EventTimestamp,
+		EventTsMs:      e.EventTsMs,
+		SubjectID:      e.SubjectID,
+		SubjectType:    e.SubjectType,
+		RawEventJson:   e.RawEvent,
+	}
+	if e.SubjectSource != nil {
+		params.SubjectSource = sql.NullString{String: *e.SubjectSource, Valid: true}
+	}
+	if e.ChainID != nil {
+		params.ChainID = sql.NullString{String: *e.ChainID, Valid: true}
+	}
+	return params
+}
+
+func boolToInt64(value bool) int64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+func randomHexToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
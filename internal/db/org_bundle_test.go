@@ -0,0 +1,178 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fr0stylo/ddash/internal/db/queries"
+)
+
+func TestOrganizationBundle_ImportReKeysIdempotently(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	source := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, source)
+	appendSubjectEvent(t, ctx, source, org.ID, "change-1", "dev.cdevents.change.merged.0.3.0", recentTimestamp(0), "change", "change/abc", "", "pkg:generic/payments@abc")
+	appendEvent(t, ctx, source, org.ID, "deploy-1", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(time.Minute), "service/payments", "staging", "pkg:generic/payments@abc")
+	appendEvent(t, ctx, source, org.ID, "deploy-2", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(2*time.Minute), "service/payments", "production", "pkg:generic/payments@abc")
+	if err := source.UpsertOrganizationPreference(ctx, org.ID, "event_retention_days", "90"); err != nil {
+		t.Fatalf("upsert preference: %v", err)
+	}
+	if _, err := source.CreateOrganizationEnvironmentPriority(ctx, queries.CreateOrganizationEnvironmentPriorityParams{OrganizationID: org.ID, Environment: "production", SortOrder: 0}); err != nil {
+		t.Fatalf("create environment priority: %v", err)
+	}
+	if err := source.UpsertServiceMetadata(ctx, queries.UpsertServiceMetadataParams{OrganizationID: org.ID, ServiceName: "payments", Label: "Owner", Value: "team-a"}); err != nil {
+		t.Fatalf("upsert metadata: %v", err)
+	}
+	if err := source.UpsertServiceDependency(ctx, queries.UpsertServiceDependencyParams{OrganizationID: org.ID, ServiceName: "payments", DependsOnServiceName: "ledger"}); err != nil {
+		t.Fatalf("upsert dependency: %v", err)
+	}
+	if _, err := source.CreateServiceIdentityRule(ctx, queries.CreateServiceIdentityRuleParams{OrganizationID: org.ID, Kind: ServiceIdentityKindAlias, Pattern: "payments-api", ServiceName: "payments"}); err != nil {
+		t.Fatalf("create service identity rule: %v", err)
+	}
+	if _, err := source.SaveOrganizationEnvironment(ctx, EnvironmentInput{OrganizationID: org.ID, Name: "production", Tier: EnvironmentTierProduction, Protected: true, Aliases: []string{"prod"}}); err != nil {
+		t.Fatalf("save environment: %v", err)
+	}
+	if err := source.UpsertGitLabProjectMapping(ctx, queries.UpsertGitLabProjectMappingParams{ProjectID: 42, OrganizationID: org.ID, ProjectPath: "acme/payments", DefaultEnvironment: "staging", Enabled: 1}); err != nil {
+		t.Fatalf("upsert gitlab mapping: %v", err)
+	}
+
+	var bundle bytes.Buffer
+	exported, err := source.ExportOrganizationBundle(ctx, org.ID, &bundle)
+	if err != nil {
+		t.Fatalf("export bundle: %v", err)
+	}
+	if exported.EventCount != 3 {
+		t.Fatalf("unexpected export: %+v", exported)
+	}
+
+	// Occupy the first id in the target so the import has to re-key.
+	target := newTestDatabase(t)
+	placeholder, err := target.CreateOrganization(ctx, queries.CreateOrganizationParams{Name: "placeholder", AuthToken: "placeholder", WebhookSecret: "secret", Enabled: 1})
+	if err != nil {
+		t.Fatalf("create placeholder organization: %v", err)
+	}
+	imported, err := target.ImportOrganizationBundle(ctx, bytes.NewReader(bundle.Bytes()), OrganizationImportOptions{})
+	if err != nil {
+		t.Fatalf("import bundle: %v", err)
+	}
+	if !imported.Created || imported.OrganizationID == placeholder.ID || imported.Name != org.Name || imported.EventsImported != 3 {
+		t.Fatalf("unexpected import: %+v", imported)
+	}
+	again, err := target.ImportOrganizationBundle(ctx, bytes.NewReader(bundle.Bytes()), OrganizationImportOptions{OrganizationID: imported.OrganizationID})
+	if err != nil {
+		t.Fatalf("import bundle again: %v", err)
+	}
+	if again.Created || again.OrganizationID != imported.OrganizationID || again.EventsImported != 0 || again.EventsSkipped != 3 {
+		t.Fatalf("expected second import to be a no-op: %+v", again)
+	}
+
+	retention, err := target.GetOrganizationPreference(ctx, queries.GetOrganizationPreferenceParams{OrganizationID: imported.OrganizationID, PreferenceKey: "event_retention_days"})
+	if err != nil || retention != "90" {
+		t.Fatalf("unexpected retention preference: %q err=%v", retention, err)
+	}
+	priorities, err := target.ListOrganizationEnvironmentPriorities(ctx, imported.OrganizationID)
+	if err != nil || len(priorities) != 1 {
+		t.Fatalf("expected environment priorities to be replaced: %+v err=%v", priorities, err)
+	}
+	dependencies, err := target.ListOrganizationServiceDependencies(ctx, imported.OrganizationID)
+	if err != nil || len(dependencies) != 1 || dependencies[0].DependsOnServiceName != "ledger" {
+		t.Fatalf("unexpected dependencies: %+v err=%v", dependencies, err)
+	}
+	aliases, err := target.ListServiceIdentityAliases(ctx, imported.OrganizationID)
+	if err != nil || len(aliases) != 1 || aliases[0].Alias != "payments-api" {
+		t.Fatalf("expected service identity rules to be imported: %+v err=%v", aliases, err)
+	}
+	environmentAliases, err := target.ListEnvironmentAliases(ctx, imported.OrganizationID)
+	if err != nil || len(environmentAliases) != 2 || environmentAliases[0].Environment != "production" {
+		t.Fatalf("expected the environment registry to be imported: %+v err=%v", environmentAliases, err)
+	}
+	mapped, err := target.GetOrganizationByGitLabProjectID(ctx, 42)
+	if err != nil || mapped.ID != imported.OrganizationID {
+		t.Fatalf("expected gitlab project to map to imported org: %+v err=%v", mapped, err)
+	}
+
+	want := dumpProjectionRows(t, ctx, source, org.ID)
+	got := dumpProjectionRows(t, ctx, target, imported.OrganizationID)
+	for _, table := range projectionTables {
+		rekeyed := make([]string, 0, len(want[table]))
+		for _, row := range want[table] {
+			rekeyed = append(rekeyed, strings.Replace(row, fmt.Sprintf("organization_id=%d ", org.ID), fmt.Sprintf("organization_id=%d ", imported.OrganizationID), 1))
+		}
+		slices.Sort(rekeyed)
+		if !slices.Equal(got[table], rekeyed) {
+			t.Fatalf("%s differs after import:\nsource=%v\ntarget=%v", table, rekeyed, got[table])
+		}
+	}
+}
+
+func TestOrganizationBundle_ImportKeepsMappingsOfOtherOrganizations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	source := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, source)
+	if err := source.UpsertGitHubInstallationMapping(ctx, queries.UpsertGitHubInstallationMappingParams{InstallationID: 7, OrganizationID: org.ID, OrganizationLabel: "acme", DefaultEnvironment: "production", Enabled: 1}); err != nil {
+		t.Fatalf("upsert github mapping: %v", err)
+	}
+	if err := source.UpsertGitLabProjectMapping(ctx, queries.UpsertGitLabProjectMappingParams{ProjectID: 42, OrganizationID: org.ID, ProjectPath: "acme/payments", DefaultEnvironment: "staging", Enabled: 1}); err != nil {
+		t.Fatalf("upsert gitlab mapping: %v", err)
+	}
+	var bundle bytes.Buffer
+	if _, err := source.ExportOrganizationBundle(ctx, org.ID, &bundle); err != nil {
+		t.Fatalf("export bundle: %v", err)
+	}
+
+	target := newTestDatabase(t)
+	existing, err := target.CreateOrganization(ctx, queries.CreateOrganizationParams{Name: org.Name, AuthToken: "existing", WebhookSecret: "secret", Enabled: 1})
+	if err != nil {
+		t.Fatalf("create existing organization: %v", err)
+	}
+	if err := target.UpsertGitHubInstallationMapping(ctx, queries.UpsertGitHubInstallationMappingParams{InstallationID: 7, OrganizationID: existing.ID, OrganizationLabel: "existing", Enabled: 1}); err != nil {
+		t.Fatalf("upsert existing github mapping: %v", err)
+	}
+	if err := target.UpsertGitLabProjectMapping(ctx, queries.UpsertGitLabProjectMappingParams{ProjectID: 42, OrganizationID: existing.ID, ProjectPath: "existing/payments", Enabled: 1}); err != nil {
+		t.Fatalf("upsert existing gitlab mapping: %v", err)
+	}
+
+	if _, err := target.ImportOrganizationBundle(ctx, bytes.NewReader(bundle.Bytes()), OrganizationImportOptions{}); err == nil {
+		t.Fatal("expected an import without a target not to merge into the organization with the same name")
+	}
+	imported, err := target.ImportOrganizationBundle(ctx, bytes.NewReader(bundle.Bytes()), OrganizationImportOptions{Name: "acme-imported"})
+	if err != nil {
+		t.Fatalf("import bundle: %v", err)
+	}
+	if !imported.Created || imported.OrganizationID == existing.ID {
+		t.Fatalf("expected a new organization instead of merging by name: %+v", imported)
+	}
+	if !slices.Equal(imported.SkippedGitHubInstallations, []int64{7}) || !slices.Equal(imported.SkippedGitLabProjects, []int64{42}) {
+		t.Fatalf("expected mappings of the existing organization to be skipped: %+v", imported)
+	}
+	if mapped, err := target.GetOrganizationByGitLabProjectID(ctx, 42); err != nil || mapped.ID != existing.ID {
+		t.Fatalf("expected gitlab project to stay with the existing org: %+v err=%v", mapped, err)
+	}
+
+	reassigned, err := target.ImportOrganizationBundle(ctx, bytes.NewReader(bundle.Bytes()), OrganizationImportOptions{OrganizationID: imported.OrganizationID, Reassign: true})
+	if err != nil {
+		t.Fatalf("import bundle with reassign: %v", err)
+	}
+	if reassigned.Created || len(reassigned.SkippedGitHubInstallations) != 0 || len(reassigned.SkippedGitLabProjects) != 0 {
+		t.Fatalf("unexpected reassigning import: %+v", reassigned)
+	}
+	if mapped, err := target.GetOrganizationByGitHubInstallationID(ctx, 7); err != nil || mapped.ID != imported.OrganizationID {
+		t.Fatalf("expected github installation to move to the imported org: %+v err=%v", mapped, err)
+	}
+	if mapped, err := target.GetOrganizationByGitLabProjectID(ctx, 42); err != nil || mapped.ID != imported.OrganizationID {
+		t.Fatalf("expected gitlab project to move to the imported org: %+v err=%v", mapped, err)
+	}
+
+	if _, err := target.ImportOrganizationBundle(ctx, bytes.NewReader(bundle.Bytes()), OrganizationImportOptions{OrganizationID: 9999}); err == nil {
+		t.Fatal("expected an unknown target organization to fail")
+	}
+}
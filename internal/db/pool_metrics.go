@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// PoolStats reports the writer and reader pool statistics.
+func (c *Database) PoolStats() (write, read sql.DBStats) {
+	return c.db.Stats(), c.readDB.Stats()
+}
+
+// registerPoolMetrics exports pool saturation for the writer and reader pools,
+// labelled with a pool attribute of "write" or "read".
+func registerPoolMetrics(c *Database) metric.Registration {
+	meter := otel.Meter("github.com/fr0stylo/ddash/internal/db")
+	inUse, _ := meter.Int64ObservableGauge("ddash.db.pool.in_use")
+	idle, _ := meter.Int64ObservableGauge("ddash.db.pool.idle")
+	maxOpen, _ := meter.Int64ObservableGauge("ddash.db.pool.max_open")
+	waits, _ := meter.Int64ObservableCounter("ddash.db.pool.wait_count")
+	waitMs, _ := meter.Int64ObservableCounter("ddash.db.pool.wait_duration_ms")
+
+	registration, _ := meter.RegisterCallback(func(_ context.Context, observer metric.Observer) error {
+		write, read := c.PoolStats()
+		for _, pool := range []struct {
+			name  string
+			stats sql.DBStats
+		}{{"write", write}, {"read", read}} {
+			attrs := metric.WithAttributes(attribute.String("pool", pool.name))
+			observer.ObserveInt64(inUse, int64(pool.stats.InUse), attrs)
+			observer.ObserveInt64(idle, int64(pool.stats.Idle), attrs)
+			observer.ObserveInt64(maxOpen, int64(pool.stats.MaxOpenConnections), attrs)
+			observer.ObserveInt64(waits, pool.stats.WaitCount, attrs)
+			observer.ObserveInt64(waitMs, pool.stats.WaitDuration.Milliseconds(), attrs)
+		}
+		return nil
+	}, inUse, idle, maxOpen, waits, waitMs)
+	return registration
+}
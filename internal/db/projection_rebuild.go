@@ -0,0 +1,948 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	sqldriver "database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fr0stylo/ddash/internal/db/queries"
+)
+
+// projectionTables lists the tables rebuilt from event_store. A rebuild
+// fills same-named TEMP tables on a dedicated connection, where they shadow
+// the real ones, and swaps their rows in when it is done.
+var projectionTables = []string{
+	"service_current_state",
+	"service_env_state",
+	"service_delivery_stats_daily",
+	"service_change_links",
+	"service_pipeline_stats_daily",
+	"service_deployment_durations",
+	"service_environment_drift",
+	"service_redeployment_stats",
+	"service_throughput_stats",
+	"service_incident_links",
+}
+
+// foreignKeyClause matches column references to other tables, which a TEMP
+// table cannot enforce against the main schema.
+var foreignKeyClause = regexp.MustCompile(`(?i)\s+REFERENCES\s+\w+\s*\([^)]*\)(\s+ON\s+DELETE\s+\w+)?`)
+
+// ErrProjectorLagging indicates a projector has not applied the whole event log.
+var ErrProjectorLagging = errors.New("projector is lagging")
+
+// ProjectionRebuildStats reports row counts after rebuild.
+type ProjectionRebuildStats struct {
+	CurrentStateRows       int64
+	EnvStateRows           int64
+	DailyStatsRows         int64
+	ChangeLinkRows         int64
+	PipelineStatsRows      int64
+	DeploymentDurationRows int64
+	EnvironmentDriftRows   int64
+	RedeploymentStatsRows  int64
+	ThroughputStatsRows    int64
+	IncidentLinkRows       int64
+}
+
+// ProjectionRebuildStep describes one step of an organization rebuild.
+type ProjectionRebuildStep struct {
+	OrganizationID int64
+	Name           string
+	Index          int
+	Total          int
+}
+
+// ProjectionRebuildProgress is called before each rebuild step starts.
+type ProjectionRebuildProgress func(ProjectionRebuildStep)
+
+type projectionRebuildStatement struct {
+	table string
+	query string
+}
+
+// RebuildServiceProjections rebuilds projection tables from event_store for
+// one organization, or for every organization when organizationID is zero.
+func (c *Database) RebuildServiceProjections(ctx context.Context, organizationID int64) (ProjectionRebuildStats, error) {
+	return c.RebuildServiceProjectionsWithProgress(ctx, organizationID, nil)
+}
+
+// RebuildServiceProjectionsWithProgress rebuilds projections like
+// RebuildServiceProjections and reports each step to progress. Organizations
+// are rebuilt one at a time and keep serving their current projections until
+// the rebuilt rows are swapped in.
+func (c *Database) RebuildServiceProjectionsWithProgress(ctx context.Context, organizationID int64, progress ProjectionRebuildProgress) (ProjectionRebuildStats, error) {
+	organizationIDs := []int64{organizationID}
+	if organizationID <= 0 {
+		orgs, err := c.ListOrganizations(ctx)
+		if err != nil {
+			return ProjectionRebuildStats{}, err
+		}
+		organizationIDs = organizationIDs[:0]
+		for _, org := range orgs {
+			organizationIDs = append(organizationIDs, org.ID)
+		}
+	}
+	for _, id := range organizationIDs {
+		if err := c.rebuildOrganizationProjections(ctx, id, progress); err != nil {
+			return ProjectionRebuildStats{}, fmt.Errorf("rebuild organization %d: %w", id, err)
+		}
+	}
+
+	stats := ProjectionRebuildStats{}
+	var err error
+	stats.CurrentStateRows, err = c.countProjectionRows(ctx, "service_current_state", organizationID)
+	if err != nil {
+		return ProjectionRebuildStats{}, err
+	}
+	stats.EnvStateRows, err = c.countProjectionRows(ctx, "service_env_state", organizationID)
+	if err != nil {
+		return ProjectionRebuildStats{}, err
+	}
+	stats.DailyStatsRows, err = c.countProjectionRows(ctx, "service_delivery_stats_daily", organizationID)
+	if err != nil {
+		return ProjectionRebuildStats{}, err
+	}
+	stats.ChangeLinkRows, err = c.countProjectionRows(ctx, "service_change_links", organizationID)
+	if err != nil {
+		return ProjectionRebuildStats{}, err
+	}
+	stats.PipelineStatsRows, err = c.countProjectionRows(ctx, "service_pipeline_stats_daily", organizationID)
+	if err != nil {
+		return ProjectionRebuildStats{}, err
+	}
+	stats.DeploymentDurationRows, err = c.countProjectionRows(ctx, "service_deployment_durations", organizationID)
+	if err != nil {
+		return ProjectionRebuildStats{}, err
+	}
+	stats.EnvironmentDriftRows, err = c.countProjectionRows(ctx, "service_environment_drift", organizationID)
+	if err != nil {
+		return ProjectionRebuildStats{}, err
+	}
+	stats.RedeploymentStatsRows, err = c.countProjectionRows(ctx, "service_redeployment_stats", organizationID)
+	if err != nil {
+		return ProjectionRebuildStats{}, err
+	}
+	stats.ThroughputStatsRows, err = c.countProjectionRows(ctx, "service_throughput_stats", organizationID)
+	if err != nil {
+		return ProjectionRebuildStats{}, err
+	}
+	stats.IncidentLinkRows, err = c.countProjectionRows(ctx, "service_incident_links", organizationID)
+	if err != nil {
+		return ProjectionRebuildStats{}, err
+	}
+
+	return stats, nil
+}
+
+// rebuildOrganizationProjections builds one organization's projections into
+// shadow tables from events up to the current watermark, then replaces the
+// organization's rows in a single transaction.
+func (c *Database) rebuildOrganizationProjections(ctx context.Context, organizationID int64, progress ProjectionRebuildProgress) (err error) {
+	total := len(projectionRebuildStatements) + 4
+	index := 0
+	report := func(name string) {
+		index++
+		if progress != nil {
+			progress(ProjectionRebuildStep{OrganizationID: organizationID, Name: name, Index: index, Total: total})
+		}
+	}
+
+	report("prepare shadow tables")
+	// A lagging projector would later apply events the rebuild already
+	// covers, so the built-in projectors are caught up first.
+	for {
+		lagging, err := c.CatchUpProjectors(ctx, defaultProjectorBatchSize)
+		if err != nil {
+			return err
+		}
+		if !lagging {
+			break
+		}
+	}
+
+	// Shadow tables are built on a connection of their own, outside the
+	// single-connection writer pool, so appends carry on during the build.
+	rebuildDB, err := sql.Open(driver, c.dsn)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rebuildDB.Close() }()
+	conn, err := rebuildDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if dropErr := dropShadowProjectionTables(context.WithoutCancel(ctx), conn); dropErr != nil {
+			// Leftover shadow tables would hide the real projections from
+			// every later query on this connection, so it must not be reused.
+			_ = conn.Raw(func(any) error { return sqldriver.ErrBadConn })
+			err = errors.Join(err, dropErr)
+		}
+		_ = conn.Close()
+	}()
+
+	if err := createShadowProjectionTables(ctx, conn); err != nil {
+		return err
+	}
+	watermark, err := c.buildShadowProjections(ctx, conn, organizationID, report)
+	if err != nil {
+		return err
+	}
+	report("swap")
+	return c.swapShadowProjections(ctx, conn, organizationID, watermark)
+}
+
+func createShadowProjectionTables(ctx context.Context, conn *sql.Conn) error {
+	if err := dropShadowProjectionTables(ctx, conn); err != nil {
+		return err
+	}
+	for _, table := range projectionTables {
+		var schema string
+		if err := conn.QueryRowContext(ctx, "SELECT sql FROM main.sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&schema); err != nil {
+			return fmt.Errorf("load %s schema: %w", table, err)
+		}
+		definition, ok := strings.CutPrefix(foreignKeyClause.ReplaceAllString(schema, ""), "CREATE TABLE ")
+		if !ok {
+			return fmt.Errorf("unexpected %s schema", table)
+		}
+		if _, err := conn.ExecContext(ctx, "CREATE TEMP TABLE "+definition); err != nil {
+			return fmt.Errorf("create shadow %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+func dropShadowProjectionTables(ctx context.Context, conn *sql.Conn) error {
+	for _, table := range projectionTables {
+		if _, err := conn.ExecContext(ctx, "DROP TABLE IF EXISTS temp."+table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildShadowProjections fills the shadow tables inside one read snapshot and
+// returns the last event seq it covered.
+func (c *Database) buildShadowProjections(ctx context.Context, conn *sql.Conn, organizationID int64, report func(string)) (int64, error) {
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	watermark, err := func() (int64, error) {
+		var watermark int64
+		if err := tx.QueryRowContext(ctx, "SELECT COALESCE(MAX(seq), 0) FROM event_store WHERE organization_id = ?", organizationID).Scan(&watermark); err != nil {
+			return 0, err
+		}
+		q := queries.New(newInstrumentedDBTX(tx, c.tracker))
+		archivedBefore, err := q.GetEventArchiveCutoff(ctx, organizationID)
+		if err != nil {
+			return 0, err
+		}
+		if err := seedArchivedProjections(ctx, tx, organizationID, archivedBefore); err != nil {
+			return 0, err
+		}
+		for _, statement := range projectionRebuildStatements {
+			report(statement.table)
+			if _, err := tx.ExecContext(ctx, statement.query, organizationID, watermark, archivedBefore); err != nil {
+				return 0, fmt.Errorf("build %s: %w", statement.table, err)
+			}
+		}
+		report("service_redeployment_stats, service_environment_drift")
+		if err := rebuildDeploymentHistoryProjections(ctx, tx, q, organizationID, watermark, archivedBefore); err != nil {
+			return 0, err
+		}
+		report("service_incident_links")
+		if err := rebuildIncidentProjections(ctx, tx, q, organizationID, watermark, archivedBefore); err != nil {
+			return 0, err
+		}
+		return watermark, nil
+	}()
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return 0, rollbackErr
+		}
+		return 0, err
+	}
+	return watermark, tx.Commit()
+}
+
+// archivedProjectionSeeds copy the projection rows contributed by archived
+// events into the shadow tables, bound as organization ?1 and archive cutoff
+// ?2. The cutoff starts a UTC week, so these rows never overlap the rows built
+// from the events that are kept. The archived environment state is mapped
+// through the current service identity and environment aliases. The other
+// rows keep the names they were projected under, because merging aggregated
+// rows of several names is not reversible; the service identities page says
+// so.
+var archivedProjectionSeeds = []string{
+	`INSERT INTO temp.service_env_state (
+		organization_id, service_name, environment,
+		latest_event_seq, latest_event_type, latest_event_ts_ms,
+		latest_status, latest_artifact_id
+	)
+	SELECT organization_id, service_name, environment, latest_event_seq, latest_event_type, latest_event_ts_ms, latest_status, latest_artifact_id
+	FROM (
+		SELECT
+			a.organization_id,
+			COALESCE(sia.service_name, a.service_name) AS service_name,
+			COALESCE(ea.environment, a.environment) AS environment,
+			a.latest_event_seq,
+			a.latest_event_type,
+			a.latest_event_ts_ms,
+			a.latest_status,
+			a.latest_artifact_id,
+			row_number() OVER (
+				PARTITION BY COALESCE(sia.service_name, a.service_name), COALESCE(ea.environment, a.environment)
+				ORDER BY a.latest_event_ts_ms DESC, a.latest_event_seq DESC
+			) AS rn
+		FROM main.event_archive_env_state a
+		LEFT JOIN main.service_identity_aliases sia
+			ON sia.organization_id = a.organization_id
+			AND sia.alias = a.service_name
+		LEFT JOIN main.environment_aliases ea
+			ON ea.organization_id = a.organization_id
+			AND ea.alias = a.environment
+		WHERE a.organization_id = ?1
+	)
+	WHERE rn = 1`,
+	`INSERT INTO temp.service_delivery_stats_daily SELECT * FROM main.service_delivery_stats_daily
+	WHERE organization_id = ?1 AND day_utc < date(?2 / 1000, 'unixepoch')`,
+	`INSERT INTO temp.service_pipeline_stats_daily SELECT * FROM main.service_pipeline_stats_daily
+	WHERE organization_id = ?1 AND day_utc < date(?2 / 1000, 'unixepoch')`,
+	`INSERT INTO temp.service_redeployment_stats SELECT * FROM main.service_redeployment_stats
+	WHERE organization_id = ?1 AND day_utc < date(?2 / 1000, 'unixepoch')`,
+	`INSERT INTO temp.service_throughput_stats SELECT * FROM main.service_throughput_stats
+	WHERE organization_id = ?1 AND week_start < date(?2 / 1000, 'unixepoch')`,
+	`INSERT INTO temp.service_change_links SELECT * FROM main.service_change_links
+	WHERE organization_id = ?1 AND event_ts_ms < ?2`,
+	`INSERT INTO temp.service_deployment_durations SELECT * FROM main.service_deployment_durations
+	WHERE organization_id = ?1 AND event_ts_ms < ?2`,
+	`INSERT INTO temp.service_environment_drift SELECT * FROM main.service_environment_drift
+	WHERE organization_id = ?1 AND drift_detected_at < ?2`,
+	`INSERT INTO temp.service_incident_links SELECT * FROM main.service_incident_links
+	WHERE organization_id = ?1 AND linked_at < ?2`,
+}
+
+// seedArchivedProjections keeps the history of archived events in a rebuild.
+func seedArchivedProjections(ctx context.Context, tx *sql.Tx, organizationID, archivedBefore int64) error {
+	if archivedBefore <= 0 {
+		return nil
+	}
+	for _, query := range archivedProjectionSeeds {
+		if _, err := tx.ExecContext(ctx, query, organizationID, archivedBefore); err != nil {
+			return fmt.Errorf("seed archived projections: %w", err)
+		}
+	}
+	return nil
+}
+
+// swapShadowProjections replaces the organization's projection rows with the
+// shadow rows. Deleting first takes the write lock, so events appended after
+// the watermark can be applied to the shadow tables before the copy without
+// racing further appends.
+func (c *Database) swapShadowProjections(ctx context.Context, conn *sql.Conn, organizationID, watermark int64) error {
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	err = func() error {
+		for _, table := range projectionTables {
+			if _, err := tx.ExecContext(ctx, "DELETE FROM main."+table+" WHERE organization_id = ?", organizationID); err != nil {
+				return err
+			}
+		}
+		if err := requireBuiltinProjectorsLive(ctx, tx); err != nil {
+			return err
+		}
+		q := queries.New(newInstrumentedDBTX(tx, c.tracker))
+		if err := applyShadowCatchUp(ctx, tx, q, organizationID, watermark); err != nil {
+			return err
+		}
+		for _, table := range projectionTables {
+			if _, err := tx.ExecContext(ctx, "INSERT INTO main."+table+" SELECT * FROM temp."+table); err != nil {
+				return fmt.Errorf("swap %s: %w", table, err)
+			}
+		}
+		return nil
+	}()
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return rollbackErr
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// requireBuiltinProjectorsLive fails the swap when a built-in projector fell
+// behind the log during the rebuild.
+func requireBuiltinProjectorsLive(ctx context.Context, tx *sql.Tx) error {
+	for _, projector := range builtinProjectors {
+		var lagging bool
+		if err := tx.QueryRowContext(ctx, `SELECT COALESCE((SELECT last_seq FROM main.projector_checkpoints WHERE name = ?), 0) < (SELECT COALESCE(MAX(seq), 0) FROM main.event_store)`, projector.Name()).Scan(&lagging); err != nil {
+			return err
+		}
+		if lagging {
+			return fmt.Errorf("%w: %s", ErrProjectorLagging, projector.Name())
+		}
+	}
+	return nil
+}
+
+// applyShadowCatchUp applies the built-in projectors to events appended after
+// the watermark, writing to the shadow tables.
+func applyShadowCatchUp(ctx context.Context, tx *sql.Tx, q *queries.Queries, organizationID, watermark int64) error {
+	rows, err := tx.QueryContext(ctx, `SELECT seq, organization_id, event_type, subject_type, subject_id, event_ts_ms
+		FROM event_store
+		WHERE organization_id = ? AND seq > ?
+		ORDER BY seq`, organizationID, watermark)
+	if err != nil {
+		return err
+	}
+	pending := []ProjectedEvent{}
+	for rows.Next() {
+		var event ProjectedEvent
+		if err := rows.Scan(&event.Seq, &event.OrganizationID, &event.EventType, &event.SubjectType, &event.SubjectID, &event.EventTsMs); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		pending = append(pending, event)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, event := range pending {
+		for _, projector := range builtinProjectors {
+			if !projectorConsumes(projector, event) {
+				continue
+			}
+			if err := projector.Apply(ctx, q, event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// projectionRebuildStatements fill the shadow projection tables for one
+// organization from events up to a watermark, bound as ?1 and ?2. Events
+// older than the archive cutoff ?3 are skipped: the rows they contributed are
+// seeded from the live projections by seedArchivedProjections.
+var projectionRebuildStatements = []projectionRebuildStatement{
+	{
+		table: "service_env_state",
+		query: `INSERT INTO service_env_state (
+			organization_id, service_name, environment,
+			latest_event_seq, latest_event_type, latest_event_ts_ms,
+			latest_status, latest_artifact_id
+		)
+		WITH ranked AS (
+			SELECT
+				es.organization_id,
+				COALESCE(sia.service_name, es.service_name) AS service_name,
+				COALESCE(ea.environment, es.environment) AS environment,
+				es.seq,
+				es.event_type,
+				es.event_ts_ms,
+				es.artifact_id,
+				es.outcome AS status,
+				row_number() OVER (
+					PARTITION BY es.organization_id,
+					COALESCE(sia.service_name, es.service_name),
+					COALESCE(ea.environment, es.environment)
+					ORDER BY es.event_ts_ms DESC, es.seq DESC
+				) AS rn
+			FROM event_store es
+			LEFT JOIN service_identity_aliases sia
+				ON sia.organization_id = es.organization_id
+				AND sia.alias = es.service_name
+			LEFT JOIN environment_aliases ea
+				ON ea.organization_id = es.organization_id
+				AND ea.alias = es.environment
+			WHERE es.organization_id = ?1 AND es.seq <= ?2 AND es.event_ts_ms >= ?3 AND es.subject_type = 'service'
+		)
+		SELECT
+			organization_id,
+			service_name,
+			environment,
+			seq,
+			event_type,
+			event_ts_ms,
+			status,
+			artifact_id
+		FROM ranked
+		WHERE rn = 1
+		ON CONFLICT(organization_id, service_name, environment) DO UPDATE SET
+			latest_event_seq = excluded.latest_event_seq,
+			latest_event_type = excluded.latest_event_type,
+			latest_event_ts_ms = excluded.latest_event_ts_ms,
+			latest_status = excluded.latest_status,
+			latest_artifact_id = excluded.latest_artifact_id`,
+	},
+	{
+		table: "service_current_state",
+		query: `INSERT INTO service_current_state (
+			organization_id, service_name,
+			latest_event_seq, latest_event_type, latest_event_ts_ms,
+			latest_status, latest_artifact_id, latest_environment,
+			drift_count, failed_streak
+		)
+		WITH latest AS (
+			SELECT
+				ses.organization_id,
+				ses.service_name,
+				ses.latest_event_seq,
+				ses.latest_event_type,
+				ses.latest_event_ts_ms,
+				ses.latest_status,
+				ses.latest_artifact_id,
+				ses.environment,
+				row_number() OVER (PARTITION BY ses.organization_id, ses.service_name ORDER BY ses.latest_event_ts_ms DESC, ses.latest_event_seq DESC) AS rn
+			FROM service_env_state ses
+			WHERE ses.organization_id = ?1 AND ses.latest_event_seq <= ?2
+		), drift AS (
+			SELECT
+				organization_id,
+				service_name,
+				COUNT(DISTINCT NULLIF(latest_artifact_id, '')) AS drift_count,
+				SUM(CASE WHEN latest_status IN ('warning', 'out-of-sync') THEN 1 ELSE 0 END) AS failed_streak
+			FROM service_env_state
+			WHERE organization_id = ?1 AND latest_event_seq <= ?2
+			GROUP BY organization_id, service_name
+		)
+		SELECT
+			l.organization_id,
+			l.service_name,
+			l.latest_event_seq,
+			l.latest_event_type,
+			l.latest_event_ts_ms,
+			l.latest_status,
+			l.latest_artifact_id,
+			l.environment,
+			COALESCE(d.drift_count, 0),
+			COALESCE(d.failed_streak, 0)
+		FROM latest l
+		LEFT JOIN drift d ON d.organization_id = l.organization_id AND d.service_name = l.service_name
+		WHERE l.rn = 1`,
+	},
+	{
+		table: "service_delivery_stats_daily",
+		query: `INSERT INTO service_delivery_stats_daily (
+			organization_id, service_name, day_utc,
+			deploy_success_count, deploy_failure_count, rollback_count
+		)
+		SELECT
+			es.organization_id,
+			COALESCE(sia.service_name, es.service_name) AS service_name,
+			date(datetime(es.event_ts_ms / 1000, 'unixepoch')) AS day_utc,
+			SUM(CASE WHEN es.outcome = 'synced' THEN 1 ELSE 0 END),
+			SUM(CASE WHEN es.outcome = 'out-of-sync' THEN 1 ELSE 0 END),
+			SUM(CASE WHEN es.outcome = 'warning' THEN 1 ELSE 0 END)
+		FROM event_store es
+		LEFT JOIN service_identity_aliases sia
+			ON sia.organization_id = es.organization_id
+			AND sia.alias = es.service_name
+		WHERE es.organization_id = ?1 AND es.seq <= ?2 AND es.event_ts_ms >= ?3 AND es.subject_type = 'service'
+		GROUP BY es.organization_id, COALESCE(sia.service_name, es.service_name), day_utc`,
+	},
+	{
+		table: "service_change_links",
+		query: `INSERT INTO service_change_links (
+			organization_id, service_name, event_seq, event_ts_ms,
+			chain_id, environment, artifact_id, pipeline_run_id,
+			run_url, actor_name
+		)
+		SELECT
+			es.organization_id,
+			COALESCE(sia.service_name, es.service_name) AS service_name,
+			es.seq,
+			es.event_ts_ms,
+			es.chain_id,
+			COALESCE(ea.environment, es.environment) AS environment,
+			es.artifact_id,
+			es.pipeline_run_id,
+			es.run_url,
+			es.actor_name
+		FROM event_store es
+		LEFT JOIN service_identity_aliases sia
+			ON sia.organization_id = es.organization_id
+			AND sia.alias = es.service_name
+		LEFT JOIN environment_aliases ea
+			ON ea.organization_id = es.organization_id
+			AND ea.alias = es.environment
+		WHERE es.organization_id = ?1 AND es.seq <= ?2 AND es.event_ts_ms >= ?3 AND es.subject_type = 'service'`,
+	},
+	{
+		table: "service_pipeline_stats_daily",
+		query: `INSERT INTO service_pipeline_stats_daily (
+			organization_id, service_name, day_utc,
+			pipeline_started_count, pipeline_succeeded_count, pipeline_failed_count,
+			total_duration_seconds, avg_duration_seconds
+		)
+		WITH runs AS (
+			SELECT
+				es.organization_id,
+				COALESCE(
+					NULLIF(es.content_service, ''),
+					NULLIF(
+						CASE
+							WHEN instr(es.artifact_id, 'pkg:generic/') = 1
+							 AND instr(substr(es.artifact_id, 13), '@') > 0
+							THEN substr(es.artifact_id, 13, instr(substr(es.artifact_id, 13), '@') - 1)
+							ELSE ''
+						END,
+						''
+					),
+					CASE
+						WHEN instr(es.service_name, '/') > 0
+						THEN substr(es.service_name, 1, instr(es.service_name, '/') - 1)
+						ELSE es.service_name
+					END
+				) AS service_name,
+				date(datetime(es.event_ts_ms / 1000, 'unixepoch')) AS day_utc,
+				es.event_type,
+				CASE
+					WHEN es.event_type LIKE 'dev.cdevents.pipeline.run.succeeded.%' OR es.event_type LIKE 'dev.cdevents.pipeline.run.failed.%'
+					THEN COALESCE((
+						SELECT (es.event_ts_ms - started.event_ts_ms) / 1000
+						FROM event_store started
+						WHERE started.organization_id = es.organization_id
+							AND started.subject_id = es.subject_id
+							AND started.event_type LIKE 'dev.cdevents.pipeline.run.started.%'
+							AND started.event_ts_ms <= es.event_ts_ms
+						ORDER BY started.event_ts_ms DESC, started.seq DESC
+						LIMIT 1
+					), 0)
+					ELSE 0
+				END AS duration_seconds
+			FROM event_store es
+			WHERE es.organization_id = ?1 AND es.seq <= ?2 AND es.event_ts_ms >= ?3 AND es.subject_type = 'pipeline'
+		), resolved AS (
+			SELECT
+				runs.organization_id,
+				COALESCE(sia.service_name, runs.service_name) AS service_name,
+				runs.day_utc,
+				runs.event_type,
+				runs.duration_seconds
+			FROM runs
+			LEFT JOIN service_identity_aliases sia
+				ON sia.organization_id = runs.organization_id
+				AND sia.alias = runs.service_name
+		)
+		SELECT
+			organization_id,
+			service_name,
+			day_utc,
+			SUM(CASE WHEN event_type LIKE 'dev.cdevents.pipeline.run.started.%' THEN 1 ELSE 0 END),
+			SUM(CASE WHEN event_type LIKE 'dev.cdevents.pipeline.run.succeeded.%' THEN 1 ELSE 0 END),
+			SUM(CASE WHEN event_type LIKE 'dev.cdevents.pipeline.run.failed.%' THEN 1 ELSE 0 END),
+			SUM(duration_seconds),
+			0
+		FROM resolved
+		GROUP BY organization_id, service_name, day_utc`,
+	},
+	{
+		table: "service_deployment_durations",
+		query: `INSERT INTO service_deployment_durations (
+			organization_id, service_name, environment,
+			event_seq, event_ts_ms, duration_seconds, artifact_id
+		)
+		SELECT
+			es.organization_id,
+			COALESCE(sia.service_name, es.service_name) AS service_name,
+			COALESCE(ea.environment, es.environment),
+			es.seq,
+			es.event_ts_ms,
+			es.duration_seconds,
+			es.artifact_id
+		FROM event_store es
+		LEFT JOIN service_identity_aliases sia
+			ON sia.organization_id = es.organization_id
+			AND sia.alias = es.service_name
+		LEFT JOIN environment_aliases ea
+			ON ea.organization_id = es.organization_id
+			AND ea.alias = es.environment
+		WHERE es.organization_id = ?1 AND es.seq <= ?2 AND es.event_ts_ms >= ?3 AND es.subject_type = 'service'
+			AND es.event_type LIKE 'dev.cdevents.service.deployed.%'`,
+	},
+	{
+		table: "service_throughput_stats",
+		query: `INSERT INTO service_throughput_stats (
+			organization_id, service_name, week_start,
+			changes_count, deployments_count
+		)
+		WITH items AS (
+			SELECT
+				es.organization_id,
+				es.subject_type,
+				es.event_type,
+				es.event_ts_ms,
+				CASE
+					WHEN es.subject_type = 'service' THEN
+						es.service_name
+					ELSE COALESCE(
+						NULLIF(es.content_service, ''),
+						CASE
+							WHEN instr(es.artifact_id, 'pkg:generic/') = 1
+							 AND instr(substr(es.artifact_id, 13), '@') > 0
+							THEN substr(es.artifact_id, 13, instr(substr(es.artifact_id, 13), '@') - 1)
+							ELSE ''
+						END
+					)
+				END AS service_name
+			FROM event_store es
+			WHERE es.organization_id = ?1 AND es.seq <= ?2 AND es.event_ts_ms >= ?3
+				AND (es.subject_type = 'change' OR (es.subject_type = 'service' AND es.event_type LIKE 'dev.cdevents.service.deployed.%'))
+		), resolved AS (
+			SELECT
+				items.organization_id,
+				items.subject_type,
+				items.event_type,
+				items.event_ts_ms,
+				COALESCE(sia.service_name, items.service_name) AS service_name
+			FROM items
+			LEFT JOIN service_identity_aliases sia
+				ON sia.organization_id = items.organization_id
+				AND sia.alias = items.service_name
+		)
+		SELECT
+			organization_id,
+			service_name,
+			date(datetime((event_ts_ms / 1000) - (strftime('%w', datetime(event_ts_ms / 1000, 'unixepoch')) * 86400), 'unixepoch')) AS week_start,
+			SUM(CASE WHEN subject_type = 'change' THEN 1 ELSE 0 END),
+			SUM(CASE WHEN event_type LIKE 'dev.cdevents.service.deployed.%' THEN 1 ELSE 0 END)
+		FROM resolved
+		WHERE service_name != ''
+		GROUP BY organization_id, service_name, week_start`,
+	},
+}
+
+// rebuildIncidentProjections replays incident events in append order once
+// deployment projections are in place, so each incident links to the same
+// deployment it was linked to on ingest.
+func rebuildIncidentProjections(ctx context.Context, tx *sql.Tx, q *queries.Queries, organizationID, watermark, archivedBefore int64) error {
+	rows, err := tx.QueryContext(ctx, `SELECT organization_id, seq
+		FROM event_store
+		WHERE organization_id = ? AND seq <= ? AND event_ts_ms >= ? AND subject_type = 'incident'
+		ORDER BY seq`, organizationID, watermark, archivedBefore)
+	if err != nil {
+		return err
+	}
+	params := []queries.UpsertIncidentLinkFromEventSeqParams{}
+	for rows.Next() {
+		var item queries.UpsertIncidentLinkFromEventSeqParams
+		if err := rows.Scan(&item.OrganizationID, &item.Seq); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		params = append(params, item)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, item := range params {
+		if err := q.UpsertIncidentLinkFromEventSeq(ctx, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type deploymentHistoryEvent struct {
+	organizationID int64
+	serviceName    string
+	environment    string
+	eventType      string
+	eventTsMs      int64
+	seq            int64
+	artifactID     string
+}
+
+type environmentArtifact struct {
+	environment string
+	artifactID  string
+	eventTsMs   int64
+	seq         int64
+}
+
+// rebuildDeploymentHistoryProjections replays service events in append order
+// to rebuild projections that depend on the environment state at the time
+// each event arrived: redeployment stats and environment drift. The replay
+// starts from the environment state at the archive cutoff.
+func rebuildDeploymentHistoryProjections(ctx context.Context, tx *sql.Tx, q *queries.Queries, organizationID, watermark, archivedBefore int64) error {
+	rows, err := tx.QueryContext(ctx, `SELECT
+			es.organization_id,
+			COALESCE(sia.service_name, es.service_name) AS service_name,
+			COALESCE(ea.environment, es.environment) AS environment,
+			es.event_type,
+			es.event_ts_ms,
+			es.seq,
+			es.artifact_id
+		FROM event_store es
+		LEFT JOIN service_identity_aliases sia
+			ON sia.organization_id = es.organization_id
+			AND sia.alias = es.service_name
+		LEFT JOIN environment_aliases ea
+			ON ea.organization_id = es.organization_id
+			AND ea.alias = es.environment
+		WHERE es.organization_id = ? AND es.seq <= ? AND es.event_ts_ms >= ? AND es.subject_type = 'service'
+		ORDER BY es.seq`, organizationID, watermark, archivedBefore)
+	if err != nil {
+		return err
+	}
+	events := []deploymentHistoryEvent{}
+	for rows.Next() {
+		var event deploymentHistoryEvent
+		if err := rows.Scan(&event.organizationID, &event.serviceName, &event.environment, &event.eventType, &event.eventTsMs, &event.seq, &event.artifactID); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		events = append(events, event)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	type serviceKey struct {
+		organizationID int64
+		serviceName    string
+	}
+	states := map[serviceKey][]environmentArtifact{}
+	baseline, err := tx.QueryContext(ctx, `SELECT service_name, environment, latest_artifact_id, latest_event_ts_ms, latest_event_seq
+		FROM (
+			SELECT
+				COALESCE(sia.service_name, a.service_name) AS service_name,
+				COALESCE(ea.environment, a.environment) AS environment,
+				a.latest_artifact_id,
+				a.latest_event_ts_ms,
+				a.latest_event_seq,
+				row_number() OVER (
+					PARTITION BY COALESCE(sia.service_name, a.service_name), COALESCE(ea.environment, a.environment)
+					ORDER BY a.latest_event_ts_ms DESC, a.latest_event_seq DESC
+				) AS rn
+			FROM event_archive_env_state a
+			LEFT JOIN service_identity_aliases sia
+				ON sia.organization_id = a.organization_id
+				AND sia.alias = a.service_name
+			LEFT JOIN environment_aliases ea
+				ON ea.organization_id = a.organization_id
+				AND ea.alias = a.environment
+			WHERE a.organization_id = ?
+		)
+		WHERE rn = 1
+		ORDER BY service_name, environment`, organizationID)
+	if err != nil {
+		return err
+	}
+	for baseline.Next() {
+		key := serviceKey{organizationID: organizationID}
+		var env environmentArtifact
+		if err := baseline.Scan(&key.serviceName, &env.environment, &env.artifactID, &env.eventTsMs, &env.seq); err != nil {
+			_ = baseline.Close()
+			return err
+		}
+		states[key] = append(states[key], env)
+	}
+	if err := baseline.Close(); err != nil {
+		return err
+	}
+	if err := baseline.Err(); err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		key := serviceKey{organizationID: event.organizationID, serviceName: event.serviceName}
+		envs := states[key]
+		position := -1
+		for i := range envs {
+			if envs[i].environment == event.environment {
+				position = i
+				break
+			}
+		}
+
+		if strings.HasPrefix(event.eventType, "dev.cdevents.service.deployed.") {
+			sameArtifact := "0"
+			if event.artifactID != "" && position >= 0 && envs[position].artifactID == event.artifactID {
+				sameArtifact = "1"
+			}
+			if err := q.UpsertRedeploymentStats(ctx, queries.UpsertRedeploymentStatsParams{
+				OrganizationID: event.organizationID,
+				ServiceName:    event.serviceName,
+				DayUtc:         time.UnixMilli(event.eventTsMs).UTC().Format(time.DateOnly),
+				SameArtifact:   sameArtifact,
+			}); err != nil {
+				return err
+			}
+		}
+
+		next := environmentArtifact{environment: event.environment, artifactID: event.artifactID, eventTsMs: event.eventTsMs, seq: event.seq}
+		switch {
+		case position < 0:
+			envs = append(envs, next)
+		case event.eventTsMs > envs[position].eventTsMs ||
+			(event.eventTsMs == envs[position].eventTsMs && event.seq > envs[position].seq):
+			envs[position] = next
+		}
+		states[key] = envs
+
+		if err := insertEnvironmentDriftRows(ctx, q, event, envs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertEnvironmentDriftRows mirrors InsertEnvironmentDrift against an
+// in-memory snapshot of one service's environment state.
+func insertEnvironmentDriftRows(ctx context.Context, q *queries.Queries, event deploymentHistoryEvent, envs []environmentArtifact) error {
+	for _, from := range envs {
+		for _, to := range envs {
+			if from.environment == to.environment || from.artifactID == "" || to.artifactID == "" || from.artifactID == to.artifactID {
+				continue
+			}
+			if err := q.InsertEnvironmentDriftRow(ctx, queries.InsertEnvironmentDriftRowParams{
+				OrganizationID:  event.organizationID,
+				ServiceName:     event.serviceName,
+				EnvironmentFrom: from.environment,
+				EnvironmentTo:   to.environment,
+				ArtifactIDFrom:  from.artifactID,
+				ArtifactIDTo:    to.artifactID,
+				DriftDetectedAt: event.eventTsMs,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Database) countProjectionRows(ctx context.Context, table string, organizationID int64) (int64, error) {
+	query := "SELECT COUNT(*) FROM " + table
+	args := []interface{}{}
+	if organizationID > 0 {
+		query += " WHERE organization_id = ?"
+		args = append(args, organizationID)
+	}
+	row := c.db.QueryRowContext(ctx, query, args...)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
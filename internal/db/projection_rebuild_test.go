@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fr0stylo/ddash/internal/db/queries"
+)
+
+func TestRebuildServiceProjections_ScopedToOrganization(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+	other, err := database.CreateOrganization(ctx, queries.CreateOrganizationParams{
+		Name:          "org-other",
+		AuthToken:     "token-other",
+		WebhookSecret: "secret",
+		Enabled:       1,
+	})
+	if err != nil {
+		t.Fatalf("create other organization: %v", err)
+	}
+	appendAnalyticsFixture(t, ctx, database, org.ID)
+	appendAnalyticsFixture(t, ctx, database, other.ID)
+
+	// Leave the other organization's projections in a state a global rebuild
+	// would overwrite.
+	if _, err := database.db.ExecContext(ctx, "DELETE FROM service_current_state WHERE organization_id = ?", other.ID); err != nil {
+		t.Fatalf("clear other organization state: %v", err)
+	}
+
+	before := loadAnalyticsSnapshot(t, ctx, database, org.ID, "payments")
+	steps := []ProjectionRebuildStep{}
+	stats, err := database.RebuildServiceProjectionsWithProgress(ctx, org.ID, func(step ProjectionRebuildStep) {
+		steps = append(steps, step)
+	})
+	if err != nil {
+		t.Fatalf("rebuild projections: %v", err)
+	}
+	if after := loadAnalyticsSnapshot(t, ctx, database, org.ID, "payments"); after != before {
+		t.Fatalf("rebuild changed analytics:\nbefore=%+v\n after=%+v", before, after)
+	}
+	if stats.CurrentStateRows != 1 || stats.DeploymentDurationRows != 3 {
+		t.Fatalf("unexpected rebuild stats: %+v", stats)
+	}
+
+	if len(steps) == 0 || steps[len(steps)-1].Name != "swap" {
+		t.Fatalf("expected rebuild to finish with swap step: %+v", steps)
+	}
+	for i, step := range steps {
+		if step.OrganizationID != org.ID || step.Index != i+1 || step.Total != len(steps) {
+			t.Fatalf("unexpected progress step %d: %+v", i, step)
+		}
+	}
+
+	if _, err := database.GetServiceCurrentState(ctx, queries.GetServiceCurrentStateParams{OrganizationID: other.ID, ServiceName: "payments"}); err == nil {
+		t.Fatalf("expected other organization projections to be left alone")
+	}
+	if count, err := database.countProjectionRows(ctx, "service_deployment_durations", other.ID); err != nil || count != 3 {
+		t.Fatalf("unexpected other organization durations: count=%d err=%v", count, err)
+	}
+}
+
+func TestRebuildServiceProjections_AppliesEventsAppendedDuringRebuild(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+	appendEvent(t, ctx, database, org.ID, "deploy-1", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(0), "service/payments", "staging", "pkg:generic/payments@v1")
+
+	_, err := database.RebuildServiceProjectionsWithProgress(ctx, org.ID, func(step ProjectionRebuildStep) {
+		if step.Name == "swap" {
+			appendEvent(t, ctx, database, org.ID, "deploy-2", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(time.Minute), "service/payments", "production", "pkg:generic/payments@v2")
+		}
+	})
+	if err != nil {
+		t.Fatalf("rebuild projections: %v", err)
+	}
+
+	stats, err := database.GetServiceDeliveryStats30d(ctx, queries.GetServiceDeliveryStats30dParams{OrganizationID: org.ID, ServiceName: "payments"})
+	if err != nil {
+		t.Fatalf("get service delivery stats: %v", err)
+	}
+	if toInt64(stats.DeploySuccessCount) != 2 {
+		t.Fatalf("unexpected success count: got=%d want=2", toInt64(stats.DeploySuccessCount))
+	}
+	state, err := database.GetServiceCurrentState(ctx, queries.GetServiceCurrentStateParams{OrganizationID: org.ID, ServiceName: "payments"})
+	if err != nil {
+		t.Fatalf("get service current state: %v", err)
+	}
+	if state.DriftCount != 2 {
+		t.Fatalf("expected appended deployment in current state: %+v", state)
+	}
+}
@@ -0,0 +1,335 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fr0stylo/ddash/internal/db/queries"
+)
+
+const defaultProjectorBatchSize = 500
+
+// ProjectedEvent is one event_store row handed to a projector.
+type ProjectedEvent struct {
+	Seq            int64
+	OrganizationID int64
+	EventType      string
+	SubjectType    string
+	SubjectID      string
+	EventTsMs      int64
+}
+
+// Projector keeps one read model up to date from event_store. Each projector
+// has its own checkpoint: while it is caught up it is applied in the same
+// transaction that appends an event, otherwise CatchUpProjectors replays the
+// log for it.
+type Projector interface {
+	// Name identifies the projector checkpoint.
+	Name() string
+	// Subjects lists the event subject types the projector consumes.
+	Subjects() []string
+	// Apply projects one event. It must only read event_store and the
+	// projector's own tables, so it can be replayed on its own.
+	Apply(ctx context.Context, q *queries.Queries, event ProjectedEvent) error
+}
+
+// ProjectorStatus reports how far a projector has applied event_store.
+type ProjectorStatus struct {
+	Name      string
+	LastSeq   int64
+	HeadSeq   int64
+	UpdatedAt time.Time
+}
+
+// Lag returns the number of event_store sequence numbers not yet applied.
+func (s ProjectorStatus) Lag() int64 {
+	if s.HeadSeq <= s.LastSeq {
+		return 0
+	}
+	return s.HeadSeq - s.LastSeq
+}
+
+// projectorFunc adapts a function to Projector.
+type projectorFunc struct {
+	name     string
+	subjects []string
+	apply    func(ctx context.Context, q *queries.Queries, event ProjectedEvent) error
+}
+
+func (p projectorFunc) Name() string       { return p.name }
+func (p projectorFunc) Subjects() []string { return p.subjects }
+func (p projectorFunc) Apply(ctx context.Context, q *queries.Queries, event ProjectedEvent) error {
+	return p.apply(ctx, q, event)
+}
+
+// builtinProjectors maintain the tables covered by RebuildServiceProjections.
+// Their checkpoints are seeded by the projector_checkpoints migration.
+var builtinProjectors = []Projector{
+	projectorFunc{name: "service_state", subjects: []string{"service", "incident"}, apply: applyServiceStateProjection},
+	projectorFunc{name: "throughput", subjects: []string{"service", "change"}, apply: applyThroughputProjection},
+	projectorFunc{name: "pipeline_stats", subjects: []string{"pipeline"}, apply: applyPipelineStatsProjection},
+}
+
+// RegisterProjector adds a read model maintained from event_store. A projector
+// without a checkpoint starts at the beginning of the log and is caught up by
+// CatchUpProjectors. Register projectors before the database is shared.
+func (c *Database) RegisterProjector(projector Projector) error {
+	name := strings.TrimSpace(projector.Name())
+	if name == "" {
+		return errors.New("projector name is required")
+	}
+	for _, existing := range c.projectors {
+		if existing.Name() == name {
+			return fmt.Errorf("projector already registered: %s", name)
+		}
+	}
+	c.projectors = append(c.projectors, projector)
+	return nil
+}
+
+// ListProjectorStatus returns the checkpoint of every registered projector.
+func (c *Database) ListProjectorStatus(ctx context.Context) ([]ProjectorStatus, error) {
+	head, err := c.GetEventStoreHeadSeq(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := c.ListProjectorCheckpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	checkpoints := make(map[string]queries.ProjectorCheckpoint, len(rows))
+	for _, row := range rows {
+		checkpoints[row.Name] = row
+	}
+	out := make([]ProjectorStatus, 0, len(c.projectors))
+	for _, projector := range c.projectors {
+		status := ProjectorStatus{Name: projector.Name(), HeadSeq: head}
+		if row, ok := checkpoints[projector.Name()]; ok {
+			status.LastSeq = row.LastSeq
+			status.UpdatedAt = time.UnixMilli(row.UpdatedAt).UTC()
+		}
+		out = append(out, status)
+	}
+	return out, nil
+}
+
+// CatchUpProjectors applies up to batchSize events to every lagging projector
+// and reports whether any projector is still behind.
+func (c *Database) CatchUpProjectors(ctx context.Context, batchSize int64) (bool, error) {
+	if batchSize <= 0 {
+		batchSize = defaultProjectorBatchSize
+	}
+	lagging := false
+	for _, projector := range c.projectors {
+		behind, err := c.catchUpProjector(ctx, projector, batchSize)
+		if err != nil {
+			return false, fmt.Errorf("catch up projector %s: %w", projector.Name(), err)
+		}
+		lagging = lagging || behind
+	}
+	return lagging, nil
+}
+
+// catchUpProjector applies one batch of events to a projector. The checkpoint
+// insert runs first so the transaction holds the write lock before it reads
+// the head of the log, which keeps appends from slipping past the checkpoint.
+func (c *Database) catchUpProjector(ctx context.Context, projector Projector, batchSize int64) (bool, error) {
+	behind := false
+	err := c.WithTx(ctx, func(q *queries.Queries) error {
+		now := time.Now().UTC().UnixMilli()
+		if err := q.EnsureProjectorCheckpoint(ctx, queries.EnsureProjectorCheckpointParams{Name: projector.Name(), UpdatedAt: now}); err != nil {
+			return err
+		}
+		checkpoint, err := q.GetProjectorCheckpoint(ctx, projector.Name())
+		if err != nil {
+			return err
+		}
+		head, err := q.GetEventStoreHeadSeq(ctx)
+		if err != nil {
+			return err
+		}
+		if checkpoint >= head {
+			return nil
+		}
+		events, err := q.ListProjectorEvents(ctx, queries.ListProjectorEventsParams{
+			AfterSeq:     checkpoint,
+			HeadSeq:      head,
+			SubjectTypes: projector.Subjects(),
+			Limit:        batchSize,
+		})
+		if err != nil {
+			return err
+		}
+		next := head
+		if int64(len(events)) == batchSize {
+			next = events[len(events)-1].Seq
+			behind = next < head
+		}
+		for _, row := range events {
+			if err := projector.Apply(ctx, q, ProjectedEvent(row)); err != nil {
+				return err
+			}
+		}
+		return q.SetProjectorCheckpoint(ctx, queries.SetProjectorCheckpointParams{LastSeq: next, UpdatedAt: now, Name: projector.Name()})
+	})
+	return behind, err
+}
+
+// liveProjectors applies caught-up projectors to the events appended by one
+// transaction and advances their checkpoints when the transaction is done.
+type liveProjectors struct {
+	registered []Projector
+	live       []Projector
+	loaded     bool
+	lastSeq    int64
+}
+
+func newLiveProjectors(registered []Projector) *liveProjectors {
+	return &liveProjectors{registered: registered}
+}
+
+// apply runs after the event was inserted, so the transaction already holds
+// the write lock when it reads the checkpoints.
+func (l *liveProjectors) apply(ctx context.Context, q *queries.Queries, event ProjectedEvent) error {
+	if !l.loaded {
+		names, err := q.ListLiveProjectorNames(ctx, event.Seq)
+		if err != nil {
+			return err
+		}
+		for _, projector := range l.registered {
+			if slices.Contains(names, projector.Name()) {
+				l.live = append(l.live, projector)
+			}
+		}
+		l.loaded = true
+	}
+	for _, projector := range l.live {
+		if !projectorConsumes(projector, event) {
+			continue
+		}
+		if err := projector.Apply(ctx, q, event); err != nil {
+			return err
+		}
+	}
+	l.lastSeq = event.Seq
+	return nil
+}
+
+func (l *liveProjectors) commit(ctx context.Context, q *queries.Queries) error {
+	if l.lastSeq == 0 || len(l.live) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(l.live))
+	for _, projector := range l.live {
+		names = append(names, projector.Name())
+	}
+	return q.AdvanceProjectorCheckpoints(ctx, queries.AdvanceProjectorCheckpointsParams{
+		LastSeq:   l.lastSeq,
+		UpdatedAt: time.Now().UTC().UnixMilli(),
+		Names:     names,
+	})
+}
+
+func projectorConsumes(projector Projector, event ProjectedEvent) bool {
+	subject := strings.TrimSpace(strings.ToLower(event.SubjectType))
+	return slices.Contains(projector.Subjects(), subject)
+}
+
+func applyServiceStateProjection(ctx context.Context, q *queries.Queries, event ProjectedEvent) error {
+	if strings.EqualFold(strings.TrimSpace(event.SubjectType), "incident") {
+		return q.UpsertIncidentLinkFromEventSeq(ctx, queries.UpsertIncidentLinkFromEventSeqParams{
+			OrganizationID: event.OrganizationID,
+			Seq:            event.Seq,
+		})
+	}
+	return applyServiceProjections(ctx, q, event)
+}
+
+func applyThroughputProjection(ctx context.Context, q *queries.Queries, event ProjectedEvent) error {
+	if strings.EqualFold(strings.TrimSpace(event.SubjectType), "service") && !strings.HasPrefix(event.EventType, "dev.cdevents.service.deployed.") {
+		return nil
+	}
+	return q.UpsertThroughputStats(ctx, queries.UpsertThroughputStatsParams{
+		OrganizationID: event.OrganizationID,
+		Seq:            event.Seq,
+	})
+}
+
+func applyPipelineStatsProjection(ctx context.Context, q *queries.Queries, event ProjectedEvent) error {
+	return q.UpsertPipelineStatsFromEvent(ctx, queries.UpsertPipelineStatsFromEventParams{
+		OrganizationID: event.OrganizationID,
+		Seq:            event.Seq,
+	})
+}
+
+// applyServiceProjections updates state and analytics projections for one
+// service event.
+func applyServiceProjections(ctx context.Context, q *queries.Queries, event ProjectedEvent) error {
+	// Redeploy detection compares against the environment state before this
+	// event is applied to it.
+	redeploy, err := q.GetRedeploymentCheckFromEventSeq(ctx, queries.GetRedeploymentCheckFromEventSeqParams{
+		OrganizationID: event.OrganizationID,
+		Seq:            event.Seq,
+	})
+	isDeploy := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if err := q.UpsertServiceEnvStateFromEventSeq(ctx, queries.UpsertServiceEnvStateFromEventSeqParams{
+		OrganizationID: event.OrganizationID,
+		Seq:            event.Seq,
+	}); err != nil {
+		return err
+	}
+	if err := q.UpsertServiceDeliveryStatsDailyFromEventSeq(ctx, queries.UpsertServiceDeliveryStatsDailyFromEventSeqParams{
+		OrganizationID: event.OrganizationID,
+		Seq:            event.Seq,
+	}); err != nil {
+		return err
+	}
+	if err := q.UpsertServiceChangeLinkFromEventSeq(ctx, queries.UpsertServiceChangeLinkFromEventSeqParams{
+		OrganizationID: event.OrganizationID,
+		Seq:            event.Seq,
+	}); err != nil {
+		return err
+	}
+
+	if isDeploy {
+		if err := q.InsertDeploymentDuration(ctx, queries.InsertDeploymentDurationParams{
+			OrganizationID: event.OrganizationID,
+			Seq:            event.Seq,
+		}); err != nil {
+			return err
+		}
+		if err := q.UpsertRedeploymentStats(ctx, queries.UpsertRedeploymentStatsParams{
+			OrganizationID: event.OrganizationID,
+			ServiceName:    redeploy.ServiceName,
+			DayUtc:         redeploy.DayUtc,
+			SameArtifact:   redeploy.SameArtifact,
+		}); err != nil {
+			return err
+		}
+	}
+
+	serviceName, err := resolveServiceName(ctx, q, event.OrganizationID, serviceNameFromSubjectID(event.SubjectID))
+	if err != nil || serviceName == "" {
+		return err
+	}
+	if err := q.UpsertServiceCurrentStateByService(ctx, queries.UpsertServiceCurrentStateByServiceParams{
+		OrganizationID: event.OrganizationID,
+		ServiceName:    serviceName,
+	}); err != nil {
+		return err
+	}
+	return q.InsertEnvironmentDrift(ctx, queries.InsertEnvironmentDriftParams{
+		OrganizationID: event.OrganizationID,
+		ServiceName:    serviceName,
+		DetectedAt:     event.EventTsMs,
+	})
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/fr0stylo/ddash/internal/db/queries"
+)
+
+type recordingProjector struct {
+	seqs []int64
+}
+
+func (p *recordingProjector) Name() string       { return "recording" }
+func (p *recordingProjector) Subjects() []string { return []string{"service"} }
+func (p *recordingProjector) Apply(_ context.Context, _ *queries.Queries, event ProjectedEvent) error {
+	p.seqs = append(p.seqs, event.Seq)
+	return nil
+}
+
+func TestCatchUpProjectors_ReplaysLogForNewProjector(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+	for i := range 3 {
+		appendEvent(t, ctx, database, org.ID, fmt.Sprintf("deploy-%d", i), "dev.cdevents.service.deployed.0.3.0", recentTimestamp(0), "service/payments", "staging", "pkg:generic/payments@v1")
+	}
+	appendSubjectEvent(t, ctx, database, org.ID, "pipeline-1", "dev.cdevents.pipelinerun.finished.0.3.0", recentTimestamp(0), "pipeline", "pipeline/build", "", "")
+
+	projector := &recordingProjector{}
+	if err := database.RegisterProjector(projector); err != nil {
+		t.Fatalf("register projector: %v", err)
+	}
+	if err := database.RegisterProjector(projector); err == nil {
+		t.Fatalf("expected duplicate projector to be rejected")
+	}
+
+	// A lagging projector is not applied inline; catch-up replays it in order.
+	appendEvent(t, ctx, database, org.ID, "deploy-3", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(0), "service/payments", "production", "pkg:generic/payments@v1")
+	if len(projector.seqs) != 0 {
+		t.Fatalf("expected lagging projector to be skipped inline: %v", projector.seqs)
+	}
+	lagging, err := database.CatchUpProjectors(ctx, 2)
+	if err != nil {
+		t.Fatalf("catch up projectors: %v", err)
+	}
+	if !lagging || len(projector.seqs) != 2 {
+		t.Fatalf("expected one partial batch: lagging=%t seqs=%v", lagging, projector.seqs)
+	}
+	for lagging {
+		if lagging, err = database.CatchUpProjectors(ctx, 2); err != nil {
+			t.Fatalf("catch up projectors: %v", err)
+		}
+	}
+	if len(projector.seqs) != 4 || !slices.IsSorted(projector.seqs) {
+		t.Fatalf("expected every service event once in order: %v", projector.seqs)
+	}
+
+	// Once caught up the projector is applied with each append.
+	appendEvent(t, ctx, database, org.ID, "deploy-4", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(0), "service/payments", "production", "pkg:generic/payments@v2")
+	if len(projector.seqs) != 5 {
+		t.Fatalf("expected live projector to apply appended event: %v", projector.seqs)
+	}
+
+	statuses, err := database.ListProjectorStatus(ctx)
+	if err != nil {
+		t.Fatalf("list projector status: %v", err)
+	}
+	names := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		names = append(names, status.Name)
+		if status.Lag() != 0 || status.HeadSeq != projector.seqs[4] {
+			t.Fatalf("expected projector to be caught up: %+v", status)
+		}
+	}
+	if !slices.Equal(names, []string{"service_state", "throughput", "pipeline_stats", "recording"}) {
+		t.Fatalf("unexpected projectors: %v", names)
+	}
+}
@@ -10,12 +10,27 @@ FROM organizations
 WHERE auth_token = ?
 LIMIT 1;
 
+-- name: GetOrganizationByIngestCredentialToken :one
+SELECT o.*
+FROM organization_ingest_credentials c
+JOIN organizations o ON o.id = c.organization_id
+WHERE c.auth_token = sqlc.arg('auth_token')
+  AND c.revoked_at IS NULL
+  AND (c.expires_at IS NULL OR c.expires_at > sqlc.arg('now'))
+LIMIT 1;
+
 -- name: GetOrganizationByID :one
 SELECT *
 FROM organizations
 WHERE id = ?
 LIMIT 1;
 
+-- name: GetOrganizationByName :one
+SELECT *
+FROM organizations
+WHERE name = ?
+LIMIT 1;
+
 -- name: GetOrganizationByJoinCode :one
 SELECT *
 FROM organizations
@@ -130,6 +145,11 @@ ON CONFLICT(installation_id) DO UPDATE SET
   enabled = excluded.enabled,
   updated_at = CURRENT_TIMESTAMP;
 
+-- name: GetGitHubInstallationMappingOrganizationID :one
+SELECT organization_id
+FROM github_installation_mappings
+WHERE installation_id = sqlc.arg('installation_id');
+
 -- name: ListGitHubInstallationMappings :many
 SELECT
   installation_id,
@@ -177,6 +197,22 @@ ON CONFLICT(project_id) DO UPDATE SET
   enabled = excluded.enabled,
   updated_at = CURRENT_TIMESTAMP;
 
+-- name: GetGitLabProjectMappingOrganizationID :one
+SELECT organization_id
+FROM gitlab_project_mappings
+WHERE project_id = sqlc.arg('project_id');
+
+-- name: ListGitLabProjectMappings :many
+SELECT
+  project_id,
+  organization_id,
+  project_path,
+  default_environment,
+  enabled
+FROM gitlab_project_mappings
+WHERE organization_id = sqlc.arg('organization_id')
+ORDER BY project_id ASC;
+
 -- name: GetOrganizationByGitLabProjectID :one
 SELECT o.*
 FROM organizations o
@@ -308,11 +344,14 @@ ON CONFLICT(organization_id, service_name, label) DO UPDATE SET
   updated_at = CURRENT_TIMESTAMP;
 
 -- name: ListDistinctServiceEnvironmentsFromEvents :many
-SELECT DISTINCT COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') AS environment
+SELECT DISTINCT COALESCE(ea.environment, es.environment) AS environment
 FROM event_store es
+LEFT JOIN environment_aliases ea
+  ON ea.organization_id = es.organization_id
+  AND ea.alias = es.environment
 WHERE es.organization_id = sqlc.arg('organization_id')
   AND es.subject_type = 'service'
-ORDER BY environment;
+ORDER BY COALESCE(ea.environment, es.environment);
 
 -- name: UpdateOrganizationSecrets :exec
 UPDATE organizations
@@ -338,6 +377,12 @@ FROM organization_preferences
 WHERE organization_id = ?
 ORDER BY preference_key;
 
+-- name: GetOrganizationPreference :one
+SELECT preference_value
+FROM organization_preferences
+WHERE organization_id = ?
+  AND preference_key = ?;
+
 -- name: UpsertOrganizationPreference :exec
 INSERT INTO organization_preferences (organization_id, preference_key, preference_value)
 VALUES (?, ?, ?)
@@ -345,6 +390,84 @@ ON CONFLICT(organization_id, preference_key) DO UPDATE SET
   preference_value = excluded.preference_value,
   updated_at = CURRENT_TIMESTAMP;
 
+-- name: ListOrganizationEventPolicies :many
+SELECT subject, mode
+FROM organization_event_policies
+WHERE organization_id = ?
+ORDER BY subject;
+
+-- name: DeleteOrganizationEventPolicies :exec
+DELETE FROM organization_event_policies
+WHERE organization_id = ?;
+
+-- name: UpsertOrganizationEventPolicy :exec
+INSERT INTO organization_event_policies (organization_id, subject, mode)
+VALUES (?, ?, ?)
+ON CONFLICT(organization_id, subject) DO UPDATE SET
+  mode = excluded.mode,
+  updated_at = CURRENT_TIMESTAMP;
+
+-- name: InsertIngestDeadLetter :exec
+INSERT INTO ingest_dead_letters (
+  organization_id,
+  reason,
+  detail,
+  headers_json,
+  body,
+  body_size,
+  truncated,
+  received_at
+)
+VALUES (
+  sqlc.arg('organization_id'),
+  sqlc.arg('reason'),
+  sqlc.arg('detail'),
+  sqlc.arg('headers_json'),
+  sqlc.arg('body'),
+  sqlc.arg('body_size'),
+  sqlc.arg('truncated'),
+  sqlc.arg('received_at')
+);
+
+-- name: PruneIngestDeadLetters :exec
+DELETE FROM ingest_dead_letters
+WHERE ingest_dead_letters.organization_id = sqlc.arg('organization_id')
+  AND (
+    ingest_dead_letters.received_at < sqlc.arg('received_before')
+    OR ingest_dead_letters.id NOT IN (
+      SELECT kept.id
+      FROM ingest_dead_letters kept
+      WHERE kept.organization_id = sqlc.arg('organization_id')
+      ORDER BY kept.received_at DESC, kept.id DESC
+      LIMIT sqlc.arg('keep_count')
+    )
+  );
+
+-- name: ListIngestDeadLetters :many
+SELECT id, organization_id, reason, detail, headers_json, body, body_size, truncated, received_at, replayed_at, replay_result
+FROM ingest_dead_letters
+WHERE organization_id = sqlc.arg('organization_id')
+ORDER BY received_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: GetIngestDeadLetter :one
+SELECT id, organization_id, reason, detail, headers_json, body, body_size, truncated, received_at, replayed_at, replay_result
+FROM ingest_dead_letters
+WHERE organization_id = sqlc.arg('organization_id')
+  AND id = sqlc.arg('id');
+
+-- name: MarkIngestDeadLetterReplayed :exec
+UPDATE ingest_dead_letters
+SET replayed_at = sqlc.arg('replayed_at'),
+    replay_result = sqlc.arg('replay_result')
+WHERE organization_id = sqlc.arg('organization_id')
+  AND id = sqlc.arg('id');
+
+-- name: DeleteIngestDeadLetter :exec
+DELETE FROM ingest_dead_letters
+WHERE organization_id = sqlc.arg('organization_id')
+  AND id = sqlc.arg('id');
+
 -- name: AppendEventStore :one
 INSERT INTO event_store (
   organization_id,
@@ -357,7 +480,20 @@ INSERT INTO event_store (
   subject_source,
   subject_type,
   chain_id,
-  raw_event_json
+  raw_event_json,
+  raw_event_compressed,
+  service_name,
+  environment,
+  artifact_id,
+  outcome,
+  actor_name,
+  environment_id,
+  content_service,
+  content_service_id,
+  pipeline_run_id,
+  run_url,
+  link_url,
+  duration_seconds
 )
 VALUES (
   sqlc.arg('organization_id'),
@@ -370,7 +506,43 @@ VALUES (
   sqlc.narg('subject_source'),
   sqlc.arg('subject_type'),
   sqlc.narg('chain_id'),
-  sqlc.arg('raw_event_json')
+  '',
+  ddash_event_compress(CAST(sqlc.arg('raw_event_json') AS TEXT)),
+  CASE
+    WHEN instr(sqlc.arg('subject_id'), '/') > 0 THEN substr(sqlc.arg('subject_id'), instr(sqlc.arg('subject_id'), '/') + 1)
+    ELSE sqlc.arg('subject_id')
+  END,
+  COALESCE(NULLIF(json_extract(sqlc.arg('raw_event_json'), '$.subject.content.environment.id'), ''), 'unknown'),
+  COALESCE(json_extract(sqlc.arg('raw_event_json'), '$.subject.content.artifactId'), ''),
+  CASE
+    WHEN sqlc.arg('subject_type') <> 'service' THEN ''
+    WHEN sqlc.arg('event_type') LIKE 'dev.cdevents.service.deployed.%' THEN 'synced'
+    WHEN sqlc.arg('event_type') LIKE 'dev.cdevents.service.upgraded.%' THEN 'synced'
+    WHEN sqlc.arg('event_type') LIKE 'dev.cdevents.service.published.%' THEN 'synced'
+    WHEN sqlc.arg('event_type') LIKE 'dev.cdevents.service.rolledback.%' THEN 'warning'
+    WHEN sqlc.arg('event_type') LIKE 'dev.cdevents.service.removed.%' THEN 'out-of-sync'
+    ELSE 'unknown'
+  END,
+  COALESCE(json_extract(sqlc.arg('raw_event_json'), '$.subject.content.actor.name'), ''),
+  COALESCE(json_extract(sqlc.arg('raw_event_json'), '$.subject.content.environment.id'), ''),
+  CASE
+    WHEN json_type(sqlc.arg('raw_event_json'), '$.subject.content.service') = 'text'
+    THEN json_extract(sqlc.arg('raw_event_json'), '$.subject.content.service')
+    ELSE ''
+  END,
+  CASE
+    WHEN json_type(sqlc.arg('raw_event_json'), '$.subject.content.service') = 'object'
+    THEN COALESCE(json_extract(sqlc.arg('raw_event_json'), '$.subject.content.service.id'), '')
+    ELSE ''
+  END,
+  COALESCE(json_extract(sqlc.arg('raw_event_json'), '$.subject.content.pipeline.runId'), ''),
+  COALESCE(json_extract(sqlc.arg('raw_event_json'), '$.subject.content.pipeline.url'), ''),
+  COALESCE(
+    NULLIF(json_extract(sqlc.arg('raw_event_json'), '$.subject.content.url'), ''),
+    json_extract(sqlc.arg('raw_event_json'), '$.subject.content.pipeline.url'),
+    ''
+  ),
+  COALESCE(json_extract(sqlc.arg('raw_event_json'), '$.subject.content.durationSeconds'), 0)
 )
 ON CONFLICT(organization_id, event_source, event_id) DO NOTHING
 RETURNING seq;
@@ -388,24 +560,20 @@ INSERT INTO service_env_state (
 )
 SELECT
   es.organization_id,
-  CASE
-    WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-    ELSE es.subject_id
-  END AS service_name,
-  COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') AS environment,
+  COALESCE(sia.service_name, es.service_name) AS service_name,
+  COALESCE(ea.environment, es.environment) AS environment,
   es.seq,
   es.event_type,
   es.event_ts_ms,
-  CASE
-    WHEN es.event_type LIKE 'dev.cdevents.service.deployed.%' THEN 'synced'
-    WHEN es.event_type LIKE 'dev.cdevents.service.upgraded.%' THEN 'synced'
-    WHEN es.event_type LIKE 'dev.cdevents.service.published.%' THEN 'synced'
-    WHEN es.event_type LIKE 'dev.cdevents.service.rolledback.%' THEN 'warning'
-    WHEN es.event_type LIKE 'dev.cdevents.service.removed.%' THEN 'out-of-sync'
-    ELSE 'unknown'
-  END AS latest_status,
-  COALESCE(json_extract(es.raw_event_json, '$.subject.content.artifactId'), '') AS latest_artifact_id
+  es.outcome AS latest_status,
+  es.artifact_id AS latest_artifact_id
 FROM event_store es
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
+LEFT JOIN environment_aliases ea
+  ON ea.organization_id = es.organization_id
+  AND ea.alias = es.environment
 WHERE es.organization_id = sqlc.arg('organization_id')
   AND es.seq = sqlc.arg('seq')
   AND es.subject_type = 'service'
@@ -431,26 +599,15 @@ INSERT INTO service_delivery_stats_daily (
 )
 SELECT
   es.organization_id,
-  CASE
-    WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-    ELSE es.subject_id
-  END AS service_name,
+  COALESCE(sia.service_name, es.service_name) AS service_name,
   date(datetime(es.event_ts_ms / 1000, 'unixepoch')) AS day_utc,
-  CASE
-    WHEN es.event_type LIKE 'dev.cdevents.service.deployed.%'
-      OR es.event_type LIKE 'dev.cdevents.service.upgraded.%'
-      OR es.event_type LIKE 'dev.cdevents.service.published.%' THEN 1
-    ELSE 0
-  END AS deploy_success_count,
-  CASE
-    WHEN es.event_type LIKE 'dev.cdevents.service.removed.%' THEN 1
-    ELSE 0
-  END AS deploy_failure_count,
-  CASE
-    WHEN es.event_type LIKE 'dev.cdevents.service.rolledback.%' THEN 1
-    ELSE 0
-  END AS rollback_count
+  CASE WHEN es.outcome = 'synced' THEN 1 ELSE 0 END AS deploy_success_count,
+  CASE WHEN es.outcome = 'out-of-sync' THEN 1 ELSE 0 END AS deploy_failure_count,
+  CASE WHEN es.outcome = 'warning' THEN 1 ELSE 0 END AS rollback_count
 FROM event_store es
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
 WHERE es.organization_id = sqlc.arg('organization_id')
   AND es.seq = sqlc.arg('seq')
   AND es.subject_type = 'service'
@@ -475,19 +632,22 @@ INSERT INTO service_change_links (
 )
 SELECT
   es.organization_id,
-  CASE
-    WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-    ELSE es.subject_id
-  END AS service_name,
+  COALESCE(sia.service_name, es.service_name) AS service_name,
   es.seq,
   es.event_ts_ms,
   es.chain_id,
-  COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') AS environment,
-  COALESCE(json_extract(es.raw_event_json, '$.subject.content.artifactId'), '') AS artifact_id,
-  COALESCE(json_extract(es.raw_event_json, '$.subject.content.pipeline.runId'), '') AS pipeline_run_id,
-  COALESCE(json_extract(es.raw_event_json, '$.subject.content.pipeline.url'), '') AS run_url,
-  COALESCE(json_extract(es.raw_event_json, '$.subject.content.actor.name'), '') AS actor_name
+  COALESCE(ea.environment, es.environment) AS environment,
+  es.artifact_id,
+  es.pipeline_run_id,
+  es.run_url,
+  es.actor_name
 FROM event_store es
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
+LEFT JOIN environment_aliases ea
+  ON ea.organization_id = es.organization_id
+  AND ea.alias = es.environment
 WHERE es.organization_id = sqlc.arg('organization_id')
   AND es.seq = sqlc.arg('seq')
   AND es.subject_type = 'service'
@@ -550,21 +710,17 @@ WITH service_events AS (
     es.event_type,
     es.event_timestamp,
     es.event_ts_ms,
-    CASE
-      WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-      ELSE es.subject_id
-    END AS service_name,
-    COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') AS environment,
-    COALESCE(json_extract(es.raw_event_json, '$.subject.content.artifactId'), '') AS artifact_id,
-    CASE
-      WHEN es.event_type LIKE 'dev.cdevents.service.deployed.%' THEN 'synced'
-      WHEN es.event_type LIKE 'dev.cdevents.service.upgraded.%' THEN 'synced'
-      WHEN es.event_type LIKE 'dev.cdevents.service.published.%' THEN 'synced'
-      WHEN es.event_type LIKE 'dev.cdevents.service.rolledback.%' THEN 'warning'
-      WHEN es.event_type LIKE 'dev.cdevents.service.removed.%' THEN 'out-of-sync'
-      ELSE 'unknown'
-    END AS status
+    COALESCE(sia.service_name, es.service_name) AS service_name,
+    COALESCE(ea.environment, es.environment) AS environment,
+    es.artifact_id,
+    es.outcome AS status
   FROM event_store es
+  LEFT JOIN service_identity_aliases sia
+    ON sia.organization_id = es.organization_id
+    AND sia.alias = es.service_name
+  LEFT JOIN environment_aliases ea
+    ON ea.organization_id = es.organization_id
+    AND ea.alias = es.environment
   WHERE es.subject_type = 'service'
     AND es.organization_id = sqlc.arg('organization_id')
 ), ranked AS (
@@ -597,24 +753,20 @@ WITH service_events AS (
     es.event_type,
     es.event_timestamp,
     es.event_ts_ms,
-    CASE
-      WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-      ELSE es.subject_id
-    END AS service_name,
-    COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') AS environment,
-    COALESCE(json_extract(es.raw_event_json, '$.subject.content.artifactId'), '') AS artifact_id,
-    CASE
-      WHEN es.event_type LIKE 'dev.cdevents.service.deployed.%' THEN 'synced'
-      WHEN es.event_type LIKE 'dev.cdevents.service.upgraded.%' THEN 'synced'
-      WHEN es.event_type LIKE 'dev.cdevents.service.published.%' THEN 'synced'
-      WHEN es.event_type LIKE 'dev.cdevents.service.rolledback.%' THEN 'warning'
-      WHEN es.event_type LIKE 'dev.cdevents.service.removed.%' THEN 'out-of-sync'
-      ELSE 'unknown'
-    END AS status
+    COALESCE(sia.service_name, es.service_name) AS service_name,
+    COALESCE(ea.environment, es.environment) AS environment,
+    es.artifact_id,
+    es.outcome AS status
   FROM event_store es
+  LEFT JOIN service_identity_aliases sia
+    ON sia.organization_id = es.organization_id
+    AND sia.alias = es.service_name
+  LEFT JOIN environment_aliases ea
+    ON ea.organization_id = es.organization_id
+    AND ea.alias = es.environment
   WHERE es.subject_type = 'service'
     AND es.organization_id = sqlc.arg('organization_id')
-    AND COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') = sqlc.arg('env')
+    AND COALESCE(ea.environment, es.environment) = sqlc.arg('env')
 ), ranked AS (
   SELECT
     service_name,
@@ -639,41 +791,82 @@ WHERE rn = 1
 ORDER BY service_name, environment;
 
 -- name: ListDeploymentsFromEvents :many
+-- One page of deployments, newest first. A page continues below the
+-- (before_ts_ms, before_seq) position of the previous page's last row;
+-- before_seq = 0 starts at the newest deployment.
 SELECT
+  es.seq,
+  es.event_ts_ms,
   es.event_timestamp AS deployed_at,
-  CASE
-    WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-    ELSE es.subject_id
-  END AS service,
-  COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') AS environment,
-  CASE
-    WHEN es.event_type LIKE 'dev.cdevents.service.deployed.%' THEN 'success'
-    WHEN es.event_type LIKE 'dev.cdevents.service.upgraded.%' THEN 'success'
-    WHEN es.event_type LIKE 'dev.cdevents.service.published.%' THEN 'success'
-    WHEN es.event_type LIKE 'dev.cdevents.service.rolledback.%' THEN 'error'
-    WHEN es.event_type LIKE 'dev.cdevents.service.removed.%' THEN 'error'
+  COALESCE(sia.service_name, es.service_name) AS service,
+  COALESCE(ea.environment, es.environment) AS environment,
+  CAST(CASE es.outcome
+    WHEN 'synced' THEN 'success'
+    WHEN 'warning' THEN 'error'
+    WHEN 'out-of-sync' THEN 'error'
     ELSE 'queued'
-  END AS status
+  END AS TEXT) AS status
 FROM event_store es
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
+LEFT JOIN environment_aliases ea
+  ON ea.organization_id = es.organization_id
+  AND ea.alias = es.environment
 WHERE es.subject_type = 'service'
   AND es.organization_id = sqlc.arg('organization_id')
-  AND (sqlc.arg('env') = '' OR sqlc.arg('env') = 'all' OR json_extract(es.raw_event_json, '$.subject.content.environment.id') = sqlc.arg('env'))
-  AND (sqlc.arg('service') = '' OR sqlc.arg('service') = 'all' OR es.subject_id = sqlc.arg('service') OR substr(es.subject_id, instr(es.subject_id, '/') + 1) = sqlc.arg('service'))
-ORDER BY es.event_ts_ms DESC, es.seq DESC;
+  AND (sqlc.arg('env') = '' OR sqlc.arg('env') = 'all' OR COALESCE(ea.environment, es.environment) = sqlc.arg('env'))
+  AND (sqlc.arg('service') = '' OR sqlc.arg('service') = 'all' OR es.subject_id = sqlc.arg('service') OR COALESCE(sia.service_name, es.service_name) = sqlc.arg('service'))
+  AND (
+    sqlc.arg('status') = '' OR sqlc.arg('status') = 'all'
+    OR (sqlc.arg('status') = 'success' AND es.outcome = 'synced')
+    OR (sqlc.arg('status') = 'error' AND es.outcome IN ('warning', 'out-of-sync'))
+    OR (sqlc.arg('status') = 'queued' AND es.outcome NOT IN ('synced', 'warning', 'out-of-sync'))
+  )
+  AND (CAST(sqlc.arg('from_ms') AS INTEGER) = 0 OR es.event_ts_ms >= sqlc.arg('from_ms'))
+  AND (CAST(sqlc.arg('to_ms') AS INTEGER) = 0 OR es.event_ts_ms < sqlc.arg('to_ms'))
+  AND (
+    CAST(sqlc.arg('before_seq') AS INTEGER) = 0
+    OR es.event_ts_ms < sqlc.arg('before_ts_ms')
+    OR (es.event_ts_ms = sqlc.arg('before_ts_ms') AND es.seq < sqlc.arg('before_seq'))
+  )
+ORDER BY es.event_ts_ms DESC, es.seq DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ListDeploymentEnvironmentCounts :many
+SELECT
+  COALESCE(ea.environment, es.environment) AS environment,
+  CAST(SUM(CASE WHEN es.event_ts_ms >= sqlc.arg('recent_from_ms') THEN 1 ELSE 0 END) AS INTEGER) AS recent_count,
+  COUNT(*) AS total_count
+FROM event_store es
+LEFT JOIN environment_aliases ea
+  ON ea.organization_id = es.organization_id
+  AND ea.alias = es.environment
+WHERE es.organization_id = sqlc.arg('organization_id')
+  AND es.subject_type = 'service'
+  AND es.event_ts_ms >= sqlc.arg('from_ms')
+GROUP BY COALESCE(ea.environment, es.environment)
+ORDER BY COALESCE(ea.environment, es.environment);
+
+-- name: ListDeploymentServices :many
+SELECT DISTINCT service_name
+FROM service_env_state
+WHERE organization_id = sqlc.arg('organization_id')
+ORDER BY service_name;
 
 -- name: GetServiceLatestFromEvents :one
 SELECT
-  CASE
-    WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-    ELSE es.subject_id
-  END AS service_name,
+  COALESCE(sia.service_name, es.service_name) AS service_name,
   es.subject_id AS raw_subject_id,
   es.event_timestamp AS last_deploy_at,
   'cdevents' AS integration_type
 FROM event_store es
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
 WHERE es.subject_type = 'service'
   AND es.organization_id = sqlc.arg('organization_id')
-  AND (es.subject_id = sqlc.arg('service') OR substr(es.subject_id, instr(es.subject_id, '/') + 1) = sqlc.arg('service'))
+  AND (es.subject_id = sqlc.arg('service') OR COALESCE(sia.service_name, es.service_name) = sqlc.arg('service'))
 ORDER BY es.event_ts_ms DESC, es.seq DESC
 LIMIT 1;
 
@@ -681,14 +874,20 @@ LIMIT 1;
 WITH service_events AS (
   SELECT
     es.seq,
-    COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') AS environment,
+    COALESCE(ea.environment, es.environment) AS environment,
     es.event_timestamp,
     es.event_ts_ms,
-    COALESCE(json_extract(es.raw_event_json, '$.subject.content.artifactId'), '') AS artifact_id
+    es.artifact_id
   FROM event_store es
+  LEFT JOIN service_identity_aliases sia
+    ON sia.organization_id = es.organization_id
+    AND sia.alias = es.service_name
+  LEFT JOIN environment_aliases ea
+    ON ea.organization_id = es.organization_id
+    AND ea.alias = es.environment
   WHERE es.subject_type = 'service'
     AND es.organization_id = sqlc.arg('organization_id')
-    AND (es.subject_id = sqlc.arg('service') OR substr(es.subject_id, instr(es.subject_id, '/') + 1) = sqlc.arg('service'))
+    AND (es.subject_id = sqlc.arg('service') OR COALESCE(sia.service_name, es.service_name) = sqlc.arg('service'))
 ), ranked AS (
   SELECT
     environment,
@@ -711,12 +910,19 @@ ORDER BY released_at DESC;
 -- name: ListDeploymentHistoryByServiceFromEvents :many
 SELECT
   es.event_timestamp AS deployed_at,
-  COALESCE(json_extract(es.raw_event_json, '$.subject.content.artifactId'), '') AS release_ref,
-  COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') AS environment
+  es.artifact_id AS release_ref,
+  COALESCE(ea.environment, es.environment) AS environment,
+  COALESCE(es.chain_id, '') AS chain_id
 FROM event_store es
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
+LEFT JOIN environment_aliases ea
+  ON ea.organization_id = es.organization_id
+  AND ea.alias = es.environment
 WHERE es.subject_type = 'service'
   AND es.organization_id = sqlc.arg('organization_id')
-  AND (es.subject_id = sqlc.arg('service') OR substr(es.subject_id, instr(es.subject_id, '/') + 1) = sqlc.arg('service'))
+  AND (es.subject_id = sqlc.arg('service') OR COALESCE(sia.service_name, es.service_name) = sqlc.arg('service'))
 ORDER BY es.event_ts_ms DESC, es.seq DESC
 LIMIT sqlc.arg('limit');
 
@@ -734,6 +940,12 @@ WHERE organization_id = sqlc.arg('organization_id')
   AND depends_on_service_name = sqlc.arg('service_name')
 ORDER BY service_name;
 
+-- name: ListOrganizationServiceDependencies :many
+SELECT service_name, depends_on_service_name
+FROM service_dependencies
+WHERE organization_id = sqlc.arg('organization_id')
+ORDER BY service_name, depends_on_service_name;
+
 -- name: UpsertServiceDependency :exec
 INSERT INTO service_dependencies (organization_id, service_name, depends_on_service_name)
 VALUES (sqlc.arg('organization_id'), sqlc.arg('service_name'), sqlc.arg('depends_on_service_name'))
@@ -803,7 +1015,566 @@ FROM (
 
   UNION ALL
 
+  SELECT COALESCE(MAX(CAST(strftime('%s', updated_at) AS INTEGER)), 0) AS version_value
+  FROM organization_event_policies
+  WHERE organization_event_policies.organization_id = sqlc.arg('org_id')
+
+  UNION ALL
+
   SELECT COALESCE(MAX(CAST(strftime('%s', created_at) AS INTEGER)), 0) AS version_value
   FROM service_dependencies
   WHERE service_dependencies.organization_id = sqlc.arg('org_id')
 );
+
+-- name: GetOrganizationLatestEventSeq :one
+SELECT CAST(COALESCE(MAX(seq), 0) AS INTEGER) AS seq
+FROM event_store
+WHERE organization_id = sqlc.arg('organization_id');
+
+-- name: ListServiceChangesAfterSeq :many
+SELECT
+  CAST(
+    COALESCE(sia.service_name, es.service_name) AS TEXT
+  ) AS service_name,
+  CAST(MAX(es.seq) AS INTEGER) AS last_seq
+FROM event_store es
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
+WHERE es.organization_id = sqlc.arg('organization_id')
+  AND es.subject_type = 'service'
+  AND es.seq > sqlc.arg('after_seq')
+GROUP BY COALESCE(sia.service_name, es.service_name)
+ORDER BY last_seq ASC
+LIMIT sqlc.arg('limit');
+
+-- name: InsertIngestSeenSignature :execrows
+INSERT INTO ingest_seen_signatures (organization_id, signature, seen_at, expires_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(organization_id, signature) DO NOTHING;
+
+-- name: PruneIngestSeenSignatures :exec
+DELETE FROM ingest_seen_signatures
+WHERE expires_at < ?;
+
+-- name: ListOrganizationIngestCredentials :many
+SELECT id, organization_id, label, auth_token, webhook_secret, created_at, expires_at, revoked_at, last_used_at
+FROM organization_ingest_credentials
+WHERE organization_id = ?
+ORDER BY created_at DESC, id DESC;
+
+-- name: InsertOrganizationIngestCredential :one
+INSERT INTO organization_ingest_credentials (organization_id, label, auth_token, webhook_secret, created_at, expires_at)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING id, organization_id, label, auth_token, webhook_secret, created_at, expires_at, revoked_at, last_used_at;
+
+-- name: ExpireOrganizationIngestCredential :execrows
+UPDATE organization_ingest_credentials
+SET expires_at = sqlc.arg('expires_at')
+WHERE organization_id = sqlc.arg('organization_id')
+  AND id = sqlc.arg('id')
+  AND revoked_at IS NULL;
+
+-- name: RevokeOrganizationIngestCredential :execrows
+UPDATE organization_ingest_credentials
+SET revoked_at = sqlc.arg('revoked_at')
+WHERE organization_id = sqlc.arg('organization_id')
+  AND id = sqlc.arg('id')
+  AND revoked_at IS NULL;
+
+-- name: TouchOrganizationIngestCredential :exec
+UPDATE organization_ingest_credentials
+SET last_used_at = sqlc.arg('used_at')
+WHERE organization_id = sqlc.arg('organization_id')
+  AND id = sqlc.arg('id')
+  AND (last_used_at IS NULL OR last_used_at < sqlc.arg('stale_before'));
+
+-- name: IncrementIngestHourlyStat :exec
+INSERT INTO ingest_hourly_stats (organization_id, bucket_start, outcome, reason, event_count)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(organization_id, bucket_start, outcome, reason) DO UPDATE SET
+  event_count = ingest_hourly_stats.event_count + excluded.event_count;
+
+-- name: PruneIngestHourlyStats :exec
+DELETE FROM ingest_hourly_stats
+WHERE organization_id = sqlc.arg('organization_id')
+  AND bucket_start < sqlc.arg('before');
+
+-- name: ListIngestHourlyStats :many
+SELECT bucket_start, outcome, reason, event_count
+FROM ingest_hourly_stats
+WHERE organization_id = sqlc.arg('organization_id')
+  AND bucket_start >= sqlc.arg('since')
+ORDER BY bucket_start, outcome, reason;
+
+-- name: UpsertIngestSourceActivity :exec
+INSERT INTO ingest_source_activity (organization_id, event_source, last_event_id, last_event_type, last_seen_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(organization_id, event_source) DO UPDATE SET
+  last_event_id = excluded.last_event_id,
+  last_event_type = excluded.last_event_type,
+  last_seen_at = excluded.last_seen_at
+WHERE excluded.last_seen_at >= ingest_source_activity.last_seen_at;
+
+-- name: ListIngestSourceActivity :many
+SELECT event_source, last_event_id, last_event_type, last_seen_at
+FROM ingest_source_activity
+WHERE organization_id = ?
+ORDER BY last_seen_at DESC
+LIMIT ?;
+
+-- name: EnsureProjectorCheckpoint :exec
+INSERT INTO projector_checkpoints (name, last_seq, updated_at)
+VALUES (?, 0, ?)
+ON CONFLICT(name) DO NOTHING;
+
+-- name: GetProjectorCheckpoint :one
+SELECT last_seq FROM projector_checkpoints WHERE name = ?;
+
+-- name: ListProjectorCheckpoints :many
+SELECT name, last_seq, updated_at
+FROM projector_checkpoints
+ORDER BY name;
+
+-- name: ListLiveProjectorNames :many
+SELECT name
+FROM projector_checkpoints
+WHERE last_seq >= (SELECT COALESCE(MAX(es.seq), 0) FROM event_store es WHERE es.seq < sqlc.arg('first_seq'));
+
+-- name: SetProjectorCheckpoint :exec
+UPDATE projector_checkpoints
+SET last_seq = sqlc.arg('last_seq'), updated_at = sqlc.arg('updated_at')
+WHERE name = sqlc.arg('name');
+
+-- name: AdvanceProjectorCheckpoints :exec
+UPDATE projector_checkpoints
+SET last_seq = sqlc.arg('last_seq'), updated_at = sqlc.arg('updated_at')
+WHERE name IN (sqlc.slice('names'));
+
+-- name: GetEventStoreHeadSeq :one
+SELECT CAST(COALESCE(MAX(seq), 0) AS INTEGER) AS head_seq FROM event_store;
+
+-- name: ListProjectorEvents :many
+SELECT seq, organization_id, event_type, subject_type, subject_id, event_ts_ms
+FROM event_store
+WHERE seq > sqlc.arg('after_seq')
+  AND seq <= sqlc.arg('head_seq')
+  AND subject_type IN (sqlc.slice('subject_types'))
+ORDER BY seq
+LIMIT sqlc.arg('limit');
+
+-- name: ListEventRetentionPolicies :many
+SELECT organization_id, CAST(preference_value AS INTEGER) AS retention_days
+FROM organization_preferences
+WHERE preference_key = 'event_retention_days'
+  AND CAST(preference_value AS INTEGER) > 0
+ORDER BY organization_id;
+
+-- name: ListEventsForArchive :many
+SELECT *
+FROM event_store
+WHERE organization_id = sqlc.arg('organization_id')
+  AND event_ts_ms < sqlc.arg('before_ms')
+  AND seq > sqlc.arg('after_seq')
+ORDER BY seq
+LIMIT sqlc.arg('limit');
+
+-- name: DeleteArchivedEvents :execrows
+DELETE FROM event_store
+WHERE organization_id = sqlc.arg('organization_id')
+  AND event_ts_ms < sqlc.arg('before_ms')
+  AND seq >= sqlc.arg('first_seq')
+  AND seq <= sqlc.arg('last_seq');
+
+-- name: InsertEventArchive :one
+INSERT INTO event_archives (organization_id, path, first_seq, last_seq, event_count, archived_before_ms, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: ListEventArchives :many
+SELECT *
+FROM event_archives
+WHERE organization_id = ?
+ORDER BY created_at DESC, id DESC
+LIMIT ?;
+
+-- name: GetEventArchiveCutoff :one
+SELECT CAST(COALESCE(MAX(archived_before_ms), 0) AS INTEGER) AS archived_before_ms
+FROM event_archives
+WHERE organization_id = ?;
+
+-- name: RestoreArchivedEvent :execrows
+INSERT INTO event_store (
+  seq, organization_id, event_id, event_type, event_source, event_timestamp,
+  subject_id, subject_source, subject_type, chain_id, raw_event_json, raw_event_compressed,
+  ingested_at, event_ts_ms,
+  service_name, environment, artifact_id, outcome, actor_name,
+  environment_id, content_service, content_service_id, pipeline_run_id, run_url, link_url, duration_seconds
+)
+VALUES (
+  sqlc.arg('seq'),
+  sqlc.arg('organization_id'),
+  sqlc.arg('event_id'),
+  sqlc.arg('event_type'),
+  sqlc.arg('event_source'),
+  sqlc.arg('event_timestamp'),
+  sqlc.arg('subject_id'),
+  sqlc.narg('subject_source'),
+  sqlc.arg('subject_type'),
+  sqlc.narg('chain_id'),
+  '',
+  ddash_event_compress(CAST(sqlc.arg('raw_event_json') AS TEXT)),
+  sqlc.arg('ingested_at'),
+  sqlc.arg('event_ts_ms'),
+  CASE
+    WHEN instr(sqlc.arg('subject_id'), '/') > 0 THEN substr(sqlc.arg('subject_id'), instr(sqlc.arg('subject_id'), '/') + 1)
+    ELSE sqlc.arg('subject_id')
+  END,
+  COALESCE(NULLIF(json_extract(sqlc.arg('raw_event_json'), '$.subject.content.environment.id'), ''), 'unknown'),
+  COALESCE(json_extract(sqlc.arg('raw_event_json'), '$.subject.content.artifactId'), ''),
+  CASE
+    WHEN sqlc.arg('subject_type') <> 'service' THEN ''
+    WHEN sqlc.arg('event_type') LIKE 'dev.cdevents.service.deployed.%' THEN 'synced'
+    WHEN sqlc.arg('event_type') LIKE 'dev.cdevents.service.upgraded.%' THEN 'synced'
+    WHEN sqlc.arg('event_type') LIKE 'dev.cdevents.service.published.%' THEN 'synced'
+    WHEN sqlc.arg('event_type') LIKE 'dev.cdevents.service.rolledback.%' THEN 'warning'
+    WHEN sqlc.arg('event_type') LIKE 'dev.cdevents.service.removed.%' THEN 'out-of-sync'
+    ELSE 'unknown'
+  END,
+  COALESCE(json_extract(sqlc.arg('raw_event_json'), '$.subject.content.actor.name'), ''),
+  COALESCE(json_extract(sqlc.arg('raw_event_json'), '$.subject.content.environment.id'), ''),
+  CASE
+    WHEN json_type(sqlc.arg('raw_event_json'), '$.subject.content.service') = 'text'
+    THEN json_extract(sqlc.arg('raw_event_json'), '$.subject.content.service')
+    ELSE ''
+  END,
+  CASE
+    WHEN json_type(sqlc.arg('raw_event_json'), '$.subject.content.service') = 'object'
+    THEN COALESCE(json_extract(sqlc.arg('raw_event_json'), '$.subject.content.service.id'), '')
+    ELSE ''
+  END,
+  COALESCE(json_extract(sqlc.arg('raw_event_json'), '$.subject.content.pipeline.runId'), ''),
+  COALESCE(json_extract(sqlc.arg('raw_event_json'), '$.subject.content.pipeline.url'), ''),
+  COALESCE(
+    NULLIF(json_extract(sqlc.arg('raw_event_json'), '$.subject.content.url'), ''),
+    json_extract(sqlc.arg('raw_event_json'), '$.subject.content.pipeline.url'),
+    ''
+  ),
+  COALESCE(json_extract(sqlc.arg('raw_event_json'), '$.subject.content.durationSeconds'), 0)
+)
+ON CONFLICT DO NOTHING;
+
+-- name: CompressEventStorePayloads :many
+-- Compresses the next chunk of event bodies stored as plain text after
+-- after_seq and returns the rewritten seqs.
+UPDATE event_store
+SET raw_event_compressed = ddash_event_compress(raw_event_json),
+    raw_event_json = ''
+WHERE event_store.seq IN (
+  SELECT pending.seq
+  FROM event_store pending
+  WHERE pending.seq > sqlc.arg('after_seq')
+    AND pending.raw_event_compressed IS NULL
+    AND pending.raw_event_json <> ''
+  ORDER BY pending.seq
+  LIMIT sqlc.arg('limit')
+)
+RETURNING seq;
+
+-- name: ListEventStoreEvents :many
+SELECT seq, event_id, event_type, event_source, event_ts_ms, subject_id, subject_type, chain_id, ingested_at
+FROM event_store
+WHERE organization_id = sqlc.arg('organization_id')
+  AND (CAST(sqlc.arg('before_seq') AS INTEGER) = 0 OR seq < sqlc.arg('before_seq'))
+  AND (CAST(sqlc.arg('event_type') AS TEXT) = '' OR event_type = sqlc.arg('event_type'))
+  AND (CAST(sqlc.arg('subject_type') AS TEXT) = '' OR subject_type = sqlc.arg('subject_type'))
+  AND (CAST(sqlc.arg('subject_id') AS TEXT) = '' OR subject_id = sqlc.arg('subject_id'))
+  AND (CAST(sqlc.arg('event_source') AS TEXT) = '' OR event_source = sqlc.arg('event_source'))
+  AND (CAST(sqlc.arg('chain_id') AS TEXT) = '' OR chain_id = sqlc.arg('chain_id'))
+  AND (CAST(sqlc.arg('from_ms') AS INTEGER) = 0 OR event_ts_ms >= sqlc.arg('from_ms'))
+  AND (CAST(sqlc.arg('to_ms') AS INTEGER) = 0 OR event_ts_ms < sqlc.arg('to_ms'))
+ORDER BY seq DESC
+LIMIT sqlc.arg('limit');
+
+-- name: GetEventStoreEvent :one
+SELECT *
+FROM event_store
+WHERE organization_id = sqlc.arg('organization_id')
+  AND seq = sqlc.arg('seq');
+
+-- name: ListEventContributions :many
+SELECT scl.service_name, scl.environment
+FROM service_change_links scl
+WHERE scl.organization_id = sqlc.arg('organization_id')
+  AND scl.event_seq = sqlc.arg('seq')
+UNION
+SELECT
+  COALESCE(sia.service_name, es.service_name) AS service_name,
+  COALESCE(ea.environment, es.environment) AS environment
+FROM event_store es
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
+LEFT JOIN environment_aliases ea
+  ON ea.organization_id = es.organization_id
+  AND ea.alias = es.environment
+WHERE es.organization_id = sqlc.arg('organization_id')
+  AND es.seq = sqlc.arg('seq')
+  AND es.subject_type = 'service'
+ORDER BY 1, 2;
+
+-- name: ListChainEvents :many
+SELECT
+  es.seq,
+  es.event_id,
+  es.event_type,
+  es.event_source,
+  es.event_ts_ms,
+  es.subject_id,
+  es.subject_type,
+  es.ingested_at,
+  es.link_url AS url
+FROM event_store es
+WHERE es.organization_id = sqlc.arg('organization_id')
+  AND es.chain_id = sqlc.arg('chain_id')
+ORDER BY es.event_ts_ms, es.seq
+LIMIT sqlc.arg('limit');
+
+-- name: ListServiceIdentityRules :many
+SELECT *
+FROM service_identity_rules
+WHERE organization_id = sqlc.arg('organization_id')
+ORDER BY id;
+
+-- name: CreateServiceIdentityRule :one
+INSERT INTO service_identity_rules (organization_id, kind, pattern, service_name)
+VALUES (sqlc.arg('organization_id'), sqlc.arg('kind'), sqlc.arg('pattern'), sqlc.arg('service_name'))
+RETURNING *;
+
+-- name: DeleteServiceIdentityRule :execrows
+DELETE FROM service_identity_rules
+WHERE organization_id = sqlc.arg('organization_id')
+  AND id = sqlc.arg('id');
+
+-- name: DeleteServiceIdentityRules :exec
+DELETE FROM service_identity_rules
+WHERE organization_id = sqlc.arg('organization_id');
+
+-- name: ListServiceIdentityAliases :many
+SELECT *
+FROM service_identity_aliases
+WHERE organization_id = sqlc.arg('organization_id')
+ORDER BY service_name, alias;
+
+-- name: GetServiceIdentityAlias :one
+SELECT service_name
+FROM service_identity_aliases
+WHERE organization_id = sqlc.arg('organization_id')
+  AND alias = sqlc.arg('alias');
+
+-- name: UpsertServiceIdentityAlias :exec
+INSERT INTO service_identity_aliases (organization_id, alias, service_name)
+VALUES (sqlc.arg('organization_id'), sqlc.arg('alias'), sqlc.arg('service_name'))
+ON CONFLICT(organization_id, alias) DO UPDATE SET
+  service_name = excluded.service_name;
+
+-- name: DeleteServiceIdentityAliases :exec
+DELETE FROM service_identity_aliases
+WHERE organization_id = sqlc.arg('organization_id');
+
+-- name: ListServiceNameCandidates :many
+SELECT ses.service_name AS name FROM service_env_state ses WHERE ses.organization_id = sqlc.arg('organization_id')
+UNION
+SELECT psd.service_name FROM service_pipeline_stats_daily psd WHERE psd.organization_id = sqlc.arg('organization_id')
+UNION
+SELECT sts.service_name FROM service_throughput_stats sts WHERE sts.organization_id = sqlc.arg('organization_id')
+UNION
+SELECT sil.service_name FROM service_incident_links sil WHERE sil.organization_id = sqlc.arg('organization_id')
+UNION
+SELECT sm.service_name FROM service_metadata sm WHERE sm.organization_id = sqlc.arg('organization_id')
+UNION
+SELECT sd.service_name FROM service_dependencies sd WHERE sd.organization_id = sqlc.arg('organization_id')
+UNION
+SELECT sdo.depends_on_service_name FROM service_dependencies sdo WHERE sdo.organization_id = sqlc.arg('organization_id')
+UNION
+SELECT sia.alias FROM service_identity_aliases sia WHERE sia.organization_id = sqlc.arg('organization_id')
+ORDER BY 1;
+
+-- name: MoveServiceMetadata :exec
+INSERT INTO service_metadata (organization_id, service_name, label, value)
+SELECT src.organization_id, sqlc.arg('to_service_name'), src.label, src.value
+FROM service_metadata src
+WHERE src.organization_id = sqlc.arg('organization_id')
+  AND src.service_name = sqlc.arg('from_service_name')
+ON CONFLICT(organization_id, service_name, label) DO NOTHING;
+
+-- name: MoveServiceDependencies :exec
+INSERT INTO service_dependencies (organization_id, service_name, depends_on_service_name)
+SELECT moved.organization_id, moved.service_name, moved.depends_on_service_name
+FROM (
+  SELECT
+    src.organization_id,
+    CASE WHEN src.service_name = sqlc.arg('from_service_name') THEN sqlc.arg('to_service_name') ELSE src.service_name END AS service_name,
+    CASE WHEN src.depends_on_service_name = sqlc.arg('from_service_name') THEN sqlc.arg('to_service_name') ELSE src.depends_on_service_name END AS depends_on_service_name
+  FROM service_dependencies src
+  WHERE src.organization_id = sqlc.arg('organization_id')
+    AND (src.service_name = sqlc.arg('from_service_name') OR src.depends_on_service_name = sqlc.arg('from_service_name'))
+) moved
+WHERE moved.service_name <> moved.depends_on_service_name
+ON CONFLICT(organization_id, service_name, depends_on_service_name) DO NOTHING;
+
+-- name: DeleteServiceDependenciesByService :exec
+DELETE FROM service_dependencies
+WHERE organization_id = sqlc.arg('organization_id')
+  AND (service_name = sqlc.arg('service_name') OR depends_on_service_name = sqlc.arg('service_name'));
+
+-- name: ListOrganizationEnvironments :many
+SELECT *
+FROM organization_environments
+WHERE organization_id = sqlc.arg('organization_id')
+ORDER BY sort_order, name;
+
+-- name: GetOrganizationEnvironment :one
+SELECT *
+FROM organization_environments
+WHERE organization_id = sqlc.arg('organization_id')
+  AND name = sqlc.arg('name');
+
+-- name: UpsertOrganizationEnvironment :one
+INSERT INTO organization_environments (organization_id, name, tier, color, protected, sort_order, url, source, changed_at_ms)
+VALUES (
+  sqlc.arg('organization_id'),
+  sqlc.arg('name'),
+  sqlc.arg('tier'),
+  sqlc.arg('color'),
+  sqlc.arg('protected'),
+  (SELECT COALESCE(MAX(oe.sort_order) + 1, 0) FROM organization_environments oe WHERE oe.organization_id = sqlc.arg('organization_id')),
+  sqlc.arg('url'),
+  sqlc.arg('source'),
+  sqlc.arg('changed_at_ms')
+)
+ON CONFLICT(organization_id, name) DO UPDATE SET
+  tier = excluded.tier,
+  color = excluded.color,
+  protected = excluded.protected,
+  url = excluded.url,
+  changed_at_ms = excluded.changed_at_ms,
+  updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: TouchOrganizationEnvironmentFromEvent :exec
+UPDATE organization_environments
+SET url = CASE WHEN CAST(sqlc.arg('url') AS TEXT) <> '' THEN CAST(sqlc.arg('url') AS TEXT) ELSE url END,
+    changed_at_ms = sqlc.arg('changed_at_ms'),
+    updated_at = CURRENT_TIMESTAMP
+WHERE organization_id = sqlc.arg('organization_id')
+  AND id = sqlc.arg('id');
+
+-- name: SetOrganizationEnvironmentSortOrder :exec
+UPDATE organization_environments
+SET sort_order = sqlc.arg('sort_order'),
+    updated_at = CURRENT_TIMESTAMP
+WHERE organization_id = sqlc.arg('organization_id')
+  AND name = sqlc.arg('name');
+
+-- name: DeleteOrganizationEnvironment :execrows
+DELETE FROM organization_environments
+WHERE organization_id = sqlc.arg('organization_id')
+  AND id = sqlc.arg('id');
+
+-- name: DeleteOrganizationEnvironments :exec
+DELETE FROM organization_environments
+WHERE organization_id = sqlc.arg('organization_id');
+
+-- name: ListEnvironmentAliases :many
+SELECT *
+FROM environment_aliases
+WHERE organization_id = sqlc.arg('organization_id')
+ORDER BY environment, alias;
+
+-- name: GetEnvironmentAlias :one
+SELECT *
+FROM environment_aliases
+WHERE organization_id = sqlc.arg('organization_id')
+  AND alias = sqlc.arg('alias');
+
+-- name: CreateEnvironmentAlias :exec
+INSERT INTO environment_aliases (organization_id, alias, environment_id, environment)
+VALUES (sqlc.arg('organization_id'), sqlc.arg('alias'), sqlc.arg('environment_id'), sqlc.arg('environment'));
+
+-- name: DeleteEnvironmentAliasesByEnvironment :exec
+DELETE FROM environment_aliases
+WHERE organization_id = sqlc.arg('organization_id')
+  AND environment_id = sqlc.arg('environment_id');
+
+-- name: ListServiceEnvironmentStateAt :many
+-- The latest service event per service and environment at or before at_ms.
+-- Archived events are represented by the environment state at the archive
+-- cutoff, which has no actor.
+WITH candidates AS (
+  SELECT
+    COALESCE(sia.service_name, es.service_name) AS service_name,
+    COALESCE(ea.environment, es.environment) AS environment,
+    es.seq AS event_seq,
+    es.event_type,
+    es.event_ts_ms,
+    es.artifact_id,
+    es.actor_name,
+    es.outcome AS status
+  FROM event_store es
+  LEFT JOIN service_identity_aliases sia
+    ON sia.organization_id = es.organization_id
+    AND sia.alias = es.service_name
+  LEFT JOIN environment_aliases ea
+    ON ea.organization_id = es.organization_id
+    AND ea.alias = es.environment
+  WHERE es.organization_id = sqlc.arg('organization_id')
+    AND es.subject_type = 'service'
+    AND es.event_ts_ms <= sqlc.arg('at_ms')
+  UNION ALL
+  SELECT
+    COALESCE(asia.service_name, a.service_name),
+    COALESCE(aea.environment, a.environment),
+    a.latest_event_seq,
+    a.latest_event_type,
+    a.latest_event_ts_ms,
+    a.latest_artifact_id,
+    '',
+    a.latest_status
+  FROM event_archive_env_state a
+  LEFT JOIN service_identity_aliases asia
+    ON asia.organization_id = a.organization_id
+    AND asia.alias = a.service_name
+  LEFT JOIN environment_aliases aea
+    ON aea.organization_id = a.organization_id
+    AND aea.alias = a.environment
+  WHERE a.organization_id = sqlc.arg('organization_id')
+    AND a.latest_event_ts_ms <= sqlc.arg('at_ms')
+), ranked AS (
+  SELECT
+    c.service_name,
+    c.environment,
+    c.event_seq,
+    c.event_type,
+    c.event_ts_ms,
+    c.artifact_id,
+    c.actor_name,
+    c.status,
+    row_number() OVER (
+      PARTITION BY c.service_name, c.environment
+      ORDER BY c.event_ts_ms DESC, c.event_seq DESC
+    ) AS rn
+  FROM candidates c
+)
+SELECT
+  r.service_name,
+  r.environment,
+  r.event_seq,
+  r.event_type,
+  r.event_ts_ms,
+  r.artifact_id,
+  r.actor_name,
+  r.status
+FROM ranked r
+WHERE r.rn = 1
+ORDER BY r.service_name, r.environment;
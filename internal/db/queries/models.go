@@ -36,20 +36,62 @@ type Environment struct {
 	Name string
 }
 
-type EventStore struct {
-	Seq            int64
+type EnvironmentAlias struct {
 	OrganizationID int64
-	EventID        string
-	EventType      string
-	EventSource    string
-	EventTimestamp string
-	SubjectID      string
-	SubjectSource  sql.NullString
-	SubjectType    string
-	ChainID        sql.NullString
-	RawEventJson   string
-	IngestedAt     time.Time
-	EventTsMs      int64
+	Alias          string
+	EnvironmentID  int64
+	Environment    string
+}
+
+type EventArchive struct {
+	ID               int64
+	OrganizationID   int64
+	Path             string
+	FirstSeq         int64
+	LastSeq          int64
+	EventCount       int64
+	ArchivedBeforeMs int64
+	CreatedAt        int64
+}
+
+type EventArchiveEnvState struct {
+	OrganizationID   int64
+	ServiceName      string
+	Environment      string
+	LatestEventSeq   int64
+	LatestEventType  string
+	LatestEventTsMs  int64
+	LatestStatus     string
+	LatestArtifactID string
+}
+
+type EventStore struct {
+	Seq                int64
+	OrganizationID     int64
+	EventID            string
+	EventType          string
+	EventSource        string
+	EventTimestamp     string
+	SubjectID          string
+	SubjectSource      sql.NullString
+	SubjectType        string
+	ChainID            sql.NullString
+	RawEventJson       string
+	IngestedAt         time.Time
+	EventTsMs          int64
+	ServiceName        string
+	Environment        string
+	ArtifactID         string
+	Outcome            string
+	ActorName          string
+	RawEventCompressed []byte
+	EnvironmentID      string
+	ContentService     string
+	ContentServiceID   string
+	PipelineRunID      string
+	RunUrl             string
+	LinkUrl            string
+	DurationSeconds    float64
 }
 
 type GithubInstallationMapping struct {
@@ -81,6 +123,44 @@ type GitlabProjectMapping struct {
 	UpdatedAt          time.Time
 }
 
+type IngestDeadLetter struct {
+	ID             int64
+	OrganizationID int64
+	Reason         string
+	Detail         string
+	HeadersJson    string
+	Body           []byte
+	BodySize       int64
+	Truncated      int64
+	ReceivedAt     int64
+	ReplayedAt     sql.NullInt64
+	ReplayResult   string
+	CreatedAt      sql.NullTime
+}
+
+type IngestHourlyStat struct {
+	OrganizationID int64
+	BucketStart    int64
+	Outcome        string
+	Reason         string
+	EventCount     int64
+}
+
+type IngestSeenSignature struct {
+	OrganizationID int64
+	Signature      string
+	SeenAt         int64
+	ExpiresAt      int64
+}
+
+type IngestSourceActivity struct {
+	OrganizationID int64
+	EventSource    string
+	LastEventID    string
+	LastEventType  string
+	LastSeenAt     int64
+}
+
 type Organization struct {
 	ID            int64
 	Name          string
@@ -92,6 +172,21 @@ type Organization struct {
 	JoinCode      sql.NullString
 }
 
+type OrganizationEnvironment struct {
+	ID             int64
+	OrganizationID int64
+	Name           string
+	Tier           string
+	Color          string
+	Protected      int64
+	SortOrder      int64
+	Url            string
+	Source         string
+	ChangedAtMs    int64
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
 type OrganizationEnvironmentPriority struct {
 	ID             int64
 	OrganizationID int64
@@ -101,6 +196,15 @@ type OrganizationEnvironmentPriority struct {
 	UpdatedAt      sql.NullTime
 }
 
+type OrganizationEventPolicy struct {
+	ID             int64
+	OrganizationID int64
+	Subject        string
+	Mode           string
+	CreatedAt      sql.NullTime
+	UpdatedAt      sql.NullTime
+}
+
 type OrganizationFeature struct {
 	ID             int64
 	OrganizationID int64
@@ -110,6 +214,18 @@ type OrganizationFeature struct {
 	UpdatedAt      sql.NullTime
 }
 
+type OrganizationIngestCredential struct {
+	ID             int64
+	OrganizationID int64
+	Label          string
+	AuthToken      string
+	WebhookSecret  string
+	CreatedAt      int64
+	ExpiresAt      sql.NullInt64
+	RevokedAt      sql.NullInt64
+	LastUsedAt     sql.NullInt64
+}
+
 type OrganizationJoinRequest struct {
 	ID             int64
 	OrganizationID int64
@@ -151,6 +267,12 @@ type OrganizationRequiredField struct {
 	IsFilterable   int64
 }
 
+type ProjectorCheckpoint struct {
+	Name      string
+	LastSeq   int64
+	UpdatedAt int64
+}
+
 type Release struct {
 	ID            int64
 	ServiceID     int64
@@ -269,6 +391,21 @@ type ServiceField struct {
 	SortOrder int64
 }
 
+type ServiceIdentityAlias struct {
+	OrganizationID int64
+	Alias          string
+	ServiceName    string
+}
+
+type ServiceIdentityRule struct {
+	ID             int64
+	OrganizationID int64
+	Kind           string
+	Pattern        string
+	ServiceName    string
+	CreatedAt      time.Time
+}
+
 type ServiceIncidentLink struct {
 	OrganizationID     int64
 	ServiceName        string
@@ -277,6 +414,9 @@ type ServiceIncidentLink struct {
 	LinkedAt           int64
 	DeploymentEventSeq sql.NullInt64
 	CreatedAt          time.Time
+	Environment        string
+	DeploymentTsMs     sql.NullInt64
+	ResolvedAt         sql.NullInt64
 }
 
 type ServiceInstance struct {
@@ -8,9 +8,39 @@ package queries
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"time"
 )
 
+const advanceProjectorCheckpoints = `-- name: AdvanceProjectorCheckpoints :exec
+UPDATE projector_checkpoints
+SET last_seq = ?1, updated_at = ?2
+WHERE name IN (/*SLICE:names*/?)
+`
+
+type AdvanceProjectorCheckpointsParams struct {
+	LastSeq   int64
+	UpdatedAt int64
+	Names     []string
+}
+
+func (q *Queries) AdvanceProjectorCheckpoints(ctx context.Context, arg AdvanceProjectorCheckpointsParams) error {
+	query := advanceProjectorCheckpoints
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.LastSeq)
+	queryParams = append(queryParams, arg.UpdatedAt)
+	if len(arg.Names) > 0 {
+		for _, v := range arg.Names {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:names*/?", strings.Repeat(",?", len(arg.Names))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:names*/?", "NULL", 1)
+	}
+	_, err := q.db.ExecContext(ctx, query, queryParams...)
+	return err
+}
+
 const appendEventStore = `-- name: AppendEventStore :one
 INSERT INTO event_store (
   organization_id,
@@ -23,7 +53,20 @@ INSERT INTO event_store (
   subject_source,
   subject_type,
   chain_id,
-  raw_event_json
+  raw_event_json,
+  raw_event_compressed,
+  service_name,
+  environment,
+  artifact_id,
+  outcome,
+  actor_name,
+  environment_id,
+  content_service,
+  content_service_id,
+  pipeline_run_id,
+  run_url,
+  link_url,
+  duration_seconds
 )
 VALUES (
   ?1,
@@ -36,7 +79,43 @@ VALUES (
   ?8,
   ?9,
   ?10,
-  ?11
+  '',
+  ddash_event_compress(CAST(?11 AS TEXT)),
+  CASE
+    WHEN instr(?7, '/') > 0 THEN substr(?7, instr(?7, '/') + 1)
+    ELSE ?7
+  END,
+  COALESCE(NULLIF(json_extract(?11, '$.subject.content.environment.id'), ''), 'unknown'),
+  COALESCE(json_extract(?11, '$.subject.content.artifactId'), ''),
+  CASE
+    WHEN ?9 <> 'service' THEN ''
+    WHEN ?3 LIKE 'dev.cdevents.service.deployed.%' THEN 'synced'
+    WHEN ?3 LIKE 'dev.cdevents.service.upgraded.%' THEN 'synced'
+    WHEN ?3 LIKE 'dev.cdevents.service.published.%' THEN 'synced'
+    WHEN ?3 LIKE 'dev.cdevents.service.rolledback.%' THEN 'warning'
+    WHEN ?3 LIKE 'dev.cdevents.service.removed.%' THEN 'out-of-sync'
+    ELSE 'unknown'
+  END,
+  COALESCE(json_extract(?11, '$.subject.content.actor.name'), ''),
+  COALESCE(json_extract(?11, '$.subject.content.environment.id'), ''),
+  CASE
+    WHEN json_type(?11, '$.subject.content.service') = 'text'
+    THEN json_extract(?11, '$.subject.content.service')
+    ELSE ''
+  END,
+  CASE
+    WHEN json_type(?11, '$.subject.content.service') = 'object'
+    THEN COALESCE(json_extract(?11, '$.subject.content.service.id'), '')
+    ELSE ''
+  END,
+  COALESCE(json_extract(?11, '$.subject.content.pipeline.runId'), ''),
+  COALESCE(json_extract(?11, '$.subject.content.pipeline.url'), ''),
+  COALESCE(
+    NULLIF(json_extract(?11, '$.subject.content.url'), ''),
+    json_extract(?11, '$.subject.content.pipeline.url'),
+    ''
+  ),
+  COALESCE(json_extract(?11, '$.subject.content.durationSeconds'), 0)
 )
 ON CONFLICT(organization_id, event_source, event_id) DO NOTHING
 RETURNING seq
@@ -75,6 +154,52 @@ func (q *Queries) AppendEventStore(ctx context.Context, arg AppendEventStorePara
 	return seq, err
 }
 
+const compressEventStorePayloads = `-- name: CompressEventStorePayloads :many
+UPDATE event_store
+SET raw_event_compressed = ddash_event_compress(raw_event_json),
+    raw_event_json = ''
+WHERE event_store.seq IN (
+  SELECT pending.seq
+  FROM event_store pending
+  WHERE pending.seq > ?1
+    AND pending.raw_event_compressed IS NULL
+    AND pending.raw_event_json <> ''
+  ORDER BY pending.seq
+  LIMIT ?2
+)
+RETURNING seq
+`
+
+type CompressEventStorePayloadsParams struct {
+	AfterSeq int64
+	Limit    int64
+}
+
+// Compresses the next chunk of event bodies stored as plain text after
+// after_seq and returns the rewritten seqs.
+func (q *Queries) CompressEventStorePayloads(ctx context.Context, arg CompressEventStorePayloadsParams) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, compressEventStorePayloads, arg.AfterSeq, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var seq int64
+		if err := rows.Scan(&seq); err != nil {
+			return nil, err
+		}
+		items = append(items, seq)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const countOrganizationOwners = `-- name: CountOrganizationOwners :one
 SELECT COUNT(*)
 FROM organization_members
@@ -88,6 +213,28 @@ func (q *Queries) CountOrganizationOwners(ctx context.Context, organizationID in
 	return count, err
 }
 
+const createEnvironmentAlias = `-- name: CreateEnvironmentAlias :exec
+INSERT INTO environment_aliases (organization_id, alias, environment_id, environment)
+VALUES (?1, ?2, ?3, ?4)
+`
+
+type CreateEnvironmentAliasParams struct {
+	OrganizationID int64
+	Alias          string
+	EnvironmentID  int64
+	Environment    string
+}
+
+func (q *Queries) CreateEnvironmentAlias(ctx context.Context, arg CreateEnvironmentAliasParams) error {
+	_, err := q.db.ExecContext(ctx, createEnvironmentAlias,
+		arg.OrganizationID,
+		arg.Alias,
+		arg.EnvironmentID,
+		arg.Environment,
+	)
+	return err
+}
+
 const createGitHubSetupIntent = `-- name: CreateGitHubSetupIntent :exec
 INSERT INTO github_setup_intents (
   state,
@@ -222,6 +369,82 @@ func (q *Queries) CreateOrganizationRequiredField(ctx context.Context, arg Creat
 	return i, err
 }
 
+const createServiceIdentityRule = `-- name: CreateServiceIdentityRule :one
+INSERT INTO service_identity_rules (organization_id, kind, pattern, service_name)
+VALUES (?1, ?2, ?3, ?4)
+RETURNING id, organization_id, kind, pattern, service_name, created_at
+`
+
+type CreateServiceIdentityRuleParams struct {
+	OrganizationID int64
+	Kind           string
+	Pattern        string
+	ServiceName    string
+}
+
+func (q *Queries) CreateServiceIdentityRule(ctx context.Context, arg CreateServiceIdentityRuleParams) (ServiceIdentityRule, error) {
+	row := q.db.QueryRowContext(ctx, createServiceIdentityRule,
+		arg.OrganizationID,
+		arg.Kind,
+		arg.Pattern,
+		arg.ServiceName,
+	)
+	var i ServiceIdentityRule
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Kind,
+		&i.Pattern,
+		&i.ServiceName,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteArchivedEvents = `-- name: DeleteArchivedEvents :execrows
+DELETE FROM event_store
+WHERE organization_id = ?1
+  AND event_ts_ms < ?2
+  AND seq >= ?3
+  AND seq <= ?4
+`
+
+type DeleteArchivedEventsParams struct {
+	OrganizationID int64
+	BeforeMs       int64
+	FirstSeq       int64
+	LastSeq        int64
+}
+
+func (q *Queries) DeleteArchivedEvents(ctx context.Context, arg DeleteArchivedEventsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteArchivedEvents,
+		arg.OrganizationID,
+		arg.BeforeMs,
+		arg.FirstSeq,
+		arg.LastSeq,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteEnvironmentAliasesByEnvironment = `-- name: DeleteEnvironmentAliasesByEnvironment :exec
+DELETE FROM environment_aliases
+WHERE organization_id = ?1
+  AND environment_id = ?2
+`
+
+type DeleteEnvironmentAliasesByEnvironmentParams struct {
+	OrganizationID int64
+	EnvironmentID  int64
+}
+
+func (q *Queries) DeleteEnvironmentAliasesByEnvironment(ctx context.Context, arg DeleteEnvironmentAliasesByEnvironmentParams) error {
+	_, err := q.db.ExecContext(ctx, deleteEnvironmentAliasesByEnvironment, arg.OrganizationID, arg.EnvironmentID)
+	return err
+}
+
 const deleteGitHubInstallationMapping = `-- name: DeleteGitHubInstallationMapping :execrows
 DELETE FROM github_installation_mappings
 WHERE installation_id = ?1
@@ -251,6 +474,22 @@ func (q *Queries) DeleteGitHubSetupIntent(ctx context.Context, state string) err
 	return err
 }
 
+const deleteIngestDeadLetter = `-- name: DeleteIngestDeadLetter :exec
+DELETE FROM ingest_dead_letters
+WHERE organization_id = ?1
+  AND id = ?2
+`
+
+type DeleteIngestDeadLetterParams struct {
+	OrganizationID int64
+	ID             int64
+}
+
+func (q *Queries) DeleteIngestDeadLetter(ctx context.Context, arg DeleteIngestDeadLetterParams) error {
+	_, err := q.db.ExecContext(ctx, deleteIngestDeadLetter, arg.OrganizationID, arg.ID)
+	return err
+}
+
 const deleteOrganization = `-- name: DeleteOrganization :exec
 DELETE FROM organizations
 WHERE id = ?
@@ -261,6 +500,25 @@ func (q *Queries) DeleteOrganization(ctx context.Context, id int64) error {
 	return err
 }
 
+const deleteOrganizationEnvironment = `-- name: DeleteOrganizationEnvironment :execrows
+DELETE FROM organization_environments
+WHERE organization_id = ?1
+  AND id = ?2
+`
+
+type DeleteOrganizationEnvironmentParams struct {
+	OrganizationID int64
+	ID             int64
+}
+
+func (q *Queries) DeleteOrganizationEnvironment(ctx context.Context, arg DeleteOrganizationEnvironmentParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOrganizationEnvironment, arg.OrganizationID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteOrganizationEnvironmentPriorities = `-- name: DeleteOrganizationEnvironmentPriorities :exec
 DELETE FROM organization_environment_priorities
 WHERE organization_id = ?
@@ -271,6 +529,26 @@ func (q *Queries) DeleteOrganizationEnvironmentPriorities(ctx context.Context, o
 	return err
 }
 
+const deleteOrganizationEnvironments = `-- name: DeleteOrganizationEnvironments :exec
+DELETE FROM organization_environments
+WHERE organization_id = ?1
+`
+
+func (q *Queries) DeleteOrganizationEnvironments(ctx context.Context, organizationID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteOrganizationEnvironments, organizationID)
+	return err
+}
+
+const deleteOrganizationEventPolicies = `-- name: DeleteOrganizationEventPolicies :exec
+DELETE FROM organization_event_policies
+WHERE organization_id = ?
+`
+
+func (q *Queries) DeleteOrganizationEventPolicies(ctx context.Context, organizationID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteOrganizationEventPolicies, organizationID)
+	return err
+}
+
 const deleteOrganizationMember = `-- name: DeleteOrganizationMember :exec
 DELETE FROM organization_members
 WHERE organization_id = ? AND user_id = ?
@@ -296,6 +574,22 @@ func (q *Queries) DeleteOrganizationRequiredFields(ctx context.Context, organiza
 	return err
 }
 
+const deleteServiceDependenciesByService = `-- name: DeleteServiceDependenciesByService :exec
+DELETE FROM service_dependencies
+WHERE organization_id = ?1
+  AND (service_name = ?2 OR depends_on_service_name = ?2)
+`
+
+type DeleteServiceDependenciesByServiceParams struct {
+	OrganizationID int64
+	ServiceName    string
+}
+
+func (q *Queries) DeleteServiceDependenciesByService(ctx context.Context, arg DeleteServiceDependenciesByServiceParams) error {
+	_, err := q.db.ExecContext(ctx, deleteServiceDependenciesByService, arg.OrganizationID, arg.ServiceName)
+	return err
+}
+
 const deleteServiceDependency = `-- name: DeleteServiceDependency :exec
 DELETE FROM service_dependencies
 WHERE organization_id = ?1
@@ -314,6 +608,45 @@ func (q *Queries) DeleteServiceDependency(ctx context.Context, arg DeleteService
 	return err
 }
 
+const deleteServiceIdentityAliases = `-- name: DeleteServiceIdentityAliases :exec
+DELETE FROM service_identity_aliases
+WHERE organization_id = ?1
+`
+
+func (q *Queries) DeleteServiceIdentityAliases(ctx context.Context, organizationID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteServiceIdentityAliases, organizationID)
+	return err
+}
+
+const deleteServiceIdentityRule = `-- name: DeleteServiceIdentityRule :execrows
+DELETE FROM service_identity_rules
+WHERE organization_id = ?1
+  AND id = ?2
+`
+
+type DeleteServiceIdentityRuleParams struct {
+	OrganizationID int64
+	ID             int64
+}
+
+func (q *Queries) DeleteServiceIdentityRule(ctx context.Context, arg DeleteServiceIdentityRuleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteServiceIdentityRule, arg.OrganizationID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteServiceIdentityRules = `-- name: DeleteServiceIdentityRules :exec
+DELETE FROM service_identity_rules
+WHERE organization_id = ?1
+`
+
+func (q *Queries) DeleteServiceIdentityRules(ctx context.Context, organizationID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteServiceIdentityRules, organizationID)
+	return err
+}
+
 const deleteServiceMetadataByService = `-- name: DeleteServiceMetadataByService :exec
 DELETE FROM service_metadata
 WHERE organization_id = ?1
@@ -330,6 +663,44 @@ func (q *Queries) DeleteServiceMetadataByService(ctx context.Context, arg Delete
 	return err
 }
 
+const ensureProjectorCheckpoint = `-- name: EnsureProjectorCheckpoint :exec
+INSERT INTO projector_checkpoints (name, last_seq, updated_at)
+VALUES (?, 0, ?)
+ON CONFLICT(name) DO NOTHING
+`
+
+type EnsureProjectorCheckpointParams struct {
+	Name      string
+	UpdatedAt int64
+}
+
+func (q *Queries) EnsureProjectorCheckpoint(ctx context.Context, arg EnsureProjectorCheckpointParams) error {
+	_, err := q.db.ExecContext(ctx, ensureProjectorCheckpoint, arg.Name, arg.UpdatedAt)
+	return err
+}
+
+const expireOrganizationIngestCredential = `-- name: ExpireOrganizationIngestCredential :execrows
+UPDATE organization_ingest_credentials
+SET expires_at = ?1
+WHERE organization_id = ?2
+  AND id = ?3
+  AND revoked_at IS NULL
+`
+
+type ExpireOrganizationIngestCredentialParams struct {
+	ExpiresAt      sql.NullInt64
+	OrganizationID int64
+	ID             int64
+}
+
+func (q *Queries) ExpireOrganizationIngestCredential(ctx context.Context, arg ExpireOrganizationIngestCredentialParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, expireOrganizationIngestCredential, arg.ExpiresAt, arg.OrganizationID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getDefaultOrganization = `-- name: GetDefaultOrganization :one
 SELECT id, name, auth_token, webhook_secret, enabled, created_at, updated_at, join_code
 FROM organizations
@@ -353,6 +724,113 @@ func (q *Queries) GetDefaultOrganization(ctx context.Context) (Organization, err
 	return i, err
 }
 
+const getEnvironmentAlias = `-- name: GetEnvironmentAlias :one
+SELECT organization_id, alias, environment_id, environment
+FROM environment_aliases
+WHERE organization_id = ?1
+  AND alias = ?2
+`
+
+type GetEnvironmentAliasParams struct {
+	OrganizationID int64
+	Alias          string
+}
+
+func (q *Queries) GetEnvironmentAlias(ctx context.Context, arg GetEnvironmentAliasParams) (EnvironmentAlias, error) {
+	row := q.db.QueryRowContext(ctx, getEnvironmentAlias, arg.OrganizationID, arg.Alias)
+	var i EnvironmentAlias
+	err := row.Scan(
+		&i.OrganizationID,
+		&i.Alias,
+		&i.EnvironmentID,
+		&i.Environment,
+	)
+	return i, err
+}
+
+const getEventArchiveCutoff = `-- name: GetEventArchiveCutoff :one
+SELECT CAST(COALESCE(MAX(archived_before_ms), 0) AS INTEGER) AS archived_before_ms
+FROM event_archives
+WHERE organization_id = ?
+`
+
+func (q *Queries) GetEventArchiveCutoff(ctx context.Context, organizationID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getEventArchiveCutoff, organizationID)
+	var archived_before_ms int64
+	err := row.Scan(&archived_before_ms)
+	return archived_before_ms, err
+}
+
+const getEventStoreEvent = `-- name: GetEventStoreEvent :one
+SELECT seq, organization_id, event_id, event_type, event_source, event_timestamp, subject_id, subject_source, subject_type, chain_id, raw_event_json, ingested_at, event_ts_ms, service_name, environment, artifact_id, outcome, actor_name, raw_event_compressed, environment_id, content_service, content_service_id, pipeline_run_id, run_url, link_url, duration_seconds
+FROM event_store
+WHERE organization_id = ?1
+  AND seq = ?2
+`
+
+type GetEventStoreEventParams struct {
+	OrganizationID int64
+	Seq            int64
+}
+
+func (q *Queries) GetEventStoreEvent(ctx context.Context, arg GetEventStoreEventParams) (EventStore, error) {
+	row := q.db.QueryRowContext(ctx, getEventStoreEvent, arg.OrganizationID, arg.Seq)
+	var i EventStore
+	err := row.Scan(
+		&i.Seq,
+		&i.OrganizationID,
+		&i.EventID,
+		&i.EventType,
+		&i.EventSource,
+		&i.EventTimestamp,
+		&i.SubjectID,
+		&i.SubjectSource,
+		&i.SubjectType,
+		&i.ChainID,
+		&i.RawEventJson,
+		&i.IngestedAt,
+		&i.EventTsMs,
+		&i.ServiceName,
+		&i.Environment,
+		&i.ArtifactID,
+		&i.Outcome,
+		&i.ActorName,
+		&i.RawEventCompressed,
+		&i.EnvironmentID,
+		&i.ContentService,
+		&i.ContentServiceID,
+		&i.PipelineRunID,
+		&i.RunUrl,
+		&i.LinkUrl,
+		&i.DurationSeconds,
+	)
+	return i, err
+}
+
+const getEventStoreHeadSeq = `-- name: GetEventStoreHeadSeq :one
+SELECT CAST(COALESCE(MAX(seq), 0) AS INTEGER) AS head_seq FROM event_store
+`
+
+func (q *Queries) GetEventStoreHeadSeq(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getEventStoreHeadSeq)
+	var head_seq int64
+	err := row.Scan(&head_seq)
+	return head_seq, err
+}
+
+const getGitHubInstallationMappingOrganizationID = `-- name: GetGitHubInstallationMappingOrganizationID :one
+SELECT organization_id
+FROM github_installation_mappings
+WHERE installation_id = ?1
+`
+
+func (q *Queries) GetGitHubInstallationMappingOrganizationID(ctx context.Context, installationID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getGitHubInstallationMappingOrganizationID, installationID)
+	var organization_id int64
+	err := row.Scan(&organization_id)
+	return organization_id, err
+}
+
 const getGitHubSetupIntentByState = `-- name: GetGitHubSetupIntentByState :one
 SELECT
   state,
@@ -386,6 +864,64 @@ func (q *Queries) GetGitHubSetupIntentByState(ctx context.Context, state string)
 	return i, err
 }
 
+const getGitLabProjectMappingOrganizationID = `-- name: GetGitLabProjectMappingOrganizationID :one
+SELECT organization_id
+FROM gitlab_project_mappings
+WHERE project_id = ?1
+`
+
+func (q *Queries) GetGitLabProjectMappingOrganizationID(ctx context.Context, projectID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getGitLabProjectMappingOrganizationID, projectID)
+	var organization_id int64
+	err := row.Scan(&organization_id)
+	return organization_id, err
+}
+
+const getIngestDeadLetter = `-- name: GetIngestDeadLetter :one
+SELECT id, organization_id, reason, detail, headers_json, body, body_size, truncated, received_at, replayed_at, replay_result
+FROM ingest_dead_letters
+WHERE organization_id = ?1
+  AND id = ?2
+`
+
+type GetIngestDeadLetterParams struct {
+	OrganizationID int64
+	ID             int64
+}
+
+type GetIngestDeadLetterRow struct {
+	ID             int64
+	OrganizationID int64
+	Reason         string
+	Detail         string
+	HeadersJson    string
+	Body           []byte
+	BodySize       int64
+	Truncated      int64
+	ReceivedAt     int64
+	ReplayedAt     sql.NullInt64
+	ReplayResult   string
+}
+
+func (q *Queries) GetIngestDeadLetter(ctx context.Context, arg GetIngestDeadLetterParams) (GetIngestDeadLetterRow, error) {
+	row := q.db.QueryRowContext(ctx, getIngestDeadLetter, arg.OrganizationID, arg.ID)
+	var i GetIngestDeadLetterRow
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Reason,
+		&i.Detail,
+		&i.HeadersJson,
+		&i.Body,
+		&i.BodySize,
+		&i.Truncated,
+		&i.ReceivedAt,
+		&i.ReplayedAt,
+		&i.ReplayResult,
+	)
+	return i, err
+}
+
 const getOrganizationByAuthToken = `-- name: GetOrganizationByAuthToken :one
 SELECT id, name, auth_token, webhook_secret, enabled, created_at, updated_at, join_code
 FROM organizations
@@ -484,10 +1020,41 @@ func (q *Queries) GetOrganizationByID(ctx context.Context, id int64) (Organizati
 	return i, err
 }
 
-const getOrganizationByJoinCode = `-- name: GetOrganizationByJoinCode :one
-SELECT id, name, auth_token, webhook_secret, enabled, created_at, updated_at, join_code
-FROM organizations
-WHERE join_code = ?
+const getOrganizationByIngestCredentialToken = `-- name: GetOrganizationByIngestCredentialToken :one
+SELECT o.id, o.name, o.auth_token, o.webhook_secret, o.enabled, o.created_at, o.updated_at, o.join_code
+FROM organization_ingest_credentials c
+JOIN organizations o ON o.id = c.organization_id
+WHERE c.auth_token = ?1
+  AND c.revoked_at IS NULL
+  AND (c.expires_at IS NULL OR c.expires_at > ?2)
+LIMIT 1
+`
+
+type GetOrganizationByIngestCredentialTokenParams struct {
+	AuthToken string
+	Now       sql.NullInt64
+}
+
+func (q *Queries) GetOrganizationByIngestCredentialToken(ctx context.Context, arg GetOrganizationByIngestCredentialTokenParams) (Organization, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationByIngestCredentialToken, arg.AuthToken, arg.Now)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.AuthToken,
+		&i.WebhookSecret,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.JoinCode,
+	)
+	return i, err
+}
+
+const getOrganizationByJoinCode = `-- name: GetOrganizationByJoinCode :one
+SELECT id, name, auth_token, webhook_secret, enabled, created_at, updated_at, join_code
+FROM organizations
+WHERE join_code = ?
 LIMIT 1
 `
 
@@ -507,6 +1074,74 @@ func (q *Queries) GetOrganizationByJoinCode(ctx context.Context, joinCode sql.Nu
 	return i, err
 }
 
+const getOrganizationByName = `-- name: GetOrganizationByName :one
+SELECT id, name, auth_token, webhook_secret, enabled, created_at, updated_at, join_code
+FROM organizations
+WHERE name = ?
+LIMIT 1
+`
+
+func (q *Queries) GetOrganizationByName(ctx context.Context, name string) (Organization, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationByName, name)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.AuthToken,
+		&i.WebhookSecret,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.JoinCode,
+	)
+	return i, err
+}
+
+const getOrganizationEnvironment = `-- name: GetOrganizationEnvironment :one
+SELECT id, organization_id, name, tier, color, protected, sort_order, url, source, changed_at_ms, created_at, updated_at
+FROM organization_environments
+WHERE organization_id = ?1
+  AND name = ?2
+`
+
+type GetOrganizationEnvironmentParams struct {
+	OrganizationID int64
+	Name           string
+}
+
+func (q *Queries) GetOrganizationEnvironment(ctx context.Context, arg GetOrganizationEnvironmentParams) (OrganizationEnvironment, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationEnvironment, arg.OrganizationID, arg.Name)
+	var i OrganizationEnvironment
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.Tier,
+		&i.Color,
+		&i.Protected,
+		&i.SortOrder,
+		&i.Url,
+		&i.Source,
+		&i.ChangedAtMs,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getOrganizationLatestEventSeq = `-- name: GetOrganizationLatestEventSeq :one
+SELECT CAST(COALESCE(MAX(seq), 0) AS INTEGER) AS seq
+FROM event_store
+WHERE organization_id = ?1
+`
+
+func (q *Queries) GetOrganizationLatestEventSeq(ctx context.Context, organizationID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationLatestEventSeq, organizationID)
+	var seq int64
+	err := row.Scan(&seq)
+	return seq, err
+}
+
 const getOrganizationMemberRole = `-- name: GetOrganizationMemberRole :one
 SELECT role
 FROM organization_members
@@ -526,6 +1161,25 @@ func (q *Queries) GetOrganizationMemberRole(ctx context.Context, arg GetOrganiza
 	return role, err
 }
 
+const getOrganizationPreference = `-- name: GetOrganizationPreference :one
+SELECT preference_value
+FROM organization_preferences
+WHERE organization_id = ?
+  AND preference_key = ?
+`
+
+type GetOrganizationPreferenceParams struct {
+	OrganizationID int64
+	PreferenceKey  string
+}
+
+func (q *Queries) GetOrganizationPreference(ctx context.Context, arg GetOrganizationPreferenceParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationPreference, arg.OrganizationID, arg.PreferenceKey)
+	var preference_value string
+	err := row.Scan(&preference_value)
+	return preference_value, err
+}
+
 const getOrganizationRenderVersion = `-- name: GetOrganizationRenderVersion :one
 SELECT COALESCE(MAX(version_value), 0) AS version
 FROM (
@@ -571,6 +1225,12 @@ FROM (
 
   UNION ALL
 
+  SELECT COALESCE(MAX(CAST(strftime('%s', updated_at) AS INTEGER)), 0) AS version_value
+  FROM organization_event_policies
+  WHERE organization_event_policies.organization_id = ?1
+
+  UNION ALL
+
   SELECT COALESCE(MAX(CAST(strftime('%s', created_at) AS INTEGER)), 0) AS version_value
   FROM service_dependencies
   WHERE service_dependencies.organization_id = ?1
@@ -584,19 +1244,49 @@ func (q *Queries) GetOrganizationRenderVersion(ctx context.Context, orgID int64)
 	return version, err
 }
 
+const getProjectorCheckpoint = `-- name: GetProjectorCheckpoint :one
+SELECT last_seq FROM projector_checkpoints WHERE name = ?
+`
+
+func (q *Queries) GetProjectorCheckpoint(ctx context.Context, name string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getProjectorCheckpoint, name)
+	var last_seq int64
+	err := row.Scan(&last_seq)
+	return last_seq, err
+}
+
+const getServiceIdentityAlias = `-- name: GetServiceIdentityAlias :one
+SELECT service_name
+FROM service_identity_aliases
+WHERE organization_id = ?1
+  AND alias = ?2
+`
+
+type GetServiceIdentityAliasParams struct {
+	OrganizationID int64
+	Alias          string
+}
+
+func (q *Queries) GetServiceIdentityAlias(ctx context.Context, arg GetServiceIdentityAliasParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getServiceIdentityAlias, arg.OrganizationID, arg.Alias)
+	var service_name string
+	err := row.Scan(&service_name)
+	return service_name, err
+}
+
 const getServiceLatestFromEvents = `-- name: GetServiceLatestFromEvents :one
 SELECT
-  CASE
-    WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-    ELSE es.subject_id
-  END AS service_name,
+  COALESCE(sia.service_name, es.service_name) AS service_name,
   es.subject_id AS raw_subject_id,
   es.event_timestamp AS last_deploy_at,
   'cdevents' AS integration_type
 FROM event_store es
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
 WHERE es.subject_type = 'service'
   AND es.organization_id = ?1
-  AND (es.subject_id = ?2 OR substr(es.subject_id, instr(es.subject_id, '/') + 1) = ?2)
+  AND (es.subject_id = ?2 OR COALESCE(sia.service_name, es.service_name) = ?2)
 ORDER BY es.event_ts_ms DESC, es.seq DESC
 LIMIT 1
 `
@@ -607,7 +1297,7 @@ type GetServiceLatestFromEventsParams struct {
 }
 
 type GetServiceLatestFromEventsRow struct {
-	ServiceName     interface{}
+	ServiceName     string
 	RawSubjectID    string
 	LastDeployAt    string
 	IntegrationType string
@@ -676,41 +1366,241 @@ func (q *Queries) GetUserByID(ctx context.Context, id int64) (User, error) {
 	return i, err
 }
 
-const listDeploymentHistoryByServiceFromEvents = `-- name: ListDeploymentHistoryByServiceFromEvents :many
+const incrementIngestHourlyStat = `-- name: IncrementIngestHourlyStat :exec
+INSERT INTO ingest_hourly_stats (organization_id, bucket_start, outcome, reason, event_count)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(organization_id, bucket_start, outcome, reason) DO UPDATE SET
+  event_count = ingest_hourly_stats.event_count + excluded.event_count
+`
+
+type IncrementIngestHourlyStatParams struct {
+	OrganizationID int64
+	BucketStart    int64
+	Outcome        string
+	Reason         string
+	EventCount     int64
+}
+
+func (q *Queries) IncrementIngestHourlyStat(ctx context.Context, arg IncrementIngestHourlyStatParams) error {
+	_, err := q.db.ExecContext(ctx, incrementIngestHourlyStat,
+		arg.OrganizationID,
+		arg.BucketStart,
+		arg.Outcome,
+		arg.Reason,
+		arg.EventCount,
+	)
+	return err
+}
+
+const insertEventArchive = `-- name: InsertEventArchive :one
+INSERT INTO event_archives (organization_id, path, first_seq, last_seq, event_count, archived_before_ms, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, organization_id, path, first_seq, last_seq, event_count, archived_before_ms, created_at
+`
+
+type InsertEventArchiveParams struct {
+	OrganizationID   int64
+	Path             string
+	FirstSeq         int64
+	LastSeq          int64
+	EventCount       int64
+	ArchivedBeforeMs int64
+	CreatedAt        int64
+}
+
+func (q *Queries) InsertEventArchive(ctx context.Context, arg InsertEventArchiveParams) (EventArchive, error) {
+	row := q.db.QueryRowContext(ctx, insertEventArchive,
+		arg.OrganizationID,
+		arg.Path,
+		arg.FirstSeq,
+		arg.LastSeq,
+		arg.EventCount,
+		arg.ArchivedBeforeMs,
+		arg.CreatedAt,
+	)
+	var i EventArchive
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Path,
+		&i.FirstSeq,
+		&i.LastSeq,
+		&i.EventCount,
+		&i.ArchivedBeforeMs,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertIngestDeadLetter = `-- name: InsertIngestDeadLetter :exec
+INSERT INTO ingest_dead_letters (
+  organization_id,
+  reason,
+  detail,
+  headers_json,
+  body,
+  body_size,
+  truncated,
+  received_at
+)
+VALUES (
+  ?1,
+  ?2,
+  ?3,
+  ?4,
+  ?5,
+  ?6,
+  ?7,
+  ?8
+)
+`
+
+type InsertIngestDeadLetterParams struct {
+	OrganizationID int64
+	Reason         string
+	Detail         string
+	HeadersJson    string
+	Body           []byte
+	BodySize       int64
+	Truncated      int64
+	ReceivedAt     int64
+}
+
+func (q *Queries) InsertIngestDeadLetter(ctx context.Context, arg InsertIngestDeadLetterParams) error {
+	_, err := q.db.ExecContext(ctx, insertIngestDeadLetter,
+		arg.OrganizationID,
+		arg.Reason,
+		arg.Detail,
+		arg.HeadersJson,
+		arg.Body,
+		arg.BodySize,
+		arg.Truncated,
+		arg.ReceivedAt,
+	)
+	return err
+}
+
+const insertIngestSeenSignature = `-- name: InsertIngestSeenSignature :execrows
+INSERT INTO ingest_seen_signatures (organization_id, signature, seen_at, expires_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT(organization_id, signature) DO NOTHING
+`
+
+type InsertIngestSeenSignatureParams struct {
+	OrganizationID int64
+	Signature      string
+	SeenAt         int64
+	ExpiresAt      int64
+}
+
+func (q *Queries) InsertIngestSeenSignature(ctx context.Context, arg InsertIngestSeenSignatureParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, insertIngestSeenSignature,
+		arg.OrganizationID,
+		arg.Signature,
+		arg.SeenAt,
+		arg.ExpiresAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const insertOrganizationIngestCredential = `-- name: InsertOrganizationIngestCredential :one
+INSERT INTO organization_ingest_credentials (organization_id, label, auth_token, webhook_secret, created_at, expires_at)
+VALUES (?, ?, ?, ?, ?, ?)
+RETURNING id, organization_id, label, auth_token, webhook_secret, created_at, expires_at, revoked_at, last_used_at
+`
+
+type InsertOrganizationIngestCredentialParams struct {
+	OrganizationID int64
+	Label          string
+	AuthToken      string
+	WebhookSecret  string
+	CreatedAt      int64
+	ExpiresAt      sql.NullInt64
+}
+
+func (q *Queries) InsertOrganizationIngestCredential(ctx context.Context, arg InsertOrganizationIngestCredentialParams) (OrganizationIngestCredential, error) {
+	row := q.db.QueryRowContext(ctx, insertOrganizationIngestCredential,
+		arg.OrganizationID,
+		arg.Label,
+		arg.AuthToken,
+		arg.WebhookSecret,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+	)
+	var i OrganizationIngestCredential
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Label,
+		&i.AuthToken,
+		&i.WebhookSecret,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const listChainEvents = `-- name: ListChainEvents :many
 SELECT
-  es.event_timestamp AS deployed_at,
-  COALESCE(json_extract(es.raw_event_json, '$.subject.content.artifactId'), '') AS release_ref,
-  COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') AS environment
+  es.seq,
+  es.event_id,
+  es.event_type,
+  es.event_source,
+  es.event_ts_ms,
+  es.subject_id,
+  es.subject_type,
+  es.ingested_at,
+  es.link_url AS url
 FROM event_store es
-WHERE es.subject_type = 'service'
-  AND es.organization_id = ?1
-  AND (es.subject_id = ?2 OR substr(es.subject_id, instr(es.subject_id, '/') + 1) = ?2)
-ORDER BY es.event_ts_ms DESC, es.seq DESC
+WHERE es.organization_id = ?1
+  AND es.chain_id = ?2
+ORDER BY es.event_ts_ms, es.seq
 LIMIT ?3
 `
 
-type ListDeploymentHistoryByServiceFromEventsParams struct {
+type ListChainEventsParams struct {
 	OrganizationID int64
-	Service        string
+	ChainID        sql.NullString
 	Limit          int64
 }
 
-type ListDeploymentHistoryByServiceFromEventsRow struct {
-	DeployedAt  string
-	ReleaseRef  interface{}
-	Environment interface{}
+type ListChainEventsRow struct {
+	Seq         int64
+	EventID     string
+	EventType   string
+	EventSource string
+	EventTsMs   int64
+	SubjectID   string
+	SubjectType string
+	IngestedAt  time.Time
+	Url         string
 }
 
-func (q *Queries) ListDeploymentHistoryByServiceFromEvents(ctx context.Context, arg ListDeploymentHistoryByServiceFromEventsParams) ([]ListDeploymentHistoryByServiceFromEventsRow, error) {
-	rows, err := q.db.QueryContext(ctx, listDeploymentHistoryByServiceFromEvents, arg.OrganizationID, arg.Service, arg.Limit)
+func (q *Queries) ListChainEvents(ctx context.Context, arg ListChainEventsParams) ([]ListChainEventsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listChainEvents, arg.OrganizationID, arg.ChainID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListDeploymentHistoryByServiceFromEventsRow
+	var items []ListChainEventsRow
 	for rows.Next() {
-		var i ListDeploymentHistoryByServiceFromEventsRow
-		if err := rows.Scan(&i.DeployedAt, &i.ReleaseRef, &i.Environment); err != nil {
+		var i ListChainEventsRow
+		if err := rows.Scan(
+			&i.Seq,
+			&i.EventID,
+			&i.EventType,
+			&i.EventSource,
+			&i.EventTsMs,
+			&i.SubjectID,
+			&i.SubjectType,
+			&i.IngestedAt,
+			&i.Url,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -724,58 +1614,44 @@ func (q *Queries) ListDeploymentHistoryByServiceFromEvents(ctx context.Context,
 	return items, nil
 }
 
-const listDeploymentsFromEvents = `-- name: ListDeploymentsFromEvents :many
+const listDeploymentEnvironmentCounts = `-- name: ListDeploymentEnvironmentCounts :many
 SELECT
-  es.event_timestamp AS deployed_at,
-  CASE
-    WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-    ELSE es.subject_id
-  END AS service,
-  COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') AS environment,
-  CASE
-    WHEN es.event_type LIKE 'dev.cdevents.service.deployed.%' THEN 'success'
-    WHEN es.event_type LIKE 'dev.cdevents.service.upgraded.%' THEN 'success'
-    WHEN es.event_type LIKE 'dev.cdevents.service.published.%' THEN 'success'
-    WHEN es.event_type LIKE 'dev.cdevents.service.rolledback.%' THEN 'error'
-    WHEN es.event_type LIKE 'dev.cdevents.service.removed.%' THEN 'error'
-    ELSE 'queued'
-  END AS status
+  COALESCE(ea.environment, es.environment) AS environment,
+  CAST(SUM(CASE WHEN es.event_ts_ms >= ?1 THEN 1 ELSE 0 END) AS INTEGER) AS recent_count,
+  COUNT(*) AS total_count
 FROM event_store es
-WHERE es.subject_type = 'service'
-  AND es.organization_id = ?1
-  AND (?2 = '' OR ?2 = 'all' OR json_extract(es.raw_event_json, '$.subject.content.environment.id') = ?2)
-  AND (?3 = '' OR ?3 = 'all' OR es.subject_id = ?3 OR substr(es.subject_id, instr(es.subject_id, '/') + 1) = ?3)
-ORDER BY es.event_ts_ms DESC, es.seq DESC
+LEFT JOIN environment_aliases ea
+  ON ea.organization_id = es.organization_id
+  AND ea.alias = es.environment
+WHERE es.organization_id = ?2
+  AND es.subject_type = 'service'
+  AND es.event_ts_ms >= ?3
+GROUP BY COALESCE(ea.environment, es.environment)
+ORDER BY COALESCE(ea.environment, es.environment)
 `
 
-type ListDeploymentsFromEventsParams struct {
+type ListDeploymentEnvironmentCountsParams struct {
+	RecentFromMs   int64
 	OrganizationID int64
-	Env            interface{}
-	Service        interface{}
+	FromMs         int64
 }
 
-type ListDeploymentsFromEventsRow struct {
-	DeployedAt  string
-	Service     interface{}
-	Environment interface{}
-	Status      string
+type ListDeploymentEnvironmentCountsRow struct {
+	Environment string
+	RecentCount int64
+	TotalCount  int64
 }
 
-func (q *Queries) ListDeploymentsFromEvents(ctx context.Context, arg ListDeploymentsFromEventsParams) ([]ListDeploymentsFromEventsRow, error) {
-	rows, err := q.db.QueryContext(ctx, listDeploymentsFromEvents, arg.OrganizationID, arg.Env, arg.Service)
+func (q *Queries) ListDeploymentEnvironmentCounts(ctx context.Context, arg ListDeploymentEnvironmentCountsParams) ([]ListDeploymentEnvironmentCountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listDeploymentEnvironmentCounts, arg.RecentFromMs, arg.OrganizationID, arg.FromMs)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListDeploymentsFromEventsRow
+	var items []ListDeploymentEnvironmentCountsRow
 	for rows.Next() {
-		var i ListDeploymentsFromEventsRow
-		if err := rows.Scan(
-			&i.DeployedAt,
-			&i.Service,
-			&i.Environment,
-			&i.Status,
-		); err != nil {
+		var i ListDeploymentEnvironmentCountsRow
+		if err := rows.Scan(&i.Environment, &i.RecentCount, &i.TotalCount); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -789,27 +1665,554 @@ func (q *Queries) ListDeploymentsFromEvents(ctx context.Context, arg ListDeploym
 	return items, nil
 }
 
-const listDistinctServiceEnvironmentsFromEvents = `-- name: ListDistinctServiceEnvironmentsFromEvents :many
-SELECT DISTINCT COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') AS environment
+const listDeploymentHistoryByServiceFromEvents = `-- name: ListDeploymentHistoryByServiceFromEvents :many
+SELECT
+  es.event_timestamp AS deployed_at,
+  es.artifact_id AS release_ref,
+  COALESCE(ea.environment, es.environment) AS environment,
+  COALESCE(es.chain_id, '') AS chain_id
 FROM event_store es
-WHERE es.organization_id = ?1
-  AND es.subject_type = 'service'
-ORDER BY environment
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
+LEFT JOIN environment_aliases ea
+  ON ea.organization_id = es.organization_id
+  AND ea.alias = es.environment
+WHERE es.subject_type = 'service'
+  AND es.organization_id = ?1
+  AND (es.subject_id = ?2 OR COALESCE(sia.service_name, es.service_name) = ?2)
+ORDER BY es.event_ts_ms DESC, es.seq DESC
+LIMIT ?3
 `
 
-func (q *Queries) ListDistinctServiceEnvironmentsFromEvents(ctx context.Context, organizationID int64) ([]interface{}, error) {
-	rows, err := q.db.QueryContext(ctx, listDistinctServiceEnvironmentsFromEvents, organizationID)
+type ListDeploymentHistoryByServiceFromEventsParams struct {
+	OrganizationID int64
+	Service        string
+	Limit          int64
+}
+
+type ListDeploymentHistoryByServiceFromEventsRow struct {
+	DeployedAt  string
+	ReleaseRef  string
+	Environment string
+	ChainID     string
+}
+
+func (q *Queries) ListDeploymentHistoryByServiceFromEvents(ctx context.Context, arg ListDeploymentHistoryByServiceFromEventsParams) ([]ListDeploymentHistoryByServiceFromEventsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listDeploymentHistoryByServiceFromEvents, arg.OrganizationID, arg.Service, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []interface{}
+	var items []ListDeploymentHistoryByServiceFromEventsRow
 	for rows.Next() {
-		var environment interface{}
-		if err := rows.Scan(&environment); err != nil {
+		var i ListDeploymentHistoryByServiceFromEventsRow
+		if err := rows.Scan(
+			&i.DeployedAt,
+			&i.ReleaseRef,
+			&i.Environment,
+			&i.ChainID,
+		); err != nil {
 			return nil, err
 		}
-		items = append(items, environment)
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeploymentServices = `-- name: ListDeploymentServices :many
+SELECT DISTINCT service_name
+FROM service_env_state
+WHERE organization_id = ?1
+ORDER BY service_name
+`
+
+func (q *Queries) ListDeploymentServices(ctx context.Context, organizationID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listDeploymentServices, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var service_name string
+		if err := rows.Scan(&service_name); err != nil {
+			return nil, err
+		}
+		items = append(items, service_name)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeploymentsFromEvents = `-- name: ListDeploymentsFromEvents :many
+SELECT
+  es.seq,
+  es.event_ts_ms,
+  es.event_timestamp AS deployed_at,
+  COALESCE(sia.service_name, es.service_name) AS service,
+  COALESCE(ea.environment, es.environment) AS environment,
+  CAST(CASE es.outcome
+    WHEN 'synced' THEN 'success'
+    WHEN 'warning' THEN 'error'
+    WHEN 'out-of-sync' THEN 'error'
+    ELSE 'queued'
+  END AS TEXT) AS status
+FROM event_store es
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
+LEFT JOIN environment_aliases ea
+  ON ea.organization_id = es.organization_id
+  AND ea.alias = es.environment
+WHERE es.subject_type = 'service'
+  AND es.organization_id = ?1
+  AND (?2 = '' OR ?2 = 'all' OR COALESCE(ea.environment, es.environment) = ?2)
+  AND (?3 = '' OR ?3 = 'all' OR es.subject_id = ?3 OR COALESCE(sia.service_name, es.service_name) = ?3)
+  AND (
+    ?4 = '' OR ?4 = 'all'
+    OR (?4 = 'success' AND es.outcome = 'synced')
+    OR (?4 = 'error' AND es.outcome IN ('warning', 'out-of-sync'))
+    OR (?4 = 'queued' AND es.outcome NOT IN ('synced', 'warning', 'out-of-sync'))
+  )
+  AND (CAST(?5 AS INTEGER) = 0 OR es.event_ts_ms >= ?5)
+  AND (CAST(?6 AS INTEGER) = 0 OR es.event_ts_ms < ?6)
+  AND (
+    CAST(?7 AS INTEGER) = 0
+    OR es.event_ts_ms < ?8
+    OR (es.event_ts_ms = ?8 AND es.seq < ?7)
+  )
+ORDER BY es.event_ts_ms DESC, es.seq DESC
+LIMIT ?9
+`
+
+type ListDeploymentsFromEventsParams struct {
+	OrganizationID int64
+	Env            interface{}
+	Service        interface{}
+	Status         interface{}
+	FromMs         int64
+	ToMs           int64
+	BeforeSeq      int64
+	BeforeTsMs     int64
+	Limit          int64
+}
+
+type ListDeploymentsFromEventsRow struct {
+	Seq         int64
+	EventTsMs   int64
+	DeployedAt  string
+	Service     string
+	Environment string
+	Status      string
+}
+
+// One page of deployments, newest first. A page continues below the
+// (before_ts_ms, before_seq) position of the previous page's last row;
+// before_seq = 0 starts at the newest deployment.
+func (q *Queries) ListDeploymentsFromEvents(ctx context.Context, arg ListDeploymentsFromEventsParams) ([]ListDeploymentsFromEventsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listDeploymentsFromEvents,
+		arg.OrganizationID,
+		arg.Env,
+		arg.Service,
+		arg.Status,
+		arg.FromMs,
+		arg.ToMs,
+		arg.BeforeSeq,
+		arg.BeforeTsMs,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDeploymentsFromEventsRow
+	for rows.Next() {
+		var i ListDeploymentsFromEventsRow
+		if err := rows.Scan(
+			&i.Seq,
+			&i.EventTsMs,
+			&i.DeployedAt,
+			&i.Service,
+			&i.Environment,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDistinctServiceEnvironmentsFromEvents = `-- name: ListDistinctServiceEnvironmentsFromEvents :many
+SELECT DISTINCT COALESCE(ea.environment, es.environment) AS environment
+FROM event_store es
+LEFT JOIN environment_aliases ea
+  ON ea.organization_id = es.organization_id
+  AND ea.alias = es.environment
+WHERE es.organization_id = ?1
+  AND es.subject_type = 'service'
+ORDER BY COALESCE(ea.environment, es.environment)
+`
+
+func (q *Queries) ListDistinctServiceEnvironmentsFromEvents(ctx context.Context, organizationID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listDistinctServiceEnvironmentsFromEvents, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var environment string
+		if err := rows.Scan(&environment); err != nil {
+			return nil, err
+		}
+		items = append(items, environment)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEnvironmentAliases = `-- name: ListEnvironmentAliases :many
+SELECT organization_id, alias, environment_id, environment
+FROM environment_aliases
+WHERE organization_id = ?1
+ORDER BY environment, alias
+`
+
+func (q *Queries) ListEnvironmentAliases(ctx context.Context, organizationID int64) ([]EnvironmentAlias, error) {
+	rows, err := q.db.QueryContext(ctx, listEnvironmentAliases, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EnvironmentAlias
+	for rows.Next() {
+		var i EnvironmentAlias
+		if err := rows.Scan(
+			&i.OrganizationID,
+			&i.Alias,
+			&i.EnvironmentID,
+			&i.Environment,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEventArchives = `-- name: ListEventArchives :many
+SELECT id, organization_id, path, first_seq, last_seq, event_count, archived_before_ms, created_at
+FROM event_archives
+WHERE organization_id = ?
+ORDER BY created_at DESC, id DESC
+LIMIT ?
+`
+
+type ListEventArchivesParams struct {
+	OrganizationID int64
+	Limit          int64
+}
+
+func (q *Queries) ListEventArchives(ctx context.Context, arg ListEventArchivesParams) ([]EventArchive, error) {
+	rows, err := q.db.QueryContext(ctx, listEventArchives, arg.OrganizationID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EventArchive
+	for rows.Next() {
+		var i EventArchive
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.Path,
+			&i.FirstSeq,
+			&i.LastSeq,
+			&i.EventCount,
+			&i.ArchivedBeforeMs,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEventContributions = `-- name: ListEventContributions :many
+SELECT scl.service_name, scl.environment
+FROM service_change_links scl
+WHERE scl.organization_id = ?1
+  AND scl.event_seq = ?2
+UNION
+SELECT
+  COALESCE(sia.service_name, es.service_name) AS service_name,
+  COALESCE(ea.environment, es.environment) AS environment
+FROM event_store es
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
+LEFT JOIN environment_aliases ea
+  ON ea.organization_id = es.organization_id
+  AND ea.alias = es.environment
+WHERE es.organization_id = ?1
+  AND es.seq = ?2
+  AND es.subject_type = 'service'
+ORDER BY 1, 2
+`
+
+type ListEventContributionsParams struct {
+	OrganizationID int64
+	Seq            int64
+}
+
+type ListEventContributionsRow struct {
+	ServiceName string
+	Environment string
+}
+
+func (q *Queries) ListEventContributions(ctx context.Context, arg ListEventContributionsParams) ([]ListEventContributionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listEventContributions, arg.OrganizationID, arg.Seq)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEventContributionsRow
+	for rows.Next() {
+		var i ListEventContributionsRow
+		if err := rows.Scan(&i.ServiceName, &i.Environment); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEventRetentionPolicies = `-- name: ListEventRetentionPolicies :many
+SELECT organization_id, CAST(preference_value AS INTEGER) AS retention_days
+FROM organization_preferences
+WHERE preference_key = 'event_retention_days'
+  AND CAST(preference_value AS INTEGER) > 0
+ORDER BY organization_id
+`
+
+type ListEventRetentionPoliciesRow struct {
+	OrganizationID int64
+	RetentionDays  int64
+}
+
+func (q *Queries) ListEventRetentionPolicies(ctx context.Context) ([]ListEventRetentionPoliciesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listEventRetentionPolicies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEventRetentionPoliciesRow
+	for rows.Next() {
+		var i ListEventRetentionPoliciesRow
+		if err := rows.Scan(&i.OrganizationID, &i.RetentionDays); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEventStoreEvents = `-- name: ListEventStoreEvents :many
+SELECT seq, event_id, event_type, event_source, event_ts_ms, subject_id, subject_type, chain_id, ingested_at
+FROM event_store
+WHERE organization_id = ?1
+  AND (CAST(?2 AS INTEGER) = 0 OR seq < ?2)
+  AND (CAST(?3 AS TEXT) = '' OR event_type = ?3)
+  AND (CAST(?4 AS TEXT) = '' OR subject_type = ?4)
+  AND (CAST(?5 AS TEXT) = '' OR subject_id = ?5)
+  AND (CAST(?6 AS TEXT) = '' OR event_source = ?6)
+  AND (CAST(?7 AS TEXT) = '' OR chain_id = ?7)
+  AND (CAST(?8 AS INTEGER) = 0 OR event_ts_ms >= ?8)
+  AND (CAST(?9 AS INTEGER) = 0 OR event_ts_ms < ?9)
+ORDER BY seq DESC
+LIMIT ?10
+`
+
+type ListEventStoreEventsParams struct {
+	OrganizationID int64
+	BeforeSeq      int64
+	EventType      string
+	SubjectType    string
+	SubjectID      string
+	EventSource    string
+	ChainID        string
+	FromMs         int64
+	ToMs           int64
+	Limit          int64
+}
+
+type ListEventStoreEventsRow struct {
+	Seq         int64
+	EventID     string
+	EventType   string
+	EventSource string
+	EventTsMs   int64
+	SubjectID   string
+	SubjectType string
+	ChainID     sql.NullString
+	IngestedAt  time.Time
+}
+
+func (q *Queries) ListEventStoreEvents(ctx context.Context, arg ListEventStoreEventsParams) ([]ListEventStoreEventsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listEventStoreEvents,
+		arg.OrganizationID,
+		arg.BeforeSeq,
+		arg.EventType,
+		arg.SubjectType,
+		arg.SubjectID,
+		arg.EventSource,
+		arg.ChainID,
+		arg.FromMs,
+		arg.ToMs,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEventStoreEventsRow
+	for rows.Next() {
+		var i ListEventStoreEventsRow
+		if err := rows.Scan(
+			&i.Seq,
+			&i.EventID,
+			&i.EventType,
+			&i.EventSource,
+			&i.EventTsMs,
+			&i.SubjectID,
+			&i.SubjectType,
+			&i.ChainID,
+			&i.IngestedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEventsForArchive = `-- name: ListEventsForArchive :many
+SELECT seq, organization_id, event_id, event_type, event_source, event_timestamp, subject_id, subject_source, subject_type, chain_id, raw_event_json, ingested_at, event_ts_ms, service_name, environment, artifact_id, outcome, actor_name, raw_event_compressed, environment_id, content_service, content_service_id, pipeline_run_id, run_url, link_url, duration_seconds
+FROM event_store
+WHERE organization_id = ?1
+  AND event_ts_ms < ?2
+  AND seq > ?3
+ORDER BY seq
+LIMIT ?4
+`
+
+type ListEventsForArchiveParams struct {
+	OrganizationID int64
+	BeforeMs       int64
+	AfterSeq       int64
+	Limit          int64
+}
+
+func (q *Queries) ListEventsForArchive(ctx context.Context, arg ListEventsForArchiveParams) ([]EventStore, error) {
+	rows, err := q.db.QueryContext(ctx, listEventsForArchive,
+		arg.OrganizationID,
+		arg.BeforeMs,
+		arg.AfterSeq,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EventStore
+	for rows.Next() {
+		var i EventStore
+		if err := rows.Scan(
+			&i.Seq,
+			&i.OrganizationID,
+			&i.EventID,
+			&i.EventType,
+			&i.EventSource,
+			&i.EventTimestamp,
+			&i.SubjectID,
+			&i.SubjectSource,
+			&i.SubjectType,
+			&i.ChainID,
+			&i.RawEventJson,
+			&i.IngestedAt,
+			&i.EventTsMs,
+			&i.ServiceName,
+			&i.Environment,
+			&i.ArtifactID,
+			&i.Outcome,
+			&i.ActorName,
+			&i.RawEventCompressed,
+			&i.EnvironmentID,
+			&i.ContentService,
+			&i.ContentServiceID,
+			&i.PipelineRunID,
+			&i.RunUrl,
+			&i.LinkUrl,
+			&i.DurationSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -869,6 +2272,213 @@ func (q *Queries) ListGitHubInstallationMappings(ctx context.Context, organizati
 	return items, nil
 }
 
+const listGitLabProjectMappings = `-- name: ListGitLabProjectMappings :many
+SELECT
+  project_id,
+  organization_id,
+  project_path,
+  default_environment,
+  enabled
+FROM gitlab_project_mappings
+WHERE organization_id = ?1
+ORDER BY project_id ASC
+`
+
+type ListGitLabProjectMappingsRow struct {
+	ProjectID          int64
+	OrganizationID     int64
+	ProjectPath        string
+	DefaultEnvironment string
+	Enabled            int64
+}
+
+func (q *Queries) ListGitLabProjectMappings(ctx context.Context, organizationID int64) ([]ListGitLabProjectMappingsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listGitLabProjectMappings, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListGitLabProjectMappingsRow
+	for rows.Next() {
+		var i ListGitLabProjectMappingsRow
+		if err := rows.Scan(
+			&i.ProjectID,
+			&i.OrganizationID,
+			&i.ProjectPath,
+			&i.DefaultEnvironment,
+			&i.Enabled,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listIngestDeadLetters = `-- name: ListIngestDeadLetters :many
+SELECT id, organization_id, reason, detail, headers_json, body, body_size, truncated, received_at, replayed_at, replay_result
+FROM ingest_dead_letters
+WHERE organization_id = ?1
+ORDER BY received_at DESC, id DESC
+LIMIT ?2
+`
+
+type ListIngestDeadLettersParams struct {
+	OrganizationID int64
+	Limit          int64
+}
+
+type ListIngestDeadLettersRow struct {
+	ID             int64
+	OrganizationID int64
+	Reason         string
+	Detail         string
+	HeadersJson    string
+	Body           []byte
+	BodySize       int64
+	Truncated      int64
+	ReceivedAt     int64
+	ReplayedAt     sql.NullInt64
+	ReplayResult   string
+}
+
+func (q *Queries) ListIngestDeadLetters(ctx context.Context, arg ListIngestDeadLettersParams) ([]ListIngestDeadLettersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listIngestDeadLetters, arg.OrganizationID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListIngestDeadLettersRow
+	for rows.Next() {
+		var i ListIngestDeadLettersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.Reason,
+			&i.Detail,
+			&i.HeadersJson,
+			&i.Body,
+			&i.BodySize,
+			&i.Truncated,
+			&i.ReceivedAt,
+			&i.ReplayedAt,
+			&i.ReplayResult,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listIngestHourlyStats = `-- name: ListIngestHourlyStats :many
+SELECT bucket_start, outcome, reason, event_count
+FROM ingest_hourly_stats
+WHERE organization_id = ?1
+  AND bucket_start >= ?2
+ORDER BY bucket_start, outcome, reason
+`
+
+type ListIngestHourlyStatsParams struct {
+	OrganizationID int64
+	Since          int64
+}
+
+type ListIngestHourlyStatsRow struct {
+	BucketStart int64
+	Outcome     string
+	Reason      string
+	EventCount  int64
+}
+
+func (q *Queries) ListIngestHourlyStats(ctx context.Context, arg ListIngestHourlyStatsParams) ([]ListIngestHourlyStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listIngestHourlyStats, arg.OrganizationID, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListIngestHourlyStatsRow
+	for rows.Next() {
+		var i ListIngestHourlyStatsRow
+		if err := rows.Scan(
+			&i.BucketStart,
+			&i.Outcome,
+			&i.Reason,
+			&i.EventCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listIngestSourceActivity = `-- name: ListIngestSourceActivity :many
+SELECT event_source, last_event_id, last_event_type, last_seen_at
+FROM ingest_source_activity
+WHERE organization_id = ?
+ORDER BY last_seen_at DESC
+LIMIT ?
+`
+
+type ListIngestSourceActivityParams struct {
+	OrganizationID int64
+	Limit          int64
+}
+
+type ListIngestSourceActivityRow struct {
+	EventSource   string
+	LastEventID   string
+	LastEventType string
+	LastSeenAt    int64
+}
+
+func (q *Queries) ListIngestSourceActivity(ctx context.Context, arg ListIngestSourceActivityParams) ([]ListIngestSourceActivityRow, error) {
+	rows, err := q.db.QueryContext(ctx, listIngestSourceActivity, arg.OrganizationID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListIngestSourceActivityRow
+	for rows.Next() {
+		var i ListIngestSourceActivityRow
+		if err := rows.Scan(
+			&i.EventSource,
+			&i.LastEventID,
+			&i.LastEventType,
+			&i.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listLegacyDeploymentsForBackfill = `-- name: ListLegacyDeploymentsForBackfill :many
 SELECT
   d.id,
@@ -922,35 +2532,142 @@ func (q *Queries) ListLegacyDeploymentsForBackfill(ctx context.Context) ([]ListL
 	return items, nil
 }
 
-const listOrganizationEnvironmentPriorities = `-- name: ListOrganizationEnvironmentPriorities :many
-SELECT id, organization_id, environment, sort_order
-FROM organization_environment_priorities
+const listLiveProjectorNames = `-- name: ListLiveProjectorNames :many
+SELECT name
+FROM projector_checkpoints
+WHERE last_seq >= (SELECT COALESCE(MAX(es.seq), 0) FROM event_store es WHERE es.seq < ?1)
+`
+
+func (q *Queries) ListLiveProjectorNames(ctx context.Context, firstSeq int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listLiveProjectorNames, firstSeq)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrganizationEnvironmentPriorities = `-- name: ListOrganizationEnvironmentPriorities :many
+SELECT id, organization_id, environment, sort_order
+FROM organization_environment_priorities
+WHERE organization_id = ?
+ORDER BY sort_order, id
+`
+
+type ListOrganizationEnvironmentPrioritiesRow struct {
+	ID             int64
+	OrganizationID int64
+	Environment    string
+	SortOrder      int64
+}
+
+func (q *Queries) ListOrganizationEnvironmentPriorities(ctx context.Context, organizationID int64) ([]ListOrganizationEnvironmentPrioritiesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOrganizationEnvironmentPriorities, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOrganizationEnvironmentPrioritiesRow
+	for rows.Next() {
+		var i ListOrganizationEnvironmentPrioritiesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.Environment,
+			&i.SortOrder,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrganizationEnvironments = `-- name: ListOrganizationEnvironments :many
+SELECT id, organization_id, name, tier, color, protected, sort_order, url, source, changed_at_ms, created_at, updated_at
+FROM organization_environments
+WHERE organization_id = ?1
+ORDER BY sort_order, name
+`
+
+func (q *Queries) ListOrganizationEnvironments(ctx context.Context, organizationID int64) ([]OrganizationEnvironment, error) {
+	rows, err := q.db.QueryContext(ctx, listOrganizationEnvironments, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OrganizationEnvironment
+	for rows.Next() {
+		var i OrganizationEnvironment
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.Name,
+			&i.Tier,
+			&i.Color,
+			&i.Protected,
+			&i.SortOrder,
+			&i.Url,
+			&i.Source,
+			&i.ChangedAtMs,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrganizationEventPolicies = `-- name: ListOrganizationEventPolicies :many
+SELECT subject, mode
+FROM organization_event_policies
 WHERE organization_id = ?
-ORDER BY sort_order, id
+ORDER BY subject
 `
 
-type ListOrganizationEnvironmentPrioritiesRow struct {
-	ID             int64
-	OrganizationID int64
-	Environment    string
-	SortOrder      int64
+type ListOrganizationEventPoliciesRow struct {
+	Subject string
+	Mode    string
 }
 
-func (q *Queries) ListOrganizationEnvironmentPriorities(ctx context.Context, organizationID int64) ([]ListOrganizationEnvironmentPrioritiesRow, error) {
-	rows, err := q.db.QueryContext(ctx, listOrganizationEnvironmentPriorities, organizationID)
+func (q *Queries) ListOrganizationEventPolicies(ctx context.Context, organizationID int64) ([]ListOrganizationEventPoliciesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOrganizationEventPolicies, organizationID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListOrganizationEnvironmentPrioritiesRow
+	var items []ListOrganizationEventPoliciesRow
 	for rows.Next() {
-		var i ListOrganizationEnvironmentPrioritiesRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.OrganizationID,
-			&i.Environment,
-			&i.SortOrder,
-		); err != nil {
+		var i ListOrganizationEventPoliciesRow
+		if err := rows.Scan(&i.Subject, &i.Mode); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -999,6 +2716,46 @@ func (q *Queries) ListOrganizationFeatures(ctx context.Context, organizationID i
 	return items, nil
 }
 
+const listOrganizationIngestCredentials = `-- name: ListOrganizationIngestCredentials :many
+SELECT id, organization_id, label, auth_token, webhook_secret, created_at, expires_at, revoked_at, last_used_at
+FROM organization_ingest_credentials
+WHERE organization_id = ?
+ORDER BY created_at DESC, id DESC
+`
+
+func (q *Queries) ListOrganizationIngestCredentials(ctx context.Context, organizationID int64) ([]OrganizationIngestCredential, error) {
+	rows, err := q.db.QueryContext(ctx, listOrganizationIngestCredentials, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OrganizationIngestCredential
+	for rows.Next() {
+		var i OrganizationIngestCredential
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.Label,
+			&i.AuthToken,
+			&i.WebhookSecret,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.RevokedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listOrganizationMembers = `-- name: ListOrganizationMembers :many
 SELECT
   u.id AS user_id,
@@ -1135,6 +2892,41 @@ func (q *Queries) ListOrganizationRequiredFields(ctx context.Context, organizati
 	return items, nil
 }
 
+const listOrganizationServiceDependencies = `-- name: ListOrganizationServiceDependencies :many
+SELECT service_name, depends_on_service_name
+FROM service_dependencies
+WHERE organization_id = ?1
+ORDER BY service_name, depends_on_service_name
+`
+
+type ListOrganizationServiceDependenciesRow struct {
+	ServiceName          string
+	DependsOnServiceName string
+}
+
+func (q *Queries) ListOrganizationServiceDependencies(ctx context.Context, organizationID int64) ([]ListOrganizationServiceDependenciesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listOrganizationServiceDependencies, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOrganizationServiceDependenciesRow
+	for rows.Next() {
+		var i ListOrganizationServiceDependenciesRow
+		if err := rows.Scan(&i.ServiceName, &i.DependsOnServiceName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listOrganizations = `-- name: ListOrganizations :many
 SELECT id, name, auth_token, webhook_secret, enabled, created_at, updated_at, join_code
 FROM organizations
@@ -1270,6 +3062,156 @@ func (q *Queries) ListPendingOrganizationJoinRequests(ctx context.Context, organ
 	return items, nil
 }
 
+const listProjectorCheckpoints = `-- name: ListProjectorCheckpoints :many
+SELECT name, last_seq, updated_at
+FROM projector_checkpoints
+ORDER BY name
+`
+
+func (q *Queries) ListProjectorCheckpoints(ctx context.Context) ([]ProjectorCheckpoint, error) {
+	rows, err := q.db.QueryContext(ctx, listProjectorCheckpoints)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProjectorCheckpoint
+	for rows.Next() {
+		var i ProjectorCheckpoint
+		if err := rows.Scan(&i.Name, &i.LastSeq, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProjectorEvents = `-- name: ListProjectorEvents :many
+SELECT seq, organization_id, event_type, subject_type, subject_id, event_ts_ms
+FROM event_store
+WHERE seq > ?1
+  AND seq <= ?2
+  AND subject_type IN (/*SLICE:subject_types*/?)
+ORDER BY seq
+LIMIT ?4
+`
+
+type ListProjectorEventsParams struct {
+	AfterSeq     int64
+	HeadSeq      int64
+	SubjectTypes []string
+	Limit        int64
+}
+
+type ListProjectorEventsRow struct {
+	Seq            int64
+	OrganizationID int64
+	EventType      string
+	SubjectType    string
+	SubjectID      string
+	EventTsMs      int64
+}
+
+func (q *Queries) ListProjectorEvents(ctx context.Context, arg ListProjectorEventsParams) ([]ListProjectorEventsRow, error) {
+	query := listProjectorEvents
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.AfterSeq)
+	queryParams = append(queryParams, arg.HeadSeq)
+	if len(arg.SubjectTypes) > 0 {
+		for _, v := range arg.SubjectTypes {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:subject_types*/?", strings.Repeat(",?", len(arg.SubjectTypes))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:subject_types*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListProjectorEventsRow
+	for rows.Next() {
+		var i ListProjectorEventsRow
+		if err := rows.Scan(
+			&i.Seq,
+			&i.OrganizationID,
+			&i.EventType,
+			&i.SubjectType,
+			&i.SubjectID,
+			&i.EventTsMs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listServiceChangesAfterSeq = `-- name: ListServiceChangesAfterSeq :many
+SELECT
+  CAST(
+    COALESCE(sia.service_name, es.service_name) AS TEXT
+  ) AS service_name,
+  CAST(MAX(es.seq) AS INTEGER) AS last_seq
+FROM event_store es
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
+WHERE es.organization_id = ?1
+  AND es.subject_type = 'service'
+  AND es.seq > ?2
+GROUP BY COALESCE(sia.service_name, es.service_name)
+ORDER BY last_seq ASC
+LIMIT ?3
+`
+
+type ListServiceChangesAfterSeqParams struct {
+	OrganizationID int64
+	AfterSeq       int64
+	Limit          int64
+}
+
+type ListServiceChangesAfterSeqRow struct {
+	ServiceName string
+	LastSeq     int64
+}
+
+func (q *Queries) ListServiceChangesAfterSeq(ctx context.Context, arg ListServiceChangesAfterSeqParams) ([]ListServiceChangesAfterSeqRow, error) {
+	rows, err := q.db.QueryContext(ctx, listServiceChangesAfterSeq, arg.OrganizationID, arg.AfterSeq, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListServiceChangesAfterSeqRow
+	for rows.Next() {
+		var i ListServiceChangesAfterSeqRow
+		if err := rows.Scan(&i.ServiceName, &i.LastSeq); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listServiceDependants = `-- name: ListServiceDependants :many
 SELECT service_name
 FROM service_dependencies
@@ -1342,18 +3284,145 @@ func (q *Queries) ListServiceDependencies(ctx context.Context, arg ListServiceDe
 	return items, nil
 }
 
+const listServiceEnvironmentStateAt = `-- name: ListServiceEnvironmentStateAt :many
+WITH candidates AS (
+  SELECT
+    COALESCE(sia.service_name, es.service_name) AS service_name,
+    COALESCE(ea.environment, es.environment) AS environment,
+    es.seq AS event_seq,
+    es.event_type,
+    es.event_ts_ms,
+    es.artifact_id,
+    es.actor_name,
+    es.outcome AS status
+  FROM event_store es
+  LEFT JOIN service_identity_aliases sia
+    ON sia.organization_id = es.organization_id
+    AND sia.alias = es.service_name
+  LEFT JOIN environment_aliases ea
+    ON ea.organization_id = es.organization_id
+    AND ea.alias = es.environment
+  WHERE es.organization_id = ?1
+    AND es.subject_type = 'service'
+    AND es.event_ts_ms <= ?2
+  UNION ALL
+  SELECT
+    COALESCE(asia.service_name, a.service_name),
+    COALESCE(aea.environment, a.environment),
+    a.latest_event_seq,
+    a.latest_event_type,
+    a.latest_event_ts_ms,
+    a.latest_artifact_id,
+    '',
+    a.latest_status
+  FROM event_archive_env_state a
+  LEFT JOIN service_identity_aliases asia
+    ON asia.organization_id = a.organization_id
+    AND asia.alias = a.service_name
+  LEFT JOIN environment_aliases aea
+    ON aea.organization_id = a.organization_id
+    AND aea.alias = a.environment
+  WHERE a.organization_id = ?1
+    AND a.latest_event_ts_ms <= ?2
+), ranked AS (
+  SELECT
+    c.service_name,
+    c.environment,
+    c.event_seq,
+    c.event_type,
+    c.event_ts_ms,
+    c.artifact_id,
+    c.actor_name,
+    c.status,
+    row_number() OVER (
+      PARTITION BY c.service_name, c.environment
+      ORDER BY c.event_ts_ms DESC, c.event_seq DESC
+    ) AS rn
+  FROM candidates c
+)
+SELECT
+  r.service_name,
+  r.environment,
+  r.event_seq,
+  r.event_type,
+  r.event_ts_ms,
+  r.artifact_id,
+  r.actor_name,
+  r.status
+FROM ranked r
+WHERE r.rn = 1
+ORDER BY r.service_name, r.environment
+`
+
+type ListServiceEnvironmentStateAtParams struct {
+	OrganizationID int64
+	AtMs           int64
+}
+
+type ListServiceEnvironmentStateAtRow struct {
+	ServiceName string
+	Environment string
+	EventSeq    int64
+	EventType   string
+	EventTsMs   int64
+	ArtifactID  string
+	ActorName   string
+	Status      string
+}
+
+// The latest service event per service and environment at or before at_ms.
+// Archived events are represented by the environment state at the archive
+// cutoff, which has no actor.
+func (q *Queries) ListServiceEnvironmentStateAt(ctx context.Context, arg ListServiceEnvironmentStateAtParams) ([]ListServiceEnvironmentStateAtRow, error) {
+	rows, err := q.db.QueryContext(ctx, listServiceEnvironmentStateAt, arg.OrganizationID, arg.AtMs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListServiceEnvironmentStateAtRow
+	for rows.Next() {
+		var i ListServiceEnvironmentStateAtRow
+		if err := rows.Scan(
+			&i.ServiceName,
+			&i.Environment,
+			&i.EventSeq,
+			&i.EventType,
+			&i.EventTsMs,
+			&i.ArtifactID,
+			&i.ActorName,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listServiceEnvironmentsFromEvents = `-- name: ListServiceEnvironmentsFromEvents :many
 WITH service_events AS (
   SELECT
     es.seq,
-    COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') AS environment,
+    COALESCE(ea.environment, es.environment) AS environment,
     es.event_timestamp,
     es.event_ts_ms,
-    COALESCE(json_extract(es.raw_event_json, '$.subject.content.artifactId'), '') AS artifact_id
+    es.artifact_id
   FROM event_store es
+  LEFT JOIN service_identity_aliases sia
+    ON sia.organization_id = es.organization_id
+    AND sia.alias = es.service_name
+  LEFT JOIN environment_aliases ea
+    ON ea.organization_id = es.organization_id
+    AND ea.alias = es.environment
   WHERE es.subject_type = 'service'
     AND es.organization_id = ?1
-    AND (es.subject_id = ?2 OR substr(es.subject_id, instr(es.subject_id, '/') + 1) = ?2)
+    AND (es.subject_id = ?2 OR COALESCE(sia.service_name, es.service_name) = ?2)
 ), ranked AS (
   SELECT
     environment,
@@ -1380,9 +3449,9 @@ type ListServiceEnvironmentsFromEventsParams struct {
 }
 
 type ListServiceEnvironmentsFromEventsRow struct {
-	Name       interface{}
+	Name       string
 	ReleasedAt string
-	Ref        interface{}
+	Ref        string
 }
 
 func (q *Queries) ListServiceEnvironmentsFromEvents(ctx context.Context, arg ListServiceEnvironmentsFromEventsParams) ([]ListServiceEnvironmentsFromEventsRow, error) {
@@ -1408,6 +3477,73 @@ func (q *Queries) ListServiceEnvironmentsFromEvents(ctx context.Context, arg Lis
 	return items, nil
 }
 
+const listServiceIdentityAliases = `-- name: ListServiceIdentityAliases :many
+SELECT organization_id, alias, service_name
+FROM service_identity_aliases
+WHERE organization_id = ?1
+ORDER BY service_name, alias
+`
+
+func (q *Queries) ListServiceIdentityAliases(ctx context.Context, organizationID int64) ([]ServiceIdentityAlias, error) {
+	rows, err := q.db.QueryContext(ctx, listServiceIdentityAliases, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ServiceIdentityAlias
+	for rows.Next() {
+		var i ServiceIdentityAlias
+		if err := rows.Scan(&i.OrganizationID, &i.Alias, &i.ServiceName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listServiceIdentityRules = `-- name: ListServiceIdentityRules :many
+SELECT id, organization_id, kind, pattern, service_name, created_at
+FROM service_identity_rules
+WHERE organization_id = ?1
+ORDER BY id
+`
+
+func (q *Queries) ListServiceIdentityRules(ctx context.Context, organizationID int64) ([]ServiceIdentityRule, error) {
+	rows, err := q.db.QueryContext(ctx, listServiceIdentityRules, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ServiceIdentityRule
+	for rows.Next() {
+		var i ServiceIdentityRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.Kind,
+			&i.Pattern,
+			&i.ServiceName,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listServiceInstancesByEnvFromEvents = `-- name: ListServiceInstancesByEnvFromEvents :many
 WITH service_events AS (
   SELECT
@@ -1415,24 +3551,20 @@ WITH service_events AS (
     es.event_type,
     es.event_timestamp,
     es.event_ts_ms,
-    CASE
-      WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-      ELSE es.subject_id
-    END AS service_name,
-    COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') AS environment,
-    COALESCE(json_extract(es.raw_event_json, '$.subject.content.artifactId'), '') AS artifact_id,
-    CASE
-      WHEN es.event_type LIKE 'dev.cdevents.service.deployed.%' THEN 'synced'
-      WHEN es.event_type LIKE 'dev.cdevents.service.upgraded.%' THEN 'synced'
-      WHEN es.event_type LIKE 'dev.cdevents.service.published.%' THEN 'synced'
-      WHEN es.event_type LIKE 'dev.cdevents.service.rolledback.%' THEN 'warning'
-      WHEN es.event_type LIKE 'dev.cdevents.service.removed.%' THEN 'out-of-sync'
-      ELSE 'unknown'
-    END AS status
+    COALESCE(sia.service_name, es.service_name) AS service_name,
+    COALESCE(ea.environment, es.environment) AS environment,
+    es.artifact_id,
+    es.outcome AS status
   FROM event_store es
+  LEFT JOIN service_identity_aliases sia
+    ON sia.organization_id = es.organization_id
+    AND sia.alias = es.service_name
+  LEFT JOIN environment_aliases ea
+    ON ea.organization_id = es.organization_id
+    AND ea.alias = es.environment
   WHERE es.subject_type = 'service'
     AND es.organization_id = ?1
-    AND COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') = ?2
+    AND COALESCE(ea.environment, es.environment) = ?2
 ), ranked AS (
   SELECT
     service_name,
@@ -1463,11 +3595,11 @@ type ListServiceInstancesByEnvFromEventsParams struct {
 }
 
 type ListServiceInstancesByEnvFromEventsRow struct {
-	ServiceName  interface{}
-	Environment  interface{}
+	ServiceName  string
+	Environment  string
 	Status       string
 	LastDeployAt string
-	ArtifactID   interface{}
+	ArtifactID   string
 }
 
 func (q *Queries) ListServiceInstancesByEnvFromEvents(ctx context.Context, arg ListServiceInstancesByEnvFromEventsParams) ([]ListServiceInstancesByEnvFromEventsRow, error) {
@@ -1506,21 +3638,17 @@ WITH service_events AS (
     es.event_type,
     es.event_timestamp,
     es.event_ts_ms,
-    CASE
-      WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-      ELSE es.subject_id
-    END AS service_name,
-    COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') AS environment,
-    COALESCE(json_extract(es.raw_event_json, '$.subject.content.artifactId'), '') AS artifact_id,
-    CASE
-      WHEN es.event_type LIKE 'dev.cdevents.service.deployed.%' THEN 'synced'
-      WHEN es.event_type LIKE 'dev.cdevents.service.upgraded.%' THEN 'synced'
-      WHEN es.event_type LIKE 'dev.cdevents.service.published.%' THEN 'synced'
-      WHEN es.event_type LIKE 'dev.cdevents.service.rolledback.%' THEN 'warning'
-      WHEN es.event_type LIKE 'dev.cdevents.service.removed.%' THEN 'out-of-sync'
-      ELSE 'unknown'
-    END AS status
+    COALESCE(sia.service_name, es.service_name) AS service_name,
+    COALESCE(ea.environment, es.environment) AS environment,
+    es.artifact_id,
+    es.outcome AS status
   FROM event_store es
+  LEFT JOIN service_identity_aliases sia
+    ON sia.organization_id = es.organization_id
+    AND sia.alias = es.service_name
+  LEFT JOIN environment_aliases ea
+    ON ea.organization_id = es.organization_id
+    AND ea.alias = es.environment
   WHERE es.subject_type = 'service'
     AND es.organization_id = ?1
 ), ranked AS (
@@ -1548,11 +3676,11 @@ ORDER BY service_name, environment
 `
 
 type ListServiceInstancesFromEventsRow struct {
-	ServiceName  interface{}
-	Environment  interface{}
+	ServiceName  string
+	Environment  string
 	Status       string
 	LastDeployAt string
-	ArtifactID   interface{}
+	ArtifactID   string
 }
 
 func (q *Queries) ListServiceInstancesFromEvents(ctx context.Context, organizationID int64) ([]ListServiceInstancesFromEventsRow, error) {
@@ -1661,6 +3789,313 @@ func (q *Queries) ListServiceMetadataByService(ctx context.Context, arg ListServ
 	return items, nil
 }
 
+const listServiceNameCandidates = `-- name: ListServiceNameCandidates :many
+SELECT ses.service_name AS name FROM service_env_state ses WHERE ses.organization_id = ?1
+UNION
+SELECT psd.service_name FROM service_pipeline_stats_daily psd WHERE psd.organization_id = ?1
+UNION
+SELECT sts.service_name FROM service_throughput_stats sts WHERE sts.organization_id = ?1
+UNION
+SELECT sil.service_name FROM service_incident_links sil WHERE sil.organization_id = ?1
+UNION
+SELECT sm.service_name FROM service_metadata sm WHERE sm.organization_id = ?1
+UNION
+SELECT sd.service_name FROM service_dependencies sd WHERE sd.organization_id = ?1
+UNION
+SELECT sdo.depends_on_service_name FROM service_dependencies sdo WHERE sdo.organization_id = ?1
+UNION
+SELECT sia.alias FROM service_identity_aliases sia WHERE sia.organization_id = ?1
+ORDER BY 1
+`
+
+func (q *Queries) ListServiceNameCandidates(ctx context.Context, organizationID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listServiceNameCandidates, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markIngestDeadLetterReplayed = `-- name: MarkIngestDeadLetterReplayed :exec
+UPDATE ingest_dead_letters
+SET replayed_at = ?1,
+    replay_result = ?2
+WHERE organization_id = ?3
+  AND id = ?4
+`
+
+type MarkIngestDeadLetterReplayedParams struct {
+	ReplayedAt     sql.NullInt64
+	ReplayResult   string
+	OrganizationID int64
+	ID             int64
+}
+
+func (q *Queries) MarkIngestDeadLetterReplayed(ctx context.Context, arg MarkIngestDeadLetterReplayedParams) error {
+	_, err := q.db.ExecContext(ctx, markIngestDeadLetterReplayed,
+		arg.ReplayedAt,
+		arg.ReplayResult,
+		arg.OrganizationID,
+		arg.ID,
+	)
+	return err
+}
+
+const moveServiceDependencies = `-- name: MoveServiceDependencies :exec
+INSERT INTO service_dependencies (organization_id, service_name, depends_on_service_name)
+SELECT moved.organization_id, moved.service_name, moved.depends_on_service_name
+FROM (
+  SELECT
+    src.organization_id,
+    CASE WHEN src.service_name = ?1 THEN ?2 ELSE src.service_name END AS service_name,
+    CASE WHEN src.depends_on_service_name = ?1 THEN ?2 ELSE src.depends_on_service_name END AS depends_on_service_name
+  FROM service_dependencies src
+  WHERE src.organization_id = ?3
+    AND (src.service_name = ?1 OR src.depends_on_service_name = ?1)
+) moved
+WHERE moved.service_name <> moved.depends_on_service_name
+ON CONFLICT(organization_id, service_name, depends_on_service_name) DO NOTHING
+`
+
+type MoveServiceDependenciesParams struct {
+	FromServiceName string
+	ToServiceName   string
+	OrganizationID  int64
+}
+
+func (q *Queries) MoveServiceDependencies(ctx context.Context, arg MoveServiceDependenciesParams) error {
+	_, err := q.db.ExecContext(ctx, moveServiceDependencies, arg.FromServiceName, arg.ToServiceName, arg.OrganizationID)
+	return err
+}
+
+const moveServiceMetadata = `-- name: MoveServiceMetadata :exec
+INSERT INTO service_metadata (organization_id, service_name, label, value)
+SELECT src.organization_id, ?1, src.label, src.value
+FROM service_metadata src
+WHERE src.organization_id = ?2
+  AND src.service_name = ?3
+ON CONFLICT(organization_id, service_name, label) DO NOTHING
+`
+
+type MoveServiceMetadataParams struct {
+	ToServiceName   string
+	OrganizationID  int64
+	FromServiceName string
+}
+
+func (q *Queries) MoveServiceMetadata(ctx context.Context, arg MoveServiceMetadataParams) error {
+	_, err := q.db.ExecContext(ctx, moveServiceMetadata, arg.ToServiceName, arg.OrganizationID, arg.FromServiceName)
+	return err
+}
+
+const pruneIngestDeadLetters = `-- name: PruneIngestDeadLetters :exec
+DELETE FROM ingest_dead_letters
+WHERE ingest_dead_letters.organization_id = ?1
+  AND (
+    ingest_dead_letters.received_at < ?2
+    OR ingest_dead_letters.id NOT IN (
+      SELECT kept.id
+      FROM ingest_dead_letters kept
+      WHERE kept.organization_id = ?1
+      ORDER BY kept.received_at DESC, kept.id DESC
+      LIMIT ?3
+    )
+  )
+`
+
+type PruneIngestDeadLettersParams struct {
+	OrganizationID int64
+	ReceivedBefore int64
+	KeepCount      int64
+}
+
+func (q *Queries) PruneIngestDeadLetters(ctx context.Context, arg PruneIngestDeadLettersParams) error {
+	_, err := q.db.ExecContext(ctx, pruneIngestDeadLetters, arg.OrganizationID, arg.ReceivedBefore, arg.KeepCount)
+	return err
+}
+
+const pruneIngestHourlyStats = `-- name: PruneIngestHourlyStats :exec
+DELETE FROM ingest_hourly_stats
+WHERE organization_id = ?1
+  AND bucket_start < ?2
+`
+
+type PruneIngestHourlyStatsParams struct {
+	OrganizationID int64
+	Before         int64
+}
+
+func (q *Queries) PruneIngestHourlyStats(ctx context.Context, arg PruneIngestHourlyStatsParams) error {
+	_, err := q.db.ExecContext(ctx, pruneIngestHourlyStats, arg.OrganizationID, arg.Before)
+	return err
+}
+
+const pruneIngestSeenSignatures = `-- name: PruneIngestSeenSignatures :exec
+DELETE FROM ingest_seen_signatures
+WHERE expires_at < ?
+`
+
+func (q *Queries) PruneIngestSeenSignatures(ctx context.Context, expiresAt int64) error {
+	_, err := q.db.ExecContext(ctx, pruneIngestSeenSignatures, expiresAt)
+	return err
+}
+
+const restoreArchivedEvent = `-- name: RestoreArchivedEvent :execrows
+INSERT INTO event_store (
+  seq, organization_id, event_id, event_type, event_source, event_timestamp,
+  subject_id, subject_source, subject_type, chain_id, raw_event_json, raw_event_compressed,
+  ingested_at, event_ts_ms,
+  service_name, environment, artifact_id, outcome, actor_name,
+  environment_id, content_service, content_service_id, pipeline_run_id, run_url, link_url, duration_seconds
+)
+VALUES (
+  ?1,
+  ?2,
+  ?3,
+  ?4,
+  ?5,
+  ?6,
+  ?7,
+  ?8,
+  ?9,
+  ?10,
+  '',
+  ddash_event_compress(CAST(?11 AS TEXT)),
+  ?12,
+  ?13,
+  CASE
+    WHEN instr(?7, '/') > 0 THEN substr(?7, instr(?7, '/') + 1)
+    ELSE ?7
+  END,
+  COALESCE(NULLIF(json_extract(?11, '$.subject.content.environment.id'), ''), 'unknown'),
+  COALESCE(json_extract(?11, '$.subject.content.artifactId'), ''),
+  CASE
+    WHEN ?9 <> 'service' THEN ''
+    WHEN ?4 LIKE 'dev.cdevents.service.deployed.%' THEN 'synced'
+    WHEN ?4 LIKE 'dev.cdevents.service.upgraded.%' THEN 'synced'
+    WHEN ?4 LIKE 'dev.cdevents.service.published.%' THEN 'synced'
+    WHEN ?4 LIKE 'dev.cdevents.service.rolledback.%' THEN 'warning'
+    WHEN ?4 LIKE 'dev.cdevents.service.removed.%' THEN 'out-of-sync'
+    ELSE 'unknown'
+  END,
+  COALESCE(json_extract(?11, '$.subject.content.actor.name'), ''),
+  COALESCE(json_extract(?11, '$.subject.content.environment.id'), ''),
+  CASE
+    WHEN json_type(?11, '$.subject.content.service') = 'text'
+    THEN json_extract(?11, '$.subject.content.service')
+    ELSE ''
+  END,
+  CASE
+    WHEN json_type(?11, '$.subject.content.service') = 'object'
+    THEN COALESCE(json_extract(?11, '$.subject.content.service.id'), '')
+    ELSE ''
+  END,
+  COALESCE(json_extract(?11, '$.subject.content.pipeline.runId'), ''),
+  COALESCE(json_extract(?11, '$.subject.content.pipeline.url'), ''),
+  COALESCE(
+    NULLIF(json_extract(?11, '$.subject.content.url'), ''),
+    json_extract(?11, '$.subject.content.pipeline.url'),
+    ''
+  ),
+  COALESCE(json_extract(?11, '$.subject.content.durationSeconds'), 0)
+)
+ON CONFLICT DO NOTHING
+`
+
+type RestoreArchivedEventParams struct {
+	Seq            int64
+	OrganizationID int64
+	EventID        string
+	EventType      string
+	EventSource    string
+	EventTimestamp string
+	SubjectID      string
+	SubjectSource  sql.NullString
+	SubjectType    string
+	ChainID        sql.NullString
+	RawEventJson   string
+	IngestedAt     time.Time
+	EventTsMs      int64
+}
+
+func (q *Queries) RestoreArchivedEvent(ctx context.Context, arg RestoreArchivedEventParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreArchivedEvent,
+		arg.Seq,
+		arg.OrganizationID,
+		arg.EventID,
+		arg.EventType,
+		arg.EventSource,
+		arg.EventTimestamp,
+		arg.SubjectID,
+		arg.SubjectSource,
+		arg.SubjectType,
+		arg.ChainID,
+		arg.RawEventJson,
+		arg.IngestedAt,
+		arg.EventTsMs,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const revokeOrganizationIngestCredential = `-- name: RevokeOrganizationIngestCredential :execrows
+UPDATE organization_ingest_credentials
+SET revoked_at = ?1
+WHERE organization_id = ?2
+  AND id = ?3
+  AND revoked_at IS NULL
+`
+
+type RevokeOrganizationIngestCredentialParams struct {
+	RevokedAt      sql.NullInt64
+	OrganizationID int64
+	ID             int64
+}
+
+func (q *Queries) RevokeOrganizationIngestCredential(ctx context.Context, arg RevokeOrganizationIngestCredentialParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeOrganizationIngestCredential, arg.RevokedAt, arg.OrganizationID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setOrganizationEnvironmentSortOrder = `-- name: SetOrganizationEnvironmentSortOrder :exec
+UPDATE organization_environments
+SET sort_order = ?1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE organization_id = ?2
+  AND name = ?3
+`
+
+type SetOrganizationEnvironmentSortOrderParams struct {
+	SortOrder      int64
+	OrganizationID int64
+	Name           string
+}
+
+func (q *Queries) SetOrganizationEnvironmentSortOrder(ctx context.Context, arg SetOrganizationEnvironmentSortOrderParams) error {
+	_, err := q.db.ExecContext(ctx, setOrganizationEnvironmentSortOrder, arg.SortOrder, arg.OrganizationID, arg.Name)
+	return err
+}
+
 const setOrganizationJoinRequestStatus = `-- name: SetOrganizationJoinRequestStatus :exec
 UPDATE organization_join_requests
 SET status = ?, reviewed_by = ?, reviewed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
@@ -1684,6 +4119,74 @@ func (q *Queries) SetOrganizationJoinRequestStatus(ctx context.Context, arg SetO
 	return err
 }
 
+const setProjectorCheckpoint = `-- name: SetProjectorCheckpoint :exec
+UPDATE projector_checkpoints
+SET last_seq = ?1, updated_at = ?2
+WHERE name = ?3
+`
+
+type SetProjectorCheckpointParams struct {
+	LastSeq   int64
+	UpdatedAt int64
+	Name      string
+}
+
+func (q *Queries) SetProjectorCheckpoint(ctx context.Context, arg SetProjectorCheckpointParams) error {
+	_, err := q.db.ExecContext(ctx, setProjectorCheckpoint, arg.LastSeq, arg.UpdatedAt, arg.Name)
+	return err
+}
+
+const touchOrganizationEnvironmentFromEvent = `-- name: TouchOrganizationEnvironmentFromEvent :exec
+UPDATE organization_environments
+SET url = CASE WHEN CAST(?1 AS TEXT) <> '' THEN CAST(?1 AS TEXT) ELSE url END,
+    changed_at_ms = ?2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE organization_id = ?3
+  AND id = ?4
+`
+
+type TouchOrganizationEnvironmentFromEventParams struct {
+	Url            string
+	ChangedAtMs    int64
+	OrganizationID int64
+	ID             int64
+}
+
+func (q *Queries) TouchOrganizationEnvironmentFromEvent(ctx context.Context, arg TouchOrganizationEnvironmentFromEventParams) error {
+	_, err := q.db.ExecContext(ctx, touchOrganizationEnvironmentFromEvent,
+		arg.Url,
+		arg.ChangedAtMs,
+		arg.OrganizationID,
+		arg.ID,
+	)
+	return err
+}
+
+const touchOrganizationIngestCredential = `-- name: TouchOrganizationIngestCredential :exec
+UPDATE organization_ingest_credentials
+SET last_used_at = ?1
+WHERE organization_id = ?2
+  AND id = ?3
+  AND (last_used_at IS NULL OR last_used_at < ?4)
+`
+
+type TouchOrganizationIngestCredentialParams struct {
+	UsedAt         sql.NullInt64
+	OrganizationID int64
+	ID             int64
+	StaleBefore    sql.NullInt64
+}
+
+func (q *Queries) TouchOrganizationIngestCredential(ctx context.Context, arg TouchOrganizationIngestCredentialParams) error {
+	_, err := q.db.ExecContext(ctx, touchOrganizationIngestCredential,
+		arg.UsedAt,
+		arg.OrganizationID,
+		arg.ID,
+		arg.StaleBefore,
+	)
+	return err
+}
+
 const updateOrganizationEnabled = `-- name: UpdateOrganizationEnabled :exec
 UPDATE organizations
 SET enabled = ?, updated_at = CURRENT_TIMESTAMP
@@ -1823,6 +4326,117 @@ func (q *Queries) UpsertGitLabProjectMapping(ctx context.Context, arg UpsertGitL
 	return err
 }
 
+const upsertIngestSourceActivity = `-- name: UpsertIngestSourceActivity :exec
+INSERT INTO ingest_source_activity (organization_id, event_source, last_event_id, last_event_type, last_seen_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(organization_id, event_source) DO UPDATE SET
+  last_event_id = excluded.last_event_id,
+  last_event_type = excluded.last_event_type,
+  last_seen_at = excluded.last_seen_at
+WHERE excluded.last_seen_at >= ingest_source_activity.last_seen_at
+`
+
+type UpsertIngestSourceActivityParams struct {
+	OrganizationID int64
+	EventSource    string
+	LastEventID    string
+	LastEventType  string
+	LastSeenAt     int64
+}
+
+func (q *Queries) UpsertIngestSourceActivity(ctx context.Context, arg UpsertIngestSourceActivityParams) error {
+	_, err := q.db.ExecContext(ctx, upsertIngestSourceActivity,
+		arg.OrganizationID,
+		arg.EventSource,
+		arg.LastEventID,
+		arg.LastEventType,
+		arg.LastSeenAt,
+	)
+	return err
+}
+
+const upsertOrganizationEnvironment = `-- name: UpsertOrganizationEnvironment :one
+INSERT INTO organization_environments (organization_id, name, tier, color, protected, sort_order, url, source, changed_at_ms)
+VALUES (
+  ?1,
+  ?2,
+  ?3,
+  ?4,
+  ?5,
+  (SELECT COALESCE(MAX(oe.sort_order) + 1, 0) FROM organization_environments oe WHERE oe.organization_id = ?1),
+  ?6,
+  ?7,
+  ?8
+)
+ON CONFLICT(organization_id, name) DO UPDATE SET
+  tier = excluded.tier,
+  color = excluded.color,
+  protected = excluded.protected,
+  url = excluded.url,
+  changed_at_ms = excluded.changed_at_ms,
+  updated_at = CURRENT_TIMESTAMP
+RETURNING id, organization_id, name, tier, color, protected, sort_order, url, source, changed_at_ms, created_at, updated_at
+`
+
+type UpsertOrganizationEnvironmentParams struct {
+	OrganizationID int64
+	Name           string
+	Tier           string
+	Color          string
+	Protected      int64
+	Url            string
+	Source         string
+	ChangedAtMs    int64
+}
+
+func (q *Queries) UpsertOrganizationEnvironment(ctx context.Context, arg UpsertOrganizationEnvironmentParams) (OrganizationEnvironment, error) {
+	row := q.db.QueryRowContext(ctx, upsertOrganizationEnvironment,
+		arg.OrganizationID,
+		arg.Name,
+		arg.Tier,
+		arg.Color,
+		arg.Protected,
+		arg.Url,
+		arg.Source,
+		arg.ChangedAtMs,
+	)
+	var i OrganizationEnvironment
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Name,
+		&i.Tier,
+		&i.Color,
+		&i.Protected,
+		&i.SortOrder,
+		&i.Url,
+		&i.Source,
+		&i.ChangedAtMs,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertOrganizationEventPolicy = `-- name: UpsertOrganizationEventPolicy :exec
+INSERT INTO organization_event_policies (organization_id, subject, mode)
+VALUES (?, ?, ?)
+ON CONFLICT(organization_id, subject) DO UPDATE SET
+  mode = excluded.mode,
+  updated_at = CURRENT_TIMESTAMP
+`
+
+type UpsertOrganizationEventPolicyParams struct {
+	OrganizationID int64
+	Subject        string
+	Mode           string
+}
+
+func (q *Queries) UpsertOrganizationEventPolicy(ctx context.Context, arg UpsertOrganizationEventPolicyParams) error {
+	_, err := q.db.ExecContext(ctx, upsertOrganizationEventPolicy, arg.OrganizationID, arg.Subject, arg.Mode)
+	return err
+}
+
 const upsertOrganizationFeature = `-- name: UpsertOrganizationFeature :exec
 INSERT INTO organization_features (organization_id, feature_key, is_enabled)
 VALUES (?, ?, ?)
@@ -1917,19 +4531,22 @@ INSERT INTO service_change_links (
 )
 SELECT
   es.organization_id,
-  CASE
-    WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-    ELSE es.subject_id
-  END AS service_name,
+  COALESCE(sia.service_name, es.service_name) AS service_name,
   es.seq,
   es.event_ts_ms,
   es.chain_id,
-  COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') AS environment,
-  COALESCE(json_extract(es.raw_event_json, '$.subject.content.artifactId'), '') AS artifact_id,
-  COALESCE(json_extract(es.raw_event_json, '$.subject.content.pipeline.runId'), '') AS pipeline_run_id,
-  COALESCE(json_extract(es.raw_event_json, '$.subject.content.pipeline.url'), '') AS run_url,
-  COALESCE(json_extract(es.raw_event_json, '$.subject.content.actor.name'), '') AS actor_name
+  COALESCE(ea.environment, es.environment) AS environment,
+  es.artifact_id,
+  es.pipeline_run_id,
+  es.run_url,
+  es.actor_name
 FROM event_store es
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
+LEFT JOIN environment_aliases ea
+  ON ea.organization_id = es.organization_id
+  AND ea.alias = es.environment
 WHERE es.organization_id = ?1
   AND es.seq = ?2
   AND es.subject_type = 'service'
@@ -2018,26 +4635,15 @@ INSERT INTO service_delivery_stats_daily (
 )
 SELECT
   es.organization_id,
-  CASE
-    WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-    ELSE es.subject_id
-  END AS service_name,
+  COALESCE(sia.service_name, es.service_name) AS service_name,
   date(datetime(es.event_ts_ms / 1000, 'unixepoch')) AS day_utc,
-  CASE
-    WHEN es.event_type LIKE 'dev.cdevents.service.deployed.%'
-      OR es.event_type LIKE 'dev.cdevents.service.upgraded.%'
-      OR es.event_type LIKE 'dev.cdevents.service.published.%' THEN 1
-    ELSE 0
-  END AS deploy_success_count,
-  CASE
-    WHEN es.event_type LIKE 'dev.cdevents.service.removed.%' THEN 1
-    ELSE 0
-  END AS deploy_failure_count,
-  CASE
-    WHEN es.event_type LIKE 'dev.cdevents.service.rolledback.%' THEN 1
-    ELSE 0
-  END AS rollback_count
+  CASE WHEN es.outcome = 'synced' THEN 1 ELSE 0 END AS deploy_success_count,
+  CASE WHEN es.outcome = 'out-of-sync' THEN 1 ELSE 0 END AS deploy_failure_count,
+  CASE WHEN es.outcome = 'warning' THEN 1 ELSE 0 END AS rollback_count
 FROM event_store es
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
 WHERE es.organization_id = ?1
   AND es.seq = ?2
   AND es.subject_type = 'service'
@@ -2088,24 +4694,20 @@ INSERT INTO service_env_state (
 )
 SELECT
   es.organization_id,
-  CASE
-    WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-    ELSE es.subject_id
-  END AS service_name,
-  COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') AS environment,
+  COALESCE(sia.service_name, es.service_name) AS service_name,
+  COALESCE(ea.environment, es.environment) AS environment,
   es.seq,
   es.event_type,
   es.event_ts_ms,
-  CASE
-    WHEN es.event_type LIKE 'dev.cdevents.service.deployed.%' THEN 'synced'
-    WHEN es.event_type LIKE 'dev.cdevents.service.upgraded.%' THEN 'synced'
-    WHEN es.event_type LIKE 'dev.cdevents.service.published.%' THEN 'synced'
-    WHEN es.event_type LIKE 'dev.cdevents.service.rolledback.%' THEN 'warning'
-    WHEN es.event_type LIKE 'dev.cdevents.service.removed.%' THEN 'out-of-sync'
-    ELSE 'unknown'
-  END AS latest_status,
-  COALESCE(json_extract(es.raw_event_json, '$.subject.content.artifactId'), '') AS latest_artifact_id
+  es.outcome AS latest_status,
+  es.artifact_id AS latest_artifact_id
 FROM event_store es
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
+LEFT JOIN environment_aliases ea
+  ON ea.organization_id = es.organization_id
+  AND ea.alias = es.environment
 WHERE es.organization_id = ?1
   AND es.seq = ?2
   AND es.subject_type = 'service'
@@ -2131,6 +4733,24 @@ func (q *Queries) UpsertServiceEnvStateFromEventSeq(ctx context.Context, arg Ups
 	return err
 }
 
+const upsertServiceIdentityAlias = `-- name: UpsertServiceIdentityAlias :exec
+INSERT INTO service_identity_aliases (organization_id, alias, service_name)
+VALUES (?1, ?2, ?3)
+ON CONFLICT(organization_id, alias) DO UPDATE SET
+  service_name = excluded.service_name
+`
+
+type UpsertServiceIdentityAliasParams struct {
+	OrganizationID int64
+	Alias          string
+	ServiceName    string
+}
+
+func (q *Queries) UpsertServiceIdentityAlias(ctx context.Context, arg UpsertServiceIdentityAliasParams) error {
+	_, err := q.db.ExecContext(ctx, upsertServiceIdentityAlias, arg.OrganizationID, arg.Alias, arg.ServiceName)
+	return err
+}
+
 const upsertServiceMetadata = `-- name: UpsertServiceMetadata :exec
 INSERT INTO service_metadata (organization_id, service_name, label, value)
 VALUES (?1, ?2, ?3, ?4)
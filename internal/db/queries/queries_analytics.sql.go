@@ -129,12 +129,12 @@ SELECT
        FROM (
          SELECT es.event_ts_ms AS change_ts_ms,
            CASE
-             WHEN instr(json_extract(es.raw_event_json, '$.subject.content.artifactId'), 'pkg:generic/') = 1
-              AND instr(substr(json_extract(es.raw_event_json, '$.subject.content.artifactId'), 13), '@') > 0
+             WHEN instr(es.artifact_id, 'pkg:generic/') = 1
+              AND instr(substr(es.artifact_id, 13), '@') > 0
              THEN substr(
-               json_extract(es.raw_event_json, '$.subject.content.artifactId'),
+               es.artifact_id,
                13,
-               instr(substr(json_extract(es.raw_event_json, '$.subject.content.artifactId'), 13), '@') - 1
+               instr(substr(es.artifact_id, 13), '@') - 1
              )
              ELSE ''
            END AS service_name
@@ -143,17 +143,20 @@ SELECT
            AND es.subject_type = 'change'
            AND es.event_ts_ms >= ?2
        ) c
-       WHERE c.service_name = d.service_name
+       LEFT JOIN service_identity_aliases sia
+         ON sia.organization_id = ?1
+         AND sia.alias = c.service_name
+       WHERE COALESCE(sia.service_name, c.service_name) = d.service_name
          AND c.change_ts_ms <= d.deploy_ts_ms
      )) / 1000 AS lead_seconds
      FROM (
        SELECT
          es.event_ts_ms AS deploy_ts_ms,
-         CASE
-           WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-           ELSE es.subject_id
-         END AS service_name
+         COALESCE(sia.service_name, es.service_name) AS service_name
        FROM event_store es
+       LEFT JOIN service_identity_aliases sia
+         ON sia.organization_id = es.organization_id
+         AND sia.alias = es.service_name
        WHERE es.organization_id = ?1
          AND es.subject_type = 'service'
          AND es.event_type LIKE 'dev.cdevents.service.deployed.%'
@@ -307,51 +310,82 @@ func (q *Queries) GetEnvironmentDriftCount(ctx context.Context, arg GetEnvironme
 	return drift_count, err
 }
 
+const getEventStorePayloadSizes = `-- name: GetEventStorePayloadSizes :one
+SELECT
+  CAST(COALESCE(SUM(CASE WHEN raw_event_compressed IS NULL THEN 1 ELSE 0 END), 0) AS INTEGER) AS plain_events,
+  CAST(COALESCE(SUM(CASE WHEN raw_event_compressed IS NULL THEN length(CAST(raw_event_json AS BLOB)) ELSE 0 END), 0) AS INTEGER) AS plain_bytes,
+  CAST(COALESCE(SUM(CASE WHEN raw_event_compressed IS NOT NULL THEN 1 ELSE 0 END), 0) AS INTEGER) AS compressed_events,
+  CAST(COALESCE(SUM(length(raw_event_compressed)), 0) AS INTEGER) AS compressed_bytes,
+  CAST(COALESCE(SUM(CASE WHEN raw_event_compressed IS NOT NULL THEN length(CAST(ddash_event_json(raw_event_json, raw_event_compressed) AS BLOB)) ELSE 0 END), 0) AS INTEGER) AS original_bytes
+FROM event_store
+`
+
+type GetEventStorePayloadSizesRow struct {
+	PlainEvents      int64
+	PlainBytes       int64
+	CompressedEvents int64
+	CompressedBytes  int64
+	OriginalBytes    int64
+}
+
+// Bytes held by event bodies, split by storage form. original_bytes is the
+// decompressed size of the compressed bodies, so reading it decompresses
+// every one of them.
+func (q *Queries) GetEventStorePayloadSizes(ctx context.Context) (GetEventStorePayloadSizesRow, error) {
+	row := q.db.QueryRowContext(ctx, getEventStorePayloadSizes)
+	var i GetEventStorePayloadSizesRow
+	err := row.Scan(
+		&i.PlainEvents,
+		&i.PlainBytes,
+		&i.CompressedEvents,
+		&i.CompressedBytes,
+		&i.OriginalBytes,
+	)
+	return i, err
+}
+
 const getMTTR = `-- name: GetMTTR :one
-WITH incident_resolutions AS (
-  SELECT
-    il.service_name,
-    il.incident_id,
-    il.linked_at AS incident_time_ms,
-    (SELECT es.event_ts_ms FROM event_store es
-     WHERE es.organization_id = il.organization_id
-       AND es.subject_id LIKE '%' || il.service_name || '%'
-       AND es.event_type LIKE 'dev.cdevents.service.deployed.%'
-       AND es.event_ts_ms > il.linked_at
-     ORDER BY es.event_ts_ms ASC LIMIT 1) AS resolved_at_ms
-  FROM service_incident_links il
-  WHERE il.organization_id = ?1
-    AND il.linked_at >= ?2
-)
 SELECT
   COUNT(*) AS incident_count,
-  COALESCE(AVG(resolved_at_ms - incident_time_ms), 0) / 1000 AS mttr_seconds,
-  COALESCE(MIN(resolved_at_ms - incident_time_ms), 0) / 1000 AS mttd_seconds,
-  COALESCE(MAX(resolved_at_ms - incident_time_ms), 0) / 1000 AS mtte_seconds
-FROM incident_resolutions
-WHERE resolved_at_ms IS NOT NULL
+  CAST(COALESCE(AVG(CASE WHEN resolved_at IS NOT NULL THEN resolved_at - linked_at END), 0) / 1000 AS REAL) AS mttr_seconds,
+  CAST(COALESCE(AVG(CASE WHEN deployment_ts_ms IS NOT NULL THEN linked_at - deployment_ts_ms END), 0) / 1000 AS REAL) AS mttd_seconds,
+  CAST(COALESCE(MAX(CASE WHEN resolved_at IS NOT NULL THEN resolved_at - linked_at END), 0) / 1000 AS REAL) AS mtte_seconds,
+  (SELECT COUNT(*) FROM service_incident_links open_links
+   WHERE open_links.organization_id = ?1
+     AND (CAST(?2 AS TEXT) = '' OR open_links.service_name = ?2)
+     AND open_links.resolved_at IS NULL) AS open_count
+FROM service_incident_links il
+WHERE il.organization_id = ?1
+  AND (CAST(?2 AS TEXT) = '' OR il.service_name = ?2)
+  AND il.incident_type <> 'resolved'
+  AND il.linked_at >= ?3
 `
 
 type GetMTTRParams struct {
 	OrganizationID int64
+	ServiceName    string
 	SinceMs        int64
 }
 
 type GetMTTRRow struct {
 	IncidentCount int64
-	MttrSeconds   int64
-	MttdSeconds   int64
-	MtteSeconds   int64
+	MttrSeconds   float64
+	MttdSeconds   float64
+	MtteSeconds   float64
+	OpenCount     int64
 }
 
+// Links still typed 'resolved' never saw a detected or reported event, so
+// they carry no detection time and stay out of the incident aggregates.
 func (q *Queries) GetMTTR(ctx context.Context, arg GetMTTRParams) (GetMTTRRow, error) {
-	row := q.db.QueryRowContext(ctx, getMTTR, arg.OrganizationID, arg.SinceMs)
+	row := q.db.QueryRowContext(ctx, getMTTR, arg.OrganizationID, arg.ServiceName, arg.SinceMs)
 	var i GetMTTRRow
 	err := row.Scan(
 		&i.IncidentCount,
 		&i.MttrSeconds,
 		&i.MttdSeconds,
 		&i.MtteSeconds,
+		&i.OpenCount,
 	)
 	return i, err
 }
@@ -398,6 +432,51 @@ func (q *Queries) GetPipelineStats30d(ctx context.Context, arg GetPipelineStats3
 	return i, err
 }
 
+const getRedeploymentCheckFromEventSeq = `-- name: GetRedeploymentCheckFromEventSeq :one
+SELECT
+  CAST(COALESCE(sia.service_name, es.service_name) AS TEXT) AS service_name,
+  CAST(date(datetime(es.event_ts_ms / 1000, 'unixepoch')) AS TEXT) AS day_utc,
+  CAST(CASE
+    WHEN es.artifact_id != ''
+     AND es.artifact_id = COALESCE(ses.latest_artifact_id, '')
+    THEN '1'
+    ELSE '0'
+  END AS TEXT) AS same_artifact
+FROM event_store es
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
+LEFT JOIN environment_aliases ea
+  ON ea.organization_id = es.organization_id
+  AND ea.alias = es.environment
+LEFT JOIN service_env_state ses ON
+  ses.organization_id = es.organization_id
+  AND ses.service_name = COALESCE(sia.service_name, es.service_name)
+  AND ses.environment = COALESCE(ea.environment, es.environment)
+WHERE es.organization_id = ?1
+  AND es.seq = ?2
+  AND es.subject_type = 'service'
+  AND es.event_type LIKE 'dev.cdevents.service.deployed.%'
+`
+
+type GetRedeploymentCheckFromEventSeqParams struct {
+	OrganizationID int64
+	Seq            int64
+}
+
+type GetRedeploymentCheckFromEventSeqRow struct {
+	ServiceName  string
+	DayUtc       string
+	SameArtifact string
+}
+
+func (q *Queries) GetRedeploymentCheckFromEventSeq(ctx context.Context, arg GetRedeploymentCheckFromEventSeqParams) (GetRedeploymentCheckFromEventSeqRow, error) {
+	row := q.db.QueryRowContext(ctx, getRedeploymentCheckFromEventSeq, arg.OrganizationID, arg.Seq)
+	var i GetRedeploymentCheckFromEventSeqRow
+	err := row.Scan(&i.ServiceName, &i.DayUtc, &i.SameArtifact)
+	return i, err
+}
+
 const getRedeploymentRate30d = `-- name: GetRedeploymentRate30d :one
 SELECT
   COALESCE(SUM(redeploy_count), 0) AS redeploy_count,
@@ -505,7 +584,7 @@ SELECT
 FROM service_throughput_stats
 WHERE organization_id = ?1
   AND service_name = ?2
-  AND week_start >= date('now', '-12 week')
+  AND week_start >= date('now', '-84 day')
 `
 
 type GetThroughputStatsParams struct {
@@ -538,16 +617,19 @@ INSERT INTO service_deployment_durations (
 )
 SELECT
   es.organization_id,
-  CASE
-    WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-    ELSE es.subject_id
-  END AS service_name,
-  COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown'),
+  COALESCE(sia.service_name, es.service_name) AS service_name,
+  COALESCE(ea.environment, es.environment),
   es.seq,
   es.event_ts_ms,
-  COALESCE(json_extract(es.raw_event_json, '$.subject.content.durationSeconds'), 0),
-  COALESCE(json_extract(es.raw_event_json, '$.subject.content.artifactId'), '')
+  es.duration_seconds,
+  es.artifact_id
 FROM event_store es
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
+LEFT JOIN environment_aliases ea
+  ON ea.organization_id = es.organization_id
+  AND ea.alias = es.environment
 WHERE es.organization_id = ?1
   AND es.seq = ?2
   AND es.subject_type = 'service'
@@ -583,28 +665,74 @@ SELECT
   ses2.environment,
   ses1.latest_artifact_id,
   ses2.latest_artifact_id,
-  strftime('%s', 'now') * 1000
+  ?1
 FROM service_env_state ses1
 JOIN service_env_state ses2 ON
   ses1.organization_id = ses2.organization_id
   AND ses1.service_name = ses2.service_name
   AND ses1.environment != ses2.environment
   AND ses1.latest_artifact_id != ses2.latest_artifact_id
-WHERE ses1.organization_id = ?1
-  AND ses1.service_name = ?2
+WHERE ses1.organization_id = ?2
+  AND ses1.service_name = ?3
   AND ses1.latest_artifact_id != ''
   AND ses2.latest_artifact_id != ''
 ON CONFLICT(organization_id, service_name, environment_from, environment_to, drift_detected_at) DO NOTHING
 `
 
 type InsertEnvironmentDriftParams struct {
+	DetectedAt     int64
 	OrganizationID int64
 	ServiceName    string
 }
 
 // Environment Drift Detection
 func (q *Queries) InsertEnvironmentDrift(ctx context.Context, arg InsertEnvironmentDriftParams) error {
-	_, err := q.db.ExecContext(ctx, insertEnvironmentDrift, arg.OrganizationID, arg.ServiceName)
+	_, err := q.db.ExecContext(ctx, insertEnvironmentDrift, arg.DetectedAt, arg.OrganizationID, arg.ServiceName)
+	return err
+}
+
+const insertEnvironmentDriftRow = `-- name: InsertEnvironmentDriftRow :exec
+INSERT INTO service_environment_drift (
+  organization_id,
+  service_name,
+  environment_from,
+  environment_to,
+  artifact_id_from,
+  artifact_id_to,
+  drift_detected_at
+)
+VALUES (
+  ?1,
+  ?2,
+  ?3,
+  ?4,
+  ?5,
+  ?6,
+  ?7
+)
+ON CONFLICT(organization_id, service_name, environment_from, environment_to, drift_detected_at) DO NOTHING
+`
+
+type InsertEnvironmentDriftRowParams struct {
+	OrganizationID  int64
+	ServiceName     string
+	EnvironmentFrom string
+	EnvironmentTo   string
+	ArtifactIDFrom  string
+	ArtifactIDTo    string
+	DriftDetectedAt int64
+}
+
+func (q *Queries) InsertEnvironmentDriftRow(ctx context.Context, arg InsertEnvironmentDriftRowParams) error {
+	_, err := q.db.ExecContext(ctx, insertEnvironmentDriftRow,
+		arg.OrganizationID,
+		arg.ServiceName,
+		arg.EnvironmentFrom,
+		arg.EnvironmentTo,
+		arg.ArtifactIDFrom,
+		arg.ArtifactIDTo,
+		arg.DriftDetectedAt,
+	)
 	return err
 }
 
@@ -818,8 +946,10 @@ const listIncidentLinks = `-- name: ListIncidentLinks :many
 SELECT
   incident_id,
   incident_type,
+  environment,
   linked_at,
-  deployment_event_seq
+  deployment_event_seq,
+  resolved_at
 FROM service_incident_links
 WHERE organization_id = ?1
   AND service_name = ?2
@@ -836,8 +966,10 @@ type ListIncidentLinksParams struct {
 type ListIncidentLinksRow struct {
 	IncidentID         string
 	IncidentType       string
+	Environment        string
 	LinkedAt           int64
 	DeploymentEventSeq sql.NullInt64
+	ResolvedAt         sql.NullInt64
 }
 
 func (q *Queries) ListIncidentLinks(ctx context.Context, arg ListIncidentLinksParams) ([]ListIncidentLinksRow, error) {
@@ -852,8 +984,10 @@ func (q *Queries) ListIncidentLinks(ctx context.Context, arg ListIncidentLinksPa
 		if err := rows.Scan(
 			&i.IncidentID,
 			&i.IncidentType,
+			&i.Environment,
 			&i.LinkedAt,
 			&i.DeploymentEventSeq,
+			&i.ResolvedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -936,32 +1070,40 @@ WITH deploy_events AS (
   SELECT
     es.event_ts_ms AS deploy_ts_ms,
     date(datetime(es.event_ts_ms / 1000, 'unixepoch')) AS day_utc,
-    CASE
-      WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-      ELSE es.subject_id
-    END AS service_name
+    COALESCE(sia.service_name, es.service_name) AS service_name
   FROM event_store es
+  LEFT JOIN service_identity_aliases sia
+    ON sia.organization_id = es.organization_id
+    AND sia.alias = es.service_name
   WHERE es.organization_id = ?1
     AND es.subject_type = 'service'
     AND es.event_type LIKE 'dev.cdevents.service.deployed.%'
     AND es.event_ts_ms >= ?2
 ), change_events AS (
   SELECT
-    es.event_ts_ms AS change_ts_ms,
-    CASE
-      WHEN instr(json_extract(es.raw_event_json, '$.subject.content.artifactId'), 'pkg:generic/') = 1
-       AND instr(substr(json_extract(es.raw_event_json, '$.subject.content.artifactId'), 13), '@') > 0
-      THEN substr(
-        json_extract(es.raw_event_json, '$.subject.content.artifactId'),
-        13,
-        instr(substr(json_extract(es.raw_event_json, '$.subject.content.artifactId'), 13), '@') - 1
-      )
-      ELSE ''
-    END AS service_name
-  FROM event_store es
-  WHERE es.organization_id = ?1
-    AND es.subject_type = 'change'
-    AND es.event_ts_ms >= ?2
+    c.change_ts_ms,
+    COALESCE(sia.service_name, c.service_name) AS service_name
+  FROM (
+    SELECT
+      es.event_ts_ms AS change_ts_ms,
+      CASE
+        WHEN instr(es.artifact_id, 'pkg:generic/') = 1
+         AND instr(substr(es.artifact_id, 13), '@') > 0
+        THEN substr(
+          es.artifact_id,
+          13,
+          instr(substr(es.artifact_id, 13), '@') - 1
+        )
+        ELSE ''
+      END AS service_name
+    FROM event_store es
+    WHERE es.organization_id = ?1
+      AND es.subject_type = 'change'
+      AND es.event_ts_ms >= ?2
+  ) c
+  LEFT JOIN service_identity_aliases sia
+    ON sia.organization_id = ?1
+    AND sia.alias = c.service_name
 )
 SELECT day_utc, service_name, lead_seconds
 FROM (
@@ -989,7 +1131,7 @@ type ListServiceLeadTimeSamplesFromEventsParams struct {
 
 type ListServiceLeadTimeSamplesFromEventsRow struct {
 	DayUtc      interface{}
-	ServiceName interface{}
+	ServiceName string
 	LeadSeconds int64
 }
 
@@ -1063,6 +1205,142 @@ func (q *Queries) ListWeeklyThroughput(ctx context.Context, arg ListWeeklyThroug
 	return items, nil
 }
 
+const upsertIncidentLinkFromEventSeq = `-- name: UpsertIncidentLinkFromEventSeq :exec
+INSERT INTO service_incident_links (
+  organization_id,
+  service_name,
+  incident_id,
+  incident_type,
+  environment,
+  linked_at,
+  deployment_event_seq,
+  deployment_ts_ms,
+  resolved_at
+)
+SELECT
+  ev.organization_id,
+  ev.service_name,
+  ev.incident_id,
+  ev.incident_type,
+  ev.environment,
+  ev.event_ts_ms,
+  (SELECT d.event_seq FROM service_deployment_durations d
+   WHERE d.organization_id = ev.organization_id
+     AND d.service_name = ev.service_name
+     AND (ev.environment = '' OR d.environment = ev.environment)
+     AND d.event_ts_ms <= ev.event_ts_ms
+   ORDER BY d.event_ts_ms DESC, d.event_seq DESC LIMIT 1),
+  (SELECT d.event_ts_ms FROM service_deployment_durations d
+   WHERE d.organization_id = ev.organization_id
+     AND d.service_name = ev.service_name
+     AND (ev.environment = '' OR d.environment = ev.environment)
+     AND d.event_ts_ms <= ev.event_ts_ms
+   ORDER BY d.event_ts_ms DESC, d.event_seq DESC LIMIT 1),
+  CASE WHEN ev.incident_type = 'resolved' THEN ev.event_ts_ms ELSE NULL END
+FROM (
+  SELECT
+    src.organization_id,
+    src.event_ts_ms,
+    src.incident_id,
+    src.incident_type,
+    COALESCE(ea.environment, src.environment) AS environment,
+    COALESCE(sia.service_name, src.service_name) AS service_name
+  FROM (
+    SELECT
+      es.organization_id,
+      es.event_ts_ms,
+      CASE
+        WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
+        ELSE es.subject_id
+      END AS incident_id,
+      CASE
+        WHEN es.event_type LIKE 'dev.cdevents.incident.resolved.%' THEN 'resolved'
+        WHEN es.event_type LIKE 'dev.cdevents.incident.reported.%' THEN 'reported'
+        ELSE 'detected'
+      END AS incident_type,
+      es.environment_id AS environment,
+      COALESCE(
+        NULLIF(
+          CASE
+            WHEN instr(es.content_service_id, '/') > 0
+            THEN substr(es.content_service_id, instr(es.content_service_id, '/') + 1)
+            ELSE es.content_service_id
+          END,
+          ''
+        ),
+        NULLIF(es.content_service, ''),
+        CASE
+          WHEN instr(es.artifact_id, 'pkg:generic/') = 1
+           AND instr(substr(es.artifact_id, 13), '@') > 0
+          THEN substr(
+            es.artifact_id,
+            13,
+            instr(substr(es.artifact_id, 13), '@') - 1
+          )
+          ELSE ''
+        END
+      ) AS service_name
+    FROM event_store es
+    WHERE es.organization_id = ?1
+      AND es.seq = ?2
+      AND es.subject_type = 'incident'
+      AND (
+        es.event_type LIKE 'dev.cdevents.incident.detected.%'
+        OR es.event_type LIKE 'dev.cdevents.incident.reported.%'
+        OR es.event_type LIKE 'dev.cdevents.incident.resolved.%'
+      )
+  ) src
+  LEFT JOIN service_identity_aliases sia
+    ON sia.organization_id = src.organization_id
+    AND sia.alias = src.service_name
+  LEFT JOIN environment_aliases ea
+    ON ea.organization_id = src.organization_id
+    AND ea.alias = src.environment
+) ev
+WHERE ev.service_name != ''
+  AND ev.incident_id != ''
+ON CONFLICT(organization_id, service_name, incident_id) DO UPDATE SET
+  incident_type = CASE
+    WHEN excluded.resolved_at IS NULL
+     AND (service_incident_links.incident_type = 'resolved' OR excluded.linked_at < service_incident_links.linked_at)
+    THEN excluded.incident_type
+    ELSE service_incident_links.incident_type
+  END,
+  environment = CASE
+    WHEN service_incident_links.environment = '' THEN excluded.environment
+    ELSE service_incident_links.environment
+  END,
+  deployment_event_seq = CASE
+    WHEN excluded.resolved_at IS NULL
+     AND (service_incident_links.incident_type = 'resolved' OR excluded.linked_at < service_incident_links.linked_at)
+    THEN excluded.deployment_event_seq
+    ELSE service_incident_links.deployment_event_seq
+  END,
+  deployment_ts_ms = CASE
+    WHEN excluded.resolved_at IS NULL
+     AND (service_incident_links.incident_type = 'resolved' OR excluded.linked_at < service_incident_links.linked_at)
+    THEN excluded.deployment_ts_ms
+    ELSE service_incident_links.deployment_ts_ms
+  END,
+  linked_at = CASE
+    WHEN excluded.resolved_at IS NULL
+     AND (service_incident_links.incident_type = 'resolved' OR excluded.linked_at < service_incident_links.linked_at)
+    THEN excluded.linked_at
+    ELSE service_incident_links.linked_at
+  END,
+  resolved_at = COALESCE(excluded.resolved_at, service_incident_links.resolved_at)
+`
+
+type UpsertIncidentLinkFromEventSeqParams struct {
+	OrganizationID int64
+	Seq            int64
+}
+
+func (q *Queries) UpsertIncidentLinkFromEventSeq(ctx context.Context, arg UpsertIncidentLinkFromEventSeqParams) error {
+	_, err := q.db.ExecContext(ctx, upsertIncidentLinkFromEventSeq, arg.OrganizationID, arg.Seq)
+	return err
+}
+
 const upsertPipelineStatsFromEvent = `-- name: UpsertPipelineStatsFromEvent :exec
 INSERT INTO service_pipeline_stats_daily (
   organization_id,
@@ -1075,28 +1353,74 @@ INSERT INTO service_pipeline_stats_daily (
   avg_duration_seconds
 )
 SELECT
-  es.organization_id,
-  COALESCE(
-    NULLIF(json_extract(es.raw_event_json, '$.subject.content.service'), ''),
-    CASE
-      WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-      ELSE es.subject_id
-    END
-  ) AS service_name,
-  date(datetime(es.event_ts_ms / 1000, 'unixepoch')) AS day_utc,
-  CASE WHEN es.event_type LIKE 'dev.cdevents.pipeline.run.started.%' THEN 1 ELSE 0 END,
-  CASE WHEN es.event_type LIKE 'dev.cdevents.pipeline.run.succeeded.%' THEN 1 ELSE 0 END,
-  CASE WHEN es.event_type LIKE 'dev.cdevents.pipeline.run.failed.%' THEN 1 ELSE 0 END,
-  0,
+  ev.organization_id,
+  COALESCE(sia.service_name, ev.service_name) AS service_name,
+  ev.day_utc,
+  ev.started_count,
+  ev.succeeded_count,
+  ev.failed_count,
+  ev.duration_seconds,
   0
-FROM event_store es
-WHERE es.organization_id = ?1
-  AND es.seq = ?2
-  AND es.subject_type = 'pipeline'
+FROM (
+  SELECT
+    es.organization_id,
+    COALESCE(
+      NULLIF(es.content_service, ''),
+      NULLIF(
+        CASE
+          WHEN instr(es.artifact_id, 'pkg:generic/') = 1
+           AND instr(substr(es.artifact_id, 13), '@') > 0
+          THEN substr(
+            es.artifact_id,
+            13,
+            instr(substr(es.artifact_id, 13), '@') - 1
+          )
+          ELSE ''
+        END,
+        ''
+      ),
+      CASE
+        WHEN instr(es.service_name, '/') > 0
+        THEN substr(
+          es.service_name,
+          1,
+          instr(es.service_name, '/') - 1
+        )
+        ELSE es.service_name
+      END
+    ) AS service_name,
+    date(datetime(es.event_ts_ms / 1000, 'unixepoch')) AS day_utc,
+    CASE WHEN es.event_type LIKE 'dev.cdevents.pipeline.run.started.%' THEN 1 ELSE 0 END AS started_count,
+    CASE WHEN es.event_type LIKE 'dev.cdevents.pipeline.run.succeeded.%' THEN 1 ELSE 0 END AS succeeded_count,
+    CASE WHEN es.event_type LIKE 'dev.cdevents.pipeline.run.failed.%' THEN 1 ELSE 0 END AS failed_count,
+    CASE
+      WHEN es.event_type LIKE 'dev.cdevents.pipeline.run.succeeded.%' OR es.event_type LIKE 'dev.cdevents.pipeline.run.failed.%'
+      THEN COALESCE((
+        SELECT (es.event_ts_ms - started.event_ts_ms) / 1000
+        FROM event_store started
+        WHERE started.organization_id = es.organization_id
+          AND started.subject_id = es.subject_id
+          AND started.event_type LIKE 'dev.cdevents.pipeline.run.started.%'
+          AND started.event_ts_ms <= es.event_ts_ms
+        ORDER BY started.event_ts_ms DESC, started.seq DESC
+        LIMIT 1
+      ), 0)
+      ELSE 0
+    END AS duration_seconds
+  FROM event_store es
+  WHERE es.organization_id = ?1
+    AND es.seq = ?2
+    AND es.subject_type = 'pipeline'
+) ev
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = ev.organization_id
+  AND sia.alias = ev.service_name
+WHERE true
 ON CONFLICT(organization_id, service_name, day_utc) DO UPDATE SET
   pipeline_started_count = service_pipeline_stats_daily.pipeline_started_count + excluded.pipeline_started_count,
   pipeline_succeeded_count = service_pipeline_stats_daily.pipeline_succeeded_count + excluded.pipeline_succeeded_count,
   pipeline_failed_count = service_pipeline_stats_daily.pipeline_failed_count + excluded.pipeline_failed_count,
+  total_duration_seconds = service_pipeline_stats_daily.total_duration_seconds + excluded.total_duration_seconds,
   updated_at = CURRENT_TIMESTAMP
 `
 
@@ -1155,17 +1479,46 @@ INSERT INTO service_throughput_stats (
   deployments_count
 )
 SELECT
-  es.organization_id,
-  CASE
-    WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-    ELSE es.subject_id
-  END AS service_name,
-  date(datetime((es.event_ts_ms / 1000) - (strftime('%w', datetime(es.event_ts_ms / 1000, 'unixepoch')) * 86400), 'unixepoch')) AS week_start,
-  CASE WHEN es.subject_type = 'change' THEN 1 ELSE 0 END,
-  CASE WHEN es.event_type LIKE 'dev.cdevents.service.deployed.%' THEN 1 ELSE 0 END
-FROM event_store es
-WHERE es.organization_id = ?1
-  AND es.seq = ?2
+  ev.organization_id,
+  COALESCE(sia.service_name, ev.service_name) AS service_name,
+  date(datetime((ev.event_ts_ms / 1000) - (strftime('%w', datetime(ev.event_ts_ms / 1000, 'unixepoch')) * 86400), 'unixepoch')) AS week_start,
+  CASE WHEN ev.subject_type = 'change' THEN 1 ELSE 0 END,
+  CASE WHEN ev.event_type LIKE 'dev.cdevents.service.deployed.%' THEN 1 ELSE 0 END
+FROM (
+  SELECT
+    es.organization_id,
+    es.subject_type,
+    es.event_type,
+    es.event_ts_ms,
+    CASE
+      WHEN es.subject_type = 'service' THEN
+        es.service_name
+      ELSE COALESCE(
+        NULLIF(es.content_service, ''),
+        CASE
+          WHEN instr(es.artifact_id, 'pkg:generic/') = 1
+           AND instr(substr(es.artifact_id, 13), '@') > 0
+          THEN substr(
+            es.artifact_id,
+            13,
+            instr(substr(es.artifact_id, 13), '@') - 1
+          )
+          ELSE ''
+        END
+      )
+    END AS service_name
+  FROM event_store es
+  WHERE es.organization_id = ?1
+    AND es.seq = ?2
+    AND (
+      es.subject_type = 'change'
+      OR (es.subject_type = 'service' AND es.event_type LIKE 'dev.cdevents.service.deployed.%')
+    )
+) ev
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = ev.organization_id
+  AND sia.alias = ev.service_name
+WHERE ev.service_name != ''
 ON CONFLICT(organization_id, service_name, week_start) DO UPDATE SET
   changes_count = service_throughput_stats.changes_count + excluded.changes_count,
   deployments_count = service_throughput_stats.deployments_count + excluded.deployments_count,
@@ -29,37 +29,57 @@ GROUP BY day
 ORDER BY day DESC
 LIMIT sqlc.arg('limit');
 
+-- name: GetEventStorePayloadSizes :one
+-- Bytes held by event bodies, split by storage form. original_bytes is the
+-- decompressed size of the compressed bodies, so reading it decompresses
+-- every one of them.
+SELECT
+  CAST(COALESCE(SUM(CASE WHEN raw_event_compressed IS NULL THEN 1 ELSE 0 END), 0) AS INTEGER) AS plain_events,
+  CAST(COALESCE(SUM(CASE WHEN raw_event_compressed IS NULL THEN length(CAST(raw_event_json AS BLOB)) ELSE 0 END), 0) AS INTEGER) AS plain_bytes,
+  CAST(COALESCE(SUM(CASE WHEN raw_event_compressed IS NOT NULL THEN 1 ELSE 0 END), 0) AS INTEGER) AS compressed_events,
+  CAST(COALESCE(SUM(length(raw_event_compressed)), 0) AS INTEGER) AS compressed_bytes,
+  CAST(COALESCE(SUM(CASE WHEN raw_event_compressed IS NOT NULL THEN length(CAST(ddash_event_json(raw_event_json, raw_event_compressed) AS BLOB)) ELSE 0 END), 0) AS INTEGER) AS original_bytes
+FROM event_store;
+
 -- name: ListServiceLeadTimeSamplesFromEvents :many
 WITH deploy_events AS (
   SELECT
     es.event_ts_ms AS deploy_ts_ms,
     date(datetime(es.event_ts_ms / 1000, 'unixepoch')) AS day_utc,
-    CASE
-      WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-      ELSE es.subject_id
-    END AS service_name
+    COALESCE(sia.service_name, es.service_name) AS service_name
   FROM event_store es
+  LEFT JOIN service_identity_aliases sia
+    ON sia.organization_id = es.organization_id
+    AND sia.alias = es.service_name
   WHERE es.organization_id = sqlc.arg('organization_id')
     AND es.subject_type = 'service'
     AND es.event_type LIKE 'dev.cdevents.service.deployed.%'
     AND es.event_ts_ms >= sqlc.arg('since_ms')
 ), change_events AS (
   SELECT
-    es.event_ts_ms AS change_ts_ms,
-    CASE
-      WHEN instr(json_extract(es.raw_event_json, '$.subject.content.artifactId'), 'pkg:generic/') = 1
-       AND instr(substr(json_extract(es.raw_event_json, '$.subject.content.artifactId'), 13), '@') > 0
-      THEN substr(
-        json_extract(es.raw_event_json, '$.subject.content.artifactId'),
-        13,
-        instr(substr(json_extract(es.raw_event_json, '$.subject.content.artifactId'), 13), '@') - 1
-      )
-      ELSE ''
-    END AS service_name
-  FROM event_store es
-  WHERE es.organization_id = sqlc.arg('organization_id')
-    AND es.subject_type = 'change'
-    AND es.event_ts_ms >= sqlc.arg('since_ms')
+    c.change_ts_ms,
+    COALESCE(sia.service_name, c.service_name) AS service_name
+  FROM (
+    SELECT
+      es.event_ts_ms AS change_ts_ms,
+      CASE
+        WHEN instr(es.artifact_id, 'pkg:generic/') = 1
+         AND instr(substr(es.artifact_id, 13), '@') > 0
+        THEN substr(
+          es.artifact_id,
+          13,
+          instr(substr(es.artifact_id, 13), '@') - 1
+        )
+        ELSE ''
+      END AS service_name
+    FROM event_store es
+    WHERE es.organization_id = sqlc.arg('organization_id')
+      AND es.subject_type = 'change'
+      AND es.event_ts_ms >= sqlc.arg('since_ms')
+  ) c
+  LEFT JOIN service_identity_aliases sia
+    ON sia.organization_id = sqlc.arg('organization_id')
+    AND sia.alias = c.service_name
 )
 SELECT day_utc, service_name, lead_seconds
 FROM (
@@ -128,28 +148,74 @@ INSERT INTO service_pipeline_stats_daily (
   avg_duration_seconds
 )
 SELECT
-  es.organization_id,
-  COALESCE(
-    NULLIF(json_extract(es.raw_event_json, '$.subject.content.service'), ''),
-    CASE
-      WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-      ELSE es.subject_id
-    END
-  ) AS service_name,
-  date(datetime(es.event_ts_ms / 1000, 'unixepoch')) AS day_utc,
-  CASE WHEN es.event_type LIKE 'dev.cdevents.pipeline.run.started.%' THEN 1 ELSE 0 END,
-  CASE WHEN es.event_type LIKE 'dev.cdevents.pipeline.run.succeeded.%' THEN 1 ELSE 0 END,
-  CASE WHEN es.event_type LIKE 'dev.cdevents.pipeline.run.failed.%' THEN 1 ELSE 0 END,
-  0,
+  ev.organization_id,
+  COALESCE(sia.service_name, ev.service_name) AS service_name,
+  ev.day_utc,
+  ev.started_count,
+  ev.succeeded_count,
+  ev.failed_count,
+  ev.duration_seconds,
   0
-FROM event_store es
-WHERE es.organization_id = sqlc.arg('organization_id')
-  AND es.seq = sqlc.arg('seq')
-  AND es.subject_type = 'pipeline'
+FROM (
+  SELECT
+    es.organization_id,
+    COALESCE(
+      NULLIF(es.content_service, ''),
+      NULLIF(
+        CASE
+          WHEN instr(es.artifact_id, 'pkg:generic/') = 1
+           AND instr(substr(es.artifact_id, 13), '@') > 0
+          THEN substr(
+            es.artifact_id,
+            13,
+            instr(substr(es.artifact_id, 13), '@') - 1
+          )
+          ELSE ''
+        END,
+        ''
+      ),
+      CASE
+        WHEN instr(es.service_name, '/') > 0
+        THEN substr(
+          es.service_name,
+          1,
+          instr(es.service_name, '/') - 1
+        )
+        ELSE es.service_name
+      END
+    ) AS service_name,
+    date(datetime(es.event_ts_ms / 1000, 'unixepoch')) AS day_utc,
+    CASE WHEN es.event_type LIKE 'dev.cdevents.pipeline.run.started.%' THEN 1 ELSE 0 END AS started_count,
+    CASE WHEN es.event_type LIKE 'dev.cdevents.pipeline.run.succeeded.%' THEN 1 ELSE 0 END AS succeeded_count,
+    CASE WHEN es.event_type LIKE 'dev.cdevents.pipeline.run.failed.%' THEN 1 ELSE 0 END AS failed_count,
+    CASE
+      WHEN es.event_type LIKE 'dev.cdevents.pipeline.run.succeeded.%' OR es.event_type LIKE 'dev.cdevents.pipeline.run.failed.%'
+      THEN COALESCE((
+        SELECT (es.event_ts_ms - started.event_ts_ms) / 1000
+        FROM event_store started
+        WHERE started.organization_id = es.organization_id
+          AND started.subject_id = es.subject_id
+          AND started.event_type LIKE 'dev.cdevents.pipeline.run.started.%'
+          AND started.event_ts_ms <= es.event_ts_ms
+        ORDER BY started.event_ts_ms DESC, started.seq DESC
+        LIMIT 1
+      ), 0)
+      ELSE 0
+    END AS duration_seconds
+  FROM event_store es
+  WHERE es.organization_id = sqlc.arg('organization_id')
+    AND es.seq = sqlc.arg('seq')
+    AND es.subject_type = 'pipeline'
+) ev
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = ev.organization_id
+  AND sia.alias = ev.service_name
+WHERE true
 ON CONFLICT(organization_id, service_name, day_utc) DO UPDATE SET
   pipeline_started_count = service_pipeline_stats_daily.pipeline_started_count + excluded.pipeline_started_count,
   pipeline_succeeded_count = service_pipeline_stats_daily.pipeline_succeeded_count + excluded.pipeline_succeeded_count,
   pipeline_failed_count = service_pipeline_stats_daily.pipeline_failed_count + excluded.pipeline_failed_count,
+  total_duration_seconds = service_pipeline_stats_daily.total_duration_seconds + excluded.total_duration_seconds,
   updated_at = CURRENT_TIMESTAMP;
 
 -- name: GetPipelineStats30d :one
@@ -180,16 +246,19 @@ INSERT INTO service_deployment_durations (
 )
 SELECT
   es.organization_id,
-  CASE
-    WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-    ELSE es.subject_id
-  END AS service_name,
-  COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown'),
+  COALESCE(sia.service_name, es.service_name) AS service_name,
+  COALESCE(ea.environment, es.environment),
   es.seq,
   es.event_ts_ms,
-  COALESCE(json_extract(es.raw_event_json, '$.subject.content.durationSeconds'), 0),
-  COALESCE(json_extract(es.raw_event_json, '$.subject.content.artifactId'), '')
+  es.duration_seconds,
+  es.artifact_id
 FROM event_store es
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
+LEFT JOIN environment_aliases ea
+  ON ea.organization_id = es.organization_id
+  AND ea.alias = es.environment
 WHERE es.organization_id = sqlc.arg('organization_id')
   AND es.seq = sqlc.arg('seq')
   AND es.subject_type = 'service'
@@ -252,7 +321,7 @@ SELECT
   ses2.environment,
   ses1.latest_artifact_id,
   ses2.latest_artifact_id,
-  strftime('%s', 'now') * 1000
+  sqlc.arg('detected_at')
 FROM service_env_state ses1
 JOIN service_env_state ses2 ON
   ses1.organization_id = ses2.organization_id
@@ -265,6 +334,27 @@ WHERE ses1.organization_id = sqlc.arg('organization_id')
   AND ses2.latest_artifact_id != ''
 ON CONFLICT(organization_id, service_name, environment_from, environment_to, drift_detected_at) DO NOTHING;
 
+-- name: InsertEnvironmentDriftRow :exec
+INSERT INTO service_environment_drift (
+  organization_id,
+  service_name,
+  environment_from,
+  environment_to,
+  artifact_id_from,
+  artifact_id_to,
+  drift_detected_at
+)
+VALUES (
+  sqlc.arg('organization_id'),
+  sqlc.arg('service_name'),
+  sqlc.arg('environment_from'),
+  sqlc.arg('environment_to'),
+  sqlc.arg('artifact_id_from'),
+  sqlc.arg('artifact_id_to'),
+  sqlc.arg('drift_detected_at')
+)
+ON CONFLICT(organization_id, service_name, environment_from, environment_to, drift_detected_at) DO NOTHING;
+
 -- name: GetEnvironmentDriftCount :one
 SELECT COUNT(*) AS drift_count
 FROM service_environment_drift
@@ -302,6 +392,32 @@ ON CONFLICT(organization_id, service_name, day_utc) DO UPDATE SET
   redeploy_count = service_redeployment_stats.redeploy_count + excluded.redeploy_count,
   updated_at = CURRENT_TIMESTAMP;
 
+-- name: GetRedeploymentCheckFromEventSeq :one
+SELECT
+  CAST(COALESCE(sia.service_name, es.service_name) AS TEXT) AS service_name,
+  CAST(date(datetime(es.event_ts_ms / 1000, 'unixepoch')) AS TEXT) AS day_utc,
+  CAST(CASE
+    WHEN es.artifact_id != ''
+     AND es.artifact_id = COALESCE(ses.latest_artifact_id, '')
+    THEN '1'
+    ELSE '0'
+  END AS TEXT) AS same_artifact
+FROM event_store es
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = es.organization_id
+  AND sia.alias = es.service_name
+LEFT JOIN environment_aliases ea
+  ON ea.organization_id = es.organization_id
+  AND ea.alias = es.environment
+LEFT JOIN service_env_state ses ON
+  ses.organization_id = es.organization_id
+  AND ses.service_name = COALESCE(sia.service_name, es.service_name)
+  AND ses.environment = COALESCE(ea.environment, es.environment)
+WHERE es.organization_id = sqlc.arg('organization_id')
+  AND es.seq = sqlc.arg('seq')
+  AND es.subject_type = 'service'
+  AND es.event_type LIKE 'dev.cdevents.service.deployed.%';
+
 -- name: GetRedeploymentRate30d :one
 SELECT
   COALESCE(SUM(redeploy_count), 0) AS redeploy_count,
@@ -325,17 +441,46 @@ INSERT INTO service_throughput_stats (
   deployments_count
 )
 SELECT
-  es.organization_id,
-  CASE
-    WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-    ELSE es.subject_id
-  END AS service_name,
-  date(datetime((es.event_ts_ms / 1000) - (strftime('%w', datetime(es.event_ts_ms / 1000, 'unixepoch')) * 86400), 'unixepoch')) AS week_start,
-  CASE WHEN es.subject_type = 'change' THEN 1 ELSE 0 END,
-  CASE WHEN es.event_type LIKE 'dev.cdevents.service.deployed.%' THEN 1 ELSE 0 END
-FROM event_store es
-WHERE es.organization_id = sqlc.arg('organization_id')
-  AND es.seq = sqlc.arg('seq')
+  ev.organization_id,
+  COALESCE(sia.service_name, ev.service_name) AS service_name,
+  date(datetime((ev.event_ts_ms / 1000) - (strftime('%w', datetime(ev.event_ts_ms / 1000, 'unixepoch')) * 86400), 'unixepoch')) AS week_start,
+  CASE WHEN ev.subject_type = 'change' THEN 1 ELSE 0 END,
+  CASE WHEN ev.event_type LIKE 'dev.cdevents.service.deployed.%' THEN 1 ELSE 0 END
+FROM (
+  SELECT
+    es.organization_id,
+    es.subject_type,
+    es.event_type,
+    es.event_ts_ms,
+    CASE
+      WHEN es.subject_type = 'service' THEN
+        es.service_name
+      ELSE COALESCE(
+        NULLIF(es.content_service, ''),
+        CASE
+          WHEN instr(es.artifact_id, 'pkg:generic/') = 1
+           AND instr(substr(es.artifact_id, 13), '@') > 0
+          THEN substr(
+            es.artifact_id,
+            13,
+            instr(substr(es.artifact_id, 13), '@') - 1
+          )
+          ELSE ''
+        END
+      )
+    END AS service_name
+  FROM event_store es
+  WHERE es.organization_id = sqlc.arg('organization_id')
+    AND es.seq = sqlc.arg('seq')
+    AND (
+      es.subject_type = 'change'
+      OR (es.subject_type = 'service' AND es.event_type LIKE 'dev.cdevents.service.deployed.%')
+    )
+) ev
+LEFT JOIN service_identity_aliases sia
+  ON sia.organization_id = ev.organization_id
+  AND sia.alias = ev.service_name
+WHERE ev.service_name != ''
 ON CONFLICT(organization_id, service_name, week_start) DO UPDATE SET
   changes_count = service_throughput_stats.changes_count + excluded.changes_count,
   deployments_count = service_throughput_stats.deployments_count + excluded.deployments_count,
@@ -352,7 +497,7 @@ SELECT
 FROM service_throughput_stats
 WHERE organization_id = sqlc.arg('organization_id')
   AND service_name = sqlc.arg('service_name')
-  AND week_start >= date('now', '-12 week');
+  AND week_start >= date('now', '-84 day');
 
 -- name: ListWeeklyThroughput :many
 SELECT
@@ -385,36 +530,157 @@ VALUES (
 )
 ON CONFLICT(organization_id, service_name, incident_id) DO NOTHING;
 
--- name: GetMTTR :one
-WITH incident_resolutions AS (
-  SELECT
-    il.service_name,
-    il.incident_id,
-    il.linked_at AS incident_time_ms,
-    (SELECT es.event_ts_ms FROM event_store es
-     WHERE es.organization_id = il.organization_id
-       AND es.subject_id LIKE '%' || il.service_name || '%'
-       AND es.event_type LIKE 'dev.cdevents.service.deployed.%'
-       AND es.event_ts_ms > il.linked_at
-     ORDER BY es.event_ts_ms ASC LIMIT 1) AS resolved_at_ms
-  FROM service_incident_links il
-  WHERE il.organization_id = sqlc.arg('organization_id')
-    AND il.linked_at >= sqlc.arg('since_ms')
+-- name: UpsertIncidentLinkFromEventSeq :exec
+INSERT INTO service_incident_links (
+  organization_id,
+  service_name,
+  incident_id,
+  incident_type,
+  environment,
+  linked_at,
+  deployment_event_seq,
+  deployment_ts_ms,
+  resolved_at
 )
+SELECT
+  ev.organization_id,
+  ev.service_name,
+  ev.incident_id,
+  ev.incident_type,
+  ev.environment,
+  ev.event_ts_ms,
+  (SELECT d.event_seq FROM service_deployment_durations d
+   WHERE d.organization_id = ev.organization_id
+     AND d.service_name = ev.service_name
+     AND (ev.environment = '' OR d.environment = ev.environment)
+     AND d.event_ts_ms <= ev.event_ts_ms
+   ORDER BY d.event_ts_ms DESC, d.event_seq DESC LIMIT 1),
+  (SELECT d.event_ts_ms FROM service_deployment_durations d
+   WHERE d.organization_id = ev.organization_id
+     AND d.service_name = ev.service_name
+     AND (ev.environment = '' OR d.environment = ev.environment)
+     AND d.event_ts_ms <= ev.event_ts_ms
+   ORDER BY d.event_ts_ms DESC, d.event_seq DESC LIMIT 1),
+  CASE WHEN ev.incident_type = 'resolved' THEN ev.event_ts_ms ELSE NULL END
+FROM (
+  SELECT
+    src.organization_id,
+    src.event_ts_ms,
+    src.incident_id,
+    src.incident_type,
+    COALESCE(ea.environment, src.environment) AS environment,
+    COALESCE(sia.service_name, src.service_name) AS service_name
+  FROM (
+    SELECT
+      es.organization_id,
+      es.event_ts_ms,
+      CASE
+        WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
+        ELSE es.subject_id
+      END AS incident_id,
+      CASE
+        WHEN es.event_type LIKE 'dev.cdevents.incident.resolved.%' THEN 'resolved'
+        WHEN es.event_type LIKE 'dev.cdevents.incident.reported.%' THEN 'reported'
+        ELSE 'detected'
+      END AS incident_type,
+      es.environment_id AS environment,
+      COALESCE(
+        NULLIF(
+          CASE
+            WHEN instr(es.content_service_id, '/') > 0
+            THEN substr(es.content_service_id, instr(es.content_service_id, '/') + 1)
+            ELSE es.content_service_id
+          END,
+          ''
+        ),
+        NULLIF(es.content_service, ''),
+        CASE
+          WHEN instr(es.artifact_id, 'pkg:generic/') = 1
+           AND instr(substr(es.artifact_id, 13), '@') > 0
+          THEN substr(
+            es.artifact_id,
+            13,
+            instr(substr(es.artifact_id, 13), '@') - 1
+          )
+          ELSE ''
+        END
+      ) AS service_name
+    FROM event_store es
+    WHERE es.organization_id = sqlc.arg('organization_id')
+      AND es.seq = sqlc.arg('seq')
+      AND es.subject_type = 'incident'
+      AND (
+        es.event_type LIKE 'dev.cdevents.incident.detected.%'
+        OR es.event_type LIKE 'dev.cdevents.incident.reported.%'
+        OR es.event_type LIKE 'dev.cdevents.incident.resolved.%'
+      )
+  ) src
+  LEFT JOIN service_identity_aliases sia
+    ON sia.organization_id = src.organization_id
+    AND sia.alias = src.service_name
+  LEFT JOIN environment_aliases ea
+    ON ea.organization_id = src.organization_id
+    AND ea.alias = src.environment
+) ev
+WHERE ev.service_name != ''
+  AND ev.incident_id != ''
+ON CONFLICT(organization_id, service_name, incident_id) DO UPDATE SET
+  incident_type = CASE
+    WHEN excluded.resolved_at IS NULL
+     AND (service_incident_links.incident_type = 'resolved' OR excluded.linked_at < service_incident_links.linked_at)
+    THEN excluded.incident_type
+    ELSE service_incident_links.incident_type
+  END,
+  environment = CASE
+    WHEN service_incident_links.environment = '' THEN excluded.environment
+    ELSE service_incident_links.environment
+  END,
+  deployment_event_seq = CASE
+    WHEN excluded.resolved_at IS NULL
+     AND (service_incident_links.incident_type = 'resolved' OR excluded.linked_at < service_incident_links.linked_at)
+    THEN excluded.deployment_event_seq
+    ELSE service_incident_links.deployment_event_seq
+  END,
+  deployment_ts_ms = CASE
+    WHEN excluded.resolved_at IS NULL
+     AND (service_incident_links.incident_type = 'resolved' OR excluded.linked_at < service_incident_links.linked_at)
+    THEN excluded.deployment_ts_ms
+    ELSE service_incident_links.deployment_ts_ms
+  END,
+  linked_at = CASE
+    WHEN excluded.resolved_at IS NULL
+     AND (service_incident_links.incident_type = 'resolved' OR excluded.linked_at < service_incident_links.linked_at)
+    THEN excluded.linked_at
+    ELSE service_incident_links.linked_at
+  END,
+  resolved_at = COALESCE(excluded.resolved_at, service_incident_links.resolved_at);
+
+-- Links still typed 'resolved' never saw a detected or reported event, so
+-- they carry no detection time and stay out of the incident aggregates.
+-- name: GetMTTR :one
 SELECT
   COUNT(*) AS incident_count,
-  COALESCE(AVG(resolved_at_ms - incident_time_ms), 0) / 1000 AS mttr_seconds,
-  COALESCE(MIN(resolved_at_ms - incident_time_ms), 0) / 1000 AS mttd_seconds,
-  COALESCE(MAX(resolved_at_ms - incident_time_ms), 0) / 1000 AS mtte_seconds
-FROM incident_resolutions
-WHERE resolved_at_ms IS NOT NULL;
+  CAST(COALESCE(AVG(CASE WHEN resolved_at IS NOT NULL THEN resolved_at - linked_at END), 0) / 1000 AS REAL) AS mttr_seconds,
+  CAST(COALESCE(AVG(CASE WHEN deployment_ts_ms IS NOT NULL THEN linked_at - deployment_ts_ms END), 0) / 1000 AS REAL) AS mttd_seconds,
+  CAST(COALESCE(MAX(CASE WHEN resolved_at IS NOT NULL THEN resolved_at - linked_at END), 0) / 1000 AS REAL) AS mtte_seconds,
+  (SELECT COUNT(*) FROM service_incident_links open_links
+   WHERE open_links.organization_id = sqlc.arg('organization_id')
+     AND (CAST(sqlc.arg('service_name') AS TEXT) = '' OR open_links.service_name = sqlc.arg('service_name'))
+     AND open_links.resolved_at IS NULL) AS open_count
+FROM service_incident_links il
+WHERE il.organization_id = sqlc.arg('organization_id')
+  AND (CAST(sqlc.arg('service_name') AS TEXT) = '' OR il.service_name = sqlc.arg('service_name'))
+  AND il.incident_type <> 'resolved'
+  AND il.linked_at >= sqlc.arg('since_ms');
 
 -- name: ListIncidentLinks :many
 SELECT
   incident_id,
   incident_type,
+  environment,
   linked_at,
-  deployment_event_seq
+  deployment_event_seq,
+  resolved_at
 FROM service_incident_links
 WHERE organization_id = sqlc.arg('organization_id')
   AND service_name = sqlc.arg('service_name')
@@ -443,12 +709,12 @@ SELECT
        FROM (
          SELECT es.event_ts_ms AS change_ts_ms,
            CASE
-             WHEN instr(json_extract(es.raw_event_json, '$.subject.content.artifactId'), 'pkg:generic/') = 1
-              AND instr(substr(json_extract(es.raw_event_json, '$.subject.content.artifactId'), 13), '@') > 0
+             WHEN instr(es.artifact_id, 'pkg:generic/') = 1
+              AND instr(substr(es.artifact_id, 13), '@') > 0
              THEN substr(
-               json_extract(es.raw_event_json, '$.subject.content.artifactId'),
+               es.artifact_id,
                13,
-               instr(substr(json_extract(es.raw_event_json, '$.subject.content.artifactId'), 13), '@') - 1
+               instr(substr(es.artifact_id, 13), '@') - 1
              )
              ELSE ''
            END AS service_name
@@ -457,17 +723,20 @@ SELECT
            AND es.subject_type = 'change'
            AND es.event_ts_ms >= sqlc.arg('since_ms')
        ) c
-       WHERE c.service_name = d.service_name
+       LEFT JOIN service_identity_aliases sia
+         ON sia.organization_id = sqlc.arg('organization_id')
+         AND sia.alias = c.service_name
+       WHERE COALESCE(sia.service_name, c.service_name) = d.service_name
          AND c.change_ts_ms <= d.deploy_ts_ms
      )) / 1000 AS lead_seconds
      FROM (
        SELECT
          es.event_ts_ms AS deploy_ts_ms,
-         CASE
-           WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1)
-           ELSE es.subject_id
-         END AS service_name
+         COALESCE(sia.service_name, es.service_name) AS service_name
        FROM event_store es
+       LEFT JOIN service_identity_aliases sia
+         ON sia.organization_id = es.organization_id
+         AND sia.alias = es.service_name
        WHERE es.organization_id = sqlc.arg('organization_id')
          AND es.subject_type = 'service'
          AND es.event_type LIKE 'dev.cdevents.service.deployed.%'
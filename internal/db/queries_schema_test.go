@@ -0,0 +1,35 @@
+package db
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// sqlcArgPattern matches the sqlc parameter macros, which SQLite cannot parse.
+var sqlcArgPattern = regexp.MustCompile(`sqlc\.(arg|narg|slice)\('[a-z_]+'\)`)
+
+// TestQueries_PrepareAgainstMigratedSchema catches queries that stop compiling
+// when a migration adds a column, such as a bare name that becomes ambiguous
+// across joined tables.
+func TestQueries_PrepareAgainstMigratedSchema(t *testing.T) {
+	database := newTestDatabase(t)
+
+	for _, file := range []string{"queries.sql", "queries_analytics.sql"} {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read %s: %v", file, err)
+		}
+		for _, block := range strings.Split(string(raw), "-- name: ")[1:] {
+			header, body, _ := strings.Cut(block, "\n")
+			name, _, _ := strings.Cut(header, " ")
+			stmt, err := database.db.Prepare(sqlcArgPattern.ReplaceAllString(body, "?"))
+			if err != nil {
+				t.Errorf("%s %s: %v", file, name, err)
+				continue
+			}
+			_ = stmt.Close()
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/fr0stylo/ddash/internal/db/queries"
+)
+
+func TestServiceIdentityRules_MergeServicesAcrossRebuild(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+	appendEvent(t, ctx, database, org.ID, "deploy-1", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(0), "service/payments-api", "staging", "pkg:generic/payments-api@abc")
+	appendEvent(t, ctx, database, org.ID, "deploy-2", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(time.Minute), "service/payments", "production", "pkg:generic/payments@abc")
+	if err := database.UpsertServiceMetadata(ctx, queries.UpsertServiceMetadataParams{OrganizationID: org.ID, ServiceName: "payments-api", Label: "Owner", Value: "team-a"}); err != nil {
+		t.Fatalf("upsert metadata: %v", err)
+	}
+	if err := database.UpsertServiceDependency(ctx, queries.UpsertServiceDependencyParams{OrganizationID: org.ID, ServiceName: "payments-api", DependsOnServiceName: "ledger"}); err != nil {
+		t.Fatalf("upsert dependency: %v", err)
+	}
+
+	if _, err := database.CreateServiceIdentityRule(ctx, queries.CreateServiceIdentityRuleParams{OrganizationID: org.ID, Kind: ServiceIdentityKindRegex, Pattern: "("}); !errors.Is(err, ErrInvalidServiceIdentityRule) {
+		t.Fatalf("expected invalid regex to be rejected, got %v", err)
+	}
+	if _, err := database.CreateServiceIdentityRule(ctx, queries.CreateServiceIdentityRuleParams{OrganizationID: org.ID, Kind: ServiceIdentityKindAlias, Pattern: "payments-api", ServiceName: "payments"}); err != nil {
+		t.Fatalf("create alias rule: %v", err)
+	}
+
+	metadata, err := database.ListServiceMetadataByService(ctx, queries.ListServiceMetadataByServiceParams{OrganizationID: org.ID, ServiceName: "payments"})
+	if err != nil || len(metadata) != 1 || metadata[0].Value != "team-a" {
+		t.Fatalf("expected metadata to carry over: %+v err=%v", metadata, err)
+	}
+	dependencies, err := database.ListOrganizationServiceDependencies(ctx, org.ID)
+	if err != nil || len(dependencies) != 1 || dependencies[0].ServiceName != "payments" {
+		t.Fatalf("expected dependency to carry over: %+v err=%v", dependencies, err)
+	}
+
+	if _, err := database.RebuildServiceProjections(ctx, org.ID); err != nil {
+		t.Fatalf("rebuild projections: %v", err)
+	}
+	if names := projectedServiceNames(t, ctx, database, org.ID); !slices.Equal(names, []string{"payments"}) {
+		t.Fatalf("expected a single merged service, got %v", names)
+	}
+	state, err := database.GetServiceCurrentState(ctx, queries.GetServiceCurrentStateParams{OrganizationID: org.ID, ServiceName: "payments"})
+	if err != nil || state.DriftCount != 2 {
+		t.Fatalf("unexpected merged current state: %+v err=%v", state, err)
+	}
+
+	// Names a regex rule rewrites are mapped as soon as their first event is
+	// appended, without another rebuild.
+	if _, err := database.CreateServiceIdentityRule(ctx, queries.CreateServiceIdentityRuleParams{OrganizationID: org.ID, Kind: ServiceIdentityKindRegex, Pattern: `^(.+)-canary$`, ServiceName: "$1"}); err != nil {
+		t.Fatalf("create regex rule: %v", err)
+	}
+	appendEvent(t, ctx, database, org.ID, "deploy-3", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(2*time.Minute), "service/payments-canary", "canary", "pkg:generic/payments@def")
+	if names := projectedServiceNames(t, ctx, database, org.ID); !slices.Equal(names, []string{"payments"}) {
+		t.Fatalf("expected canary to project onto payments, got %v", names)
+	}
+}
+
+func TestServiceIdentityRules_DeletedRuleStopsMappingNewEvents(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+	rule, err := database.CreateServiceIdentityRule(ctx, queries.CreateServiceIdentityRuleParams{OrganizationID: org.ID, Kind: ServiceIdentityKindRegex, Pattern: `^(.+)-canary$`, ServiceName: "$1"})
+	if err != nil {
+		t.Fatalf("create regex rule: %v", err)
+	}
+	appendEvent(t, ctx, database, org.ID, "deploy-1", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(0), "service/payments-canary", "canary", "pkg:generic/payments@abc")
+
+	if _, err := database.DeleteServiceIdentityRule(ctx, queries.DeleteServiceIdentityRuleParams{OrganizationID: org.ID, ID: rule.ID}); err != nil {
+		t.Fatalf("delete regex rule: %v", err)
+	}
+	appendEvent(t, ctx, database, org.ID, "deploy-2", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(time.Minute), "service/ledger-canary", "canary", "pkg:generic/ledger@abc")
+
+	aliases, err := database.ListServiceIdentityAliases(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("list aliases: %v", err)
+	}
+	if len(aliases) != 0 {
+		t.Fatalf("expected the deleted rule to stop mapping names, got %+v", aliases)
+	}
+}
+
+func projectedServiceNames(t *testing.T, ctx context.Context, database *Database, organizationID int64) []string {
+	t.Helper()
+	rows, err := database.db.QueryContext(ctx, `SELECT service_name FROM service_env_state WHERE organization_id = ?1
+		UNION SELECT service_name FROM service_current_state WHERE organization_id = ?1
+		UNION SELECT service_name FROM service_delivery_stats_daily WHERE organization_id = ?1
+		ORDER BY 1`, organizationID)
+	if err != nil {
+		t.Fatalf("list projected services: %v", err)
+	}
+	defer func() { _ = rows.Close() }()
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("scan projected service: %v", err)
+		}
+		names = append(names, name)
+	}
+	return names
+}
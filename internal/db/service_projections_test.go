@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/fr0stylo/ddash/internal/db/queries"
 )
@@ -16,8 +18,8 @@ func TestAppendEventStore_UpdatesServiceProjectionTables(t *testing.T) {
 	database := newTestDatabase(t)
 	org := createTestOrganization(t, ctx, database)
 
-	appendEvent(t, ctx, database, org.ID, "p1", "dev.cdevents.service.deployed.0.3.0", "2026-02-21T10:00:00Z", "service/payments", "staging", "pkg:generic/payments@v1")
-	appendEvent(t, ctx, database, org.ID, "p2", "dev.cdevents.service.rolledback.0.3.0", "2026-02-21T11:00:00Z", "service/payments", "staging", "pkg:generic/payments@v0")
+	appendEvent(t, ctx, database, org.ID, "p1", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(0), "service/payments", "staging", "pkg:generic/payments@v1")
+	appendEvent(t, ctx, database, org.ID, "p2", "dev.cdevents.service.rolledback.0.3.0", recentTimestamp(time.Hour), "service/payments", "staging", "pkg:generic/payments@v0")
 
 	state, err := database.GetServiceCurrentState(ctx, queries.GetServiceCurrentStateParams{
 		OrganizationID: org.ID,
@@ -87,14 +89,15 @@ func TestAppendEventStore_DuplicateDoesNotDoubleCountProjection(t *testing.T) {
 	database := newTestDatabase(t)
 	org := createTestOrganization(t, ctx, database)
 
-	raw := `{"context":{"id":"dup-1","source":"tests/source","type":"dev.cdevents.service.deployed.0.3.0","timestamp":"2026-02-21T10:00:00Z","specversion":"0.5.0"},"subject":{"id":"service/catalog","source":"tests/source","content":{"environment":{"id":"prod"},"artifactId":"pkg:generic/catalog@v1"}}}`
+	timestamp := recentTimestamp(0)
+	raw := fmt.Sprintf(`{"context":{"id":"dup-1","source":"tests/source","type":"dev.cdevents.service.deployed.0.3.0","timestamp":%q,"specversion":"0.5.0"},"subject":{"id":"service/catalog","source":"tests/source","content":{"environment":{"id":"prod"},"artifactId":"pkg:generic/catalog@v1"}}}`, timestamp)
 	params := queries.AppendEventStoreParams{
 		OrganizationID: org.ID,
 		EventID:        "dup-1",
 		EventType:      "dev.cdevents.service.deployed.0.3.0",
 		EventSource:    "tests/source",
-		EventTimestamp: "2026-02-21T10:00:00Z",
-		EventTsMs:      mustUnixMillis(t, "2026-02-21T10:00:00Z"),
+		EventTimestamp: timestamp,
+		EventTsMs:      mustUnixMillis(t, timestamp),
 		SubjectID:      "service/catalog",
 		SubjectSource:  sql.NullString{String: "tests/source", Valid: true},
 		SubjectType:    "service",
@@ -119,3 +122,49 @@ func TestAppendEventStore_DuplicateDoesNotDoubleCountProjection(t *testing.T) {
 		t.Fatalf("duplicate should not double count: got=%d want=1", toInt64(stats.DeploySuccessCount))
 	}
 }
+
+func TestListServiceChangesAfterSeq_OneRowPerResolvedService(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	database := newTestDatabase(t)
+	org := createTestOrganization(t, ctx, database)
+	if _, err := database.CreateServiceIdentityRule(ctx, queries.CreateServiceIdentityRuleParams{OrganizationID: org.ID, Kind: ServiceIdentityKindAlias, Pattern: "payments-api", ServiceName: "payments"}); err != nil {
+		t.Fatalf("create alias rule: %v", err)
+	}
+	appendEvent(t, ctx, database, org.ID, "deploy-1", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(0), "service/payments-api", "staging", "pkg:generic/payments@abc")
+	appendEvent(t, ctx, database, org.ID, "deploy-2", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(time.Minute), "service/ledger", "staging", "pkg:generic/ledger@abc")
+	appendEvent(t, ctx, database, org.ID, "deploy-3", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(2*time.Minute), "service/payments-api", "production", "pkg:generic/payments@abc")
+	appendEvent(t, ctx, database, org.ID, "deploy-4", "dev.cdevents.service.deployed.0.3.0", recentTimestamp(3*time.Minute), "service/billing", "staging", "pkg:generic/billing@abc")
+	appendSubjectEvent(t, ctx, database, org.ID, "pipeline-1", "dev.cdevents.pipelinerun.finished.0.2.0", recentTimestamp(4*time.Minute), "pipelinerun", "pipeline/billing", "", "")
+
+	seqs := map[string]int64{}
+	for _, eventID := range []string{"deploy-2", "deploy-3", "deploy-4"} {
+		var seq int64
+		if err := database.db.QueryRowContext(ctx, "SELECT seq FROM event_store WHERE organization_id = ? AND event_id = ?", org.ID, eventID).Scan(&seq); err != nil {
+			t.Fatalf("read seq of %s: %v", eventID, err)
+		}
+		seqs[eventID] = seq
+	}
+
+	changes, err := database.ListServiceChangesAfterSeq(ctx, queries.ListServiceChangesAfterSeqParams{OrganizationID: org.ID, AfterSeq: 0, Limit: 10})
+	if err != nil {
+		t.Fatalf("list service changes: %v", err)
+	}
+	want := []queries.ListServiceChangesAfterSeqRow{
+		{ServiceName: "ledger", LastSeq: seqs["deploy-2"]},
+		{ServiceName: "payments", LastSeq: seqs["deploy-3"]},
+		{ServiceName: "billing", LastSeq: seqs["deploy-4"]},
+	}
+	if !slices.Equal(changes, want) {
+		t.Fatalf("unexpected service changes: got=%+v want=%+v", changes, want)
+	}
+
+	changes, err = database.ListServiceChangesAfterSeq(ctx, queries.ListServiceChangesAfterSeqParams{OrganizationID: org.ID, AfterSeq: seqs["deploy-2"], Limit: 10})
+	if err != nil {
+		t.Fatalf("list service changes after seq: %v", err)
+	}
+	if !slices.Equal(changes, want[1:]) {
+		t.Fatalf("unexpected service changes after seq: got=%+v want=%+v", changes, want[1:])
+	}
+}
@@ -4,25 +4,25 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"fmt"
 	"strings"
+	"time"
 
 	"github.com/fr0stylo/ddash/internal/db/queries"
 )
 
 // GetOrganizationByAuthToken fetches an org by auth token.
 func (c *Database) GetOrganizationByAuthToken(ctx context.Context, authToken string) (queries.Organization, error) {
-	return c.Queries.GetOrganizationByAuthToken(ctx, authToken)
+	return c.reader.GetOrganizationByAuthToken(ctx, authToken)
 }
 
 // GetOrganizationByID fetches an org by id.
 func (c *Database) GetOrganizationByID(ctx context.Context, id int64) (queries.Organization, error) {
-	return c.Queries.GetOrganizationByID(ctx, id)
+	return c.reader.GetOrganizationByID(ctx, id)
 }
 
 // ListOrganizations returns all organizations.
 func (c *Database) ListOrganizations(ctx context.Context) ([]queries.Organization, error) {
-	return c.Queries.ListOrganizations(ctx)
+	return c.reader.ListOrganizations(ctx)
 }
 
 // UpdateOrganizationName updates organization name.
@@ -51,22 +51,22 @@ func (c *Database) UpsertUser(ctx context.Context, params queries.UpsertUserPara
 
 // GetUserByID fetches a user by id.
 func (c *Database) GetUserByID(ctx context.Context, id int64) (queries.User, error) {
-	return c.Queries.GetUserByID(ctx, id)
+	return c.reader.GetUserByID(ctx, id)
 }
 
 // GetUserByEmailOrNickname fetches a user by email/nickname.
 func (c *Database) GetUserByEmailOrNickname(ctx context.Context, email, nickname string) (queries.User, error) {
-	return c.Queries.GetUserByEmailOrNickname(ctx, queries.GetUserByEmailOrNicknameParams{Email: email, Nickname: nickname})
+	return c.reader.GetUserByEmailOrNickname(ctx, queries.GetUserByEmailOrNicknameParams{Email: email, Nickname: nickname})
 }
 
 // ListOrganizationsByUser lists orgs where user is a member.
 func (c *Database) ListOrganizationsByUser(ctx context.Context, userID int64) ([]queries.Organization, error) {
-	return c.Queries.ListOrganizationsByUser(ctx, userID)
+	return c.reader.ListOrganizationsByUser(ctx, userID)
 }
 
 // GetOrganizationMemberRole returns one user's role in org.
 func (c *Database) GetOrganizationMemberRole(ctx context.Context, organizationID, userID int64) (string, error) {
-	return c.Queries.GetOrganizationMemberRole(ctx, queries.GetOrganizationMemberRoleParams{OrganizationID: organizationID, UserID: userID})
+	return c.reader.GetOrganizationMemberRole(ctx, queries.GetOrganizationMemberRoleParams{OrganizationID: organizationID, UserID: userID})
 }
 
 // UpsertOrganizationMember upserts org membership and role.
@@ -81,12 +81,12 @@ func (c *Database) DeleteOrganizationMember(ctx context.Context, organizationID,
 
 // CountOrganizationOwners returns owner count in org.
 func (c *Database) CountOrganizationOwners(ctx context.Context, organizationID int64) (int64, error) {
-	return c.Queries.CountOrganizationOwners(ctx, organizationID)
+	return c.reader.CountOrganizationOwners(ctx, organizationID)
 }
 
 // ListOrganizationMembers lists members joined with user profile.
 func (c *Database) ListOrganizationMembers(ctx context.Context, organizationID int64) ([]queries.ListOrganizationMembersRow, error) {
-	return c.Queries.ListOrganizationMembers(ctx, organizationID)
+	return c.reader.ListOrganizationMembers(ctx, organizationID)
 }
 
 // UpsertGitHubInstallationMapping upserts installation mapping for an organization.
@@ -96,7 +96,7 @@ func (c *Database) UpsertGitHubInstallationMapping(ctx context.Context, params q
 
 // ListGitHubInstallationMappings lists GitHub installation mappings for an organization.
 func (c *Database) ListGitHubInstallationMappings(ctx context.Context, organizationID int64) ([]queries.ListGitHubInstallationMappingsRow, error) {
-	return c.Queries.ListGitHubInstallationMappings(ctx, organizationID)
+	return c.reader.ListGitHubInstallationMappings(ctx, organizationID)
 }
 
 // DeleteGitHubInstallationMapping deletes one installation mapping for an organization.
@@ -106,7 +106,7 @@ func (c *Database) DeleteGitHubInstallationMapping(ctx context.Context, params q
 
 // GetOrganizationByGitHubInstallationID resolves organization for GitHub installation.
 func (c *Database) GetOrganizationByGitHubInstallationID(ctx context.Context, installationID int64) (queries.Organization, error) {
-	return c.Queries.GetOrganizationByGitHubInstallationID(ctx, installationID)
+	return c.reader.GetOrganizationByGitHubInstallationID(ctx, installationID)
 }
 
 // CreateGitHubSetupIntent stores setup intent state.
@@ -116,7 +116,7 @@ func (c *Database) CreateGitHubSetupIntent(ctx context.Context, params queries.C
 
 // GetGitHubSetupIntentByState resolves setup intent by state.
 func (c *Database) GetGitHubSetupIntentByState(ctx context.Context, state string) (queries.GetGitHubSetupIntentByStateRow, error) {
-	return c.Queries.GetGitHubSetupIntentByState(ctx, state)
+	return c.reader.GetGitHubSetupIntentByState(ctx, state)
 }
 
 // DeleteGitHubSetupIntent removes setup intent state.
@@ -131,12 +131,22 @@ func (c *Database) UpsertGitLabProjectMapping(ctx context.Context, params querie
 
 // GetOrganizationByGitLabProjectID resolves organization for GitLab project id.
 func (c *Database) GetOrganizationByGitLabProjectID(ctx context.Context, projectID int64) (queries.Organization, error) {
-	return c.Queries.GetOrganizationByGitLabProjectID(ctx, projectID)
+	return c.reader.GetOrganizationByGitLabProjectID(ctx, projectID)
 }
 
 // GetDefaultOrganization returns the first organization.
 func (c *Database) GetDefaultOrganization(ctx context.Context) (queries.Organization, error) {
-	return c.Queries.GetDefaultOrganization(ctx)
+	return c.reader.GetDefaultOrganization(ctx)
+}
+
+// GetOrganizationByJoinCode fetches organization by join code.
+func (c *Database) GetOrganizationByJoinCode(ctx context.Context, joinCode sql.NullString) (queries.Organization, error) {
+	return c.reader.GetOrganizationByJoinCode(ctx, joinCode)
+}
+
+// ListPendingOrganizationJoinRequests returns org join requests awaiting review.
+func (c *Database) ListPendingOrganizationJoinRequests(ctx context.Context, organizationID int64) ([]queries.ListPendingOrganizationJoinRequestsRow, error) {
+	return c.reader.ListPendingOrganizationJoinRequests(ctx, organizationID)
 }
 
 // CreateOrganization inserts a new organization.
@@ -152,47 +162,68 @@ type ListDeploymentHistoryByServiceFromEventsParams = queries.ListDeploymentHist
 
 // ListServiceInstancesFromEvents returns current service states from event stream.
 func (c *Database) ListServiceInstancesFromEvents(ctx context.Context, organizationID int64) ([]queries.ListServiceInstancesFromEventsRow, error) {
-	return c.Queries.ListServiceInstancesFromEvents(ctx, organizationID)
+	return c.reader.ListServiceInstancesFromEvents(ctx, organizationID)
 }
 
 // ListServiceInstancesByEnvFromEvents returns current service states by environment from event stream.
 func (c *Database) ListServiceInstancesByEnvFromEvents(ctx context.Context, params queries.ListServiceInstancesByEnvFromEventsParams) ([]queries.ListServiceInstancesByEnvFromEventsRow, error) {
-	return c.Queries.ListServiceInstancesByEnvFromEvents(ctx, params)
+	return c.reader.ListServiceInstancesByEnvFromEvents(ctx, params)
 }
 
 // ListDeploymentsFromEvents returns deployment rows from event stream.
 func (c *Database) ListDeploymentsFromEvents(ctx context.Context, params queries.ListDeploymentsFromEventsParams) ([]queries.ListDeploymentsFromEventsRow, error) {
-	return c.Queries.ListDeploymentsFromEvents(ctx, params)
+	return c.reader.ListDeploymentsFromEvents(ctx, params)
+}
+
+// ListDeploymentEnvironmentCounts counts deployments per environment since from_ms,
+// and separately since recent_from_ms.
+func (c *Database) ListDeploymentEnvironmentCounts(ctx context.Context, params queries.ListDeploymentEnvironmentCountsParams) ([]queries.ListDeploymentEnvironmentCountsRow, error) {
+	return c.reader.ListDeploymentEnvironmentCounts(ctx, params)
+}
+
+// ListDeploymentServices returns the services with at least one deployment.
+func (c *Database) ListDeploymentServices(ctx context.Context, organizationID int64) ([]string, error) {
+	return c.reader.ListDeploymentServices(ctx, organizationID)
 }
 
 // GetOrganizationRenderVersion returns coarse UI render version for one organization.
 func (c *Database) GetOrganizationRenderVersion(ctx context.Context, orgID int64) (interface{}, error) {
-	return c.Queries.GetOrganizationRenderVersion(ctx, orgID)
+	return c.reader.GetOrganizationRenderVersion(ctx, orgID)
+}
+
+// GetOrganizationLatestEventSeq returns the highest event_store sequence for one organization.
+func (c *Database) GetOrganizationLatestEventSeq(ctx context.Context, organizationID int64) (int64, error) {
+	return c.reader.GetOrganizationLatestEventSeq(ctx, organizationID)
+}
+
+// ListServiceChangesAfterSeq returns services with service events newer than a sequence.
+func (c *Database) ListServiceChangesAfterSeq(ctx context.Context, params queries.ListServiceChangesAfterSeqParams) ([]queries.ListServiceChangesAfterSeqRow, error) {
+	return c.reader.ListServiceChangesAfterSeq(ctx, params)
 }
 
 // GetServiceLatestFromEvents returns latest event for a service.
 func (c *Database) GetServiceLatestFromEvents(ctx context.Context, params queries.GetServiceLatestFromEventsParams) (queries.GetServiceLatestFromEventsRow, error) {
-	return c.Queries.GetServiceLatestFromEvents(ctx, params)
+	return c.reader.GetServiceLatestFromEvents(ctx, params)
 }
 
 // ListServiceEnvironmentsFromEvents returns latest service state per environment from event stream.
 func (c *Database) ListServiceEnvironmentsFromEvents(ctx context.Context, params queries.ListServiceEnvironmentsFromEventsParams) ([]queries.ListServiceEnvironmentsFromEventsRow, error) {
-	return c.Queries.ListServiceEnvironmentsFromEvents(ctx, params)
+	return c.reader.ListServiceEnvironmentsFromEvents(ctx, params)
 }
 
 // ListDeploymentHistoryByServiceFromEvents returns service deployment history from event stream.
 func (c *Database) ListDeploymentHistoryByServiceFromEvents(ctx context.Context, params queries.ListDeploymentHistoryByServiceFromEventsParams) ([]queries.ListDeploymentHistoryByServiceFromEventsRow, error) {
-	return c.Queries.ListDeploymentHistoryByServiceFromEvents(ctx, params)
+	return c.reader.ListDeploymentHistoryByServiceFromEvents(ctx, params)
 }
 
 // ListServiceDependencies returns dependencies for one service.
 func (c *Database) ListServiceDependencies(ctx context.Context, params queries.ListServiceDependenciesParams) ([]string, error) {
-	return c.Queries.ListServiceDependencies(ctx, params)
+	return c.reader.ListServiceDependencies(ctx, params)
 }
 
 // ListServiceDependants returns dependants for one service.
 func (c *Database) ListServiceDependants(ctx context.Context, params queries.ListServiceDependantsParams) ([]string, error) {
-	return c.Queries.ListServiceDependants(ctx, params)
+	return c.reader.ListServiceDependants(ctx, params)
 }
 
 // UpsertServiceDependency creates one dependency edge.
@@ -207,42 +238,52 @@ func (c *Database) DeleteServiceDependency(ctx context.Context, params queries.D
 
 // GetServiceCurrentState returns current projected status metrics for one service.
 func (c *Database) GetServiceCurrentState(ctx context.Context, params queries.GetServiceCurrentStateParams) (queries.GetServiceCurrentStateRow, error) {
-	return c.Queries.GetServiceCurrentState(ctx, params)
+	return c.reader.GetServiceCurrentState(ctx, params)
 }
 
 // GetServiceDeliveryStats30d returns 30d delivery counters for one service.
 func (c *Database) GetServiceDeliveryStats30d(ctx context.Context, params queries.GetServiceDeliveryStats30dParams) (queries.GetServiceDeliveryStats30dRow, error) {
-	return c.Queries.GetServiceDeliveryStats30d(ctx, params)
+	return c.reader.GetServiceDeliveryStats30d(ctx, params)
 }
 
 // ListServiceChangeLinksRecent returns recent change link rows for one service.
 func (c *Database) ListServiceChangeLinksRecent(ctx context.Context, params queries.ListServiceChangeLinksRecentParams) ([]queries.ListServiceChangeLinksRecentRow, error) {
-	return c.Queries.ListServiceChangeLinksRecent(ctx, params)
+	return c.reader.ListServiceChangeLinksRecent(ctx, params)
 }
 
 // ListServiceLeadTimeSamplesFromEvents returns lead-time samples derived from change->deploy ordering.
 func (c *Database) ListServiceLeadTimeSamplesFromEvents(ctx context.Context, params queries.ListServiceLeadTimeSamplesFromEventsParams) ([]queries.ListServiceLeadTimeSamplesFromEventsRow, error) {
-	return c.Queries.ListServiceLeadTimeSamplesFromEvents(ctx, params)
+	return c.reader.ListServiceLeadTimeSamplesFromEvents(ctx, params)
 }
 
 // ListOrganizationRequiredFields returns required fields for an org.
 func (c *Database) ListOrganizationRequiredFields(ctx context.Context, organizationID int64) ([]queries.ListOrganizationRequiredFieldsRow, error) {
-	return c.Queries.ListOrganizationRequiredFields(ctx, organizationID)
+	return c.reader.ListOrganizationRequiredFields(ctx, organizationID)
 }
 
 // ListOrganizationEnvironmentPriorities returns environment ordering for an org.
 func (c *Database) ListOrganizationEnvironmentPriorities(ctx context.Context, organizationID int64) ([]queries.ListOrganizationEnvironmentPrioritiesRow, error) {
-	return c.Queries.ListOrganizationEnvironmentPriorities(ctx, organizationID)
+	return c.reader.ListOrganizationEnvironmentPriorities(ctx, organizationID)
 }
 
 // ListOrganizationFeatures returns feature flags for an org.
 func (c *Database) ListOrganizationFeatures(ctx context.Context, organizationID int64) ([]queries.ListOrganizationFeaturesRow, error) {
-	return c.Queries.ListOrganizationFeatures(ctx, organizationID)
+	return c.reader.ListOrganizationFeatures(ctx, organizationID)
 }
 
 // ListOrganizationPreferences returns preferences for an org.
 func (c *Database) ListOrganizationPreferences(ctx context.Context, organizationID int64) ([]queries.ListOrganizationPreferencesRow, error) {
-	return c.Queries.ListOrganizationPreferences(ctx, organizationID)
+	return c.reader.ListOrganizationPreferences(ctx, organizationID)
+}
+
+// ListOrganizationEventPolicies returns event subject policies for an org.
+func (c *Database) ListOrganizationEventPolicies(ctx context.Context, organizationID int64) ([]queries.ListOrganizationEventPoliciesRow, error) {
+	return c.reader.ListOrganizationEventPolicies(ctx, organizationID)
+}
+
+// GetOrganizationPreference returns one preference value for an org.
+func (c *Database) GetOrganizationPreference(ctx context.Context, params queries.GetOrganizationPreferenceParams) (string, error) {
+	return c.reader.GetOrganizationPreference(ctx, params)
 }
 
 // UpsertOrganizationFeature upserts one feature flag for an org.
@@ -279,40 +320,24 @@ func (c *Database) CreateOrganizationEnvironmentPriority(ctx context.Context, pa
 
 // ListServiceMetadataByService returns metadata values for a service.
 func (c *Database) ListServiceMetadataByService(ctx context.Context, params queries.ListServiceMetadataByServiceParams) ([]queries.ListServiceMetadataByServiceRow, error) {
-	return c.Queries.ListServiceMetadataByService(ctx, params)
+	return c.reader.ListServiceMetadataByService(ctx, params)
 }
 
 // ListServiceMetadataByOrganization returns metadata values for all services in an org.
 func (c *Database) ListServiceMetadataByOrganization(ctx context.Context, organizationID int64) ([]queries.ListServiceMetadataByOrganizationRow, error) {
-	return c.Queries.ListServiceMetadataByOrganization(ctx, organizationID)
+	return c.reader.ListServiceMetadataByOrganization(ctx, organizationID)
 }
 
 // ListDistinctServiceEnvironmentsFromEvents returns discovered environments from service events.
 func (c *Database) ListDistinctServiceEnvironmentsFromEvents(ctx context.Context, organizationID int64) ([]string, error) {
-	rows, err := c.Queries.ListDistinctServiceEnvironmentsFromEvents(ctx, organizationID)
+	rows, err := c.reader.ListDistinctServiceEnvironmentsFromEvents(ctx, organizationID)
 	if err != nil {
 		return nil, err
 	}
 	result := make([]string, 0, len(rows))
 	for _, row := range rows {
-		switch value := row.(type) {
-		case nil:
-			continue
-		case string:
-			value = strings.TrimSpace(value)
-			if value != "" {
-				result = append(result, value)
-			}
-		case []byte:
-			text := strings.TrimSpace(string(value))
-			if text != "" {
-				result = append(result, text)
-			}
-		default:
-			text := strings.TrimSpace(fmt.Sprint(value))
-			if text != "" {
-				result = append(result, text)
-			}
+		if value := strings.TrimSpace(row); value != "" {
+			result = append(result, value)
 		}
 	}
 	return result, nil
@@ -345,69 +370,187 @@ func (c *Database) UpdateOrganizationSecrets(ctx context.Context, authToken, web
 // AppendEventStore stores a CDEvent in the append-only event store.
 func (c *Database) AppendEventStore(ctx context.Context, params queries.AppendEventStoreParams) error {
 	return c.WithTx(ctx, func(q *queries.Queries) error {
-		_, err := appendEventWithProjections(ctx, q, params)
-		return err
+		projectors := newLiveProjectors(c.projectors)
+		if _, _, err := appendEventWithProjections(ctx, q, params, projectors, c.identityRules); err != nil {
+			return err
+		}
+		return projectors.commit(ctx, q)
 	})
 }
 
-// AppendEventStoreBatch stores multiple CDEvents in one transaction.
-func (c *Database) AppendEventStoreBatch(ctx context.Context, params []queries.AppendEventStoreParams) error {
-	if len(params) == 0 {
+// AppendIngestDeadLetter stores one rejected webhook delivery and prunes the
+// organization's dead letters received before receivedBefore or beyond keepCount.
+func (c *Database) AppendIngestDeadLetter(ctx context.Context, params queries.InsertIngestDeadLetterParams, receivedBefore, keepCount int64) error {
+	return c.WithTx(ctx, func(q *queries.Queries) error {
+		if err := q.InsertIngestDeadLetter(ctx, params); err != nil {
+			return err
+		}
+		return q.PruneIngestDeadLetters(ctx, queries.PruneIngestDeadLettersParams{
+			OrganizationID: params.OrganizationID,
+			ReceivedBefore: receivedBefore,
+			KeepCount:      keepCount,
+		})
+	})
+}
+
+// RememberIngestSignature records a webhook signature and reports whether it
+// was unseen. Signatures past their expiry are pruned in the same transaction.
+func (c *Database) RememberIngestSignature(ctx context.Context, params queries.InsertIngestSeenSignatureParams) (bool, error) {
+	inserted := false
+	err := c.WithTx(ctx, func(q *queries.Queries) error {
+		if err := q.PruneIngestSeenSignatures(ctx, params.SeenAt); err != nil {
+			return err
+		}
+		rows, err := q.InsertIngestSeenSignature(ctx, params)
+		if err != nil {
+			return err
+		}
+		inserted = rows > 0
 		return nil
+	})
+	return inserted, err
+}
+
+// ListIngestDeadLetters returns the newest rejected deliveries for an org.
+func (c *Database) ListIngestDeadLetters(ctx context.Context, params queries.ListIngestDeadLettersParams) ([]queries.ListIngestDeadLettersRow, error) {
+	return c.reader.ListIngestDeadLetters(ctx, params)
+}
+
+// GetIngestDeadLetter returns one rejected delivery for an org.
+func (c *Database) GetIngestDeadLetter(ctx context.Context, params queries.GetIngestDeadLetterParams) (queries.GetIngestDeadLetterRow, error) {
+	return c.reader.GetIngestDeadLetter(ctx, params)
+}
+
+// MarkIngestDeadLetterReplayed records the outcome of a dead-letter replay.
+func (c *Database) MarkIngestDeadLetterReplayed(ctx context.Context, params queries.MarkIngestDeadLetterReplayedParams) error {
+	return c.Queries.MarkIngestDeadLetterReplayed(ctx, params)
+}
+
+// DeleteIngestDeadLetter removes one rejected delivery for an org.
+func (c *Database) DeleteIngestDeadLetter(ctx context.Context, params queries.DeleteIngestDeadLetterParams) error {
+	return c.Queries.DeleteIngestDeadLetter(ctx, params)
+}
+
+// GetOrganizationByIngestCredentialToken resolves an org from one of its
+// active ingest credentials.
+func (c *Database) GetOrganizationByIngestCredentialToken(ctx context.Context, authToken string, now int64) (queries.Organization, error) {
+	return c.reader.GetOrganizationByIngestCredentialToken(ctx, queries.GetOrganizationByIngestCredentialTokenParams{
+		AuthToken: authToken,
+		Now:       sql.NullInt64{Int64: now, Valid: true},
+	})
+}
+
+// ListOrganizationIngestCredentials returns all ingest credentials for an org, newest first.
+func (c *Database) ListOrganizationIngestCredentials(ctx context.Context, organizationID int64) ([]queries.OrganizationIngestCredential, error) {
+	return c.reader.ListOrganizationIngestCredentials(ctx, organizationID)
+}
+
+// CreateOrganizationIngestCredential adds one ingest credential for an org.
+func (c *Database) CreateOrganizationIngestCredential(ctx context.Context, params queries.InsertOrganizationIngestCredentialParams) (queries.OrganizationIngestCredential, error) {
+	return c.Queries.InsertOrganizationIngestCredential(ctx, params)
+}
+
+// ExpireOrganizationIngestCredential sets the expiry of one unrevoked credential.
+func (c *Database) ExpireOrganizationIngestCredential(ctx context.Context, params queries.ExpireOrganizationIngestCredentialParams) (int64, error) {
+	return c.Queries.ExpireOrganizationIngestCredential(ctx, params)
+}
+
+// RevokeOrganizationIngestCredential revokes one credential immediately.
+func (c *Database) RevokeOrganizationIngestCredential(ctx context.Context, params queries.RevokeOrganizationIngestCredentialParams) (int64, error) {
+	return c.Queries.RevokeOrganizationIngestCredential(ctx, params)
+}
+
+// TouchOrganizationIngestCredential records credential use, at most once per throttle window.
+func (c *Database) TouchOrganizationIngestCredential(ctx context.Context, organizationID, credentialID, usedAt, throttle int64) error {
+	return c.Queries.TouchOrganizationIngestCredential(ctx, queries.TouchOrganizationIngestCredentialParams{
+		UsedAt:         sql.NullInt64{Int64: usedAt, Valid: true},
+		OrganizationID: organizationID,
+		ID:             credentialID,
+		StaleBefore:    sql.NullInt64{Int64: usedAt - throttle, Valid: true},
+	})
+}
+
+// AppendedEvent describes one event newly committed to event_store.
+type AppendedEvent struct {
+	OrganizationID int64
+	EventID        string
+	Seq            int64
+	SubjectType    string
+	ServiceName    string
+}
+
+// AppendEventStoreBatch stores multiple CDEvents in one transaction and
+// returns the events that were not already present. Accepted and duplicate
+// counts and per-source activity are rolled up in the same transaction.
+func (c *Database) AppendEventStoreBatch(ctx context.Context, params []queries.AppendEventStoreParams) ([]AppendedEvent, error) {
+	if len(params) == 0 {
+		return nil, nil
 	}
-	return c.WithTx(ctx, func(q *queries.Queries) error {
+	appended := make([]AppendedEvent, 0, len(params))
+	activity := newIngestActivity(time.Now().UTC())
+	err := c.WithTx(ctx, func(q *queries.Queries) error {
+		projectors := newLiveProjectors(c.projectors)
 		for _, item := range params {
-			if _, err := appendEventWithProjections(ctx, q, item); err != nil {
+			event, inserted, err := appendEventWithProjections(ctx, q, item, projectors, c.identityRules)
+			if err != nil {
 				return err
 			}
+			activity.add(item, inserted)
+			if inserted {
+				appended = append(appended, event)
+			}
 		}
-		return nil
+		if err := projectors.commit(ctx, q); err != nil {
+			return err
+		}
+		return activity.write(ctx, q)
 	})
+	if err != nil {
+		return nil, err
+	}
+	return appended, nil
 }
 
-func appendEventWithProjections(ctx context.Context, q *queries.Queries, params queries.AppendEventStoreParams) (bool, error) {
+func appendEventWithProjections(ctx context.Context, q *queries.Queries, params queries.AppendEventStoreParams, projectors *liveProjectors, identities *serviceIdentityRuleCache) (AppendedEvent, bool, error) {
 	seq, err := q.AppendEventStore(ctx, params)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return false, nil
+			return AppendedEvent{}, false, nil
 		}
-		return false, err
+		return AppendedEvent{}, false, err
 	}
-	if strings.TrimSpace(strings.ToLower(params.SubjectType)) != "service" {
-		return true, nil
+	if err := applyEnvironmentEvent(ctx, q, params); err != nil {
+		return AppendedEvent{}, false, err
 	}
-
-	projectionParams := queries.UpsertServiceEnvStateFromEventSeqParams{
-		OrganizationID: params.OrganizationID,
-		Seq:            seq,
+	if err := materializeServiceIdentities(ctx, q, identities, params); err != nil {
+		return AppendedEvent{}, false, err
 	}
-	if err := q.UpsertServiceEnvStateFromEventSeq(ctx, projectionParams); err != nil {
-		return false, err
+	if err := projectors.apply(ctx, q, projectedEventFromParams(params, seq)); err != nil {
+		return AppendedEvent{}, false, err
 	}
-	if err := q.UpsertServiceDeliveryStatsDailyFromEventSeq(ctx, queries.UpsertServiceDeliveryStatsDailyFromEventSeqParams{
+	appended := AppendedEvent{
 		OrganizationID: params.OrganizationID,
+		EventID:        params.EventID,
 		Seq:            seq,
-	}); err != nil {
-		return false, err
+		SubjectType:    params.SubjectType,
 	}
-	if err := q.UpsertServiceChangeLinkFromEventSeq(ctx, queries.UpsertServiceChangeLinkFromEventSeqParams{
-		OrganizationID: params.OrganizationID,
-		Seq:            seq,
-	}); err != nil {
-		return false, err
+	if strings.EqualFold(strings.TrimSpace(params.SubjectType), "service") {
+		if appended.ServiceName, err = resolveServiceName(ctx, q, params.OrganizationID, serviceNameFromSubjectID(params.SubjectID)); err != nil {
+			return AppendedEvent{}, false, err
+		}
 	}
+	return appended, true, nil
+}
 
-	serviceName := serviceNameFromSubjectID(params.SubjectID)
-	if serviceName == "" {
-		return true, nil
-	}
-	if err := q.UpsertServiceCurrentStateByService(ctx, queries.UpsertServiceCurrentStateByServiceParams{
+func projectedEventFromParams(params queries.AppendEventStoreParams, seq int64) ProjectedEvent {
+	return ProjectedEvent{
+		Seq:            seq,
 		OrganizationID: params.OrganizationID,
-		ServiceName:    serviceName,
-	}); err != nil {
-		return false, err
+		EventType:      params.EventType,
+		SubjectType:    params.SubjectType,
+		SubjectID:      params.SubjectID,
+		EventTsMs:      params.EventTsMs,
 	}
-	return true, nil
 }
 
 func serviceNameFromSubjectID(subjectID string) string {
@@ -421,186 +564,6 @@ func serviceNameFromSubjectID(subjectID string) string {
 	return subjectID
 }
 
-// ProjectionRebuildStats reports row counts after rebuild.
-type ProjectionRebuildStats struct {
-	CurrentStateRows int64
-	EnvStateRows     int64
-	DailyStatsRows   int64
-	ChangeLinkRows   int64
-}
-
-// RebuildServiceProjections rebuilds projection tables from event_store.
-func (c *Database) RebuildServiceProjections(ctx context.Context, organizationID int64) (ProjectionRebuildStats, error) {
-	stats := ProjectionRebuildStats{}
-	if err := c.execProjectionRebuild(ctx, organizationID); err != nil {
-		return ProjectionRebuildStats{}, err
-	}
-
-	var err error
-	stats.CurrentStateRows, err = c.countProjectionRows(ctx, "service_current_state", organizationID)
-	if err != nil {
-		return ProjectionRebuildStats{}, err
-	}
-	stats.EnvStateRows, err = c.countProjectionRows(ctx, "service_env_state", organizationID)
-	if err != nil {
-		return ProjectionRebuildStats{}, err
-	}
-	stats.DailyStatsRows, err = c.countProjectionRows(ctx, "service_delivery_stats_daily", organizationID)
-	if err != nil {
-		return ProjectionRebuildStats{}, err
-	}
-	stats.ChangeLinkRows, err = c.countProjectionRows(ctx, "service_change_links", organizationID)
-	if err != nil {
-		return ProjectionRebuildStats{}, err
-	}
-
-	return stats, nil
-}
-
-func (c *Database) execProjectionRebuild(ctx context.Context, _ int64) error {
-	statements := []string{
-		"DELETE FROM service_current_state",
-		"DELETE FROM service_env_state",
-		"DELETE FROM service_delivery_stats_daily",
-		"DELETE FROM service_change_links",
-		`INSERT INTO service_env_state (
-			organization_id, service_name, environment,
-			latest_event_seq, latest_event_type, latest_event_ts_ms,
-			latest_status, latest_artifact_id
-		)
-		WITH ranked AS (
-			SELECT
-				es.organization_id,
-				CASE WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1) ELSE es.subject_id END AS service_name,
-				COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') AS environment,
-				es.seq,
-				es.event_type,
-				es.event_ts_ms,
-				COALESCE(json_extract(es.raw_event_json, '$.subject.content.artifactId'), '') AS artifact_id,
-				CASE
-					WHEN es.event_type LIKE 'dev.cdevents.service.deployed.%' THEN 'synced'
-					WHEN es.event_type LIKE 'dev.cdevents.service.upgraded.%' THEN 'synced'
-					WHEN es.event_type LIKE 'dev.cdevents.service.published.%' THEN 'synced'
-					WHEN es.event_type LIKE 'dev.cdevents.service.rolledback.%' THEN 'warning'
-					WHEN es.event_type LIKE 'dev.cdevents.service.removed.%' THEN 'out-of-sync'
-					ELSE 'unknown'
-				END AS status,
-				row_number() OVER (
-					PARTITION BY es.organization_id,
-					CASE WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1) ELSE es.subject_id END,
-					COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown')
-					ORDER BY es.event_ts_ms DESC, es.seq DESC
-				) AS rn
-			FROM event_store es
-			WHERE es.subject_type = 'service'
-		)
-		SELECT
-			organization_id,
-			service_name,
-			environment,
-			seq,
-			event_type,
-			event_ts_ms,
-			status,
-			artifact_id
-		FROM ranked
-		WHERE rn = 1`,
-		`INSERT INTO service_current_state (
-			organization_id, service_name,
-			latest_event_seq, latest_event_type, latest_event_ts_ms,
-			latest_status, latest_artifact_id, latest_environment,
-			drift_count, failed_streak
-		)
-		WITH latest AS (
-			SELECT
-				ses.organization_id,
-				ses.service_name,
-				ses.latest_event_seq,
-				ses.latest_event_type,
-				ses.latest_event_ts_ms,
-				ses.latest_status,
-				ses.latest_artifact_id,
-				ses.environment,
-				row_number() OVER (PARTITION BY ses.organization_id, ses.service_name ORDER BY ses.latest_event_ts_ms DESC, ses.latest_event_seq DESC) AS rn
-			FROM service_env_state ses
-		), drift AS (
-			SELECT
-				organization_id,
-				service_name,
-				COUNT(DISTINCT NULLIF(latest_artifact_id, '')) AS drift_count,
-				SUM(CASE WHEN latest_status IN ('warning', 'out-of-sync') THEN 1 ELSE 0 END) AS failed_streak
-			FROM service_env_state
-			GROUP BY organization_id, service_name
-		)
-		SELECT
-			l.organization_id,
-			l.service_name,
-			l.latest_event_seq,
-			l.latest_event_type,
-			l.latest_event_ts_ms,
-			l.latest_status,
-			l.latest_artifact_id,
-			l.environment,
-			COALESCE(d.drift_count, 0),
-			COALESCE(d.failed_streak, 0)
-		FROM latest l
-		LEFT JOIN drift d ON d.organization_id = l.organization_id AND d.service_name = l.service_name
-		WHERE l.rn = 1`,
-		`INSERT INTO service_delivery_stats_daily (
-			organization_id, service_name, day_utc,
-			deploy_success_count, deploy_failure_count, rollback_count
-		)
-		SELECT
-			es.organization_id,
-			CASE WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1) ELSE es.subject_id END AS service_name,
-			date(datetime(es.event_ts_ms / 1000, 'unixepoch')) AS day_utc,
-			SUM(CASE WHEN es.event_type LIKE 'dev.cdevents.service.deployed.%' OR es.event_type LIKE 'dev.cdevents.service.upgraded.%' OR es.event_type LIKE 'dev.cdevents.service.published.%' THEN 1 ELSE 0 END),
-			SUM(CASE WHEN es.event_type LIKE 'dev.cdevents.service.removed.%' THEN 1 ELSE 0 END),
-			SUM(CASE WHEN es.event_type LIKE 'dev.cdevents.service.rolledback.%' THEN 1 ELSE 0 END)
-		FROM event_store es
-		WHERE es.subject_type = 'service'
-		GROUP BY es.organization_id, service_name, day_utc`,
-		`INSERT INTO service_change_links (
-			organization_id, service_name, event_seq, event_ts_ms,
-			chain_id, environment, artifact_id, pipeline_run_id,
-			run_url, actor_name
-		)
-		SELECT
-			es.organization_id,
-			CASE WHEN instr(es.subject_id, '/') > 0 THEN substr(es.subject_id, instr(es.subject_id, '/') + 1) ELSE es.subject_id END AS service_name,
-			es.seq,
-			es.event_ts_ms,
-			es.chain_id,
-			COALESCE(NULLIF(json_extract(es.raw_event_json, '$.subject.content.environment.id'), ''), 'unknown') AS environment,
-			COALESCE(json_extract(es.raw_event_json, '$.subject.content.artifactId'), '') AS artifact_id,
-			COALESCE(json_extract(es.raw_event_json, '$.subject.content.pipeline.runId'), '') AS pipeline_run_id,
-			COALESCE(json_extract(es.raw_event_json, '$.subject.content.pipeline.url'), '') AS run_url,
-			COALESCE(json_extract(es.raw_event_json, '$.subject.content.actor.name'), '') AS actor_name
-		FROM event_store es
-		WHERE es.subject_type = 'service'`,
-	}
-
-	for _, statement := range statements {
-		if _, err := c.db.ExecContext(ctx, statement); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func (c *Database) countProjectionRows(ctx context.Context, table string, organizationID int64) (int64, error) {
-	query := "SELECT COUNT(*) FROM " + table
-	args := []interface{}{}
-	if organizationID > 0 {
-		query += " WHERE organization_id = ?"
-		args = append(args, organizationID)
-	}
-	row := c.db.QueryRowContext(ctx, query, args...)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
-}
-
 // WithTx runs a function within a transaction.
 func (c *Database) WithTx(ctx context.Context, fn func(*queries.Queries) error) error {
 	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{})
@@ -618,45 +581,76 @@ func (c *Database) WithTx(ctx context.Context, fn func(*queries.Queries) error)
 }
 
 func (c *Database) GetPipelineStats30d(ctx context.Context, arg queries.GetPipelineStats30dParams) (queries.GetPipelineStats30dRow, error) {
-	return c.Queries.GetPipelineStats30d(ctx, arg)
+	return c.reader.GetPipelineStats30d(ctx, arg)
 }
 
 func (c *Database) GetDeploymentDurationStats(ctx context.Context, arg queries.GetDeploymentDurationStatsParams) (queries.GetDeploymentDurationStatsRow, error) {
-	return c.Queries.GetDeploymentDurationStats(ctx, arg)
+	return c.reader.GetDeploymentDurationStats(ctx, arg)
 }
 
 func (c *Database) GetEnvironmentDriftCount(ctx context.Context, arg queries.GetEnvironmentDriftCountParams) (int64, error) {
-	return c.Queries.GetEnvironmentDriftCount(ctx, arg)
+	return c.reader.GetEnvironmentDriftCount(ctx, arg)
 }
 
 func (c *Database) ListEnvironmentDrifts(ctx context.Context, arg queries.ListEnvironmentDriftsParams) ([]queries.ListEnvironmentDriftsRow, error) {
-	return c.Queries.ListEnvironmentDrifts(ctx, arg)
+	return c.reader.ListEnvironmentDrifts(ctx, arg)
 }
 
 func (c *Database) GetRedeploymentRate30d(ctx context.Context, arg queries.GetRedeploymentRate30dParams) (queries.GetRedeploymentRate30dRow, error) {
-	return c.Queries.GetRedeploymentRate30d(ctx, arg)
+	return c.reader.GetRedeploymentRate30d(ctx, arg)
 }
 
 func (c *Database) GetThroughputStats(ctx context.Context, arg queries.GetThroughputStatsParams) (queries.GetThroughputStatsRow, error) {
-	return c.Queries.GetThroughputStats(ctx, arg)
+	return c.reader.GetThroughputStats(ctx, arg)
 }
 
 func (c *Database) ListWeeklyThroughput(ctx context.Context, arg queries.ListWeeklyThroughputParams) ([]queries.ListWeeklyThroughputRow, error) {
-	return c.Queries.ListWeeklyThroughput(ctx, arg)
+	return c.reader.ListWeeklyThroughput(ctx, arg)
 }
 
 func (c *Database) GetArtifactAgeByEnvironment(ctx context.Context, arg queries.GetArtifactAgeByEnvironmentParams) ([]queries.GetArtifactAgeByEnvironmentRow, error) {
-	return c.Queries.GetArtifactAgeByEnvironment(ctx, arg)
+	return c.reader.GetArtifactAgeByEnvironment(ctx, arg)
 }
 
 func (c *Database) GetMTTR(ctx context.Context, arg queries.GetMTTRParams) (queries.GetMTTRRow, error) {
-	return c.Queries.GetMTTR(ctx, arg)
+	return c.reader.GetMTTR(ctx, arg)
 }
 
 func (c *Database) ListIncidentLinks(ctx context.Context, arg queries.ListIncidentLinksParams) ([]queries.ListIncidentLinksRow, error) {
-	return c.Queries.ListIncidentLinks(ctx, arg)
+	return c.reader.ListIncidentLinks(ctx, arg)
 }
 
 func (c *Database) GetComprehensiveDeliveryMetrics(ctx context.Context, arg queries.GetComprehensiveDeliveryMetricsParams) (queries.GetComprehensiveDeliveryMetricsRow, error) {
-	return c.Queries.GetComprehensiveDeliveryMetrics(ctx, arg)
+	return c.reader.GetComprehensiveDeliveryMetrics(ctx, arg)
+}
+
+// ListEventStoreEvents pages through an organization's stored events.
+func (c *Database) ListEventStoreEvents(ctx context.Context, arg queries.ListEventStoreEventsParams) ([]queries.ListEventStoreEventsRow, error) {
+	return c.reader.ListEventStoreEvents(ctx, arg)
+}
+
+// GetEventStoreEvent fetches one stored event by seq, with RawEventJson
+// holding the event body even when it is stored compressed.
+func (c *Database) GetEventStoreEvent(ctx context.Context, arg queries.GetEventStoreEventParams) (queries.EventStore, error) {
+	row, err := c.reader.GetEventStoreEvent(ctx, arg)
+	if err != nil {
+		return row, err
+	}
+	row.RawEventJson, err = eventPayload(row.RawEventJson, row.RawEventCompressed)
+	return row, err
+}
+
+// ListEventContributions returns the projection rows one event produced.
+func (c *Database) ListEventContributions(ctx context.Context, arg queries.ListEventContributionsParams) ([]queries.ListEventContributionsRow, error) {
+	return c.reader.ListEventContributions(ctx, arg)
+}
+
+// ListChainEvents returns the events sharing one chain id.
+func (c *Database) ListChainEvents(ctx context.Context, arg queries.ListChainEventsParams) ([]queries.ListChainEventsRow, error) {
+	return c.reader.ListChainEvents(ctx, arg)
+}
+
+// ListServiceEnvironmentStateAt returns per-environment service state at a moment.
+func (c *Database) ListServiceEnvironmentStateAt(ctx context.Context, arg queries.ListServiceEnvironmentStateAtParams) ([]queries.ListServiceEnvironmentStateAtRow, error) {
+	return c.reader.ListServiceEnvironmentStateAt(ctx, arg)
 }
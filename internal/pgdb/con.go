@@ -0,0 +1,75 @@
+package pgdb
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+
+	// PostgreSQL driver.
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+
+	"github.com/fr0stylo/ddash/internal/pgdb/queries"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+const (
+	driver  = "pgx"
+	dialect = "postgres"
+)
+
+// Database wraps sqlc queries with the shared PostgreSQL connection pool.
+type Database struct {
+	*queries.Queries
+	db *sql.DB
+}
+
+// New opens the PostgreSQL database at the provided connection URL and
+// applies pending migrations.
+func New(url string) (*Database, error) {
+	if url == "" {
+		return nil, errors.New("postgres connection url is required")
+	}
+	db, err := sql.Open(driver, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	goose.SetBaseFS(migrationsFS)
+
+	if err := goose.SetDialect(dialect); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to set goose dialect: %w", err)
+	}
+
+	if err := goose.Up(db, "migrations"); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	return &Database{db: db, Queries: queries.New(db)}, nil
+}
+
+// Close closes the underlying connection pool.
+func (c *Database) Close() error {
+	return c.db.Close()
+}
+
+// WithTx runs a function within a transaction.
+func (c *Database) WithTx(ctx context.Context, fn func(*queries.Queries) error) error {
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	if err := fn(queries.New(tx)); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return rollbackErr
+		}
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,307 @@
+package pgdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fr0stylo/ddash/internal/pgdb/queries"
+)
+
+const (
+	// EnvironmentTierProduction marks environments that serve production traffic.
+	EnvironmentTierProduction = "production"
+	// EnvironmentTierNonProduction marks every other environment.
+	EnvironmentTierNonProduction = "non-production"
+
+	// EnvironmentSourceEvent marks environments registered by an
+	// environment.created or environment.modified event.
+	EnvironmentSourceEvent = "event"
+	// EnvironmentSourceSettings marks environments registered in settings.
+	EnvironmentSourceSettings = "settings"
+)
+
+var (
+	// ErrInvalidEnvironment is returned for registry entries that cannot be stored.
+	ErrInvalidEnvironment = errors.New("invalid environment")
+	// ErrProtectedEnvironment is returned when deleting a protected environment.
+	ErrProtectedEnvironment = errors.New("environment is protected")
+)
+
+var environmentColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// EnvironmentInput is one environment registry entry managed in settings.
+type EnvironmentInput struct {
+	OrganizationID int64
+	Name           string
+	Tier           string
+	Color          string
+	Protected      bool
+	URL            string
+	// Aliases are the other names events report the environment under.
+	Aliases []string
+}
+
+// SaveOrganizationEnvironment registers an environment or updates the one
+// with the same name, replaces its aliases and reorders the environment
+// priorities. Like every registry write it holds the organization's event
+// store lock, so environment events appended meanwhile wait for it.
+// Projections keep the old names until they are rebuilt.
+func (c *Database) SaveOrganizationEnvironment(ctx context.Context, input EnvironmentInput) (queries.OrganizationEnvironment, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Color = strings.TrimSpace(input.Color)
+	input.URL = strings.TrimSpace(input.URL)
+	if input.Name == "" {
+		return queries.OrganizationEnvironment{}, fmt.Errorf("%w: name is required", ErrInvalidEnvironment)
+	}
+	if input.Tier != EnvironmentTierProduction && input.Tier != EnvironmentTierNonProduction {
+		return queries.OrganizationEnvironment{}, fmt.Errorf("%w: unknown tier %q", ErrInvalidEnvironment, input.Tier)
+	}
+	if input.Color != "" && !environmentColorPattern.MatchString(input.Color) {
+		return queries.OrganizationEnvironment{}, fmt.Errorf("%w: color must look like #1f2937", ErrInvalidEnvironment)
+	}
+
+	var environment queries.OrganizationEnvironment
+	err := c.WithTx(ctx, func(q *queries.Queries) error {
+		if err := q.LockOrganizationEventStore(ctx, input.OrganizationID); err != nil {
+			return err
+		}
+		var err error
+		environment, err = q.UpsertOrganizationEnvironment(ctx, queries.UpsertOrganizationEnvironmentParams{
+			OrganizationID: input.OrganizationID,
+			Name:           input.Name,
+			Tier:           input.Tier,
+			Color:          input.Color,
+			Protected:      input.Protected,
+			Url:            input.URL,
+			Source:         EnvironmentSourceSettings,
+			ChangedAtMs:    time.Now().UTC().UnixMilli(),
+		})
+		if err != nil {
+			return err
+		}
+		if err := replaceEnvironmentAliases(ctx, q, environment, input.Aliases); err != nil {
+			return err
+		}
+		return syncEnvironmentPriorities(ctx, q, input.OrganizationID)
+	})
+	return environment, err
+}
+
+// DeleteOrganizationEnvironment removes an environment and its aliases from
+// the registry. Protected environments have to be unprotected first.
+func (c *Database) DeleteOrganizationEnvironment(ctx context.Context, organizationID int64, name string) (bool, error) {
+	deleted := false
+	err := c.WithTx(ctx, func(q *queries.Queries) error {
+		if err := q.LockOrganizationEventStore(ctx, organizationID); err != nil {
+			return err
+		}
+		environment, err := q.GetOrganizationEnvironment(ctx, queries.GetOrganizationEnvironmentParams{OrganizationID: organizationID, Name: strings.TrimSpace(name)})
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if environment.Protected {
+			return fmt.Errorf("%w: %s", ErrProtectedEnvironment, environment.Name)
+		}
+		rows, err := q.DeleteOrganizationEnvironment(ctx, queries.DeleteOrganizationEnvironmentParams{OrganizationID: organizationID, ID: environment.ID})
+		if err != nil {
+			return err
+		}
+		deleted = rows > 0
+		return syncEnvironmentPriorities(ctx, q, organizationID)
+	})
+	return deleted, err
+}
+
+// replaceEnvironmentAliases stores the registered name and every alias of an
+// environment. A name can only belong to one environment.
+func replaceEnvironmentAliases(ctx context.Context, q *queries.Queries, environment queries.OrganizationEnvironment, aliases []string) error {
+	existing, err := q.ListEnvironmentAliases(ctx, environment.OrganizationID)
+	if err != nil {
+		return err
+	}
+	owners := make(map[string]string, len(existing))
+	for _, alias := range existing {
+		if alias.EnvironmentID != environment.ID {
+			owners[strings.ToLower(alias.Alias)] = alias.Environment
+		}
+	}
+
+	names := []string{environment.Name}
+	seen := map[string]bool{strings.ToLower(environment.Name): true}
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		if alias == "" || seen[strings.ToLower(alias)] {
+			continue
+		}
+		seen[strings.ToLower(alias)] = true
+		names = append(names, alias)
+	}
+	for _, name := range names {
+		if owner, ok := owners[strings.ToLower(name)]; ok {
+			return fmt.Errorf("%w: %q already belongs to %s", ErrInvalidEnvironment, name, owner)
+		}
+	}
+
+	if err := q.DeleteEnvironmentAliasesByEnvironment(ctx, queries.DeleteEnvironmentAliasesByEnvironmentParams{
+		OrganizationID: environment.OrganizationID,
+		EnvironmentID:  environment.ID,
+	}); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := q.CreateEnvironmentAlias(ctx, queries.CreateEnvironmentAliasParams{
+			OrganizationID: environment.OrganizationID,
+			Alias:          name,
+			EnvironmentID:  environment.ID,
+			Environment:    environment.Name,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncEnvironmentPriorities puts the registered environments first in the
+// environment priorities, in registry order, followed by the unregistered
+// environments that were already prioritised. Priorities of names that are
+// now aliases are dropped.
+func syncEnvironmentPriorities(ctx context.Context, q *queries.Queries, organizationID int64) error {
+	environments, err := q.ListOrganizationEnvironments(ctx, organizationID)
+	if err != nil || len(environments) == 0 {
+		return err
+	}
+	aliases, err := q.ListEnvironmentAliases(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	registered := make(map[string]bool, len(aliases))
+	for _, alias := range aliases {
+		registered[strings.ToLower(alias.Alias)] = true
+	}
+	priorities, err := q.ListOrganizationEnvironmentPriorities(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+
+	order := make([]string, 0, len(environments)+len(priorities))
+	for _, environment := range environments {
+		order = append(order, environment.Name)
+	}
+	for _, priority := range priorities {
+		if !registered[strings.ToLower(priority.Environment)] {
+			registered[strings.ToLower(priority.Environment)] = true
+			order = append(order, priority.Environment)
+		}
+	}
+
+	if err := q.DeleteOrganizationEnvironmentPriorities(ctx, organizationID); err != nil {
+		return err
+	}
+	for index, name := range order {
+		if _, err := q.CreateOrganizationEnvironmentPriority(ctx, queries.CreateOrganizationEnvironmentPriorityParams{
+			OrganizationID: organizationID,
+			Environment:    name,
+			SortOrder:      int64(index),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyEnvironmentEvent keeps the registry in step with environment.created,
+// environment.modified and environment.deleted events. An event older than
+// the last change to its environment is ignored, so replaying a log does not
+// undo changes made in settings; protected environments are never deleted by
+// events.
+func applyEnvironmentEvent(ctx context.Context, q *queries.Queries, params queries.AppendEventStoreParams) error {
+	if !strings.EqualFold(strings.TrimSpace(params.SubjectType), "environment") {
+		return nil
+	}
+	name := serviceNameFromSubjectID(params.SubjectID)
+	if name == "" {
+		return nil
+	}
+	deleted := strings.HasPrefix(params.EventType, "dev.cdevents.environment.deleted.")
+	if !deleted &&
+		!strings.HasPrefix(params.EventType, "dev.cdevents.environment.created.") &&
+		!strings.HasPrefix(params.EventType, "dev.cdevents.environment.modified.") {
+		return nil
+	}
+
+	var environment queries.OrganizationEnvironment
+	alias, err := q.GetEnvironmentAlias(ctx, queries.GetEnvironmentAliasParams{OrganizationID: params.OrganizationID, Alias: name})
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return err
+	default:
+		if environment, err = q.GetOrganizationEnvironment(ctx, queries.GetOrganizationEnvironmentParams{OrganizationID: params.OrganizationID, Name: alias.Environment}); err != nil {
+			return err
+		}
+		if params.EventTsMs < environment.ChangedAtMs {
+			return nil
+		}
+	}
+
+	if deleted {
+		// Deleting a name that is only an alias leaves the environment alone.
+		if environment.ID == 0 || environment.Protected || !strings.EqualFold(environment.Name, name) {
+			return nil
+		}
+		if _, err := q.DeleteOrganizationEnvironment(ctx, queries.DeleteOrganizationEnvironmentParams{OrganizationID: params.OrganizationID, ID: environment.ID}); err != nil {
+			return err
+		}
+		return syncEnvironmentPriorities(ctx, q, params.OrganizationID)
+	}
+
+	url := environmentEventURL(params.RawEventJson)
+	if environment.ID != 0 {
+		return q.TouchOrganizationEnvironmentFromEvent(ctx, queries.TouchOrganizationEnvironmentFromEventParams{
+			Url:            url,
+			ChangedAtMs:    params.EventTsMs,
+			OrganizationID: params.OrganizationID,
+			ID:             environment.ID,
+		})
+	}
+	tier := EnvironmentTierNonProduction
+	if strings.EqualFold(name, "production") || strings.EqualFold(name, "prod") {
+		tier = EnvironmentTierProduction
+	}
+	environment, err = q.UpsertOrganizationEnvironment(ctx, queries.UpsertOrganizationEnvironmentParams{
+		OrganizationID: params.OrganizationID,
+		Name:           name,
+		Tier:           tier,
+		Url:            url,
+		Source:         EnvironmentSourceEvent,
+		ChangedAtMs:    params.EventTsMs,
+	})
+	if err != nil {
+		return err
+	}
+	if err := replaceEnvironmentAliases(ctx, q, environment, nil); err != nil {
+		return err
+	}
+	return syncEnvironmentPriorities(ctx, q, params.OrganizationID)
+}
+
+func environmentEventURL(raw json.RawMessage) string {
+	var payload struct {
+		Subject struct {
+			Content struct {
+				URL string `json:"url"`
+			} `json:"content"`
+		} `json:"subject"`
+	}
+	_ = json.Unmarshal(raw, &payload)
+	return strings.TrimSpace(payload.Subject.Content.URL)
+}
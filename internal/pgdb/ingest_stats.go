@@ -0,0 +1,117 @@
+package pgdb
+
+import (
+	"context"
+	"time"
+
+	"github.com/fr0stylo/ddash/internal/pgdb/queries"
+)
+
+const (
+	// IngestOutcomeAccepted counts events newly appended to the event store.
+	IngestOutcomeAccepted = "accepted"
+	// IngestOutcomeDuplicate counts events already present in the event store.
+	IngestOutcomeDuplicate = "duplicate"
+
+	ingestStatsRetention = 7 * 24 * time.Hour
+)
+
+// ingestActivity accumulates hourly outcome counts and the latest event per
+// source for one append transaction.
+type ingestActivity struct {
+	now     time.Time
+	counts  map[ingestStatKey]int64
+	sources map[ingestSourceKey]queries.AppendEventStoreParams
+}
+
+type ingestStatKey struct {
+	organizationID int64
+	outcome        string
+}
+
+type ingestSourceKey struct {
+	organizationID int64
+	source         string
+}
+
+func newIngestActivity(now time.Time) *ingestActivity {
+	return &ingestActivity{
+		now:     now,
+		counts:  map[ingestStatKey]int64{},
+		sources: map[ingestSourceKey]queries.AppendEventStoreParams{},
+	}
+}
+
+func (a *ingestActivity) add(params queries.AppendEventStoreParams, inserted bool) {
+	outcome := IngestOutcomeDuplicate
+	if inserted {
+		outcome = IngestOutcomeAccepted
+		a.sources[ingestSourceKey{organizationID: params.OrganizationID, source: params.EventSource}] = params
+	}
+	a.counts[ingestStatKey{organizationID: params.OrganizationID, outcome: outcome}]++
+}
+
+func (a *ingestActivity) write(ctx context.Context, q *queries.Queries) error {
+	bucket := ingestStatsBucket(a.now)
+	organizations := map[int64]struct{}{}
+	for key, count := range a.counts {
+		organizations[key.organizationID] = struct{}{}
+		if err := incrementIngestHourlyStat(ctx, q, key.organizationID, bucket, key.outcome, "", count); err != nil {
+			return err
+		}
+	}
+	for organizationID := range organizations {
+		if err := pruneIngestHourlyStats(ctx, q, organizationID, bucket); err != nil {
+			return err
+		}
+	}
+	for key, params := range a.sources {
+		if err := q.UpsertIngestSourceActivity(ctx, queries.UpsertIngestSourceActivityParams{
+			OrganizationID: key.organizationID,
+			EventSource:    key.source,
+			LastEventID:    params.EventID,
+			LastEventType:  params.EventType,
+			LastSeenAt:     a.now.UnixMilli(),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordIngestOutcome adds count events with the given outcome and reason to
+// the current hourly bucket of an organization.
+func (c *Database) RecordIngestOutcome(ctx context.Context, organizationID int64, outcome, reason string, count int64, at time.Time) error {
+	bucket := ingestStatsBucket(at.UTC())
+	return c.WithTx(ctx, func(q *queries.Queries) error {
+		if err := incrementIngestHourlyStat(ctx, q, organizationID, bucket, outcome, reason, count); err != nil {
+			return err
+		}
+		return pruneIngestHourlyStats(ctx, q, organizationID, bucket)
+	})
+}
+
+func incrementIngestHourlyStat(ctx context.Context, q *queries.Queries, organizationID, bucket int64, outcome, reason string, count int64) error {
+	if count <= 0 {
+		return nil
+	}
+	return q.IncrementIngestHourlyStat(ctx, queries.IncrementIngestHourlyStatParams{
+		OrganizationID: organizationID,
+		BucketStart:    bucket,
+		Outcome:        outcome,
+		Reason:         reason,
+		EventCount:     count,
+	})
+}
+
+// pruneIngestHourlyStats drops an organization's buckets past the retention window.
+func pruneIngestHourlyStats(ctx context.Context, q *queries.Queries, organizationID, bucket int64) error {
+	return q.PruneIngestHourlyStats(ctx, queries.PruneIngestHourlyStatsParams{
+		OrganizationID: organizationID,
+		Before:         bucket - ingestStatsRetention.Milliseconds(),
+	})
+}
+
+func ingestStatsBucket(at time.Time) int64 {
+	return at.Truncate(time.Hour).UnixMilli()
+}
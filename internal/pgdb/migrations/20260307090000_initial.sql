@@ -0,0 +1,520 @@
+-- +goose Up
+-- The PostgreSQL schema mirrors the SQLite schema as of the environment
+-- registry migration, minus the tables only the SQLite store maintains
+-- (projector checkpoints, event archives, service identity rules and the
+-- environment registry). Flags are BOOLEAN, raw events are JSONB and the
+-- CDEvent field extraction SQLite repeats in every query lives in the
+-- functions below.
+
+-- +goose StatementBegin
+CREATE FUNCTION service_name_from_subject(subject_id TEXT) RETURNS TEXT
+LANGUAGE sql IMMUTABLE STRICT AS $$
+  SELECT CASE
+    WHEN strpos(subject_id, '/') > 0 THEN substr(subject_id, strpos(subject_id, '/') + 1)
+    ELSE subject_id
+  END
+$$;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION event_environment(raw JSONB) RETURNS TEXT
+LANGUAGE sql IMMUTABLE AS $$
+  SELECT COALESCE(NULLIF(raw #>> '{subject,content,environment,id}', ''), 'unknown')
+$$;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION event_artifact_id(raw JSONB) RETURNS TEXT
+LANGUAGE sql IMMUTABLE AS $$
+  SELECT COALESCE(raw #>> '{subject,content,artifactId}', '')
+$$;
+-- +goose StatementEnd
+
+-- artifact_service_name returns <name> for pkg:generic/<name>@<version>
+-- artifacts and '' otherwise.
+-- +goose StatementBegin
+CREATE FUNCTION artifact_service_name(artifact_id TEXT) RETURNS TEXT
+LANGUAGE sql IMMUTABLE AS $$
+  SELECT CASE
+    WHEN left(artifact_id, 12) = 'pkg:generic/' AND strpos(substr(artifact_id, 13), '@') > 0
+    THEN split_part(substr(artifact_id, 13), '@', 1)
+    ELSE ''
+  END
+$$;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION service_status(event_type TEXT) RETURNS TEXT
+LANGUAGE sql IMMUTABLE AS $$
+  SELECT CASE
+    WHEN event_type LIKE 'dev.cdevents.service.deployed.%' THEN 'synced'
+    WHEN event_type LIKE 'dev.cdevents.service.upgraded.%' THEN 'synced'
+    WHEN event_type LIKE 'dev.cdevents.service.published.%' THEN 'synced'
+    WHEN event_type LIKE 'dev.cdevents.service.rolledback.%' THEN 'warning'
+    WHEN event_type LIKE 'dev.cdevents.service.removed.%' THEN 'out-of-sync'
+    ELSE 'unknown'
+  END
+$$;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION utc_day(ts_ms BIGINT) RETURNS TEXT
+LANGUAGE sql STABLE AS $$
+  SELECT to_char(to_timestamp(ts_ms / 1000) AT TIME ZONE 'UTC', 'YYYY-MM-DD')
+$$;
+-- +goose StatementEnd
+
+-- utc_week_start returns the Sunday starting the week of ts_ms.
+-- +goose StatementBegin
+CREATE FUNCTION utc_week_start(ts_ms BIGINT) RETURNS TEXT
+LANGUAGE sql STABLE AS $$
+  SELECT to_char(
+    (to_timestamp(ts_ms / 1000) AT TIME ZONE 'UTC')
+      - make_interval(days => extract(dow FROM to_timestamp(ts_ms / 1000) AT TIME ZONE 'UTC')::INTEGER),
+    'YYYY-MM-DD'
+  )
+$$;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION utc_days_ago(days INTEGER) RETURNS TEXT
+LANGUAGE sql STABLE AS $$
+  SELECT to_char((now() AT TIME ZONE 'UTC') - make_interval(days => days), 'YYYY-MM-DD')
+$$;
+-- +goose StatementEnd
+
+CREATE TABLE organizations
+(
+    id             BIGSERIAL PRIMARY KEY,
+    name           TEXT NOT NULL UNIQUE,
+    auth_token     TEXT NOT NULL UNIQUE,
+    join_code      TEXT UNIQUE,
+    webhook_secret TEXT NOT NULL,
+    enabled        BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE users
+(
+    id         BIGSERIAL PRIMARY KEY,
+    github_id  TEXT,
+    email      TEXT NOT NULL UNIQUE,
+    nickname   TEXT NOT NULL UNIQUE,
+    name       TEXT,
+    avatar_url TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE organization_members
+(
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id         BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role            TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (organization_id, user_id),
+    CHECK (role IN ('owner', 'admin', 'member'))
+);
+CREATE INDEX idx_org_members_user ON organization_members (user_id);
+
+CREATE TABLE organization_join_requests
+(
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id         BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    request_code    TEXT NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'pending',
+    reviewed_by     BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at     TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (organization_id, user_id),
+    CHECK (status IN ('pending', 'approved', 'rejected'))
+);
+CREATE INDEX idx_org_join_requests_org_status ON organization_join_requests (organization_id, status);
+
+CREATE TABLE organization_required_fields
+(
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    label           TEXT NOT NULL,
+    field_type      TEXT NOT NULL,
+    sort_order      BIGINT NOT NULL DEFAULT 0,
+    is_filterable   BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_org_required_fields_org_order ON organization_required_fields (organization_id, sort_order, id);
+
+CREATE TABLE organization_environment_priorities
+(
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    environment     TEXT NOT NULL,
+    sort_order      BIGINT NOT NULL DEFAULT 0,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (organization_id, environment)
+);
+CREATE INDEX idx_org_environment_priorities_org_order ON organization_environment_priorities (organization_id, sort_order, id);
+
+CREATE TABLE organization_features
+(
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    feature_key     TEXT NOT NULL,
+    is_enabled      BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (organization_id, feature_key)
+);
+
+CREATE TABLE organization_preferences
+(
+    id               BIGSERIAL PRIMARY KEY,
+    organization_id  BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    preference_key   TEXT NOT NULL,
+    preference_value TEXT NOT NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (organization_id, preference_key)
+);
+
+CREATE TABLE organization_event_policies
+(
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    subject         TEXT NOT NULL,
+    mode            TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (organization_id, subject)
+);
+
+CREATE TABLE service_metadata
+(
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    service_name    TEXT NOT NULL,
+    label           TEXT NOT NULL,
+    value           TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (organization_id, service_name, label)
+);
+
+CREATE TABLE service_dependencies
+(
+    organization_id         BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    service_name            TEXT NOT NULL,
+    depends_on_service_name TEXT NOT NULL,
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, service_name, depends_on_service_name),
+    CHECK (length(trim(service_name)) > 0),
+    CHECK (length(trim(depends_on_service_name)) > 0),
+    CHECK (service_name <> depends_on_service_name)
+);
+CREATE INDEX idx_service_dependencies_org_depends_on ON service_dependencies (organization_id, depends_on_service_name, service_name);
+
+CREATE TABLE github_installation_mappings
+(
+    installation_id     BIGINT PRIMARY KEY,
+    organization_id     BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    organization_label  TEXT NOT NULL DEFAULT '',
+    default_environment TEXT NOT NULL DEFAULT '',
+    enabled             BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_github_installation_mappings_org ON github_installation_mappings (organization_id, installation_id);
+
+CREATE TABLE github_setup_intents
+(
+    state               TEXT PRIMARY KEY,
+    organization_id     BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    organization_label  TEXT NOT NULL DEFAULT '',
+    default_environment TEXT NOT NULL DEFAULT '',
+    expires_at          TIMESTAMPTZ NOT NULL,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_github_setup_intents_expires_at ON github_setup_intents (expires_at);
+
+CREATE TABLE gitlab_project_mappings
+(
+    project_id          BIGINT PRIMARY KEY,
+    organization_id     BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    project_path        TEXT NOT NULL DEFAULT '',
+    default_environment TEXT NOT NULL DEFAULT '',
+    enabled             BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_gitlab_project_mappings_org ON gitlab_project_mappings (organization_id, project_id);
+
+CREATE TABLE event_store
+(
+    seq             BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    event_id        TEXT NOT NULL,
+    event_type      TEXT NOT NULL,
+    event_source    TEXT NOT NULL,
+    event_timestamp TEXT NOT NULL,
+    event_ts_ms     BIGINT NOT NULL DEFAULT 0,
+    subject_id      TEXT NOT NULL,
+    subject_source  TEXT,
+    subject_type    TEXT NOT NULL,
+    chain_id        TEXT,
+    raw_event_json  JSONB NOT NULL,
+    ingested_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (organization_id, event_source, event_id)
+);
+CREATE INDEX idx_event_store_org_seq ON event_store (organization_id, seq DESC);
+CREATE INDEX idx_event_store_org_type_time ON event_store (organization_id, event_type, event_ts_ms DESC);
+CREATE INDEX idx_event_store_org_subject_time ON event_store (organization_id, subject_id, event_ts_ms DESC);
+CREATE INDEX idx_event_store_org_subjecttype_time ON event_store (organization_id, subject_type, event_ts_ms DESC, seq DESC);
+CREATE INDEX idx_event_store_org_service_time
+    ON event_store (organization_id, service_name_from_subject(subject_id), event_ts_ms DESC, seq DESC)
+    WHERE subject_type = 'service';
+
+CREATE TABLE service_current_state
+(
+    organization_id    BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    service_name       TEXT NOT NULL,
+    latest_event_seq   BIGINT NOT NULL,
+    latest_event_type  TEXT NOT NULL,
+    latest_event_ts_ms BIGINT NOT NULL,
+    latest_status      TEXT NOT NULL,
+    latest_artifact_id TEXT NOT NULL DEFAULT '',
+    latest_environment TEXT NOT NULL DEFAULT 'unknown',
+    drift_count        BIGINT NOT NULL DEFAULT 0,
+    failed_streak      BIGINT NOT NULL DEFAULT 0,
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, service_name)
+);
+
+CREATE TABLE service_env_state
+(
+    organization_id    BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    service_name       TEXT NOT NULL,
+    environment        TEXT NOT NULL,
+    latest_event_seq   BIGINT NOT NULL,
+    latest_event_type  TEXT NOT NULL,
+    latest_event_ts_ms BIGINT NOT NULL,
+    latest_status      TEXT NOT NULL,
+    latest_artifact_id TEXT NOT NULL DEFAULT '',
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, service_name, environment)
+);
+
+CREATE TABLE service_delivery_stats_daily
+(
+    organization_id      BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    service_name         TEXT NOT NULL,
+    day_utc              TEXT NOT NULL,
+    deploy_success_count BIGINT NOT NULL DEFAULT 0,
+    deploy_failure_count BIGINT NOT NULL DEFAULT 0,
+    rollback_count       BIGINT NOT NULL DEFAULT 0,
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, service_name, day_utc)
+);
+CREATE INDEX idx_service_delivery_stats_daily_org_day ON service_delivery_stats_daily (organization_id, day_utc DESC);
+
+CREATE TABLE service_change_links
+(
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    service_name    TEXT NOT NULL,
+    event_seq       BIGINT NOT NULL,
+    event_ts_ms     BIGINT NOT NULL,
+    chain_id        TEXT,
+    environment     TEXT NOT NULL DEFAULT 'unknown',
+    artifact_id     TEXT NOT NULL DEFAULT '',
+    pipeline_run_id TEXT NOT NULL DEFAULT '',
+    run_url         TEXT NOT NULL DEFAULT '',
+    actor_name      TEXT NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, service_name, event_seq)
+);
+CREATE INDEX idx_service_change_links_org_service_time ON service_change_links (organization_id, service_name, event_ts_ms DESC);
+
+CREATE TABLE service_pipeline_stats_daily
+(
+    organization_id          BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    service_name             TEXT NOT NULL,
+    day_utc                  TEXT NOT NULL,
+    pipeline_started_count   BIGINT NOT NULL DEFAULT 0,
+    pipeline_succeeded_count BIGINT NOT NULL DEFAULT 0,
+    pipeline_failed_count    BIGINT NOT NULL DEFAULT 0,
+    total_duration_seconds   BIGINT NOT NULL DEFAULT 0,
+    updated_at               TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, service_name, day_utc)
+);
+CREATE INDEX idx_pipeline_stats_org_day ON service_pipeline_stats_daily (organization_id, day_utc DESC);
+
+CREATE TABLE service_deployment_durations
+(
+    organization_id  BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    service_name     TEXT NOT NULL,
+    environment      TEXT NOT NULL,
+    event_seq        BIGINT NOT NULL,
+    event_ts_ms      BIGINT NOT NULL,
+    duration_seconds BIGINT NOT NULL,
+    artifact_id      TEXT NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, service_name, environment, event_seq)
+);
+CREATE INDEX idx_deployment_durations_org_time ON service_deployment_durations (organization_id, event_ts_ms DESC);
+
+CREATE TABLE service_environment_drift
+(
+    organization_id   BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    service_name      TEXT NOT NULL,
+    environment_from  TEXT NOT NULL,
+    environment_to    TEXT NOT NULL,
+    artifact_id_from  TEXT NOT NULL DEFAULT '',
+    artifact_id_to    TEXT NOT NULL DEFAULT '',
+    drift_detected_at BIGINT NOT NULL,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, service_name, environment_from, environment_to, drift_detected_at)
+);
+CREATE INDEX idx_environment_drift_org_service ON service_environment_drift (organization_id, service_name, drift_detected_at DESC);
+
+CREATE TABLE service_redeployment_stats
+(
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    service_name    TEXT NOT NULL,
+    day_utc         TEXT NOT NULL,
+    redeploy_count  BIGINT NOT NULL DEFAULT 0,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, service_name, day_utc)
+);
+
+CREATE TABLE service_throughput_stats
+(
+    organization_id   BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    service_name      TEXT NOT NULL,
+    week_start        TEXT NOT NULL,
+    changes_count     BIGINT NOT NULL DEFAULT 0,
+    deployments_count BIGINT NOT NULL DEFAULT 0,
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, service_name, week_start)
+);
+
+CREATE TABLE service_incident_links
+(
+    organization_id      BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    service_name         TEXT NOT NULL,
+    incident_id          TEXT NOT NULL,
+    incident_type        TEXT NOT NULL,
+    environment          TEXT NOT NULL DEFAULT '',
+    linked_at            BIGINT NOT NULL,
+    deployment_event_seq BIGINT,
+    deployment_ts_ms     BIGINT,
+    resolved_at          BIGINT,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, service_name, incident_id)
+);
+CREATE INDEX idx_incident_links_org_linked ON service_incident_links (organization_id, linked_at DESC);
+
+CREATE TABLE ingest_dead_letters
+(
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    reason          TEXT NOT NULL,
+    detail          TEXT NOT NULL DEFAULT '',
+    headers_json    TEXT NOT NULL DEFAULT '{}',
+    body            BYTEA NOT NULL,
+    body_size       BIGINT NOT NULL,
+    truncated       BOOLEAN NOT NULL DEFAULT FALSE,
+    received_at     BIGINT NOT NULL,
+    replayed_at     BIGINT,
+    replay_result   TEXT NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_ingest_dead_letters_org_received ON ingest_dead_letters (organization_id, received_at DESC);
+
+CREATE TABLE ingest_seen_signatures
+(
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    signature       TEXT NOT NULL,
+    seen_at         BIGINT NOT NULL,
+    expires_at      BIGINT NOT NULL,
+    PRIMARY KEY (organization_id, signature)
+);
+CREATE INDEX idx_ingest_seen_signatures_expires ON ingest_seen_signatures (expires_at);
+
+CREATE TABLE organization_ingest_credentials
+(
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    label           TEXT NOT NULL,
+    auth_token      TEXT NOT NULL UNIQUE,
+    webhook_secret  TEXT NOT NULL,
+    created_at      BIGINT NOT NULL,
+    expires_at      BIGINT,
+    revoked_at      BIGINT,
+    last_used_at    BIGINT
+);
+CREATE INDEX idx_org_ingest_credentials_org ON organization_ingest_credentials (organization_id, created_at DESC);
+
+CREATE TABLE ingest_hourly_stats
+(
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    bucket_start    BIGINT NOT NULL,
+    outcome         TEXT NOT NULL,
+    reason          TEXT NOT NULL DEFAULT '',
+    event_count     BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (organization_id, bucket_start, outcome, reason)
+);
+
+CREATE TABLE ingest_source_activity
+(
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    event_source    TEXT NOT NULL,
+    last_event_id   TEXT NOT NULL,
+    last_event_type TEXT NOT NULL,
+    last_seen_at    BIGINT NOT NULL,
+    PRIMARY KEY (organization_id, event_source)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS ingest_source_activity;
+DROP TABLE IF EXISTS ingest_hourly_stats;
+DROP TABLE IF EXISTS organization_ingest_credentials;
+DROP TABLE IF EXISTS ingest_seen_signatures;
+DROP TABLE IF EXISTS ingest_dead_letters;
+DROP TABLE IF EXISTS service_incident_links;
+DROP TABLE IF EXISTS service_throughput_stats;
+DROP TABLE IF EXISTS service_redeployment_stats;
+DROP TABLE IF EXISTS service_environment_drift;
+DROP TABLE IF EXISTS service_deployment_durations;
+DROP TABLE IF EXISTS service_pipeline_stats_daily;
+DROP TABLE IF EXISTS service_change_links;
+DROP TABLE IF EXISTS service_delivery_stats_daily;
+DROP TABLE IF EXISTS service_env_state;
+DROP TABLE IF EXISTS service_current_state;
+DROP TABLE IF EXISTS event_store;
+DROP TABLE IF EXISTS gitlab_project_mappings;
+DROP TABLE IF EXISTS github_setup_intents;
+DROP TABLE IF EXISTS github_installation_mappings;
+DROP TABLE IF EXISTS service_dependencies;
+DROP TABLE IF EXISTS service_metadata;
+DROP TABLE IF EXISTS organization_event_policies;
+DROP TABLE IF EXISTS organization_preferences;
+DROP TABLE IF EXISTS organization_features;
+DROP TABLE IF EXISTS organization_environment_priorities;
+DROP TABLE IF EXISTS organization_required_fields;
+DROP TABLE IF EXISTS organization_join_requests;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS organizations;
+DROP FUNCTION IF EXISTS utc_days_ago(INTEGER);
+DROP FUNCTION IF EXISTS utc_week_start(BIGINT);
+DROP FUNCTION IF EXISTS utc_day(BIGINT);
+DROP FUNCTION IF EXISTS service_status(TEXT);
+DROP FUNCTION IF EXISTS artifact_service_name(TEXT);
+DROP FUNCTION IF EXISTS event_artifact_id(JSONB);
+DROP FUNCTION IF EXISTS event_environment(JSONB);
+DROP FUNCTION IF EXISTS service_name_from_subject(TEXT);
@@ -0,0 +1,125 @@
+-- +goose Up
+-- Service identity rules and the environment registry, as in the SQLite
+-- schema. Environment names and aliases match in any letter case, which
+-- SQLite gets from COLLATE NOCASE and these tables from lower() indexes.
+CREATE TABLE service_identity_rules
+(
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    kind            TEXT NOT NULL,
+    pattern         TEXT NOT NULL,
+    service_name    TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (organization_id, kind, pattern),
+    CHECK (kind IN ('alias', 'regex')),
+    CHECK (length(trim(pattern)) > 0),
+    CHECK (length(trim(service_name)) > 0)
+);
+
+-- service_identity_aliases holds every derived service name the rules rewrite,
+-- so projections and reads resolve names with an indexed lookup.
+CREATE TABLE service_identity_aliases
+(
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    alias           TEXT NOT NULL,
+    service_name    TEXT NOT NULL,
+    PRIMARY KEY (organization_id, alias),
+    CHECK (alias <> service_name)
+);
+
+CREATE TABLE organization_environments
+(
+    id              BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name            TEXT NOT NULL,
+    tier            TEXT NOT NULL DEFAULT 'non-production',
+    color           TEXT NOT NULL DEFAULT '',
+    protected       BOOLEAN NOT NULL DEFAULT FALSE,
+    sort_order      BIGINT NOT NULL DEFAULT 0,
+    url             TEXT NOT NULL DEFAULT '',
+    source          TEXT NOT NULL DEFAULT 'settings',
+    changed_at_ms   BIGINT NOT NULL DEFAULT 0,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (tier IN ('production', 'non-production')),
+    CHECK (source IN ('event', 'settings')),
+    CHECK (length(trim(name)) > 0)
+);
+CREATE UNIQUE INDEX idx_organization_environments_name ON organization_environments (organization_id, lower(name));
+
+-- environment_aliases maps every name an environment is reported under,
+-- including its own name in any letter case, onto the registered name.
+CREATE TABLE environment_aliases
+(
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    alias           TEXT NOT NULL,
+    environment_id  BIGINT NOT NULL REFERENCES organization_environments(id) ON DELETE CASCADE,
+    environment     TEXT NOT NULL
+);
+CREATE UNIQUE INDEX idx_environment_aliases_alias ON environment_aliases (organization_id, lower(alias));
+CREATE INDEX idx_environment_aliases_environment ON environment_aliases (environment_id);
+
+-- resolve_service_name and resolve_environment map a name derived from an
+-- event onto the service or registered environment it belongs to, or
+-- return it unchanged. They play the part of the alias joins in the SQLite
+-- queries.
+-- +goose StatementBegin
+CREATE FUNCTION resolve_service_name(org BIGINT, name TEXT) RETURNS TEXT
+LANGUAGE sql STABLE AS $$
+  SELECT COALESCE(
+    (SELECT sia.service_name FROM service_identity_aliases sia WHERE sia.organization_id = org AND sia.alias = name),
+    name
+  )
+$$;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION resolve_environment(org BIGINT, name TEXT) RETURNS TEXT
+LANGUAGE sql STABLE AS $$
+  SELECT COALESCE(
+    (SELECT ea.environment FROM environment_aliases ea WHERE ea.organization_id = org AND lower(ea.alias) = lower(name)),
+    name
+  )
+$$;
+-- +goose StatementEnd
+
+-- Register the environments already announced by environment events, unless
+-- their latest event deleted them.
+INSERT INTO organization_environments (organization_id, name, sort_order, source, changed_at_ms)
+SELECT
+    latest.organization_id,
+    latest.name,
+    COALESCE(
+        (SELECT p.sort_order
+         FROM organization_environment_priorities p
+         WHERE p.organization_id = latest.organization_id AND p.environment = latest.name),
+        1000
+    ),
+    'event',
+    latest.event_ts_ms
+FROM (
+    SELECT DISTINCT ON (es.organization_id, lower(service_name_from_subject(es.subject_id)))
+        es.organization_id,
+        service_name_from_subject(es.subject_id) AS name,
+        es.event_type,
+        es.event_ts_ms
+    FROM event_store es
+    WHERE es.subject_type = 'environment'
+    ORDER BY es.organization_id, lower(service_name_from_subject(es.subject_id)), es.event_ts_ms DESC, es.seq DESC
+) latest
+WHERE latest.event_type NOT LIKE 'dev.cdevents.environment.deleted.%'
+  AND length(trim(latest.name)) > 0
+ON CONFLICT (organization_id, lower(name)) DO NOTHING;
+
+INSERT INTO environment_aliases (organization_id, alias, environment_id, environment)
+SELECT organization_id, name, id, name
+FROM organization_environments
+ON CONFLICT (organization_id, lower(alias)) DO NOTHING;
+
+-- +goose Down
+DROP FUNCTION IF EXISTS resolve_environment(BIGINT, TEXT);
+DROP FUNCTION IF EXISTS resolve_service_name(BIGINT, TEXT);
+DROP TABLE IF EXISTS environment_aliases;
+DROP TABLE IF EXISTS organization_environments;
+DROP TABLE IF EXISTS service_identity_aliases;
+DROP TABLE IF EXISTS service_identity_rules;
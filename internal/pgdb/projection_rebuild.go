@@ -0,0 +1,69 @@
+package pgdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/fr0stylo/ddash/internal/pgdb/queries"
+)
+
+// projectionTables lists the tables rebuilt from event_store.
+var projectionTables = []string{
+	"service_current_state",
+	"service_env_state",
+	"service_delivery_stats_daily",
+	"service_change_links",
+	"service_pipeline_stats_daily",
+	"service_deployment_durations",
+	"service_environment_drift",
+	"service_redeployment_stats",
+	"service_throughput_stats",
+	"service_incident_links",
+}
+
+// RebuildOrganizationProjections clears one organization's projections and
+// replays its event log through them, so service identity rules and
+// environment aliases changed since the events were appended apply to every
+// row. The rebuild runs in one transaction under the organization's event
+// store lock: readers keep the old rows until it commits and appends wait
+// for it. progress, when set, is called before each step.
+func (c *Database) RebuildOrganizationProjections(ctx context.Context, organizationID int64, progress func(step string, index, total int)) error {
+	const total = 3
+	report := func(step string, index int) {
+		if progress != nil {
+			progress(step, index, total)
+		}
+	}
+
+	tx, err := c.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	q := queries.New(tx)
+	if err := q.LockOrganizationEventStore(ctx, organizationID); err != nil {
+		return err
+	}
+
+	report("clear projections", 1)
+	for _, table := range projectionTables {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE organization_id = $1", organizationID); err != nil {
+			return fmt.Errorf("clear %s: %w", table, err)
+		}
+	}
+
+	report("replay events", 2)
+	events, err := q.ListOrganizationEventsForProjection(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		if err := applyProjections(ctx, q, projectedEvent(event)); err != nil {
+			return fmt.Errorf("replay event %d: %w", event.Seq, err)
+		}
+	}
+
+	report("commit", 3)
+	return tx.Commit()
+}
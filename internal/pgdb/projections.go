@@ -0,0 +1,137 @@
+package pgdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/fr0stylo/ddash/internal/pgdb/queries"
+)
+
+// projectedEvent is one event_store row applied to the read models.
+type projectedEvent struct {
+	Seq            int64
+	OrganizationID int64
+	EventType      string
+	SubjectType    string
+	SubjectID      string
+	EventTsMs      int64
+}
+
+func projectedEventFromParams(params queries.AppendEventStoreParams, seq int64) projectedEvent {
+	return projectedEvent{
+		Seq:            seq,
+		OrganizationID: params.OrganizationID,
+		EventType:      params.EventType,
+		SubjectType:    params.SubjectType,
+		SubjectID:      params.SubjectID,
+		EventTsMs:      params.EventTsMs,
+	}
+}
+
+// applyProjections updates every read model for one newly appended event in
+// the append transaction. Unlike the SQLite store there are no projector
+// checkpoints: the read models are always current with event_store.
+// Service and environment names are resolved through the organization's
+// aliases when the event is projected, so rows written before a rule or
+// alias changed keep their old names until the projections are rebuilt.
+func applyProjections(ctx context.Context, q *queries.Queries, event projectedEvent) error {
+	switch strings.ToLower(strings.TrimSpace(event.SubjectType)) {
+	case "service":
+		if err := applyServiceProjections(ctx, q, event); err != nil {
+			return err
+		}
+		return applyThroughputProjection(ctx, q, event)
+	case "incident":
+		return q.UpsertIncidentLinkFromEventSeq(ctx, queries.UpsertIncidentLinkFromEventSeqParams{
+			OrganizationID: event.OrganizationID,
+			Seq:            event.Seq,
+		})
+	case "change":
+		return applyThroughputProjection(ctx, q, event)
+	case "pipeline":
+		return q.UpsertPipelineStatsFromEvent(ctx, queries.UpsertPipelineStatsFromEventParams{
+			OrganizationID: event.OrganizationID,
+			Seq:            event.Seq,
+		})
+	}
+	return nil
+}
+
+func applyThroughputProjection(ctx context.Context, q *queries.Queries, event projectedEvent) error {
+	if strings.EqualFold(strings.TrimSpace(event.SubjectType), "service") && !strings.HasPrefix(event.EventType, "dev.cdevents.service.deployed.") {
+		return nil
+	}
+	return q.UpsertThroughputStats(ctx, queries.UpsertThroughputStatsParams{
+		OrganizationID: event.OrganizationID,
+		Seq:            event.Seq,
+	})
+}
+
+// applyServiceProjections updates state and analytics projections for one
+// service event.
+func applyServiceProjections(ctx context.Context, q *queries.Queries, event projectedEvent) error {
+	// Redeploy detection compares against the environment state before this
+	// event is applied to it.
+	redeploy, err := q.GetRedeploymentCheckFromEventSeq(ctx, queries.GetRedeploymentCheckFromEventSeqParams{
+		OrganizationID: event.OrganizationID,
+		Seq:            event.Seq,
+	})
+	isDeploy := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if err := q.UpsertServiceEnvStateFromEventSeq(ctx, queries.UpsertServiceEnvStateFromEventSeqParams{
+		OrganizationID: event.OrganizationID,
+		Seq:            event.Seq,
+	}); err != nil {
+		return err
+	}
+	if err := q.UpsertServiceDeliveryStatsDailyFromEventSeq(ctx, queries.UpsertServiceDeliveryStatsDailyFromEventSeqParams{
+		OrganizationID: event.OrganizationID,
+		Seq:            event.Seq,
+	}); err != nil {
+		return err
+	}
+	if err := q.UpsertServiceChangeLinkFromEventSeq(ctx, queries.UpsertServiceChangeLinkFromEventSeqParams{
+		OrganizationID: event.OrganizationID,
+		Seq:            event.Seq,
+	}); err != nil {
+		return err
+	}
+
+	if isDeploy {
+		if err := q.InsertDeploymentDuration(ctx, queries.InsertDeploymentDurationParams{
+			OrganizationID: event.OrganizationID,
+			Seq:            event.Seq,
+		}); err != nil {
+			return err
+		}
+		if err := q.UpsertRedeploymentStats(ctx, queries.UpsertRedeploymentStatsParams{
+			OrganizationID: event.OrganizationID,
+			ServiceName:    redeploy.ServiceName,
+			DayUtc:         redeploy.DayUtc,
+			SameArtifact:   redeploy.SameArtifact,
+		}); err != nil {
+			return err
+		}
+	}
+
+	serviceName, err := resolveServiceName(ctx, q, event.OrganizationID, serviceNameFromSubjectID(event.SubjectID))
+	if err != nil || serviceName == "" {
+		return err
+	}
+	if err := q.UpsertServiceCurrentStateByService(ctx, queries.UpsertServiceCurrentStateByServiceParams{
+		OrganizationID: event.OrganizationID,
+		ServiceName:    serviceName,
+	}); err != nil {
+		return err
+	}
+	return q.InsertEnvironmentDrift(ctx, queries.InsertEnvironmentDriftParams{
+		OrganizationID: event.OrganizationID,
+		ServiceName:    serviceName,
+		DetectedAt:     event.EventTsMs,
+	})
+}
@@ -0,0 +1,1032 @@
+-- name: GetDefaultOrganization :one
+SELECT *
+FROM organizations
+ORDER BY id
+LIMIT 1;
+
+-- name: GetOrganizationByAuthToken :one
+SELECT *
+FROM organizations
+WHERE auth_token = $1
+LIMIT 1;
+
+-- name: GetOrganizationByIngestCredentialToken :one
+SELECT o.*
+FROM organization_ingest_credentials c
+JOIN organizations o ON o.id = c.organization_id
+WHERE c.auth_token = sqlc.arg('auth_token')
+  AND c.revoked_at IS NULL
+  AND (c.expires_at IS NULL OR c.expires_at > sqlc.arg('now')::bigint)
+LIMIT 1;
+
+-- name: GetOrganizationByID :one
+SELECT *
+FROM organizations
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetOrganizationByJoinCode :one
+SELECT *
+FROM organizations
+WHERE join_code = $1
+LIMIT 1;
+
+-- name: ListOrganizations :many
+SELECT *
+FROM organizations
+ORDER BY name, id;
+
+-- name: UpdateOrganizationName :exec
+UPDATE organizations
+SET name = $1, updated_at = now()
+WHERE id = $2;
+
+-- name: UpdateOrganizationEnabled :exec
+UPDATE organizations
+SET enabled = $1, updated_at = now()
+WHERE id = $2;
+
+-- name: DeleteOrganization :exec
+DELETE FROM organizations
+WHERE id = $1;
+
+-- name: UpsertUser :one
+INSERT INTO users (github_id, email, nickname, name, avatar_url)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (email) DO UPDATE SET
+  github_id = excluded.github_id,
+  nickname = excluded.nickname,
+  name = excluded.name,
+  avatar_url = excluded.avatar_url,
+  updated_at = now()
+RETURNING *;
+
+-- name: GetUserByID :one
+SELECT *
+FROM users
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetUserByEmailOrNickname :one
+SELECT *
+FROM users
+WHERE email = $1 OR nickname = $2
+LIMIT 1;
+
+-- name: ListOrganizationsByUser :many
+SELECT o.*
+FROM organizations o
+JOIN organization_members m ON m.organization_id = o.id
+WHERE m.user_id = $1
+ORDER BY o.name, o.id;
+
+-- name: GetOrganizationMemberRole :one
+SELECT role
+FROM organization_members
+WHERE organization_id = $1 AND user_id = $2
+LIMIT 1;
+
+-- name: UpsertOrganizationMember :exec
+INSERT INTO organization_members (organization_id, user_id, role)
+VALUES ($1, $2, $3)
+ON CONFLICT (organization_id, user_id) DO UPDATE SET
+  role = excluded.role,
+  updated_at = now();
+
+-- name: DeleteOrganizationMember :exec
+DELETE FROM organization_members
+WHERE organization_id = $1 AND user_id = $2;
+
+-- name: CountOrganizationOwners :one
+SELECT COUNT(*)
+FROM organization_members
+WHERE organization_id = $1 AND role = 'owner';
+
+-- name: ListOrganizationMembers :many
+SELECT
+  u.id AS user_id,
+  u.email,
+  u.nickname,
+  u.name,
+  u.avatar_url,
+  m.role
+FROM organization_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.organization_id = $1
+ORDER BY
+  CASE m.role WHEN 'owner' THEN 0 WHEN 'admin' THEN 1 ELSE 2 END,
+  COALESCE(NULLIF(u.name, ''), u.nickname, u.email);
+
+-- name: UpsertGitHubInstallationMapping :exec
+INSERT INTO github_installation_mappings (
+  installation_id,
+  organization_id,
+  organization_label,
+  default_environment,
+  enabled
+)
+VALUES (
+  sqlc.arg('installation_id'),
+  sqlc.arg('organization_id'),
+  sqlc.arg('organization_label'),
+  sqlc.arg('default_environment'),
+  sqlc.arg('enabled')
+)
+ON CONFLICT (installation_id) DO UPDATE SET
+  organization_id = excluded.organization_id,
+  organization_label = excluded.organization_label,
+  default_environment = excluded.default_environment,
+  enabled = excluded.enabled,
+  updated_at = now();
+
+-- name: ListGitHubInstallationMappings :many
+SELECT
+  installation_id,
+  organization_id,
+  organization_label,
+  default_environment,
+  enabled
+FROM github_installation_mappings
+WHERE organization_id = sqlc.arg('organization_id')
+ORDER BY installation_id ASC;
+
+-- name: DeleteGitHubInstallationMapping :execrows
+DELETE FROM github_installation_mappings
+WHERE installation_id = sqlc.arg('installation_id')
+  AND organization_id = sqlc.arg('organization_id');
+
+-- name: GetOrganizationByGitHubInstallationID :one
+SELECT o.*
+FROM organizations o
+JOIN github_installation_mappings m ON m.organization_id = o.id
+WHERE m.installation_id = sqlc.arg('installation_id')
+  AND m.enabled
+  AND o.enabled
+LIMIT 1;
+
+-- name: UpsertGitLabProjectMapping :exec
+INSERT INTO gitlab_project_mappings (
+  project_id,
+  organization_id,
+  project_path,
+  default_environment,
+  enabled
+)
+VALUES (
+  sqlc.arg('project_id'),
+  sqlc.arg('organization_id'),
+  sqlc.arg('project_path'),
+  sqlc.arg('default_environment'),
+  sqlc.arg('enabled')
+)
+ON CONFLICT (project_id) DO UPDATE SET
+  organization_id = excluded.organization_id,
+  project_path = excluded.project_path,
+  default_environment = excluded.default_environment,
+  enabled = excluded.enabled,
+  updated_at = now();
+
+-- name: GetOrganizationByGitLabProjectID :one
+SELECT o.*
+FROM organizations o
+JOIN gitlab_project_mappings m ON m.organization_id = o.id
+WHERE m.project_id = sqlc.arg('project_id')
+  AND m.enabled
+  AND o.enabled
+LIMIT 1;
+
+-- name: CreateGitHubSetupIntent :exec
+INSERT INTO github_setup_intents (
+  state,
+  organization_id,
+  organization_label,
+  default_environment,
+  expires_at
+)
+VALUES (
+  sqlc.arg('state'),
+  sqlc.arg('organization_id'),
+  sqlc.arg('organization_label'),
+  sqlc.arg('default_environment'),
+  sqlc.arg('expires_at')
+);
+
+-- name: GetGitHubSetupIntentByState :one
+SELECT
+  state,
+  organization_id,
+  organization_label,
+  default_environment,
+  expires_at
+FROM github_setup_intents
+WHERE state = sqlc.arg('state')
+LIMIT 1;
+
+-- name: DeleteGitHubSetupIntent :exec
+DELETE FROM github_setup_intents
+WHERE state = sqlc.arg('state');
+
+-- name: CreateOrganization :one
+INSERT INTO organizations (name, auth_token, join_code, webhook_secret, enabled)
+VALUES (sqlc.arg('name'), sqlc.arg('auth_token'), sqlc.arg('join_code'), sqlc.arg('webhook_secret'), sqlc.arg('enabled'))
+RETURNING *;
+
+-- name: UpsertOrganizationJoinRequest :exec
+INSERT INTO organization_join_requests (organization_id, user_id, request_code, status)
+VALUES ($1, $2, $3, 'pending')
+ON CONFLICT (organization_id, user_id) DO UPDATE SET
+  request_code = excluded.request_code,
+  status = 'pending',
+  reviewed_by = NULL,
+  reviewed_at = NULL,
+  updated_at = now();
+
+-- name: ListPendingOrganizationJoinRequests :many
+SELECT
+  r.organization_id,
+  r.user_id,
+  r.request_code,
+  r.status,
+  u.email,
+  u.nickname,
+  u.name
+FROM organization_join_requests r
+JOIN users u ON u.id = r.user_id
+WHERE r.organization_id = $1
+  AND r.status = 'pending'
+ORDER BY r.created_at, r.id;
+
+-- name: SetOrganizationJoinRequestStatus :exec
+UPDATE organization_join_requests
+SET status = $1, reviewed_by = $2, reviewed_at = now(), updated_at = now()
+WHERE organization_id = $3 AND user_id = $4;
+
+-- name: ListOrganizationRequiredFields :many
+SELECT id, organization_id, label, field_type, sort_order, is_filterable
+FROM organization_required_fields
+WHERE organization_id = $1
+ORDER BY sort_order, id;
+
+-- name: DeleteOrganizationRequiredFields :exec
+DELETE FROM organization_required_fields
+WHERE organization_id = $1;
+
+-- name: CreateOrganizationRequiredField :one
+INSERT INTO organization_required_fields (organization_id, label, field_type, sort_order, is_filterable)
+VALUES (sqlc.arg('organization_id'), sqlc.arg('label'), sqlc.arg('field_type'), sqlc.arg('sort_order'), sqlc.arg('is_filterable'))
+RETURNING *;
+
+-- name: ListOrganizationEnvironmentPriorities :many
+SELECT id, organization_id, environment, sort_order
+FROM organization_environment_priorities
+WHERE organization_id = $1
+ORDER BY sort_order, id;
+
+-- name: DeleteOrganizationEnvironmentPriorities :exec
+DELETE FROM organization_environment_priorities
+WHERE organization_id = $1;
+
+-- name: CreateOrganizationEnvironmentPriority :one
+INSERT INTO organization_environment_priorities (organization_id, environment, sort_order)
+VALUES (sqlc.arg('organization_id'), sqlc.arg('environment'), sqlc.arg('sort_order'))
+RETURNING *;
+
+-- name: ListServiceMetadataByService :many
+SELECT label, value
+FROM service_metadata
+WHERE organization_id = sqlc.arg('organization_id')
+  AND service_name = sqlc.arg('service_name')
+ORDER BY label;
+
+-- name: ListServiceMetadataByOrganization :many
+SELECT service_name, label, value
+FROM service_metadata
+WHERE organization_id = sqlc.arg('organization_id')
+ORDER BY service_name, label;
+
+-- name: DeleteServiceMetadataByService :exec
+DELETE FROM service_metadata
+WHERE organization_id = sqlc.arg('organization_id')
+  AND service_name = sqlc.arg('service_name');
+
+-- name: UpsertServiceMetadata :exec
+INSERT INTO service_metadata (organization_id, service_name, label, value)
+VALUES (sqlc.arg('organization_id'), sqlc.arg('service_name'), sqlc.arg('label'), sqlc.arg('value'))
+ON CONFLICT (organization_id, service_name, label) DO UPDATE SET
+  value = excluded.value,
+  updated_at = now();
+
+-- name: ListDistinctServiceEnvironmentsFromEvents :many
+SELECT DISTINCT resolve_environment(es.organization_id, event_environment(es.raw_event_json)) AS environment
+FROM event_store es
+WHERE es.organization_id = sqlc.arg('organization_id')
+  AND es.subject_type = 'service'
+ORDER BY 1;
+
+-- name: UpdateOrganizationSecrets :exec
+UPDATE organizations
+SET auth_token = $1, webhook_secret = $2, enabled = $3, updated_at = now()
+WHERE id = $4;
+
+-- name: ListOrganizationFeatures :many
+SELECT feature_key, is_enabled
+FROM organization_features
+WHERE organization_id = $1
+ORDER BY feature_key;
+
+-- name: UpsertOrganizationFeature :exec
+INSERT INTO organization_features (organization_id, feature_key, is_enabled)
+VALUES ($1, $2, $3)
+ON CONFLICT (organization_id, feature_key) DO UPDATE SET
+  is_enabled = excluded.is_enabled,
+  updated_at = now();
+
+-- name: ListOrganizationPreferences :many
+SELECT preference_key, preference_value
+FROM organization_preferences
+WHERE organization_id = $1
+ORDER BY preference_key;
+
+-- name: GetOrganizationPreference :one
+SELECT preference_value
+FROM organization_preferences
+WHERE organization_id = $1
+  AND preference_key = $2;
+
+-- name: UpsertOrganizationPreference :exec
+INSERT INTO organization_preferences (organization_id, preference_key, preference_value)
+VALUES ($1, $2, $3)
+ON CONFLICT (organization_id, preference_key) DO UPDATE SET
+  preference_value = excluded.preference_value,
+  updated_at = now();
+
+-- name: ListOrganizationEventPolicies :many
+SELECT subject, mode
+FROM organization_event_policies
+WHERE organization_id = $1
+ORDER BY subject;
+
+-- name: DeleteOrganizationEventPolicies :exec
+DELETE FROM organization_event_policies
+WHERE organization_id = $1;
+
+-- name: UpsertOrganizationEventPolicy :exec
+INSERT INTO organization_event_policies (organization_id, subject, mode)
+VALUES ($1, $2, $3)
+ON CONFLICT (organization_id, subject) DO UPDATE SET
+  mode = excluded.mode,
+  updated_at = now();
+
+-- name: InsertIngestDeadLetter :exec
+INSERT INTO ingest_dead_letters (
+  organization_id,
+  reason,
+  detail,
+  headers_json,
+  body,
+  body_size,
+  truncated,
+  received_at
+)
+VALUES (
+  sqlc.arg('organization_id'),
+  sqlc.arg('reason'),
+  sqlc.arg('detail'),
+  sqlc.arg('headers_json'),
+  sqlc.arg('body'),
+  sqlc.arg('body_size'),
+  sqlc.arg('truncated'),
+  sqlc.arg('received_at')
+);
+
+-- name: PruneIngestDeadLetters :exec
+DELETE FROM ingest_dead_letters
+WHERE ingest_dead_letters.organization_id = sqlc.arg('organization_id')
+  AND (
+    ingest_dead_letters.received_at < sqlc.arg('received_before')
+    OR ingest_dead_letters.id NOT IN (
+      SELECT kept.id
+      FROM ingest_dead_letters kept
+      WHERE kept.organization_id = sqlc.arg('organization_id')
+      ORDER BY kept.received_at DESC, kept.id DESC
+      LIMIT sqlc.arg('keep_count')::bigint
+    )
+  );
+
+-- name: ListIngestDeadLetters :many
+SELECT id, organization_id, reason, detail, headers_json, body, body_size, truncated, received_at, replayed_at, replay_result
+FROM ingest_dead_letters
+WHERE organization_id = sqlc.arg('organization_id')
+ORDER BY received_at DESC, id DESC
+LIMIT sqlc.arg('limit')::bigint;
+
+-- name: GetIngestDeadLetter :one
+SELECT id, organization_id, reason, detail, headers_json, body, body_size, truncated, received_at, replayed_at, replay_result
+FROM ingest_dead_letters
+WHERE organization_id = sqlc.arg('organization_id')
+  AND id = sqlc.arg('id');
+
+-- name: MarkIngestDeadLetterReplayed :exec
+UPDATE ingest_dead_letters
+SET replayed_at = sqlc.arg('replayed_at'),
+    replay_result = sqlc.arg('replay_result')
+WHERE organization_id = sqlc.arg('organization_id')
+  AND id = sqlc.arg('id');
+
+-- name: DeleteIngestDeadLetter :exec
+DELETE FROM ingest_dead_letters
+WHERE organization_id = sqlc.arg('organization_id')
+  AND id = sqlc.arg('id');
+
+-- name: LockOrganizationEventStore :exec
+-- Serializes appends per organization so seq order matches commit order
+-- for readers that follow the event store by seq.
+SELECT pg_advisory_xact_lock(sqlc.arg('organization_id')::bigint);
+
+-- name: AppendEventStore :one
+INSERT INTO event_store (
+  organization_id,
+  event_id,
+  event_type,
+  event_source,
+  event_timestamp,
+  event_ts_ms,
+  subject_id,
+  subject_source,
+  subject_type,
+  chain_id,
+  raw_event_json
+)
+VALUES (
+  sqlc.arg('organization_id'),
+  sqlc.arg('event_id'),
+  sqlc.arg('event_type'),
+  sqlc.arg('event_source'),
+  sqlc.arg('event_timestamp'),
+  sqlc.arg('event_ts_ms'),
+  sqlc.arg('subject_id'),
+  sqlc.narg('subject_source'),
+  sqlc.arg('subject_type'),
+  sqlc.narg('chain_id'),
+  sqlc.arg('raw_event_json')
+)
+ON CONFLICT (organization_id, event_source, event_id) DO NOTHING
+RETURNING seq;
+
+-- name: UpsertServiceEnvStateFromEventSeq :exec
+INSERT INTO service_env_state (
+  organization_id,
+  service_name,
+  environment,
+  latest_event_seq,
+  latest_event_type,
+  latest_event_ts_ms,
+  latest_status,
+  latest_artifact_id
+)
+SELECT
+  es.organization_id,
+  resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)),
+  resolve_environment(es.organization_id, event_environment(es.raw_event_json)),
+  es.seq,
+  es.event_type,
+  es.event_ts_ms,
+  service_status(es.event_type),
+  event_artifact_id(es.raw_event_json)
+FROM event_store es
+WHERE es.organization_id = sqlc.arg('organization_id')
+  AND es.seq = sqlc.arg('seq')
+  AND es.subject_type = 'service'
+ON CONFLICT (organization_id, service_name, environment) DO UPDATE SET
+  latest_event_seq = excluded.latest_event_seq,
+  latest_event_type = excluded.latest_event_type,
+  latest_event_ts_ms = excluded.latest_event_ts_ms,
+  latest_status = excluded.latest_status,
+  latest_artifact_id = excluded.latest_artifact_id,
+  updated_at = now()
+WHERE
+  excluded.latest_event_ts_ms > service_env_state.latest_event_ts_ms
+  OR (excluded.latest_event_ts_ms = service_env_state.latest_event_ts_ms AND excluded.latest_event_seq > service_env_state.latest_event_seq);
+
+-- name: UpsertServiceDeliveryStatsDailyFromEventSeq :exec
+INSERT INTO service_delivery_stats_daily (
+  organization_id,
+  service_name,
+  day_utc,
+  deploy_success_count,
+  deploy_failure_count,
+  rollback_count
+)
+SELECT
+  es.organization_id,
+  resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)),
+  utc_day(es.event_ts_ms),
+  CASE WHEN service_status(es.event_type) = 'synced' THEN 1 ELSE 0 END,
+  CASE WHEN es.event_type LIKE 'dev.cdevents.service.removed.%' THEN 1 ELSE 0 END,
+  CASE WHEN es.event_type LIKE 'dev.cdevents.service.rolledback.%' THEN 1 ELSE 0 END
+FROM event_store es
+WHERE es.organization_id = sqlc.arg('organization_id')
+  AND es.seq = sqlc.arg('seq')
+  AND es.subject_type = 'service'
+ON CONFLICT (organization_id, service_name, day_utc) DO UPDATE SET
+  deploy_success_count = service_delivery_stats_daily.deploy_success_count + excluded.deploy_success_count,
+  deploy_failure_count = service_delivery_stats_daily.deploy_failure_count + excluded.deploy_failure_count,
+  rollback_count = service_delivery_stats_daily.rollback_count + excluded.rollback_count,
+  updated_at = now();
+
+-- name: UpsertServiceChangeLinkFromEventSeq :exec
+INSERT INTO service_change_links (
+  organization_id,
+  service_name,
+  event_seq,
+  event_ts_ms,
+  chain_id,
+  environment,
+  artifact_id,
+  pipeline_run_id,
+  run_url,
+  actor_name
+)
+SELECT
+  es.organization_id,
+  resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)),
+  es.seq,
+  es.event_ts_ms,
+  es.chain_id,
+  resolve_environment(es.organization_id, event_environment(es.raw_event_json)),
+  event_artifact_id(es.raw_event_json),
+  COALESCE(es.raw_event_json #>> '{subject,content,pipeline,runId}', ''),
+  COALESCE(es.raw_event_json #>> '{subject,content,pipeline,url}', ''),
+  COALESCE(es.raw_event_json #>> '{subject,content,actor,name}', '')
+FROM event_store es
+WHERE es.organization_id = sqlc.arg('organization_id')
+  AND es.seq = sqlc.arg('seq')
+  AND es.subject_type = 'service'
+ON CONFLICT (organization_id, service_name, event_seq) DO NOTHING;
+
+-- name: UpsertServiceCurrentStateByService :exec
+INSERT INTO service_current_state (
+  organization_id,
+  service_name,
+  latest_event_seq,
+  latest_event_type,
+  latest_event_ts_ms,
+  latest_status,
+  latest_artifact_id,
+  latest_environment,
+  drift_count,
+  failed_streak
+)
+SELECT
+  ses.organization_id,
+  ses.service_name,
+  ses.latest_event_seq,
+  ses.latest_event_type,
+  ses.latest_event_ts_ms,
+  ses.latest_status,
+  ses.latest_artifact_id,
+  ses.environment,
+  (
+    SELECT COUNT(DISTINCT NULLIF(se2.latest_artifact_id, ''))
+    FROM service_env_state se2
+    WHERE se2.organization_id = ses.organization_id
+      AND se2.service_name = ses.service_name
+  ),
+  (
+    SELECT COUNT(*)
+    FROM service_env_state se3
+    WHERE se3.organization_id = ses.organization_id
+      AND se3.service_name = ses.service_name
+      AND se3.latest_status IN ('warning', 'out-of-sync')
+  )
+FROM service_env_state ses
+WHERE ses.organization_id = sqlc.arg('organization_id')
+  AND ses.service_name = sqlc.arg('service_name')
+ORDER BY ses.latest_event_ts_ms DESC, ses.latest_event_seq DESC
+LIMIT 1
+ON CONFLICT (organization_id, service_name) DO UPDATE SET
+  latest_event_seq = excluded.latest_event_seq,
+  latest_event_type = excluded.latest_event_type,
+  latest_event_ts_ms = excluded.latest_event_ts_ms,
+  latest_status = excluded.latest_status,
+  latest_artifact_id = excluded.latest_artifact_id,
+  latest_environment = excluded.latest_environment,
+  drift_count = excluded.drift_count,
+  failed_streak = excluded.failed_streak,
+  updated_at = now();
+
+-- name: ListServiceInstancesFromEvents :many
+SELECT DISTINCT ON (resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)))
+  resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)) AS service_name,
+  resolve_environment(es.organization_id, event_environment(es.raw_event_json)) AS environment,
+  service_status(es.event_type) AS status,
+  es.event_timestamp AS last_deploy_at,
+  event_artifact_id(es.raw_event_json) AS artifact_id
+FROM event_store es
+WHERE es.subject_type = 'service'
+  AND es.organization_id = sqlc.arg('organization_id')
+ORDER BY resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)), es.event_ts_ms DESC, es.seq DESC;
+
+-- name: ListServiceInstancesByEnvFromEvents :many
+SELECT DISTINCT ON (resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)))
+  resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)) AS service_name,
+  resolve_environment(es.organization_id, event_environment(es.raw_event_json)) AS environment,
+  service_status(es.event_type) AS status,
+  es.event_timestamp AS last_deploy_at,
+  event_artifact_id(es.raw_event_json) AS artifact_id
+FROM event_store es
+WHERE es.subject_type = 'service'
+  AND es.organization_id = sqlc.arg('organization_id')
+  AND resolve_environment(es.organization_id, event_environment(es.raw_event_json)) = sqlc.arg('env')::text
+ORDER BY resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)), es.event_ts_ms DESC, es.seq DESC;
+
+-- name: ListDeploymentsFromEvents :many
+-- One page of deployments, newest first. A page continues below the
+-- (before_ts_ms, before_seq) position of the previous page's last row;
+-- before_seq = 0 starts at the newest deployment.
+SELECT
+  es.seq,
+  es.event_ts_ms,
+  es.event_timestamp AS deployed_at,
+  resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)) AS service,
+  resolve_environment(es.organization_id, event_environment(es.raw_event_json)) AS environment,
+  (CASE
+    WHEN service_status(es.event_type) = 'synced' THEN 'success'
+    WHEN service_status(es.event_type) IN ('warning', 'out-of-sync') THEN 'error'
+    ELSE 'queued'
+  END)::text AS status
+FROM event_store es
+WHERE es.subject_type = 'service'
+  AND es.organization_id = sqlc.arg('organization_id')
+  AND (sqlc.arg('env')::text IN ('', 'all') OR resolve_environment(es.organization_id, event_environment(es.raw_event_json)) = sqlc.arg('env')::text)
+  AND (sqlc.arg('service')::text IN ('', 'all') OR es.subject_id = sqlc.arg('service')::text OR resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)) = sqlc.arg('service')::text)
+  AND (
+    sqlc.arg('status')::text IN ('', 'all')
+    OR (sqlc.arg('status')::text = 'success' AND service_status(es.event_type) = 'synced')
+    OR (sqlc.arg('status')::text = 'error' AND service_status(es.event_type) IN ('warning', 'out-of-sync'))
+    OR (sqlc.arg('status')::text = 'queued' AND service_status(es.event_type) NOT IN ('synced', 'warning', 'out-of-sync'))
+  )
+  AND (sqlc.arg('from_ms')::bigint = 0 OR es.event_ts_ms >= sqlc.arg('from_ms')::bigint)
+  AND (sqlc.arg('to_ms')::bigint = 0 OR es.event_ts_ms < sqlc.arg('to_ms')::bigint)
+  AND (
+    sqlc.arg('before_seq')::bigint = 0
+    OR (es.event_ts_ms, es.seq) < (sqlc.arg('before_ts_ms')::bigint, sqlc.arg('before_seq')::bigint)
+  )
+ORDER BY es.event_ts_ms DESC, es.seq DESC
+LIMIT sqlc.arg('limit')::bigint;
+
+-- name: ListDeploymentEnvironmentCounts :many
+SELECT
+  resolve_environment(es.organization_id, event_environment(es.raw_event_json)) AS environment,
+  COUNT(*) FILTER (WHERE es.event_ts_ms >= sqlc.arg('recent_from_ms')::bigint) AS recent_count,
+  COUNT(*) AS total_count
+FROM event_store es
+WHERE es.organization_id = sqlc.arg('organization_id')
+  AND es.subject_type = 'service'
+  AND es.event_ts_ms >= sqlc.arg('from_ms')::bigint
+GROUP BY resolve_environment(es.organization_id, event_environment(es.raw_event_json))
+ORDER BY resolve_environment(es.organization_id, event_environment(es.raw_event_json));
+
+-- name: ListDeploymentServices :many
+SELECT DISTINCT service_name
+FROM service_env_state
+WHERE organization_id = sqlc.arg('organization_id')
+ORDER BY service_name;
+
+-- name: GetServiceLatestFromEvents :one
+SELECT
+  resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)) AS service_name,
+  es.subject_id AS raw_subject_id,
+  es.event_timestamp AS last_deploy_at,
+  'cdevents'::text AS integration_type
+FROM event_store es
+WHERE es.subject_type = 'service'
+  AND es.organization_id = sqlc.arg('organization_id')
+  AND (es.subject_id = sqlc.arg('service')::text OR resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)) = sqlc.arg('service')::text)
+ORDER BY es.event_ts_ms DESC, es.seq DESC
+LIMIT 1;
+
+-- name: ListServiceEnvironmentsFromEvents :many
+SELECT name, released_at, ref
+FROM (
+  SELECT DISTINCT ON (resolve_environment(es.organization_id, event_environment(es.raw_event_json)))
+    resolve_environment(es.organization_id, event_environment(es.raw_event_json)) AS name,
+    es.event_timestamp AS released_at,
+    event_artifact_id(es.raw_event_json) AS ref
+  FROM event_store es
+  WHERE es.subject_type = 'service'
+    AND es.organization_id = sqlc.arg('organization_id')
+    AND (es.subject_id = sqlc.arg('service')::text OR resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)) = sqlc.arg('service')::text)
+  ORDER BY resolve_environment(es.organization_id, event_environment(es.raw_event_json)), es.event_ts_ms DESC, es.seq DESC
+) latest
+ORDER BY released_at DESC;
+
+-- name: ListDeploymentHistoryByServiceFromEvents :many
+SELECT
+  es.event_timestamp AS deployed_at,
+  event_artifact_id(es.raw_event_json) AS release_ref,
+  resolve_environment(es.organization_id, event_environment(es.raw_event_json)) AS environment,
+  COALESCE(es.chain_id, '') AS chain_id
+FROM event_store es
+WHERE es.subject_type = 'service'
+  AND es.organization_id = sqlc.arg('organization_id')
+  AND (es.subject_id = sqlc.arg('service')::text OR resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)) = sqlc.arg('service')::text)
+ORDER BY es.event_ts_ms DESC, es.seq DESC
+LIMIT sqlc.arg('limit')::bigint;
+
+-- name: ListServiceDependencies :many
+SELECT depends_on_service_name
+FROM service_dependencies
+WHERE organization_id = sqlc.arg('organization_id')
+  AND service_name = sqlc.arg('service_name')
+ORDER BY depends_on_service_name;
+
+-- name: ListServiceDependants :many
+SELECT service_name
+FROM service_dependencies
+WHERE organization_id = sqlc.arg('organization_id')
+  AND depends_on_service_name = sqlc.arg('service_name')
+ORDER BY service_name;
+
+-- name: UpsertServiceDependency :exec
+INSERT INTO service_dependencies (organization_id, service_name, depends_on_service_name)
+VALUES (sqlc.arg('organization_id'), sqlc.arg('service_name'), sqlc.arg('depends_on_service_name'))
+ON CONFLICT (organization_id, service_name, depends_on_service_name) DO NOTHING;
+
+-- name: DeleteServiceDependency :exec
+DELETE FROM service_dependencies
+WHERE organization_id = sqlc.arg('organization_id')
+  AND service_name = sqlc.arg('service_name')
+  AND depends_on_service_name = sqlc.arg('depends_on_service_name');
+
+-- name: GetOrganizationRenderVersion :one
+SELECT GREATEST(
+  (SELECT COALESCE(MAX(seq), 0) FROM event_store WHERE event_store.organization_id = sqlc.arg('org_id')),
+  (SELECT COALESCE(MAX(EXTRACT(EPOCH FROM updated_at)::bigint), 0) FROM organizations WHERE id = sqlc.arg('org_id')),
+  (SELECT COALESCE(MAX(EXTRACT(EPOCH FROM updated_at)::bigint), 0) FROM service_metadata WHERE service_metadata.organization_id = sqlc.arg('org_id')),
+  (SELECT COALESCE(MAX(EXTRACT(EPOCH FROM updated_at)::bigint), 0) FROM organization_required_fields WHERE organization_required_fields.organization_id = sqlc.arg('org_id')),
+  (SELECT COALESCE(MAX(EXTRACT(EPOCH FROM updated_at)::bigint), 0) FROM organization_environment_priorities WHERE organization_environment_priorities.organization_id = sqlc.arg('org_id')),
+  (SELECT COALESCE(MAX(EXTRACT(EPOCH FROM updated_at)::bigint), 0) FROM organization_features WHERE organization_features.organization_id = sqlc.arg('org_id')),
+  (SELECT COALESCE(MAX(EXTRACT(EPOCH FROM updated_at)::bigint), 0) FROM organization_preferences WHERE organization_preferences.organization_id = sqlc.arg('org_id')),
+  (SELECT COALESCE(MAX(EXTRACT(EPOCH FROM updated_at)::bigint), 0) FROM organization_event_policies WHERE organization_event_policies.organization_id = sqlc.arg('org_id')),
+  (SELECT COALESCE(MAX(EXTRACT(EPOCH FROM created_at)::bigint), 0) FROM service_dependencies WHERE service_dependencies.organization_id = sqlc.arg('org_id'))
+)::bigint AS version;
+
+-- name: GetOrganizationLatestEventSeq :one
+SELECT COALESCE(MAX(seq), 0)::bigint AS seq
+FROM event_store
+WHERE organization_id = sqlc.arg('organization_id');
+
+-- name: ListServiceChangesAfterSeq :many
+SELECT
+  resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)) AS service_name,
+  MAX(es.seq)::bigint AS last_seq
+FROM event_store es
+WHERE es.organization_id = sqlc.arg('organization_id')
+  AND es.subject_type = 'service'
+  AND es.seq > sqlc.arg('after_seq')
+GROUP BY resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id))
+ORDER BY last_seq ASC
+LIMIT sqlc.arg('limit')::bigint;
+
+-- name: InsertIngestSeenSignature :execrows
+INSERT INTO ingest_seen_signatures (organization_id, signature, seen_at, expires_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (organization_id, signature) DO NOTHING;
+
+-- name: PruneIngestSeenSignatures :exec
+DELETE FROM ingest_seen_signatures
+WHERE expires_at < $1;
+
+-- name: ListOrganizationIngestCredentials :many
+SELECT id, organization_id, label, auth_token, webhook_secret, created_at, expires_at, revoked_at, last_used_at
+FROM organization_ingest_credentials
+WHERE organization_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: InsertOrganizationIngestCredential :one
+INSERT INTO organization_ingest_credentials (organization_id, label, auth_token, webhook_secret, created_at, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, organization_id, label, auth_token, webhook_secret, created_at, expires_at, revoked_at, last_used_at;
+
+-- name: ExpireOrganizationIngestCredential :execrows
+UPDATE organization_ingest_credentials
+SET expires_at = sqlc.arg('expires_at')
+WHERE organization_id = sqlc.arg('organization_id')
+  AND id = sqlc.arg('id')
+  AND revoked_at IS NULL;
+
+-- name: RevokeOrganizationIngestCredential :execrows
+UPDATE organization_ingest_credentials
+SET revoked_at = sqlc.arg('revoked_at')
+WHERE organization_id = sqlc.arg('organization_id')
+  AND id = sqlc.arg('id')
+  AND revoked_at IS NULL;
+
+-- name: TouchOrganizationIngestCredential :exec
+UPDATE organization_ingest_credentials
+SET last_used_at = sqlc.arg('used_at')
+WHERE organization_id = sqlc.arg('organization_id')
+  AND id = sqlc.arg('id')
+  AND (last_used_at IS NULL OR last_used_at < sqlc.arg('stale_before')::bigint);
+
+-- name: IncrementIngestHourlyStat :exec
+INSERT INTO ingest_hourly_stats (organization_id, bucket_start, outcome, reason, event_count)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (organization_id, bucket_start, outcome, reason) DO UPDATE SET
+  event_count = ingest_hourly_stats.event_count + excluded.event_count;
+
+-- name: PruneIngestHourlyStats :exec
+DELETE FROM ingest_hourly_stats
+WHERE organization_id = sqlc.arg('organization_id')
+  AND bucket_start < sqlc.arg('before');
+
+-- name: ListIngestHourlyStats :many
+SELECT bucket_start, outcome, reason, event_count
+FROM ingest_hourly_stats
+WHERE organization_id = sqlc.arg('organization_id')
+  AND bucket_start >= sqlc.arg('since')
+ORDER BY bucket_start, outcome, reason;
+
+-- name: UpsertIngestSourceActivity :exec
+INSERT INTO ingest_source_activity (organization_id, event_source, last_event_id, last_event_type, last_seen_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (organization_id, event_source) DO UPDATE SET
+  last_event_id = excluded.last_event_id,
+  last_event_type = excluded.last_event_type,
+  last_seen_at = excluded.last_seen_at
+WHERE excluded.last_seen_at >= ingest_source_activity.last_seen_at;
+
+-- name: ListIngestSourceActivity :many
+SELECT event_source, last_event_id, last_event_type, last_seen_at
+FROM ingest_source_activity
+WHERE organization_id = sqlc.arg('organization_id')
+ORDER BY last_seen_at DESC
+LIMIT sqlc.arg('limit')::bigint;
+
+-- name: ListServiceIdentityRules :many
+SELECT *
+FROM service_identity_rules
+WHERE organization_id = sqlc.arg('organization_id')
+ORDER BY id;
+
+-- name: CreateServiceIdentityRule :one
+INSERT INTO service_identity_rules (organization_id, kind, pattern, service_name)
+VALUES (sqlc.arg('organization_id'), sqlc.arg('kind'), sqlc.arg('pattern'), sqlc.arg('service_name'))
+RETURNING *;
+
+-- name: DeleteServiceIdentityRule :execrows
+DELETE FROM service_identity_rules
+WHERE organization_id = sqlc.arg('organization_id')
+  AND id = sqlc.arg('id');
+
+-- name: ListServiceIdentityAliases :many
+SELECT *
+FROM service_identity_aliases
+WHERE organization_id = sqlc.arg('organization_id')
+ORDER BY service_name, alias;
+
+-- name: GetServiceIdentityAlias :one
+SELECT service_name
+FROM service_identity_aliases
+WHERE organization_id = sqlc.arg('organization_id')
+  AND alias = sqlc.arg('alias');
+
+-- name: UpsertServiceIdentityAlias :exec
+INSERT INTO service_identity_aliases (organization_id, alias, service_name)
+VALUES (sqlc.arg('organization_id'), sqlc.arg('alias'), sqlc.arg('service_name'))
+ON CONFLICT (organization_id, alias) DO UPDATE SET
+  service_name = excluded.service_name;
+
+-- name: DeleteServiceIdentityAliases :exec
+DELETE FROM service_identity_aliases
+WHERE organization_id = sqlc.arg('organization_id');
+
+-- name: ListServiceNameCandidates :many
+SELECT ses.service_name AS name FROM service_env_state ses WHERE ses.organization_id = sqlc.arg('organization_id')
+UNION
+SELECT psd.service_name FROM service_pipeline_stats_daily psd WHERE psd.organization_id = sqlc.arg('organization_id')
+UNION
+SELECT sts.service_name FROM service_throughput_stats sts WHERE sts.organization_id = sqlc.arg('organization_id')
+UNION
+SELECT sil.service_name FROM service_incident_links sil WHERE sil.organization_id = sqlc.arg('organization_id')
+UNION
+SELECT sm.service_name FROM service_metadata sm WHERE sm.organization_id = sqlc.arg('organization_id')
+UNION
+SELECT sd.service_name FROM service_dependencies sd WHERE sd.organization_id = sqlc.arg('organization_id')
+UNION
+SELECT sdo.depends_on_service_name FROM service_dependencies sdo WHERE sdo.organization_id = sqlc.arg('organization_id')
+UNION
+SELECT sia.alias FROM service_identity_aliases sia WHERE sia.organization_id = sqlc.arg('organization_id')
+ORDER BY 1;
+
+-- name: MoveServiceMetadata :exec
+INSERT INTO service_metadata (organization_id, service_name, label, value)
+SELECT src.organization_id, sqlc.arg('to_service_name')::text, src.label, src.value
+FROM service_metadata src
+WHERE src.organization_id = sqlc.arg('organization_id')
+  AND src.service_name = sqlc.arg('from_service_name')::text
+ON CONFLICT (organization_id, service_name, label) DO NOTHING;
+
+-- name: MoveServiceDependencies :exec
+INSERT INTO service_dependencies (organization_id, service_name, depends_on_service_name)
+SELECT moved.organization_id, moved.service_name, moved.depends_on_service_name
+FROM (
+  SELECT
+    src.organization_id,
+    CASE WHEN src.service_name = sqlc.arg('from_service_name')::text THEN sqlc.arg('to_service_name')::text ELSE src.service_name END AS service_name,
+    CASE WHEN src.depends_on_service_name = sqlc.arg('from_service_name')::text THEN sqlc.arg('to_service_name')::text ELSE src.depends_on_service_name END AS depends_on_service_name
+  FROM service_dependencies src
+  WHERE src.organization_id = sqlc.arg('organization_id')
+    AND (src.service_name = sqlc.arg('from_service_name')::text OR src.depends_on_service_name = sqlc.arg('from_service_name')::text)
+) moved
+WHERE moved.service_name <> moved.depends_on_service_name
+ON CONFLICT (organization_id, service_name, depends_on_service_name) DO NOTHING;
+
+-- name: DeleteServiceDependenciesByService :exec
+DELETE FROM service_dependencies
+WHERE organization_id = sqlc.arg('organization_id')
+  AND (service_name = sqlc.arg('service_name') OR depends_on_service_name = sqlc.arg('service_name'));
+
+-- name: ListOrganizationEnvironments :many
+SELECT *
+FROM organization_environments
+WHERE organization_id = sqlc.arg('organization_id')
+ORDER BY sort_order, name;
+
+-- name: GetOrganizationEnvironment :one
+SELECT *
+FROM organization_environments
+WHERE organization_id = sqlc.arg('organization_id')
+  AND lower(name) = lower(sqlc.arg('name')::text);
+
+-- name: UpsertOrganizationEnvironment :one
+INSERT INTO organization_environments (organization_id, name, tier, color, protected, sort_order, url, source, changed_at_ms)
+VALUES (
+  sqlc.arg('organization_id'),
+  sqlc.arg('name'),
+  sqlc.arg('tier'),
+  sqlc.arg('color'),
+  sqlc.arg('protected'),
+  (SELECT COALESCE(MAX(oe.sort_order) + 1, 0) FROM organization_environments oe WHERE oe.organization_id = sqlc.arg('organization_id')),
+  sqlc.arg('url'),
+  sqlc.arg('source'),
+  sqlc.arg('changed_at_ms')
+)
+ON CONFLICT (organization_id, lower(name)) DO UPDATE SET
+  tier = excluded.tier,
+  color = excluded.color,
+  protected = excluded.protected,
+  url = excluded.url,
+  changed_at_ms = excluded.changed_at_ms,
+  updated_at = now()
+RETURNING *;
+
+-- name: TouchOrganizationEnvironmentFromEvent :exec
+UPDATE organization_environments
+SET url = CASE WHEN sqlc.arg('url')::text <> '' THEN sqlc.arg('url')::text ELSE url END,
+    changed_at_ms = sqlc.arg('changed_at_ms'),
+    updated_at = now()
+WHERE organization_id = sqlc.arg('organization_id')
+  AND id = sqlc.arg('id');
+
+-- name: SetOrganizationEnvironmentSortOrder :exec
+UPDATE organization_environments
+SET sort_order = sqlc.arg('sort_order'),
+    updated_at = now()
+WHERE organization_id = sqlc.arg('organization_id')
+  AND lower(name) = lower(sqlc.arg('name')::text);
+
+-- name: DeleteOrganizationEnvironment :execrows
+DELETE FROM organization_environments
+WHERE organization_id = sqlc.arg('organization_id')
+  AND id = sqlc.arg('id');
+
+-- name: ListEnvironmentAliases :many
+SELECT *
+FROM environment_aliases
+WHERE organization_id = sqlc.arg('organization_id')
+ORDER BY environment, alias;
+
+-- name: GetEnvironmentAlias :one
+SELECT *
+FROM environment_aliases
+WHERE organization_id = sqlc.arg('organization_id')
+  AND lower(alias) = lower(sqlc.arg('alias')::text);
+
+-- name: CreateEnvironmentAlias :exec
+INSERT INTO environment_aliases (organization_id, alias, environment_id, environment)
+VALUES (sqlc.arg('organization_id'), sqlc.arg('alias'), sqlc.arg('environment_id'), sqlc.arg('environment'));
+
+-- name: DeleteEnvironmentAliasesByEnvironment :exec
+DELETE FROM environment_aliases
+WHERE organization_id = sqlc.arg('organization_id')
+  AND environment_id = sqlc.arg('environment_id');
+
+-- name: ListOrganizationEventsForProjection :many
+-- Events applied to the read models when an organization's projections are
+-- rebuilt, oldest first.
+SELECT seq, organization_id, event_type, subject_type, subject_id, event_ts_ms
+FROM event_store
+WHERE organization_id = sqlc.arg('organization_id')
+ORDER BY seq;
@@ -0,0 +1,564 @@
+-- name: CountEventStore :one
+SELECT COUNT(*)
+FROM event_store;
+
+-- name: CountEventStoreByOrganization :one
+SELECT COUNT(*)
+FROM event_store
+WHERE organization_id = sqlc.arg('organization_id');
+
+-- name: ListServiceLeadTimeSamplesFromEvents :many
+WITH deploy_events AS (
+  SELECT
+    es.event_ts_ms AS deploy_ts_ms,
+    utc_day(es.event_ts_ms) AS day_utc,
+    resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)) AS service_name
+  FROM event_store es
+  WHERE es.organization_id = sqlc.arg('organization_id')
+    AND es.subject_type = 'service'
+    AND es.event_type LIKE 'dev.cdevents.service.deployed.%'
+    AND es.event_ts_ms >= sqlc.arg('since_ms')::bigint
+), change_events AS (
+  SELECT
+    es.event_ts_ms AS change_ts_ms,
+    resolve_service_name(es.organization_id, artifact_service_name(es.raw_event_json #>> '{subject,content,artifactId}')) AS service_name
+  FROM event_store es
+  WHERE es.organization_id = sqlc.arg('organization_id')
+    AND es.subject_type = 'change'
+    AND es.event_ts_ms >= sqlc.arg('since_ms')::bigint
+), samples AS (
+  SELECT
+    d.day_utc,
+    d.service_name,
+    (d.deploy_ts_ms - (
+      SELECT MAX(c.change_ts_ms)
+      FROM change_events c
+      WHERE c.service_name = d.service_name
+        AND c.change_ts_ms <= d.deploy_ts_ms
+    )) / 1000 AS lead_seconds
+  FROM deploy_events d
+  WHERE d.service_name <> ''
+)
+SELECT day_utc, service_name, lead_seconds::bigint AS lead_seconds
+FROM samples
+WHERE lead_seconds IS NOT NULL
+  AND lead_seconds >= 0
+ORDER BY day_utc DESC, service_name ASC, lead_seconds ASC;
+
+-- name: GetServiceCurrentState :one
+SELECT
+  latest_status,
+  latest_event_ts_ms,
+  drift_count,
+  failed_streak
+FROM service_current_state
+WHERE organization_id = sqlc.arg('organization_id')
+  AND service_name = sqlc.arg('service_name')
+LIMIT 1;
+
+-- name: GetServiceDeliveryStats30d :one
+SELECT
+  COALESCE(SUM(deploy_success_count), 0)::bigint AS deploy_success_count,
+  COALESCE(SUM(deploy_failure_count), 0)::bigint AS deploy_failure_count,
+  COALESCE(SUM(rollback_count), 0)::bigint AS rollback_count
+FROM service_delivery_stats_daily
+WHERE organization_id = sqlc.arg('organization_id')
+  AND service_name = sqlc.arg('service_name')
+  AND day_utc >= utc_days_ago(30);
+
+-- name: ListServiceChangeLinksRecent :many
+SELECT
+  event_ts_ms,
+  chain_id,
+  environment,
+  artifact_id,
+  pipeline_run_id,
+  run_url,
+  actor_name
+FROM service_change_links
+WHERE organization_id = sqlc.arg('organization_id')
+  AND service_name = sqlc.arg('service_name')
+ORDER BY event_ts_ms DESC
+LIMIT sqlc.arg('limit')::bigint;
+
+-- Pipeline Execution Metrics
+-- name: UpsertPipelineStatsFromEvent :exec
+INSERT INTO service_pipeline_stats_daily (
+  organization_id,
+  service_name,
+  day_utc,
+  pipeline_started_count,
+  pipeline_succeeded_count,
+  pipeline_failed_count,
+  total_duration_seconds
+)
+SELECT
+  es.organization_id,
+  resolve_service_name(es.organization_id, COALESCE(
+    NULLIF(es.raw_event_json #>> '{subject,content,service}', ''),
+    NULLIF(artifact_service_name(es.raw_event_json #>> '{subject,content,artifactId}'), ''),
+    split_part(service_name_from_subject(es.subject_id), '/', 1)
+  )),
+  utc_day(es.event_ts_ms),
+  CASE WHEN es.event_type LIKE 'dev.cdevents.pipeline.run.started.%' THEN 1 ELSE 0 END,
+  CASE WHEN es.event_type LIKE 'dev.cdevents.pipeline.run.succeeded.%' THEN 1 ELSE 0 END,
+  CASE WHEN es.event_type LIKE 'dev.cdevents.pipeline.run.failed.%' THEN 1 ELSE 0 END,
+  CASE
+    WHEN es.event_type LIKE 'dev.cdevents.pipeline.run.succeeded.%' OR es.event_type LIKE 'dev.cdevents.pipeline.run.failed.%'
+    THEN COALESCE((
+      SELECT (es.event_ts_ms - started.event_ts_ms) / 1000
+      FROM event_store started
+      WHERE started.organization_id = es.organization_id
+        AND started.subject_id = es.subject_id
+        AND started.event_type LIKE 'dev.cdevents.pipeline.run.started.%'
+        AND started.event_ts_ms <= es.event_ts_ms
+      ORDER BY started.event_ts_ms DESC, started.seq DESC
+      LIMIT 1
+    ), 0)
+    ELSE 0
+  END
+FROM event_store es
+WHERE es.organization_id = sqlc.arg('organization_id')
+  AND es.seq = sqlc.arg('seq')
+  AND es.subject_type = 'pipeline'
+ON CONFLICT (organization_id, service_name, day_utc) DO UPDATE SET
+  pipeline_started_count = service_pipeline_stats_daily.pipeline_started_count + excluded.pipeline_started_count,
+  pipeline_succeeded_count = service_pipeline_stats_daily.pipeline_succeeded_count + excluded.pipeline_succeeded_count,
+  pipeline_failed_count = service_pipeline_stats_daily.pipeline_failed_count + excluded.pipeline_failed_count,
+  total_duration_seconds = service_pipeline_stats_daily.total_duration_seconds + excluded.total_duration_seconds,
+  updated_at = now();
+
+-- name: GetPipelineStats30d :one
+SELECT
+  COALESCE(SUM(pipeline_started_count), 0)::bigint AS pipeline_started_count,
+  COALESCE(SUM(pipeline_succeeded_count), 0)::bigint AS pipeline_succeeded_count,
+  COALESCE(SUM(pipeline_failed_count), 0)::bigint AS pipeline_failed_count,
+  COALESCE(SUM(total_duration_seconds), 0)::bigint AS total_duration_seconds,
+  COALESCE(SUM(total_duration_seconds)::double precision / NULLIF(SUM(pipeline_started_count), 0), 0)::double precision AS avg_duration_seconds
+FROM service_pipeline_stats_daily
+WHERE organization_id = sqlc.arg('organization_id')
+  AND service_name = sqlc.arg('service_name')
+  AND day_utc >= utc_days_ago(30);
+
+-- Deployment Duration Metrics
+-- name: InsertDeploymentDuration :exec
+INSERT INTO service_deployment_durations (
+  organization_id,
+  service_name,
+  environment,
+  event_seq,
+  event_ts_ms,
+  duration_seconds,
+  artifact_id
+)
+SELECT
+  es.organization_id,
+  resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)),
+  resolve_environment(es.organization_id, event_environment(es.raw_event_json)),
+  es.seq,
+  es.event_ts_ms,
+  CASE
+    WHEN jsonb_typeof(es.raw_event_json #> '{subject,content,durationSeconds}') = 'number'
+    THEN (es.raw_event_json #>> '{subject,content,durationSeconds}')::numeric::bigint
+    ELSE 0
+  END,
+  event_artifact_id(es.raw_event_json)
+FROM event_store es
+WHERE es.organization_id = sqlc.arg('organization_id')
+  AND es.seq = sqlc.arg('seq')
+  AND es.subject_type = 'service'
+  AND es.event_type LIKE 'dev.cdevents.service.deployed.%'
+ON CONFLICT (organization_id, service_name, environment, event_seq) DO NOTHING;
+
+-- name: GetDeploymentDurationStats :one
+SELECT
+  COUNT(*) AS sample_count,
+  COALESCE(AVG(duration_seconds), 0)::double precision AS avg_duration_seconds,
+  COALESCE(MIN(duration_seconds), 0)::bigint AS min_duration_seconds,
+  COALESCE(MAX(duration_seconds), 0)::bigint AS max_duration_seconds,
+  COALESCE(
+    (SELECT d2.duration_seconds FROM service_deployment_durations d2
+     WHERE d2.organization_id = sqlc.arg('organization_id')
+       AND d2.service_name = sqlc.arg('service_name')
+       AND d2.environment = sqlc.arg('environment')::text
+     ORDER BY d2.event_ts_ms DESC LIMIT 1),
+    0
+  )::bigint AS last_duration_seconds
+FROM service_deployment_durations
+WHERE service_deployment_durations.organization_id = sqlc.arg('organization_id')
+  AND service_deployment_durations.service_name = sqlc.arg('service_name')
+  AND service_deployment_durations.event_ts_ms >= sqlc.arg('since_ms')::bigint;
+
+-- Environment Drift Detection
+-- name: InsertEnvironmentDrift :exec
+INSERT INTO service_environment_drift (
+  organization_id,
+  service_name,
+  environment_from,
+  environment_to,
+  artifact_id_from,
+  artifact_id_to,
+  drift_detected_at
+)
+SELECT
+  ses1.organization_id,
+  ses1.service_name,
+  ses1.environment,
+  ses2.environment,
+  ses1.latest_artifact_id,
+  ses2.latest_artifact_id,
+  sqlc.arg('detected_at')::bigint
+FROM service_env_state ses1
+JOIN service_env_state ses2 ON
+  ses1.organization_id = ses2.organization_id
+  AND ses1.service_name = ses2.service_name
+  AND ses1.environment <> ses2.environment
+  AND ses1.latest_artifact_id <> ses2.latest_artifact_id
+WHERE ses1.organization_id = sqlc.arg('organization_id')
+  AND ses1.service_name = sqlc.arg('service_name')
+  AND ses1.latest_artifact_id <> ''
+  AND ses2.latest_artifact_id <> ''
+ON CONFLICT (organization_id, service_name, environment_from, environment_to, drift_detected_at) DO NOTHING;
+
+-- name: GetEnvironmentDriftCount :one
+SELECT COUNT(*) AS drift_count
+FROM service_environment_drift
+WHERE organization_id = sqlc.arg('organization_id')
+  AND service_name = sqlc.arg('service_name')
+  AND drift_detected_at >= sqlc.arg('since_ms');
+
+-- name: ListEnvironmentDrifts :many
+SELECT
+  environment_from,
+  environment_to,
+  artifact_id_from,
+  artifact_id_to,
+  drift_detected_at
+FROM service_environment_drift
+WHERE organization_id = sqlc.arg('organization_id')
+  AND service_name = sqlc.arg('service_name')
+ORDER BY drift_detected_at DESC
+LIMIT sqlc.arg('limit')::bigint;
+
+-- Re-deployment Rate Metrics
+-- name: UpsertRedeploymentStats :exec
+INSERT INTO service_redeployment_stats (
+  organization_id,
+  service_name,
+  day_utc,
+  redeploy_count
+)
+VALUES (
+  sqlc.arg('organization_id'),
+  sqlc.arg('service_name'),
+  sqlc.arg('day_utc'),
+  CASE WHEN sqlc.arg('same_artifact')::text = '1' THEN 1 ELSE 0 END
+)
+ON CONFLICT (organization_id, service_name, day_utc) DO UPDATE SET
+  redeploy_count = service_redeployment_stats.redeploy_count + excluded.redeploy_count,
+  updated_at = now();
+
+-- name: GetRedeploymentCheckFromEventSeq :one
+SELECT
+  resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)) AS service_name,
+  utc_day(es.event_ts_ms) AS day_utc,
+  (CASE
+    WHEN event_artifact_id(es.raw_event_json) <> ''
+     AND event_artifact_id(es.raw_event_json) = COALESCE(ses.latest_artifact_id, '')
+    THEN '1'
+    ELSE '0'
+  END)::text AS same_artifact
+FROM event_store es
+LEFT JOIN service_env_state ses ON
+  ses.organization_id = es.organization_id
+  AND ses.service_name = resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id))
+  AND ses.environment = resolve_environment(es.organization_id, event_environment(es.raw_event_json))
+WHERE es.organization_id = sqlc.arg('organization_id')
+  AND es.seq = sqlc.arg('seq')
+  AND es.subject_type = 'service'
+  AND es.event_type LIKE 'dev.cdevents.service.deployed.%';
+
+-- name: GetRedeploymentRate30d :one
+SELECT
+  COALESCE((
+    SELECT SUM(r.redeploy_count)
+    FROM service_redeployment_stats r
+    WHERE r.organization_id = sqlc.arg('organization_id')
+      AND r.service_name = sqlc.arg('service_name')::text
+      AND r.day_utc >= utc_days_ago(30)
+  ), 0)::bigint AS redeploy_count,
+  (
+    SELECT COUNT(*)
+    FROM service_delivery_stats_daily d
+    WHERE d.organization_id = sqlc.arg('organization_id')
+      AND d.service_name = sqlc.arg('service_name')::text
+      AND d.day_utc >= utc_days_ago(30)
+      AND d.deploy_success_count > 0
+  )::bigint AS deploy_days;
+
+-- Throughput Metrics (Changes per week)
+-- name: UpsertThroughputStats :exec
+INSERT INTO service_throughput_stats (
+  organization_id,
+  service_name,
+  week_start,
+  changes_count,
+  deployments_count
+)
+SELECT
+  ev.organization_id,
+  ev.service_name,
+  utc_week_start(ev.event_ts_ms),
+  CASE WHEN ev.subject_type = 'change' THEN 1 ELSE 0 END,
+  CASE WHEN ev.event_type LIKE 'dev.cdevents.service.deployed.%' THEN 1 ELSE 0 END
+FROM (
+  SELECT
+    es.organization_id,
+    es.subject_type,
+    es.event_type,
+    es.event_ts_ms,
+    resolve_service_name(es.organization_id, CASE
+      WHEN es.subject_type = 'service' THEN service_name_from_subject(es.subject_id)
+      ELSE COALESCE(
+        NULLIF(es.raw_event_json #>> '{subject,content,service}', ''),
+        artifact_service_name(es.raw_event_json #>> '{subject,content,artifactId}')
+      )
+    END) AS service_name
+  FROM event_store es
+  WHERE es.organization_id = sqlc.arg('organization_id')
+    AND es.seq = sqlc.arg('seq')
+    AND (
+      es.subject_type = 'change'
+      OR (es.subject_type = 'service' AND es.event_type LIKE 'dev.cdevents.service.deployed.%')
+    )
+) ev
+WHERE ev.service_name <> ''
+ON CONFLICT (organization_id, service_name, week_start) DO UPDATE SET
+  changes_count = service_throughput_stats.changes_count + excluded.changes_count,
+  deployments_count = service_throughput_stats.deployments_count + excluded.deployments_count,
+  updated_at = now();
+
+-- name: GetThroughputStats :one
+SELECT
+  COALESCE(SUM(changes_count), 0)::bigint AS changes_count,
+  COALESCE(SUM(deployments_count), 0)::bigint AS deployments_count,
+  COALESCE(SUM(deployments_count)::double precision / NULLIF(COUNT(*), 0), 0)::double precision AS avg_deployments_per_week
+FROM service_throughput_stats
+WHERE organization_id = sqlc.arg('organization_id')
+  AND service_name = sqlc.arg('service_name')
+  AND week_start >= utc_days_ago(84);
+
+-- name: ListWeeklyThroughput :many
+SELECT
+  week_start,
+  changes_count,
+  deployments_count
+FROM service_throughput_stats
+WHERE organization_id = sqlc.arg('organization_id')
+  AND service_name = sqlc.arg('service_name')
+ORDER BY week_start DESC
+LIMIT sqlc.arg('limit')::bigint;
+
+-- Incident Links (for MTTR calculation)
+-- name: UpsertIncidentLinkFromEventSeq :exec
+INSERT INTO service_incident_links (
+  organization_id,
+  service_name,
+  incident_id,
+  incident_type,
+  environment,
+  linked_at,
+  deployment_event_seq,
+  deployment_ts_ms,
+  resolved_at
+)
+SELECT
+  ev.organization_id,
+  ev.service_name,
+  ev.incident_id,
+  ev.incident_type,
+  ev.environment,
+  ev.event_ts_ms,
+  deployment.event_seq,
+  deployment.event_ts_ms,
+  CASE WHEN ev.incident_type = 'resolved' THEN ev.event_ts_ms END
+FROM (
+  SELECT
+    es.organization_id,
+    es.event_ts_ms,
+    service_name_from_subject(es.subject_id) AS incident_id,
+    CASE
+      WHEN es.event_type LIKE 'dev.cdevents.incident.resolved.%' THEN 'resolved'
+      WHEN es.event_type LIKE 'dev.cdevents.incident.reported.%' THEN 'reported'
+      ELSE 'detected'
+    END AS incident_type,
+    resolve_environment(es.organization_id, COALESCE(es.raw_event_json #>> '{subject,content,environment,id}', '')) AS environment,
+    resolve_service_name(es.organization_id, COALESCE(
+      NULLIF(service_name_from_subject(es.raw_event_json #>> '{subject,content,service,id}'), ''),
+      CASE
+        WHEN jsonb_typeof(es.raw_event_json #> '{subject,content,service}') = 'string'
+        THEN NULLIF(es.raw_event_json #>> '{subject,content,service}', '')
+      END,
+      artifact_service_name(es.raw_event_json #>> '{subject,content,artifactId}')
+    )) AS service_name
+  FROM event_store es
+  WHERE es.organization_id = sqlc.arg('organization_id')
+    AND es.seq = sqlc.arg('seq')
+    AND es.subject_type = 'incident'
+    AND (
+      es.event_type LIKE 'dev.cdevents.incident.detected.%'
+      OR es.event_type LIKE 'dev.cdevents.incident.reported.%'
+      OR es.event_type LIKE 'dev.cdevents.incident.resolved.%'
+    )
+) ev
+LEFT JOIN LATERAL (
+  SELECT d.event_seq, d.event_ts_ms
+  FROM service_deployment_durations d
+  WHERE d.organization_id = ev.organization_id
+    AND d.service_name = ev.service_name
+    AND (ev.environment = '' OR d.environment = ev.environment)
+    AND d.event_ts_ms <= ev.event_ts_ms
+  ORDER BY d.event_ts_ms DESC, d.event_seq DESC
+  LIMIT 1
+) deployment ON TRUE
+WHERE ev.service_name <> ''
+  AND ev.incident_id <> ''
+ON CONFLICT (organization_id, service_name, incident_id) DO UPDATE SET
+  incident_type = CASE
+    WHEN excluded.resolved_at IS NULL
+     AND (service_incident_links.incident_type = 'resolved' OR excluded.linked_at < service_incident_links.linked_at)
+    THEN excluded.incident_type
+    ELSE service_incident_links.incident_type
+  END,
+  environment = CASE
+    WHEN service_incident_links.environment = '' THEN excluded.environment
+    ELSE service_incident_links.environment
+  END,
+  deployment_event_seq = CASE
+    WHEN excluded.resolved_at IS NULL
+     AND (service_incident_links.incident_type = 'resolved' OR excluded.linked_at < service_incident_links.linked_at)
+    THEN excluded.deployment_event_seq
+    ELSE service_incident_links.deployment_event_seq
+  END,
+  deployment_ts_ms = CASE
+    WHEN excluded.resolved_at IS NULL
+     AND (service_incident_links.incident_type = 'resolved' OR excluded.linked_at < service_incident_links.linked_at)
+    THEN excluded.deployment_ts_ms
+    ELSE service_incident_links.deployment_ts_ms
+  END,
+  linked_at = CASE
+    WHEN excluded.resolved_at IS NULL
+     AND (service_incident_links.incident_type = 'resolved' OR excluded.linked_at < service_incident_links.linked_at)
+    THEN excluded.linked_at
+    ELSE service_incident_links.linked_at
+  END,
+  resolved_at = COALESCE(excluded.resolved_at, service_incident_links.resolved_at);
+
+-- name: GetMTTR :one
+SELECT
+  COUNT(*) AS incident_count,
+  (COALESCE(AVG(il.resolved_at - il.linked_at), 0) / 1000)::double precision AS mttr_seconds,
+  (COALESCE(AVG(il.linked_at - il.deployment_ts_ms), 0) / 1000)::double precision AS mttd_seconds,
+  (COALESCE(MAX(il.resolved_at - il.linked_at), 0) / 1000)::double precision AS mtte_seconds,
+  (
+    SELECT COUNT(*)
+    FROM service_incident_links open_links
+    WHERE open_links.organization_id = sqlc.arg('organization_id')
+      AND (sqlc.arg('service_name')::text = '' OR open_links.service_name = sqlc.arg('service_name')::text)
+      AND open_links.resolved_at IS NULL
+  )::bigint AS open_count
+FROM service_incident_links il
+WHERE il.organization_id = sqlc.arg('organization_id')
+  AND (sqlc.arg('service_name')::text = '' OR il.service_name = sqlc.arg('service_name')::text)
+  AND il.incident_type <> 'resolved'
+  AND il.linked_at >= sqlc.arg('since_ms')::bigint;
+
+-- name: ListIncidentLinks :many
+SELECT
+  incident_id,
+  incident_type,
+  environment,
+  linked_at,
+  deployment_event_seq,
+  resolved_at
+FROM service_incident_links
+WHERE organization_id = sqlc.arg('organization_id')
+  AND service_name = sqlc.arg('service_name')
+ORDER BY linked_at DESC
+LIMIT sqlc.arg('limit')::bigint;
+
+-- Artifact Age (Time since last deployment per environment)
+-- name: GetArtifactAgeByEnvironment :many
+SELECT
+  environment,
+  latest_artifact_id,
+  (((EXTRACT(EPOCH FROM now()) * 1000)::bigint - latest_event_ts_ms) / 1000)::bigint AS age_seconds,
+  latest_event_ts_ms
+FROM service_env_state
+WHERE organization_id = sqlc.arg('organization_id')
+  AND service_name = sqlc.arg('service_name')
+  AND latest_artifact_id <> '';
+
+-- Comprehensive Delivery Metrics Summary
+-- name: GetComprehensiveDeliveryMetrics :one
+WITH deploy_events AS (
+  SELECT
+    es.event_ts_ms AS deploy_ts_ms,
+    resolve_service_name(es.organization_id, service_name_from_subject(es.subject_id)) AS service_name
+  FROM event_store es
+  WHERE es.organization_id = sqlc.arg('organization_id')
+    AND es.subject_type = 'service'
+    AND es.event_type LIKE 'dev.cdevents.service.deployed.%'
+    AND es.event_ts_ms >= sqlc.arg('since_ms')::bigint
+), change_events AS (
+  SELECT
+    es.event_ts_ms AS change_ts_ms,
+    resolve_service_name(es.organization_id, artifact_service_name(es.raw_event_json #>> '{subject,content,artifactId}')) AS service_name
+  FROM event_store es
+  WHERE es.organization_id = sqlc.arg('organization_id')
+    AND es.subject_type = 'change'
+    AND es.event_ts_ms >= sqlc.arg('since_ms')::bigint
+), lead_times AS (
+  SELECT
+    (d.deploy_ts_ms - (
+      SELECT MAX(c.change_ts_ms)
+      FROM change_events c
+      WHERE c.service_name = d.service_name
+        AND c.change_ts_ms <= d.deploy_ts_ms
+    )) / 1000 AS lead_seconds
+  FROM deploy_events d
+  WHERE d.service_name <> ''
+), delivery AS (
+  SELECT
+    COALESCE(SUM(deploy_success_count), 0) AS success_count,
+    COALESCE(SUM(deploy_failure_count), 0) AS failure_count,
+    COALESCE(SUM(rollback_count), 0) AS rollback_count,
+    COUNT(DISTINCT day_utc) FILTER (WHERE deploy_success_count > 0) AS active_days
+  FROM service_delivery_stats_daily
+  WHERE organization_id = sqlc.arg('organization_id')
+    AND day_utc >= utc_days_ago(30)
+), pipelines AS (
+  SELECT
+    COALESCE(SUM(pipeline_succeeded_count), 0) AS succeeded_count,
+    COALESCE(SUM(pipeline_failed_count), 0) AS failed_count
+  FROM service_pipeline_stats_daily
+  WHERE organization_id = sqlc.arg('organization_id')
+    AND day_utc >= utc_days_ago(30)
+)
+SELECT
+  (SELECT COALESCE(AVG(lead_seconds), 0) FROM lead_times)::double precision AS lead_time_seconds,
+  delivery.success_count::bigint AS deployment_frequency_30d,
+  COALESCE(
+    (delivery.failure_count + delivery.rollback_count)::double precision / NULLIF(delivery.success_count + delivery.failure_count, 0),
+    0
+  )::double precision AS change_failure_rate,
+  (
+    SELECT COALESCE(AVG(dd.duration_seconds), 0)
+    FROM service_deployment_durations dd
+    WHERE dd.organization_id = sqlc.arg('organization_id')
+      AND dd.event_ts_ms >= sqlc.arg('since_ms')::bigint
+  )::double precision AS avg_deployment_duration_seconds,
+  pipelines.succeeded_count::bigint AS pipeline_success_count_30d,
+  pipelines.failed_count::bigint AS pipeline_failure_count_30d,
+  delivery.active_days::bigint AS active_deploy_days_30d
+FROM delivery, pipelines;
@@ -0,0 +1,237 @@
+package pgdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/fr0stylo/ddash/internal/pgdb/queries"
+	"github.com/fr0stylo/ddash/internal/serviceidentity"
+)
+
+const (
+	// ServiceIdentityKindAlias maps one exact service name to another.
+	ServiceIdentityKindAlias = serviceidentity.KindAlias
+	// ServiceIdentityKindRegex rewrites every service name the pattern
+	// matches; the target may reference capture groups as $1 or ${name}.
+	ServiceIdentityKindRegex = serviceidentity.KindRegex
+)
+
+// ErrInvalidServiceIdentityRule is returned for rules that cannot be applied.
+var ErrInvalidServiceIdentityRule = serviceidentity.ErrInvalidRule
+
+func loadServiceIdentityRules(ctx context.Context, q *queries.Queries, organizationID int64) (serviceidentity.Rules, error) {
+	rows, err := q.ListServiceIdentityRules(ctx, organizationID)
+	if err != nil {
+		return serviceidentity.Rules{}, err
+	}
+	rules := make([]serviceidentity.Rule, 0, len(rows))
+	for _, row := range rows {
+		rules = append(rules, serviceidentity.Rule{Kind: row.Kind, Pattern: row.Pattern, ServiceName: row.ServiceName})
+	}
+	return serviceidentity.Compile(rules)
+}
+
+// CreateServiceIdentityRule adds a mapping rule and rewrites the alias table
+// in the same transaction. It holds the organization's event store lock, so
+// no append materializes aliases from the old rules in between. Projections
+// keep the old names until they are rebuilt.
+func (c *Database) CreateServiceIdentityRule(ctx context.Context, params queries.CreateServiceIdentityRuleParams) (queries.ServiceIdentityRule, error) {
+	params.Pattern = strings.TrimSpace(params.Pattern)
+	params.ServiceName = strings.TrimSpace(params.ServiceName)
+	if err := serviceidentity.Validate(serviceidentity.Rule{Kind: params.Kind, Pattern: params.Pattern, ServiceName: params.ServiceName}); err != nil {
+		return queries.ServiceIdentityRule{}, err
+	}
+
+	var rule queries.ServiceIdentityRule
+	err := c.WithTx(ctx, func(q *queries.Queries) error {
+		if err := q.LockOrganizationEventStore(ctx, params.OrganizationID); err != nil {
+			return err
+		}
+		var err error
+		if rule, err = q.CreateServiceIdentityRule(ctx, params); err != nil {
+			return err
+		}
+		return syncServiceIdentityAliases(ctx, q, params.OrganizationID)
+	})
+	return rule, err
+}
+
+// DeleteServiceIdentityRule removes a mapping rule and rewrites the alias
+// table. Metadata already carried over to a merged service stays there.
+func (c *Database) DeleteServiceIdentityRule(ctx context.Context, params queries.DeleteServiceIdentityRuleParams) (int64, error) {
+	var deleted int64
+	err := c.WithTx(ctx, func(q *queries.Queries) error {
+		if err := q.LockOrganizationEventStore(ctx, params.OrganizationID); err != nil {
+			return err
+		}
+		var err error
+		if deleted, err = q.DeleteServiceIdentityRule(ctx, params); err != nil || deleted == 0 {
+			return err
+		}
+		return syncServiceIdentityAliases(ctx, q, params.OrganizationID)
+	})
+	return deleted, err
+}
+
+// syncServiceIdentityAliases maps every service name the organization has
+// seen through its rules and stores the names that change. Metadata and
+// dependencies of a name that is newly merged into another service move to
+// that service; values already set on the target win.
+func syncServiceIdentityAliases(ctx context.Context, q *queries.Queries, organizationID int64) error {
+	rules, err := loadServiceIdentityRules(ctx, q, organizationID)
+	if err != nil {
+		return err
+	}
+	existing, err := q.ListServiceIdentityAliases(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	previous := make(map[string]string, len(existing))
+	for _, alias := range existing {
+		previous[alias.Alias] = alias.ServiceName
+	}
+	candidates, err := q.ListServiceNameCandidates(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	candidates = append(candidates, rules.Aliases()...)
+
+	if err := q.DeleteServiceIdentityAliases(ctx, organizationID); err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for _, name := range candidates {
+		target := rules.Resolve(name)
+		if target == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if previous[name] != target {
+			if err := moveServiceIdentity(ctx, q, organizationID, name, target); err != nil {
+				return err
+			}
+		}
+		if err := q.UpsertServiceIdentityAlias(ctx, queries.UpsertServiceIdentityAliasParams{
+			OrganizationID: organizationID,
+			Alias:          name,
+			ServiceName:    target,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func moveServiceIdentity(ctx context.Context, q *queries.Queries, organizationID int64, from, to string) error {
+	if err := q.MoveServiceMetadata(ctx, queries.MoveServiceMetadataParams{OrganizationID: organizationID, FromServiceName: from, ToServiceName: to}); err != nil {
+		return err
+	}
+	if err := q.DeleteServiceMetadataByService(ctx, queries.DeleteServiceMetadataByServiceParams{OrganizationID: organizationID, ServiceName: from}); err != nil {
+		return err
+	}
+	if err := q.MoveServiceDependencies(ctx, queries.MoveServiceDependenciesParams{OrganizationID: organizationID, FromServiceName: from, ToServiceName: to}); err != nil {
+		return err
+	}
+	return q.DeleteServiceDependenciesByService(ctx, queries.DeleteServiceDependenciesByServiceParams{OrganizationID: organizationID, ServiceName: from})
+}
+
+// materializeServiceIdentities stores aliases for the service names an event
+// introduces that a regex rule rewrites, so projections of the event and
+// every later read pick up the mapping. Exact aliases are stored when their
+// rule is created. Rules are read in the append transaction, under the
+// event store lock, because other processes may change them.
+func materializeServiceIdentities(ctx context.Context, q *queries.Queries, params queries.AppendEventStoreParams) error {
+	rules, err := loadServiceIdentityRules(ctx, q, params.OrganizationID)
+	if err != nil || !rules.HasRegexes() {
+		return err
+	}
+	for _, name := range eventServiceNames(params) {
+		if rules.IsAlias(name) {
+			continue
+		}
+		target := rules.Resolve(name)
+		if target == "" {
+			continue
+		}
+		if err := q.UpsertServiceIdentityAlias(ctx, queries.UpsertServiceIdentityAliasParams{
+			OrganizationID: params.OrganizationID,
+			Alias:          name,
+			ServiceName:    target,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveServiceName maps a derived service name through the organization's
+// aliases.
+func resolveServiceName(ctx context.Context, q *queries.Queries, organizationID int64, name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	resolved, err := q.GetServiceIdentityAlias(ctx, queries.GetServiceIdentityAliasParams{OrganizationID: organizationID, Alias: name})
+	if errors.Is(err, sql.ErrNoRows) {
+		return name, nil
+	}
+	return resolved, err
+}
+
+// eventServiceNames returns the service names the projections derive from an
+// event, mirroring the expressions in the projection queries.
+func eventServiceNames(params queries.AppendEventStoreParams) []string {
+	var payload struct {
+		Subject struct {
+			Content struct {
+				Service    json.RawMessage `json:"service"`
+				ArtifactID string          `json:"artifactId"`
+			} `json:"content"`
+		} `json:"subject"`
+	}
+	_ = json.Unmarshal(params.RawEventJson, &payload)
+	content := payload.Subject.Content
+
+	names := []string{}
+	add := func(name string) {
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	_, afterType, hasType := strings.Cut(params.SubjectID, "/")
+	switch params.SubjectType {
+	case "service":
+		if hasType {
+			add(afterType)
+		} else {
+			add(params.SubjectID)
+		}
+	case "pipeline":
+		first, _, _ := strings.Cut(afterType, "/")
+		add(first)
+	case "incident":
+		var service struct {
+			ID string `json:"id"`
+		}
+		if json.Unmarshal(content.Service, &service) == nil {
+			if _, name, ok := strings.Cut(service.ID, "/"); ok {
+				add(name)
+			} else {
+				add(service.ID)
+			}
+		}
+	}
+	var service string
+	if json.Unmarshal(content.Service, &service) == nil {
+		add(service)
+	}
+	if rest, ok := strings.CutPrefix(content.ArtifactID, "pkg:generic/"); ok {
+		if name, _, found := strings.Cut(rest, "@"); found {
+			add(name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,256 @@
+package pgdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fr0stylo/ddash/internal/pgdb/queries"
+)
+
+// UpdateOrganizationName updates organization name.
+func (c *Database) UpdateOrganizationName(ctx context.Context, organizationID int64, name string) error {
+	return c.Queries.UpdateOrganizationName(ctx, queries.UpdateOrganizationNameParams{Name: name, ID: organizationID})
+}
+
+// UpdateOrganizationEnabled updates organization enabled state.
+func (c *Database) UpdateOrganizationEnabled(ctx context.Context, organizationID int64, enabled bool) error {
+	return c.Queries.UpdateOrganizationEnabled(ctx, queries.UpdateOrganizationEnabledParams{Enabled: enabled, ID: organizationID})
+}
+
+// GetUserByEmailOrNickname fetches a user by email/nickname.
+func (c *Database) GetUserByEmailOrNickname(ctx context.Context, email, nickname string) (queries.User, error) {
+	return c.Queries.GetUserByEmailOrNickname(ctx, queries.GetUserByEmailOrNicknameParams{Email: email, Nickname: nickname})
+}
+
+// GetOrganizationMemberRole returns one user's role in org.
+func (c *Database) GetOrganizationMemberRole(ctx context.Context, organizationID, userID int64) (string, error) {
+	return c.Queries.GetOrganizationMemberRole(ctx, queries.GetOrganizationMemberRoleParams{OrganizationID: organizationID, UserID: userID})
+}
+
+// UpsertOrganizationMember upserts org membership and role.
+func (c *Database) UpsertOrganizationMember(ctx context.Context, organizationID, userID int64, role string) error {
+	return c.Queries.UpsertOrganizationMember(ctx, queries.UpsertOrganizationMemberParams{OrganizationID: organizationID, UserID: userID, Role: role})
+}
+
+// DeleteOrganizationMember removes user from org membership.
+func (c *Database) DeleteOrganizationMember(ctx context.Context, organizationID, userID int64) error {
+	return c.Queries.DeleteOrganizationMember(ctx, queries.DeleteOrganizationMemberParams{OrganizationID: organizationID, UserID: userID})
+}
+
+// UpsertOrganizationFeature upserts one feature flag for an org.
+func (c *Database) UpsertOrganizationFeature(ctx context.Context, organizationID int64, featureKey string, isEnabled bool) error {
+	return c.Queries.UpsertOrganizationFeature(ctx, queries.UpsertOrganizationFeatureParams{
+		OrganizationID: organizationID,
+		FeatureKey:     featureKey,
+		IsEnabled:      isEnabled,
+	})
+}
+
+// UpsertOrganizationPreference upserts one preference value for an org.
+func (c *Database) UpsertOrganizationPreference(ctx context.Context, organizationID int64, preferenceKey, preferenceValue string) error {
+	return c.Queries.UpsertOrganizationPreference(ctx, queries.UpsertOrganizationPreferenceParams{
+		OrganizationID:  organizationID,
+		PreferenceKey:   preferenceKey,
+		PreferenceValue: preferenceValue,
+	})
+}
+
+// UpdateOrganizationSecrets updates auth token and webhook secret.
+func (c *Database) UpdateOrganizationSecrets(ctx context.Context, authToken, webhookSecret string, enabled bool, organizationID int64) error {
+	return c.Queries.UpdateOrganizationSecrets(ctx, queries.UpdateOrganizationSecretsParams{
+		AuthToken:     authToken,
+		WebhookSecret: webhookSecret,
+		Enabled:       enabled,
+		ID:            organizationID,
+	})
+}
+
+// ListDistinctServiceEnvironmentsFromEvents returns discovered environments from service events.
+func (c *Database) ListDistinctServiceEnvironmentsFromEvents(ctx context.Context, organizationID int64) ([]string, error) {
+	rows, err := c.Queries.ListDistinctServiceEnvironmentsFromEvents(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if value := strings.TrimSpace(row); value != "" {
+			result = append(result, value)
+		}
+	}
+	return result, nil
+}
+
+// AppendIngestDeadLetter stores one rejected webhook delivery and prunes the
+// organization's dead letters received before receivedBefore or beyond keepCount.
+func (c *Database) AppendIngestDeadLetter(ctx context.Context, params queries.InsertIngestDeadLetterParams, receivedBefore, keepCount int64) error {
+	return c.WithTx(ctx, func(q *queries.Queries) error {
+		if err := q.InsertIngestDeadLetter(ctx, params); err != nil {
+			return err
+		}
+		return q.PruneIngestDeadLetters(ctx, queries.PruneIngestDeadLettersParams{
+			OrganizationID: params.OrganizationID,
+			ReceivedBefore: receivedBefore,
+			KeepCount:      keepCount,
+		})
+	})
+}
+
+// RememberIngestSignature records a webhook signature and reports whether it
+// was unseen. Signatures past their expiry are pruned in the same transaction.
+func (c *Database) RememberIngestSignature(ctx context.Context, params queries.InsertIngestSeenSignatureParams) (bool, error) {
+	inserted := false
+	err := c.WithTx(ctx, func(q *queries.Queries) error {
+		if err := q.PruneIngestSeenSignatures(ctx, params.SeenAt); err != nil {
+			return err
+		}
+		rows, err := q.InsertIngestSeenSignature(ctx, params)
+		if err != nil {
+			return err
+		}
+		inserted = rows > 0
+		return nil
+	})
+	return inserted, err
+}
+
+// GetOrganizationByIngestCredentialToken resolves an org from one of its
+// active ingest credentials.
+func (c *Database) GetOrganizationByIngestCredentialToken(ctx context.Context, authToken string, now int64) (queries.Organization, error) {
+	return c.Queries.GetOrganizationByIngestCredentialToken(ctx, queries.GetOrganizationByIngestCredentialTokenParams{
+		AuthToken: authToken,
+		Now:       now,
+	})
+}
+
+// CreateOrganizationIngestCredential adds one ingest credential for an org.
+func (c *Database) CreateOrganizationIngestCredential(ctx context.Context, params queries.InsertOrganizationIngestCredentialParams) (queries.OrganizationIngestCredential, error) {
+	return c.Queries.InsertOrganizationIngestCredential(ctx, params)
+}
+
+// TouchOrganizationIngestCredential records credential use, at most once per throttle window.
+func (c *Database) TouchOrganizationIngestCredential(ctx context.Context, organizationID, credentialID, usedAt, throttle int64) error {
+	return c.Queries.TouchOrganizationIngestCredential(ctx, queries.TouchOrganizationIngestCredentialParams{
+		UsedAt:         sql.NullInt64{Int64: usedAt, Valid: true},
+		OrganizationID: organizationID,
+		ID:             credentialID,
+		StaleBefore:    usedAt - throttle,
+	})
+}
+
+// AppendedEvent describes one event newly committed to event_store.
+type AppendedEvent struct {
+	OrganizationID int64
+	EventID        string
+	Seq            int64
+	SubjectType    string
+	ServiceName    string
+}
+
+// AppendEventStore stores a CDEvent in the append-only event store.
+func (c *Database) AppendEventStore(ctx context.Context, params queries.AppendEventStoreParams) error {
+	return c.WithTx(ctx, func(q *queries.Queries) error {
+		if err := q.LockOrganizationEventStore(ctx, params.OrganizationID); err != nil {
+			return err
+		}
+		_, _, err := appendEventWithProjections(ctx, q, params)
+		return err
+	})
+}
+
+// AppendEventStoreBatch stores multiple CDEvents in one transaction and
+// returns the events that were not already present. Accepted and duplicate
+// counts and per-source activity are rolled up in the same transaction.
+//
+// Appends take a per-organization advisory lock so sequence order matches
+// commit order and projections see events in the order they were stored.
+func (c *Database) AppendEventStoreBatch(ctx context.Context, params []queries.AppendEventStoreParams) ([]AppendedEvent, error) {
+	if len(params) == 0 {
+		return nil, nil
+	}
+	appended := make([]AppendedEvent, 0, len(params))
+	activity := newIngestActivity(time.Now().UTC())
+	err := c.WithTx(ctx, func(q *queries.Queries) error {
+		if err := lockOrganizations(ctx, q, params); err != nil {
+			return err
+		}
+		for _, item := range params {
+			event, inserted, err := appendEventWithProjections(ctx, q, item)
+			if err != nil {
+				return err
+			}
+			activity.add(item, inserted)
+			if inserted {
+				appended = append(appended, event)
+			}
+		}
+		return activity.write(ctx, q)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return appended, nil
+}
+
+// lockOrganizations takes the event store locks in ascending organization
+// order so concurrent batches cannot deadlock.
+func lockOrganizations(ctx context.Context, q *queries.Queries, params []queries.AppendEventStoreParams) error {
+	organizations := make([]int64, 0, 1)
+	for _, item := range params {
+		if !slices.Contains(organizations, item.OrganizationID) {
+			organizations = append(organizations, item.OrganizationID)
+		}
+	}
+	slices.Sort(organizations)
+	for _, organizationID := range organizations {
+		if err := q.LockOrganizationEventStore(ctx, organizationID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendEventWithProjections(ctx context.Context, q *queries.Queries, params queries.AppendEventStoreParams) (AppendedEvent, bool, error) {
+	seq, err := q.AppendEventStore(ctx, params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return AppendedEvent{}, false, nil
+		}
+		return AppendedEvent{}, false, err
+	}
+	if err := applyEnvironmentEvent(ctx, q, params); err != nil {
+		return AppendedEvent{}, false, err
+	}
+	if err := materializeServiceIdentities(ctx, q, params); err != nil {
+		return AppendedEvent{}, false, err
+	}
+	if err := applyProjections(ctx, q, projectedEventFromParams(params, seq)); err != nil {
+		return AppendedEvent{}, false, err
+	}
+	appended := AppendedEvent{
+		OrganizationID: params.OrganizationID,
+		EventID:        params.EventID,
+		Seq:            seq,
+		SubjectType:    params.SubjectType,
+	}
+	if strings.EqualFold(strings.TrimSpace(params.SubjectType), "service") {
+		if appended.ServiceName, err = resolveServiceName(ctx, q, params.OrganizationID, serviceNameFromSubjectID(params.SubjectID)); err != nil {
+			return AppendedEvent{}, false, err
+		}
+	}
+	return appended, true, nil
+}
+
+func serviceNameFromSubjectID(subjectID string) string {
+	subjectID = strings.TrimSpace(subjectID)
+	if subjectID == "" {
+		return ""
+	}
+	if idx := strings.LastIndex(subjectID, "/"); idx >= 0 && idx+1 < len(subjectID) {
+		return strings.TrimSpace(subjectID[idx+1:])
+	}
+	return subjectID
+}
@@ -0,0 +1,122 @@
+package serviceidentity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// KindAlias maps one exact service name onto another.
+	KindAlias = "alias"
+	// KindRegex rewrites every service name the pattern matches; the target
+	// may reference capture groups as $1 or ${name}.
+	KindRegex = "regex"
+)
+
+// ErrInvalidRule is returned for rules that cannot be applied.
+var ErrInvalidRule = errors.New("invalid service identity rule")
+
+// Rule maps the service names found in events onto a dashboard service.
+type Rule struct {
+	Kind        string
+	Pattern     string
+	ServiceName string
+}
+
+// Validate reports why a rule cannot be stored, or nil. Pattern and service
+// name are expected to be trimmed.
+func Validate(rule Rule) error {
+	if rule.Pattern == "" || rule.ServiceName == "" {
+		return fmt.Errorf("%w: pattern and service name are required", ErrInvalidRule)
+	}
+	switch rule.Kind {
+	case KindAlias:
+		if rule.Pattern == rule.ServiceName {
+			return fmt.Errorf("%w: %q cannot alias itself", ErrInvalidRule, rule.Pattern)
+		}
+	case KindRegex:
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidRule, rule.Kind)
+	}
+	return nil
+}
+
+// Rules are an organization's compiled mapping rules. Exact aliases win over
+// regex rules, which are tried in the order they were added.
+type Rules struct {
+	aliases map[string]string
+	regexes []regexRule
+}
+
+type regexRule struct {
+	pattern *regexp.Regexp
+	target  string
+}
+
+// Compile prepares rules for resolving names.
+func Compile(rules []Rule) (Rules, error) {
+	compiled := Rules{aliases: map[string]string{}}
+	for _, rule := range rules {
+		switch rule.Kind {
+		case KindAlias:
+			if _, ok := compiled.aliases[rule.Pattern]; !ok {
+				compiled.aliases[rule.Pattern] = rule.ServiceName
+			}
+		case KindRegex:
+			pattern, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return Rules{}, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+			}
+			compiled.regexes = append(compiled.regexes, regexRule{pattern: pattern, target: rule.ServiceName})
+		default:
+			return Rules{}, fmt.Errorf("%w: unknown kind %q", ErrInvalidRule, rule.Kind)
+		}
+	}
+	return compiled, nil
+}
+
+// Resolve returns the service name a derived name maps to, or "" when no
+// rule changes it. Targets are not resolved again, so rules never chain.
+func (r Rules) Resolve(name string) string {
+	if name == "" {
+		return ""
+	}
+	target, ok := r.aliases[name]
+	if !ok {
+		for _, rule := range r.regexes {
+			if rule.pattern.MatchString(name) {
+				target, ok = strings.TrimSpace(rule.pattern.ReplaceAllString(name, rule.target)), true
+				break
+			}
+		}
+	}
+	if !ok || target == "" || target == name {
+		return ""
+	}
+	return target
+}
+
+// Aliases returns the names exact aliases map.
+func (r Rules) Aliases() []string {
+	names := make([]string, 0, len(r.aliases))
+	for name := range r.aliases {
+		names = append(names, name)
+	}
+	return names
+}
+
+// IsAlias reports whether an exact alias maps the name.
+func (r Rules) IsAlias(name string) bool {
+	_, ok := r.aliases[name]
+	return ok
+}
+
+// HasRegexes reports whether any regex rule is present.
+func (r Rules) HasRegexes() bool {
+	return len(r.regexes) > 0
+}
@@ -0,0 +1,53 @@
+package serviceidentity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateRejectsRulesThatCannotApply(t *testing.T) {
+	t.Parallel()
+
+	invalid := []Rule{
+		{Kind: KindAlias, Pattern: "payments", ServiceName: "payments"},
+		{Kind: KindAlias, Pattern: "", ServiceName: "payments"},
+		{Kind: KindRegex, Pattern: "(", ServiceName: "payments"},
+		{Kind: "glob", Pattern: "payments-*", ServiceName: "payments"},
+	}
+	for _, rule := range invalid {
+		if err := Validate(rule); !errors.Is(err, ErrInvalidRule) {
+			t.Fatalf("expected %+v to be invalid, got %v", rule, err)
+		}
+	}
+	if err := Validate(Rule{Kind: KindRegex, Pattern: `^(.+)-canary$`, ServiceName: "$1"}); err != nil {
+		t.Fatalf("expected regex rule to be valid: %v", err)
+	}
+}
+
+func TestRulesResolveAliasesBeforeRegexes(t *testing.T) {
+	t.Parallel()
+
+	rules, err := Compile([]Rule{
+		{Kind: KindRegex, Pattern: `^(.+)-canary$`, ServiceName: "$1"},
+		{Kind: KindAlias, Pattern: "billing-canary", ServiceName: "billing"},
+		{Kind: KindAlias, Pattern: "payments-api", ServiceName: "payments"},
+	})
+	if err != nil {
+		t.Fatalf("compile rules: %v", err)
+	}
+	cases := map[string]string{
+		"payments-api":   "payments",
+		"ledger-canary":  "ledger",
+		"billing-canary": "billing",
+		"payments":       "",
+		"":               "",
+	}
+	for name, want := range cases {
+		if got := rules.Resolve(name); got != want {
+			t.Fatalf("Resolve(%q) = %q, want %q", name, got, want)
+		}
+	}
+	if !rules.IsAlias("payments-api") || rules.IsAlias("ledger-canary") || !rules.HasRegexes() {
+		t.Fatalf("unexpected rule lookups: %+v", rules)
+	}
+}
@@ -5,4 +5,9 @@ import base "github.com/fr0stylo/ddash/pkg/eventpublisher"
 type Client = base.Client
 type Event = base.Event
 
+const (
+	SignatureSchemeBody        = base.SignatureSchemeBody
+	SignatureSchemeTimestamped = base.SignatureSchemeTimestamped
+)
+
 var BuildEventBody = base.BuildEventBody
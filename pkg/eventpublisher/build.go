@@ -149,6 +149,7 @@ func BuildEventBody(event Event) ([]byte, string, error) {
 			body, err := buildGenericEventBody(Event{
 				Type:        resolved,
 				Source:      source,
+				Service:     service,
 				Environment: environment,
 				Artifact:    artifact,
 				SubjectID:   subjectID,
@@ -190,6 +191,10 @@ func buildGenericEventBody(event Event) ([]byte, error) {
 			},
 		},
 	}
+	if service := strings.TrimSpace(event.Service); service != "" && strings.HasPrefix(event.Type, "dev.cdevents.incident.") {
+		content := payload["subject"].(map[string]any)["content"].(map[string]any)
+		content["service"] = map[string]any{"id": "service/" + service}
+	}
 	if chainID := strings.TrimSpace(event.ChainID); chainID != "" {
 		payload["context"].(map[string]any)["chainId"] = chainID
 	}
@@ -84,6 +84,32 @@ func TestBuildEventBodySupportsAcceptedCustomPrefixes(t *testing.T) {
 	}
 }
 
+func TestBuildEventBodyIncludesIncidentService(t *testing.T) {
+	body, _, err := BuildEventBody(Event{
+		Type:        "dev.cdevents.incident.detected.0.1.0",
+		Source:      "monitoring/test",
+		Service:     "payments",
+		Environment: "production",
+		SubjectID:   "INC-42",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("invalid json body: %v", err)
+	}
+	subject := payload["subject"].(map[string]any)
+	if subject["id"] != "incident/INC-42" {
+		t.Fatalf("unexpected subject id: %v", subject["id"])
+	}
+	content := subject["content"].(map[string]any)
+	service, ok := content["service"].(map[string]any)
+	if !ok || service["id"] != "service/payments" {
+		t.Fatalf("expected service reference in incident content, got %v", content["service"])
+	}
+}
+
 func TestClientPublishSendsSignedRequest(t *testing.T) {
 	var gotAuth string
 	var gotSignature string
@@ -132,3 +158,54 @@ func TestClientPublishSendsSignedRequest(t *testing.T) {
 		t.Fatalf("unexpected content type: %s", gotContentType)
 	}
 }
+
+func TestClientPublishSignsTimestampedRequest(t *testing.T) {
+	var gotTimestamp, gotNonce, gotSignature string
+	var gotBody []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTimestamp = r.Header.Get("X-Webhook-Timestamp")
+		gotNonce = r.Header.Get("X-Webhook-Nonce")
+		gotSignature = r.Header.Get("X-Webhook-Signature")
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := Client{
+		Endpoint:        server.URL,
+		Token:           "token-123",
+		Secret:          "secret-123",
+		SignatureScheme: SignatureSchemeTimestamped,
+	}
+	if _, err := client.Publish(context.Background(), Event{
+		Type:        "service.deployed",
+		Source:      "ci/test",
+		Service:     "orders-api",
+		Environment: "production",
+		Artifact:    "pkg:generic/orders-api@abc123",
+	}); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+	if gotTimestamp == "" || gotNonce == "" {
+		t.Fatalf("expected timestamp and nonce headers, got %q %q", gotTimestamp, gotNonce)
+	}
+	if want := signTimestamped(gotTimestamp, gotNonce, gotBody, "secret-123"); gotSignature != want {
+		t.Fatalf("unexpected signature: got=%s want=%s", gotSignature, want)
+	}
+	if gotSignature == sign(gotBody, "secret-123") {
+		t.Fatalf("expected timestamped signature to differ from body signature")
+	}
+}
+
+func TestClientPublishRejectsUnknownSignatureScheme(t *testing.T) {
+	client := Client{Endpoint: "http://127.0.0.1:0", Token: "t", Secret: "s", SignatureScheme: "v9"}
+	_, err := client.Publish(context.Background(), Event{
+		Type:        "service.deployed",
+		Service:     "orders-api",
+		Environment: "production",
+	})
+	if err == nil || !strings.Contains(err.Error(), "unsupported signature scheme") {
+		t.Fatalf("expected unsupported scheme error, got %v", err)
+	}
+}
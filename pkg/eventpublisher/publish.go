@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -47,8 +49,22 @@ func (c Client) publishBody(ctx context.Context, body []byte) error {
 		return fmt.Errorf("build request: %w", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("X-Webhook-Signature", sign(body, secret))
 	req.Header.Set("Content-Type", "application/json")
+	switch strings.ToLower(strings.TrimSpace(c.SignatureScheme)) {
+	case "", SignatureSchemeBody:
+		req.Header.Set("X-Webhook-Signature", sign(body, secret))
+	case SignatureSchemeTimestamped:
+		nonce, err := newNonce()
+		if err != nil {
+			return fmt.Errorf("generate nonce: %w", err)
+		}
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set("X-Webhook-Timestamp", timestamp)
+		req.Header.Set("X-Webhook-Nonce", nonce)
+		req.Header.Set("X-Webhook-Signature", signTimestamped(timestamp, nonce, body, secret))
+	default:
+		return fmt.Errorf("unsupported signature scheme %q", c.SignatureScheme)
+	}
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
@@ -68,3 +84,19 @@ func sign(body []byte, secret string) string {
 	mac.Write(body)
 	return hex.EncodeToString(mac.Sum(nil))
 }
+
+// signTimestamped signs "<timestamp>.<nonce>.<body>".
+func signTimestamped(timestamp, nonce string, body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + nonce + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newNonce() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
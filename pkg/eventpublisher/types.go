@@ -5,12 +5,22 @@ import (
 	"time"
 )
 
+const (
+	// SignatureSchemeBody signs the request body only.
+	SignatureSchemeBody = "body"
+	// SignatureSchemeTimestamped signs a timestamp, a random nonce and the body
+	// so DDash can reject stale and replayed requests.
+	SignatureSchemeTimestamped = "timestamped"
+)
+
 type Client struct {
 	Endpoint   string
 	Token      string
 	Secret     string
 	Timeout    time.Duration
 	HTTPClient *http.Client
+	// SignatureScheme selects how requests are signed; empty means SignatureSchemeBody.
+	SignatureScheme string
 }
 
 type Event struct {
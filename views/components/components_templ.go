@@ -55,6 +55,11 @@ type ServiceField struct {
 	Filterable bool
 }
 
+type EventPolicy struct {
+	Subject string
+	Mode    string
+}
+
 type ServiceEnvironment struct {
 	Name            string
 	LastDeploy      string
@@ -81,6 +86,7 @@ type DeploymentRecord struct {
 	DeployedAgo string
 	ReleaseURL  string
 	Environment string
+	ChainURL    string
 }
 
 type ServiceRiskEvent struct {
@@ -88,6 +94,7 @@ type ServiceRiskEvent struct {
 	Environment   string
 	Artifact      string
 	ChainID       string
+	ChainURL      string
 	PipelineRunID string
 	RunURL        string
 	ActorName     string
@@ -101,6 +108,200 @@ type GitHubInstallationMapping struct {
 	Status             string
 }
 
+type DeadLetter struct {
+	ID           int64
+	ReceivedAt   string
+	Reason       string
+	Detail       string
+	Size         string
+	Headers      string
+	Body         string
+	Replayable   bool
+	ReplayStatus string
+}
+
+type IngestCredential struct {
+	ID            int64
+	Label         string
+	AuthToken     string
+	WebhookSecret string
+	CreatedAt     string
+	ExpiresAt     string
+	LastUsedAt    string
+	Status        string
+	Active        bool
+}
+
+type IngestHealthHour struct {
+	Label      string
+	Accepted   int64
+	Duplicates int64
+	Dropped    int64
+	Rejected   int64
+	BarWidth   string
+}
+
+type IngestRejection struct {
+	Reason string
+	Count  int64
+}
+
+type IngestSource struct {
+	Source        string
+	LastEventType string
+	LastEventID   string
+	LastSeen      string
+	Stale         bool
+}
+
+// StorageViews tells pages which storage-backed views are served, so links to
+// the SQLite-only pages are hidden on PostgreSQL.
+type StorageViews struct {
+	Events            bool
+	Projectors        bool
+	ServiceIdentities bool
+	Environments      bool
+}
+
+type IngestHealth struct {
+	Accepted   int64
+	Duplicates int64
+	Dropped    int64
+	Rejected   int64
+	Hours      []IngestHealthHour
+	Rejections []IngestRejection
+	Sources    []IngestSource
+}
+
+type ProjectorRow struct {
+	Name      string
+	LastSeq   int64
+	HeadSeq   int64
+	Lag       int64
+	UpdatedAt string
+}
+
+type EventFilter struct {
+	EventType   string
+	SubjectType string
+	SubjectID   string
+	Source      string
+	ChainID     string
+	From        string
+	To          string
+}
+
+type EventRow struct {
+	Seq         int64
+	EventType   string
+	SubjectType string
+	SubjectID   string
+	Source      string
+	ChainID     string
+	Timestamp   string
+	DetailURL   string
+}
+
+type EventLink struct {
+	Label string
+	URL   string
+}
+
+type EventDetail struct {
+	EventRow
+	EventID    string
+	IngestedAt string
+	ChainURL   string
+	RawJSON    string
+	Links      []EventLink
+}
+
+type ChainStage struct {
+	SubjectType string
+	SubjectID   string
+	Started     string
+	Duration    string
+	Wait        string
+	Events      []ChainEvent
+}
+
+type ChainEvent struct {
+	EventType string
+	Timestamp string
+	Offset    string
+	DetailURL string
+	URL       string
+}
+
+type DeliveryChain struct {
+	ID        string
+	Started   string
+	Finished  string
+	Duration  string
+	Truncated bool
+	EventsURL string
+	Stages    []ChainStage
+}
+
+type ServiceIdentityRule struct {
+	ID          int64
+	Kind        string
+	Pattern     string
+	ServiceName string
+	CreatedAt   string
+}
+
+type ServiceIdentityAlias struct {
+	Alias       string
+	ServiceName string
+	ServiceURL  string
+}
+
+type EnvironmentEntry struct {
+	Name      string
+	Tier      string
+	Color     string
+	Protected bool
+	URL       string
+	Source    string
+	Aliases   string
+	UpdatedAt string
+}
+
+type HistoryFilter struct {
+	At      string
+	Compare string
+}
+
+// EnvironmentMatrixCell is one service in one environment; an empty Status
+// means nothing had been deployed there yet.
+type EnvironmentMatrixCell struct {
+	ArtifactID string
+	Status     string
+	Actor      string
+	DeployedAt string
+}
+
+type EnvironmentMatrixRow struct {
+	Service    string
+	ServiceURL string
+	Cells      []EnvironmentMatrixCell
+}
+
+type EnvironmentMatrix struct {
+	At           string
+	Environments []string
+	Rows         []EnvironmentMatrixRow
+}
+
+type EnvironmentChangeRow struct {
+	Service     string
+	Environment string
+	Kind        string
+	Before      EnvironmentMatrixCell
+	After       EnvironmentMatrixCell
+}
+
 type DeploymentStatus string
 
 type DeploymentRow struct {
@@ -112,6 +313,20 @@ type DeploymentRow struct {
 	MetadataTags string
 }
 
+type DeploymentFilter struct {
+	Env     string
+	Service string
+	Status  string
+	From    string
+	To      string
+}
+
+type DeploymentEnvironmentCount struct {
+	Environment string
+	Count7d     int64
+	Count30d    int64
+}
+
 type ServiceDetail struct {
 	Title             string
 	Description       string
@@ -198,6 +413,14 @@ func RequiredFieldsJSON(fields []ServiceField) string {
 	return fmt.Sprintf("[%s]", strings.Join(parts, ","))
 }
 
+func EventPoliciesJSON(policies []EventPolicy) string {
+	parts := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		parts = append(parts, fmt.Sprintf(`{"subject":%q,"mode":%q}`, policy.Subject, policy.Mode))
+	}
+	return fmt.Sprintf("[%s]", strings.Join(parts, ","))
+}
+
 func StringListJSON(values []string) string {
 	parts := make([]string, 0, len(values))
 	for _, value := range values {
@@ -225,6 +448,7 @@ type ServiceMetricsData struct {
 	RedeploymentRate    RedeploymentData
 	Throughput          ThroughputData
 	ArtifactAges        []ArtifactAgeData
+	Incidents           IncidentStatsData
 	Comprehensive       ComprehensiveData
 }
 
@@ -263,6 +487,13 @@ type ArtifactAgeData struct {
 	LastEventTsMs int64
 }
 
+type IncidentStatsData struct {
+	IncidentCount     int64
+	OpenIncidentCount int64
+	MTTRSeconds       float64
+	MTTDSeconds       float64
+}
+
 type ComprehensiveData struct {
 	LeadTimeSeconds              float64
 	DeploymentFrequency30d       int64
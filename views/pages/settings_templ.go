@@ -15,7 +15,7 @@ import (
 	"github.com/fr0stylo/ddash/views/components"
 )
 
-func SettingsPage(requiredFields []components.ServiceField, environmentOrder []string, authToken string, webhookSecret string, enabled bool, showSyncStatus bool, showMetadataBadges bool, showEnvironmentColumn bool, enableSSELiveUpdates bool, showDeploymentHistory bool, showMetadataFilters bool, strictMetadataEnforcement bool, maskSensitiveMetadataValues bool, allowServiceMetadataEditing bool, showOnboardingHints bool, showIntegrationTypeBadges bool, showServiceDetailInsights bool, showServiceDeliveryMetrics bool, showServiceDependencies bool, deploymentRetentionDays int, defaultDashboardView string, statusSemanticsMode string, csrfToken string) templ.Component {
+func SettingsPage(requiredFields []components.ServiceField, environmentOrder []string, eventPolicies []components.EventPolicy, authToken string, webhookSecret string, enabled bool, showSyncStatus bool, showMetadataBadges bool, showEnvironmentColumn bool, enableSSELiveUpdates bool, showDeploymentHistory bool, showMetadataFilters bool, strictMetadataEnforcement bool, maskSensitiveMetadataValues bool, allowServiceMetadataEditing bool, showOnboardingHints bool, showIntegrationTypeBadges bool, showServiceDetailInsights bool, showServiceDeliveryMetrics bool, showServiceDependencies bool, deploymentRetentionDays int, eventRetentionDays int, defaultDashboardView string, statusSemanticsMode string, webhookSignatureScheme string, credentials []components.IngestCredential, credentialsEnabled bool, views components.StorageViews, csrfToken string) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -64,8 +64,48 @@ func SettingsPage(requiredFields []components.ServiceField, environmentOrder []s
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
+				if views.Events {
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<a class=\"inline-flex h-9 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 shadow-sm hover:bg-gray-50\" href=\"/events\">Events</a>")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, " <a class=\"inline-flex h-9 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 shadow-sm hover:bg-gray-50\" href=\"/settings/dead-letters\">Dead letters</a> <a class=\"inline-flex h-9 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 shadow-sm hover:bg-gray-50\" href=\"/settings/ingest-health\">Ingest health</a> ")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				if views.Projectors {
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "<a class=\"inline-flex h-9 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 shadow-sm hover:bg-gray-50\" href=\"/settings/projections\">Projections</a>")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, " ")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				if views.ServiceIdentities {
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "<a class=\"inline-flex h-9 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 shadow-sm hover:bg-gray-50\" href=\"/settings/service-identities\">Service identities</a>")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, " ")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				if views.Environments {
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "<a class=\"inline-flex h-9 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 shadow-sm hover:bg-gray-50\" href=\"/settings/environments\">Environments</a>")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, " ")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
 				if showOnboardingHints {
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<a class=\"inline-flex h-9 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 shadow-sm hover:bg-gray-50\" href=\"/onboarding\">Onboarding</a>")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "<a class=\"inline-flex h-9 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 shadow-sm hover:bg-gray-50\" href=\"/onboarding\">Onboarding</a>")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
@@ -76,7 +116,7 @@ func SettingsPage(requiredFields []components.ServiceField, environmentOrder []s
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, " <main class=\"mx-auto max-w-5xl px-4 py-8 sm:px-6 lg:px-8\" x-data=\"")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, " <main class=\"mx-auto max-w-5xl px-4 py-8 sm:px-6 lg:px-8\" x-data=\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -100,10 +140,13 @@ func SettingsPage(requiredFields []components.ServiceField, environmentOrder []s
 				showServiceDeliveryMetrics: %t,
 				showServiceDependencies: %t,
 				deploymentRetentionDays: %d,
+				eventRetentionDays: %d,
 				defaultDashboardView: %q,
 				statusSemanticsMode: %q,
+				webhookSignatureScheme: %q,
 					requiredFields: %s,
 					environmentOrder: %s,
+					eventPolicies: %s,
 					csrfToken: %q,
 					activeTab: 'general',
 					saving: false,
@@ -129,10 +172,13 @@ func SettingsPage(requiredFields []components.ServiceField, environmentOrder []s
 						showServiceDeliveryMetrics: this.showServiceDeliveryMetrics,
 						showServiceDependencies: this.showServiceDependencies,
 						deploymentRetentionDays: this.deploymentRetentionDays,
+						eventRetentionDays: this.eventRetentionDays,
 						defaultDashboardView: this.defaultDashboardView,
 						statusSemanticsMode: this.statusSemanticsMode,
+						webhookSignatureScheme: this.webhookSignatureScheme,
 						requiredFields: this.requiredFields,
 						environmentOrder: this.environmentOrder,
+						eventPolicies: this.eventPolicies,
 					};
 				},
 				showToast(message) {
@@ -160,15 +206,15 @@ func SettingsPage(requiredFields []components.ServiceField, environmentOrder []s
 						this.saving = false;
 					}
 				},
-			}`, authToken, webhookSecret, enabled, showSyncStatus, showMetadataBadges, showEnvironmentColumn, enableSSELiveUpdates, showDeploymentHistory, showMetadataFilters, strictMetadataEnforcement, maskSensitiveMetadataValues, allowServiceMetadataEditing, showOnboardingHints, showIntegrationTypeBadges, showServiceDetailInsights, showServiceDeliveryMetrics, showServiceDependencies, deploymentRetentionDays, defaultDashboardView, statusSemanticsMode, components.RequiredFieldsJSON(requiredFields), components.StringListJSON(environmentOrder), csrfToken))
+			}`, authToken, webhookSecret, enabled, showSyncStatus, showMetadataBadges, showEnvironmentColumn, enableSSELiveUpdates, showDeploymentHistory, showMetadataFilters, strictMetadataEnforcement, maskSensitiveMetadataValues, allowServiceMetadataEditing, showOnboardingHints, showIntegrationTypeBadges, showServiceDetailInsights, showServiceDeliveryMetrics, showServiceDependencies, deploymentRetentionDays, eventRetentionDays, defaultDashboardView, statusSemanticsMode, webhookSignatureScheme, components.RequiredFieldsJSON(requiredFields), components.StringListJSON(environmentOrder), components.EventPoliciesJSON(eventPolicies), csrfToken))
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/pages/settings.templ`, Line: 103, Col: 550}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/pages/settings.templ`, Line: 135, Col: 639}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "\"><div class=\"flex flex-col gap-8\"><div class=\"inline-flex w-fit items-center rounded-xl border border-gray-200 bg-gray-50 p-1\"><button type=\"button\" class=\"inline-flex h-8 items-center rounded-lg px-3 text-xs font-medium transition\" :class=\"activeTab === 'general' ? 'bg-white text-gray-900 shadow-sm' : 'text-gray-600 hover:text-gray-900'\" @click=\"activeTab = 'general'\">General</button> <button type=\"button\" class=\"inline-flex h-8 items-center rounded-lg px-3 text-xs font-medium transition\" :class=\"activeTab === 'features' ? 'bg-white text-gray-900 shadow-sm' : 'text-gray-600 hover:text-gray-900'\" @click=\"activeTab = 'features'\">Features</button></div><div class=\"fixed right-6 top-6 rounded-lg border border-gray-200 bg-white px-4 py-2 text-sm text-gray-700 shadow-lg\" x-show=\"toast\" x-transition x-text=\"toast\"></div><div x-show=\"activeTab === 'general'\" class=\"space-y-8\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "\"><div class=\"flex flex-col gap-8\"><div class=\"inline-flex w-fit items-center rounded-xl border border-gray-200 bg-gray-50 p-1\"><button type=\"button\" class=\"inline-flex h-8 items-center rounded-lg px-3 text-xs font-medium transition\" :class=\"activeTab === 'general' ? 'bg-white text-gray-900 shadow-sm' : 'text-gray-600 hover:text-gray-900'\" @click=\"activeTab = 'general'\">General</button> <button type=\"button\" class=\"inline-flex h-8 items-center rounded-lg px-3 text-xs font-medium transition\" :class=\"activeTab === 'features' ? 'bg-white text-gray-900 shadow-sm' : 'text-gray-600 hover:text-gray-900'\" @click=\"activeTab = 'features'\">Features</button></div><div class=\"fixed right-6 top-6 rounded-lg border border-gray-200 bg-white px-4 py-2 text-sm text-gray-700 shadow-lg\" x-show=\"toast\" x-transition x-text=\"toast\"></div><div x-show=\"activeTab === 'general'\" class=\"space-y-8\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -184,7 +230,7 @@ func SettingsPage(requiredFields []components.ServiceField, environmentOrder []s
 					}()
 				}
 				ctx = templ.InitializeContext(ctx)
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "<div class=\"space-y-4\"><div><label class=\"text-xs font-medium text-gray-500\">Auth token</label> <input type=\"text\" x-model=\"authToken\" class=\"mt-1 h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200\" placeholder=\"tenant-token\"></div><div><label class=\"text-xs font-medium text-gray-500\">Webhook secret</label> <input type=\"text\" x-model=\"webhookSecret\" class=\"mt-1 h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200\" placeholder=\"webhook-secret\"></div><label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"enabled\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\"> Enabled</label></div>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "<div class=\"space-y-4\"><div><label class=\"text-xs font-medium text-gray-500\">Auth token</label> <input type=\"text\" x-model=\"authToken\" class=\"mt-1 h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200\" placeholder=\"tenant-token\"></div><div><label class=\"text-xs font-medium text-gray-500\">Webhook secret</label> <input type=\"text\" x-model=\"webhookSecret\" class=\"mt-1 h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200\" placeholder=\"webhook-secret\"></div><div><label class=\"text-xs font-medium text-gray-500\">Webhook signature</label> <select x-model=\"webhookSignatureScheme\" class=\"mt-1 h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200\"><option value=\"body\">Body only</option> <option value=\"timestamped\">Timestamp and nonce (replay protected)</option></select><p class=\"mt-1 text-xs text-gray-500\">Timestamped signatures are always accepted; choose it here to refuse body-only signatures.</p></div><label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"enabled\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\"> Enabled</label></div>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
@@ -194,7 +240,243 @@ func SettingsPage(requiredFields []components.ServiceField, environmentOrder []s
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Var6 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			if credentialsEnabled {
+				templ_7745c5c3_Var6 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+					templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+					templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+					if !templ_7745c5c3_IsBuffer {
+						defer func() {
+							templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+							if templ_7745c5c3_Err == nil {
+								templ_7745c5c3_Err = templ_7745c5c3_BufErr
+							}
+						}()
+					}
+					ctx = templ.InitializeContext(ctx)
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "<div class=\"space-y-4\"><div class=\"text-sm text-gray-600\">Additional auth token and webhook secret pairs accepted alongside the organization credentials. Add a new one, move senders over, then expire or revoke the old one.</div><form method=\"post\" action=\"/settings/credentials\" class=\"flex flex-col gap-3 sm:flex-row sm:items-center\">")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = components.CSRFInput(csrfToken).Render(ctx, templ_7745c5c3_Buffer)
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "<input type=\"text\" name=\"label\" required class=\"h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200\" placeholder=\"Label, e.g. ci-2026\"> <input type=\"number\" name=\"validHours\" min=\"0\" class=\"h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200 sm:w-40\" placeholder=\"Valid hours\"> <button type=\"submit\" class=\"inline-flex h-10 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 hover:bg-gray-50\">Add credential</button></form>")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					if len(credentials) == 0 {
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "<div class=\"rounded-lg border border-dashed border-gray-200 bg-gray-50 px-4 py-3 text-sm text-gray-500\">No additional credentials.</div>")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+					} else {
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "<div class=\"space-y-3\">")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						for _, item := range credentials {
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "<div class=\"rounded-lg border border-gray-200 p-4\"><div class=\"flex flex-col gap-3 sm:flex-row sm:items-center sm:justify-between\"><div class=\"space-y-1\"><p class=\"text-sm font-medium text-gray-900\">")
+							if templ_7745c5c3_Err != nil {
+								return templ_7745c5c3_Err
+							}
+							var templ_7745c5c3_Var7 string
+							templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(item.Label)
+							if templ_7745c5c3_Err != nil {
+								return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/pages/settings.templ`, Line: 230, Col: 70}
+							}
+							_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
+							if templ_7745c5c3_Err != nil {
+								return templ_7745c5c3_Err
+							}
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, " · ")
+							if templ_7745c5c3_Err != nil {
+								return templ_7745c5c3_Err
+							}
+							var templ_7745c5c3_Var8 string
+							templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(item.Status)
+							if templ_7745c5c3_Err != nil {
+								return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/pages/settings.templ`, Line: 230, Col: 89}
+							}
+							_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
+							if templ_7745c5c3_Err != nil {
+								return templ_7745c5c3_Err
+							}
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "</p><p class=\"text-xs text-gray-500\">Created ")
+							if templ_7745c5c3_Err != nil {
+								return templ_7745c5c3_Err
+							}
+							var templ_7745c5c3_Var9 string
+							templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(item.CreatedAt)
+							if templ_7745c5c3_Err != nil {
+								return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/pages/settings.templ`, Line: 232, Col: 38}
+							}
+							_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
+							if templ_7745c5c3_Err != nil {
+								return templ_7745c5c3_Err
+							}
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, " ")
+							if templ_7745c5c3_Err != nil {
+								return templ_7745c5c3_Err
+							}
+							if item.ExpiresAt != "" {
+								templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "· expires ")
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+								var templ_7745c5c3_Var10 string
+								templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(item.ExpiresAt)
+								if templ_7745c5c3_Err != nil {
+									return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/pages/settings.templ`, Line: 234, Col: 42}
+								}
+								_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+								templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, " ")
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+							}
+							if item.LastUsedAt != "" {
+								templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, "· last used ")
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+								var templ_7745c5c3_Var11 string
+								templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(item.LastUsedAt)
+								if templ_7745c5c3_Err != nil {
+									return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/pages/settings.templ`, Line: 237, Col: 45}
+								}
+								_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var11))
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+							} else {
+								templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, "· never used")
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+							}
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, "</p></div>")
+							if templ_7745c5c3_Err != nil {
+								return templ_7745c5c3_Err
+							}
+							if item.Active {
+								templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, "<div class=\"flex items-center gap-2\"><form method=\"post\" action=\"/settings/credentials/expire\" class=\"flex items-center gap-2\">")
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+								templ_7745c5c3_Err = components.CSRFInput(csrfToken).Render(ctx, templ_7745c5c3_Buffer)
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+								templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 28, "<input type=\"hidden\" name=\"id\" value=\"")
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+								var templ_7745c5c3_Var12 string
+								templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprint(item.ID))
+								if templ_7745c5c3_Err != nil {
+									return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/pages/settings.templ`, Line: 247, Col: 73}
+								}
+								_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var12))
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+								templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 29, "\"> <input type=\"number\" name=\"graceHours\" min=\"0\" value=\"24\" class=\"h-8 w-full rounded-lg border border-gray-200 bg-white px-2 text-xs shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200 sm:w-auto\"> <button type=\"submit\" class=\"inline-flex h-8 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 hover:bg-gray-50\">Expire in hours</button></form><form method=\"post\" action=\"/settings/credentials/revoke\">")
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+								templ_7745c5c3_Err = components.CSRFInput(csrfToken).Render(ctx, templ_7745c5c3_Buffer)
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+								templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 30, "<input type=\"hidden\" name=\"id\" value=\"")
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+								var templ_7745c5c3_Var13 string
+								templ_7745c5c3_Var13, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprint(item.ID))
+								if templ_7745c5c3_Err != nil {
+									return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/pages/settings.templ`, Line: 253, Col: 73}
+								}
+								_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var13))
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+								templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 31, "\"> <button type=\"submit\" class=\"inline-flex h-8 items-center rounded-lg border border-red-200 bg-white px-3 text-xs font-medium text-red-700 hover:bg-red-50\">Revoke</button></form></div>")
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+							}
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 32, "</div>")
+							if templ_7745c5c3_Err != nil {
+								return templ_7745c5c3_Err
+							}
+							if item.Active {
+								templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 33, "<details class=\"mt-3\"><summary class=\"cursor-pointer text-xs font-medium text-gray-500\">Token and secret</summary><pre class=\"mt-2 overflow-x-auto rounded-lg bg-gray-50 p-3 font-mono text-xs text-gray-700\">")
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+								var templ_7745c5c3_Var14 string
+								templ_7745c5c3_Var14, templ_7745c5c3_Err = templ.JoinStringErrs("Auth token: " + item.AuthToken + "\nWebhook secret: " + item.WebhookSecret)
+								if templ_7745c5c3_Err != nil {
+									return templ.Error{Err: templ_7745c5c3_Err, FileName: `views/pages/settings.templ`, Line: 262, Col: 182}
+								}
+								_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var14))
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+								templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 34, "</pre></details>")
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+							}
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 35, "</div>")
+							if templ_7745c5c3_Err != nil {
+								return templ_7745c5c3_Err
+							}
+						}
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 36, "</div>")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 37, "</div>")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					return nil
+				})
+				templ_7745c5c3_Err = components.Card("Ingest credentials").Render(templ.WithChildren(ctx, templ_7745c5c3_Var6), templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Var15 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+				templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+				templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+				if !templ_7745c5c3_IsBuffer {
+					defer func() {
+						templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+						if templ_7745c5c3_Err == nil {
+							templ_7745c5c3_Err = templ_7745c5c3_BufErr
+						}
+					}()
+				}
+				ctx = templ.InitializeContext(ctx)
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 38, "<div class=\"space-y-4\"><div id=\"metadata-requirements\" class=\"text-sm text-gray-600\">These required metadata fields apply to every service in the organization.</div><div class=\"rounded-lg border border-dashed border-gray-200 bg-gray-50 p-4\"><div class=\"flex items-center justify-between\"><div><p class=\"text-sm font-medium text-gray-700\">Organization-wide metadata fields</p><p class=\"text-xs text-gray-500\">Add, edit, or remove requirements. Changes apply to all services.</p></div><button type=\"button\" class=\"inline-flex h-9 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 shadow-sm hover:bg-gray-50\" @click=\"requiredFields.push({ label: '', type: 'text', filterable: false })\">Add field</button></div><div class=\"mt-4 space-y-3\"><template x-for=\"(field, index) in requiredFields\" :key=\"index\"><div class=\"flex flex-col gap-3 sm:flex-row sm:items-center\"><input type=\"text\" x-model=\"field.label\" class=\"h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200\" placeholder=\"Field label\"> <select x-model=\"field.type\" class=\"h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200 sm:w-40\"><option value=\"text\">Text</option> <option value=\"url\">URL</option> <option value=\"select\">Select</option></select> <label class=\"inline-flex h-10 items-center gap-2 rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700\"><input type=\"checkbox\" x-model=\"field.filterable\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\"> Filterable</label> <button type=\"button\" class=\"inline-flex h-10 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 hover:bg-gray-50 sm:w-auto\" @click=\"requiredFields.splice(index, 1)\">Remove</button></div></template><div class=\"text-xs text-gray-400\" x-show=\"requiredFields.length === 0\">No metadata requirements yet. Click Add field to create one.</div></div></div></div>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				return nil
+			})
+			templ_7745c5c3_Err = components.Card("Metadata requirements").Render(templ.WithChildren(ctx, templ_7745c5c3_Var15), templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Var16 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 				templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 				templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 				if !templ_7745c5c3_IsBuffer {
@@ -206,17 +488,17 @@ func SettingsPage(requiredFields []components.ServiceField, environmentOrder []s
 					}()
 				}
 				ctx = templ.InitializeContext(ctx)
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "<div class=\"space-y-4\"><div id=\"metadata-requirements\" class=\"text-sm text-gray-600\">These required metadata fields apply to every service in the organization.</div><div class=\"rounded-lg border border-dashed border-gray-200 bg-gray-50 p-4\"><div class=\"flex items-center justify-between\"><div><p class=\"text-sm font-medium text-gray-700\">Organization-wide metadata fields</p><p class=\"text-xs text-gray-500\">Add, edit, or remove requirements. Changes apply to all services.</p></div><button type=\"button\" class=\"inline-flex h-9 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 shadow-sm hover:bg-gray-50\" @click=\"requiredFields.push({ label: '', type: 'text', filterable: false })\">Add field</button></div><div class=\"mt-4 space-y-3\"><template x-for=\"(field, index) in requiredFields\" :key=\"index\"><div class=\"flex flex-col gap-3 sm:flex-row sm:items-center\"><input type=\"text\" x-model=\"field.label\" class=\"h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200\" placeholder=\"Field label\"> <select x-model=\"field.type\" class=\"h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200 sm:w-40\"><option value=\"text\">Text</option> <option value=\"url\">URL</option> <option value=\"select\">Select</option></select> <label class=\"inline-flex h-10 items-center gap-2 rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700\"><input type=\"checkbox\" x-model=\"field.filterable\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\"> Filterable</label> <button type=\"button\" class=\"inline-flex h-10 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 hover:bg-gray-50 sm:w-auto\" @click=\"requiredFields.splice(index, 1)\">Remove</button></div></template><div class=\"text-xs text-gray-400\" x-show=\"requiredFields.length === 0\">No metadata requirements yet. Click Add field to create one.</div></div></div></div>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 39, "<div class=\"space-y-4\"><div class=\"text-sm text-gray-600\">Top item is highest priority in service environment view.</div><div class=\"rounded-lg border border-dashed border-gray-200 bg-gray-50 p-4\"><div class=\"flex items-center justify-between\"><div><p class=\"text-sm font-medium text-gray-700\">Environment order</p><p class=\"text-xs text-gray-500\">Example: production, loadtest, uat, qa, uat2</p></div><button type=\"button\" class=\"inline-flex h-9 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 shadow-sm hover:bg-gray-50\" @click=\"environmentOrder.push('')\">Add environment</button></div><div class=\"mt-4 space-y-3\"><template x-for=\"(env, index) in environmentOrder\" :key=\"index\"><div class=\"flex items-center gap-2\"><span class=\"w-6 text-center text-xs text-gray-500\" x-text=\"index + 1\"></span> <input type=\"text\" x-model=\"environmentOrder[index]\" class=\"h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200\" placeholder=\"Environment name\"> <button type=\"button\" class=\"inline-flex h-10 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 hover:bg-gray-50\" @click=\"if (index > 0) { const t = environmentOrder[index - 1]; environmentOrder[index - 1] = environmentOrder[index]; environmentOrder[index] = t; }\">Up</button> <button type=\"button\" class=\"inline-flex h-10 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 hover:bg-gray-50\" @click=\"if (index < environmentOrder.length - 1) { const t = environmentOrder[index + 1]; environmentOrder[index + 1] = environmentOrder[index]; environmentOrder[index] = t; }\">Down</button> <button type=\"button\" class=\"inline-flex h-10 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 hover:bg-gray-50\" @click=\"environmentOrder.splice(index, 1)\">Remove</button></div></template><div class=\"text-xs text-gray-400\" x-show=\"environmentOrder.length === 0\">No environment priorities yet.</div></div></div></div>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				return nil
 			})
-			templ_7745c5c3_Err = components.Card("Metadata requirements").Render(templ.WithChildren(ctx, templ_7745c5c3_Var6), templ_7745c5c3_Buffer)
+			templ_7745c5c3_Err = components.Card("Environment priority").Render(templ.WithChildren(ctx, templ_7745c5c3_Var16), templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Var7 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_Var17 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 				templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 				templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 				if !templ_7745c5c3_IsBuffer {
@@ -228,21 +510,21 @@ func SettingsPage(requiredFields []components.ServiceField, environmentOrder []s
 					}()
 				}
 				ctx = templ.InitializeContext(ctx)
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "<div class=\"space-y-4\"><div class=\"text-sm text-gray-600\">Top item is highest priority in service environment view.</div><div class=\"rounded-lg border border-dashed border-gray-200 bg-gray-50 p-4\"><div class=\"flex items-center justify-between\"><div><p class=\"text-sm font-medium text-gray-700\">Environment order</p><p class=\"text-xs text-gray-500\">Example: production, loadtest, uat, qa, uat2</p></div><button type=\"button\" class=\"inline-flex h-9 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 shadow-sm hover:bg-gray-50\" @click=\"environmentOrder.push('')\">Add environment</button></div><div class=\"mt-4 space-y-3\"><template x-for=\"(env, index) in environmentOrder\" :key=\"index\"><div class=\"flex items-center gap-2\"><span class=\"w-6 text-center text-xs text-gray-500\" x-text=\"index + 1\"></span> <input type=\"text\" x-model=\"environmentOrder[index]\" class=\"h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200\" placeholder=\"Environment name\"> <button type=\"button\" class=\"inline-flex h-10 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 hover:bg-gray-50\" @click=\"if (index > 0) { const t = environmentOrder[index - 1]; environmentOrder[index - 1] = environmentOrder[index]; environmentOrder[index] = t; }\">Up</button> <button type=\"button\" class=\"inline-flex h-10 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 hover:bg-gray-50\" @click=\"if (index < environmentOrder.length - 1) { const t = environmentOrder[index + 1]; environmentOrder[index + 1] = environmentOrder[index]; environmentOrder[index] = t; }\">Down</button> <button type=\"button\" class=\"inline-flex h-10 items-center rounded-lg border border-gray-200 bg-white px-3 text-xs font-medium text-gray-700 hover:bg-gray-50\" @click=\"environmentOrder.splice(index, 1)\">Remove</button></div></template><div class=\"text-xs text-gray-400\" x-show=\"environmentOrder.length === 0\">No environment priorities yet.</div></div></div></div>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 40, "<div class=\"space-y-4\"><div class=\"text-sm text-gray-600\">Choose how incoming CDEvents are handled per subject. Strict validates the CDEvents schema and, for service and environment events, admits only the v0.5 types; drop acknowledges without storing, reject refuses the delivery.</div><div class=\"rounded-lg border border-dashed border-gray-200 bg-gray-50 p-4\"><div class=\"space-y-3\"><template x-for=\"policy in eventPolicies\" :key=\"policy.subject\"><div class=\"flex flex-col gap-3 sm:flex-row sm:items-center\"><span class=\"w-full text-sm font-medium text-gray-700\" x-text=\"policy.subject\"></span> <select x-model=\"policy.mode\" class=\"h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200 sm:w-40\"><option value=\"accept\">Accept</option> <option value=\"strict\">Strict</option> <option value=\"drop\">Drop</option> <option value=\"reject\">Reject</option></select></div></template></div></div></div>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				return nil
 			})
-			templ_7745c5c3_Err = components.Card("Environment priority").Render(templ.WithChildren(ctx, templ_7745c5c3_Var7), templ_7745c5c3_Buffer)
+			templ_7745c5c3_Err = components.Card("Event ingestion").Render(templ.WithChildren(ctx, templ_7745c5c3_Var17), templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "</div><div x-show=\"activeTab === 'features'\" class=\"space-y-8\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 41, "</div><div x-show=\"activeTab === 'features'\" class=\"space-y-8\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Var8 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_Var18 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 				templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 				templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 				if !templ_7745c5c3_IsBuffer {
@@ -254,17 +536,17 @@ func SettingsPage(requiredFields []components.ServiceField, environmentOrder []s
 					}()
 				}
 				ctx = templ.InitializeContext(ctx)
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "<div class=\"space-y-4\"><label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showSyncStatus\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show sync status on dashboards</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showMetadataBadges\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show missing metadata badges on dashboards</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showEnvironmentColumn\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show environment columns</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"enableSSELiveUpdates\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Enable live updates (SSE)</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showDeploymentHistory\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show deployment history on service page</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showMetadataFilters\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show metadata filters</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"strictMetadataEnforcement\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Strict metadata enforcement</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"maskSensitiveMetadataValues\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Mask sensitive metadata values</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"allowServiceMetadataEditing\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Allow service metadata editing</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showOnboardingHints\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show onboarding hints</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showIntegrationTypeBadges\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show integration type badges</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showServiceDetailInsights\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show service detail insights</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showServiceDeliveryMetrics\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show delivery metrics on service page</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showServiceDependencies\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show service dependencies and dependants</label><div class=\"space-y-1\"><label class=\"text-xs font-medium text-gray-500\">Deployment retention days</label><input type=\"number\" min=\"1\" x-model.number=\"deploymentRetentionDays\" class=\"h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200\"></div><div class=\"space-y-1\"><label class=\"text-xs font-medium text-gray-500\">Default dashboard view</label><select x-model=\"defaultDashboardView\" class=\"h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200\"><option value=\"grid\">Grid</option><option value=\"table\">Table</option></select></div><div class=\"space-y-1\"><label class=\"text-xs font-medium text-gray-500\">Status semantics</label><select x-model=\"statusSemanticsMode\" class=\"h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200\"><option value=\"technical\">Technical</option><option value=\"plain\">Plain</option></select></div></div>")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 42, "<div class=\"space-y-4\"><label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showSyncStatus\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show sync status on dashboards</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showMetadataBadges\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show missing metadata badges on dashboards</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showEnvironmentColumn\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show environment columns</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"enableSSELiveUpdates\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Enable live updates (SSE)</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showDeploymentHistory\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show deployment history on service page</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showMetadataFilters\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show metadata filters</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"strictMetadataEnforcement\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Strict metadata enforcement</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"maskSensitiveMetadataValues\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Mask sensitive metadata values</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"allowServiceMetadataEditing\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Allow service metadata editing</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showOnboardingHints\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show onboarding hints</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showIntegrationTypeBadges\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show integration type badges</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showServiceDetailInsights\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show service detail insights</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showServiceDeliveryMetrics\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show delivery metrics on service page</label> <label class=\"flex items-center gap-2 text-sm text-gray-700\"><input type=\"checkbox\" x-model=\"showServiceDependencies\" class=\"h-4 w-4 rounded border-gray-300 text-gray-900\">Show service dependencies and dependants</label><div class=\"space-y-1\"><label class=\"text-xs font-medium text-gray-500\">Deployment retention days</label><input type=\"number\" min=\"1\" x-model.number=\"deploymentRetentionDays\" class=\"h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200\"></div><div class=\"space-y-1\"><label class=\"text-xs font-medium text-gray-500\">Event retention days (0 keeps every event)</label><input type=\"number\" min=\"0\" x-model.number=\"eventRetentionDays\" class=\"h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200\"></div><div class=\"space-y-1\"><label class=\"text-xs font-medium text-gray-500\">Default dashboard view</label><select x-model=\"defaultDashboardView\" class=\"h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200\"><option value=\"grid\">Grid</option><option value=\"table\">Table</option></select></div><div class=\"space-y-1\"><label class=\"text-xs font-medium text-gray-500\">Status semantics</label><select x-model=\"statusSemanticsMode\" class=\"h-10 w-full rounded-lg border border-gray-200 bg-white px-3 text-sm shadow-sm outline-none focus:border-gray-300 focus:ring-2 focus:ring-gray-200\"><option value=\"technical\">Technical</option><option value=\"plain\">Plain</option></select></div></div>")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				return nil
 			})
-			templ_7745c5c3_Err = components.Card("Dashboard and behavior").Render(templ.WithChildren(ctx, templ_7745c5c3_Var8), templ_7745c5c3_Buffer)
+			templ_7745c5c3_Err = components.Card("Dashboard and behavior").Render(templ.WithChildren(ctx, templ_7745c5c3_Var18), templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "</div><div class=\"sticky bottom-4 z-10 flex justify-end\"><button type=\"button\" class=\"inline-flex h-10 items-center rounded-lg bg-gray-900 px-4 text-sm font-medium text-white shadow-lg hover:bg-gray-800\" @click=\"save()\" :disabled=\"saving\"><span x-show=\"!saving\">Save settings</span> <span x-show=\"saving\">Saving...</span></button></div></div></main>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 43, "</div><div class=\"sticky bottom-4 z-10 flex justify-end\"><button type=\"button\" class=\"inline-flex h-10 items-center rounded-lg bg-gray-900 px-4 text-sm font-medium text-white shadow-lg hover:bg-gray-800\" @click=\"save()\" :disabled=\"saving\"><span x-show=\"!saving\">Save settings</span> <span x-show=\"saving\">Saving...</span></button></div></div></main>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}